@@ -24,6 +24,7 @@ package cloudprober
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
@@ -33,8 +34,12 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cloudprober/cloudprober/config"
+	"github.com/cloudprober/cloudprober/config/etcdsource"
+	"github.com/cloudprober/cloudprober/config/grpcsource"
+	"github.com/cloudprober/cloudprober/config/k8ssource"
 	configpb "github.com/cloudprober/cloudprober/config/proto"
 	"github.com/cloudprober/cloudprober/config/runconfig"
 	"github.com/cloudprober/cloudprober/internal/servers"
@@ -48,6 +53,8 @@ import (
 	"google.golang.org/grpc/channelz/service"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -63,6 +70,13 @@ const (
 	DisableHTTPDebugVar = "CLOUDPROBER_DISABLE_HTTP_PPROF"
 )
 
+// configSource is a config source that can be watched for config updates,
+// e.g. the gRPC and etcd config sources, and the config file watcher.
+type configSource interface {
+	Watch(ctx context.Context, f func(*config.Update))
+	Close() error
+}
+
 // Global prober.Prober instance protected by a mutex.
 var cloudProber struct {
 	prober          *prober.Prober
@@ -70,8 +84,14 @@ var cloudProber struct {
 	defaultGRPCLn   net.Listener
 	rawConfig       string
 	parsedConfig    string
+	configWarnings  []config.Warning
 	config          *configpb.ProberConfig
+	configSource    configSource
 	cancelInitCtx   context.CancelFunc
+
+	// startedProber is the prober started by Start. Unlike prober, it's not
+	// reset when Start's context is canceled, so that Wait can wait for it.
+	startedProber *prober.Prober
 	sync.Mutex
 }
 
@@ -140,6 +160,19 @@ func setDebugHandlers(srvMux *http.ServeMux) {
 	srvMux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 }
 
+// writeAuditConfig writes the loaded config to the config audit directory, if
+// configured. Errors are logged but are not fatal.
+func writeAuditConfig(cfg *configpb.ProberConfig, parsedConfig, format string, vars map[string]string, l *logger.Logger) {
+	auditFile, err := config.WriteAuditConfig(cfg, parsedConfig, format, vars)
+	if err != nil {
+		l.Warningf("Error writing config audit file: %v", err)
+		return
+	}
+	if auditFile != "" {
+		l.Infof("Wrote config audit file: %s", auditFile)
+	}
+}
+
 // InitFromConfig initializes Cloudprober using the provided config.
 func InitFromConfig(configFile string) error {
 	// Return immediately if prober is already initialized.
@@ -157,14 +190,106 @@ func InitFromConfig(configFile string) error {
 
 	globalLogger := logger.NewWithAttrs(slog.String("component", "global"))
 
-	configStr, configFormat, err := config.GetConfig(configFile, globalLogger)
+	var cfg *configpb.ProberConfig
+	var configStr, parsedConfigStr, configFormat string
+	var configSource configSource
+
+	err := config.RunWithLoadTimeout(func(ctx context.Context, setStage func(string)) error {
+		// If config loading times out, we return without using the config
+		// source, so close it here if it was created anyway.
+		defer func() {
+			if ctx.Err() != nil && configSource != nil {
+				configSource.Close()
+			}
+		}()
+
+		var err error
+		var configContent string
+
+		switch src := config.ConfigSource(configFile); {
+		case grpcsource.IsSource(src):
+			// Config received over gRPC is already a proto, no parsing
+			// required. It's processed by the source, like ParseConfig
+			// processes the other configs.
+			setStage("connecting to config server " + src)
+			grpcSource, err := grpcsource.New(src, sysvars.Vars(), globalLogger)
+			if err != nil {
+				return err
+			}
+			setStage("fetching config from " + src)
+			if cfg, err = grpcSource.GetConfig(ctx); err != nil {
+				grpcSource.Close()
+				return err
+			}
+			configSource = grpcSource
+			configStr = prototext.Format(cfg)
+			parsedConfigStr, configFormat = configStr, "textpb"
+			return nil
+
+		case etcdsource.IsSource(src):
+			setStage("connecting to etcd " + src)
+			etcdSource, err := etcdsource.New(src, sysvars.Vars(), globalLogger)
+			if err != nil {
+				return err
+			}
+			setStage("reading config from " + src)
+			if configContent, configFormat, err = etcdSource.GetConfig(ctx); err != nil {
+				etcdSource.Close()
+				return err
+			}
+			configSource = etcdSource
+
+		case k8ssource.IsSource(src):
+			k8sSource, err := k8ssource.New(src, sysvars.Vars(), globalLogger)
+			if err != nil {
+				return err
+			}
+			setStage("reading config from " + src)
+			if configContent, configFormat, err = k8sSource.GetConfig(ctx); err != nil {
+				k8sSource.Close()
+				return err
+			}
+			// Kubernetes source adds the probes from the Probe objects to
+			// the parsed config.
+			setStage("parsing config")
+			if cfg, parsedConfigStr, err = k8sSource.ParseConfig(ctx, configContent, configFormat); err != nil {
+				k8sSource.Close()
+				return err
+			}
+			configSource = k8sSource
+			configStr = configContent
+			return nil
+
+		default:
+			setStage("reading config")
+			configContent, configFormat, err = config.GetConfig(ctx, configFile, globalLogger)
+			if err != nil {
+				return err
+			}
+			if config.ReloadEnabled() {
+				configSource = config.NewWatcher(configFile, configContent, sysvars.Vars(), globalLogger)
+			}
+		}
+
+		setStage("parsing config")
+		cfg, parsedConfigStr, err = config.ParseConfig(ctx, configContent, configFormat, sysvars.Vars(), globalLogger)
+		if err != nil {
+			if configSource != nil {
+				configSource.Close()
+			}
+			return err
+		}
+		configStr = configContent
+		return nil
+	})
 	if err != nil {
 		return err
 	}
+	writeAuditConfig(cfg, parsedConfigStr, configFormat, sysvars.Vars(), globalLogger)
 
-	cfg, parsedConfigStr, err := config.ParseConfig(configStr, configFormat, sysvars.Vars(), globalLogger)
-	if err != nil {
-		return err
+	configWarnings := config.LintConfig(cfg)
+	for _, w := range configWarnings {
+		globalLogger.Warningf("Config warning: %s", w)
 	}
 
 	// Start default HTTP server. It's used for profile handlers and
@@ -206,7 +331,7 @@ func InitFromConfig(configFile string) error {
 		runconfig.SetDefaultGRPCServer(s)
 	}
 
-	pr := &prober.Prober{}
+	pr := &prober.Prober{ActiveConfig: GetRawConfig}
 
 	// initCtx is used to clean up in case of partial initialization failures. For
 	// example, user-configured servers open listeners during initialization and
@@ -221,10 +346,14 @@ func InitFromConfig(configFile string) error {
 		return err
 	}
 
+	runconfig.SetConfigChecksum(config.Checksum(cfg))
+
 	cloudProber.prober = pr
 	cloudProber.config = cfg
+	cloudProber.configSource = configSource
 	cloudProber.rawConfig = configStr
 	cloudProber.parsedConfig = parsedConfigStr
+	cloudProber.configWarnings = configWarnings
 	cloudProber.defaultServerLn = ln
 	cloudProber.defaultGRPCLn = grpcLn
 	cloudProber.cancelInitCtx = cancelFunc
@@ -255,8 +384,13 @@ func Start(ctx context.Context) {
 		cloudProber.defaultGRPCLn = nil
 		cloudProber.rawConfig = ""
 		cloudProber.parsedConfig = ""
+		cloudProber.configWarnings = nil
 		cloudProber.config = nil
 		cloudProber.prober = nil
+		if cloudProber.configSource != nil {
+			cloudProber.configSource.Close()
+			cloudProber.configSource = nil
+		}
 	}()
 
 	go httpSrv.Serve(cloudProber.defaultServerLn)
@@ -269,11 +403,119 @@ func Start(ctx context.Context) {
 	}
 
 	cloudProber.prober.Start(ctx)
+	cloudProber.startedProber = cloudProber.prober
+	if cloudProber.configSource != nil {
+		go cloudProber.configSource.Watch(ctx, configUpdateHandler(ctx, cloudProber.prober))
+	}
 	srvMux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "OK")
 	})
 }
 
+// StartupCheck starts a previously initialized Cloudprober, waits for all the
+// probes to run once, and then stops it, flushing the surfacers. It returns an
+// error if any of the probes failed or didn't run within the timeout, or if
+// any of the surfacers failed to export the results.
+func StartupCheck(ctx context.Context, timeout time.Duration) error {
+	cloudProber.Lock()
+	pr := cloudProber.prober
+	cloudProber.Unlock()
+	if pr == nil {
+		return errors.New("prober is not initialized, did you call cloudprober.InitFromConfig first?")
+	}
+	pr.EnableStartupCheck()
+
+	startCtx, cancelF := context.WithCancel(ctx)
+	Start(startCtx)
+
+	waitCtx, cancelWait := context.WithTimeout(ctx, timeout)
+	defer cancelWait()
+	probeErr := pr.WaitForStartupCheck(waitCtx)
+
+	cancelF()
+	return errors.Join(probeErr, pr.Wait(context.Background()))
+}
+
+// Wait waits for the Cloudprober started by Start to stop after Start's
+// context is canceled, i.e. for the surfacers to flush the buffered metrics,
+// or until ctx is done. It returns the surfacers' flush error, or an error if
+// ctx is done first.
+func Wait(ctx context.Context) error {
+	cloudProber.Lock()
+	pr := cloudProber.startedProber
+	cloudProber.Unlock()
+	if pr == nil {
+		return nil
+	}
+	return pr.Wait(ctx)
+}
+
+// Simulate reads and parses the config, like InitFromConfig does, and
+// estimates the load that it'd generate, without running any probes or
+// surfacers. See prober.Simulate for the details.
+func Simulate(ctx context.Context, configFile string, discoveryWait time.Duration) (*prober.SimulationReport, error) {
+	if err := sysvars.Init(logger.NewWithAttrs(slog.String("component", sysvarsModuleName)), nil); err != nil {
+		return nil, err
+	}
+	globalLogger := logger.NewWithAttrs(slog.String("component", "global"))
+
+	configContent, configFormat, err := config.GetConfig(ctx, configFile, globalLogger)
+	if err != nil {
+		return nil, err
+	}
+	cfg, _, err := config.ParseConfig(ctx, configContent, configFormat, sysvars.Vars(), globalLogger)
+	if err != nil {
+		return nil, err
+	}
+	return prober.Simulate(ctx, cfg, discoveryWait, globalLogger)
+}
+
+// configUpdateHandler returns a function that applies config updates
+// received from the config source (gRPC, etcd, or the config file watcher).
+// Only probe and surfacer changes are applied, other changes require a
+// restart. Unchanged probes and surfacers keep running.
+func configUpdateHandler(ctx context.Context, pr *prober.Prober) func(*config.Update) {
+	l := logger.NewWithAttrs(slog.String("component", "global"))
+
+	return func(u *config.Update) {
+		cfg := u.Config
+		cloudProber.Lock()
+		oldCfg := cloudProber.config
+		cloudProber.Unlock()
+		if oldCfg == nil {
+			return
+		}
+
+		l.Infof("Received config update from the config source, updating surfacers and probes.")
+		// Update surfacers first, as probes may refer to the new surfacers.
+		if err := pr.UpdateSurfacers(ctx, cfg.GetSurfacer()); err != nil {
+			l.Errorf("Error updating surfacers: %v", err)
+		}
+		if err := pr.UpdateProbes(ctx, cfg.GetProbe()); err != nil {
+			l.Errorf("Error updating probes: %v", err)
+		}
+
+		oldRest, newRest := proto.Clone(oldCfg).(*configpb.ProberConfig), proto.Clone(cfg).(*configpb.ProberConfig)
+		oldRest.Probe, newRest.Probe = nil, nil
+		oldRest.Surfacer, newRest.Surfacer = nil, nil
+		if !proto.Equal(oldRest, newRest) {
+			l.Warningf("Config changes other than probes and surfacers are not applied until restart.")
+		}
+
+		runconfig.SetConfigChecksum(config.Checksum(cfg))
+		// Like at startup, secrets are redacted using the config before the
+		// secrets substitution.
+		writeAuditConfig(cfg, u.ParsedConfig, u.Format, sysvars.Vars(), l)
+
+		cloudProber.Lock()
+		defer cloudProber.Unlock()
+		cloudProber.config = cfg
+		cloudProber.rawConfig = u.Content
+		cloudProber.parsedConfig = u.ParsedConfig
+		cloudProber.configWarnings = config.LintConfig(cfg)
+	}
+}
+
 // GetConfig returns the prober config.
 func GetConfig() *configpb.ProberConfig {
 	cloudProber.Lock()
@@ -295,9 +537,17 @@ func GetParsedConfig() string {
 	return cloudProber.parsedConfig
 }
 
+// GetConfigWarnings returns the warnings for the running config, e.g. use of
+// deprecated fields.
+func GetConfigWarnings() []config.Warning {
+	cloudProber.Lock()
+	defer cloudProber.Unlock()
+	return cloudProber.configWarnings
+}
+
 // GetInfo returns information on all the probes, servers and surfacers.
 func GetInfo() (map[string]*probes.ProbeInfo, []*surfacers.SurfacerInfo, []*servers.ServerInfo) {
 	cloudProber.Lock()
 	defer cloudProber.Unlock()
-	return cloudProber.prober.Probes, cloudProber.prober.Surfacers, cloudProber.prober.Servers
+	return cloudProber.prober.Probes, cloudProber.prober.GetSurfacers(), cloudProber.prober.Servers
 }
@@ -26,6 +26,7 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"runtime/debug"
 	"runtime/pprof"
 	"strconv"
 	"syscall"
@@ -48,8 +49,17 @@ var (
 	cpuprofile       = flag.String("cpuprof", "", "Write cpu profile to file")
 	memprofile       = flag.String("memprof", "", "Write heap profile to file")
 	configTest       = flag.Bool("configtest", false, "Dry run to test config file")
+	configTestStrict = flag.Bool("configtest_strict", false, "With --configtest, also run the semantic checks on the config, e.g. for duplicate probe names, and check that the secrets can be resolved and the surfacers' endpoints are reachable. Test fails if any errors are found; warnings are only reported")
 	dumpConfig       = flag.Bool("dumpconfig", false, "Dump processed config to stdout")
 	dumpConfigFormat = flag.String("dumpconfig_fmt", "textpb", "Dump config format (textpb, json, yaml)")
+	dumpConfigGzip   = flag.Bool("dumpconfig_gzip", false, "Gzip the dumped config. Compressed output is written to stdout as it is")
+	dumpConfigRedact = flag.Bool("dumpconfig_redact", false, "Redact secrets, i.e. values substituted from environment variables, in the dumped config")
+	dumpSchema       = flag.String("dump_schema", "", "Dump the config schema to stdout, in the given format, and exit. Supported formats: jsonschema. Schema can be used to validate the JSON and YAML configs, e.g. in the editors")
+	dumpConfigMode   = flag.String("dumpconfig_mode", "raw", "Dump config mode (raw, expanded). Raw config is processed using the config test sysvars. Expanded config is processed using the actual sysvars, and has the defaults and the effective probe settings filled in, and secrets redacted")
+	startupCheck     = flag.Bool("startup_check", false, "Run all probes once, export their results, and exit. Exit status is non-zero if any probe or surfacer fails")
+	startupCheckTime = flag.Duration("startup_check_timeout", time.Minute, "How long to wait for the probes to run in the startup check mode")
+	simulate         = flag.Bool("simulate", false, "Estimate the load generated by the config, without sending any probe traffic: number of targets, probes per second and bandwidth for each probe, and the surfacers' write rates. Targets are expanded, including the targets discovery")
+	simulateWait     = flag.Duration("simulate_discovery_wait", 10*time.Second, "How long to wait for the discovered targets of each probe in the simulate mode. Probes wait concurrently.")
 	testInstanceName = flag.String("test_instance_name", "ig-us-central1-a-01-0000", "Instance name example to be used in tests")
 
 	// configTestVars provides a sane set of sysvars for config testing.
@@ -61,6 +71,7 @@ var (
 var version string
 var buildTimestamp string
 var dirty string
+var commit string
 var l *logger.Logger
 
 func setupConfigTestVars() {
@@ -114,6 +125,21 @@ func setupProfiling() {
 	}(f)
 }
 
+// vcsRevision returns the VCS revision embedded in the binary by the Go
+// toolchain, if any. It's used if commit is not set through -ldflags.
+func vcsRevision() string {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, s := range bi.Settings {
+		if s.Key == "vcs.revision" {
+			return s.Value
+		}
+	}
+	return ""
+}
+
 func main() {
 	flag.Parse()
 
@@ -136,6 +162,10 @@ func main() {
 		}
 		runconfig.SetBuildTimestamp(time.Unix(ts, 0))
 	}
+	if commit == "" {
+		commit = vcsRevision()
+	}
+	runconfig.SetBuildCommit(commit)
 
 	if *versionFlag {
 		fmt.Println(runconfig.Version())
@@ -145,61 +175,129 @@ func main() {
 	if *buildInfoFlag {
 		fmt.Println(runconfig.Version())
 		fmt.Println("Built at: ", runconfig.BuildTimestamp())
+		fmt.Println("Commit: ", runconfig.BuildCommit())
 		return
 	}
 
 	setupConfigTestVars()
 
+	if *dumpSchema != "" {
+		out, err := cloudprober.ConfigSchema(*dumpSchema)
+		if err != nil {
+			l.Criticalf("Error generating config schema. Err: %v", err)
+		}
+		fmt.Println(string(out))
+		return
+	}
+
 	if *dumpConfig {
-		sysvars.Init(nil, configTestVars)
-		out, err := config.DumpConfig("", *dumpConfigFormat, sysvars.Vars())
+		var dumpOpts []config.DumpOption
+		switch *dumpConfigMode {
+		case "raw":
+			sysvars.Init(nil, configTestVars)
+		case "expanded":
+			sysvars.Init(nil, nil)
+			dumpOpts = append(dumpOpts, config.WithExpansion())
+		default:
+			l.Criticalf("Unknown --dumpconfig_mode: %s, should be one of: raw, expanded", *dumpConfigMode)
+		}
+		if *dumpConfigGzip {
+			dumpOpts = append(dumpOpts, config.WithGzip())
+		}
+		if *dumpConfigRedact {
+			dumpOpts = append(dumpOpts, config.WithRedaction())
+		}
+		out, compressed, err := config.DumpConfig("", *dumpConfigFormat, sysvars.Vars(), dumpOpts...)
 		if err != nil {
 			l.Criticalf("Error dumping config. Err: %v", err)
 		}
+		if compressed {
+			os.Stdout.Write(out)
+			return
+		}
 		fmt.Println(string(out))
 		return
 	}
 
 	if *configTest {
 		sysvars.Init(nil, configTestVars)
+		if *configTestStrict {
+			issues, err := config.StrictConfigTest("", sysvars.Vars())
+			if err != nil {
+				l.Criticalf("Config test failed. Err: %v", err)
+			}
+			for _, issue := range issues {
+				fmt.Println(issue.String())
+			}
+			if config.HasValidationErrors(issues) {
+				l.Critical("Config test failed: semantic checks found errors")
+			}
+			return
+		}
 		if err := config.ConfigTest("", sysvars.Vars()); err != nil {
 			l.Criticalf("Config test failed. Err: %v", err)
 		}
 		return
 	}
 
+	if *simulate {
+		report, err := cloudprober.Simulate(context.Background(), "", *simulateWait)
+		if err != nil {
+			l.Criticalf("Simulation failed. Err: %v", err)
+		}
+		if err := report.Write(os.Stdout); err != nil {
+			l.Criticalf("Error writing the simulation report. Err: %v", err)
+		}
+		return
+	}
+
 	setupProfiling()
 
 	if err := cloudprober.InitFromConfig(""); err != nil {
 		l.Criticalf("Error initializing cloudprober. Err: %v", err)
 	}
 
+	if *startupCheck {
+		if err := cloudprober.StartupCheck(context.Background(), *startupCheckTime); err != nil {
+			l.Criticalf("Startup check failed: %v", err)
+		}
+		l.Info("Startup check passed")
+		return
+	}
+
 	// web.Init sets up web UI for cloudprober.
 	if err := web.Init(); err != nil {
 		l.Criticalf("Error initializing web interface. Err: %v", err)
 	}
 
-	startCtx := context.Background()
-
 	if *stopTime == 0 {
 		*stopTime = time.Duration(cloudprober.GetConfig().GetStopTimeSec()) * time.Second
 	}
 
-	if *stopTime != 0 {
-		// Set up signal handling for the cancelation of the start context.
-		sigs := make(chan os.Signal, 1)
-		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
-		ctx, cancelF := context.WithCancel(startCtx)
-		startCtx = ctx
+	// Set up signal handling for the cancelation of the start context. On
+	// cancelation, surfacers flush the buffered metrics. We exit once they are
+	// done, or after the stop time, whichever comes first. Zero stop time
+	// means that we wait only for the surfacers' own flush timeouts.
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	startCtx, cancelF := context.WithCancel(context.Background())
 
-		go func() {
-			sig := <-sigs
-			l.Warningf("Received signal \"%v\", canceling the start context and waiting for %v before closing", sig, *stopTime)
-			cancelF()
-			time.Sleep(*stopTime)
-			os.Exit(0)
-		}()
-	}
+	go func() {
+		sig := <-sigs
+		l.Warningf("Received signal \"%v\", canceling the start context and waiting for the surfacers to flush", sig)
+		cancelF()
+
+		waitCtx := context.Background()
+		if *stopTime != 0 {
+			var cancelWait context.CancelFunc
+			waitCtx, cancelWait = context.WithTimeout(waitCtx, *stopTime)
+			defer cancelWait()
+		}
+		if err := cloudprober.Wait(waitCtx); err != nil {
+			l.Warningf("Error flushing the surfacers: %v", err)
+		}
+		os.Exit(0)
+	}()
 	cloudprober.Start(startCtx)
 
 	// Wait forever
@@ -0,0 +1,108 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+)
+
+var configAuditDir = flag.String("config_audit_dir", "", "If set, every successfully loaded config is written to this directory, in textpb format and with secrets redacted, for auditing.")
+
+// RedactedPlaceholder replaces the secret values in redacted configs.
+const RedactedPlaceholder = "<redacted>"
+
+// redactedConfig returns the config with secrets (values from envSecret
+// and secret template functions) redacted. Secrets are identified by their
+// placeholders in the parsed config, i.e. the config before env vars and
+// secrets substitution, so that only the substituted values are redacted,
+// even if the same values appear elsewhere in the config. Redacted config is
+// processed in the same way as the original config, using the same
+// variables. If envSecrets is false, env vars are substituted, and only the
+// secrets from the secret providers are redacted.
+func redactedConfig(cfg *configpb.ProberConfig, parsedConfig, format string, vars map[string]string, envSecrets bool) (*configpb.ProberConfig, error) {
+	hasEnvSecrets := envSecrets && EnvRegex.MatchString(parsedConfig)
+	if !hasEnvSecrets && !SecretRegex.MatchString(parsedConfig) {
+		return cfg, nil
+	}
+
+	configStr := SecretRegex.ReplaceAllString(parsedConfig, RedactedPlaceholder)
+	if envSecrets {
+		configStr = EnvRegex.ReplaceAllString(configStr, RedactedPlaceholder)
+	} else {
+		var err error
+		if configStr, err = substEnvVars(configStr, false, nil); err != nil {
+			return nil, err
+		}
+	}
+	redacted, err := configToProto(configStr, format)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing redacted config: %v", err)
+	}
+	if err := processConfig(redacted, vars, nil); err != nil {
+		return nil, fmt.Errorf("error processing redacted config: %v", err)
+	}
+	return redacted, nil
+}
+
+// WriteAuditConfig writes the loaded config, with secrets redacted, to a new
+// timestamped file in the --config_audit_dir directory. parsedConfig and
+// format are the processed config string and its format, as returned by
+// ParseConfig, and are used to identify secrets. vars are the variables the
+// config was parsed with. It's a no-op if --config_audit_dir is not set.
+func WriteAuditConfig(cfg *configpb.ProberConfig, parsedConfig, format string, vars map[string]string) (string, error) {
+	if *configAuditDir == "" {
+		return "", nil
+	}
+	return writeAuditConfig(*configAuditDir, time.Now(), cfg, parsedConfig, format, vars)
+}
+
+func writeAuditConfig(dir string, ts time.Time, cfg *configpb.ProberConfig, parsedConfig, format string, vars map[string]string) (string, error) {
+	redacted, err := redactedConfig(cfg, parsedConfig, format, vars, true)
+	if err != nil {
+		return "", err
+	}
+	b, err := marshalConfig(redacted, "textpb")
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("error creating config audit dir: %v", err)
+	}
+
+	// Config checksum (of the real config) makes the file name unique, even
+	// if two configs are loaded at the same time.
+	fileName := filepath.Join(dir, fmt.Sprintf("cloudprober-%s-%s.cfg", ts.UTC().Format("20060102T150405.000Z"), Checksum(cfg)))
+
+	// Audit files are created read-only and are never overwritten.
+	f, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0444)
+	if err != nil {
+		return "", fmt.Errorf("error creating config audit file: %v", err)
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return "", fmt.Errorf("error writing config audit file %s: %v", fileName, err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("error writing config audit file %s: %v", fileName, err)
+	}
+	return fileName, nil
+}
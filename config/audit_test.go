@@ -0,0 +1,104 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+func TestWriteAuditConfig(t *testing.T) {
+	os.Setenv("SECRET_AUDIT_TOKEN", "s3cr3t-token")
+	defer os.Unsetenv("SECRET_AUDIT_TOKEN")
+
+	content := `
+probe {
+  name: "p1"
+  type: HTTP
+  targets {
+    host_names: "www.example.com"
+  }
+  http_probe {
+    header {
+      key: "Authorization"
+      value: "Bearer {{ envSecret "SECRET_AUDIT_TOKEN" }}"
+    }
+  }
+}
+`
+	cfg, parsedConfig, err := ParseConfig(context.Background(), content, "textpb", nil, nil)
+	if err != nil {
+		t.Fatalf("Error parsing config: %v", err)
+	}
+	assert.Equal(t, "Bearer s3cr3t-token", cfg.GetProbe()[0].GetHttpProbe().GetHeader()["Authorization"])
+
+	dir := filepath.Join(t.TempDir(), "audit")
+	ts := time.Date(2023, 10, 14, 8, 20, 47, 0, time.UTC)
+
+	fileName, err := writeAuditConfig(dir, ts, cfg, parsedConfig, "textpb", nil)
+	if err != nil {
+		t.Fatalf("Error writing audit config: %v", err)
+	}
+	assert.True(t, strings.HasPrefix(filepath.Base(fileName), "cloudprober-20231014T082047.000Z-"), "audit file name: %s", fileName)
+
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("Error reading audit file: %v", err)
+	}
+	assert.NotContains(t, string(b), "s3cr3t-token")
+
+	auditCfg, err := configToProto(string(b), "textpb")
+	if err != nil {
+		t.Fatalf("Error parsing audit config: %v", err)
+	}
+	assert.Equal(t, "Bearer "+RedactedPlaceholder, auditCfg.GetProbe()[0].GetHttpProbe().GetHeader()["Authorization"])
+	assert.Equal(t, "www.example.com", auditCfg.GetProbe()[0].GetTargets().GetHostNames())
+
+	// Same config, same timestamp: audit files are never overwritten.
+	_, err = writeAuditConfig(dir, ts, cfg, parsedConfig, "textpb", nil)
+	assert.Error(t, err, "expected error on overwriting audit file")
+}
+
+func TestWriteAuditConfigNoSecrets(t *testing.T) {
+	cfg, err := configToProto(`probe { name: "p1" type: EXTERNAL }`, "textpb")
+	if err != nil {
+		t.Fatalf("Error parsing config: %v", err)
+	}
+
+	fileName, err := writeAuditConfig(t.TempDir(), time.Now(), cfg, prototext.Format(cfg), "textpb", nil)
+	if err != nil {
+		t.Fatalf("Error writing audit config: %v", err)
+	}
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("Error reading audit file: %v", err)
+	}
+	auditCfg, err := configToProto(string(b), "textpb")
+	assert.NoError(t, err)
+	assert.Equal(t, "p1", auditCfg.GetProbe()[0].GetName())
+}
+
+func TestWriteAuditConfigDisabled(t *testing.T) {
+	fileName, err := WriteAuditConfig(nil, "", "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "", fileName)
+}
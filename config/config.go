@@ -15,6 +15,13 @@
 package config
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -22,76 +29,157 @@ import (
 	"regexp"
 	"strings"
 
-	"cloud.google.com/go/compute/metadata"
 	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/config/secrets"
 	"github.com/cloudprober/cloudprober/internal/file"
 	"github.com/cloudprober/cloudprober/logger"
+	probespb "github.com/cloudprober/cloudprober/probes/proto"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
 	"sigs.k8s.io/yaml"
 )
 
 var (
-	configFile = flag.String("config_file", "", "Config file")
+	configFile    = flag.String("config_file", "", "Config file")
+	strictEnvVars = flag.Bool("strict_env_vars", false, "Fail config parsing if an environment variable placeholder refers to an undefined variable and has no default")
 )
 
 // EnvRegex is the regex used to find environment variable placeholders
 // in the config file. The placeholders are of the form **$<env_var_name>**,
-// and are added during Go template processing for envSecret functions.
-var EnvRegex = regexp.MustCompile(`\*\*\$([^*\s]+)\*\*`)
+// or **$<env_var_name>:-<default>** to fall back to a default value if the
+// variable is not defined, and are added during Go template processing for
+// envSecret functions.
+var EnvRegex = regexp.MustCompile(`\*\*\$([^*\s:]+)(?:(:-)([^*]*))?\*\*`)
+
+// SecretRegex is the regex used to find the secret placeholders in the config
+// file. The placeholders are of the form **secret:<provider>:<secret>**, and
+// are added during Go template processing for secret functions.
+var SecretRegex = regexp.MustCompile(`\*\*secret:([^*\s]+)\*\*`)
 
 const (
 	configMetadataKeyName = "cloudprober_config"
 	defaultConfigFile     = "/etc/cloudprober.cfg"
 )
 
-func readConfigFile(fileName string) (string, string, error) {
-	b, err := file.ReadFile(fileName)
+// readConfigFile reads the config file, expanding the include directives in
+// it, and returns its content and format. Remote reads are canceled if ctx
+// is done.
+func readConfigFile(ctx context.Context, fileName string) (string, string, error) {
+	b, err := file.ReadConfigFile(ctx, fileName)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Source markers are comments, which are not supported in JSON.
+	format := FormatFromFileName(fileName)
+	markers := format == "textpb" || format == "yaml"
+
+	content, err := expandIncludes(ctx, string(b), fileName, nil, markers)
 	if err != nil {
 		return "", "", err
 	}
+	if markers && content != string(b) {
+		content = sourceMarker(fileName, 1) + content
+	}
 
+	return content, format, nil
+}
+
+// FormatFromFileName returns the config format based on the file name
+// extension, or an empty string if extension doesn't tell the format.
+func FormatFromFileName(fileName string) string {
+	// Ignore the query part of the URLs, e.g. https://host/c.yaml?v=1.
+	if strings.Contains(fileName, "://") {
+		fileName, _, _ = strings.Cut(fileName, "?")
+	}
 	switch filepath.Ext(fileName) {
 	case ".pb.txt", ".cfg", ".textpb":
-		return string(b), "textpb", nil
+		return "textpb"
 	case ".json":
-		return string(b), "json", nil
+		return "json"
 	case ".yaml", ".yml":
-		return string(b), "yaml", nil
+		return "yaml"
 	}
-
-	return string(b), "", nil
+	return ""
 }
 
-func GetConfig(confFile string, l *logger.Logger) (content string, format string, err error) {
+// ConfigSource returns the config source specified by the user: confFile if
+// it's not empty, otherwise the value of the --config_file flag.
+func ConfigSource(confFile string) string {
 	if confFile != "" {
-		return readConfigFile(confFile)
+		return confFile
 	}
+	return *configFile
+}
 
-	if *configFile != "" {
-		return readConfigFile(*configFile)
+// GetConfig returns the config content and format. Config is read from
+// confFile if it's not empty, otherwise from the --config_file flag. Config
+// files can be remote, e.g. https://, gs://, or s3:// URLs, and the remote
+// config is cached in the --config_cache_file, if set.
+func GetConfig(ctx context.Context, confFile string, l *logger.Logger) (content string, format string, err error) {
+	if confFile == "" {
+		confFile = *configFile
+	}
+	if confFile != "" {
+		return readConfigWithCache(ctx, confFile, *configCacheFile, *configCacheMaxAge, l)
 	}
 
-	// On GCE first check if there is a config in custom metadata
-	// attributes.
-	if metadata.OnGCE() {
-		if config, err := ReadFromGCEMetadata(configMetadataKeyName); err != nil {
-			l.Infof("Error reading config from metadata. Err: %v", err)
-		} else {
-			return config, "", nil
-		}
+	// Check if there is a config in the cloud metadata, e.g. GCE custom
+	// metadata attributes.
+	if config, ok := readConfigFromMetadata(configMetadataKeyName, l); ok {
+		return config, "", nil
 	}
 
 	// If config not found in metadata, check default config on disk
 	if _, err := os.Stat(defaultConfigFile); !os.IsNotExist(err) {
-		return readConfigFile(defaultConfigFile)
+		return readConfigFile(ctx, defaultConfigFile)
 	}
 
 	l.Warningf("Config file %s not found. Using default config.", defaultConfigFile)
 	return DefaultConfig(), "textpb", nil
 }
 
+var (
+	// yamlBlockRe matches the YAML constructs that are not valid in textpb:
+	// keys with nested values, e.g. "probe:", and list items, e.g. "- name".
+	yamlBlockRe = regexp.MustCompile(`(?m)^\s*([A-Za-z_]\w*:\s*$|-\s)`)
+
+	// textpbMessageRe matches the beginning of a textpb message field, e.g.
+	// "probe {" or "probe: {".
+	textpbMessageRe = regexp.MustCompile(`(^|\s)[A-Za-z_]\w*\s*(:\s*)?[{<]`)
+
+	// yamlKeyRe matches YAML mapping keys, e.g. "name: dns". These are valid
+	// in textpb as well.
+	yamlKeyRe = regexp.MustCompile(`(?m)^\s*[A-Za-z_]\w*:\s`)
+)
+
+// detectConfigFormat guesses the config format from its content. Config is
+// considered JSON if it parses as a JSON object, and YAML if it begins with a
+// document marker (---), has YAML-only constructs (nested keys or lists), or
+// has YAML mapping keys but no textpb messages. Otherwise, it's considered
+// textpb.
+func detectConfigFormat(configStr string) string {
+	trimmed := strings.TrimSpace(configStr)
+	if strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed)) {
+		return "json"
+	}
+	if strings.HasPrefix(trimmed, "---") || yamlBlockRe.MatchString(configStr) {
+		return "yaml"
+	}
+	if !textpbMessageRe.MatchString(configStr) && yamlKeyRe.MatchString(configStr) {
+		return "yaml"
+	}
+	return "textpb"
+}
+
+// configToProto converts the config string to a ProberConfig proto. If config
+// format is not specified, it's detected from the config content.
 func configToProto(configStr, configFormat string) (*configpb.ProberConfig, error) {
+	if configFormat == "" {
+		configFormat = detectConfigFormat(configStr)
+	}
+
 	cfg := &configpb.ProberConfig{}
 	switch configFormat {
 	case "yaml":
@@ -99,10 +187,16 @@ func configToProto(configStr, configFormat string) (*configpb.ProberConfig, erro
 		if err != nil {
 			return nil, fmt.Errorf("error converting YAML config to JSON: %v", err)
 		}
+		if err := validateJSONDurations(jsonCfg); err != nil {
+			return nil, fmt.Errorf("invalid config: %v", err)
+		}
 		if err := protojson.Unmarshal(jsonCfg, cfg); err != nil {
 			return nil, fmt.Errorf("error unmarshaling intermediate JSON to proto: %v", err)
 		}
 	case "json":
+		if err := validateJSONDurations([]byte(configStr)); err != nil {
+			return nil, fmt.Errorf("invalid config: %v", err)
+		}
 		if err := protojson.Unmarshal([]byte(configStr), cfg); err != nil {
 			return nil, err
 		}
@@ -119,38 +213,107 @@ func ConfigTest(fileName string, baseVars map[string]string) error {
 	if fileName == "" {
 		fileName = *configFile
 	}
-	content, configFormat, err := readConfigFile(fileName)
+	content, configFormat, err := readConfigFile(context.Background(), fileName)
 	if err != nil {
 		return err
 	}
 
-	configStr, err := ParseTemplate(content, baseVars, func(v string) (string, error) {
-		return v + "-test-value", nil
-	})
+	_, _, err = parseConfig(content, configFormat, baseVars, fakeGCECustomMetadata, fakeSecret, nil)
+	return err
+}
 
-	if err != nil {
-		return err
-	}
+// fakeGCECustomMetadata stands in for the GCE metadata lookups while testing
+// the config.
+func fakeGCECustomMetadata(v string) (string, error) {
+	return v + "-test-value", nil
+}
 
-	_, err = configToProto(configStr, configFormat)
-	return err
+// fakeSecret stands in for the secret providers while testing the config, so
+// that config can be tested without access to the secrets.
+func fakeSecret(secretRef string) (string, error) {
+	if _, _, err := secrets.ParseRef(secretRef); err != nil {
+		return "", err
+	}
+	return secretRef + "-test-value", nil
 }
 
-func DumpConfig(fileName, outFormat string, baseVars map[string]string) ([]byte, error) {
+// StrictConfigTest is like ConfigTest, but it also runs the semantic checks
+// (see Validate) and the lint checks (see LintConfig) on the config, and
+// checks that the secrets can be resolved and the surfacers' endpoints are
+// reachable. It returns all the
+// issues found; error is returned only if the config couldn't be read or
+// parsed.
+func StrictConfigTest(fileName string, baseVars map[string]string) ([]ValidationError, error) {
 	if fileName == "" {
 		fileName = *configFile
 	}
-
-	content, configFormat, err := readConfigFile(fileName)
+	content, configFormat, err := readConfigFile(context.Background(), fileName)
 	if err != nil {
 		return nil, err
 	}
 
-	cfg, _, err := ParseConfig(content, configFormat, baseVars, nil)
+	// Secrets are resolved, to check that they are accessible.
+	cfg, _, err := renderConfig(content, configFormat, baseVars, fakeGCECustomMetadata, nil, nil)
 	if err != nil {
 		return nil, err
 	}
+	if err := expandConfig(cfg, baseVars, nil); err != nil {
+		return nil, err
+	}
+
+	issues := Validate(cfg)
+	if HasValidationErrors(issues) {
+		// Remaining checks would mostly repeat the issues found above.
+		return issues, nil
+	}
+	if err := checkConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	for _, w := range LintConfig(cfg) {
+		issues = append(issues, ValidationError{Path: w.Field, Severity: SeverityWarning, Message: w.Message})
+	}
+	return append(issues, CheckSurfacerEndpoints(cfg, defaultEndpointCheckTimeout)...), nil
+}
+
+type dumpOptions struct {
+	gzip   bool
+	redact bool
+	expand bool
+}
+
+// DumpOption configures the DumpConfig behavior.
+type DumpOption func(*dumpOptions)
+
+// WithGzip makes DumpConfig gzip the marshaled config.
+func WithGzip() DumpOption {
+	return func(do *dumpOptions) {
+		do.gzip = true
+	}
+}
+
+// WithRedaction makes DumpConfig replace the secrets, i.e. values substituted
+// from the environment variables placeholders (see envSecret), by the
+// RedactedPlaceholder. Secrets from the secret providers (see secret) are
+// always redacted.
+func WithRedaction() DumpOption {
+	return func(do *dumpOptions) {
+		do.redact = true
+	}
+}
+
+// WithExpansion makes DumpConfig return the expanded config, i.e. the config
+// with defaults and the effective per-probe settings filled in (see
+// ExpandedConfig). Expanded config is always redacted, as if WithRedaction
+// was given.
+func WithExpansion() DumpOption {
+	return func(do *dumpOptions) {
+		do.expand = true
+		do.redact = true
+	}
+}
 
+func marshalConfig(cfg *configpb.ProberConfig, outFormat string) ([]byte, error) {
 	switch outFormat {
 	case "yaml":
 		jsonCfg, err := protojson.Marshal(cfg)
@@ -167,40 +330,286 @@ func DumpConfig(fileName, outFormat string, baseVars map[string]string) ([]byte,
 	}
 }
 
+func gzipBytes(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Checksum returns a short checksum of the config. It changes whenever the
+// config changes.
+func Checksum(cfg *configpb.ProberConfig) string {
+	b, err := proto.MarshalOptions{Deterministic: true, AllowPartial: true}.Marshal(cfg)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+// DumpConfig parses the config file and returns the processed config in the
+// given format. If WithGzip option is given, returned bytes are gzipped and
+// second return value is set to true. If WithRedaction option is given,
+// secrets are redacted in the returned config. If WithExpansion option is
+// given, the expanded config is returned.
+func DumpConfig(fileName, outFormat string, baseVars map[string]string, opts ...DumpOption) ([]byte, bool, error) {
+	do := &dumpOptions{}
+	for _, opt := range opts {
+		opt(do)
+	}
+
+	if fileName == "" {
+		fileName = *configFile
+	}
+
+	content, configFormat, err := readConfigFile(context.Background(), fileName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Secrets from the secret providers are always redacted, so there is no
+	// need to resolve them.
+	cfg, parsedConfig, err := parseConfig(content, configFormat, baseVars, nil, fakeSecret, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if cfg, err = redactedConfig(cfg, parsedConfig, configFormat, baseVars, do.redact); err != nil {
+		return nil, false, err
+	}
+
+	if do.expand {
+		cfg = ExpandedConfig(cfg)
+	}
+
+	out, err := marshalConfig(cfg, outFormat)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !do.gzip {
+		return out, false, nil
+	}
+
+	compressed, err := gzipBytes(out)
+	if err != nil {
+		return nil, false, fmt.Errorf("error compressing config: %v", err)
+	}
+	return compressed, true, nil
+}
+
 // substEnvVars substitutes environment variables in the config string.
-func substEnvVars(configStr string, l *logger.Logger) string {
+// Placeholders for undefined variables are replaced by their default value,
+// if there is one. Otherwise, they are left as they are, or, in the strict
+// mode, an error is returned.
+func substEnvVars(configStr string, strict bool, l *logger.Logger) (string, error) {
 	m := EnvRegex.FindAllStringSubmatch(configStr, -1)
 	if len(m) == 0 {
-		return configStr
+		return configStr, nil
 	}
 
-	var envVars []string
+	var undefined []string
+	seen := make(map[string]bool)
 	for _, match := range m {
-		if len(match) != 2 {
+		// match[0] is the whole placeholder, match[2] is the default value
+		// separator, if any.
+		placeholder, v, hasDefault, defaultVal := match[0], match[1], match[2] != "", match[3]
+		if seen[placeholder] {
 			continue
 		}
-		fmt.Printf("Found env var: %v\n", match)
-		envVars = append(envVars, match[1]) // match[0] is the whole string.
-	}
+		seen[placeholder] = true
 
-	for _, v := range envVars {
 		envVal := os.Getenv(v)
 		if envVal == "" {
-			l.Warningf("Environment variable %s not defined, skipping substitution.", v)
-			continue
+			if !hasDefault {
+				if !strict {
+					l.Warningf("Environment variable %s not defined, skipping substitution.", v)
+				}
+				undefined = append(undefined, v)
+				continue
+			}
+			envVal = defaultVal
 		}
-		configStr = strings.ReplaceAll(configStr, "**$"+v+"**", envVal)
+		configStr = strings.ReplaceAll(configStr, placeholder, envVal)
+	}
+
+	if strict && len(undefined) > 0 {
+		return "", fmt.Errorf("environment variables not defined: %s", strings.Join(undefined, ", "))
+	}
+	return configStr, nil
+}
+
+// substSecrets substitutes the environment variables (see substEnvVars) and
+// the secrets in the config string. Secrets are resolved using the
+// resolveSecret function, and are masked in the logs.
+func substSecrets(configStr string, strictEnvVars bool, resolveSecret func(string) (string, error), l *logger.Logger) (string, error) {
+	configStr, err := substEnvVars(configStr, strictEnvVars, l)
+	if err != nil {
+		return "", err
+	}
+
+	resolved := make(map[string]string)
+	var resolveErr error
+	configStr = SecretRegex.ReplaceAllStringFunc(configStr, func(placeholder string) string {
+		secretRef := SecretRegex.FindStringSubmatch(placeholder)[1]
+		if v, ok := resolved[secretRef]; ok {
+			return v
+		}
+		v, err := resolveSecret(secretRef)
+		if err != nil {
+			resolveErr = errors.Join(resolveErr, err)
+			return placeholder
+		}
+		logger.MaskSecret(v)
+		resolved[secretRef] = v
+		return v
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return configStr, nil
+}
+
+// parseConfig renders the config and processes it (see processConfig).
+func parseConfig(content, format string, vars map[string]string, getGCECustomMetadata, resolveSecret func(string) (string, error), l *logger.Logger) (*configpb.ProberConfig, string, error) {
+	cfg, parsedConfig, err := renderConfig(content, format, vars, getGCECustomMetadata, resolveSecret, l)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := processConfig(cfg, vars, l); err != nil {
+		return nil, "", err
 	}
+	return cfg, parsedConfig, nil
+}
+
+// processConfig applies the matching overrides, expands the probe templates,
+// validates the references between the config sections, e.g. probe to
+// surfacer references, and resolves the probes' validator sets.
+func processConfig(cfg *configpb.ProberConfig, vars map[string]string, l *logger.Logger) error {
+	if err := expandConfig(cfg, vars, l); err != nil {
+		return err
+	}
+	return checkConfig(cfg)
+}
 
-	return configStr
+// ProcessConfig processes a config that was not parsed by ParseConfig, e.g.
+// config received as a proto from the gRPC config source (see
+// processConfig).
+func ProcessConfig(cfg *configpb.ProberConfig, vars map[string]string, l *logger.Logger) error {
+	return processConfig(cfg, vars, l)
 }
 
-func ParseConfig(content, format string, vars map[string]string, l *logger.Logger) (*configpb.ProberConfig, string, error) {
-	parsedConfig, err := ParseTemplate(content, vars, nil)
+// expandConfig applies the matching overrides and expands the probe
+// templates.
+func expandConfig(cfg *configpb.ProberConfig, vars map[string]string, l *logger.Logger) error {
+	if err := applyOverrides(cfg, vars, l); err != nil {
+		return fmt.Errorf("invalid config: %v", err)
+	}
+	if err := expandProbeTemplates(cfg); err != nil {
+		return fmt.Errorf("invalid config: %v", err)
+	}
+	return nil
+}
+
+// checkConfig validates the expanded config and resolves the probes'
+// validator sets.
+func checkConfig(cfg *configpb.ProberConfig) error {
+	if err := validateReferences(cfg); err != nil {
+		return fmt.Errorf("invalid config: %v", err)
+	}
+	if err := validateDurations(cfg); err != nil {
+		return fmt.Errorf("invalid config: %v", err)
+	}
+	if err := resolveValidatorSets(cfg); err != nil {
+		return fmt.Errorf("invalid config: %v", err)
+	}
+	return nil
+}
+
+// CheckProbe validates the probe against the parsed config, like
+// checkConfig does for the config's own probes, and resolves the probe's
+// validator sets. It's used for the probes that are added to the config
+// after parsing, e.g. from the Kubernetes Probe objects.
+func CheckProbe(cfg *configpb.ProberConfig, p *probespb.ProbeDef) error {
+	return checkConfig(&configpb.ProberConfig{
+		Probe:         []*probespb.ProbeDef{p},
+		Surfacer:      cfg.GetSurfacer(),
+		SharedTargets: cfg.GetSharedTargets(),
+		ValidatorSet:  cfg.GetValidatorSet(),
+	})
+}
+
+// renderConfig processes the config template and converts the result to a
+// ProberConfig proto. If the template refers to sharedTargets, template is
+// processed once more, this time with the shared targets discovered in the
+// first pass. Secrets are resolved using resolveSecret, secrets.Resolve by
+// default, once per config render.
+func renderConfig(content, format string, vars map[string]string, getGCECustomMetadata, resolveSecret func(string) (string, error), l *logger.Logger) (*configpb.ProberConfig, string, error) {
+	if resolveSecret == nil {
+		resolveSecret = func(secretRef string) (string, error) {
+			return secrets.Resolve(context.Background(), secretRef)
+		}
+	}
+	resolveSecret = memoizeSecrets(resolveSecret)
+
+	parsedConfig, err := parseTemplate(content, vars, nil, getGCECustomMetadata)
 	if err != nil {
-		return nil, "", fmt.Errorf("error parsing config file as Go template. Err: %v", err)
+		return nil, "", fmt.Errorf("error parsing config file as Go template. Err: %v", withSourceLocation(err, content, templateErrLineRe))
+	}
+
+	configStr, err := substSecrets(parsedConfig, *strictEnvVars, resolveSecret, l)
+	if err != nil {
+		return nil, "", err
+	}
+	cfg, err := configToProto(configStr, format)
+	err = withSourceLocation(err, configStr, configErrLineRe)
+	if err != nil || !usesTemplateTargets(content) {
+		return cfg, parsedConfig, err
 	}
 
-	cfg, err := configToProto(substEnvVars(parsedConfig, l), format)
-	return cfg, parsedConfig, err
+	tmplTargets := templateTargets(cfg, l)
+	if len(tmplTargets) == 0 {
+		return cfg, parsedConfig, nil
+	}
+
+	parsedConfig, err = parseTemplate(content, vars, tmplTargets, getGCECustomMetadata)
+	if err != nil {
+		return nil, "", fmt.Errorf("error parsing config file as Go template with targets. Err: %v", withSourceLocation(err, content, templateErrLineRe))
+	}
+	if configStr, err = substSecrets(parsedConfig, *strictEnvVars, resolveSecret, l); err != nil {
+		return nil, "", err
+	}
+	cfg, err = configToProto(configStr, format)
+	return cfg, parsedConfig, withSourceLocation(err, configStr, configErrLineRe)
+}
+
+// memoizeSecrets returns a resolve function that resolves every secret only
+// once, e.g. across the template passes in renderConfig. Errors are not
+// cached.
+func memoizeSecrets(resolve func(string) (string, error)) func(string) (string, error) {
+	resolved := make(map[string]string)
+	return func(ref string) (string, error) {
+		if v, ok := resolved[ref]; ok {
+			return v, nil
+		}
+		v, err := resolve(ref)
+		if err != nil {
+			return "", err
+		}
+		resolved[ref] = v
+		return v, nil
+	}
+}
+
+func ParseConfig(ctx context.Context, content, format string, vars map[string]string, l *logger.Logger) (*configpb.ProberConfig, string, error) {
+	resolveSecret := func(secretRef string) (string, error) {
+		return secrets.Resolve(ctx, secretRef)
+	}
+	return parseConfig(content, format, vars, nil, resolveSecret, l)
 }
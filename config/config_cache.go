@@ -0,0 +1,91 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudprober/cloudprober/internal/file"
+	"github.com/cloudprober/cloudprober/logger"
+)
+
+var (
+	configCacheFile   = flag.String("config_cache_file", "", "If set, the config fetched from a remote location (http(s)://, gs://, or s3://) is saved to this file, and is used as a fallback if a later fetch fails")
+	configCacheMaxAge = flag.Duration("config_cache_max_age", 0, "Maximum age of the cached config (--config_cache_file) for it to be used as a fallback. Zero means no limit.")
+)
+
+// saveConfigCache saves the config content to the cache file. File is written
+// atomically, through a temporary file in the same directory.
+func saveConfigCache(cacheFile, content string) error {
+	f, err := os.CreateTemp(filepath.Dir(cacheFile), filepath.Base(cacheFile)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), cacheFile)
+}
+
+// readConfigCache returns the cached config content, if the cache file exists
+// and is not older than maxAge.
+func readConfigCache(cacheFile string, maxAge time.Duration) (string, error) {
+	fi, err := os.Stat(cacheFile)
+	if err != nil {
+		return "", err
+	}
+	if age := time.Since(fi.ModTime()); maxAge > 0 && age > maxAge {
+		return "", fmt.Errorf("cached config %s is too old (age: %v, max age: %v)", cacheFile, age.Round(time.Second), maxAge)
+	}
+	b, err := os.ReadFile(cacheFile)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// readConfigWithCache reads the config file through readConfigFile. If the
+// file is remote and a cache file is configured, the fetched config is saved
+// to the cache file, and the cached config is returned if the fetch fails.
+func readConfigWithCache(ctx context.Context, fileName, cacheFile string, maxAge time.Duration, l *logger.Logger) (string, string, error) {
+	content, format, err := readConfigFile(ctx, fileName)
+	if cacheFile == "" || !file.IsRemote(fileName) {
+		return content, format, err
+	}
+
+	if err == nil {
+		if err := saveConfigCache(cacheFile, content); err != nil {
+			l.Warningf("Error saving the config fetched from %s to the cache file %s: %v", fileName, cacheFile, err)
+		}
+		return content, format, nil
+	}
+
+	cached, cacheErr := readConfigCache(cacheFile, maxAge)
+	if cacheErr != nil {
+		return "", "", fmt.Errorf("%v (cached config not usable: %v)", err, cacheErr)
+	}
+	l.Warningf("Error fetching config from %s: %v. Using the cached config from %s.", fileName, err, cacheFile)
+	return cached, FormatFromFileName(fileName), nil
+}
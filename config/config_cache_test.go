@@ -0,0 +1,77 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadConfigWithCache(t *testing.T) {
+	cfgContent := "probe {\n  name: \"remote\"\n}\n"
+	serverUp := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !serverUp {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(cfgContent))
+	}))
+	defer ts.Close()
+
+	cacheFile := filepath.Join(t.TempDir(), "cloudprober.cfg.cache")
+	cfgURL := ts.URL + "/cloudprober.cfg?version=1"
+
+	// No cache yet, fetch fails.
+	serverUp = false
+	_, _, err := readConfigWithCache(context.Background(), cfgURL, cacheFile, 0, nil)
+	assert.Error(t, err)
+
+	// Successful fetch populates the cache.
+	serverUp = true
+	content, format, err := readConfigWithCache(context.Background(), cfgURL, cacheFile, 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, cfgContent, content)
+	assert.Equal(t, "textpb", format)
+	b, err := os.ReadFile(cacheFile)
+	assert.NoError(t, err)
+	assert.Equal(t, cfgContent, string(b))
+
+	// Fetch fails, cached config is used.
+	serverUp = false
+	content, format, err = readConfigWithCache(context.Background(), cfgURL, cacheFile, time.Hour, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, cfgContent, content)
+	assert.Equal(t, "textpb", format)
+
+	// Cached config is too old.
+	oldTime := time.Now().Add(-2 * time.Hour)
+	assert.NoError(t, os.Chtimes(cacheFile, oldTime, oldTime))
+	_, _, err = readConfigWithCache(context.Background(), cfgURL, cacheFile, time.Hour, nil)
+	assert.ErrorContains(t, err, "too old")
+
+	// Local files are not cached.
+	localCacheFile := filepath.Join(t.TempDir(), "local.cache")
+	_, _, err = readConfigWithCache(context.Background(), "testdata/cloudprober_base.cfg", localCacheFile, 0, nil)
+	assert.NoError(t, err)
+	assert.NoFileExists(t, localCacheFile)
+}
@@ -16,12 +16,18 @@ package config
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/config/secrets"
 	"github.com/cloudprober/cloudprober/logger"
 	probespb "github.com/cloudprober/cloudprober/probes/proto"
 	surfacerspb "github.com/cloudprober/cloudprober/surfacers/proto"
@@ -34,7 +40,7 @@ import (
 func testConfigToProto(t *testing.T, fileName string) (*configpb.ProberConfig, error) {
 	t.Helper()
 
-	configStr, configFormat, err := readConfigFile(fileName)
+	configStr, configFormat, err := readConfigFile(context.Background(), fileName)
 	if err != nil {
 		t.Error(err)
 	}
@@ -104,6 +110,91 @@ func TestConfigToProto(t *testing.T) {
 	}
 }
 
+func TestDetectConfigFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		configStr string
+		want      string
+	}{
+		{
+			name:      "json",
+			configStr: `{"probe": [{"name": "dns_k8s", "type": "DNS"}]}`,
+			want:      "json",
+		},
+		{
+			name: "yaml",
+			configStr: `
+probe:
+  - name: dns_k8s
+    type: DNS
+    tcpProbe: {}`,
+			want: "yaml",
+		},
+		{
+			name:      "yaml_document_marker",
+			configStr: "---\nsysvars_interval_msec: 1000",
+			want:      "yaml",
+		},
+		{
+			name:      "yaml_flat",
+			configStr: `sysvars_interval_msec: 1000`,
+			want:      "yaml",
+		},
+		{
+			name: "textpb",
+			configStr: `
+probe {
+  name: "dns_k8s"
+  type: DNS
+}`,
+			want: "textpb",
+		},
+		{
+			// Valid textpb with YAML-like "key: value" fields, but not JSON.
+			name:      "textpb_single_line",
+			configStr: `sysvars_interval_msec: 1000 probe { name: "dns_k8s" type: DNS }`,
+			want:      "textpb",
+		},
+		{
+			name: "textpb_colon",
+			configStr: `
+probe: {
+  name: "dns_k8s"
+  type: DNS
+}`,
+			want: "textpb",
+		},
+		{
+			name:      "empty",
+			configStr: "",
+			want:      "textpb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectConfigFormat(tt.configStr))
+		})
+	}
+
+	// Configs in the testdata should parse the same without the format.
+	for _, fileName := range []string{"testdata/cloudprober.cfg", "testdata/cloudprober.yaml", "testdata/cloudprober.json"} {
+		t.Run(fileName, func(t *testing.T) {
+			configStr, configFormat, err := readConfigFile(context.Background(), fileName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := configToProto(configStr, configFormat)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := configToProto(configStr, "")
+			assert.NoError(t, err)
+			assert.Equal(t, want.String(), got.String())
+		})
+	}
+}
+
 func TestConfigTest(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -192,7 +283,8 @@ surfacer: {
 	}
 	for _, tt := range tests {
 		t.Run(tt.format, func(t *testing.T) {
-			got, err := DumpConfig(tt.configFile, tt.format, nil)
+			got, compressed, err := DumpConfig(tt.configFile, tt.format, nil)
+			assert.False(t, compressed, "compressed")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("DumpConfig() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -219,6 +311,93 @@ surfacer: {
 	}
 }
 
+func TestDumpConfigGzip(t *testing.T) {
+	want, _, err := DumpConfig("testdata/cloudprober_base.cfg", "textpb", nil)
+	assert.NoError(t, err)
+
+	got, compressed, err := DumpConfig("testdata/cloudprober_base.cfg", "textpb", nil, WithGzip())
+	assert.NoError(t, err)
+	assert.True(t, compressed, "compressed")
+
+	r, err := gzip.NewReader(bytes.NewReader(got))
+	assert.NoError(t, err)
+	uncompressed, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(uncompressed))
+}
+
+func TestDumpConfigRedaction(t *testing.T) {
+	// Secret value is same as the probe name, to make sure that only the
+	// substituted value is redacted.
+	os.Setenv("SECRET_DUMP_TOKEN", "p1")
+	defer os.Unsetenv("SECRET_DUMP_TOKEN")
+
+	fileName := filepath.Join(t.TempDir(), "cloudprober.cfg")
+	content := `
+probe {
+  name: "p1"
+  type: HTTP
+  targets {
+    host_names: "p1"
+  }
+  http_probe {
+    header {
+      key: "Authorization"
+      value: "{{ envSecret "SECRET_DUMP_TOKEN" }}"
+    }
+  }
+}
+override {
+  selector {
+    key: "env"
+    value: "prod"
+  }
+  config {
+    surfacer {
+      type: PROMETHEUS
+    }
+  }
+}
+`
+	if err := os.WriteFile(fileName, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	vars := map[string]string{"env": "prod"}
+
+	for _, format := range []string{"yaml", "json", "textpb"} {
+		t.Run(format, func(t *testing.T) {
+			out, _, err := DumpConfig(fileName, format, vars)
+			if err != nil {
+				t.Fatalf("DumpConfig() error: %v", err)
+			}
+			wantCfg, err := configToProto(string(out), format)
+			if err != nil {
+				t.Fatalf("Error parsing dumped config: %v", err)
+			}
+
+			out, _, err = DumpConfig(fileName, format, vars, WithRedaction())
+			if err != nil {
+				t.Fatalf("DumpConfig() error: %v", err)
+			}
+			gotCfg, err := configToProto(string(out), format)
+			if err != nil {
+				t.Fatalf("Error parsing redacted config: %v", err)
+			}
+
+			probe := gotCfg.GetProbe()[0]
+			assert.Equal(t, RedactedPlaceholder, probe.GetHttpProbe().GetHeader()["Authorization"])
+			assert.Equal(t, "p1", probe.GetName())
+			assert.Equal(t, "p1", probe.GetTargets().GetHostNames())
+
+			// Except for the secret, redacted config is same as the original,
+			// including the applied override.
+			assert.Len(t, gotCfg.GetSurfacer(), 1)
+			probe.GetHttpProbe().GetHeader()["Authorization"] = "p1"
+			assert.True(t, proto.Equal(wantCfg, gotCfg), "got: %v, want: %v", gotCfg, wantCfg)
+		})
+	}
+}
+
 func TestSubstEnvVars(t *testing.T) {
 	os.Setenv("SECRET_PROBE_NAME1", "testprobe")
 	os.Setenv("SECRET_PROBE_NAME2", "x")
@@ -229,8 +408,10 @@ func TestSubstEnvVars(t *testing.T) {
 	tests := []struct {
 		name      string
 		configStr string
+		strict    bool
 		want      string
 		wantLog   string
+		wantErr   bool
 	}{
 		{
 			name:      "no_env_vars",
@@ -269,13 +450,184 @@ func TestSubstEnvVars(t *testing.T) {
 			want:      `probe {name: "**$SECRET_PROBEX_NAME**"}`,
 			wantLog:   "SECRET_PROBEX_NAME not defined",
 		},
+		{
+			name:      "env_var_default",
+			configStr: `probe {name: "**$SECRET_PROBEX_NAME:-localhost:5432**-**$SECRET_PROBE_NAME2:-y**"}`,
+			want:      `probe {name: "localhost:5432-x"}`,
+		},
+		{
+			name:      "env_var_empty_default",
+			configStr: `probe {name: "p**$SECRET_PROBEX_NAME:-**"}`,
+			want:      `probe {name: "p"}`,
+		},
+		{
+			name:      "strict_env_var_default",
+			configStr: `probe {name: "**$SECRET_PROBEX_NAME:-default**"}`,
+			strict:    true,
+			want:      `probe {name: "default"}`,
+		},
+		{
+			name:      "strict_env_var_not_defined",
+			configStr: `probe {name: "**$SECRET_PROBE_NAME1**-**$SECRET_PROBEX_NAME**"}`,
+			strict:    true,
+			wantErr:   true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var buf bytes.Buffer
 			l := logger.New(logger.WithWriter(&buf))
-			assert.Equal(t, tt.want, substEnvVars(tt.configStr, l))
+			got, err := substEnvVars(tt.configStr, tt.strict, l)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "SECRET_PROBEX_NAME")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
 			assert.Contains(t, buf.String(), tt.wantLog)
 		})
 	}
 }
+
+func TestParseConfigStrictEnvVars(t *testing.T) {
+	os.Unsetenv("SECRET_PROBEX_NAME")
+	defer func(v bool) { *strictEnvVars = v }(*strictEnvVars)
+
+	content := `probe {
+		name: "{{ envSecret "SECRET_PROBEX_NAME" }}"
+		type: PING
+		targets { host_names: "localhost" }
+	}`
+
+	*strictEnvVars = false
+	_, _, err := ParseConfig(context.Background(), content, "textpb", nil, nil)
+	assert.NoError(t, err)
+
+	*strictEnvVars = true
+	_, _, err = ParseConfig(context.Background(), content, "textpb", nil, nil)
+	assert.ErrorContains(t, err, "environment variables not defined: SECRET_PROBEX_NAME")
+}
+
+type testSecretProvider map[string]string
+
+func (tp testSecretProvider) Secret(ctx context.Context, ref string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if v, ok := tp[ref]; ok {
+		return v, nil
+	}
+	return "", errors.New("secret not found")
+}
+
+func TestParseConfigSecrets(t *testing.T) {
+	secrets.Register("testcfg", testSecretProvider{"api_token": "s3cr3t-token"})
+
+	content := `probe {
+		name: "p1"
+		type: HTTP
+		targets { host_names: "localhost" }
+		http_probe {
+			header {
+				key: "Authorization"
+				value: "Bearer {{ secret "testcfg:api_token" }}"
+			}
+		}
+	}`
+	cfg, parsedConfig, err := ParseConfig(context.Background(), content, "textpb", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer s3cr3t-token", cfg.GetProbe()[0].GetHttpProbe().GetHeader()["Authorization"])
+	assert.NotContains(t, parsedConfig, "s3cr3t-token")
+
+	// Resolved secrets are masked in the logs.
+	var buf bytes.Buffer
+	logger.New(logger.WithWriter(&buf)).Infof("Authorization: Bearer s3cr3t-token")
+	assert.NotContains(t, buf.String(), "s3cr3t-token")
+	assert.Contains(t, buf.String(), logger.MaskedSecret)
+
+	// Secrets are not resolved while testing or dumping the config.
+	fileName := filepath.Join(t.TempDir(), "cloudprober.cfg")
+	if err := os.WriteFile(fileName, []byte(strings.ReplaceAll(content, "api_token", "missing_token")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, ConfigTest(fileName, nil))
+	out, _, err := DumpConfig(fileName, "textpb", nil)
+	assert.NoError(t, err)
+	dumpCfg, err := configToProto(string(out), "textpb")
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer "+RedactedPlaceholder, dumpCfg.GetProbe()[0].GetHttpProbe().GetHeader()["Authorization"])
+
+	_, _, err = ParseConfig(context.Background(), strings.ReplaceAll(content, "api_token", "missing_token"), "textpb", nil, nil)
+	assert.ErrorContains(t, err, "error resolving secret testcfg:missing_token: secret not found")
+
+	_, _, err = ParseConfig(context.Background(), strings.ReplaceAll(content, "testcfg:", "nocfg:"), "textpb", nil, nil)
+	assert.ErrorContains(t, err, "unknown secret provider nocfg")
+
+	// Secret resolution uses the parsing context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = ParseConfig(ctx, content, "textpb", nil, nil)
+	assert.ErrorContains(t, err, "error resolving secret testcfg:api_token: context canceled")
+}
+
+func TestParseConfigSecretsResolvedOnce(t *testing.T) {
+	secrets.Register("testcfg", testSecretProvider{})
+
+	// Config template is processed twice, as it uses sharedTargets.
+	content := `
+	shared_targets {
+		name: "web"
+		targets { host_names: "web-1" }
+	}
+	probe {
+		name: "static"
+		type: HTTP
+		targets { host_names: "localhost" }
+		http_probe {
+			header {
+				key: "Authorization"
+				value: "Bearer {{ secret "testcfg:api_token" }}"
+			}
+		}
+	}
+	{{range index sharedTargets "web"}}
+	probe {
+		name: "{{.Name}}"
+		type: HTTP
+		targets { host_names: "{{.Name}}" }
+		http_probe {
+			header {
+				key: "Authorization"
+				value: "Bearer {{ secret "testcfg:api_token" }}"
+			}
+		}
+	}
+	{{end}}`
+
+	var numResolved int
+	resolve := func(ref string) (string, error) {
+		numResolved++
+		return "s3cr3t-token", nil
+	}
+	cfg, _, err := parseConfig(content, "textpb", nil, nil, resolve, nil)
+	assert.NoError(t, err)
+	assert.Len(t, cfg.GetProbe(), 2)
+	assert.Equal(t, "Bearer s3cr3t-token", cfg.GetProbe()[1].GetHttpProbe().GetHeader()["Authorization"])
+	assert.Equal(t, 1, numResolved, "number of secret resolutions")
+}
+
+func TestChecksum(t *testing.T) {
+	cfg1 := &configpb.ProberConfig{
+		Probe: []*probespb.ProbeDef{{Name: proto.String("p1")}},
+	}
+	cfg2 := &configpb.ProberConfig{
+		Probe: []*probespb.ProbeDef{{Name: proto.String("p1")}},
+	}
+	cfg3 := &configpb.ProberConfig{
+		Probe: []*probespb.ProbeDef{{Name: proto.String("p2")}},
+	}
+
+	assert.Len(t, Checksum(cfg1), 16, "checksum length")
+	assert.Equal(t, Checksum(cfg1), Checksum(cfg2), "checksum for same configs")
+	assert.NotEqual(t, Checksum(cfg1), Checksum(cfg3), "checksum for different configs")
+}
@@ -114,6 +114,97 @@ Cloudprober configs support some macros to make configs construction easier:
 
 		{{end}}
 		{{end}}
+
+	secret
+		Refers to a secret in a secrets provider: HashiCorp Vault (vault),
+		AWS Secrets Manager (awssm) or GCP Secret Manager (gcpsm). Secrets are
+		resolved at the config load time, and are masked in the logs and in the
+		--dumpconfig output. See the secrets package for the reference format.
+
+		http_probe {
+		  header {
+		    key: "Authorization"
+		    value: "Bearer {{secret "vault:kv/probes/api_token"}}"
+		  }
+		}
+
+	include
+		Inlines the content of another config file, e.g. to split probe
+		definitions across multiple files. Relative paths are resolved relative
+		to the including file's directory. Included files can include other
+		files too, but include cycles are not allowed. Includes are expanded
+		before the template processing, so included files can use the macros
+		and variables as well. File path should be a quoted string.
+
+		# Probes owned by team-a.
+		{{include "probes/team-a.cfg"}}
+
+# Cloud metadata variables
+
+Cloudprober detects the cloud provider it's running on (GCE, AWS EC2 or Azure,
+controlled by the --cloud_metadata flag) and adds the following variables to
+the template data. These variables are always set; off-cloud they are empty.
+
+	cloud_provider: gce, ec2 or azure.
+	region:         region of the instance, e.g. us-central1, us-east-1, eastus.
+	zone:           zone of the instance, e.g. us-central1-a, us-east-1a, 1.
+	instance_id:    provider assigned ID of the instance.
+
+Example:
+
+	probe {
+	  name: "http_{{.region}}"
+	  type: HTTP
+	  targets {
+	    host_names: "service.{{.region}}.example.com"
+	  }
+	}
+
+Provider specific variables, e.g. EC2_InstanceType or Azure_VMSize, are
+available as well.
+
+# Command-line variables
+
+Variables can also be set on the command line using the repeatable --var flag,
+e.g. for quick local testing of config changes:
+
+	cloudprober --config_file=cloudprober.cfg --var env=prod --var region=us
+
+Variables set through --var take precedence over all other variables, i.e.
+system and cloud metadata variables above, and the test variables used by
+--configtest and --dumpconfig. Environment variables are not template
+variables; they are read using the env macro, irrespective of --var.
+
+# Targets
+
+Endpoints of the static (host_names, endpoints) and file based shared targets
+are available to the config template through the sharedTargets function. It
+returns a map from the shared targets name to the list of endpoints. Each
+endpoint has Name, IP, Port and Labels fields. Being a function, it doesn't
+clash with the template variables, e.g. a "targets" variable. Example:
+
+	shared_targets {
+	  name: "web"
+	  targets {
+	    file_targets {
+	      file_path: "/var/run/cloudprober/web.textpb"
+	    }
+	  }
+	}
+
+	{{range index sharedTargets "web"}}
+	probe {
+	  name: "http-{{.Name}}"
+	  type: HTTP
+	  targets {
+	    host_names: "{{.Name}}"
+	  }
+	}
+	{{end}}
+
+Note that targets are discovered only once, while parsing the config at startup.
+Changes to the targets later, e.g. file updates, are not reflected in the
+generated config. Other targets types are not available to templates.
 */
 package config
 
@@ -126,6 +217,10 @@ import (
 	"cloud.google.com/go/compute/metadata"
 	"github.com/Masterminds/sprig/v3"
 	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/config/secrets"
+	"github.com/cloudprober/cloudprober/internal/sysvars"
+	surfacerspb "github.com/cloudprober/cloudprober/surfacers/proto"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
 	"google.golang.org/protobuf/encoding/prototext"
 )
 
@@ -148,14 +243,43 @@ var ReadFromGCEMetadata = func(metadataKeyName string) (string, error) {
 	return metadata.ProjectAttributeValue(metadataKeyName)
 }
 
-// DefaultConfig returns the default config string.
+// cloudSurfacers are the surfacers that we add to the default config on the
+// cloud providers, so that a zero-config launch on a cloud VM exports metrics
+// to the provider's monitoring service.
+var cloudSurfacers = map[string]surfacerspb.Type{
+	"gce": surfacerspb.Type_STACKDRIVER,
+	"ec2": surfacerspb.Type_CLOUDWATCH,
+}
+
+// DefaultConfig returns the default config string. On the cloud providers
+// that have a monitoring surfacer (see cloudSurfacers), default config adds
+// that surfacer to the default surfacers (prometheus and file).
 func DefaultConfig() string {
-	b, _ := prototext.Marshal(&configpb.ProberConfig{})
+	return defaultConfig(sysvars.Vars()["cloud_provider"])
+}
+
+func defaultConfig(cloudProvider string) string {
+	cfg := &configpb.ProberConfig{}
+	if sType, ok := cloudSurfacers[cloudProvider]; ok {
+		// Default surfacers are used only if no surfacer is configured, so we
+		// add them explicitly.
+		for _, t := range []surfacerspb.Type{surfacerspb.Type_PROMETHEUS, surfacerspb.Type_FILE, sType} {
+			cfg.Surfacer = append(cfg.Surfacer, &surfacerspb.SurfacerDef{Type: t.Enum()})
+		}
+	}
+	b, _ := prototext.Marshal(cfg)
 	return string(b)
 }
 
 // ParseTemplate processes a config file as a Go text template.
 func ParseTemplate(config string, sysVars map[string]string, getGCECustomMetadata func(string) (string, error)) (string, error) {
+	return parseTemplate(config, sysVars, nil, getGCECustomMetadata)
+}
+
+// parseTemplate processes a config file as a Go text template. Template data
+// consists of the sysVars. Shared targets' endpoints are available through
+// the sharedTargets function.
+func parseTemplate(config string, sysVars map[string]string, tmplTargets map[string][]endpoint.Endpoint, getGCECustomMetadata func(string) (string, error)) (string, error) {
 	if getGCECustomMetadata == nil {
 		getGCECustomMetadata = ReadFromGCEMetadata
 	}
@@ -189,6 +313,30 @@ func ParseTemplate(config string, sysVars map[string]string, getGCECustomMetadat
 			return matches[n], nil
 		},
 		"envSecret": func(s string) string { return "**$" + s + "**" },
+
+		// secret refers to a secret in a secrets provider, e.g.
+		// {{secret "vault:kv/probes/api_token"}}. Like envSecret, it adds a
+		// placeholder that's substituted after the template processing.
+		"secret": func(s string) (string, error) {
+			if _, _, err := secrets.ParseRef(s); err != nil {
+				return "", err
+			}
+			return "**secret:" + s + "**", nil
+		},
+
+		// include directives are expanded while reading the config file. If
+		// we are here, include was used outside a config file, or with a
+		// non-literal path.
+		"include": func(s string) (string, error) {
+			return "", fmt.Errorf("include %s: include is supported only in config files, with a quoted file path", s)
+		},
+
+		"sharedTargets": func() map[string][]endpoint.Endpoint {
+			if tmplTargets == nil {
+				return map[string][]endpoint.Endpoint{}
+			}
+			return tmplTargets
+		},
 	}
 
 	for name, f := range sprig.TxtFuncMap() {
@@ -201,8 +349,14 @@ func ParseTemplate(config string, sysVars map[string]string, getGCECustomMetadat
 	if err != nil {
 		return "", err
 	}
+
+	data := make(map[string]interface{}, len(sysVars))
+	for k, v := range sysVars {
+		data[k] = v
+	}
+
 	var b bytes.Buffer
-	if err := configTmpl.Execute(&b, sysVars); err != nil {
+	if err := configTmpl.Execute(&b, data); err != nil {
 		return "", err
 	}
 	return b.String(), nil
@@ -15,11 +15,15 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"cloud.google.com/go/compute/metadata"
 	configpb "github.com/cloudprober/cloudprober/config/proto"
+	surfacerspb "github.com/cloudprober/cloudprober/surfacers/proto"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/encoding/prototext"
 )
@@ -181,3 +185,121 @@ probe {
 	assert.Len(t, cfg.GetProbe(), 1, "number of probes")
 	assert.Equal(t, "google_dot_com_from-undefined", cfg.GetProbe()[0].GetName(), "probe name")
 }
+
+func TestParseConfigWithTemplateTargets(t *testing.T) {
+	targetsFile := filepath.Join(t.TempDir(), "targets.textpb")
+	os.WriteFile(targetsFile, []byte(`
+		resource {
+		  name: "web-1"
+		  port: 8080
+		}
+		resource {
+		  name: "web-2"
+		  port: 8081
+		}
+	`), 0644)
+
+	config := `
+shared_targets {
+  name: "dns"
+  targets {
+    host_names: "dns-1,dns-2"
+  }
+}
+shared_targets {
+  name: "web"
+  targets {
+    file_targets {
+      file_path: "` + targetsFile + `"
+    }
+  }
+}
+shared_targets {
+  name: "rds"
+  targets {
+    rds_targets {
+      resource_path: "gcp://gce_instances/p1"
+    }
+  }
+}
+{{range $name, $eps := sharedTargets}}
+{{range $eps}}
+probe {
+  name: "{{$name}}-{{.Name}}{{if .Port}}-{{.Port}}{{end}}"
+  type: HTTP
+  targets {
+    host_names: "{{.Name}}"
+  }
+}
+{{end}}
+{{end}}
+`
+	// "targets" variable is not shadowed by the shared targets.
+	config += `
+probe {
+  name: "{{.targets}}"
+  type: HTTP
+  targets {
+    host_names: "{{range index sharedTargets "dns"}}{{.Name}}{{end}}"
+  }
+}
+`
+	cfg, _, err := ParseConfig(context.Background(), config, "textpb", map[string]string{"targets": "var-targets"}, nil)
+	assert.NoError(t, err)
+
+	var names []string
+	for _, p := range cfg.GetProbe() {
+		names = append(names, p.GetName())
+	}
+	assert.Equal(t, []string{"dns-dns-1", "dns-dns-2", "web-web-1-8080", "web-web-2-8081", "var-targets"}, names)
+	assert.Equal(t, "dns-1dns-2", cfg.GetProbe()[4].GetTargets().GetHostNames())
+
+	// Listers are reused across the parses.
+	numListers := len(templateListers)
+	_, _, err = ParseConfig(context.Background(), config, "textpb", map[string]string{"targets": "var-targets"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, numListers, len(templateListers), "number of template listers")
+}
+
+func TestUsesTemplateTargets(t *testing.T) {
+	assert.True(t, usesTemplateTargets("{{range index sharedTargets \"web\"}}"))
+	assert.True(t, usesTemplateTargets("{{range $n, $e := sharedTargets}}"))
+	assert.False(t, usesTemplateTargets("{{.sharedTargetsFile}}"))
+	assert.False(t, usesTemplateTargets("{{range .targets}}"))
+	assert.False(t, usesTemplateTargets("probe { targets { host_names: \"a\" } }"))
+}
+
+func TestDefaultConfig(t *testing.T) {
+	tests := []struct {
+		cloudProvider string
+		wantSurfacers []surfacerspb.Type
+	}{
+		{
+			cloudProvider: "",
+		},
+		{
+			cloudProvider: "azure",
+		},
+		{
+			cloudProvider: "gce",
+			wantSurfacers: []surfacerspb.Type{surfacerspb.Type_PROMETHEUS, surfacerspb.Type_FILE, surfacerspb.Type_STACKDRIVER},
+		},
+		{
+			cloudProvider: "ec2",
+			wantSurfacers: []surfacerspb.Type{surfacerspb.Type_PROMETHEUS, surfacerspb.Type_FILE, surfacerspb.Type_CLOUDWATCH},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.cloudProvider, func(t *testing.T) {
+			cfg := &configpb.ProberConfig{}
+			assert.NoError(t, prototext.Unmarshal([]byte(defaultConfig(test.cloudProvider)), cfg))
+
+			var gotSurfacers []surfacerspb.Type
+			for _, s := range cfg.GetSurfacer() {
+				gotSurfacers = append(gotSurfacers, s.GetType())
+			}
+			assert.Equal(t, test.wantSurfacers, gotSurfacers)
+		})
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package etcdsource implements a config source that reads cloudprober's config
+from an etcd key, and watches the key for updates. Unlike the gRPC source,
+config is stored as text, so it goes through the regular config parsing,
+including the template processing.
+*/
+package etcdsource
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudprober/cloudprober/config"
+	pb "github.com/cloudprober/cloudprober/config/etcdsource/proto"
+	"github.com/cloudprober/cloudprober/internal/file"
+	"github.com/cloudprober/cloudprober/internal/tlsconfig"
+	"github.com/cloudprober/cloudprober/logger"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+var clientConfFile = flag.String("config_etcd_client_conf", "", "Client config (ClientConf textproto) for the etcd config source. Used only if config file is an etcd:// URL.")
+
+// Scheme is the prefix that identifies an etcd config source.
+const Scheme = "etcd://"
+
+const (
+	minRetryDelay = time.Second
+	maxRetryDelay = time.Minute
+)
+
+// IsSource returns true if the given config file refers to an etcd config
+// source.
+func IsSource(configFile string) bool {
+	return strings.HasPrefix(configFile, Scheme)
+}
+
+// etcdClient is the part of the etcd client that we use. It allows us to use
+// a fake client in tests.
+type etcdClient interface {
+	Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error)
+	Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan
+	Close() error
+}
+
+// Source is an etcd config source.
+type Source struct {
+	endpoints []string
+	key       string
+	c         *pb.ClientConf
+	client    etcdClient
+	vars      map[string]string
+	l         *logger.Logger
+
+	// Last etcd revision seen by us. Watch starts from the next revision.
+	revision int64
+}
+
+func readClientConf(fileName string) (*pb.ClientConf, error) {
+	c := &pb.ClientConf{}
+	if fileName == "" {
+		return c, nil
+	}
+	b, err := file.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if err := prototext.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("error parsing client config file %s: %v", fileName, err)
+	}
+	return c, nil
+}
+
+// parseURL parses an etcd config source URL of the form
+// etcd://host1:port1[,host2:port2...]/key. Key is whatever follows the first
+// slash after the endpoints, e.g. key for etcd://etcd:2379//cloudprober/cfg is
+// "/cloudprober/cfg".
+func parseURL(configFile string) ([]string, string, error) {
+	hosts, key, _ := strings.Cut(strings.TrimPrefix(configFile, Scheme), "/")
+	if hosts == "" || key == "" {
+		return nil, "", fmt.Errorf("invalid config source %s, should be of the form %shost:port/key", configFile, Scheme)
+	}
+	return strings.Split(hosts, ","), key, nil
+}
+
+func clientConfig(endpoints []string, c *pb.ClientConf) (clientv3.Config, error) {
+	cfg := clientv3.Config{
+		Endpoints:   endpoints,
+		DialTimeout: time.Duration(c.GetTimeoutSec()) * time.Second,
+		Username:    c.GetUsername(),
+		Password:    c.GetPassword(),
+	}
+
+	if c.GetTlsConfig() != nil {
+		cfg.TLS = &tls.Config{}
+		if err := tlsconfig.UpdateTLSConfig(cfg.TLS, c.GetTlsConfig()); err != nil {
+			return cfg, fmt.Errorf("error initializing TLS config (%+v): %v", c.GetTlsConfig(), err)
+		}
+	}
+	return cfg, nil
+}
+
+// New creates a new etcd config source for the given etcd:// URL. Client
+// config is read from the file specified by the --config_etcd_client_conf
+// flag. Vars are used for the config template processing.
+func New(configFile string, vars map[string]string, l *logger.Logger) (*Source, error) {
+	c, err := readClientConf(*clientConfFile)
+	if err != nil {
+		return nil, fmt.Errorf("etcdsource: %v", err)
+	}
+
+	endpoints, key, err := parseURL(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("etcdsource: %v", err)
+	}
+
+	cfg, err := clientConfig(endpoints, c)
+	if err != nil {
+		return nil, fmt.Errorf("etcdsource: %v", err)
+	}
+	client, err := clientv3.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("etcdsource: error creating etcd client for %v: %v", endpoints, err)
+	}
+
+	return newSource(endpoints, key, c, client, vars, l), nil
+}
+
+func newSource(endpoints []string, key string, c *pb.ClientConf, client etcdClient, vars map[string]string, l *logger.Logger) *Source {
+	return &Source{
+		endpoints: endpoints,
+		key:       key,
+		c:         c,
+		client:    client,
+		vars:      vars,
+		l:         l,
+	}
+}
+
+// format returns the config format. See format_key_suffix in ClientConf for
+// how the format is determined.
+func (s *Source) format(ctx context.Context) (string, error) {
+	formatKey := s.key + s.c.GetFormatKeySuffix()
+	resp, err := s.client.Get(ctx, formatKey)
+	if err != nil {
+		return "", fmt.Errorf("error reading format key %s: %v", formatKey, err)
+	}
+	if len(resp.Kvs) == 0 {
+		return config.FormatFromFileName(s.key), nil
+	}
+
+	switch format := strings.TrimSpace(string(resp.Kvs[0].Value)); format {
+	case "textpb", "json", "yaml":
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid config format %q in the key %s", format, formatKey)
+	}
+}
+
+// GetConfig reads the config and its format from etcd.
+func (s *Source) GetConfig(ctx context.Context) (string, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.c.GetTimeoutSec())*time.Second)
+	defer cancel()
+
+	resp, err := s.client.Get(ctx, s.key)
+	if err != nil {
+		return "", "", fmt.Errorf("etcdsource: error reading config key %s from %v: %v", s.key, s.endpoints, err)
+	}
+	if len(resp.Kvs) == 0 {
+		return "", "", fmt.Errorf("etcdsource: config key %s not found in %v", s.key, s.endpoints)
+	}
+
+	format, err := s.format(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("etcdsource: %v", err)
+	}
+	s.revision = resp.Header.GetRevision()
+	return string(resp.Kvs[0].Value), format, nil
+}
+
+// update parses the updated config and calls f with it. Invalid configs are
+// logged and skipped.
+func (s *Source) update(ctx context.Context, content string, f func(*config.Update)) {
+	format, err := s.format(ctx)
+	if err != nil {
+		s.l.Errorf("etcdsource: ignoring config update: %v", err)
+		return
+	}
+	cfg, parsedConfig, err := config.ParseConfig(ctx, content, format, s.vars, s.l)
+	if err != nil {
+		s.l.Errorf("etcdsource: ignoring invalid config update from the key %s: %v", s.key, err)
+		return
+	}
+	f(&config.Update{Config: cfg, Content: content, ParsedConfig: parsedConfig, Format: format})
+}
+
+// resync re-reads the config after we've missed updates, e.g. because the
+// revisions we were watching from have been compacted.
+func (s *Source) resync(ctx context.Context, f func(*config.Update)) error {
+	lastRevision := s.revision
+	resp, err := s.client.Get(ctx, s.key)
+	if err != nil {
+		return err
+	}
+	s.revision = resp.Header.GetRevision()
+	if len(resp.Kvs) != 0 && resp.Kvs[0].ModRevision > lastRevision {
+		s.update(ctx, string(resp.Kvs[0].Value), f)
+	}
+	return nil
+}
+
+// watchOnce watches the config key and calls f for every config update. It
+// returns when the watch breaks.
+func (s *Source) watchOnce(ctx context.Context, f func(*config.Update)) error {
+	ctx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+	defer cancel()
+
+	for resp := range s.client.Watch(ctx, s.key, clientv3.WithRev(s.revision+1)) {
+		if resp.CompactRevision != 0 {
+			s.l.Warningf("etcdsource: revisions up to %d have been compacted, re-reading config", resp.CompactRevision)
+			if err := s.resync(ctx, f); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := resp.Err(); err != nil {
+			return err
+		}
+
+		for _, ev := range resp.Events {
+			s.revision = ev.Kv.ModRevision
+			if ev.Type == clientv3.EventTypeDelete {
+				s.l.Warningf("etcdsource: config key %s deleted, keeping the current config", s.key)
+				continue
+			}
+			s.update(ctx, string(ev.Kv.Value), f)
+		}
+	}
+	return errors.New("watch channel closed")
+}
+
+// Watch watches the config key for updates and calls f for every new config.
+// Watch re-establishes the watch, with exponential backoff, if it breaks. It
+// returns only when the context is canceled or watch is disabled in the
+// client config.
+func (s *Source) Watch(ctx context.Context, f func(*config.Update)) {
+	if !s.c.GetWatch() {
+		return
+	}
+
+	delay := minRetryDelay
+	for {
+		start := time.Now()
+		err := s.watchOnce(ctx, f)
+		if ctx.Err() != nil {
+			return
+		}
+		// Reset backoff if the watch was up for a while.
+		if time.Since(start) > maxRetryDelay {
+			delay = minRetryDelay
+		}
+		s.l.Warningf("etcdsource: watch on the key %s broke (%v), retrying in %v", s.key, err, delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
+
+// Close closes the etcd client.
+func (s *Source) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,239 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdsource
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/config"
+	pb "github.com/cloudprober/cloudprober/config/etcdsource/proto"
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/stretchr/testify/assert"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+type fakeClient struct {
+	mu       sync.Mutex
+	kvs      map[string]*mvccpb.KeyValue
+	revision int64
+
+	watchCh  chan clientv3.WatchResponse
+	watchRev chan int64
+}
+
+func newFakeClient(kvs map[string]string) *fakeClient {
+	fc := &fakeClient{
+		kvs:      make(map[string]*mvccpb.KeyValue),
+		watchCh:  make(chan clientv3.WatchResponse),
+		watchRev: make(chan int64, 10),
+	}
+	for k, v := range kvs {
+		fc.put(k, v)
+	}
+	return fc
+}
+
+func (fc *fakeClient) put(key, value string) *mvccpb.KeyValue {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.revision++
+	kv := &mvccpb.KeyValue{Key: []byte(key), Value: []byte(value), ModRevision: fc.revision}
+	fc.kvs[key] = kv
+	return kv
+}
+
+func (fc *fakeClient) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	resp := &clientv3.GetResponse{Header: &etcdserverpb.ResponseHeader{Revision: fc.revision}}
+	if kv, ok := fc.kvs[key]; ok {
+		resp.Kvs = []*mvccpb.KeyValue{kv}
+	}
+	return resp, nil
+}
+
+func (fc *fakeClient) Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	fc.watchRev <- clientv3.OpGet(key, opts...).Rev()
+	return fc.watchCh
+}
+
+func (fc *fakeClient) Close() error { return nil }
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		configFile    string
+		wantEndpoints []string
+		wantKey       string
+		wantErr       bool
+	}{
+		{
+			configFile:    "etcd://etcd:2379/cloudprober.cfg",
+			wantEndpoints: []string{"etcd:2379"},
+			wantKey:       "cloudprober.cfg",
+		},
+		{
+			configFile:    "etcd://etcd-1:2379,etcd-2:2379//cloudprober/config",
+			wantEndpoints: []string{"etcd-1:2379", "etcd-2:2379"},
+			wantKey:       "/cloudprober/config",
+		},
+		{configFile: "etcd://etcd:2379", wantErr: true},
+		{configFile: "etcd://etcd:2379/", wantErr: true},
+		{configFile: "etcd:///cloudprober.cfg", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.configFile, func(t *testing.T) {
+			endpoints, key, err := parseURL(test.configFile)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantEndpoints, endpoints)
+			assert.Equal(t, test.wantKey, key)
+		})
+	}
+}
+
+func TestGetConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        string
+		kvs        map[string]string
+		wantFormat string
+		wantErr    bool
+	}{
+		{
+			name:       "format_key",
+			key:        "cloudprober",
+			kvs:        map[string]string{"cloudprober": "probe: []", "cloudprober.format": "yaml\n"},
+			wantFormat: "yaml",
+		},
+		{
+			name:       "format_key_overrides_extension",
+			key:        "cloudprober.cfg",
+			kvs:        map[string]string{"cloudprober.cfg": "{}", "cloudprober.cfg.format": "json"},
+			wantFormat: "json",
+		},
+		{
+			name:       "extension",
+			key:        "/cloudprober/config.yaml",
+			kvs:        map[string]string{"/cloudprober/config.yaml": "probe: []"},
+			wantFormat: "yaml",
+		},
+		{
+			name:       "unknown",
+			key:        "cloudprober",
+			kvs:        map[string]string{"cloudprober": "probe {}"},
+			wantFormat: "",
+		},
+		{
+			name:    "invalid_format",
+			key:     "cloudprober",
+			kvs:     map[string]string{"cloudprober": "probe {}", "cloudprober.format": "toml"},
+			wantErr: true,
+		},
+		{
+			name:    "missing_key",
+			key:     "cloudprober",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newSource([]string{"etcd:2379"}, test.key, &pb.ClientConf{}, newFakeClient(test.kvs), nil, nil)
+			content, format, err := s.GetConfig(context.Background())
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.kvs[test.key], content)
+			assert.Equal(t, test.wantFormat, format)
+			assert.Equal(t, int64(len(test.kvs)), s.revision)
+		})
+	}
+}
+
+func TestWatch(t *testing.T) {
+	fc := newFakeClient(map[string]string{"cloudprober.cfg": `grpc_port: 9314`})
+	s := newSource([]string{"etcd:2379"}, "cloudprober.cfg", &pb.ClientConf{}, fc, map[string]string{"port": "9315"}, nil)
+
+	_, _, err := s.GetConfig(context.Background())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates := make(chan *configpb.ProberConfig, 10)
+	go s.Watch(ctx, func(u *config.Update) { updates <- u.Config })
+
+	assert.Equal(t, int64(2), <-fc.watchRev, "watch revision")
+
+	sendEvent := func(typ mvccpb.Event_EventType, value string) {
+		t.Helper()
+		kv := fc.put("cloudprober.cfg", value)
+		fc.watchCh <- clientv3.WatchResponse{Events: []*clientv3.Event{{Type: typ, Kv: kv}}}
+	}
+	wantUpdate := func(wantPort int32) {
+		t.Helper()
+		select {
+		case cfg := <-updates:
+			assert.Equal(t, wantPort, cfg.GetGrpcPort())
+		case <-time.After(time.Second):
+			t.Fatalf("didn't get the config update with grpc_port %d", wantPort)
+		}
+	}
+
+	// Config is processed as a template.
+	sendEvent(mvccpb.PUT, `grpc_port: {{.port}}`)
+	wantUpdate(9315)
+
+	// Invalid config and deletion are ignored.
+	sendEvent(mvccpb.PUT, `grpc_port: "invalid"`)
+	sendEvent(mvccpb.DELETE, "")
+	sendEvent(mvccpb.PUT, `grpc_port: 9316`)
+	wantUpdate(9316)
+	assert.Equal(t, fc.revision, s.revision)
+
+	// Compaction: config is re-read.
+	fc.put("cloudprober.cfg", `grpc_port: 9317`)
+	fc.watchCh <- clientv3.WatchResponse{CompactRevision: fc.revision, Canceled: true}
+	wantUpdate(9317)
+
+	// Watch is re-established from the next revision after the channel is
+	// closed.
+	close(fc.watchCh)
+	select {
+	case rev := <-fc.watchRev:
+		assert.Equal(t, fc.revision+1, rev, "watch revision")
+	case <-time.After(3 * time.Second):
+		t.Fatal("watch was not re-established")
+	}
+	assert.Len(t, updates, 0)
+}
+
+func TestWatchDisabled(t *testing.T) {
+	fc := newFakeClient(nil)
+	s := newSource([]string{"etcd:2379"}, "cloudprober.cfg", &pb.ClientConf{Watch: new(bool)}, fc, nil, nil)
+	s.Watch(context.Background(), func(*config.Update) {})
+	assert.Len(t, fc.watchRev, 0)
+}
@@ -0,0 +1,235 @@
+// This file defines the client config for the etcd config source. To use
+// etcd as the config source, start cloudprober with the config file set to
+// an etcd:// URL, e.g.:
+//   cloudprober --config_file=etcd://etcd-1:2379,etcd-2:2379/cloudprober.cfg
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.21.5
+// source: github.com/cloudprober/cloudprober/config/etcdsource/proto/config.proto
+
+package proto
+
+import (
+	proto "github.com/cloudprober/cloudprober/internal/tlsconfig/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ClientConf configures the connection to the etcd cluster. It's read from
+// the file specified by the --config_etcd_client_conf flag.
+type ClientConf struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// TLS config. If not specified, an insecure connection is used.
+	TlsConfig *proto.TLSConfig `protobuf:"bytes,1,opt,name=tls_config,json=tlsConfig" json:"tls_config,omitempty"`
+	// Username and password for the etcd authentication.
+	Username *string `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
+	Password *string `protobuf:"bytes,3,opt,name=password" json:"password,omitempty"`
+	// Whether to watch the config key for updates. If enabled, probes are
+	// added, removed, or re-created as the config changes in etcd. Changes to
+	// other parts of the config require a restart.
+	Watch *bool `protobuf:"varint,4,opt,name=watch,def=1" json:"watch,omitempty"`
+	// Suffix of the companion key that specifies the config format (textpb,
+	// json or yaml), e.g. config in "cloudprober.cfg" with the format in
+	// "cloudprober.cfg.format". If companion key doesn't exist, format is
+	// inferred from the config key's extension (.cfg, .textpb, .json, .yaml
+	// or .yml), and failing that, from the config content.
+	FormatKeySuffix *string `protobuf:"bytes,5,opt,name=format_key_suffix,json=formatKeySuffix,def=.format" json:"format_key_suffix,omitempty"`
+	// Timeout for connecting to etcd and for the initial config read.
+	TimeoutSec *int32 `protobuf:"varint,6,opt,name=timeout_sec,json=timeoutSec,def=30" json:"timeout_sec,omitempty"`
+}
+
+// Default values for ClientConf fields.
+const (
+	Default_ClientConf_Watch           = bool(true)
+	Default_ClientConf_FormatKeySuffix = string(".format")
+	Default_ClientConf_TimeoutSec      = int32(30)
+)
+
+func (x *ClientConf) Reset() {
+	*x = ClientConf{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientConf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientConf) ProtoMessage() {}
+
+func (x *ClientConf) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientConf.ProtoReflect.Descriptor instead.
+func (*ClientConf) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ClientConf) GetTlsConfig() *proto.TLSConfig {
+	if x != nil {
+		return x.TlsConfig
+	}
+	return nil
+}
+
+func (x *ClientConf) GetUsername() string {
+	if x != nil && x.Username != nil {
+		return *x.Username
+	}
+	return ""
+}
+
+func (x *ClientConf) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+func (x *ClientConf) GetWatch() bool {
+	if x != nil && x.Watch != nil {
+		return *x.Watch
+	}
+	return Default_ClientConf_Watch
+}
+
+func (x *ClientConf) GetFormatKeySuffix() string {
+	if x != nil && x.FormatKeySuffix != nil {
+		return *x.FormatKeySuffix
+	}
+	return Default_ClientConf_FormatKeySuffix
+}
+
+func (x *ClientConf) GetTimeoutSec() int32 {
+	if x != nil && x.TimeoutSec != nil {
+		return *x.TimeoutSec
+	}
+	return Default_ClientConf_TimeoutSec
+}
+
+var File_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto protoreflect.FileDescriptor
+
+var file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_rawDesc = []byte{
+	0x0a, 0x47, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x65, 0x74, 0x63, 0x64,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1d, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x65, 0x74,
+	0x63, 0x64, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x1a, 0x48, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
+	0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x74, 0x6c, 0x73, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0xfb, 0x01, 0x0a, 0x0a, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e,
+	0x66, 0x12, 0x3f, 0x0a, 0x0a, 0x74, 0x6c, 0x73, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f,
+	0x62, 0x65, 0x72, 0x2e, 0x74, 0x6c, 0x73, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x54, 0x4c,
+	0x53, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x09, 0x74, 0x6c, 0x73, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x1a, 0x0a, 0x05, 0x77, 0x61,
+	0x74, 0x63, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x3a, 0x04, 0x74, 0x72, 0x75, 0x65, 0x52,
+	0x05, 0x77, 0x61, 0x74, 0x63, 0x68, 0x12, 0x33, 0x0a, 0x11, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74,
+	0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x73, 0x75, 0x66, 0x66, 0x69, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x3a, 0x07, 0x2e, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x0f, 0x66, 0x6f, 0x72, 0x6d,
+	0x61, 0x74, 0x4b, 0x65, 0x79, 0x53, 0x75, 0x66, 0x66, 0x69, 0x78, 0x12, 0x23, 0x0a, 0x0b, 0x74,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05,
+	0x3a, 0x02, 0x33, 0x30, 0x52, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63,
+	0x42, 0x3c, 0x5a, 0x3a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x65, 0x74,
+	0x63, 0x64, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+}
+
+var (
+	file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_rawDescOnce sync.Once
+	file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_rawDescData = file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_rawDesc
+)
+
+func file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_rawDescGZIP() []byte {
+	file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_rawDescOnce.Do(func() {
+		file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_rawDescData)
+	})
+	return file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_rawDescData
+}
+
+var file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_goTypes = []interface{}{
+	(*ClientConf)(nil),      // 0: cloudprober.config.etcdsource.ClientConf
+	(*proto.TLSConfig)(nil), // 1: cloudprober.tlsconfig.TLSConfig
+}
+var file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_depIdxs = []int32{
+	1, // 0: cloudprober.config.etcdsource.ClientConf.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_init() }
+func file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_init() {
+	if File_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClientConf); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_goTypes,
+		DependencyIndexes: file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_depIdxs,
+		MessageInfos:      file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_msgTypes,
+	}.Build()
+	File_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto = out.File
+	file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_rawDesc = nil
+	file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_goTypes = nil
+	file_github_com_cloudprober_cloudprober_config_etcdsource_proto_config_proto_depIdxs = nil
+}
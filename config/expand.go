@@ -0,0 +1,125 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/probes/options"
+	probespb "github.com/cloudprober/cloudprober/probes/proto"
+	surfacerspb "github.com/cloudprober/cloudprober/surfacers/proto"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ExpandedConfig returns a copy of the processed config (see ParseConfig)
+// with the effective settings made explicit, for inspection and debugging:
+//   - unset fields that have a default value in the proto are set to it.
+//   - probes' interval and timeout are set to their effective values, as
+//     durations.
+//   - probes without targets, that run without them, get dummy_targets.
+//   - default and required surfacers are added, if not configured.
+//
+// Expanded config is equivalent to the original config, but it's not meant
+// to be used as a config as defaults may change between releases.
+func ExpandedConfig(cfg *configpb.ProberConfig) *configpb.ProberConfig {
+	cfg = proto.Clone(cfg).(*configpb.ProberConfig)
+
+	for _, p := range cfg.GetProbe() {
+		expandProbe(p)
+	}
+	cfg.Surfacer = expandedSurfacers(cfg.GetSurfacer())
+
+	fillDefaults(cfg.ProtoReflect())
+	return cfg
+}
+
+// expandProbe sets the probe's interval, timeout and targets to their
+// effective values. Invalid durations are left as such.
+func expandProbe(p *probespb.ProbeDef) {
+	if d, _, ok := probeDuration(p.GetIntervalMsec(), p.GetInterval(), options.DefaultInterval, "", "interval"); ok {
+		p.IntervalMsec, p.Interval = nil, proto.String(d.String())
+	}
+	if d, _, ok := probeDuration(p.GetTimeoutMsec(), p.GetTimeout(), options.DefaultTimeout, "", "timeout"); ok {
+		p.TimeoutMsec, p.Timeout = nil, proto.String(d.String())
+	}
+
+	if p.GetTargets() == nil {
+		switch p.GetType() {
+		case probespb.ProbeDef_USER_DEFINED, probespb.ProbeDef_EXTERNAL, probespb.ProbeDef_EXTENSION:
+			p.Targets = &targetspb.TargetsDef{Type: &targetspb.TargetsDef_DummyTargets{DummyTargets: &targetspb.DummyTargets{}}}
+		}
+	}
+}
+
+// expandedSurfacers returns the surfacers that will run for the configured
+// surfacers, see surfacers.effectiveDefs.
+func expandedSurfacers(sDefs []*surfacerspb.SurfacerDef) []*surfacerspb.SurfacerDef {
+	found := make(map[string]bool)
+	for _, s := range sDefs {
+		found[SurfacerRefName(s)] = true
+	}
+
+	var names []string
+	if len(sDefs) == 0 {
+		names = defaultSurfacerNames
+	}
+	for _, name := range requiredSurfacerNames {
+		if !found[name] {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		sType := surfacerspb.Type(surfacerspb.Type_value[strings.ToUpper(name)])
+		sDefs = append(sDefs, &surfacerspb.SurfacerDef{Type: sType.Enum()})
+	}
+	return sDefs
+}
+
+// fillDefaults sets the unset singular scalar fields that have a default
+// value, in the message and all its set sub-messages. Fields that are part
+// of a oneof are left alone, as setting them would change the oneof.
+func fillDefaults(m protoreflect.Message) {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.HasDefault() && !fd.IsList() && fd.ContainingOneof() == nil && !m.Has(fd) {
+			m.Set(fd, fd.Default())
+		}
+	}
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					fillDefaults(mv.Message())
+					return true
+				})
+			}
+		case fd.Message() == nil:
+		case fd.IsList():
+			for j := 0; j < v.List().Len(); j++ {
+				fillDefaults(v.List().Get(j).Message())
+			}
+		default:
+			fillDefaults(v.Message())
+		}
+		return true
+	})
+}
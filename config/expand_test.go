@@ -0,0 +1,137 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	probespb "github.com/cloudprober/cloudprober/probes/proto"
+	surfacerspb "github.com/cloudprober/cloudprober/surfacers/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExpandedConfig(t *testing.T) {
+	cfg, err := configToProto(`
+		probe {
+			name: "p1"
+			type: HTTP
+			interval_msec: 5000
+			targets {
+				host_names: "www.example.com"
+			}
+			http_probe {
+				relative_url: "/health"
+			}
+		}
+		probe {
+			name: "p2"
+			type: EXTERNAL
+			interval: "1m"
+			timeout: "bad"
+			external_probe {
+				command: "./probe.sh"
+			}
+		}`, "textpb")
+	assert.NoError(t, err)
+	orig := proto.Clone(cfg)
+
+	expanded := ExpandedConfig(cfg)
+	assert.True(t, proto.Equal(orig, cfg), "original config modified")
+
+	p1, p2 := expanded.GetProbe()[0], expanded.GetProbe()[1]
+	assert.Equal(t, "5s", p1.GetInterval())
+	assert.Nil(t, p1.IntervalMsec)
+	assert.Equal(t, "1s", p1.GetTimeout())
+	assert.Equal(t, "www.example.com", p1.GetTargets().GetHostNames())
+	assert.NotNil(t, p1.GetHttpProbe().Method, "http_probe.method default not filled")
+	assert.Nil(t, p1.GetHttpProbe().GetSchemeType(), "oneof fields shouldn't be filled")
+	assert.Equal(t, probespb.ProbeDef_IGNORE, p1.GetNoTargetsPolicy())
+	assert.NotNil(t, p1.NoTargetsPolicy)
+
+	assert.Equal(t, "1m0s", p2.GetInterval())
+	assert.Equal(t, "bad", p2.GetTimeout(), "invalid timeout should be left as is")
+	assert.NotNil(t, p2.GetTargets().GetDummyTargets())
+
+	var sTypes []surfacerspb.Type
+	for _, s := range expanded.GetSurfacer() {
+		sTypes = append(sTypes, s.GetType())
+	}
+	assert.Equal(t, []surfacerspb.Type{surfacerspb.Type_PROMETHEUS, surfacerspb.Type_FILE, surfacerspb.Type_PROBESTATUS}, sTypes)
+}
+
+func TestExpandedSurfacers(t *testing.T) {
+	for _, test := range []struct {
+		desc  string
+		sDefs []*surfacerspb.SurfacerDef
+		want  []surfacerspb.Type
+	}{
+		{
+			desc:  "disabled_defaults",
+			sDefs: []*surfacerspb.SurfacerDef{{}},
+			want:  []surfacerspb.Type{surfacerspb.Type_NONE, surfacerspb.Type_PROBESTATUS},
+		},
+		{
+			desc:  "configured_probestatus",
+			sDefs: []*surfacerspb.SurfacerDef{{Type: surfacerspb.Type_PROBESTATUS.Enum()}},
+			want:  []surfacerspb.Type{surfacerspb.Type_PROBESTATUS},
+		},
+		{
+			desc:  "configured_stackdriver",
+			sDefs: []*surfacerspb.SurfacerDef{{Type: surfacerspb.Type_STACKDRIVER.Enum()}},
+			want:  []surfacerspb.Type{surfacerspb.Type_STACKDRIVER, surfacerspb.Type_PROBESTATUS},
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			var got []surfacerspb.Type
+			for _, s := range expandedSurfacers(test.sDefs) {
+				got = append(got, s.GetType())
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestDumpConfigExpanded(t *testing.T) {
+	t.Setenv("TEST_SECRET_TOKEN", "secret-token-value")
+
+	fileName := filepath.Join(t.TempDir(), "cloudprober.cfg")
+	assert.NoError(t, os.WriteFile(fileName, []byte(`
+		probe {
+			name: "{{.probe_name}}"
+			type: HTTP
+			targets {
+				host_names: "www.example.com"
+			}
+			http_probe {
+				headers {
+					name: "Authorization"
+					value: "**$TEST_SECRET_TOKEN**"
+				}
+			}
+		}`), 0644))
+
+	out, _, err := DumpConfig(fileName, "textpb", map[string]string{"probe_name": "web"}, WithExpansion())
+	assert.NoError(t, err)
+
+	cfg, err := configToProto(string(out), "textpb")
+	assert.NoError(t, err)
+	p := cfg.GetProbe()[0]
+	assert.Equal(t, "web", p.GetName())
+	assert.Equal(t, "2s", p.GetInterval())
+	assert.Equal(t, RedactedPlaceholder, p.GetHttpProbe().GetHeaders()[0].GetValue())
+}
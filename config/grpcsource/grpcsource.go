@@ -0,0 +1,246 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package grpcsource implements a config source that fetches cloudprober's
+config from a central config service over gRPC. Config is received as a
+ProberConfig proto, so there is no text parsing or template processing
+involved, but the received config is processed like the other configs:
+overrides are applied, probe templates are expanded and references are
+validated (see config.ProcessConfig).
+*/
+package grpcsource
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cloudprober/cloudprober/config"
+	pb "github.com/cloudprober/cloudprober/config/grpcsource/proto"
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/internal/file"
+	"github.com/cloudprober/cloudprober/internal/oauth"
+	"github.com/cloudprober/cloudprober/internal/tlsconfig"
+	"github.com/cloudprober/cloudprober/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	grpcoauth "google.golang.org/grpc/credentials/oauth"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+var clientConfFile = flag.String("config_grpc_client_conf", "", "Client config (ClientConf textproto) for the gRPC config source. Used only if config file is a grpc:// URL.")
+
+// Scheme is the prefix that identifies a gRPC config source.
+const Scheme = "grpc://"
+
+const (
+	minRetryDelay = time.Second
+	maxRetryDelay = time.Minute
+)
+
+// IsSource returns true if the given config file refers to a gRPC config
+// source.
+func IsSource(configFile string) bool {
+	return strings.HasPrefix(configFile, Scheme)
+}
+
+// Source is a gRPC config source.
+type Source struct {
+	addr   string
+	c      *pb.ClientConf
+	conn   *grpc.ClientConn
+	client pb.ConfigServiceClient
+	req    *pb.GetConfigRequest
+	vars   map[string]string
+	l      *logger.Logger
+
+	lastVersion string
+}
+
+func readClientConf(fileName string) (*pb.ClientConf, error) {
+	c := &pb.ClientConf{}
+	if fileName == "" {
+		return c, nil
+	}
+	b, err := file.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if err := prototext.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("error parsing client config file %s: %v", fileName, err)
+	}
+	return c, nil
+}
+
+func dialOpts(c *pb.ClientConf, l *logger.Logger) ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+
+	// Transport security options.
+	if c.GetTlsConfig() != nil {
+		tlsConfig := &tls.Config{}
+		if err := tlsconfig.UpdateTLSConfig(tlsConfig, c.GetTlsConfig()); err != nil {
+			return nil, fmt.Errorf("error initializing TLS config (%+v): %v", c.GetTlsConfig(), err)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	// OAuth related options.
+	if c.GetOauthConfig() != nil {
+		oauthTS, err := oauth.TokenSourceFromConfig(c.GetOauthConfig(), l)
+		if err != nil {
+			return nil, fmt.Errorf("error getting token source from OAuth config (%+v): %v", c.GetOauthConfig(), err)
+		}
+		opts = append(opts, grpc.WithPerRPCCredentials(grpcoauth.TokenSource{TokenSource: oauthTS}))
+	}
+
+	return opts, nil
+}
+
+// New creates a new gRPC config source for the given grpc:// URL. Client
+// config is read from the file specified by the --config_grpc_client_conf
+// flag. vars are the variables used for processing the received configs,
+// e.g. for matching the config overrides.
+func New(configFile string, vars map[string]string, l *logger.Logger) (*Source, error) {
+	c, err := readClientConf(*clientConfFile)
+	if err != nil {
+		return nil, fmt.Errorf("grpcsource: %v", err)
+	}
+	return newSource(configFile, c, vars, l)
+}
+
+func newSource(configFile string, c *pb.ClientConf, vars map[string]string, l *logger.Logger) (*Source, error) {
+	if !IsSource(configFile) {
+		return nil, fmt.Errorf("grpcsource: invalid config source %s, should be of the form %shost:port", configFile, Scheme)
+	}
+
+	s := &Source{
+		addr: strings.TrimPrefix(configFile, Scheme),
+		c:    c,
+		vars: vars,
+		l:    l,
+	}
+	if s.addr == "" {
+		return nil, fmt.Errorf("grpcsource: config server address is empty")
+	}
+
+	hostname, _ := os.Hostname()
+	s.req = &pb.GetConfigRequest{InstanceName: &hostname}
+
+	opts, err := dialOpts(c, l)
+	if err != nil {
+		return nil, fmt.Errorf("grpcsource: %v", err)
+	}
+	s.conn, err = grpc.Dial(s.addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("grpcsource: error connecting to config server (%s): %v", s.addr, err)
+	}
+	s.client = pb.NewConfigServiceClient(s.conn)
+
+	return s, nil
+}
+
+// GetConfig fetches the config from the config server.
+func (s *Source) GetConfig(ctx context.Context) (*configpb.ProberConfig, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.c.GetTimeoutSec())*time.Second)
+	defer cancel()
+
+	resp, err := s.client.GetConfig(ctx, s.req)
+	if err != nil {
+		return nil, fmt.Errorf("grpcsource: error getting config from %s: %v", s.addr, err)
+	}
+	if resp.GetConfig() == nil {
+		return nil, fmt.Errorf("grpcsource: config server %s returned empty config", s.addr)
+	}
+	if err := config.ProcessConfig(resp.GetConfig(), s.vars, s.l); err != nil {
+		return nil, fmt.Errorf("grpcsource: config from %s: %v", s.addr, err)
+	}
+	s.lastVersion = resp.GetVersion()
+	return resp.GetConfig(), nil
+}
+
+// watchOnce opens a config stream and calls f for every config update. It
+// returns when the stream breaks.
+func (s *Source) watchOnce(ctx context.Context, f func(*config.Update)) error {
+	stream, err := s.client.WatchConfig(ctx, s.req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if resp.GetConfig() == nil {
+			s.l.Warningf("grpcsource: ignoring empty config from %s", s.addr)
+			continue
+		}
+		if resp.GetVersion() != "" && resp.GetVersion() == s.lastVersion {
+			continue
+		}
+		if err := config.ProcessConfig(resp.GetConfig(), s.vars, s.l); err != nil {
+			s.l.Errorf("grpcsource: ignoring invalid config update from %s: %v", s.addr, err)
+			continue
+		}
+		s.lastVersion = resp.GetVersion()
+		cfgStr := prototext.Format(resp.GetConfig())
+		f(&config.Update{Config: resp.GetConfig(), Content: cfgStr, ParsedConfig: cfgStr, Format: "textpb"})
+	}
+}
+
+// Watch watches for config updates and calls f for every new config. Watch
+// re-establishes the stream, with exponential backoff, if it breaks. It
+// returns only when the context is canceled or watch is disabled in the
+// client config.
+func (s *Source) Watch(ctx context.Context, f func(*config.Update)) {
+	if !s.c.GetWatch() {
+		return
+	}
+
+	delay := minRetryDelay
+	for {
+		start := time.Now()
+		err := s.watchOnce(ctx, f)
+		if ctx.Err() != nil {
+			return
+		}
+		// Reset backoff if the stream was up for a while.
+		if time.Since(start) > maxRetryDelay {
+			delay = minRetryDelay
+		}
+		s.l.Warningf("grpcsource: config stream from %s broke (%v), retrying in %v", s.addr, err, delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
+
+// Close closes the connection to the config server.
+func (s *Source) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,188 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcsource
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/config"
+	pb "github.com/cloudprober/cloudprober/config/grpcsource/proto"
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	probespb "github.com/cloudprober/cloudprober/probes/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+type testServer struct {
+	pb.UnimplementedConfigServiceServer
+	updates chan *pb.GetConfigResponse
+	gotReq  *pb.GetConfigRequest
+}
+
+func (s *testServer) GetConfig(ctx context.Context, req *pb.GetConfigRequest) (*pb.GetConfigResponse, error) {
+	s.gotReq = req
+	return testResponse("v1", 10), nil
+}
+
+func (s *testServer) WatchConfig(req *pb.GetConfigRequest, stream pb.ConfigService_WatchConfigServer) error {
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case resp := <-s.updates:
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func testResponse(version string, grpcPort int32) *pb.GetConfigResponse {
+	return &pb.GetConfigResponse{
+		Version: proto.String(version),
+		Config:  &configpb.ProberConfig{GrpcPort: proto.Int32(grpcPort)},
+	}
+}
+
+func startTestServer(t *testing.T) (*testServer, string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("error starting listener: %v", err)
+	}
+
+	ts := &testServer{updates: make(chan *pb.GetConfigResponse)}
+	srv := grpc.NewServer()
+	pb.RegisterConfigServiceServer(srv, ts)
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+
+	return ts, Scheme + ln.Addr().String()
+}
+
+func TestIsSource(t *testing.T) {
+	assert.True(t, IsSource("grpc://localhost:9314"))
+	assert.False(t, IsSource("/etc/cloudprober.cfg"))
+	assert.False(t, IsSource(""))
+}
+
+func TestReadClientConf(t *testing.T) {
+	c, err := readClientConf("")
+	assert.NoError(t, err)
+	assert.True(t, c.GetWatch(), "default watch")
+
+	fileName := filepath.Join(t.TempDir(), "client.cfg")
+	os.WriteFile(fileName, []byte("watch: false\ntimeout_sec: 5"), 0644)
+	c, err = readClientConf(fileName)
+	assert.NoError(t, err)
+	assert.False(t, c.GetWatch())
+	assert.Equal(t, int32(5), c.GetTimeoutSec())
+
+	os.WriteFile(fileName, []byte("invalid_field: 1"), 0644)
+	_, err = readClientConf(fileName)
+	assert.Error(t, err)
+}
+
+func TestNewErrors(t *testing.T) {
+	_, err := newSource("localhost:9314", &pb.ClientConf{}, nil, nil)
+	assert.Error(t, err, "no scheme")
+	_, err = newSource(Scheme, &pb.ClientConf{}, nil, nil)
+	assert.Error(t, err, "no address")
+}
+
+func TestGetConfig(t *testing.T) {
+	ts, addr := startTestServer(t)
+
+	s, err := newSource(addr, &pb.ClientConf{}, nil, nil)
+	assert.NoError(t, err)
+	defer s.Close()
+
+	cfg, err := s.GetConfig(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10), cfg.GetGrpcPort())
+	assert.Equal(t, "v1", s.lastVersion)
+
+	hostname, _ := os.Hostname()
+	assert.Equal(t, hostname, ts.gotReq.GetInstanceName())
+}
+
+func TestWatch(t *testing.T) {
+	ts, addr := startTestServer(t)
+
+	s, err := newSource(addr, &pb.ClientConf{}, nil, nil)
+	assert.NoError(t, err)
+	defer s.Close()
+
+	_, err = s.GetConfig(context.Background())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gotCfg := make(chan *configpb.ProberConfig, 10)
+	go s.Watch(ctx, func(u *config.Update) { gotCfg <- u.Config })
+
+	// Same version as GetConfig, an empty config and an invalid config should
+	// be skipped. A valid config with the invalid config's version should be
+	// applied.
+	invalid := testResponse("v3", 20)
+	invalid.Config.Probe = []*probespb.ProbeDef{{
+		Name:      proto.String("p1"),
+		Type:      probespb.ProbeDef_PING.Enum(),
+		Surfacers: []string{"unknown"},
+	}}
+	ts.updates <- testResponse("v1", 10)
+	ts.updates <- &pb.GetConfigResponse{Version: proto.String("v2")}
+	ts.updates <- invalid
+	ts.updates <- testResponse("v3", 25)
+	ts.updates <- testResponse("v4", 30)
+
+	for _, wantPort := range []int32{25, 30} {
+		select {
+		case cfg := <-gotCfg:
+			assert.Equal(t, wantPort, cfg.GetGrpcPort())
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for config update")
+		}
+	}
+	assert.Len(t, gotCfg, 0)
+}
+
+func TestWatchDisabled(t *testing.T) {
+	_, addr := startTestServer(t)
+
+	s, err := newSource(addr, &pb.ClientConf{Watch: proto.Bool(false)}, nil, nil)
+	assert.NoError(t, err)
+	defer s.Close()
+
+	done := make(chan struct{})
+	go func() {
+		s.Watch(context.Background(), func(*config.Update) {})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch didn't return with watch disabled")
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudprober/cloudprober/internal/file"
+)
+
+// includeRe matches the include directives, e.g. {{include "team-a.cfg"}}.
+var includeRe = regexp.MustCompile(`\{\{-?\s*include\s+"([^"]+)"\s*-?\}\}`)
+
+// resolveIncludePath returns the path of the included file. Relative paths
+// are resolved relative to the including file's directory.
+func resolveIncludePath(parent, fileName string) string {
+	// Remote paths, e.g. gs://bucket/config.cfg, are joined as URL paths.
+	if i := strings.Index(parent, "://"); i != -1 {
+		if strings.Contains(fileName, "://") {
+			return fileName
+		}
+		prefix, parentPath := parent[:i+3], parent[i+3:]
+		if strings.HasPrefix(fileName, "/") {
+			return prefix + strings.SplitN(parentPath, "/", 2)[0] + fileName
+		}
+		return prefix + path.Join(path.Dir(parentPath), fileName)
+	}
+
+	if filepath.IsAbs(fileName) {
+		return fileName
+	}
+	return filepath.Join(filepath.Dir(parent), fileName)
+}
+
+// sourceMarkerPrefix is the prefix of the comment lines that are added around
+// the included content, to track where the config lines came from. Markers
+// are comments in both textpb and YAML formats. A marker line tells the file
+// and the line number of the line following it.
+const sourceMarkerPrefix = "# cloudprober:source "
+
+func sourceMarker(fileName string, line int) string {
+	return fmt.Sprintf("%s%s:%d\n", sourceMarkerPrefix, fileName, line)
+}
+
+// isGlob returns true if the include path is a glob pattern.
+func isGlob(includePath string) bool {
+	return strings.ContainsAny(includePath, "*?[")
+}
+
+// includedFiles returns the files for the include path. Glob patterns are
+// expanded to the matching files, in lexical order, and are supported only
+// for local files.
+func includedFiles(includePath string) ([]string, error) {
+	if !isGlob(includePath) {
+		return []string{includePath}, nil
+	}
+	if file.IsRemote(includePath) {
+		return nil, fmt.Errorf("glob patterns are supported only for local files: %s", includePath)
+	}
+	return filepath.Glob(includePath)
+}
+
+// expandIncludes replaces the include directives in the content of the given
+// file by the included files' content, recursively. Included files are read
+// in the same way as the main config file. Include path can be a glob
+// pattern, e.g. "cloudprober.d/*.cfg", in which case all the matching files
+// are included. stack is the chain of files including this file, and is used
+// to detect include cycles.
+//
+// If markers is true, included content is surrounded by the source markers
+// (see sourceMarkerPrefix), which are used to report errors with the
+// originating file and line.
+func expandIncludes(ctx context.Context, content, fileName string, stack []string, markers bool) (string, error) {
+	stack = append(stack, fileName)
+
+	var errs []string
+	includeFile := func(includePath string, line int) (string, bool) {
+		for _, f := range stack {
+			if f == includePath {
+				errs = append(errs, fmt.Sprintf("include cycle: %s -> %s", strings.Join(stack, " -> "), includePath))
+				return "", false
+			}
+		}
+
+		b, err := file.ReadConfigFile(ctx, includePath)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("error reading included file %s (included from %s:%d): %v", includePath, fileName, line, err))
+			return "", false
+		}
+		s, err := expandIncludes(ctx, string(b), includePath, stack, markers)
+		if err != nil {
+			errs = append(errs, err.Error())
+			return "", false
+		}
+		if markers {
+			if !strings.HasSuffix(s, "\n") {
+				s += "\n"
+			}
+			s = sourceMarker(includePath, 1) + s
+		}
+		return s, true
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, m := range includeRe.FindAllStringSubmatchIndex(content, -1) {
+		out.WriteString(content[last:m[0]])
+		last = m[1]
+
+		line := strings.Count(content[:m[0]], "\n") + 1
+		includePath := resolveIncludePath(fileName, content[m[2]:m[3]])
+		files, err := includedFiles(includePath)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("error including %s (included from %s:%d): %v", includePath, fileName, line, err))
+			continue
+		}
+
+		var included []string
+		for _, f := range files {
+			if s, ok := includeFile(f, line); ok {
+				included = append(included, s)
+			}
+		}
+		if !markers {
+			out.WriteString(strings.Join(included, "\n"))
+			continue
+		}
+		// Included content starts on a new line, and the rest of the
+		// directive's line continues on a new line after it.
+		out.WriteString("\n")
+		out.WriteString(strings.Join(included, ""))
+		out.WriteString(sourceMarker(fileName, line))
+	}
+	out.WriteString(content[last:])
+
+	if len(errs) > 0 {
+		return "", errors.New(strings.Join(errs, "; "))
+	}
+	return out.String(), nil
+}
+
+var (
+	// configErrLineRe matches the line numbers in the textpb and YAML parsing
+	// errors, e.g. "(line 5:3)" and "yaml: line 5:".
+	configErrLineRe = regexp.MustCompile(`\bline (\d+)`)
+
+	// templateErrLineRe matches the line numbers in the config template
+	// errors, e.g. "template: cloudprober_cfg:5:".
+	templateErrLineRe = regexp.MustCompile(`cloudprober_cfg:(\d+)`)
+)
+
+// sourceLocation returns the originating file and line for the given line of
+// the config, using the source markers in the config. It returns false if
+// there are no source markers before the line.
+func sourceLocation(config string, line int) (string, int, bool) {
+	lines := strings.Split(config, "\n")
+	if line < 1 || line > len(lines) {
+		return "", 0, false
+	}
+	for i := line - 2; i >= 0; i-- {
+		marker, ok := strings.CutPrefix(lines[i], sourceMarkerPrefix)
+		if !ok {
+			continue
+		}
+		sep := strings.LastIndex(marker, ":")
+		if sep == -1 {
+			return "", 0, false
+		}
+		markerLine, err := strconv.Atoi(marker[sep+1:])
+		if err != nil {
+			return "", 0, false
+		}
+		return marker[:sep], markerLine + (line - 2 - i), true
+	}
+	return "", 0, false
+}
+
+// withSourceLocation adds the originating file and line to the config
+// parsing error, if the error refers to a line of the config and config has
+// the source markers.
+func withSourceLocation(err error, config string, lineRe *regexp.Regexp) error {
+	if err == nil {
+		return nil
+	}
+	m := lineRe.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	line, _ := strconv.Atoi(m[1])
+	fileName, fileLine, ok := sourceLocation(config, line)
+	if !ok {
+		return err
+	}
+	return fmt.Errorf("%s:%d: %w", fileName, fileLine, err)
+}
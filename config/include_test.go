@@ -0,0 +1,270 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestResolveIncludePath(t *testing.T) {
+	tests := []struct {
+		parent, fileName, want string
+	}{
+		{parent: "/etc/cloudprober.cfg", fileName: "probes/a.cfg", want: "/etc/probes/a.cfg"},
+		{parent: "/etc/probes/a.cfg", fileName: "../b.cfg", want: "/etc/b.cfg"},
+		{parent: "/etc/cloudprober.cfg", fileName: "/opt/a.cfg", want: "/opt/a.cfg"},
+		{parent: "cloudprober.cfg", fileName: "a.cfg", want: "a.cfg"},
+		{parent: "gs://bucket/cfg/cloudprober.cfg", fileName: "probes/a.cfg", want: "gs://bucket/cfg/probes/a.cfg"},
+		{parent: "gs://bucket/cfg/cloudprober.cfg", fileName: "/a.cfg", want: "gs://bucket/a.cfg"},
+		{parent: "gs://bucket/cfg/cloudprober.cfg", fileName: "gs://other/a.cfg", want: "gs://other/a.cfg"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.parent+"_"+test.fileName, func(t *testing.T) {
+			assert.Equal(t, test.want, resolveIncludePath(test.parent, test.fileName))
+		})
+	}
+}
+
+func TestReadConfigFileWithIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"cloudprober.cfg": `{{include "probes/team-a.cfg"}}
+{{- include "probes/team-b.cfg" }}`,
+		"probes/team-a.cfg": `probe {
+  name: "team-a"
+  type: PING
+  targets { host_names: "{{.host}}" }
+}
+`,
+		// Nested include, relative to the including file.
+		"probes/team-b.cfg": `{{include "team-b/http.cfg"}}`,
+		"probes/team-b/http.cfg": `probe {
+  name: "team-b"
+  type: HTTP
+  targets { host_names: "team-b.example.com" }
+}
+`,
+	})
+
+	content, format, err := readConfigFile(context.Background(), filepath.Join(dir, "cloudprober.cfg"))
+	if err != nil {
+		t.Fatalf("readConfigFile() error: %v", err)
+	}
+	assert.Equal(t, "textpb", format)
+
+	cfg, _, err := ParseConfig(context.Background(), content, format, map[string]string{"host": "1.1.1.1"}, nil)
+	if err != nil {
+		t.Fatalf("ParseConfig() error: %v", err)
+	}
+	var probes []string
+	for _, p := range cfg.GetProbe() {
+		probes = append(probes, p.GetName())
+	}
+	assert.Equal(t, []string{"team-a", "team-b"}, probes)
+	assert.Equal(t, "1.1.1.1", cfg.GetProbe()[0].GetTargets().GetHostNames())
+}
+
+func TestReadConfigFileIncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"cycle.cfg":   `{{include "a.cfg"}}`,
+		"a.cfg":       `{{include "sub/b.cfg"}}`,
+		"sub/b.cfg":   `{{include "../a.cfg"}}`,
+		"self.cfg":    `{{include "self.cfg"}}`,
+		"missing.cfg": `{{include "not-there.cfg"}}`,
+	})
+
+	tests := []struct {
+		fileName string
+		wantErr  string
+	}{
+		{
+			fileName: "cycle.cfg",
+			wantErr:  "include cycle: " + filepath.Join(dir, "cycle.cfg") + " -> " + filepath.Join(dir, "a.cfg") + " -> " + filepath.Join(dir, "sub/b.cfg") + " -> " + filepath.Join(dir, "a.cfg"),
+		},
+		{
+			fileName: "self.cfg",
+			wantErr:  "include cycle",
+		},
+		{
+			fileName: "missing.cfg",
+			wantErr:  "error reading included file " + filepath.Join(dir, "not-there.cfg"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fileName, func(t *testing.T) {
+			_, _, err := readConfigFile(context.Background(), filepath.Join(dir, test.fileName))
+			assert.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestIncludeOutsideConfigFile(t *testing.T) {
+	_, _, err := ParseConfig(context.Background(), `{{include "probes.cfg"}}`, "textpb", nil, nil)
+	assert.ErrorContains(t, err, "include is supported only in config files")
+}
+
+func TestReadConfigFileIncludeGlob(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"cloudprober.cfg": `{{include "cloudprober.d/*.cfg"}}
+{{include "empty.d/*.cfg"}}`,
+		"cloudprober.d/b.cfg":      `probe { name: "b" type: PING targets { host_names: "b" } }`,
+		"cloudprober.d/a.cfg":      `probe { name: "a" type: PING targets { host_names: "a" } }`,
+		"cloudprober.d/c.cfg.bak":  `probe { name: "c" }`,
+		"empty.d/not-matching.txt": ``,
+	})
+
+	content, format, err := readConfigFile(context.Background(), filepath.Join(dir, "cloudprober.cfg"))
+	assert.NoError(t, err)
+	cfg, _, err := ParseConfig(context.Background(), content, format, nil, nil)
+	assert.NoError(t, err)
+
+	var probes []string
+	for _, p := range cfg.GetProbe() {
+		probes = append(probes, p.GetName())
+	}
+	assert.Equal(t, []string{"a", "b"}, probes, "files should be included in lexical order")
+
+	_, err = includedFiles("gs://bucket/cloudprober.d/*.cfg")
+	assert.ErrorContains(t, err, "supported only for local files")
+}
+
+func TestIncludeErrorLocation(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"cloudprober.cfg": `probe {
+  name: "main"
+  type: PING
+  targets { host_names: "main" }
+}
+{{include "team-a/probes.cfg"}}
+surfacer { type: FILE }
+bad_main_field: 1
+`,
+		"team-a/probes.cfg": `{{include "common.cfg"}}
+probe {
+  name: "team-a"
+  type: PING
+  bad_team_a_field: true
+}
+`,
+		"team-a/common.cfg": `probe {
+  name: "common"
+  bad_common_field: true
+}`,
+		"tmpl.cfg": `{{include "tmpl-probes.cfg"}}`,
+		"tmpl-probes.cfg": `probe {
+  name: "{{ .name | undefinedFunc }}"
+}`,
+		"cloudprober.yaml": `probe:
+  - name: main
+    type: PING
+{{include "team-b.yaml"}}
+`,
+		"team-b.yaml": `  - name: team-b
+    type: PING
+     bad_indent: true
+`,
+	})
+
+	tests := []struct {
+		fileName  string
+		removeErr []string
+		wantErr   string
+	}{
+		{
+			fileName: "cloudprober.cfg",
+			wantErr:  filepath.Join(dir, "team-a/common.cfg") + ":3: ",
+		},
+		{
+			fileName:  "cloudprober.cfg",
+			removeErr: []string{"team-a/common.cfg"},
+			wantErr:   filepath.Join(dir, "team-a/probes.cfg") + ":5: ",
+		},
+		{
+			fileName:  "cloudprober.cfg",
+			removeErr: []string{"team-a/common.cfg", "team-a/probes.cfg"},
+			wantErr:   filepath.Join(dir, "cloudprober.cfg") + ":8: ",
+		},
+		{
+			fileName: "tmpl.cfg",
+			wantErr:  filepath.Join(dir, "tmpl-probes.cfg") + ":2: ",
+		},
+		{
+			fileName: "cloudprober.yaml",
+			wantErr:  filepath.Join(dir, "team-b.yaml") + ":3: ",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.wantErr, func(t *testing.T) {
+			// Fix the errors in the given files.
+			for _, f := range test.removeErr {
+				b, err := os.ReadFile(filepath.Join(dir, f))
+				assert.NoError(t, err)
+				fixed := regexp.MustCompile(`(?m)^\s*bad_\w+: \w+$`).ReplaceAllString(string(b), "")
+				writeTestFiles(t, dir, map[string]string{f: fixed})
+			}
+
+			content, format, err := readConfigFile(context.Background(), filepath.Join(dir, test.fileName))
+			assert.NoError(t, err)
+			_, _, err = ParseConfig(context.Background(), content, format, map[string]string{"name": "x"}, nil)
+			assert.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestSourceLocation(t *testing.T) {
+	config := "# cloudprober:source main.cfg:1\nline1\n# cloudprober:source inc.cfg:1\ninc1\ninc2\n# cloudprober:source main.cfg:2\nline2"
+
+	for line, want := range map[int]string{
+		2: "main.cfg:1",
+		4: "inc.cfg:1",
+		5: "inc.cfg:2",
+		7: "main.cfg:2",
+	} {
+		f, l, ok := sourceLocation(config, line)
+		assert.True(t, ok, "line %d", line)
+		assert.Equal(t, want, fmt.Sprintf("%s:%d", f, l), "line %d", line)
+	}
+
+	_, _, ok := sourceLocation("line1\nline2", 2)
+	assert.False(t, ok, "no markers")
+	_, _, ok = sourceLocation(config, 100)
+	assert.False(t, ok, "out of range")
+}
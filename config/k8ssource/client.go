@@ -0,0 +1,343 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8ssource
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/cloudprober/cloudprober/config/k8ssource/proto"
+	"github.com/cloudprober/cloudprober/internal/oauth"
+	"github.com/cloudprober/cloudprober/internal/tlsconfig"
+	"github.com/cloudprober/cloudprober/logger"
+	"golang.org/x/oauth2"
+)
+
+// Variables defined by Kubernetes spec to find out local CA cert and token.
+var (
+	localCACert    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+	localTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+const (
+	minRetryDelay = time.Second
+	maxRetryDelay = time.Minute
+
+	// Server closes the watch after this timeout, and we start a new watch
+	// from the last seen resource version.
+	watchTimeoutSec = 300
+)
+
+// errGone is returned when the resource version we are watching from is too
+// old, and the objects need to be listed again.
+var errGone = errors.New("resource version is too old")
+
+// apiClient is a minimal kubernetes API client.
+type apiClient struct {
+	baseURL string
+	httpC   *http.Client
+}
+
+func newAPIClient(c *pb.ClientConf, l *logger.Logger) (*apiClient, error) {
+	host := c.GetApiServerAddress()
+	inCluster := host == ""
+	if inCluster {
+		h, p := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if h == "" || p == "" {
+			return nil, errors.New("not running in cluster: KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT environment variables not set, and api_server_address is not configured")
+		}
+		host = net.JoinHostPort(h, p)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{}
+	if c.GetTlsConfig() != nil {
+		if err := tlsconfig.UpdateTLSConfig(transport.TLSClientConfig, c.GetTlsConfig()); err != nil {
+			return nil, err
+		}
+	} else {
+		certs, err := os.ReadFile(localCACert)
+		if err != nil {
+			return nil, fmt.Errorf("error while reading local ca.crt file (%s): %v", localCACert, err)
+		}
+		transport.TLSClientConfig.RootCAs = x509.NewCertPool()
+		transport.TLSClientConfig.RootCAs.AppendCertsFromPEM(certs)
+	}
+
+	var rt http.RoundTripper = transport
+	// Service account token is used if available, e.g. it may not be
+	// available if client certificates are used to authenticate.
+	if _, err := os.Stat(localTokenFile); err == nil {
+		ts, err := oauth.K8STokenSource(l)
+		if err != nil {
+			return nil, fmt.Errorf("error while creating token source from k8s token file: %v", err)
+		}
+		rt = &oauth2.Transport{Source: ts, Base: transport}
+	}
+
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	return &apiClient{
+		baseURL: strings.TrimSuffix(host, "/"),
+		httpC:   &http.Client{Transport: rt},
+	}, nil
+}
+
+func (c *apiClient) get(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	u := c.baseURL + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpC.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusGone {
+			return nil, errGone
+		}
+		return nil, fmt.Errorf("GET %s: HTTP response status: %s, body: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// object is the part of a kubernetes object that we use: data for the
+// ConfigMaps and Secrets, and spec for the custom resources.
+type object struct {
+	Metadata struct {
+		Name            string `json:"name"`
+		Namespace       string `json:"namespace"`
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+	Data map[string]string `json:"data,omitempty"`
+	Spec json.RawMessage   `json:"spec,omitempty"`
+}
+
+func (o *object) key() string {
+	return o.Metadata.Namespace + "/" + o.Metadata.Name
+}
+
+type objectList struct {
+	Metadata struct {
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+	Items []*object `json:"items"`
+}
+
+type watchEvent struct {
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// watcher keeps track of the objects in a collection, e.g.
+// /api/v1/namespaces/default/configmaps, by listing the objects and then
+// watching them for changes.
+type watcher struct {
+	client  *apiClient
+	path    string
+	query   url.Values
+	timeout time.Duration
+	l       *logger.Logger
+
+	mu              sync.Mutex
+	objects         map[string]*object
+	resourceVersion string
+}
+
+func newWatcher(client *apiClient, path string, query url.Values, timeout time.Duration, l *logger.Logger) *watcher {
+	return &watcher{
+		client:  client,
+		path:    path,
+		query:   query,
+		timeout: timeout,
+		l:       l,
+		objects: make(map[string]*object),
+	}
+}
+
+// list lists the objects, replacing the objects seen so far.
+func (w *watcher) list(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	resp, err := w.client.get(ctx, w.path, w.query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var ol objectList
+	if err := json.NewDecoder(resp.Body).Decode(&ol); err != nil {
+		return fmt.Errorf("error parsing the list response for %s: %v", w.path, err)
+	}
+
+	objects := make(map[string]*object, len(ol.Items))
+	for _, o := range ol.Items {
+		objects[o.key()] = o
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.objects, w.resourceVersion = objects, ol.Metadata.ResourceVersion
+	return nil
+}
+
+// snapshot returns the current objects, sorted by namespace and name.
+func (w *watcher) snapshot() []*object {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var objects []*object
+	for _, o := range w.objects {
+		objects = append(objects, o)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].key() < objects[j].key() })
+	return objects
+}
+
+// handleEvent applies the watch event to the objects. It returns true if
+// the objects changed.
+func (w *watcher) handleEvent(ev *watchEvent) (bool, error) {
+	if ev.Type == "ERROR" {
+		var status struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		json.Unmarshal(ev.Object, &status)
+		if status.Code == http.StatusGone {
+			return false, errGone
+		}
+		return false, fmt.Errorf("watch error: %s (code: %d)", status.Message, status.Code)
+	}
+
+	o := &object{}
+	if err := json.Unmarshal(ev.Object, o); err != nil {
+		return false, fmt.Errorf("error parsing the watch event object: %v", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.resourceVersion = o.Metadata.ResourceVersion
+
+	switch ev.Type {
+	case "ADDED", "MODIFIED":
+		w.objects[o.key()] = o
+	case "DELETED":
+		delete(w.objects, o.key())
+	default:
+		// BOOKMARK events only update the resource version.
+		return false, nil
+	}
+	return true, nil
+}
+
+// watchOnce watches the objects, calling onChange after every change. It
+// returns when the watch breaks, or is closed by the server.
+func (w *watcher) watchOnce(ctx context.Context, onChange func()) error {
+	query := url.Values{}
+	for k, v := range w.query {
+		query[k] = v
+	}
+	w.mu.Lock()
+	query.Set("resourceVersion", w.resourceVersion)
+	w.mu.Unlock()
+	query.Set("watch", "1")
+	query.Set("allowWatchBookmarks", "true")
+	query.Set("timeoutSeconds", fmt.Sprint(watchTimeoutSec))
+
+	resp, err := w.client.get(ctx, w.path, query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		ev := &watchEvent{}
+		if err := dec.Decode(ev); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		changed, err := w.handleEvent(ev)
+		if err != nil {
+			return err
+		}
+		if changed {
+			onChange()
+		}
+	}
+}
+
+// run watches the objects until the context is canceled, calling onChange
+// after every change. If the watch breaks, it's re-established with
+// exponential backoff, re-listing the objects if required.
+func (w *watcher) run(ctx context.Context, onChange func()) {
+	delay := minRetryDelay
+	for {
+		start := time.Now()
+		err := w.watchOnce(ctx, onChange)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			delay = minRetryDelay
+			continue
+		}
+
+		if err == errGone {
+			w.l.Infof("k8ssource: watch for %s expired, listing the objects again", w.path)
+			if err = w.list(ctx); err == nil {
+				onChange()
+				continue
+			}
+		}
+		// Reset backoff if the watch was up for a while.
+		if time.Since(start) > maxRetryDelay {
+			delay = minRetryDelay
+		}
+		w.l.Warningf("k8ssource: watch for %s broke (%v), retrying in %v", w.path, err, delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
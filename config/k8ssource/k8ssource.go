@@ -0,0 +1,265 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package k8ssource implements a config source that reads cloudprober's config
+from a kubernetes ConfigMap or Secret through the API server, and watches it
+for updates, so that config changes don't require pod restarts. Config source
+URL is of the form:
+
+	k8s://configmap/<namespace>/<name>[/<key>]
+	k8s://secret/<namespace>/<name>[/<key>]
+
+Key defaults to "cloudprober.cfg". Config format is inferred from the key's
+extension, e.g. "cloudprober.yaml" is parsed as YAML.
+
+Source can optionally watch the Probe custom resources, and add them to the
+config as probes (see ProbeController in the client config).
+*/
+package k8ssource
+
+import (
+	"context"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudprober/cloudprober/config"
+	pb "github.com/cloudprober/cloudprober/config/k8ssource/proto"
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/internal/file"
+	"github.com/cloudprober/cloudprober/logger"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+var clientConfFile = flag.String("config_k8s_client_conf", "", "Client config (ClientConf textproto) for the kubernetes config source. Used only if config file is a k8s:// URL.")
+
+// Scheme is the prefix that identifies a kubernetes config source.
+const Scheme = "k8s://"
+
+const defaultKey = "cloudprober.cfg"
+
+// IsSource returns true if the given config file refers to a kubernetes
+// config source.
+func IsSource(configFile string) bool {
+	return strings.HasPrefix(configFile, Scheme)
+}
+
+// Source is a kubernetes config source.
+type Source struct {
+	c       *pb.ClientConf
+	src     string
+	key     string
+	secret  bool
+	cm      *watcher
+	probes  *probeController
+	timeout time.Duration
+	vars    map[string]string
+	l       *logger.Logger
+
+	mu      sync.Mutex
+	content string
+}
+
+func readClientConf(fileName string) (*pb.ClientConf, error) {
+	c := &pb.ClientConf{}
+	if fileName == "" {
+		return c, nil
+	}
+	b, err := file.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if err := prototext.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("error parsing client config file %s: %v", fileName, err)
+	}
+	return c, nil
+}
+
+// parseURL parses a kubernetes config source URL, returning the collection
+// path of the object, its name, and the key in the object's data.
+func parseURL(configFile string) (path, name, key string, secret bool, err error) {
+	parts := strings.Split(strings.TrimPrefix(configFile, Scheme), "/")
+	if len(parts) < 3 || len(parts) > 4 || parts[1] == "" || parts[2] == "" || (parts[0] != "configmap" && parts[0] != "secret") {
+		return "", "", "", false, fmt.Errorf("invalid config source %s, should be of the form %sconfigmap/<namespace>/<name>[/<key>] or %ssecret/<namespace>/<name>[/<key>]", configFile, Scheme, Scheme)
+	}
+	key = defaultKey
+	if len(parts) == 4 && parts[3] != "" {
+		key = parts[3]
+	}
+	secret = parts[0] == "secret"
+	return fmt.Sprintf("/api/v1/namespaces/%s/%ss", parts[1], parts[0]), parts[2], key, secret, nil
+}
+
+// New creates a new kubernetes config source for the given k8s:// URL.
+// Client config is read from the file specified by the
+// --config_k8s_client_conf flag. Vars are used for the config template
+// processing.
+func New(configFile string, vars map[string]string, l *logger.Logger) (*Source, error) {
+	c, err := readClientConf(*clientConfFile)
+	if err != nil {
+		return nil, fmt.Errorf("k8ssource: %v", err)
+	}
+	client, err := newAPIClient(c, l)
+	if err != nil {
+		return nil, fmt.Errorf("k8ssource: %v", err)
+	}
+	return newSource(configFile, c, client, vars, l)
+}
+
+func newSource(configFile string, c *pb.ClientConf, client *apiClient, vars map[string]string, l *logger.Logger) (*Source, error) {
+	path, name, key, secret, err := parseURL(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("k8ssource: %v", err)
+	}
+	timeout := time.Duration(c.GetTimeoutSec()) * time.Second
+
+	s := &Source{
+		c:       c,
+		src:     configFile,
+		key:     key,
+		secret:  secret,
+		cm:      newWatcher(client, path, url.Values{"fieldSelector": {"metadata.name=" + name}}, timeout, l),
+		timeout: timeout,
+		vars:    vars,
+		l:       l,
+	}
+	if c.GetProbeController() != nil {
+		s.probes = newProbeController(c.GetProbeController(), client, timeout, l)
+	}
+	return s, nil
+}
+
+// configContent returns the config from the watched object.
+func (s *Source) configContent() (string, error) {
+	objects := s.cm.snapshot()
+	if len(objects) == 0 {
+		return "", fmt.Errorf("%s not found", s.src)
+	}
+	v, ok := objects[0].Data[s.key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in %s", s.key, s.src)
+	}
+	if !s.secret {
+		return v, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return "", fmt.Errorf("error decoding key %s in %s: %v", s.key, s.src, err)
+	}
+	return string(b), nil
+}
+
+// GetConfig reads the config and its format from the ConfigMap (or Secret).
+// If the probe controller is configured, it also lists the Probe objects.
+func (s *Source) GetConfig(ctx context.Context) (string, string, error) {
+	if err := s.cm.list(ctx); err != nil {
+		return "", "", fmt.Errorf("k8ssource: error reading %s: %v", s.src, err)
+	}
+	content, err := s.configContent()
+	if err != nil {
+		return "", "", fmt.Errorf("k8ssource: %v", err)
+	}
+	if s.probes != nil {
+		if err := s.probes.list(ctx); err != nil {
+			return "", "", fmt.Errorf("k8ssource: error listing Probe objects: %v", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.content = content
+	return content, config.FormatFromFileName(s.key), nil
+}
+
+// ParseConfig parses the config read by GetConfig, and adds the probes from
+// the Probe objects to it. It returns the parsed config, and the config
+// string after the template processing (see config.ParseConfig).
+func (s *Source) ParseConfig(ctx context.Context, content, format string) (*configpb.ProberConfig, string, error) {
+	cfg, parsedConfig, err := config.ParseConfig(ctx, content, format, s.vars, s.l)
+	if err != nil {
+		return nil, "", err
+	}
+	if s.probes != nil {
+		cfg.Probe = s.probes.mergeProbes(cfg)
+	}
+	return cfg, parsedConfig, nil
+}
+
+// update re-parses the config after a change in the watched objects, and
+// calls f with it. Invalid configs are logged and skipped.
+func (s *Source) update(ctx context.Context, f func(*config.Update)) {
+	content, err := s.configContent()
+	if err != nil {
+		s.l.Warningf("k8ssource: %v, keeping the current config", err)
+		s.mu.Lock()
+		content = s.content
+		s.mu.Unlock()
+	}
+
+	format := config.FormatFromFileName(s.key)
+	cfg, parsedConfig, err := s.ParseConfig(ctx, content, format)
+	if err != nil {
+		s.l.Errorf("k8ssource: ignoring invalid config update from %s: %v", s.src, err)
+		return
+	}
+
+	s.mu.Lock()
+	s.content = content
+	s.mu.Unlock()
+	f(&config.Update{Config: cfg, Content: content, ParsedConfig: parsedConfig, Format: format})
+}
+
+// Watch watches the ConfigMap (or Secret), and the Probe objects if the
+// probe controller is configured, and calls f for every new config. Watch
+// returns only when the context is canceled, or if there is nothing to
+// watch.
+func (s *Source) Watch(ctx context.Context, f func(*config.Update)) {
+	if !s.c.GetWatch() && s.probes == nil {
+		return
+	}
+
+	changes := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+	if s.c.GetWatch() {
+		go s.cm.run(ctx, notify)
+	}
+	if s.probes != nil {
+		s.probes.run(ctx, notify)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-changes:
+			s.update(ctx, f)
+		}
+	}
+}
+
+// Close closes the idle connections to the API server.
+func (s *Source) Close() error {
+	s.cm.client.httpC.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,249 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8ssource
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/config"
+	pb "github.com/cloudprober/cloudprober/config/k8ssource/proto"
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeAPIServer serves the list responses for the collection paths, and
+// streams the watch events sent to the path's channel.
+type fakeAPIServer struct {
+	mu        sync.Mutex
+	lists     map[string]string
+	events    map[string]chan string
+	listCount map[string]int
+	queries   map[string]string
+}
+
+func newFakeAPIServer(t *testing.T) (*fakeAPIServer, *apiClient) {
+	t.Helper()
+	f := &fakeAPIServer{
+		lists:     make(map[string]string),
+		events:    make(map[string]chan string),
+		listCount: make(map[string]int),
+		queries:   make(map[string]string),
+	}
+	ts := httptest.NewServer(f)
+	t.Cleanup(ts.Close)
+	return f, &apiClient{baseURL: ts.URL, httpC: ts.Client()}
+}
+
+func (f *fakeAPIServer) setList(path, rv string, items ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	list := `{"metadata": {"resourceVersion": "` + rv + `"}, "items": [`
+	for i, item := range items {
+		if i > 0 {
+			list += ","
+		}
+		list += item
+	}
+	f.lists[path] = list + "]}"
+	if f.events[path] == nil {
+		f.events[path] = make(chan string, 10)
+	}
+}
+
+func (f *fakeAPIServer) sendEvent(path, evType, obj string) {
+	f.mu.Lock()
+	ch := f.events[path]
+	f.mu.Unlock()
+	ch <- `{"type": "` + evType + `", "object": ` + obj + `}`
+}
+
+func (f *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	list, ok := f.lists[r.URL.Path]
+	events := f.events[r.URL.Path]
+	if r.URL.Query().Get("watch") == "" {
+		f.listCount[r.URL.Path]++
+		f.queries[r.URL.Path] = r.URL.RawQuery
+	}
+	f.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if r.URL.Query().Get("watch") == "" {
+		w.Write([]byte(list))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-events:
+			w.Write([]byte(ev + "\n"))
+			w.(http.Flusher).Flush()
+		}
+	}
+}
+
+func testObject(ns, name, rv string, data map[string]string) string {
+	o := &object{Data: data}
+	o.Metadata.Namespace, o.Metadata.Name, o.Metadata.ResourceVersion = ns, name, rv
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func testClientConf() *pb.ClientConf {
+	return &pb.ClientConf{TimeoutSec: proto.Int32(5)}
+}
+
+// waitForProbes waits for a config update with the wanted probes. Changes
+// may be coalesced or repeated, so intermediate updates are skipped.
+func waitForProbes(t *testing.T, ch chan *configpb.ProberConfig, want []string) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	var got []string
+	for {
+		select {
+		case cfg := <-ch:
+			if got = probeNames(cfg); assert.ObjectsAreEqual(want, got) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for the config update with probes %v, last update: %v", want, got)
+		}
+	}
+}
+
+func probeNames(cfg *configpb.ProberConfig) []string {
+	var names []string
+	for _, p := range cfg.GetProbe() {
+		names = append(names, p.GetName())
+	}
+	return names
+}
+
+func TestParseURL(t *testing.T) {
+	for _, test := range []struct {
+		url        string
+		wantPath   string
+		wantName   string
+		wantKey    string
+		wantSecret bool
+		wantErr    bool
+	}{
+		{url: "k8s://configmap/monitoring/cloudprober", wantPath: "/api/v1/namespaces/monitoring/configmaps", wantName: "cloudprober", wantKey: "cloudprober.cfg"},
+		{url: "k8s://secret/monitoring/cloudprober/config.yaml", wantPath: "/api/v1/namespaces/monitoring/secrets", wantName: "cloudprober", wantKey: "config.yaml", wantSecret: true},
+		{url: "k8s://configmap/monitoring", wantErr: true},
+		{url: "k8s://deployment/monitoring/cloudprober", wantErr: true},
+		{url: "k8s://configmap//cloudprober", wantErr: true},
+		{url: "k8s://configmap/monitoring/cloudprober/key/extra", wantErr: true},
+	} {
+		t.Run(test.url, func(t *testing.T) {
+			path, name, key, secret, err := parseURL(test.url)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, [4]interface{}{test.wantPath, test.wantName, test.wantKey, test.wantSecret}, [4]interface{}{path, name, key, secret})
+		})
+	}
+}
+
+func TestSourceConfigMap(t *testing.T) {
+	fake, client := newFakeAPIServer(t)
+	path := "/api/v1/namespaces/monitoring/configmaps"
+	fake.setList(path, "10", testObject("monitoring", "cloudprober", "10", map[string]string{
+		"cloudprober.yaml": "probe:\n  - name: p1\n    type: PING\n    targets:\n      host_names: localhost\n",
+	}))
+
+	s, err := newSource("k8s://configmap/monitoring/cloudprober/cloudprober.yaml", testClientConf(), client, nil, nil)
+	if err != nil {
+		t.Fatalf("Error creating source: %v", err)
+	}
+
+	content, format, err := s.GetConfig(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "yaml", format)
+	cfg, _, err := s.ParseConfig(context.Background(), content, format)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"p1"}, probeNames(cfg))
+	assert.Equal(t, "fieldSelector=metadata.name%3Dcloudprober", fake.queries[path])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan *configpb.ProberConfig, 10)
+	go s.Watch(ctx, func(u *config.Update) { updates <- u.Config })
+
+	fake.sendEvent(path, "MODIFIED", testObject("monitoring", "cloudprober", "11", map[string]string{
+		"cloudprober.yaml": "probe:\n  - name: p2\n    type: PING\n    targets:\n      host_names: localhost\n",
+	}))
+	waitForProbes(t, updates, []string{"p2"})
+
+	// Invalid config is skipped.
+	fake.sendEvent(path, "MODIFIED", testObject("monitoring", "cloudprober", "12", map[string]string{
+		"cloudprober.yaml": "probe:\n  - nme: p3\n",
+	}))
+	// Deleted ConfigMap keeps the current config.
+	fake.sendEvent(path, "DELETED", testObject("monitoring", "cloudprober", "13", nil))
+	waitForProbes(t, updates, []string{"p2"})
+
+	// Expired watch makes us list the objects again.
+	fake.setList(path, "20", testObject("monitoring", "cloudprober", "20", map[string]string{
+		"cloudprober.yaml": "probe:\n  - name: p4\n    type: PING\n    targets:\n      host_names: localhost\n",
+	}))
+	fake.sendEvent(path, "ERROR", `{"kind": "Status", "code": 410, "message": "too old resource version"}`)
+	waitForProbes(t, updates, []string{"p4"})
+	fake.mu.Lock()
+	assert.Equal(t, 2, fake.listCount[path])
+	fake.mu.Unlock()
+}
+
+func TestSourceSecret(t *testing.T) {
+	fake, client := newFakeAPIServer(t)
+	fake.setList("/api/v1/namespaces/monitoring/secrets", "10", testObject("monitoring", "cloudprober", "10", map[string]string{
+		"cloudprober.cfg": base64.StdEncoding.EncodeToString([]byte(`probe { name: "p1" type: PING targets { host_names: "localhost" } }`)),
+	}))
+
+	s, err := newSource("k8s://secret/monitoring/cloudprober", testClientConf(), client, nil, nil)
+	if err != nil {
+		t.Fatalf("Error creating source: %v", err)
+	}
+	content, format, err := s.GetConfig(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "textpb", format)
+	assert.Equal(t, `probe { name: "p1" type: PING targets { host_names: "localhost" } }`, content)
+
+	// Missing key and object.
+	s, _ = newSource("k8s://secret/monitoring/cloudprober/other.cfg", testClientConf(), client, nil, nil)
+	_, _, err = s.GetConfig(context.Background())
+	assert.ErrorContains(t, err, "key other.cfg not found")
+
+	s, _ = newSource("k8s://configmap/monitoring/cloudprober", testClientConf(), client, nil, nil)
+	_, _, err = s.GetConfig(context.Background())
+	assert.ErrorContains(t, err, "404")
+}
@@ -0,0 +1,179 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8ssource
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/cloudprober/cloudprober/config"
+	pb "github.com/cloudprober/cloudprober/config/k8ssource/proto"
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/probes/options"
+	probespb "github.com/cloudprober/cloudprober/probes/proto"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Probe types that are not allowed in the Probe objects by default.
+var defaultDisallowedTypes = map[probespb.ProbeDef_Type]bool{
+	probespb.ProbeDef_EXTERNAL:     true,
+	probespb.ProbeDef_USER_DEFINED: true,
+}
+
+// probeController materializes the Probe custom resources as probes.
+type probeController struct {
+	allowedTypes map[probespb.ProbeDef_Type]bool
+	watchers     []*watcher
+	l            *logger.Logger
+
+	// Probe objects that failed conversion, along with their resource
+	// version, so that errors are logged only once per object version.
+	mu      sync.Mutex
+	invalid map[string]string
+}
+
+func newProbeController(c *pb.ProbeController, client *apiClient, timeout time.Duration, l *logger.Logger) *probeController {
+	pc := &probeController{l: l, invalid: make(map[string]string)}
+	if len(c.GetAllowedType()) != 0 {
+		pc.allowedTypes = make(map[probespb.ProbeDef_Type]bool)
+		for _, t := range c.GetAllowedType() {
+			pc.allowedTypes[t] = true
+		}
+	}
+
+	query := url.Values{}
+	if c.GetLabelSelector() != "" {
+		query.Set("labelSelector", c.GetLabelSelector())
+	}
+	base := fmt.Sprintf("/apis/%s/%s", c.GetApiGroup(), c.GetApiVersion())
+	if len(c.GetNamespace()) == 0 {
+		pc.watchers = append(pc.watchers, newWatcher(client, base+"/probes", query, timeout, l))
+	}
+	for _, ns := range c.GetNamespace() {
+		pc.watchers = append(pc.watchers, newWatcher(client, fmt.Sprintf("%s/namespaces/%s/probes", base, ns), query, timeout, l))
+	}
+	return pc
+}
+
+func (pc *probeController) typeAllowed(t probespb.ProbeDef_Type) bool {
+	if pc.allowedTypes != nil {
+		return pc.allowedTypes[t]
+	}
+	return !defaultDisallowedTypes[t]
+}
+
+// probeDef converts the Probe object to a probe definition.
+func (pc *probeController) probeDef(o *object) (*probespb.ProbeDef, error) {
+	p := &probespb.ProbeDef{}
+	if len(o.Spec) == 0 {
+		return nil, fmt.Errorf("spec is missing")
+	}
+	// Name is not required in the spec, so we allow partial messages and
+	// check the required fields after setting the name.
+	if err := (protojson.UnmarshalOptions{AllowPartial: true}).Unmarshal(o.Spec, p); err != nil {
+		return nil, fmt.Errorf("invalid spec: %v", err)
+	}
+	p.Name = proto.String(o.key())
+	if p.Type == nil {
+		return nil, fmt.Errorf("probe type is not set")
+	}
+	if !pc.typeAllowed(p.GetType()) {
+		return nil, fmt.Errorf("probe type %s is not allowed", p.GetType())
+	}
+	return p, nil
+}
+
+// checkProbe validates the probe definition against the config, the same
+// way as config's own probes are validated, and resolves its validator
+// sets.
+func checkProbe(cfg *configpb.ProberConfig, p *probespb.ProbeDef) error {
+	if err := config.CheckProbe(cfg, p); err != nil {
+		return err
+	}
+	// Targets are not created here, as some target types start background
+	// discovery; they are checked when the probe is initialized.
+	tp := proto.Clone(options.WithGlobalLabels(p, cfg.GetAdditionalLabel())).(*probespb.ProbeDef)
+	tp.Targets = &targetspb.TargetsDef{Type: &targetspb.TargetsDef_DummyTargets{}}
+	_, err := options.BuildProbeOptions(tp, nil, nil, nil)
+	return err
+}
+
+// mergeProbes returns the config's probes, along with the probes from the
+// Probe objects. Config's probes take precedence over the Probe objects.
+// Probe objects that fail validation are logged and skipped.
+func (pc *probeController) mergeProbes(cfg *configpb.ProberConfig) []*probespb.ProbeDef {
+	probes := cfg.GetProbe()
+	names := make(map[string]bool)
+	for _, p := range probes {
+		names[p.GetName()] = true
+	}
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	current := make(map[string]bool)
+	for _, w := range pc.watchers {
+		for _, o := range w.snapshot() {
+			key := o.key()
+			current[key] = true
+
+			p, err := pc.probeDef(o)
+			if err == nil && names[key] {
+				err = fmt.Errorf("probe %s is already defined in the config", key)
+			}
+			if err == nil {
+				err = checkProbe(cfg, p)
+			}
+			if err != nil {
+				if pc.invalid[key] != o.Metadata.ResourceVersion {
+					pc.l.Errorf("k8ssource: skipping Probe object %s: %v", key, err)
+					pc.invalid[key] = o.Metadata.ResourceVersion
+				}
+				continue
+			}
+			delete(pc.invalid, key)
+			names[key] = true
+			probes = append(probes, p)
+		}
+	}
+	for key := range pc.invalid {
+		if !current[key] {
+			delete(pc.invalid, key)
+		}
+	}
+	return probes
+}
+
+func (pc *probeController) list(ctx context.Context) error {
+	for _, w := range pc.watchers {
+		if err := w.list(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run starts watching the Probe objects in the background.
+func (pc *probeController) run(ctx context.Context, onChange func()) {
+	for _, w := range pc.watchers {
+		go w.run(ctx, onChange)
+	}
+}
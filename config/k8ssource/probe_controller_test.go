@@ -0,0 +1,152 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8ssource
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudprober/cloudprober/config"
+	pb "github.com/cloudprober/cloudprober/config/k8ssource/proto"
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	probespb "github.com/cloudprober/cloudprober/probes/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func testProbeObject(ns, name, rv, spec string) string {
+	o := &object{Spec: json.RawMessage(spec)}
+	o.Metadata.Namespace, o.Metadata.Name, o.Metadata.ResourceVersion = ns, name, rv
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func TestProbeDef(t *testing.T) {
+	pc := newProbeController(&pb.ProbeController{}, nil, 0, nil)
+
+	p, err := pc.probeDef(&object{Spec: json.RawMessage(`{"type": "HTTP", "targets": {"host_names": "web"}, "interval": "10s", "name": "ignored"}`)})
+	assert.NoError(t, err)
+	assert.Equal(t, "HTTP", p.GetType().String())
+	assert.Equal(t, "10s", p.GetInterval())
+	assert.Equal(t, "/", p.GetName())
+
+	for _, spec := range []string{
+		``,
+		`{"targets": {"host_names": "web"}}`,
+		`{"type": "HTTP", "unknown_field": 1}`,
+		`{"type": "EXTERNAL", "external_probe": {"command": "rm -rf /"}}`,
+	} {
+		_, err := pc.probeDef(&object{Spec: json.RawMessage(spec)})
+		assert.Error(t, err, spec)
+	}
+
+	// Explicitly allowed types.
+	pc = newProbeController(&pb.ProbeController{AllowedType: []probespb.ProbeDef_Type{probespb.ProbeDef_EXTERNAL}}, nil, 0, nil)
+	_, err = pc.probeDef(&object{Spec: json.RawMessage(`{"type": "EXTERNAL", "external_probe": {"command": "true"}}`)})
+	assert.NoError(t, err)
+	_, err = pc.probeDef(&object{Spec: json.RawMessage(`{"type": "HTTP"}`)})
+	assert.Error(t, err)
+}
+
+func TestProbeController(t *testing.T) {
+	fake, client := newFakeAPIServer(t)
+	cmPath := "/api/v1/namespaces/monitoring/configmaps"
+	fake.setList(cmPath, "10", testObject("monitoring", "cloudprober", "10", map[string]string{
+		"cloudprober.cfg": `probe { name: "team-b/db" type: PING targets { host_names: "localhost" } }`,
+	}))
+	probesA, probesB := "/apis/cloudprober.org/v1/namespaces/team-a/probes", "/apis/cloudprober.org/v1/namespaces/team-b/probes"
+	fake.setList(probesA, "5",
+		testProbeObject("team-a", "web", "5", `{"type": "HTTP", "targets": {"host_names": "web"}}`),
+		testProbeObject("team-a", "cmd", "5", `{"type": "EXTERNAL", "external_probe": {"command": "true"}}`))
+	fake.setList(probesB, "7",
+		testProbeObject("team-b", "db", "7", `{"type": "TCP", "targets": {"host_names": "db"}}`))
+
+	c := testClientConf()
+	c.Watch = proto.Bool(false)
+	c.ProbeController = &pb.ProbeController{
+		Namespace:     []string{"team-a", "team-b"},
+		LabelSelector: proto.String("cloudprober=enabled"),
+	}
+	s, err := newSource("k8s://configmap/monitoring/cloudprober", c, client, nil, nil)
+	if err != nil {
+		t.Fatalf("Error creating source: %v", err)
+	}
+
+	content, format, err := s.GetConfig(context.Background())
+	assert.NoError(t, err)
+	cfg, _, err := s.ParseConfig(context.Background(), content, format)
+	assert.NoError(t, err)
+	// team-a/cmd is not allowed, and team-b/db conflicts with the config's
+	// probe.
+	assert.Equal(t, []string{"team-b/db", "team-a/web"}, probeNames(cfg))
+	assert.Equal(t, "PING", cfg.GetProbe()[0].GetType().String())
+	assert.Equal(t, "labelSelector=cloudprober%3Denabled", fake.queries[probesA])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan *configpb.ProberConfig, 10)
+	go s.Watch(ctx, func(u *config.Update) { updates <- u.Config })
+
+	fake.sendEvent(probesA, "ADDED", testProbeObject("team-a", "api", "6", `{"type": "HTTP", "targets": {"host_names": "api"}}`))
+	waitForProbes(t, updates, []string{"team-b/db", "team-a/api", "team-a/web"})
+
+	fake.sendEvent(probesA, "DELETED", testProbeObject("team-a", "web", "7", `{}`))
+	waitForProbes(t, updates, []string{"team-b/db", "team-a/api"})
+}
+
+func TestProbeControllerInvalidProbes(t *testing.T) {
+	fake, client := newFakeAPIServer(t)
+	cmPath := "/api/v1/namespaces/monitoring/configmaps"
+	fake.setList(cmPath, "10", testObject("monitoring", "cloudprober", "10", map[string]string{
+		"cloudprober.cfg": `
+			surfacer { type: PROMETHEUS }
+			additional_label { key: "env" value: "prod" }
+			validator_set {
+				name: "ok"
+				validator { name: "status" http_validator { success_status_codes: "200" } }
+			}`,
+	}))
+	probesPath := "/apis/cloudprober.org/v1/namespaces/team-a/probes"
+	fake.setList(probesPath, "5",
+		testProbeObject("team-a", "web", "5", `{"type": "HTTP", "targets": {"host_names": "web"}, "surfacers": ["prometheus"], "validator_set": ["ok"]}`),
+		testProbeObject("team-a", "bad-surfacer", "5", `{"type": "HTTP", "targets": {"host_names": "web"}, "surfacers": ["stackdriver"]}`),
+		testProbeObject("team-a", "bad-validator-set", "5", `{"type": "HTTP", "targets": {"host_names": "web"}, "validator_set": ["unknown"]}`),
+		testProbeObject("team-a", "bad-interval", "5", `{"type": "HTTP", "targets": {"host_names": "web"}, "interval": "1s", "timeout": "5s"}`),
+		testProbeObject("team-a", "bare-interval", "5", `{"type": "HTTP", "targets": {"host_names": "web"}, "interval": "10"}`),
+		testProbeObject("team-a", "dup-label", "5", `{"type": "HTTP", "targets": {"host_names": "web"}, "additional_label": [{"key": "env", "value": "dev", "merge_policy": "ERROR"}]}`))
+
+	c := testClientConf()
+	c.Watch = proto.Bool(false)
+	c.ProbeController = &pb.ProbeController{Namespace: []string{"team-a"}}
+	s, err := newSource("k8s://configmap/monitoring/cloudprober", c, client, nil, nil)
+	if err != nil {
+		t.Fatalf("Error creating source: %v", err)
+	}
+
+	content, format, err := s.GetConfig(context.Background())
+	assert.NoError(t, err)
+	cfg, _, err := s.ParseConfig(context.Background(), content, format)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"team-a/web"}, probeNames(cfg))
+
+	// Validator sets are resolved.
+	p := cfg.GetProbe()[0]
+	assert.Empty(t, p.GetValidatorSet())
+	if assert.Len(t, p.GetValidator(), 1) {
+		assert.Equal(t, "status", p.GetValidator()[0].GetName())
+	}
+	assert.Len(t, s.probes.invalid, 5)
+}
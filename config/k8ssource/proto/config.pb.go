@@ -0,0 +1,375 @@
+// This file defines the client config for the kubernetes config source. To
+// read the config from a ConfigMap or a Secret through the kubernetes API
+// server, start cloudprober with the config file set to a k8s:// URL, e.g.:
+//   cloudprober --config_file=k8s://configmap/monitoring/cloudprober
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.21.5
+// source: github.com/cloudprober/cloudprober/config/k8ssource/proto/config.proto
+
+package proto
+
+import (
+	proto "github.com/cloudprober/cloudprober/internal/tlsconfig/proto"
+	proto1 "github.com/cloudprober/cloudprober/probes/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ClientConf configures the kubernetes config source. It's read from the
+// file specified by the --config_k8s_client_conf flag.
+type ClientConf struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Kubernetes API server address. If not specified, we assume in-cluster
+	// operation and use the KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT
+	// environment variables, and the service account's token and CA cert.
+	ApiServerAddress *string `protobuf:"bytes,1,opt,name=api_server_address,json=apiServerAddress" json:"api_server_address,omitempty"`
+	// TLS config to connect to the API server. If not specified, in-cluster CA
+	// cert is used.
+	TlsConfig *proto.TLSConfig `protobuf:"bytes,2,opt,name=tls_config,json=tlsConfig" json:"tls_config,omitempty"`
+	// Whether to watch the ConfigMap (or Secret) for updates. If enabled,
+	// probes and surfacers are updated as the config changes. Changes to other
+	// parts of the config require a restart.
+	Watch *bool `protobuf:"varint,3,opt,name=watch,def=1" json:"watch,omitempty"`
+	// Timeout for the initial config read.
+	TimeoutSec *int32 `protobuf:"varint,4,opt,name=timeout_sec,json=timeoutSec,def=30" json:"timeout_sec,omitempty"`
+	// If configured, Probe objects (see ProbeController) are watched, and
+	// probes are added, updated and removed along with them.
+	ProbeController *ProbeController `protobuf:"bytes,5,opt,name=probe_controller,json=probeController" json:"probe_controller,omitempty"`
+}
+
+// Default values for ClientConf fields.
+const (
+	Default_ClientConf_Watch      = bool(true)
+	Default_ClientConf_TimeoutSec = int32(30)
+)
+
+func (x *ClientConf) Reset() {
+	*x = ClientConf{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientConf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientConf) ProtoMessage() {}
+
+func (x *ClientConf) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientConf.ProtoReflect.Descriptor instead.
+func (*ClientConf) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ClientConf) GetApiServerAddress() string {
+	if x != nil && x.ApiServerAddress != nil {
+		return *x.ApiServerAddress
+	}
+	return ""
+}
+
+func (x *ClientConf) GetTlsConfig() *proto.TLSConfig {
+	if x != nil {
+		return x.TlsConfig
+	}
+	return nil
+}
+
+func (x *ClientConf) GetWatch() bool {
+	if x != nil && x.Watch != nil {
+		return *x.Watch
+	}
+	return Default_ClientConf_Watch
+}
+
+func (x *ClientConf) GetTimeoutSec() int32 {
+	if x != nil && x.TimeoutSec != nil {
+		return *x.TimeoutSec
+	}
+	return Default_ClientConf_TimeoutSec
+}
+
+func (x *ClientConf) GetProbeController() *ProbeController {
+	if x != nil {
+		return x.ProbeController
+	}
+	return nil
+}
+
+// ProbeController watches the Probe custom resources, and materializes them
+// as probes. Probe object's spec is a probe definition, in the same format as
+// the probe in the YAML config, e.g.:
+//
+//	apiVersion: cloudprober.org/v1
+//	kind: Probe
+//	metadata:
+//	  name: web
+//	  namespace: team-a
+//	spec:
+//	  type: HTTP
+//	  targets:
+//	    host_names: web.team-a.svc.cluster.local
+//	  interval: 10s
+//
+// Probes are named <namespace>/<name>, e.g. team-a/web for the above object,
+// so that teams can't override each other's probes or the probes in the main
+// config. Probe objects with invalid spec are skipped (with an error log).
+// CRD for the Probe objects is available in config/k8ssource/probe_crd.yaml.
+type ProbeController struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Namespaces to watch the Probe objects in. If not specified, objects in
+	// all namespaces are watched.
+	Namespace []string `protobuf:"bytes,1,rep,name=namespace" json:"namespace,omitempty"`
+	// Label selector to filter the Probe objects, e.g. "cloudprober=enabled".
+	LabelSelector *string `protobuf:"bytes,2,opt,name=label_selector,json=labelSelector" json:"label_selector,omitempty"`
+	// Probe types allowed in the Probe objects. If not specified, all types
+	// but EXTERNAL and USER_DEFINED are allowed, as the EXTERNAL probes run
+	// commands on the cloudprober host.
+	AllowedType []proto1.ProbeDef_Type `protobuf:"varint,3,rep,name=allowed_type,json=allowedType,enum=cloudprober.probes.ProbeDef_Type" json:"allowed_type,omitempty"`
+	// API group and version of the Probe custom resource.
+	ApiGroup   *string `protobuf:"bytes,4,opt,name=api_group,json=apiGroup,def=cloudprober.org" json:"api_group,omitempty"`
+	ApiVersion *string `protobuf:"bytes,5,opt,name=api_version,json=apiVersion,def=v1" json:"api_version,omitempty"`
+}
+
+// Default values for ProbeController fields.
+const (
+	Default_ProbeController_ApiGroup   = string("cloudprober.org")
+	Default_ProbeController_ApiVersion = string("v1")
+)
+
+func (x *ProbeController) Reset() {
+	*x = ProbeController{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProbeController) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeController) ProtoMessage() {}
+
+func (x *ProbeController) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeController.ProtoReflect.Descriptor instead.
+func (*ProbeController) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProbeController) GetNamespace() []string {
+	if x != nil {
+		return x.Namespace
+	}
+	return nil
+}
+
+func (x *ProbeController) GetLabelSelector() string {
+	if x != nil && x.LabelSelector != nil {
+		return *x.LabelSelector
+	}
+	return ""
+}
+
+func (x *ProbeController) GetAllowedType() []proto1.ProbeDef_Type {
+	if x != nil {
+		return x.AllowedType
+	}
+	return nil
+}
+
+func (x *ProbeController) GetApiGroup() string {
+	if x != nil && x.ApiGroup != nil {
+		return *x.ApiGroup
+	}
+	return Default_ProbeController_ApiGroup
+}
+
+func (x *ProbeController) GetApiVersion() string {
+	if x != nil && x.ApiVersion != nil {
+		return *x.ApiVersion
+	}
+	return Default_ProbeController_ApiVersion
+}
+
+var File_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto protoreflect.FileDescriptor
+
+var file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_rawDesc = []byte{
+	0x0a, 0x46, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x6b, 0x38, 0x73, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1c, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70,
+	0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x6b, 0x38, 0x73,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x1a, 0x48, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x2f, 0x74, 0x6c, 0x73, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x3c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x96,
+	0x02, 0x0a, 0x0a, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x12, 0x2c, 0x0a,
+	0x12, 0x61, 0x70, 0x69, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x61, 0x70, 0x69, 0x53, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x3f, 0x0a, 0x0a, 0x74,
+	0x6c, 0x73, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x20, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x74, 0x6c,
+	0x73, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x54, 0x4c, 0x53, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x52, 0x09, 0x74, 0x6c, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1a, 0x0a, 0x05,
+	0x77, 0x61, 0x74, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x3a, 0x04, 0x74, 0x72, 0x75,
+	0x65, 0x52, 0x05, 0x77, 0x61, 0x74, 0x63, 0x68, 0x12, 0x23, 0x0a, 0x0b, 0x74, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x02, 0x33,
+	0x30, 0x52, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x12, 0x58, 0x0a,
+	0x10, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65,
+	0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70,
+	0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x6b, 0x38, 0x73,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x74,
+	0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e,
+	0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x22, 0xef, 0x01, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x62,
+	0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x6e,
+	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09,
+	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x6c, 0x61, 0x62,
+	0x65, 0x6c, 0x5f, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x12, 0x44, 0x0a, 0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x62,
+	0x65, 0x44, 0x65, 0x66, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x61, 0x6c, 0x6c, 0x6f, 0x77,
+	0x65, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2c, 0x0a, 0x09, 0x61, 0x70, 0x69, 0x5f, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x3a, 0x0f, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x6f, 0x72, 0x67, 0x52, 0x08, 0x61, 0x70, 0x69, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x12, 0x23, 0x0a, 0x0b, 0x61, 0x70, 0x69, 0x5f, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x3a, 0x02, 0x76, 0x31, 0x52, 0x0a, 0x61,
+	0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x3b, 0x5a, 0x39, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f,
+	0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x6b, 0x38, 0x73, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+}
+
+var (
+	file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_rawDescOnce sync.Once
+	file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_rawDescData = file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_rawDesc
+)
+
+func file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_rawDescGZIP() []byte {
+	file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_rawDescOnce.Do(func() {
+		file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_rawDescData)
+	})
+	return file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_rawDescData
+}
+
+var file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_goTypes = []interface{}{
+	(*ClientConf)(nil),        // 0: cloudprober.config.k8ssource.ClientConf
+	(*ProbeController)(nil),   // 1: cloudprober.config.k8ssource.ProbeController
+	(*proto.TLSConfig)(nil),   // 2: cloudprober.tlsconfig.TLSConfig
+	(proto1.ProbeDef_Type)(0), // 3: cloudprober.probes.ProbeDef.Type
+}
+var file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_depIdxs = []int32{
+	2, // 0: cloudprober.config.k8ssource.ClientConf.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
+	1, // 1: cloudprober.config.k8ssource.ClientConf.probe_controller:type_name -> cloudprober.config.k8ssource.ProbeController
+	3, // 2: cloudprober.config.k8ssource.ProbeController.allowed_type:type_name -> cloudprober.probes.ProbeDef.Type
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_init() }
+func file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_init() {
+	if File_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClientConf); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProbeController); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_goTypes,
+		DependencyIndexes: file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_depIdxs,
+		MessageInfos:      file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_msgTypes,
+	}.Build()
+	File_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto = out.File
+	file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_rawDesc = nil
+	file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_goTypes = nil
+	file_github_com_cloudprober_cloudprober_config_k8ssource_proto_config_proto_depIdxs = nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Warning is a non-fatal issue found in a parsed config, e.g. use of a
+// deprecated field.
+type Warning struct {
+	// Field is the path of the field the warning is about, e.g.
+	// probe[0].http_probe.relative_url. It's empty for config wide warnings.
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (w Warning) String() string {
+	if w.Field == "" {
+		return w.Message
+	}
+	return w.Field + ": " + w.Message
+}
+
+// LintConfig returns the warnings for the given parsed config. Currently it
+// reports:
+//   - deprecated fields that are set.
+//   - environment variable placeholders that were not substituted as the
+//     corresponding variables are not defined.
+func LintConfig(cfg *configpb.ProberConfig) []Warning {
+	var warnings []Warning
+	lintMessage(cfg.ProtoReflect(), "", &warnings)
+	return warnings
+}
+
+// lintMessage looks at the message fields in the declaration order, to keep
+// the warnings order stable.
+func lintMessage(m protoreflect.Message, path string, warnings *[]Warning) {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !m.Has(fd) {
+			continue
+		}
+		v := m.Get(fd)
+
+		fieldPath := fd.TextName()
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+
+		if opts, ok := fd.Options().(*descriptorpb.FieldOptions); ok && opts.GetDeprecated() {
+			*warnings = append(*warnings, Warning{Field: fieldPath, Message: "field is deprecated"})
+		}
+
+		switch {
+		case fd.IsList():
+			l := v.List()
+			for i := 0; i < l.Len(); i++ {
+				lintValue(fd, l.Get(i), fmt.Sprintf("%s[%d]", fieldPath, i), warnings)
+			}
+		case fd.IsMap():
+			keys := make(map[string]protoreflect.MapKey)
+			v.Map().Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+				keys[k.String()] = k
+				return true
+			})
+			for _, k := range sortedKeys(keys) {
+				lintValue(fd.MapValue(), v.Map().Get(keys[k]), fmt.Sprintf("%s[%s]", fieldPath, k), warnings)
+			}
+		default:
+			lintValue(fd, v, fieldPath, warnings)
+		}
+	}
+}
+
+func sortedKeys(m map[string]protoreflect.MapKey) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func lintValue(fd protoreflect.FieldDescriptor, v protoreflect.Value, path string, warnings *[]Warning) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		lintMessage(v.Message(), path, warnings)
+	case protoreflect.StringKind:
+		for _, m := range EnvRegex.FindAllStringSubmatch(v.String(), -1) {
+			*warnings = append(*warnings, Warning{
+				Field:   path,
+				Message: fmt.Sprintf("environment variable %s is not defined", m[1]),
+			})
+		}
+	}
+}
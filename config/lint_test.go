@@ -0,0 +1,69 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintConfig(t *testing.T) {
+	content := `
+		probe {
+			name: "ping"
+			type: PING
+			targets {
+				host_names: "1.1.1.1"
+			}
+		}
+		probe {
+			name: "http"
+			type: HTTP
+			targets {
+				host_names: "**$CLOUDPROBER_LINT_TEST_HOST**"
+			}
+			additional_label {
+				key: "token"
+				value: "**$CLOUDPROBER_LINT_TEST_TOKEN:-default**"
+			}
+		}
+		global_targets_options {
+			rds_server_address: "rds-server:9314"
+		}`
+
+	cfg, _, err := ParseConfig(context.Background(), content, "textpb", nil, nil)
+	if err != nil {
+		t.Fatalf("ParseConfig() error: %v", err)
+	}
+
+	want := []Warning{
+		{Field: "probe[1].targets.host_names", Message: "environment variable CLOUDPROBER_LINT_TEST_HOST is not defined"},
+		{Field: "global_targets_options.rds_server_address", Message: "field is deprecated"},
+	}
+	assert.Equal(t, want, LintConfig(cfg))
+
+	cfg, _, err = ParseConfig(context.Background(), `probe { name: "ping" type: PING targets { host_names: "1.1.1.1" } }`, "textpb", nil, nil)
+	if err != nil {
+		t.Fatalf("ParseConfig() error: %v", err)
+	}
+	assert.Empty(t, LintConfig(cfg))
+}
+
+func TestWarningString(t *testing.T) {
+	assert.Equal(t, "probe[0].name: some warning", Warning{Field: "probe[0].name", Message: "some warning"}.String())
+	assert.Equal(t, "some warning", Warning{Message: "some warning"}.String())
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var configLoadTimeout = flag.Duration("config_load_timeout", 0, "Overall timeout for loading the config, including remote fetches, e.g. from GCS or GCE metadata, and template processing. Zero means no timeout.")
+
+// RunWithLoadTimeout runs the config loading function f, enforcing the
+// --config_load_timeout. f reports its progress using setStage, and the last
+// reported stage is included in the error if loading times out.
+//
+// On timeout, the context passed to f is canceled. Fetches from the gRPC,
+// etcd and Kubernetes config sources, remote file reads (GCS, S3 and
+// HTTP(S)), and secret resolution use this context, and return on timeout.
+// Cloud metadata reads don't, and run to completion in the background. As
+// f's result is discarded on timeout, f should close the config source that
+// it creates if the context is canceled.
+func RunWithLoadTimeout(f func(ctx context.Context, setStage func(string)) error) error {
+	return runWithTimeout(*configLoadTimeout, f)
+}
+
+func runWithTimeout(timeout time.Duration, f func(ctx context.Context, setStage func(string)) error) error {
+	var mu sync.Mutex
+	stage := "start"
+	setStage := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		stage = s
+	}
+
+	if timeout <= 0 {
+		return f(context.Background(), setStage)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- f(ctx, setStage)
+	}()
+
+	select {
+	case err := <-errCh:
+		// f may return because of the context timeout, report it as such.
+		if ctx.Err() == nil {
+			return err
+		}
+	case <-ctx.Done():
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return fmt.Errorf("config load timed out at stage %q (timeout: %v)", stage, timeout)
+}
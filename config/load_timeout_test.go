@@ -0,0 +1,102 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunWithTimeout(t *testing.T) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+	ctxCanceled := make(chan struct{})
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		f       func(ctx context.Context, setStage func(string)) error
+		wantErr string
+	}{
+		{
+			name:    "no_timeout",
+			timeout: 0,
+			f:       func(ctx context.Context, setStage func(string)) error { return nil },
+		},
+		{
+			name:    "no_timeout_error",
+			timeout: 0,
+			f:       func(ctx context.Context, setStage func(string)) error { return errors.New("bad config") },
+			wantErr: "bad config",
+		},
+		{
+			name:    "finishes_in_time",
+			timeout: time.Minute,
+			f: func(ctx context.Context, setStage func(string)) error {
+				setStage("reading config")
+				return nil
+			},
+		},
+		{
+			name:    "error_in_time",
+			timeout: time.Minute,
+			f:       func(ctx context.Context, setStage func(string)) error { return errors.New("bad config") },
+			wantErr: "bad config",
+		},
+		{
+			name:    "timeout",
+			timeout: 10 * time.Millisecond,
+			f: func(ctx context.Context, setStage func(string)) error {
+				setStage("reading config")
+				setStage("parsing config")
+				<-unblock
+				return nil
+			},
+			wantErr: `config load timed out at stage "parsing config"`,
+		},
+		{
+			name:    "timeout_cancels_ctx",
+			timeout: 10 * time.Millisecond,
+			f: func(ctx context.Context, setStage func(string)) error {
+				setStage("fetching config")
+				<-ctx.Done()
+				close(ctxCanceled)
+				return ctx.Err()
+			},
+			wantErr: `config load timed out at stage "fetching config"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := runWithTimeout(test.timeout, test.f)
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, test.wantErr)
+		})
+	}
+	// Config loading function exits on timeout if it respects the context.
+	select {
+	case <-ctxCanceled:
+	case <-time.After(time.Second):
+		t.Error("context was not canceled on timeout")
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/compute/metadata"
+	"github.com/cloudprober/cloudprober/logger"
+)
+
+// MetadataConfigSource is a cloud metadata based config source. If no config
+// file is specified, GetConfig looks for the config in the active metadata
+// sources, before falling back to the default config file.
+type MetadataConfigSource interface {
+	// Name returns the name of the source, e.g. gce.
+	Name() string
+
+	// Active reports whether we are running in the source's environment.
+	Active() bool
+
+	// ReadConfig returns the config stored in the metadata under the given
+	// key.
+	ReadConfig(key string) (string, error)
+}
+
+var (
+	metadataConfigSources   = []MetadataConfigSource{&gceConfigSource{}, &ec2ConfigSource{}}
+	metadataConfigSourcesMu sync.RWMutex
+)
+
+// RegisterMetadataConfigSource registers an additional metadata config
+// source. Sources are looked at in the order of their registration, after
+// the built-in GCE and EC2 sources.
+func RegisterMetadataConfigSource(src MetadataConfigSource) {
+	metadataConfigSourcesMu.Lock()
+	defer metadataConfigSourcesMu.Unlock()
+	metadataConfigSources = append(metadataConfigSources, src)
+}
+
+// readConfigFromMetadata returns the config from the first active metadata
+// source that returns it successfully.
+func readConfigFromMetadata(key string, l *logger.Logger) (string, bool) {
+	metadataConfigSourcesMu.RLock()
+	defer metadataConfigSourcesMu.RUnlock()
+
+	for _, src := range metadataConfigSources {
+		if !src.Active() {
+			continue
+		}
+		config, err := src.ReadConfig(key)
+		if err != nil {
+			l.Infof("Error reading config from %s metadata. Err: %v", src.Name(), err)
+			continue
+		}
+		l.Infof("Read config from %s metadata.", src.Name())
+		return config, true
+	}
+	return "", false
+}
+
+// gceConfigSource reads config from the GCE custom metadata.
+type gceConfigSource struct{}
+
+func (*gceConfigSource) Name() string { return "gce" }
+
+func (*gceConfigSource) Active() bool { return metadata.OnGCE() }
+
+func (*gceConfigSource) ReadConfig(key string) (string, error) {
+	return ReadFromGCEMetadata(key)
+}
+
+// ec2IMDSEndpoint is the EC2 instance metadata service (IMDS) endpoint.
+var ec2IMDSEndpoint = "http://169.254.169.254"
+
+var configFromEC2UserData = flag.Bool("config_from_ec2_user_data", false, "If no config file is specified, read the config from the EC2 instance user-data. It's off by default, as user-data is commonly used for other purposes, e.g. the instance initialization scripts.")
+
+// ec2ConfigSource reads config from the EC2 instance user-data, using IMDSv2.
+// EC2 doesn't have a keyed metadata big enough for the configs (instance tags
+// are limited to 256 characters), so the key is not used. As user-data may
+// be anything, the source is used only if enabled through the
+// --config_from_ec2_user_data flag.
+type ec2ConfigSource struct{}
+
+func (*ec2ConfigSource) Name() string { return "ec2" }
+
+// token gets an IMDSv2 session token.
+func (*ec2ConfigSource) token(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, ec2IMDSEndpoint+"/latest/api/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error getting IMDSv2 token, status: %s", resp.Status)
+	}
+	b, err := io.ReadAll(resp.Body)
+	return string(b), err
+}
+
+// Active reports whether the source is enabled and IMDSv2 is available.
+// Similar to sysvars, we use a short timeout so that we don't slow down
+// startup outside of EC2.
+func (s *ec2ConfigSource) Active() bool {
+	if !*configFromEC2UserData {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := s.token(ctx)
+	return err == nil
+}
+
+func (s *ec2ConfigSource) ReadConfig(_ string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	token, err := s.token(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ec2IMDSEndpoint+"/latest/user-data", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", errors.New("user-data not set")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error getting user-data, status: %s", resp.Status)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	// User-data is commonly used for the instance initialization scripts.
+	config := string(b)
+	if strings.HasPrefix(config, "#!") || strings.HasPrefix(config, "#cloud-config") {
+		return "", errors.New("user-data is not a cloudprober config")
+	}
+	return config, nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMetadataSource struct {
+	name   string
+	active bool
+	config map[string]string
+}
+
+func (s *testMetadataSource) Name() string { return s.name }
+func (s *testMetadataSource) Active() bool { return s.active }
+
+func (s *testMetadataSource) ReadConfig(key string) (string, error) {
+	if c, ok := s.config[key]; ok {
+		return c, nil
+	}
+	return "", errors.New("not found")
+}
+
+func TestReadConfigFromMetadata(t *testing.T) {
+	oldSources := metadataConfigSources
+	defer func() { metadataConfigSources = oldSources }()
+
+	inactive := &testMetadataSource{name: "inactive", config: map[string]string{"cfg": "inactive"}}
+	notFound := &testMetadataSource{name: "not-found", active: true}
+	found := &testMetadataSource{name: "found", active: true, config: map[string]string{"cfg": "found"}}
+
+	metadataConfigSources = []MetadataConfigSource{inactive, notFound}
+	_, ok := readConfigFromMetadata("cfg", nil)
+	assert.False(t, ok)
+
+	RegisterMetadataConfigSource(found)
+	config, ok := readConfigFromMetadata("cfg", nil)
+	assert.True(t, ok)
+	assert.Equal(t, "found", config)
+}
+
+func TestEC2ConfigSource(t *testing.T) {
+	const token = "test-token"
+
+	tests := []struct {
+		name       string
+		userData   string
+		noToken    bool
+		wantActive bool
+		wantErr    bool
+	}{
+		{
+			name:       "config",
+			userData:   `probe { name: "ping" type: PING }`,
+			wantActive: true,
+		},
+		{
+			name:       "no_user_data",
+			wantActive: true,
+			wantErr:    true,
+		},
+		{
+			name:       "script",
+			userData:   "#!/bin/bash\necho hello",
+			wantActive: true,
+			wantErr:    true,
+		},
+		{
+			name:    "no_imdsv2",
+			noToken: true,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/latest/api/token" && r.Method == http.MethodPut && !test.noToken:
+					if r.Header.Get("X-aws-ec2-metadata-token-ttl-seconds") == "" {
+						http.Error(w, "missing ttl", http.StatusBadRequest)
+						return
+					}
+					w.Write([]byte(token))
+				case r.URL.Path == "/latest/user-data" && test.userData != "":
+					if r.Header.Get("X-aws-ec2-metadata-token") != token {
+						http.Error(w, "unauthorized", http.StatusUnauthorized)
+						return
+					}
+					w.Write([]byte(test.userData))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer ts.Close()
+
+			oldEndpoint := ec2IMDSEndpoint
+			defer func() { ec2IMDSEndpoint = oldEndpoint }()
+			ec2IMDSEndpoint = ts.URL
+
+			src := &ec2ConfigSource{}
+			oldFlag := *configFromEC2UserData
+			defer func() { *configFromEC2UserData = oldFlag }()
+
+			// Source is used only if explicitly enabled.
+			*configFromEC2UserData = false
+			assert.False(t, src.Active(), "source not enabled")
+
+			*configFromEC2UserData = true
+			assert.Equal(t, test.wantActive, src.Active())
+
+			config, err := src.ReadConfig(configMetadataKeyName)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.userData, config)
+		})
+	}
+}
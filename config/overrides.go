@@ -0,0 +1,53 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/logger"
+	"google.golang.org/protobuf/proto"
+)
+
+// overrideMatches returns true if all the override's selector variables
+// match the runtime variables.
+func overrideMatches(o *configpb.ConfigOverride, vars map[string]string) bool {
+	for k, v := range o.GetSelector() {
+		if val, ok := vars[k]; !ok || val != v {
+			return false
+		}
+	}
+	return true
+}
+
+// applyOverrides merges the matching overrides over the base config, in
+// order, and removes all the overrides from the config.
+func applyOverrides(cfg *configpb.ProberConfig, vars map[string]string, l *logger.Logger) error {
+	overrides := cfg.GetOverride()
+	cfg.Override = nil
+
+	for i, o := range overrides {
+		if len(o.GetConfig().GetOverride()) > 0 {
+			return fmt.Errorf("override %d: nested overrides are not supported", i)
+		}
+		if !overrideMatches(o, vars) {
+			continue
+		}
+		l.Infof("Applying config override %d, selector: %v", i, o.GetSelector())
+		proto.Merge(cfg, o.GetConfig())
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	content := `
+		probe {
+			name: "base"
+			type: PING
+			targets {
+				host_names: "1.1.1.1"
+			}
+		}
+		sysvars_interval_msec: 10000
+		override {
+			selector {
+				key: "cloud_provider"
+				value: "gce"
+			}
+			selector {
+				key: "region"
+				value: "us-central1"
+			}
+			config {
+				probe {
+					name: "us_central1"
+					type: PING
+					targets {
+						host_names: "2.2.2.2"
+					}
+				}
+				sysvars_interval_msec: 20000
+			}
+		}
+		override {
+			selector {
+				key: "region"
+				value: "us-east-1"
+			}
+			config {
+				probe {
+					name: "us_east_1"
+					type: PING
+					targets {
+						host_names: "3.3.3.3"
+					}
+				}
+			}
+		}`
+
+	tests := []struct {
+		name             string
+		vars             map[string]string
+		wantProbes       []string
+		wantSysvarsIntvl int32
+	}{
+		{
+			name:             "no_vars",
+			wantProbes:       []string{"base"},
+			wantSysvarsIntvl: 10000,
+		},
+		{
+			name:             "partial_match",
+			vars:             map[string]string{"cloud_provider": "ec2", "region": "us-central1"},
+			wantProbes:       []string{"base"},
+			wantSysvarsIntvl: 10000,
+		},
+		{
+			name:             "us_central1",
+			vars:             map[string]string{"cloud_provider": "gce", "region": "us-central1"},
+			wantProbes:       []string{"base", "us_central1"},
+			wantSysvarsIntvl: 20000,
+		},
+		{
+			name:             "us_east_1",
+			vars:             map[string]string{"cloud_provider": "ec2", "region": "us-east-1"},
+			wantProbes:       []string{"base", "us_east_1"},
+			wantSysvarsIntvl: 10000,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, _, err := ParseConfig(context.Background(), content, "textpb", test.vars, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Empty(t, cfg.GetOverride())
+
+			var probes []string
+			for _, p := range cfg.GetProbe() {
+				probes = append(probes, p.GetName())
+			}
+			assert.Equal(t, test.wantProbes, probes)
+			assert.Equal(t, test.wantSysvarsIntvl, cfg.GetSysvarsIntervalMsec())
+		})
+	}
+}
+
+func TestApplyOverridesNested(t *testing.T) {
+	content := `
+		override {
+			config {
+				override {
+					config {}
+				}
+			}
+		}`
+	_, _, err := ParseConfig(context.Background(), content, "textpb", nil, nil)
+	assert.ErrorContains(t, err, "nested overrides are not supported")
+}
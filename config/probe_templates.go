@@ -0,0 +1,84 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	httppb "github.com/cloudprober/cloudprober/probes/http/proto"
+	probespb "github.com/cloudprober/cloudprober/probes/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// expandProbeTemplate returns the probes generated from the probe template,
+// one for each instance.
+func expandProbeTemplate(pt *configpb.ProbeTemplate) ([]*probespb.ProbeDef, error) {
+	tmplName := pt.GetProbe().GetName()
+
+	var probes []*probespb.ProbeDef
+	instances := make(map[string]bool)
+	for _, inst := range pt.GetInstance() {
+		if instances[inst.GetName()] {
+			return nil, fmt.Errorf("probe_template %s: instance %s is defined twice", tmplName, inst.GetName())
+		}
+		instances[inst.GetName()] = true
+
+		p := proto.Clone(pt.GetProbe()).(*probespb.ProbeDef)
+		p.Name = proto.String(tmplName + "-" + inst.GetName())
+
+		if inst.GetTargets() != nil {
+			p.Targets = inst.GetTargets()
+		}
+		p.AdditionalLabel = append(p.AdditionalLabel, inst.GetAdditionalLabel()...)
+
+		if len(inst.GetHttpHeader()) > 0 || inst.GetOauthConfig() != nil {
+			if p.GetType() != probespb.ProbeDef_HTTP {
+				return nil, fmt.Errorf("probe_template %s: instance %s: http_header and oauth_config are supported only for HTTP probes", tmplName, inst.GetName())
+			}
+			if p.GetHttpProbe() == nil {
+				p.Probe = &probespb.ProbeDef_HttpProbe{HttpProbe: &httppb.ProbeConf{}}
+			}
+			httpConf := p.GetHttpProbe()
+			httpConf.Headers = append(httpConf.Headers, inst.GetHttpHeader()...)
+			if inst.GetOauthConfig() != nil {
+				httpConf.OauthConfig = inst.GetOauthConfig()
+			}
+		}
+
+		probes = append(probes, p)
+	}
+	return probes, nil
+}
+
+// expandProbeTemplates replaces the probe templates by the probes generated
+// from them. Generated probes are added after the regular probes.
+func expandProbeTemplates(cfg *configpb.ProberConfig) error {
+	var errs []error
+	for _, pt := range cfg.GetProbeTemplate() {
+		probes, err := expandProbeTemplate(pt)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		cfg.Probe = append(cfg.Probe, probes...)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	cfg.ProbeTemplate = nil
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandProbeTemplates(t *testing.T) {
+	tmpl := `
+		probe {
+			name: "regular"
+			type: PING
+			targets {
+				host_names: "1.1.1.1"
+			}
+		}
+		probe_template {
+			probe {
+				name: "api"
+				type: HTTP
+				targets {
+					host_names: "default.example.com"
+				}
+				additional_label {
+					key: "team"
+					value: "sre"
+				}
+				http_probe {
+					relative_url: "/health"
+					headers {
+						name: "X-Source"
+						value: "cloudprober"
+					}
+				}
+			}
+			instance {
+				name: "acme"
+				targets {
+					host_names: "acme.example.com"
+				}
+				additional_label {
+					key: "customer"
+					value: "acme"
+				}
+				http_header {
+					name: "Authorization"
+					value: "Bearer acme-token"
+				}
+			}
+			instance {
+				name: "globex"
+				oauth_config {
+					bearer_token {
+						file: "/etc/globex/token"
+					}
+				}
+			}
+		}`
+
+	cfg, _, err := ParseConfig(context.Background(), tmpl, "textpb", nil, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Empty(t, cfg.GetProbeTemplate())
+
+	probes := cfg.GetProbe()
+	if !assert.Len(t, probes, 3) {
+		return
+	}
+	assert.Equal(t, "regular", probes[0].GetName())
+
+	acme, globex := probes[1], probes[2]
+	assert.Equal(t, "api-acme", acme.GetName())
+	assert.Equal(t, "acme.example.com", acme.GetTargets().GetHostNames())
+	var labels []string
+	for _, al := range acme.GetAdditionalLabel() {
+		labels = append(labels, al.GetKey()+"="+al.GetValue())
+	}
+	assert.Equal(t, []string{"team=sre", "customer=acme"}, labels)
+	var headers []string
+	for _, h := range acme.GetHttpProbe().GetHeaders() {
+		headers = append(headers, h.GetName()+"="+h.GetValue())
+	}
+	assert.Equal(t, []string{"X-Source=cloudprober", "Authorization=Bearer acme-token"}, headers)
+	assert.Equal(t, "/health", acme.GetHttpProbe().GetRelativeUrl())
+
+	assert.Equal(t, "api-globex", globex.GetName())
+	assert.Equal(t, "default.example.com", globex.GetTargets().GetHostNames())
+	assert.Len(t, globex.GetAdditionalLabel(), 1)
+	assert.Len(t, globex.GetHttpProbe().GetHeaders(), 1, "template headers modified")
+	assert.Equal(t, "/etc/globex/token", globex.GetHttpProbe().GetOauthConfig().GetBearerToken().GetFile())
+	assert.Nil(t, acme.GetHttpProbe().GetOauthConfig())
+}
+
+func TestExpandProbeTemplatesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "duplicate_instance",
+			config: `
+				probe_template {
+					probe {
+						name: "api"
+						type: HTTP
+					}
+					instance {
+						name: "acme"
+					}
+					instance {
+						name: "acme"
+					}
+				}`,
+			wantErr: "probe_template api: instance acme is defined twice",
+		},
+		{
+			name: "http_header_for_non_http_probe",
+			config: `
+				probe_template {
+					probe {
+						name: "ping"
+						type: PING
+					}
+					instance {
+						name: "acme"
+						http_header {
+							name: "Authorization"
+							value: "Bearer acme-token"
+						}
+					}
+				}`,
+			wantErr: "supported only for HTTP probes",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := ParseConfig(context.Background(), test.config, "textpb", nil, nil)
+			assert.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
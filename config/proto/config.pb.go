@@ -7,12 +7,16 @@
 package proto
 
 import (
-	proto3 "github.com/cloudprober/cloudprober/internal/rds/server/proto"
+	proto3 "github.com/cloudprober/cloudprober/internal/alerting/proto"
+	proto9 "github.com/cloudprober/cloudprober/internal/oauth/proto"
+	proto4 "github.com/cloudprober/cloudprober/internal/rds/server/proto"
 	proto2 "github.com/cloudprober/cloudprober/internal/servers/proto"
-	proto4 "github.com/cloudprober/cloudprober/internal/tlsconfig/proto"
+	proto5 "github.com/cloudprober/cloudprober/internal/tlsconfig/proto"
+	proto7 "github.com/cloudprober/cloudprober/internal/validators/proto"
+	proto8 "github.com/cloudprober/cloudprober/probes/http/proto"
 	proto "github.com/cloudprober/cloudprober/probes/proto"
 	proto1 "github.com/cloudprober/cloudprober/surfacers/proto"
-	proto5 "github.com/cloudprober/cloudprober/targets/proto"
+	proto6 "github.com/cloudprober/cloudprober/targets/proto"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -82,8 +86,94 @@ type ProberConfig struct {
 	//	  }
 	//	}
 	SharedTargets []*SharedTargets `protobuf:"bytes,4,rep,name=shared_targets,json=sharedTargets" json:"shared_targets,omitempty"`
+	// Validator sets allow you to re-use the same validators across multiple
+	// probes. Probes refer to validator sets by name, and can combine them with
+	// their own validators. Validator sets are resolved while parsing the
+	// config.
+	// Example:
+	//
+	//	validator_set {
+	//	  name: "api-checks"
+	//	  validator {
+	//	    name: "status-2xx"
+	//	    http_validator {
+	//	      success_status_codes: "200-299"
+	//	    }
+	//	  }
+	//	}
+	//
+	//	probe {
+	//	  name: "api-http"
+	//	  type: HTTP
+	//	  validator_set: "api-checks"
+	//	  validator {
+	//	    name: "has-version"
+	//	    regex: "version"
+	//	  }
+	//	}
+	ValidatorSet []*ValidatorSet `protobuf:"bytes,5,rep,name=validator_set,json=validatorSet" json:"validator_set,omitempty"`
+	// Probe templates are expanded into one probe per instance while parsing
+	// the config, e.g. to run the same probe for a lot of customers, each with
+	// their own endpoint, labels and credentials. Generated probes are named
+	// <probe name>-<instance name>. See ProbeTemplate below for details.
+	// Example:
+	//
+	//	probe_template {
+	//	  probe {
+	//	    name: "api"
+	//	    type: HTTP
+	//	    http_probe {
+	//	      relative_url: "/health"
+	//	    }
+	//	  }
+	//	  instance {
+	//	    name: "acme"
+	//	    targets {
+	//	      host_names: "acme.api.example.com"
+	//	    }
+	//	    additional_label {
+	//	      key: "customer"
+	//	      value: "acme"
+	//	    }
+	//	    http_header {
+	//	      name: "Authorization"
+	//	      value: "Bearer {{envSecret "ACME_TOKEN"}}"
+	//	    }
+	//	  }
+	//	  instance {
+	//	    name: "globex"
+	//	    ...
+	//	  }
+	//	}
+	ProbeTemplate []*ProbeTemplate `protobuf:"bytes,6,rep,name=probe_template,json=probeTemplate" json:"probe_template,omitempty"`
+	// Environment specific overrides. An override's config is merged over the
+	// base config if its selector matches the runtime variables (see the
+	// sysvars package for the available variables), e.g.:
+	//
+	//	override {
+	//	  selector {
+	//	    key: "region"
+	//	    value: "us-east-1"
+	//	  }
+	//	  config {
+	//	    probe {
+	//	      name: "us_east_1_only"
+	//	      ...
+	//	    }
+	//	  }
+	//	}
+	//
+	// Overrides are applied in order. Merging follows the protobuf merge
+	// semantics: singular fields are replaced and repeated fields (e.g. probes,
+	// surfacers) are appended to.
+	Override []*ConfigOverride `protobuf:"bytes,7,rep,name=override" json:"override,omitempty"`
+	// Maintenance windows, during which alert notifications are suppressed for
+	// the matching probes and targets. Probes keep running and exporting their
+	// metrics, and the targets in the scope of a window get an additional
+	// "in_maintenance" metric, set to 1 while the window is active.
+	MaintenanceWindow []*proto3.MaintenanceWindow `protobuf:"bytes,8,rep,name=maintenance_window,json=maintenanceWindow" json:"maintenance_window,omitempty"`
 	// Resource discovery server
-	RdsServer *proto3.ServerConf `protobuf:"bytes,95,opt,name=rds_server,json=rdsServer" json:"rds_server,omitempty"`
+	RdsServer *proto4.ServerConf `protobuf:"bytes,95,opt,name=rds_server,json=rdsServer" json:"rds_server,omitempty"`
 	// Port for the default HTTP server. This port is also used for prometheus
 	// exporter (URL /metrics). Default port is 9313. If not specified in the
 	// config, default port can be overridden by the environment variable
@@ -105,7 +195,26 @@ type ProberConfig struct {
 	//     tls_cert_file: "..."
 	//     tls_key_file: "..."
 	//     }
-	GrpcTlsConfig *proto4.TLSConfig `protobuf:"bytes,105,opt,name=grpc_tls_config,json=grpcTlsConfig" json:"grpc_tls_config,omitempty"`
+	GrpcTlsConfig *proto5.TLSConfig `protobuf:"bytes,105,opt,name=grpc_tls_config,json=grpcTlsConfig" json:"grpc_tls_config,omitempty"`
+	// If enabled, methods of the Cloudprober gRPC service (see
+	// prober/proto/service.proto), e.g. ListProbes and PauseProbe, are also
+	// available as JSON over HTTP on the default HTTP server, at the
+	// /admin/v1/<method> URLs. Requests are POST requests with the JSON
+	// request message as the body, e.g.:
+	//
+	//	curl -d '{"probe_name": "web"}' localhost:9313/admin/v1/PauseProbe
+	//
+	// It requires the default gRPC server (see grpc_port). Note that these
+	// URLs are not authenticated, so only the read-only methods and the
+	// RunProbe method are available by default (see
+	// admin_http_api_allow_add_remove).
+	EnableAdminHttpApi *bool `protobuf:"varint,111,opt,name=enable_admin_http_api,json=enableAdminHttpApi" json:"enable_admin_http_api,omitempty"`
+	// If enabled, AddProbe, RemoveProbe, PauseProbe and ResumeProbe methods are
+	// also available over the admin HTTP API (see enable_admin_http_api). As the HTTP API is not
+	// authenticated, enable it only if the default HTTP server is not
+	// reachable by untrusted clients: AddProbe can add the probes that run
+	// arbitrary commands, e.g. external probes.
+	AdminHttpApiAllowAddRemove *bool `protobuf:"varint,112,opt,name=admin_http_api_allow_add_remove,json=adminHttpApiAllowAddRemove" json:"admin_http_api_allow_add_remove,omitempty"`
 	// Host for the default HTTP server. Default listens on all addresses. If not
 	// specified in the config, default port can be overridden by the environment
 	// variable CLOUDPROBER_HOST.
@@ -123,14 +232,48 @@ type ProberConfig struct {
 	// tools/cloudprober_startup.sh in the cloudprober directory for an example on
 	// how to use these variables.
 	SysvarsEnvVar *string `protobuf:"bytes,98,opt,name=sysvars_env_var,json=sysvarsEnvVar,def=SYSVARS" json:"sysvars_env_var,omitempty"`
-	// Time between triggering cancelation of various goroutines and exiting the
-	// process. If --stop_time flag is also configured, that gets priority.
-	// You may want to set it to 0 if cloudprober is running as a backend for
-	// the probes and you don't want time lost in stop and start.
+	// If enabled, an info-style metric, probe_info, is exported for each probe
+	// at the sysvars_interval_msec interval. This metric always has the value 1
+	// and carries probe's details (type, interval, timeout, number of targets,
+	// and config version) as labels. It's useful for joining with probe
+	// metrics in PromQL, for example:
+	//
+	//	total * on(probe) group_left(interval) probe_info
+	ExportProbeInfo *bool `protobuf:"varint,106,opt,name=export_probe_info,json=exportProbeInfo" json:"export_probe_info,omitempty"`
+	// Global rate limit on new outbound connections, shared by all probes'
+	// dialers (TCP, HTTP and gRPC probes). It smooths out connection bursts,
+	// e.g. when a lot of probes start at the same time after a config reload.
+	// Current wait time for new connections is exported as the
+	// conn_limiter_wait_msec metric, at the sysvars_interval_msec interval.
+	ConnRateLimit *ConnRateLimit `protobuf:"bytes,107,opt,name=conn_rate_limit,json=connRateLimit" json:"conn_rate_limit,omitempty"`
+	// Default prefix for the probes' metric names. It's used for the probes
+	// that don't specify their own metric_prefix. See metric_prefix in
+	// ProbeDef for more details.
+	MetricPrefix *string `protobuf:"bytes,108,opt,name=metric_prefix,json=metricPrefix" json:"metric_prefix,omitempty"`
+	// Additional labels for all the probes. These labels are added before the
+	// probes' own additional labels. If a probe defines a label with the same
+	// key, probe's label's merge_policy decides the label's value, e.g.
+	// global "tags" label can be combined with the probe's "tags" label using
+	// the CONCAT merge policy. See AdditionalLabel in probes config for
+	// details.
+	AdditionalLabel []*proto.AdditionalLabel `protobuf:"bytes,110,rep,name=additional_label,json=additionalLabel" json:"additional_label,omitempty"`
+	// If enabled, an aggregate availability metric, probe_availability_ratio,
+	// is exported for each probe at the probe's stats export interval. It's the
+	// ratio of the targets whose all probe runs in the last interval succeeded
+	// to the targets that reported results in that interval. The number of
+	// such targets is exported as probe_targets_reporting. If no targets
+	// reported in the interval, probe_availability_ratio is not exported (but
+	// probe_targets_reporting is, with the value 0).
+	ExportAvailabilityRatio *bool `protobuf:"varint,109,opt,name=export_availability_ratio,json=exportAvailabilityRatio" json:"export_availability_ratio,omitempty"`
+	// Maximum time between triggering cancelation of various goroutines and
+	// exiting the process. Cloudprober exits as soon as the surfacers have
+	// flushed the buffered metrics, or after this time, whichever comes first.
+	// If set to 0, cloudprober waits only for the surfacers' own flush timeouts.
+	// If --stop_time flag is also configured, that gets priority.
 	StopTimeSec *int32 `protobuf:"varint,99,opt,name=stop_time_sec,json=stopTimeSec,def=5" json:"stop_time_sec,omitempty"`
 	// Global targets options. Per-probe options are specified within the probe
 	// stanza.
-	GlobalTargetsOptions *proto5.GlobalTargetsOptions `protobuf:"bytes,100,opt,name=global_targets_options,json=globalTargetsOptions" json:"global_targets_options,omitempty"`
+	GlobalTargetsOptions *proto6.GlobalTargetsOptions `protobuf:"bytes,100,opt,name=global_targets_options,json=globalTargetsOptions" json:"global_targets_options,omitempty"`
 }
 
 // Default values for ProberConfig fields.
@@ -201,7 +344,35 @@ func (x *ProberConfig) GetSharedTargets() []*SharedTargets {
 	return nil
 }
 
-func (x *ProberConfig) GetRdsServer() *proto3.ServerConf {
+func (x *ProberConfig) GetValidatorSet() []*ValidatorSet {
+	if x != nil {
+		return x.ValidatorSet
+	}
+	return nil
+}
+
+func (x *ProberConfig) GetProbeTemplate() []*ProbeTemplate {
+	if x != nil {
+		return x.ProbeTemplate
+	}
+	return nil
+}
+
+func (x *ProberConfig) GetOverride() []*ConfigOverride {
+	if x != nil {
+		return x.Override
+	}
+	return nil
+}
+
+func (x *ProberConfig) GetMaintenanceWindow() []*proto3.MaintenanceWindow {
+	if x != nil {
+		return x.MaintenanceWindow
+	}
+	return nil
+}
+
+func (x *ProberConfig) GetRdsServer() *proto4.ServerConf {
 	if x != nil {
 		return x.RdsServer
 	}
@@ -222,13 +393,27 @@ func (x *ProberConfig) GetGrpcPort() int32 {
 	return 0
 }
 
-func (x *ProberConfig) GetGrpcTlsConfig() *proto4.TLSConfig {
+func (x *ProberConfig) GetGrpcTlsConfig() *proto5.TLSConfig {
 	if x != nil {
 		return x.GrpcTlsConfig
 	}
 	return nil
 }
 
+func (x *ProberConfig) GetEnableAdminHttpApi() bool {
+	if x != nil && x.EnableAdminHttpApi != nil {
+		return *x.EnableAdminHttpApi
+	}
+	return false
+}
+
+func (x *ProberConfig) GetAdminHttpApiAllowAddRemove() bool {
+	if x != nil && x.AdminHttpApiAllowAddRemove != nil {
+		return *x.AdminHttpApiAllowAddRemove
+	}
+	return false
+}
+
 func (x *ProberConfig) GetHost() string {
 	if x != nil && x.Host != nil {
 		return *x.Host
@@ -257,6 +442,41 @@ func (x *ProberConfig) GetSysvarsEnvVar() string {
 	return Default_ProberConfig_SysvarsEnvVar
 }
 
+func (x *ProberConfig) GetExportProbeInfo() bool {
+	if x != nil && x.ExportProbeInfo != nil {
+		return *x.ExportProbeInfo
+	}
+	return false
+}
+
+func (x *ProberConfig) GetConnRateLimit() *ConnRateLimit {
+	if x != nil {
+		return x.ConnRateLimit
+	}
+	return nil
+}
+
+func (x *ProberConfig) GetMetricPrefix() string {
+	if x != nil && x.MetricPrefix != nil {
+		return *x.MetricPrefix
+	}
+	return ""
+}
+
+func (x *ProberConfig) GetAdditionalLabel() []*proto.AdditionalLabel {
+	if x != nil {
+		return x.AdditionalLabel
+	}
+	return nil
+}
+
+func (x *ProberConfig) GetExportAvailabilityRatio() bool {
+	if x != nil && x.ExportAvailabilityRatio != nil {
+		return *x.ExportAvailabilityRatio
+	}
+	return false
+}
+
 func (x *ProberConfig) GetStopTimeSec() int32 {
 	if x != nil && x.StopTimeSec != nil {
 		return *x.StopTimeSec
@@ -264,26 +484,149 @@ func (x *ProberConfig) GetStopTimeSec() int32 {
 	return Default_ProberConfig_StopTimeSec
 }
 
-func (x *ProberConfig) GetGlobalTargetsOptions() *proto5.GlobalTargetsOptions {
+func (x *ProberConfig) GetGlobalTargetsOptions() *proto6.GlobalTargetsOptions {
 	if x != nil {
 		return x.GlobalTargetsOptions
 	}
 	return nil
 }
 
+type ConfigOverride struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Runtime variables that should match for the override to apply, e.g.
+	// cloud_provider: "gce", region: "us-central1". All variables should
+	// match. Override with an empty selector always applies.
+	Selector map[string]string `protobuf:"bytes,1,rep,name=selector" json:"selector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Config to merge over the base config. It cannot contain overrides
+	// itself.
+	Config *ProberConfig `protobuf:"bytes,2,opt,name=config" json:"config,omitempty"`
+}
+
+func (x *ConfigOverride) Reset() {
+	*x = ConfigOverride{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfigOverride) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigOverride) ProtoMessage() {}
+
+func (x *ConfigOverride) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigOverride.ProtoReflect.Descriptor instead.
+func (*ConfigOverride) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_config_proto_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ConfigOverride) GetSelector() map[string]string {
+	if x != nil {
+		return x.Selector
+	}
+	return nil
+}
+
+func (x *ConfigOverride) GetConfig() *ProberConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type ConnRateLimit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// New connections per second.
+	Rate *float32 `protobuf:"fixed32,1,req,name=rate" json:"rate,omitempty"`
+	// Maximum number of connections that can be made at once, before the rate
+	// limit kicks in.
+	Burst *int32 `protobuf:"varint,2,opt,name=burst,def=1" json:"burst,omitempty"`
+}
+
+// Default values for ConnRateLimit fields.
+const (
+	Default_ConnRateLimit_Burst = int32(1)
+)
+
+func (x *ConnRateLimit) Reset() {
+	*x = ConnRateLimit{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConnRateLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnRateLimit) ProtoMessage() {}
+
+func (x *ConnRateLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnRateLimit.ProtoReflect.Descriptor instead.
+func (*ConnRateLimit) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_config_proto_config_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ConnRateLimit) GetRate() float32 {
+	if x != nil && x.Rate != nil {
+		return *x.Rate
+	}
+	return 0
+}
+
+func (x *ConnRateLimit) GetBurst() int32 {
+	if x != nil && x.Burst != nil {
+		return *x.Burst
+	}
+	return Default_ConnRateLimit_Burst
+}
+
 type SharedTargets struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Name    *string            `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
-	Targets *proto5.TargetsDef `protobuf:"bytes,2,req,name=targets" json:"targets,omitempty"`
+	Targets *proto6.TargetsDef `protobuf:"bytes,2,req,name=targets" json:"targets,omitempty"`
 }
 
 func (x *SharedTargets) Reset() {
 	*x = SharedTargets{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[1]
+		mi := &file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -296,7 +639,7 @@ func (x *SharedTargets) String() string {
 func (*SharedTargets) ProtoMessage() {}
 
 func (x *SharedTargets) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[1]
+	mi := &file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -309,7 +652,7 @@ func (x *SharedTargets) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SharedTargets.ProtoReflect.Descriptor instead.
 func (*SharedTargets) Descriptor() ([]byte, []int) {
-	return file_github_com_cloudprober_cloudprober_config_proto_config_proto_rawDescGZIP(), []int{1}
+	return file_github_com_cloudprober_cloudprober_config_proto_config_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *SharedTargets) GetName() string {
@@ -319,13 +662,214 @@ func (x *SharedTargets) GetName() string {
 	return ""
 }
 
-func (x *SharedTargets) GetTargets() *proto5.TargetsDef {
+func (x *SharedTargets) GetTargets() *proto6.TargetsDef {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+type ValidatorSet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      *string             `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	Validator []*proto7.Validator `protobuf:"bytes,2,rep,name=validator" json:"validator,omitempty"`
+}
+
+func (x *ValidatorSet) Reset() {
+	*x = ValidatorSet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValidatorSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidatorSet) ProtoMessage() {}
+
+func (x *ValidatorSet) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidatorSet.ProtoReflect.Descriptor instead.
+func (*ValidatorSet) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_config_proto_config_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ValidatorSet) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *ValidatorSet) GetValidator() []*proto7.Validator {
+	if x != nil {
+		return x.Validator
+	}
+	return nil
+}
+
+type ProbeTemplate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Probe config shared by all the instances. Instance's fields below are
+	// applied to a copy of it for each instance.
+	Probe    *proto.ProbeDef           `protobuf:"bytes,1,req,name=probe" json:"probe,omitempty"`
+	Instance []*ProbeTemplate_Instance `protobuf:"bytes,2,rep,name=instance" json:"instance,omitempty"`
+}
+
+func (x *ProbeTemplate) Reset() {
+	*x = ProbeTemplate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProbeTemplate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeTemplate) ProtoMessage() {}
+
+func (x *ProbeTemplate) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeTemplate.ProtoReflect.Descriptor instead.
+func (*ProbeTemplate) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_config_proto_config_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ProbeTemplate) GetProbe() *proto.ProbeDef {
+	if x != nil {
+		return x.Probe
+	}
+	return nil
+}
+
+func (x *ProbeTemplate) GetInstance() []*ProbeTemplate_Instance {
+	if x != nil {
+		return x.Instance
+	}
+	return nil
+}
+
+type ProbeTemplate_Instance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Instance name, appended to the probe's name to get the generated
+	// probe's name. It should be unique within the template.
+	Name *string `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	// Targets for the instance. If specified, these replace the template
+	// probe's targets.
+	Targets *proto6.TargetsDef `protobuf:"bytes,2,opt,name=targets" json:"targets,omitempty"`
+	// Additional labels for the instance, added after the template probe's
+	// additional labels.
+	AdditionalLabel []*proto.AdditionalLabel `protobuf:"bytes,3,rep,name=additional_label,json=additionalLabel" json:"additional_label,omitempty"`
+	// HTTP headers for the instance, e.g. for authorization, added after the
+	// template probe's headers. Only for HTTP probes.
+	HttpHeader []*proto8.ProbeConf_Header `protobuf:"bytes,4,rep,name=http_header,json=httpHeader" json:"http_header,omitempty"`
+	// OAuth config for the instance. If specified, it replaces the template
+	// probe's OAuth config. Only for HTTP probes.
+	OauthConfig *proto9.Config `protobuf:"bytes,5,opt,name=oauth_config,json=oauthConfig" json:"oauth_config,omitempty"`
+}
+
+func (x *ProbeTemplate_Instance) Reset() {
+	*x = ProbeTemplate_Instance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProbeTemplate_Instance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeTemplate_Instance) ProtoMessage() {}
+
+func (x *ProbeTemplate_Instance) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeTemplate_Instance.ProtoReflect.Descriptor instead.
+func (*ProbeTemplate_Instance) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_config_proto_config_proto_rawDescGZIP(), []int{5, 0}
+}
+
+func (x *ProbeTemplate_Instance) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *ProbeTemplate_Instance) GetTargets() *proto6.TargetsDef {
 	if x != nil {
 		return x.Targets
 	}
 	return nil
 }
 
+func (x *ProbeTemplate_Instance) GetAdditionalLabel() []*proto.AdditionalLabel {
+	if x != nil {
+		return x.AdditionalLabel
+	}
+	return nil
+}
+
+func (x *ProbeTemplate_Instance) GetHttpHeader() []*proto8.ProbeConf_Header {
+	if x != nil {
+		return x.HttpHeader
+	}
+	return nil
+}
+
+func (x *ProbeTemplate_Instance) GetOauthConfig() *proto9.Config {
+	if x != nil {
+		return x.OauthConfig
+	}
+	return nil
+}
+
 var File_github_com_cloudprober_cloudprober_config_proto_config_proto protoreflect.FileDescriptor
 
 var file_github_com_cloudprober_cloudprober_config_proto_config_proto_rawDesc = []byte{
@@ -333,89 +877,200 @@ var file_github_com_cloudprober_cloudprober_config_proto_config_proto_rawDesc =
 	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
 	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b,
-	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x1a, 0x48, 0x67, 0x69, 0x74,
+	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x1a, 0x47, 0x67, 0x69, 0x74,
 	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f,
 	0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f,
-	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x74, 0x6c, 0x73, 0x63, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x3c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c,
-	0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x69, 0x6e,
+	0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x44, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x2f, 0x6f, 0x61, 0x75, 0x74, 0x68, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x48, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62,
+	0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x74, 0x6c, 0x73, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x49, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x41, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75,
+	0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f,
+	0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x68, 0x74, 0x74, 0x70, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x3c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x49, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x72, 0x64,
+	0x73, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x46, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f,
+	0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73,
 	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x1a, 0x49, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x6f, 0x74, 0x6f, 0x1a, 0x3f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
 	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75,
-	0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
-	0x2f, 0x72, 0x64, 0x73, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x46,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64,
-	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62,
-	0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x73, 0x65, 0x72, 0x76,
-	0x65, 0x72, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x3f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
-	0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63,
-	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x73, 0x75, 0x72, 0x66, 0x61,
-	0x63, 0x65, 0x72, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f,
-	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x74, 0x61, 0x72, 0x67,
-	0x65, 0x74, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
-	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xe9, 0x05, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x62,
-	0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x32, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x62,
-	0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70,
-	0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x50, 0x72, 0x6f,
-	0x62, 0x65, 0x44, 0x65, 0x66, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x3d, 0x0a, 0x08,
-	0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21,
-	0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x73, 0x75, 0x72,
-	0x66, 0x61, 0x63, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x44, 0x65,
-	0x66, 0x52, 0x08, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x12, 0x36, 0x0a, 0x06, 0x73,
-	0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x63, 0x6c,
-	0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
-	0x73, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x44, 0x65, 0x66, 0x52, 0x06, 0x73, 0x65, 0x72,
-	0x76, 0x65, 0x72, 0x12, 0x41, 0x0a, 0x0e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x5f, 0x74, 0x61,
-	0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x6c,
-	0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x53, 0x68, 0x61, 0x72, 0x65, 0x64,
-	0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x52, 0x0d, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x54,
-	0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x3a, 0x0a, 0x0a, 0x72, 0x64, 0x73, 0x5f, 0x73, 0x65,
-	0x72, 0x76, 0x65, 0x72, 0x18, 0x5f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63, 0x6c, 0x6f,
-	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x72, 0x64, 0x73, 0x2e, 0x53, 0x65, 0x72,
-	0x76, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x52, 0x09, 0x72, 0x64, 0x73, 0x53, 0x65, 0x72, 0x76,
-	0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x60, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70,
-	0x6f, 0x72, 0x74, 0x18, 0x68, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x67, 0x72, 0x70, 0x63, 0x50,
-	0x6f, 0x72, 0x74, 0x12, 0x48, 0x0a, 0x0f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x74, 0x6c, 0x73, 0x5f,
-	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x69, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x63,
-	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x74, 0x6c, 0x73, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x54, 0x4c, 0x53, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0d,
-	0x67, 0x72, 0x70, 0x63, 0x54, 0x6c, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x12, 0x0a,
-	0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x65, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73,
-	0x74, 0x12, 0x2c, 0x0a, 0x0e, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6a, 0x69, 0x74,
-	0x74, 0x65, 0x72, 0x18, 0x66, 0x20, 0x01, 0x28, 0x08, 0x3a, 0x05, 0x66, 0x61, 0x6c, 0x73, 0x65,
-	0x52, 0x0d, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x4a, 0x69, 0x74, 0x74, 0x65, 0x72, 0x12,
-	0x39, 0x0a, 0x15, 0x73, 0x79, 0x73, 0x76, 0x61, 0x72, 0x73, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72,
-	0x76, 0x61, 0x6c, 0x5f, 0x6d, 0x73, 0x65, 0x63, 0x18, 0x61, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x05,
-	0x31, 0x30, 0x30, 0x30, 0x30, 0x52, 0x13, 0x73, 0x79, 0x73, 0x76, 0x61, 0x72, 0x73, 0x49, 0x6e,
-	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x65, 0x63, 0x12, 0x2f, 0x0a, 0x0f, 0x73, 0x79,
-	0x73, 0x76, 0x61, 0x72, 0x73, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x76, 0x61, 0x72, 0x18, 0x62, 0x20,
-	0x01, 0x28, 0x09, 0x3a, 0x07, 0x53, 0x59, 0x53, 0x56, 0x41, 0x52, 0x53, 0x52, 0x0d, 0x73, 0x79,
-	0x73, 0x76, 0x61, 0x72, 0x73, 0x45, 0x6e, 0x76, 0x56, 0x61, 0x72, 0x12, 0x25, 0x0a, 0x0d, 0x73,
-	0x74, 0x6f, 0x70, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x63, 0x20, 0x01,
-	0x28, 0x05, 0x3a, 0x01, 0x35, 0x52, 0x0b, 0x73, 0x74, 0x6f, 0x70, 0x54, 0x69, 0x6d, 0x65, 0x53,
-	0x65, 0x63, 0x12, 0x5f, 0x0a, 0x16, 0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x5f, 0x74, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x73, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x64, 0x20, 0x01,
+	0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72,
+	0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x96, 0x0b, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x32, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62,
+	0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44,
+	0x65, 0x66, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x3d, 0x0a, 0x08, 0x73, 0x75, 0x72,
+	0x66, 0x61, 0x63, 0x65, 0x72, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x63, 0x6c,
+	0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63,
+	0x65, 0x72, 0x2e, 0x53, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x44, 0x65, 0x66, 0x52, 0x08,
+	0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x12, 0x36, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x44, 0x65, 0x66, 0x52, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x12, 0x41, 0x0a, 0x0e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x53, 0x68, 0x61, 0x72, 0x65, 0x64, 0x54, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x73, 0x52, 0x0d, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x54, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x73, 0x12, 0x3e, 0x0a, 0x0d, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72,
+	0x5f, 0x73, 0x65, 0x74, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x6f, 0x72, 0x53, 0x65, 0x74, 0x52, 0x0c, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72,
+	0x53, 0x65, 0x74, 0x12, 0x41, 0x0a, 0x0e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x5f, 0x74, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x6c,
+	0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x54,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x0d, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x54, 0x65,
+	0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x37, 0x0a, 0x08, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69,
+	0x64, 0x65, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4f, 0x76, 0x65,
+	0x72, 0x72, 0x69, 0x64, 0x65, 0x52, 0x08, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x12,
+	0x56, 0x0a, 0x12, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x77,
+	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x63, 0x6c,
+	0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x69,
+	0x6e, 0x67, 0x2e, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x57, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x52, 0x11, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x3a, 0x0a, 0x0a, 0x72, 0x64, 0x73, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x5f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63, 0x6c,
+	0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x72, 0x64, 0x73, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x52, 0x09, 0x72, 0x64, 0x73, 0x53, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x60, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x72, 0x70, 0x63, 0x5f,
+	0x70, 0x6f, 0x72, 0x74, 0x18, 0x68, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x67, 0x72, 0x70, 0x63,
+	0x50, 0x6f, 0x72, 0x74, 0x12, 0x48, 0x0a, 0x0f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x74, 0x6c, 0x73,
+	0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x69, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e,
+	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x74, 0x6c, 0x73, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x54, 0x4c, 0x53, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
+	0x0d, 0x67, 0x72, 0x70, 0x63, 0x54, 0x6c, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x31,
+	0x0a, 0x15, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x5f, 0x68,
+	0x74, 0x74, 0x70, 0x5f, 0x61, 0x70, 0x69, 0x18, 0x6f, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x65,
+	0x6e, 0x61, 0x62, 0x6c, 0x65, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x48, 0x74, 0x74, 0x70, 0x41, 0x70,
+	0x69, 0x12, 0x43, 0x0a, 0x1f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x5f, 0x68, 0x74, 0x74, 0x70, 0x5f,
+	0x61, 0x70, 0x69, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x61, 0x64, 0x64, 0x5f, 0x72, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x18, 0x70, 0x20, 0x01, 0x28, 0x08, 0x52, 0x1a, 0x61, 0x64, 0x6d, 0x69,
+	0x6e, 0x48, 0x74, 0x74, 0x70, 0x41, 0x70, 0x69, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x41, 0x64, 0x64,
+	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x65,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x0e, 0x64, 0x69,
+	0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6a, 0x69, 0x74, 0x74, 0x65, 0x72, 0x18, 0x66, 0x20, 0x01,
+	0x28, 0x08, 0x3a, 0x05, 0x66, 0x61, 0x6c, 0x73, 0x65, 0x52, 0x0d, 0x64, 0x69, 0x73, 0x61, 0x62,
+	0x6c, 0x65, 0x4a, 0x69, 0x74, 0x74, 0x65, 0x72, 0x12, 0x39, 0x0a, 0x15, 0x73, 0x79, 0x73, 0x76,
+	0x61, 0x72, 0x73, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x6d, 0x73, 0x65,
+	0x63, 0x18, 0x61, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x05, 0x31, 0x30, 0x30, 0x30, 0x30, 0x52, 0x13,
+	0x73, 0x79, 0x73, 0x76, 0x61, 0x72, 0x73, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d,
+	0x73, 0x65, 0x63, 0x12, 0x2f, 0x0a, 0x0f, 0x73, 0x79, 0x73, 0x76, 0x61, 0x72, 0x73, 0x5f, 0x65,
+	0x6e, 0x76, 0x5f, 0x76, 0x61, 0x72, 0x18, 0x62, 0x20, 0x01, 0x28, 0x09, 0x3a, 0x07, 0x53, 0x59,
+	0x53, 0x56, 0x41, 0x52, 0x53, 0x52, 0x0d, 0x73, 0x79, 0x73, 0x76, 0x61, 0x72, 0x73, 0x45, 0x6e,
+	0x76, 0x56, 0x61, 0x72, 0x12, 0x2a, 0x0a, 0x11, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x70,
+	0x72, 0x6f, 0x62, 0x65, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x6a, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0f, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x49, 0x6e, 0x66, 0x6f,
+	0x12, 0x42, 0x0a, 0x0f, 0x63, 0x6f, 0x6e, 0x6e, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x18, 0x6b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x6c, 0x6f, 0x75,
+	0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x52, 0x61, 0x74, 0x65,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x6e, 0x52, 0x61, 0x74, 0x65, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x5f, 0x70,
+	0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x6c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x4e, 0x0a, 0x10, 0x61, 0x64, 0x64,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x6e, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65,
+	0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x61, 0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x0f, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x61, 0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x3a, 0x0a, 0x19, 0x65, 0x78, 0x70,
+	0x6f, 0x72, 0x74, 0x5f, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x5f, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x6d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x17, 0x65, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x52, 0x61, 0x74, 0x69, 0x6f, 0x12, 0x25, 0x0a, 0x0d, 0x73, 0x74, 0x6f, 0x70, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x63, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x01, 0x35, 0x52,
+	0x0b, 0x73, 0x74, 0x6f, 0x70, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x65, 0x63, 0x12, 0x5f, 0x0a, 0x16,
+	0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x5f, 0x6f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x64, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x73, 0x2e, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x14, 0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xc7, 0x01,
+	0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65,
+	0x12, 0x45, 0x0a, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x29, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
+	0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x2e,
+	0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x73,
+	0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x31, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70,
+	0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x1a, 0x3b, 0x0a, 0x0d, 0x53, 0x65,
+	0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x3c, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x6e, 0x52,
+	0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x02, 0x28, 0x02, 0x52, 0x04, 0x72, 0x61, 0x74, 0x65, 0x12, 0x17, 0x0a, 0x05,
+	0x62, 0x75, 0x72, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x01, 0x31, 0x52, 0x05,
+	0x62, 0x75, 0x72, 0x73, 0x74, 0x22, 0x5e, 0x0a, 0x0d, 0x53, 0x68, 0x61, 0x72, 0x65, 0x64, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x02, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x39, 0x0a, 0x07, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x02, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x63, 0x6c,
+	0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x44, 0x65, 0x66, 0x52, 0x07, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x73, 0x22, 0x63, 0x0a, 0x0c, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x6f, 0x72, 0x53, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x02, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x3f, 0x0a, 0x09, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x6f, 0x72, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x52,
+	0x09, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x22, 0xba, 0x03, 0x0a, 0x0d, 0x50,
+	0x72, 0x6f, 0x62, 0x65, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x32, 0x0a, 0x05,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x01, 0x20, 0x02, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x6c,
+	0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73,
+	0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44, 0x65, 0x66, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x62, 0x65,
+	0x12, 0x3f, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x23, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
+	0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x2e, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x1a, 0xb3, 0x02, 0x0a, 0x08, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x39, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65,
+	0x72, 0x2e, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x73, 0x44, 0x65, 0x66, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x4e, 0x0a,
+	0x10, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x5f, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70,
+	0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x41, 0x64, 0x64,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x0f, 0x61, 0x64,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x4a, 0x0a,
+	0x0b, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x04, 0x20, 0x03,
 	0x28, 0x0b, 0x32, 0x29, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
-	0x2e, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x2e, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x54,
-	0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x14, 0x67,
-	0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x4f, 0x70, 0x74, 0x69,
-	0x6f, 0x6e, 0x73, 0x22, 0x5e, 0x0a, 0x0d, 0x53, 0x68, 0x61, 0x72, 0x65, 0x64, 0x54, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x02,
-	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x39, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67,
-	0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x02, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x63, 0x6c, 0x6f, 0x75,
-	0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x2e,
-	0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x44, 0x65, 0x66, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67,
-	0x65, 0x74, 0x73, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c,
-	0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x68, 0x74, 0x74, 0x70, 0x2e, 0x50, 0x72, 0x6f,
+	0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x0a, 0x68,
+	0x74, 0x74, 0x70, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x3c, 0x0a, 0x0c, 0x6f, 0x61, 0x75,
+	0x74, 0x68, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x6f, 0x61,
+	0x75, 0x74, 0x68, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0b, 0x6f, 0x61, 0x75, 0x74,
+	0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65,
+	0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 }
 
 var (
@@ -430,32 +1085,58 @@ func file_github_com_cloudprober_cloudprober_config_proto_config_proto_rawDescGZ
 	return file_github_com_cloudprober_cloudprober_config_proto_config_proto_rawDescData
 }
 
-var file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_github_com_cloudprober_cloudprober_config_proto_config_proto_goTypes = []interface{}{
 	(*ProberConfig)(nil),                // 0: cloudprober.ProberConfig
-	(*SharedTargets)(nil),               // 1: cloudprober.SharedTargets
-	(*proto.ProbeDef)(nil),              // 2: cloudprober.probes.ProbeDef
-	(*proto1.SurfacerDef)(nil),          // 3: cloudprober.surfacer.SurfacerDef
-	(*proto2.ServerDef)(nil),            // 4: cloudprober.servers.ServerDef
-	(*proto3.ServerConf)(nil),           // 5: cloudprober.rds.ServerConf
-	(*proto4.TLSConfig)(nil),            // 6: cloudprober.tlsconfig.TLSConfig
-	(*proto5.GlobalTargetsOptions)(nil), // 7: cloudprober.targets.GlobalTargetsOptions
-	(*proto5.TargetsDef)(nil),           // 8: cloudprober.targets.TargetsDef
+	(*ConfigOverride)(nil),              // 1: cloudprober.ConfigOverride
+	(*ConnRateLimit)(nil),               // 2: cloudprober.ConnRateLimit
+	(*SharedTargets)(nil),               // 3: cloudprober.SharedTargets
+	(*ValidatorSet)(nil),                // 4: cloudprober.ValidatorSet
+	(*ProbeTemplate)(nil),               // 5: cloudprober.ProbeTemplate
+	nil,                                 // 6: cloudprober.ConfigOverride.SelectorEntry
+	(*ProbeTemplate_Instance)(nil),      // 7: cloudprober.ProbeTemplate.Instance
+	(*proto.ProbeDef)(nil),              // 8: cloudprober.probes.ProbeDef
+	(*proto1.SurfacerDef)(nil),          // 9: cloudprober.surfacer.SurfacerDef
+	(*proto2.ServerDef)(nil),            // 10: cloudprober.servers.ServerDef
+	(*proto3.MaintenanceWindow)(nil),    // 11: cloudprober.alerting.MaintenanceWindow
+	(*proto4.ServerConf)(nil),           // 12: cloudprober.rds.ServerConf
+	(*proto5.TLSConfig)(nil),            // 13: cloudprober.tlsconfig.TLSConfig
+	(*proto.AdditionalLabel)(nil),       // 14: cloudprober.probes.AdditionalLabel
+	(*proto6.GlobalTargetsOptions)(nil), // 15: cloudprober.targets.GlobalTargetsOptions
+	(*proto6.TargetsDef)(nil),           // 16: cloudprober.targets.TargetsDef
+	(*proto7.Validator)(nil),            // 17: cloudprober.validators.Validator
+	(*proto8.ProbeConf_Header)(nil),     // 18: cloudprober.probes.http.ProbeConf.Header
+	(*proto9.Config)(nil),               // 19: cloudprober.oauth.Config
 }
 var file_github_com_cloudprober_cloudprober_config_proto_config_proto_depIdxs = []int32{
-	2, // 0: cloudprober.ProberConfig.probe:type_name -> cloudprober.probes.ProbeDef
-	3, // 1: cloudprober.ProberConfig.surfacer:type_name -> cloudprober.surfacer.SurfacerDef
-	4, // 2: cloudprober.ProberConfig.server:type_name -> cloudprober.servers.ServerDef
-	1, // 3: cloudprober.ProberConfig.shared_targets:type_name -> cloudprober.SharedTargets
-	5, // 4: cloudprober.ProberConfig.rds_server:type_name -> cloudprober.rds.ServerConf
-	6, // 5: cloudprober.ProberConfig.grpc_tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
-	7, // 6: cloudprober.ProberConfig.global_targets_options:type_name -> cloudprober.targets.GlobalTargetsOptions
-	8, // 7: cloudprober.SharedTargets.targets:type_name -> cloudprober.targets.TargetsDef
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	8,  // 0: cloudprober.ProberConfig.probe:type_name -> cloudprober.probes.ProbeDef
+	9,  // 1: cloudprober.ProberConfig.surfacer:type_name -> cloudprober.surfacer.SurfacerDef
+	10, // 2: cloudprober.ProberConfig.server:type_name -> cloudprober.servers.ServerDef
+	3,  // 3: cloudprober.ProberConfig.shared_targets:type_name -> cloudprober.SharedTargets
+	4,  // 4: cloudprober.ProberConfig.validator_set:type_name -> cloudprober.ValidatorSet
+	5,  // 5: cloudprober.ProberConfig.probe_template:type_name -> cloudprober.ProbeTemplate
+	1,  // 6: cloudprober.ProberConfig.override:type_name -> cloudprober.ConfigOverride
+	11, // 7: cloudprober.ProberConfig.maintenance_window:type_name -> cloudprober.alerting.MaintenanceWindow
+	12, // 8: cloudprober.ProberConfig.rds_server:type_name -> cloudprober.rds.ServerConf
+	13, // 9: cloudprober.ProberConfig.grpc_tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
+	2,  // 10: cloudprober.ProberConfig.conn_rate_limit:type_name -> cloudprober.ConnRateLimit
+	14, // 11: cloudprober.ProberConfig.additional_label:type_name -> cloudprober.probes.AdditionalLabel
+	15, // 12: cloudprober.ProberConfig.global_targets_options:type_name -> cloudprober.targets.GlobalTargetsOptions
+	6,  // 13: cloudprober.ConfigOverride.selector:type_name -> cloudprober.ConfigOverride.SelectorEntry
+	0,  // 14: cloudprober.ConfigOverride.config:type_name -> cloudprober.ProberConfig
+	16, // 15: cloudprober.SharedTargets.targets:type_name -> cloudprober.targets.TargetsDef
+	17, // 16: cloudprober.ValidatorSet.validator:type_name -> cloudprober.validators.Validator
+	8,  // 17: cloudprober.ProbeTemplate.probe:type_name -> cloudprober.probes.ProbeDef
+	7,  // 18: cloudprober.ProbeTemplate.instance:type_name -> cloudprober.ProbeTemplate.Instance
+	16, // 19: cloudprober.ProbeTemplate.Instance.targets:type_name -> cloudprober.targets.TargetsDef
+	14, // 20: cloudprober.ProbeTemplate.Instance.additional_label:type_name -> cloudprober.probes.AdditionalLabel
+	18, // 21: cloudprober.ProbeTemplate.Instance.http_header:type_name -> cloudprober.probes.http.ProbeConf.Header
+	19, // 22: cloudprober.ProbeTemplate.Instance.oauth_config:type_name -> cloudprober.oauth.Config
+	23, // [23:23] is the sub-list for method output_type
+	23, // [23:23] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_github_com_cloudprober_cloudprober_config_proto_config_proto_init() }
@@ -477,6 +1158,30 @@ func file_github_com_cloudprober_cloudprober_config_proto_config_proto_init() {
 			}
 		}
 		file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfigOverride); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConnRateLimit); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SharedTargets); i {
 			case 0:
 				return &v.state
@@ -488,6 +1193,42 @@ func file_github_com_cloudprober_cloudprober_config_proto_config_proto_init() {
 				return nil
 			}
 		}
+		file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ValidatorSet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProbeTemplate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cloudprober_cloudprober_config_proto_config_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProbeTemplate_Instance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -495,7 +1236,7 @@ func file_github_com_cloudprober_cloudprober_config_proto_config_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_github_com_cloudprober_cloudprober_config_proto_config_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
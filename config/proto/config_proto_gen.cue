@@ -4,9 +4,13 @@ import (
 	"github.com/cloudprober/cloudprober/probes/proto"
 	proto_1 "github.com/cloudprober/cloudprober/surfacers/proto"
 	proto_5 "github.com/cloudprober/cloudprober/internal/servers/proto"
-	proto_A "github.com/cloudprober/cloudprober/internal/rds/server/proto"
-	proto_8 "github.com/cloudprober/cloudprober/internal/tlsconfig/proto"
-	proto_E "github.com/cloudprober/cloudprober/targets/proto"
+	proto_A "github.com/cloudprober/cloudprober/internal/alerting/proto"
+	proto_8 "github.com/cloudprober/cloudprober/internal/rds/server/proto"
+	proto_E "github.com/cloudprober/cloudprober/internal/tlsconfig/proto"
+	proto_B "github.com/cloudprober/cloudprober/targets/proto"
+	proto_36 "github.com/cloudprober/cloudprober/internal/validators/proto"
+	proto_9 "github.com/cloudprober/cloudprober/probes/http/proto"
+	proto_3 "github.com/cloudprober/cloudprober/internal/oauth/proto"
 )
 
 // Cloudprober config proto defines the config schema. Cloudprober config can
@@ -62,11 +66,97 @@ import (
 	//   }
 	// }
 	sharedTargets?: [...#SharedTargets] @protobuf(4,SharedTargets,name=shared_targets)
+
+	// Validator sets allow you to re-use the same validators across multiple
+	// probes. Probes refer to validator sets by name, and can combine them with
+	// their own validators. Validator sets are resolved while parsing the
+	// config.
+	// Example:
+	// validator_set {
+	//   name: "api-checks"
+	//   validator {
+	//     name: "status-2xx"
+	//     http_validator {
+	//       success_status_codes: "200-299"
+	//     }
+	//   }
+	// }
+	//
+	// probe {
+	//   name: "api-http"
+	//   type: HTTP
+	//   validator_set: "api-checks"
+	//   validator {
+	//     name: "has-version"
+	//     regex: "version"
+	//   }
+	// }
+	validatorSet?: [...#ValidatorSet] @protobuf(5,ValidatorSet,name=validator_set)
+
+	// Probe templates are expanded into one probe per instance while parsing
+	// the config, e.g. to run the same probe for a lot of customers, each with
+	// their own endpoint, labels and credentials. Generated probes are named
+	// <probe name>-<instance name>. See ProbeTemplate below for details.
+	// Example:
+	// probe_template {
+	//   probe {
+	//     name: "api"
+	//     type: HTTP
+	//     http_probe {
+	//       relative_url: "/health"
+	//     }
+	//   }
+	//   instance {
+	//     name: "acme"
+	//     targets {
+	//       host_names: "acme.api.example.com"
+	//     }
+	//     additional_label {
+	//       key: "customer"
+	//       value: "acme"
+	//     }
+	//     http_header {
+	//       name: "Authorization"
+	//       value: "Bearer {{envSecret "ACME_TOKEN"}}"
+	//     }
+	//   }
+	//   instance {
+	//     name: "globex"
+	//     ...
+	//   }
+	// }
+	probeTemplate?: [...#ProbeTemplate] @protobuf(6,ProbeTemplate,name=probe_template)
+
+	// Environment specific overrides. An override's config is merged over the
+	// base config if its selector matches the runtime variables (see the
+	// sysvars package for the available variables), e.g.:
+	// override {
+	//   selector {
+	//     key: "region"
+	//     value: "us-east-1"
+	//   }
+	//   config {
+	//     probe {
+	//       name: "us_east_1_only"
+	//       ...
+	//     }
+	//   }
+	// }
+	// Overrides are applied in order. Merging follows the protobuf merge
+	// semantics: singular fields are replaced and repeated fields (e.g. probes,
+	// surfacers) are appended to.
+	override?: [...#ConfigOverride] @protobuf(7,ConfigOverride)
+
+	// Maintenance windows, during which alert notifications are suppressed for
+	// the matching probes and targets. Probes keep running and exporting their
+	// metrics, and the targets in the scope of a window get an additional
+	// "in_maintenance" metric, set to 1 while the window is active.
+	maintenanceWindow?: [...proto_A.#MaintenanceWindow] @protobuf(8,alerting.MaintenanceWindow,name=maintenance_window)
 	// Common services related options.
-	// Next tag: 106
+	// Next tag: 112
 
 	// Resource discovery server
-	rdsServer?: proto_A.#ServerConf @protobuf(95,rds.ServerConf,name=rds_server)
+	rdsServer?: proto_8.#ServerConf @protobuf(95,rds.ServerConf,name=rds_server)
 
 	// Port for the default HTTP server. This port is also used for prometheus
 	// exporter (URL /metrics). Default port is 9313. If not specified in the
@@ -91,7 +181,26 @@ import (
 	//       tls_cert_file: "..."
 	//       tls_key_file: "..."
 	//     }
-	grpcTlsConfig?: proto_8.#TLSConfig @protobuf(105,tlsconfig.TLSConfig,name=grpc_tls_config)
+	grpcTlsConfig?: proto_E.#TLSConfig @protobuf(105,tlsconfig.TLSConfig,name=grpc_tls_config)
+
+	// If enabled, methods of the Cloudprober gRPC service (see
+	// prober/proto/service.proto), e.g. ListProbes and PauseProbe, are also
+	// available as JSON over HTTP on the default HTTP server, at the
+	// /admin/v1/<method> URLs. Requests are POST requests with the JSON
+	// request message as the body, e.g.:
+	//   curl -d '{"probe_name": "web"}' localhost:9313/admin/v1/PauseProbe
+	// It requires the default gRPC server (see grpc_port). Note that these
+	// URLs are not authenticated, so only the read-only methods and the
+	// RunProbe method are available by default (see
+	// admin_http_api_allow_add_remove).
+	enableAdminHttpApi?: bool @protobuf(111,bool,name=enable_admin_http_api)
+
+	// If enabled, AddProbe, RemoveProbe, PauseProbe and ResumeProbe methods are
+	// also available over the admin HTTP API (see enable_admin_http_api). As the HTTP API is not
+	// authenticated, enable it only if the default HTTP server is not
+	// reachable by untrusted clients: AddProbe can add the probes that run
+	// arbitrary commands, e.g. external probes.
+	adminHttpApiAllowAddRemove?: bool @protobuf(112,bool,name=admin_http_api_allow_add_remove)
 
 	// Host for the default HTTP server. Default listens on all addresses. If not
 	// specified in the config, default port can be overridden by the environment
@@ -114,18 +223,112 @@ import (
 	// how to use these variables.
 	sysvarsEnvVar?: string @protobuf(98,string,name=sysvars_env_var,#"default="SYSVARS""#)
 
-	// Time between triggering cancelation of various goroutines and exiting the
-	// process. If --stop_time flag is also configured, that gets priority.
-	// You may want to set it to 0 if cloudprober is running as a backend for
-	// the probes and you don't want time lost in stop and start.
+	// If enabled, an info-style metric, probe_info, is exported for each probe
+	// at the sysvars_interval_msec interval. This metric always has the value 1
+	// and carries probe's details (type, interval, timeout, number of targets,
+	// and config version) as labels. It's useful for joining with probe
+	// metrics in PromQL, for example:
+	//   total * on(probe) group_left(interval) probe_info
+	exportProbeInfo?: bool @protobuf(106,bool,name=export_probe_info)
+
+	// Global rate limit on new outbound connections, shared by all probes'
+	// dialers (TCP, HTTP and gRPC probes). It smooths out connection bursts,
+	// e.g. when a lot of probes start at the same time after a config reload.
+	// Current wait time for new connections is exported as the
+	// conn_limiter_wait_msec metric, at the sysvars_interval_msec interval.
+	connRateLimit?: #ConnRateLimit @protobuf(107,ConnRateLimit,name=conn_rate_limit)
+
+	// Default prefix for the probes' metric names. It's used for the probes
+	// that don't specify their own metric_prefix. See metric_prefix in
+	// ProbeDef for more details.
+	metricPrefix?: string @protobuf(108,string,name=metric_prefix)
+
+	// Additional labels for all the probes. These labels are added before the
+	// probes' own additional labels. If a probe defines a label with the same
+	// key, probe's label's merge_policy decides the label's value, e.g.
+	// global "tags" label can be combined with the probe's "tags" label using
+	// the CONCAT merge policy. See AdditionalLabel in probes config for
+	// details.
+	additionalLabel?: [...proto.#AdditionalLabel] @protobuf(110,probes.AdditionalLabel,name=additional_label)
+
+	// If enabled, an aggregate availability metric, probe_availability_ratio,
+	// is exported for each probe at the probe's stats export interval. It's the
+	// ratio of the targets whose all probe runs in the last interval succeeded
+	// to the targets that reported results in that interval. The number of
+	// such targets is exported as probe_targets_reporting. If no targets
+	// reported in the interval, probe_availability_ratio is not exported (but
+	// probe_targets_reporting is, with the value 0).
+	exportAvailabilityRatio?: bool @protobuf(109,bool,name=export_availability_ratio)
+
+	// Maximum time between triggering cancelation of various goroutines and
+	// exiting the process. Cloudprober exits as soon as the surfacers have
+	// flushed the buffered metrics, or after this time, whichever comes first.
+	// If set to 0, cloudprober waits only for the surfacers' own flush timeouts.
+	// If --stop_time flag is also configured, that gets priority.
 	stopTimeSec?: int32 @protobuf(99,int32,name=stop_time_sec,"default=5")
 
 	// Global targets options. Per-probe options are specified within the probe
 	// stanza.
-	globalTargetsOptions?: proto_E.#GlobalTargetsOptions @protobuf(100,targets.GlobalTargetsOptions,name=global_targets_options)
+	globalTargetsOptions?: proto_B.#GlobalTargetsOptions @protobuf(100,targets.GlobalTargetsOptions,name=global_targets_options)
+}
+
+#ConfigOverride: {
+	// Runtime variables that should match for the override to apply, e.g.
+	// cloud_provider: "gce", region: "us-central1". All variables should
+	// match. Override with an empty selector always applies.
+	selector?: {
+		[string]: string
+	} @protobuf(1,map[string]string)
+
+	// Config to merge over the base config. It cannot contain overrides
+	// itself.
+	config?: #ProberConfig @protobuf(2,ProberConfig)
+}
+
+#ConnRateLimit: {
+	// New connections per second.
+	rate?: float32 @protobuf(1,float)
+
+	// Maximum number of connections that can be made at once, before the rate
+	// limit kicks in.
+	burst?: int32 @protobuf(2,int32,"default=1")
 }
 
 #SharedTargets: {
 	name?:    string              @protobuf(1,string)
-	targets?: proto_E.#TargetsDef @protobuf(2,targets.TargetsDef)
+	targets?: proto_B.#TargetsDef @protobuf(2,targets.TargetsDef)
+}
+
+#ValidatorSet: {
+	name?: string @protobuf(1,string)
+	validator?: [...proto_36.#Validator] @protobuf(2,validators.Validator)
+}
+
+#ProbeTemplate: {
+	// Probe config shared by all the instances. Instance's fields below are
+	// applied to a copy of it for each instance.
+	probe?: proto.#ProbeDef @protobuf(1,probes.ProbeDef)
+
+	#Instance: {
+		// Instance name, appended to the probe's name to get the generated
+		// probe's name. It should be unique within the template.
+		name?: string @protobuf(1,string)
+
+		// Targets for the instance. If specified, these replace the template
+		// probe's targets.
+		targets?: proto_B.#TargetsDef @protobuf(2,targets.TargetsDef)
+
+		// Additional labels for the instance, added after the template probe's
+		// additional labels.
+		additionalLabel?: [...proto.#AdditionalLabel] @protobuf(3,probes.AdditionalLabel,name=additional_label)
+
+		// HTTP headers for the instance, e.g. for authorization, added after the
+		// template probe's headers. Only for HTTP probes.
+		httpHeader?: [...proto_9.#ProbeConf.#Header] @protobuf(4,probes.http.ProbeConf.Header,name=http_header)
+
+		// OAuth config for the instance. If specified, it replaces the template
+		// probe's OAuth config. Only for HTTP probes.
+		oauthConfig?: proto_3.#Config @protobuf(5,oauth.Config,name=oauth_config)
+	}
+	instance?: [...#Instance] @protobuf(2,Instance)
 }
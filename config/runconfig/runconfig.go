@@ -33,7 +33,9 @@ type runConfig struct {
 	sync.RWMutex
 	grpcSrv        *grpc.Server
 	version        string
+	configChecksum string
 	buildTimestamp time.Time
+	buildCommit    string
 	rdsServer      *rdsserver.Server
 	httpServeMux   *http.ServeMux
 }
@@ -71,6 +73,21 @@ func Version() string {
 	return rc.version
 }
 
+// SetConfigChecksum sets the checksum of the config in use.
+func SetConfigChecksum(checksum string) {
+	rc.Lock()
+	defer rc.Unlock()
+	rc.configChecksum = checksum
+}
+
+// ConfigChecksum returns the config checksum set through the
+// SetConfigChecksum() function call, or an empty string if it was not set.
+func ConfigChecksum() string {
+	rc.RLock()
+	defer rc.RUnlock()
+	return rc.configChecksum
+}
+
 // SetBuildTimestamp sets the cloudprober build timestamp.
 func SetBuildTimestamp(ts time.Time) {
 	rc.Lock()
@@ -85,6 +102,20 @@ func BuildTimestamp() time.Time {
 	return rc.buildTimestamp
 }
 
+// SetBuildCommit sets the VCS commit cloudprober was built from.
+func SetBuildCommit(commit string) {
+	rc.Lock()
+	defer rc.Unlock()
+	rc.buildCommit = commit
+}
+
+// BuildCommit returns the recorded build commit.
+func BuildCommit() string {
+	rc.RLock()
+	defer rc.RUnlock()
+	return rc.buildCommit
+}
+
 // SetLocalRDSServer stores local RDS server in the runconfig. It can later
 // be retrieved throuhg LocalRDSServer().
 func SetLocalRDSServer(srv *rdsserver.Server) {
@@ -0,0 +1,322 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schema generates a JSON Schema for the cloudprober config, from
+// the config protobuf descriptors. Schema can be used by the editors and the
+// pre-commit hooks to validate the JSON and YAML configs.
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	draft = "https://json-schema.org/draft/2020-12/schema"
+
+	// Prefix of the cloudprober's proto import paths.
+	importPrefix = "github.com/cloudprober/cloudprober/"
+)
+
+// Lines like "Next tag: 12" are for the config maintainers.
+var nextTagRe = regexp.MustCompile(`(?i)^next (available )?tag`)
+
+// Docs maps the full names of the messages, fields, enums and enum values to
+// their doc comments.
+type Docs map[protoreflect.FullName]string
+
+func cleanComment(comment string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(comment), "\n") {
+		line = strings.TrimSpace(line)
+		if nextTagRe.MatchString(line) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func (docs Docs) add(d protoreflect.Descriptor, locs protoreflect.SourceLocations) {
+	loc := locs.ByDescriptor(d)
+	comment := loc.LeadingComments
+	if strings.TrimSpace(comment) == "" {
+		comment = loc.TrailingComments
+	}
+	if c := cleanComment(comment); c != "" {
+		docs[d.FullName()] = c
+	}
+}
+
+func (docs Docs) addEnums(enums protoreflect.EnumDescriptors, locs protoreflect.SourceLocations) {
+	for i := 0; i < enums.Len(); i++ {
+		ed := enums.Get(i)
+		docs.add(ed, locs)
+		for j := 0; j < ed.Values().Len(); j++ {
+			docs.add(ed.Values().Get(j), locs)
+		}
+	}
+}
+
+func (docs Docs) addMessages(msgs protoreflect.MessageDescriptors, locs protoreflect.SourceLocations) {
+	for i := 0; i < msgs.Len(); i++ {
+		md := msgs.Get(i)
+		docs.add(md, locs)
+		for j := 0; j < md.Fields().Len(); j++ {
+			docs.add(md.Fields().Get(j), locs)
+		}
+		docs.addEnums(md.Enums(), locs)
+		docs.addMessages(md.Messages(), locs)
+	}
+}
+
+// ParseDocs parses the proto files, and the files they import, to extract
+// their doc comments. Files are read from fsys, which should have the
+// cloudprober source tree layout, e.g. config/proto/config.proto.
+func ParseDocs(ctx context.Context, fsys fs.FS, files ...string) (Docs, error) {
+	compiler := protocompile.Compiler{
+		Resolver: &protocompile.SourceResolver{
+			Accessor: func(path string) (io.ReadCloser, error) {
+				return fsys.Open(strings.TrimPrefix(path, importPrefix))
+			},
+		},
+		SourceInfoMode: protocompile.SourceInfoStandard,
+	}
+
+	var importPaths []string
+	for _, f := range files {
+		importPaths = append(importPaths, importPrefix+f)
+	}
+	fds, err := compiler.Compile(ctx, importPaths...)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(Docs)
+	seen := make(map[string]bool)
+	var addFile func(fd protoreflect.FileDescriptor)
+	addFile = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		docs.addEnums(fd.Enums(), fd.SourceLocations())
+		docs.addMessages(fd.Messages(), fd.SourceLocations())
+		for i := 0; i < fd.Imports().Len(); i++ {
+			addFile(fd.Imports().Get(i).FileDescriptor)
+		}
+	}
+	for _, fd := range fds {
+		addFile(fd)
+	}
+	return docs, nil
+}
+
+type generator struct {
+	docs Docs
+	defs map[string]any
+}
+
+func defRef(name protoreflect.FullName) map[string]any {
+	return map[string]any{"$ref": "#/$defs/" + string(name)}
+}
+
+// withDoc adds the descriptor's doc as the schema's description.
+func (g *generator) withDoc(schema map[string]any, d protoreflect.Descriptor) map[string]any {
+	if doc := g.docs[d.FullName()]; doc != "" {
+		schema["description"] = doc
+	}
+	return schema
+}
+
+func (g *generator) enumDef(ed protoreflect.EnumDescriptor) {
+	name := string(ed.FullName())
+	if g.defs[name] != nil {
+		return
+	}
+
+	var values []any
+	var valueDocs []string
+	for i := 0; i < ed.Values().Len(); i++ {
+		v := ed.Values().Get(i)
+		values = append(values, string(v.Name()))
+		if doc := g.docs[v.FullName()]; doc != "" {
+			valueDocs = append(valueDocs, fmt.Sprintf("%s: %s", v.Name(), strings.ReplaceAll(doc, "\n", " ")))
+		}
+	}
+	schema := g.withDoc(map[string]any{"type": "string", "enum": values}, ed)
+	if len(valueDocs) > 0 {
+		desc, _ := schema["description"].(string)
+		schema["description"] = strings.TrimSpace(desc + "\n\n" + strings.Join(valueDocs, "\n"))
+	}
+	g.defs[name] = schema
+}
+
+// valueSchema returns the schema for a single value of the field, as parsed
+// by protojson.
+func (g *generator) valueSchema(fd protoreflect.FieldDescriptor) map[string]any {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.StringKind:
+		return map[string]any{"type": "string"}
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "contentEncoding": "base64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return map[string]any{"type": "number"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind, protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{"type": "integer"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson accepts 64-bit integers as strings as well.
+		return map[string]any{"type": []string{"integer", "string"}, "pattern": `^-?[0-9]+$`}
+	case protoreflect.EnumKind:
+		g.enumDef(fd.Enum())
+		return defRef(fd.Enum().FullName())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		g.messageDef(fd.Message())
+		return defRef(fd.Message().FullName())
+	}
+	return map[string]any{}
+}
+
+// defaultValue returns the field's default value, as it'd appear in JSON.
+func defaultValue(fd protoreflect.FieldDescriptor) any {
+	v := fd.Default()
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return nil
+	case protoreflect.BytesKind:
+		return nil
+	}
+	return v.Interface()
+}
+
+func (g *generator) fieldSchema(fd protoreflect.FieldDescriptor) map[string]any {
+	var schema map[string]any
+	switch {
+	case fd.IsMap():
+		schema = map[string]any{
+			"type":                 "object",
+			"additionalProperties": g.valueSchema(fd.MapValue()),
+		}
+	case fd.IsList():
+		schema = map[string]any{
+			"type":  "array",
+			"items": g.valueSchema(fd),
+		}
+	default:
+		schema = g.valueSchema(fd)
+		if fd.HasDefault() {
+			if v := defaultValue(fd); v != nil {
+				schema["default"] = v
+			}
+		}
+	}
+
+	// $ref siblings are allowed in the 2020-12 draft.
+	return g.withDoc(schema, fd)
+}
+
+// oneofSchema returns the schema that allows at most one of the oneof's
+// fields to be set.
+func oneofSchema(od protoreflect.OneofDescriptor) map[string]any {
+	var pairs []any
+	fields := od.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		for j := i + 1; j < fields.Len(); j++ {
+			pairs = append(pairs, map[string]any{"required": []string{string(fields.Get(i).Name()), string(fields.Get(j).Name())}})
+		}
+	}
+	return map[string]any{"not": map[string]any{"anyOf": pairs}}
+}
+
+func (g *generator) messageDef(md protoreflect.MessageDescriptor) {
+	name := string(md.FullName())
+	if g.defs[name] != nil {
+		return
+	}
+	// Placeholder, to stop the recursion for the recursive messages.
+	schema := map[string]any{"type": "object"}
+	g.defs[name] = schema
+
+	props := make(map[string]any)
+	var required []string
+	for i := 0; i < md.Fields().Len(); i++ {
+		fd := md.Fields().Get(i)
+		props[string(fd.Name())] = g.fieldSchema(fd)
+		if fd.Cardinality() == protoreflect.Required {
+			required = append(required, string(fd.Name()))
+		}
+	}
+	schema["properties"] = props
+	schema["additionalProperties"] = false
+	// Extensions are set using their full names in brackets, e.g.
+	// "[myprober.redis_probe]".
+	if md.ExtensionRanges().Len() > 0 {
+		schema["patternProperties"] = map[string]any{`^\[.+\]$`: map[string]any{}}
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	var oneofs []any
+	for i := 0; i < md.Oneofs().Len(); i++ {
+		od := md.Oneofs().Get(i)
+		if od.IsSynthetic() || od.Fields().Len() < 2 {
+			continue
+		}
+		oneofs = append(oneofs, oneofSchema(od))
+	}
+	if len(oneofs) > 0 {
+		schema["allOf"] = oneofs
+	}
+	g.withDoc(schema, md)
+}
+
+// JSONSchema returns the JSON Schema for the message, with the docs as the
+// descriptions. Properties use the proto field names, as the cloudprober
+// configs do. Messages and enums are defined under "$defs", using their full
+// names. Extensions are allowed, but are not validated.
+func JSONSchema(md protoreflect.MessageDescriptor, docs Docs) ([]byte, error) {
+	g := &generator{docs: docs, defs: make(map[string]any)}
+	g.messageDef(md)
+
+	// encoding/json sorts the map keys, so the output is stable. We use a
+	// struct for the top level, to keep "$defs" at the end.
+	schema := struct {
+		Schema      string         `json:"$schema"`
+		Title       string         `json:"title"`
+		Description string         `json:"description,omitempty"`
+		Ref         string         `json:"$ref"`
+		Defs        map[string]any `json:"$defs"`
+	}{
+		Schema:      draft,
+		Title:       string(md.FullName()),
+		Description: docs[md.FullName()],
+		Ref:         "#/$defs/" + string(md.FullName()),
+		Defs:        g.defs,
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
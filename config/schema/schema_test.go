@@ -0,0 +1,188 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"sigs.k8s.io/yaml"
+)
+
+func testSchema(t *testing.T) ([]byte, *jsonschema.Schema) {
+	t.Helper()
+
+	docs, err := ParseDocs(context.Background(), os.DirFS("../.."), "config/proto/config.proto")
+	require.NoError(t, err)
+	b, err := JSONSchema((&configpb.ProberConfig{}).ProtoReflect().Descriptor(), docs)
+	require.NoError(t, err)
+
+	c := jsonschema.NewCompiler()
+	require.NoError(t, c.AddResource("schema.json", bytes.NewReader(b)))
+	s, err := c.Compile("schema.json")
+	require.NoError(t, err)
+	return b, s
+}
+
+func validateYAML(s *jsonschema.Schema, config string) error {
+	jsonCfg, err := yaml.YAMLToJSON([]byte(config))
+	if err != nil {
+		return err
+	}
+	var v any
+	if err := json.Unmarshal(jsonCfg, &v); err != nil {
+		return err
+	}
+	return s.Validate(v)
+}
+
+func TestJSONSchema(t *testing.T) {
+	b, s := testSchema(t)
+
+	var schema struct {
+		Ref  string                    `json:"$ref"`
+		Defs map[string]map[string]any `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(b, &schema))
+	assert.Equal(t, "#/$defs/cloudprober.ProberConfig", schema.Ref)
+	assert.NotContains(t, string(b), "Next tag", "maintainer comments")
+
+	probeDef := schema.Defs["cloudprober.probes.ProbeDef"]
+	assert.Equal(t, []any{"name", "type"}, probeDef["required"])
+	props := probeDef["properties"].(map[string]any)
+	name := props["name"].(map[string]any)
+	assert.Equal(t, "string", name["type"])
+	assert.Contains(t, name["description"], "Probe name")
+	assert.Equal(t, "#/$defs/cloudprober.probes.ProbeDef.Type", props["type"].(map[string]any)["$ref"])
+	assert.Equal(t, "array", props["validator"].(map[string]any)["type"])
+
+	probeType := schema.Defs["cloudprober.probes.ProbeDef.Type"]
+	assert.Contains(t, probeType["enum"], "HTTP")
+	assert.Contains(t, probeType["description"], "USER_DEFINED: ")
+
+	pingProbe := schema.Defs["cloudprober.probes.ping.ProbeConf"]
+	assert.Equal(t, 2.0, pingProbe["properties"].(map[string]any)["packets_per_probe"].(map[string]any)["default"])
+
+	for _, test := range []struct {
+		desc    string
+		config  string
+		wantErr string
+	}{
+		{
+			desc: "valid",
+			config: `
+probe:
+  - name: web
+    type: HTTP
+    interval: 10s
+    targets:
+      host_names: www.google.com
+    http_probe:
+      protocol: HTTPS
+      header:
+        X-Probe: cloudprober
+      requests_per_probe: 2
+    validator:
+      - name: status
+        http_validator:
+          success_status_codes: "200-299"
+surfacer:
+  - type: PROMETHEUS
+    prometheus_surfacer:
+      metrics_prefix: cloudprober_
+`,
+		},
+		{
+			desc:    "unknown_field",
+			config:  "probe:\n  - name: p\n    type: PING\n    intervall: 10s",
+			wantErr: "intervall",
+		},
+		{
+			desc:    "invalid_enum",
+			config:  "probe:\n  - name: p\n    type: SMTP",
+			wantErr: "type",
+		},
+		{
+			desc:    "invalid_type",
+			config:  "probe:\n  - name: p\n    type: HTTP\n    http_probe:\n      requests_per_probe: two",
+			wantErr: "requests_per_probe",
+		},
+		{
+			desc:    "missing_required",
+			config:  "probe:\n  - type: HTTP",
+			wantErr: "name",
+		},
+		{
+			desc:    "multiple_oneof_fields",
+			config:  "probe:\n  - name: p\n    type: HTTP\n    http_probe: {}\n    dns_probe: {}",
+			wantErr: "not",
+		},
+		{
+			desc:   "extension",
+			config: "probe:\n  - name: p\n    type: EXTENSION\n    \"[myprober.redis_probe]\":\n      op: GET",
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			err := validateYAML(s, test.config)
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
+
+// TestExampleConfigs verifies that the example configs are valid per the
+// schema, after conversion to JSON.
+func TestExampleConfigs(t *testing.T) {
+	_, s := testSchema(t)
+
+	files, err := filepath.Glob("../../examples/*/*.cfg")
+	require.NoError(t, err)
+	for _, f := range files {
+		content, err := os.ReadFile(f)
+		require.NoError(t, err)
+		if strings.Contains(string(content), "{{") {
+			continue // Templates need processing.
+		}
+
+		t.Run(filepath.Base(filepath.Dir(f))+"/"+filepath.Base(f), func(t *testing.T) {
+			cfg := &configpb.ProberConfig{}
+			require.NoError(t, prototext.Unmarshal(content, cfg))
+			jsonCfg, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(cfg)
+			require.NoError(t, err)
+			var v any
+			require.NoError(t, json.Unmarshal(jsonCfg, &v))
+			assert.NoError(t, s.Validate(v))
+		})
+	}
+}
+
+func TestCleanComment(t *testing.T) {
+	assert.Equal(t, "Probe config.\nSecond line.", cleanComment(" Probe config.\n Second line.\n Next tag: 12\n"))
+	assert.Equal(t, "", cleanComment(" Next available tag: 5\n"))
+}
@@ -0,0 +1,125 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// awsSecretsManagerURL returns the Secrets Manager endpoint for the region.
+// It's a variable, so that it can be overridden in tests.
+var awsSecretsManagerURL = func(region string) string {
+	return fmt.Sprintf("https://secretsmanager.%s.amazonaws.com/", region)
+}
+
+// awsProvider reads the secrets from AWS Secrets Manager, using the
+// credentials from the default AWS credentials chain, e.g. environment
+// variables, shared credentials file, or the instance's IAM role.
+//
+// Secret reference is the secret's name or ARN, optionally followed by
+// "#<key>" to select a key in a JSON secret, e.g. prod/probes#api_token.
+// For ARNs, region is taken from the ARN, otherwise from the AWS config.
+type awsProvider struct {
+	client *http.Client
+}
+
+// awsRegion returns the region from the secret's ARN, e.g.
+// arn:aws:secretsmanager:us-west-2:123456789012:secret:probes.
+func awsRegion(secretID string) string {
+	parts := strings.Split(secretID, ":")
+	if len(parts) > 3 && parts[0] == "arn" {
+		return parts[3]
+	}
+	return ""
+}
+
+func (p *awsProvider) Secret(ctx context.Context, ref string) (string, error) {
+	secretID, key := splitKey(ref)
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error loading AWS config: %v", err)
+	}
+	if cfg.Credentials == nil {
+		return "", errors.New("no AWS credentials provider found")
+	}
+	creds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error retrieving AWS credentials: %v", err)
+	}
+	region := awsRegion(secretID)
+	if region == "" {
+		region = cfg.Region
+	}
+	if region == "" {
+		return "", errors.New("AWS region is not configured")
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, awsSecretsManagerURL(region), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	hash := sha256.Sum256(body)
+	if err := v4.NewSigner().SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "secretsmanager", region, time.Now()); err != nil {
+		return "", fmt.Errorf("error signing the request: %v", err)
+	}
+
+	client := p.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GetSecretValue request failed, status: %s, response: %s", resp.Status, respBody)
+	}
+
+	var v struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(respBody, &v); err != nil {
+		return "", fmt.Errorf("error parsing GetSecretValue response: %v", err)
+	}
+	if v.SecretString == nil {
+		return "", errors.New("secret has no string value, binary secrets are not supported")
+	}
+	return jsonKey(*v.SecretString, key)
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAWSRegion(t *testing.T) {
+	assert.Equal(t, "us-west-2", awsRegion("arn:aws:secretsmanager:us-west-2:123456789012:secret:probes"))
+	assert.Equal(t, "", awsRegion("prod/probes"))
+}
+
+func TestAWSProvider(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test-key-id")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test-secret")
+	t.Setenv("AWS_REGION", "us-east-2")
+	t.Setenv("AWS_CONFIG_FILE", "/nonexistent")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/nonexistent")
+
+	secrets := map[string]string{
+		"prod/probes": `{"api_token": "s3cr3t"}`,
+		"arn:aws:secretsmanager:us-west-2:123456789012:secret:plain": "plain-s3cr3t",
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" || !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test-key-id/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var req struct{ SecretId string }
+		json.NewDecoder(r.Body).Decode(&req)
+		v, ok := secrets[req.SecretId]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type": "ResourceNotFoundException"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"SecretString": v})
+	}))
+	defer ts.Close()
+
+	var gotRegion string
+	oldURL := awsSecretsManagerURL
+	defer func() { awsSecretsManagerURL = oldURL }()
+	awsSecretsManagerURL = func(region string) string {
+		gotRegion = region
+		return ts.URL
+	}
+
+	p := &awsProvider{}
+	v, err := p.Secret(context.Background(), "prod/probes#api_token")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", v)
+	assert.Equal(t, "us-east-2", gotRegion)
+
+	v, err = p.Secret(context.Background(), "arn:aws:secretsmanager:us-west-2:123456789012:secret:plain")
+	assert.NoError(t, err)
+	assert.Equal(t, "plain-s3cr3t", v)
+	assert.Equal(t, "us-west-2", gotRegion, "region from ARN")
+
+	_, err = p.Secret(context.Background(), "prod/missing")
+	assert.ErrorContains(t, err, "ResourceNotFoundException")
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+)
+
+// gcpSecretManagerURL is the Secret Manager API base URL. It's a variable, so
+// that it can be overridden in tests.
+var gcpSecretManagerURL = "https://secretmanager.googleapis.com/v1/"
+
+// gcpProvider reads the secrets from GCP Secret Manager, using the
+// application default credentials.
+//
+// Secret reference is the secret's resource name, optionally with the
+// version (latest by default), and optionally followed by "#<key>" to select
+// a key in a JSON secret, e.g. projects/p1/secrets/probes/versions/2#api_token.
+type gcpProvider struct {
+	client *http.Client
+}
+
+// gcpSecretVersion returns the resource name of the secret's version.
+func gcpSecretVersion(name string) (string, error) {
+	parts := strings.Split(strings.Trim(name, "/"), "/")
+	if len(parts) < 4 || parts[0] != "projects" || parts[2] != "secrets" {
+		return "", fmt.Errorf("invalid GCP secret name: %s, it should be like projects/<project>/secrets/<secret>", name)
+	}
+	switch len(parts) {
+	case 4:
+		return strings.Join(parts, "/") + "/versions/latest", nil
+	case 6:
+		if parts[4] == "versions" {
+			return strings.Join(parts, "/"), nil
+		}
+	}
+	return "", fmt.Errorf("invalid GCP secret name: %s, it should be like projects/<project>/secrets/<secret>/versions/<version>", name)
+}
+
+func (p *gcpProvider) Secret(ctx context.Context, ref string) (string, error) {
+	name, key := splitKey(ref)
+	version, err := gcpSecretVersion(name)
+	if err != nil {
+		return "", err
+	}
+
+	client := p.client
+	if client == nil {
+		if client, err = google.DefaultClient(ctx, "https://www.googleapis.com/auth/cloud-platform"); err != nil {
+			return "", fmt.Errorf("error creating GCP client: %v", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcpSecretManagerURL+version+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("secret access request failed, status: %s", resp.Status)
+	}
+
+	var v struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "", fmt.Errorf("error parsing secret access response: %v", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(v.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("error decoding secret payload: %v", err)
+	}
+	return jsonKey(string(data), key)
+}
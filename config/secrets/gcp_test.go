@@ -0,0 +1,72 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGCPSecretVersion(t *testing.T) {
+	for name, want := range map[string]string{
+		"projects/p1/secrets/probes":            "projects/p1/secrets/probes/versions/latest",
+		"projects/p1/secrets/probes/versions/2": "projects/p1/secrets/probes/versions/2",
+		"/projects/p1/secrets/probes/":          "projects/p1/secrets/probes/versions/latest",
+		"probes":                                "",
+		"projects/p1/secrets/probes/aliases/2":  "",
+		"projects/p1/topics/probes/versions/2":  "",
+	} {
+		got, err := gcpSecretVersion(name)
+		if want == "" {
+			assert.Error(t, err, name)
+			continue
+		}
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+}
+
+func TestGCPProvider(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/projects/p1/secrets/probes/versions/latest:access" {
+			http.NotFound(w, r)
+			return
+		}
+		data := base64.StdEncoding.EncodeToString([]byte(`{"api_token": "s3cr3t"}`))
+		w.Write([]byte(`{"name": "projects/p1/secrets/probes/versions/1", "payload": {"data": "` + data + `"}}`))
+	}))
+	defer ts.Close()
+
+	oldURL := gcpSecretManagerURL
+	defer func() { gcpSecretManagerURL = oldURL }()
+	gcpSecretManagerURL = ts.URL + "/v1/"
+
+	p := &gcpProvider{client: ts.Client()}
+	v, err := p.Secret(context.Background(), "projects/p1/secrets/probes#api_token")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", v)
+
+	v, err = p.Secret(context.Background(), "projects/p1/secrets/probes")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"api_token": "s3cr3t"}`, v)
+
+	_, err = p.Secret(context.Background(), "projects/p1/secrets/other")
+	assert.ErrorContains(t, err, "404")
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package secrets implements the secret providers that resolve the secret
+references in the config, e.g.:
+
+	api_key: "{{secret "vault:kv/probes/api_token"}}"
+
+A secret reference consists of the provider name and a provider specific
+reference to the secret, separated by a colon. Built-in providers are:
+
+	vault: HashiCorp Vault KV secrets engine, e.g. vault:kv/probes/api_token.
+	awssm: AWS Secrets Manager, e.g. awssm:prod/probes#api_token.
+	gcpsm: GCP Secret Manager, e.g. gcpsm:projects/p1/secrets/api_token.
+
+AWS and GCP secrets can be JSON objects, in which case a key in the object can
+be selected by adding "#<key>" to the reference.
+*/
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// resolveTimeout is the timeout for resolving a secret.
+const resolveTimeout = 30 * time.Second
+
+// Provider resolves the secrets stored in a secrets backend.
+type Provider interface {
+	// Secret returns the value of the secret. ref is the provider specific
+	// part of the secret reference, e.g. kv/probes/api_token for
+	// vault:kv/probes/api_token.
+	Secret(ctx context.Context, ref string) (string, error)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]Provider{
+		"vault": &vaultProvider{},
+		"awssm": &awsProvider{},
+		"gcpsm": &gcpProvider{},
+	}
+)
+
+// Register registers a secret provider with the given name, replacing the
+// existing provider with the same name, if any. It allows adding custom
+// providers, and overriding the built-in ones.
+func Register(name string, p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[name] = p
+}
+
+// ParseRef splits the secret reference into the provider name and the
+// provider specific reference. It returns an error if the reference is
+// malformed, or if the provider is not registered.
+func ParseRef(secretRef string) (string, string, error) {
+	name, ref, ok := strings.Cut(secretRef, ":")
+	if !ok || name == "" || ref == "" {
+		return "", "", fmt.Errorf("invalid secret reference: %s, it should be like <provider>:<secret>, e.g. vault:kv/probes/api_token", secretRef)
+	}
+
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	if providers[name] == nil {
+		var names []string
+		for n := range providers {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return "", "", fmt.Errorf("unknown secret provider %s in %s, available providers: %s", name, secretRef, strings.Join(names, ", "))
+	}
+	return name, ref, nil
+}
+
+// Resolve returns the value of the secret referenced by secretRef, e.g.
+// vault:kv/probes/api_token. Resolution is canceled if ctx is done.
+func Resolve(ctx context.Context, secretRef string) (string, error) {
+	name, ref, err := ParseRef(secretRef)
+	if err != nil {
+		return "", err
+	}
+
+	providersMu.RLock()
+	p := providers[name]
+	providersMu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+
+	v, err := p.Secret(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("error resolving secret %s: %v", secretRef, err)
+	}
+	return v, nil
+}
+
+// splitKey splits the "#<key>" suffix from the reference.
+func splitKey(ref string) (string, string) {
+	ref, key, _ := strings.Cut(ref, "#")
+	return ref, key
+}
+
+// jsonKey returns the value of the key in the secret, which should be a JSON
+// object. Non-string values are returned in the JSON format. Secret is
+// returned as it is if key is empty.
+func jsonKey(secret, key string) (string, error) {
+	if key == "" {
+		return secret, nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &m); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, can't get the key %s from it", key)
+	}
+	return stringValue(m, key)
+}
+
+func stringValue(m map[string]interface{}, key string) (string, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in the secret", key)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
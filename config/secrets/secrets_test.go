@@ -0,0 +1,96 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testProvider map[string]string
+
+func (tp testProvider) Secret(_ context.Context, ref string) (string, error) {
+	if v, ok := tp[ref]; ok {
+		return v, nil
+	}
+	return "", errors.New("not found")
+}
+
+func TestParseRef(t *testing.T) {
+	for _, test := range []struct {
+		secretRef string
+		wantName  string
+		wantRef   string
+		wantErr   string
+	}{
+		{secretRef: "vault:kv/probes/api_token", wantName: "vault", wantRef: "kv/probes/api_token"},
+		{secretRef: "awssm:arn:aws:secretsmanager:us-west-2:1234:secret:p", wantName: "awssm", wantRef: "arn:aws:secretsmanager:us-west-2:1234:secret:p"},
+		{secretRef: "kv/probes/api_token", wantErr: "invalid secret reference"},
+		{secretRef: "vault:", wantErr: "invalid secret reference"},
+		{secretRef: "keyring:api_token", wantErr: "unknown secret provider keyring in keyring:api_token, available providers: awssm, gcpsm, vault"},
+	} {
+		t.Run(test.secretRef, func(t *testing.T) {
+			name, ref, err := ParseRef(test.secretRef)
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantName, name)
+			assert.Equal(t, test.wantRef, ref)
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	Register("test", testProvider{"api_token": "s3cr3t"})
+	defer func() {
+		providersMu.Lock()
+		delete(providers, "test")
+		providersMu.Unlock()
+	}()
+
+	v, err := Resolve(context.Background(), "test:api_token")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", v)
+
+	_, err = Resolve(context.Background(), "test:missing")
+	assert.ErrorContains(t, err, "error resolving secret test:missing: not found")
+}
+
+func TestJSONKey(t *testing.T) {
+	secret := `{"api_token": "s3cr3t", "port": 8080}`
+
+	v, err := jsonKey(secret, "")
+	assert.NoError(t, err)
+	assert.Equal(t, secret, v)
+
+	v, err = jsonKey(secret, "api_token")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", v)
+
+	v, err = jsonKey(secret, "port")
+	assert.NoError(t, err)
+	assert.Equal(t, "8080", v)
+
+	_, err = jsonKey(secret, "user")
+	assert.ErrorContains(t, err, "key user not found")
+
+	_, err = jsonKey("s3cr3t", "api_token")
+	assert.ErrorContains(t, err, "not a JSON object")
+}
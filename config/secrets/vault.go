@@ -0,0 +1,115 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+var vaultKVVersion = flag.Int("vault_kv_version", 2, "Version of the Vault KV secrets engine used for the vault secrets, 1 or 2")
+
+// vaultProvider reads the secrets from the HashiCorp Vault KV secrets engine.
+// Vault address and token are taken from the standard VAULT_ADDR and
+// VAULT_TOKEN environment variables, and the namespace, if any, from
+// VAULT_NAMESPACE.
+//
+// Secret reference is the secret's path, starting with the secrets engine
+// mount, and the key, e.g. kv/probes/api_token for the api_token key of the
+// kv/probes secret. Key can also be specified explicitly: kv/probes#api_token.
+type vaultProvider struct {
+	client *http.Client
+}
+
+// vaultURL returns the URL for the secret's path, for the configured KV
+// version.
+func vaultURL(addr, secretPath string, kvVersion int) (string, error) {
+	secretPath = strings.Trim(secretPath, "/")
+	if kvVersion == 1 {
+		return strings.TrimSuffix(addr, "/") + "/v1/" + secretPath, nil
+	}
+	mount, rest, ok := strings.Cut(secretPath, "/")
+	if !ok || rest == "" {
+		return "", fmt.Errorf("invalid vault secret path: %s, it should include the secrets engine mount, e.g. kv/probes", secretPath)
+	}
+	return strings.TrimSuffix(addr, "/") + "/v1/" + mount + "/data/" + rest, nil
+}
+
+func (p *vaultProvider) Secret(ctx context.Context, ref string) (string, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return "", errors.New("VAULT_ADDR environment variable is not set")
+	}
+
+	secretPath, key := splitKey(ref)
+	if key == "" {
+		secretPath, key = path.Split(secretPath)
+	}
+	if secretPath == "" || key == "" {
+		return "", fmt.Errorf("invalid vault secret reference: %s, it should be like kv/probes/api_token", ref)
+	}
+
+	url, err := vaultURL(addr, secretPath, *vaultKVVersion)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", os.Getenv("VAULT_TOKEN"))
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	client := p.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault request failed, status: %s", resp.Status)
+	}
+
+	// KV v1 returns the secret in "data", while KV v2 returns the secret and
+	// its metadata in "data", with the secret in "data.data".
+	var v struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "", fmt.Errorf("error parsing vault response: %v", err)
+	}
+	data := v.Data
+	if *vaultKVVersion != 1 {
+		data, _ = v.Data["data"].(map[string]interface{})
+	}
+	return stringValue(data, key)
+}
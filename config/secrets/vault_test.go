@@ -0,0 +1,73 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVaultProvider(t *testing.T) {
+	var gotPath, gotToken string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotToken = r.URL.Path, r.Header.Get("X-Vault-Token")
+		switch r.URL.Path {
+		case "/v1/kv/data/probes":
+			w.Write([]byte(`{"data": {"data": {"api_token": "s3cr3t"}, "metadata": {"version": 1}}}`))
+		case "/v1/kv/probes":
+			w.Write([]byte(`{"data": {"api_token": "v1-s3cr3t"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	t.Setenv("VAULT_ADDR", ts.URL)
+	t.Setenv("VAULT_TOKEN", "test-token")
+
+	p := &vaultProvider{}
+	for _, ref := range []string{"kv/probes/api_token", "kv/probes#api_token"} {
+		v, err := p.Secret(context.Background(), ref)
+		assert.NoError(t, err, ref)
+		assert.Equal(t, "s3cr3t", v, ref)
+		assert.Equal(t, "/v1/kv/data/probes", gotPath)
+		assert.Equal(t, "test-token", gotToken)
+	}
+
+	_, err := p.Secret(context.Background(), "kv/probes/user")
+	assert.ErrorContains(t, err, "key user not found")
+
+	_, err = p.Secret(context.Background(), "kv/other/api_token")
+	assert.ErrorContains(t, err, "404")
+
+	_, err = p.Secret(context.Background(), "api_token")
+	assert.ErrorContains(t, err, "invalid vault secret reference")
+
+	oldVersion := *vaultKVVersion
+	defer func() { *vaultKVVersion = oldVersion }()
+	*vaultKVVersion = 1
+	v, err := p.Secret(context.Background(), "kv/probes/api_token")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1-s3cr3t", v)
+	assert.Equal(t, "/v1/kv/probes", gotPath)
+
+	t.Setenv("VAULT_ADDR", "")
+	_, err = p.Secret(context.Background(), "kv/probes/api_token")
+	assert.ErrorContains(t, err, "VAULT_ADDR")
+}
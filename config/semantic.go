@@ -0,0 +1,305 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/probes/options"
+	probespb "github.com/cloudprober/cloudprober/probes/proto"
+	surfacerspb "github.com/cloudprober/cloudprober/surfacers/proto"
+)
+
+// Severity is the severity of a validation issue.
+type Severity string
+
+// Severities of the validation issues. Config with errors will not work as
+// intended, while warnings point to the likely mistakes.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// ValidationError is an issue found by the semantic checks of the config.
+type ValidationError struct {
+	// Path is the path of the offending field, e.g. probe[2].timeout. It's
+	// empty for config wide issues.
+	Path     string   `json:"path,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (ve ValidationError) String() string {
+	s := strings.ToUpper(string(ve.Severity))
+	if ve.Path != "" {
+		s += " " + ve.Path
+	}
+	return s + ": " + ve.Message
+}
+
+// HasValidationErrors returns true if any of the issues is an error.
+func HasValidationErrors(issues []ValidationError) bool {
+	for _, ve := range issues {
+		if ve.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate runs the semantic checks, i.e. checks that go beyond parsing, on
+// the expanded config (see expandConfig). Currently it reports:
+//   - duplicate probe, shared_targets and validator_set names.
+//   - surfacers that can't be referred to separately as they have the same
+//     name.
+//   - probe intervals shorter than the timeouts.
+//   - dangling references to surfacers, shared_targets and validator_sets.
+func Validate(cfg *configpb.ProberConfig) []ValidationError {
+	var issues []ValidationError
+	addIssue := func(sev Severity, path, format string, args ...interface{}) {
+		issues = append(issues, ValidationError{Path: path, Severity: sev, Message: fmt.Sprintf(format, args...)})
+	}
+
+	probes := make(map[string]int)
+	for i, p := range cfg.GetProbe() {
+		if prev, ok := probes[p.GetName()]; ok {
+			addIssue(SeverityError, fmt.Sprintf("probe[%d].name", i), "duplicate probe name %s, also used by probe[%d]", p.GetName(), prev)
+			continue
+		}
+		probes[p.GetName()] = i
+	}
+
+	sharedTargets := make(map[string]int)
+	for i, st := range cfg.GetSharedTargets() {
+		if prev, ok := sharedTargets[st.GetName()]; ok {
+			addIssue(SeverityError, fmt.Sprintf("shared_targets[%d].name", i), "duplicate shared_targets name %s, also used by shared_targets[%d]", st.GetName(), prev)
+			continue
+		}
+		sharedTargets[st.GetName()] = i
+	}
+
+	validatorSets := make(map[string]int)
+	for i, vs := range cfg.GetValidatorSet() {
+		if prev, ok := validatorSets[vs.GetName()]; ok {
+			addIssue(SeverityError, fmt.Sprintf("validator_set[%d].name", i), "duplicate validator_set name %s, also used by validator_set[%d]", vs.GetName(), prev)
+			continue
+		}
+		validatorSets[vs.GetName()] = i
+	}
+
+	surfacerNames := make(map[string]int)
+	for i, s := range cfg.GetSurfacer() {
+		name := SurfacerRefName(s)
+		if name == "" {
+			continue
+		}
+		if prev, ok := surfacerNames[name]; ok {
+			// Explicitly named surfacers are expected to be unique, while the
+			// surfacers of the same type may be fine if they are not referred
+			// to by name.
+			sev := SeverityWarning
+			if s.GetName() != "" {
+				sev = SeverityError
+			}
+			addIssue(sev, fmt.Sprintf("surfacer[%d]", i), "surfacer name %s is also used by surfacer[%d], set the name field to refer to them separately", name, prev)
+			continue
+		}
+		surfacerNames[name] = i
+	}
+
+	surfacers := SurfacerRefNames(cfg)
+	for i, p := range cfg.GetProbe() {
+		path := fmt.Sprintf("probe[%d]", i)
+
+		if ve := validateProbeTimings(p, path); ve != nil {
+			issues = append(issues, *ve)
+		}
+
+		for j, s := range p.GetSurfacers() {
+			if !surfacers[s] {
+				addIssue(SeverityError, fmt.Sprintf("%s.surfacers[%d]", path, j), "unknown surfacer %s", s)
+			}
+		}
+		if st := p.GetTargets().GetSharedTargets(); st != "" {
+			if _, ok := sharedTargets[st]; !ok {
+				addIssue(SeverityError, path+".targets.shared_targets", "unknown shared_targets %s", st)
+			}
+		}
+		for j, vs := range p.GetValidatorSet() {
+			if _, ok := validatorSets[vs]; !ok {
+				addIssue(SeverityError, fmt.Sprintf("%s.validator_set[%d]", path, j), "unknown validator_set %s", vs)
+			}
+		}
+	}
+
+	return issues
+}
+
+// probeDuration returns the probe's duration from the msec or the string
+// field (see options.ProbeDuration), along with the path of the field that
+// was set. Ok is false if the duration can't be determined; such issues are
+// reported while parsing the probe's options.
+func probeDuration(msec int32, str string, def time.Duration, path, field string) (d time.Duration, fieldPath string, ok bool) {
+	d, err := options.ProbeDuration(msec, str, def, field)
+	if err != nil {
+		return 0, "", false
+	}
+	switch {
+	case msec != 0:
+		fieldPath = path + "." + field + "_msec"
+	case str != "":
+		fieldPath = path + "." + field
+	}
+	return d, fieldPath, true
+}
+
+// validateProbeTimings returns an issue if the probe's timeout is longer than
+// its interval, except for the UDP probes that are allowed to have
+// overlapping runs.
+func validateProbeTimings(p *probespb.ProbeDef, path string) *ValidationError {
+	if p.GetType() == probespb.ProbeDef_UDP {
+		return nil
+	}
+	interval, intervalPath, ok := probeDuration(p.GetIntervalMsec(), p.GetInterval(), options.DefaultInterval, path, "interval")
+	if !ok {
+		return nil
+	}
+	timeout, timeoutPath, ok := probeDuration(p.GetTimeoutMsec(), p.GetTimeout(), options.DefaultTimeout, path, "timeout")
+	if !ok || timeout <= interval {
+		return nil
+	}
+
+	// Point to the field that was set, preferably the timeout.
+	fieldPath := timeoutPath
+	if fieldPath == "" {
+		fieldPath = intervalPath
+	}
+	return &ValidationError{
+		Path:     fieldPath,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("interval (%v) is shorter than the timeout (%v)", interval, timeout),
+	}
+}
+
+const (
+	defaultEndpointCheckTimeout = 5 * time.Second
+	defaultDatadogServer        = "api.datadoghq.com"
+	defaultPostgresPort         = "5432"
+)
+
+// postgresAddress returns the host:port in the postgres connection string,
+// which can be a URL, e.g. postgres://user@host:5432/db, or a list of
+// key=value pairs, e.g. "host=db.local port=5432". It returns an empty string
+// for unix domain sockets, and if the host is not specified.
+func postgresAddress(connStr string) string {
+	var host, port string
+	if strings.HasPrefix(connStr, "postgres://") || strings.HasPrefix(connStr, "postgresql://") {
+		u, err := url.Parse(connStr)
+		if err != nil {
+			return ""
+		}
+		host, port = u.Hostname(), u.Port()
+	} else {
+		for _, kv := range strings.Fields(connStr) {
+			k, v, _ := strings.Cut(kv, "=")
+			switch k {
+			case "host":
+				host = v
+			case "port":
+				port = v
+			}
+		}
+	}
+	if host == "" || strings.HasPrefix(host, "/") {
+		return ""
+	}
+	if port == "" {
+		port = defaultPostgresPort
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// surfacerEndpoint returns the network address the surfacer exports data to,
+// and the path of the config field it comes from. Address is empty if the
+// surfacer doesn't have a checkable endpoint.
+func surfacerEndpoint(s *surfacerspb.SurfacerDef, path string) (addr, fieldPath string) {
+	switch {
+	case s.GetDatadogSurfacer() != nil:
+		server := s.GetDatadogSurfacer().GetServer()
+		if server == "" {
+			server = defaultDatadogServer
+		}
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "443")
+		}
+		return server, path + ".datadog_surfacer.server"
+	case s.GetPostgresSurfacer() != nil:
+		return postgresAddress(s.GetPostgresSurfacer().GetConnectionString()), path + ".postgres_surfacer.connection_string"
+	}
+	return "", ""
+}
+
+// CheckSurfacerEndpoints checks that the surfacers can reach their
+// endpoints: network endpoints, e.g. the postgres server, should accept
+// connections within the timeout, and the directories for the file based
+// surfacers should exist. Unreachable network endpoints are reported as
+// warnings, as they may be reachable only from where the prober runs.
+func CheckSurfacerEndpoints(cfg *configpb.ProberConfig, timeout time.Duration) []ValidationError {
+	var issues []ValidationError
+	for i, s := range cfg.GetSurfacer() {
+		path := fmt.Sprintf("surfacer[%d]", i)
+
+		var filePath, fileField string
+		switch {
+		case s.GetFileSurfacer().GetFilePath() != "":
+			filePath, fileField = s.GetFileSurfacer().GetFilePath(), path+".file_surfacer.file_path"
+		case s.GetSqliteSurfacer().GetFilePath() != "":
+			filePath, fileField = s.GetSqliteSurfacer().GetFilePath(), path+".sqlite_surfacer.file_path"
+		}
+		if filePath != "" {
+			if fi, err := os.Stat(filepath.Dir(filePath)); err != nil || !fi.IsDir() {
+				issues = append(issues, ValidationError{
+					Path:     fileField,
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("directory for %s doesn't exist", filePath),
+				})
+			}
+			continue
+		}
+
+		addr, fieldPath := surfacerEndpoint(s, path)
+		if addr == "" {
+			continue
+		}
+		conn, err := net.DialTimeout("tcp", addr, timeout)
+		if err != nil {
+			issues = append(issues, ValidationError{
+				Path:     fieldPath,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("endpoint %s is not reachable: %v", addr, err),
+			})
+			continue
+		}
+		conn.Close()
+	}
+	return issues
+}
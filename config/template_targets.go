@@ -0,0 +1,104 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"regexp"
+	"sync"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/targets"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// templateTargetsRegex matches references to sharedTargets in config
+// templates.
+var templateTargetsRegex = regexp.MustCompile(`\bsharedTargets\b`)
+
+// Targets listers created for the config templates, keyed by their
+// serialized definition. File targets refresh themselves in the background,
+// so we reuse the listers across the config parses, instead of creating new
+// ones (and refresh goroutines) every time the config is parsed.
+var (
+	templateListers   = make(map[string]targets.Targets)
+	templateListersMu sync.Mutex
+)
+
+// usesTemplateTargets returns true if the config template refers to
+// sharedTargets.
+func usesTemplateTargets(config string) bool {
+	return templateTargetsRegex.MatchString(config)
+}
+
+// templateTargetsSupported returns true if targets can be discovered at the
+// config parsing time. We support only static and file based targets, as
+// other targets types require cloudprober to be fully initialized.
+func templateTargetsSupported(td *targetspb.TargetsDef) bool {
+	switch td.GetType().(type) {
+	case *targetspb.TargetsDef_HostNames, *targetspb.TargetsDef_FileTargets:
+		return true
+	case nil:
+		return len(td.GetEndpoints()) > 0
+	}
+	return false
+}
+
+// templateTargets returns a snapshot of the shared targets' endpoints, keyed
+// by the shared targets' name. Unsupported or failing targets are skipped
+// with a warning.
+func templateTargets(cfg *configpb.ProberConfig, l *logger.Logger) map[string][]endpoint.Endpoint {
+	tt := make(map[string][]endpoint.Endpoint)
+
+	for _, st := range cfg.GetSharedTargets() {
+		if !templateTargetsSupported(st.GetTargets()) {
+			l.Warningf("Shared targets %s: only static and file targets are available in config templates, skipping.", st.GetName())
+			continue
+		}
+
+		tgts, err := templateLister(st.GetTargets(), l)
+		if err != nil {
+			l.Warningf("Shared targets %s: error creating targets for config template: %v", st.GetName(), err)
+			continue
+		}
+		tt[st.GetName()] = tgts.ListEndpoints()
+	}
+
+	return tt
+}
+
+// templateLister returns the targets lister for the given targets
+// definition, creating it if required.
+func templateLister(td *targetspb.TargetsDef, l *logger.Logger) (targets.Targets, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(td)
+	if err != nil {
+		return nil, err
+	}
+	key := string(b)
+
+	templateListersMu.Lock()
+	defer templateListersMu.Unlock()
+	if tgts := templateListers[key]; tgts != nil {
+		return tgts, nil
+	}
+	tgts, err := targets.New(td, nil, nil, l, l)
+	if err != nil {
+		return nil, err
+	}
+	templateListers[key] = tgts
+	return tgts, nil
+}
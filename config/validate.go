@@ -0,0 +1,159 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	surfacerspb "github.com/cloudprober/cloudprober/surfacers/proto"
+)
+
+// Surfacers that are added if no surfacer is configured, and surfacers that
+// are always added. These should be kept in sync with the surfacers package.
+var (
+	defaultSurfacerNames  = []string{"prometheus", "file"}
+	requiredSurfacerNames = []string{"probestatus"}
+)
+
+// SurfacerRefName returns the name used to refer to the surfacer in the probe
+// config: surfacer's name if configured, otherwise its type in lower case. If
+// type is not set, it's inferred from the surfacer config field name, e.g.
+// "file" for file_surfacer. An empty string is returned for the surfacer{}
+// stanza that disables surfacers.
+func SurfacerRefName(s *surfacerspb.SurfacerDef) string {
+	if s.GetName() != "" {
+		return s.GetName()
+	}
+	if s.GetType() != surfacerspb.Type_NONE {
+		return strings.ToLower(s.GetType().String())
+	}
+	fd := s.ProtoReflect().WhichOneof(s.ProtoReflect().Descriptor().Oneofs().ByName("surfacer"))
+	if fd == nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(fd.Name()), "_surfacer")
+}
+
+func SurfacerRefNames(cfg *configpb.ProberConfig) map[string]bool {
+	names := make(map[string]bool)
+	if len(cfg.GetSurfacer()) == 0 {
+		for _, name := range defaultSurfacerNames {
+			names[name] = true
+		}
+	}
+	for _, s := range cfg.GetSurfacer() {
+		if name := SurfacerRefName(s); name != "" {
+			names[name] = true
+		}
+	}
+	for _, name := range requiredSurfacerNames {
+		names[name] = true
+	}
+	return names
+}
+
+// validateReferences cross-checks the references between config sections
+// and returns an error for every dangling reference:
+//   - probe to surfacer references (probe's "surfacers" field).
+//   - probe to shared targets references (targets' "shared_targets" field).
+//   - probe to validator sets references (probe's "validator_set" field).
+func validateReferences(cfg *configpb.ProberConfig) error {
+	surfacers := SurfacerRefNames(cfg)
+
+	sharedTargets := make(map[string]bool)
+	for _, st := range cfg.GetSharedTargets() {
+		sharedTargets[st.GetName()] = true
+	}
+
+	validatorSets := make(map[string]bool)
+	for _, vs := range cfg.GetValidatorSet() {
+		validatorSets[vs.GetName()] = true
+	}
+
+	var errs []error
+	for _, p := range cfg.GetProbe() {
+		for _, s := range p.GetSurfacers() {
+			if !surfacers[s] {
+				errs = append(errs, fmt.Errorf("probe %s: unknown surfacer %s", p.GetName(), s))
+			}
+		}
+		if st := p.GetTargets().GetSharedTargets(); st != "" && !sharedTargets[st] {
+			errs = append(errs, fmt.Errorf("probe %s: unknown shared_targets %s", p.GetName(), st))
+		}
+		for _, vs := range p.GetValidatorSet() {
+			if !validatorSets[vs] {
+				errs = append(errs, fmt.Errorf("probe %s: unknown validator_set %s", p.GetName(), vs))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// durationFields are the probe fields that take a duration string, e.g. "10s".
+var durationFields = []string{"interval", "timeout"}
+
+// bareNumber returns true if the duration string is a number without a unit,
+// e.g. "10". "0" is unambiguous and allowed.
+func bareNumber(d string) bool {
+	if _, err := strconv.ParseFloat(d, 64); err != nil {
+		return false
+	}
+	return d != "0"
+}
+
+// validateDurations returns an error for every probe duration field that is
+// a bare number, as the intended unit can't be known: interval "10" may mean
+// 10s or 10ms.
+func validateDurations(cfg *configpb.ProberConfig) error {
+	var errs []error
+	for _, p := range cfg.GetProbe() {
+		for i, d := range []string{p.GetInterval(), p.GetTimeout()} {
+			if bareNumber(d) {
+				errs = append(errs, fmt.Errorf("probe %s: %s (%s) needs an explicit unit, e.g. \"%ss\"", p.GetName(), durationFields[i], d, d))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateJSONDurations is like validateDurations, but for the config in
+// JSON format (YAML configs are converted to JSON before parsing). YAML and
+// JSON allow specifying durations as numbers, e.g. "interval: 10", which the
+// proto parser rejects with an obscure error.
+func validateJSONDurations(jsonCfg []byte) error {
+	var cfg struct {
+		Probe []map[string]interface{} `json:"probe"`
+	}
+	// Leave the syntax errors to the proto parser.
+	if err := json.Unmarshal(jsonCfg, &cfg); err != nil {
+		return nil
+	}
+
+	var errs []error
+	for _, p := range cfg.Probe {
+		for _, f := range durationFields {
+			if v, ok := p[f].(float64); ok {
+				errs = append(errs, fmt.Errorf("probe %v: %s (%v) needs an explicit unit, e.g. \"%vs\"", p["name"], f, v, v))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
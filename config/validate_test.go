@@ -0,0 +1,214 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateReferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr []string
+	}{
+		{
+			name: "default_surfacers",
+			config: `
+				probe {
+					name: "p1"
+					type: EXTERNAL
+					surfacers: "file"
+					surfacers: "prometheus"
+					surfacers: "probestatus"
+				}`,
+		},
+		{
+			name: "named_and_inferred_surfacers",
+			config: `
+				probe {
+					name: "p1"
+					type: EXTERNAL
+					surfacers: "local-file"
+					surfacers: "prometheus"
+				}
+				surfacer {
+					name: "local-file"
+					type: FILE
+				}
+				surfacer {
+					prometheus_surfacer {}
+				}`,
+		},
+		{
+			name: "unknown_surfacer",
+			config: `
+				probe {
+					name: "p1"
+					type: EXTERNAL
+					surfacers: "file"
+				}
+				surfacer {
+					name: "local-file"
+					type: FILE
+				}`,
+			wantErr: []string{"probe p1: unknown surfacer file"},
+		},
+		{
+			name: "shared_targets",
+			config: `
+				probe {
+					name: "p1"
+					type: EXTERNAL
+					targets {
+						shared_targets: "web"
+					}
+				}
+				probe {
+					name: "p2"
+					type: EXTERNAL
+					targets {
+						shared_targets: "webb"
+					}
+					surfacers: "stackdriver"
+				}
+				shared_targets {
+					name: "web"
+					targets {
+						host_names: "www.google.com"
+					}
+				}`,
+			wantErr: []string{
+				"probe p2: unknown surfacer stackdriver",
+				"probe p2: unknown shared_targets webb",
+			},
+		},
+		{
+			name: "validator_sets",
+			config: `
+				probe {
+					name: "p1"
+					type: EXTERNAL
+					validator_set: "api"
+					validator_set: "apii"
+				}
+				validator_set {
+					name: "api"
+				}`,
+			wantErr: []string{"probe p1: unknown validator_set apii"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, err := configToProto(test.config, "textpb")
+			if err != nil {
+				t.Fatalf("Error parsing test config: %v", err)
+			}
+
+			err = validateReferences(cfg)
+			if len(test.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			if !assert.Error(t, err) {
+				return
+			}
+			for _, s := range test.wantErr {
+				assert.Contains(t, err.Error(), s)
+			}
+		})
+	}
+}
+
+func TestParseConfigInvalidReferences(t *testing.T) {
+	_, _, err := ParseConfig(context.Background(), `
+		probe {
+			name: "p1"
+			type: EXTERNAL
+			surfacers: "fiel"
+		}`, "textpb", nil, nil)
+	assert.ErrorContains(t, err, "probe p1: unknown surfacer fiel")
+}
+
+func TestValidateDurations(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		config  string
+		wantErr []string
+	}{
+		{
+			name:   "textpb_valid",
+			format: "textpb",
+			config: `probe { name: "p1" type: PING interval: "10s" timeout: "0" }`,
+		},
+		{
+			name:    "textpb_bare_number",
+			format:  "textpb",
+			config:  `probe { name: "p1" type: PING interval: "10" timeout: "500" }`,
+			wantErr: []string{`probe p1: interval (10) needs an explicit unit, e.g. "10s"`, `probe p1: timeout (500) needs an explicit unit`},
+		},
+		{
+			name:   "yaml_valid",
+			format: "yaml",
+			config: "probe:\n  - name: p1\n    type: PING\n    interval: 10s\n",
+		},
+		{
+			name:    "yaml_number",
+			format:  "yaml",
+			config:  "probe:\n  - name: p1\n    type: PING\n    interval: 10\n    timeout: 2.5\n",
+			wantErr: []string{`probe p1: interval (10) needs an explicit unit, e.g. "10s"`, `probe p1: timeout (2.5) needs an explicit unit`},
+		},
+		{
+			name:    "yaml_quoted_number",
+			format:  "yaml",
+			config:  "probe:\n  - name: p1\n    type: PING\n    interval: \"10\"\n",
+			wantErr: []string{`probe p1: interval (10) needs an explicit unit`},
+		},
+		{
+			name:    "json_number",
+			format:  "json",
+			config:  `{"probe": [{"name": "p1", "type": "PING", "timeout": 1}]}`,
+			wantErr: []string{`probe p1: timeout (1) needs an explicit unit, e.g. "1s"`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := ParseConfig(context.Background(), test.config, test.format, nil, nil)
+			if len(test.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			for _, want := range test.wantErr {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestConfigTestDurations(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), "cloudprober.yaml")
+	if err := os.WriteFile(cfgFile, []byte("probe:\n  - name: p1\n    type: PING\n    interval: 30\n"), 0644); err != nil {
+		t.Fatalf("Error writing config file: %v", err)
+	}
+	assert.ErrorContains(t, ConfigTest(cfgFile, nil), "probe p1: interval (30) needs an explicit unit")
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	validatorspb "github.com/cloudprober/cloudprober/internal/validators/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// resolveValidatorSets replaces the probes' validator_set references by the
+// validators from the referred sets. Set validators are added before the
+// probe's own validators. References should already be validated using
+// validateReferences.
+func resolveValidatorSets(cfg *configpb.ProberConfig) error {
+	validatorSets := make(map[string]*configpb.ValidatorSet)
+	for _, vs := range cfg.GetValidatorSet() {
+		if validatorSets[vs.GetName()] != nil {
+			return fmt.Errorf("validator_set %s is defined twice", vs.GetName())
+		}
+		validatorSets[vs.GetName()] = vs
+	}
+
+	var errs []error
+	for _, p := range cfg.GetProbe() {
+		if len(p.GetValidatorSet()) == 0 {
+			continue
+		}
+
+		// Validator name to its source, for error messages.
+		sources := make(map[string]string)
+		var validators []*validatorspb.Validator
+		add := func(v *validatorspb.Validator, source string) {
+			if prev, ok := sources[v.GetName()]; ok {
+				errs = append(errs, fmt.Errorf("probe %s: validator %s from %s is already defined in %s", p.GetName(), v.GetName(), source, prev))
+				return
+			}
+			sources[v.GetName()] = source
+			validators = append(validators, v)
+		}
+
+		for _, name := range p.GetValidatorSet() {
+			for _, v := range validatorSets[name].GetValidator() {
+				add(proto.Clone(v).(*validatorspb.Validator), "validator_set "+name)
+			}
+		}
+		for _, v := range p.GetValidator() {
+			add(v, "probe config")
+		}
+
+		p.Validator, p.ValidatorSet = validators, nil
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,130 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveValidatorSets(t *testing.T) {
+	validatorSets := `
+		validator_set {
+			name: "status"
+			validator {
+				name: "status-2xx"
+				http_validator {
+					success_status_codes: "200-299"
+				}
+			}
+		}
+		validator_set {
+			name: "content"
+			validator {
+				name: "has-version"
+				regex: "version"
+			}
+			validator {
+				name: "no-error"
+				regex: "^((?!error).)*$"
+			}
+		}`
+
+	tests := []struct {
+		name           string
+		probe          string
+		wantValidators map[string][]string
+		wantErr        string
+	}{
+		{
+			name: "sets_and_inline",
+			probe: `
+				probe {
+					name: "p1"
+					type: HTTP
+					validator_set: "status"
+					validator_set: "content"
+					validator {
+						name: "inline"
+						regex: "ok"
+					}
+				}
+				probe {
+					name: "p2"
+					type: HTTP
+					validator_set: "content"
+				}
+				probe {
+					name: "p3"
+					type: HTTP
+					validator {
+						name: "inline"
+						regex: "ok"
+					}
+				}`,
+			wantValidators: map[string][]string{
+				"p1": {"status-2xx", "has-version", "no-error", "inline"},
+				"p2": {"has-version", "no-error"},
+				"p3": {"inline"},
+			},
+		},
+		{
+			name: "duplicate_validator",
+			probe: `
+				probe {
+					name: "p1"
+					type: HTTP
+					validator_set: "content"
+					validator {
+						name: "no-error"
+						regex: "ok"
+					}
+				}`,
+			wantErr: "probe p1: validator no-error from probe config is already defined in validator_set content",
+		},
+		{
+			name: "duplicate_set",
+			probe: `
+				validator_set {
+					name: "status"
+				}`,
+			wantErr: "validator_set status is defined twice",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, _, err := ParseConfig(context.Background(), validatorSets+test.probe, "textpb", nil, nil)
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got := make(map[string][]string)
+			for _, p := range cfg.GetProbe() {
+				assert.Empty(t, p.GetValidatorSet(), "probe %s validator_set", p.GetName())
+				for _, v := range p.GetValidator() {
+					got[p.GetName()] = append(got[p.GetName()], v.GetName())
+				}
+			}
+			assert.Equal(t, test.wantValidators, got)
+		})
+	}
+}
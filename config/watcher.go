@@ -0,0 +1,182 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/internal/file"
+	"github.com/cloudprober/cloudprober/logger"
+)
+
+var (
+	configReloadInterval = flag.Duration("config_reload_interval", 0, "If set, config source (config file, remote URL or cloud metadata) is checked for changes at this interval, and the changes are applied without a restart. Local config files are re-read only if their modification time changes, unless they include other files.")
+	configReloadOnSighup = flag.Bool("config_reload_on_sighup", false, "Reload config on SIGHUP, and apply the changes without a restart.")
+)
+
+// ReloadEnabled returns true if config reloading is enabled, through the
+// --config_reload_interval or --config_reload_on_sighup flags.
+func ReloadEnabled() bool {
+	return *configReloadInterval > 0 || *configReloadOnSighup
+}
+
+// Update is a config update from a watchable config source.
+type Update struct {
+	Config *configpb.ProberConfig
+
+	// Content is the config as read from the source, and ParsedConfig is the
+	// config after the template processing, but before the secrets
+	// substitution (see ParseConfig). Format is the config format.
+	Content      string
+	ParsedConfig string
+	Format       string
+}
+
+// Watcher watches the config source used by GetConfig: config file, remote
+// URL, or cloud metadata, for changes. Config is re-read periodically, and
+// on SIGHUP, if enabled.
+type Watcher struct {
+	confFile    string
+	vars        map[string]string
+	interval    time.Duration
+	lastContent string
+	lastModTime time.Time
+	l           *logger.Logger
+
+	// includes is true if the local config file includes other files. Such
+	// files are always re-read, as included files may change independently.
+	includes bool
+
+	// reload triggers a reload, bypassing the modification time check. It's
+	// signaled on SIGHUP.
+	reload chan os.Signal
+	sighup bool
+}
+
+// NewWatcher returns a watcher for the config source used by GetConfig for
+// confFile. content is the currently loaded config content, which is used to
+// detect the changes, and vars are the variables for the config parsing.
+func NewWatcher(confFile, content string, vars map[string]string, l *logger.Logger) *Watcher {
+	w := &Watcher{
+		confFile:    confFile,
+		vars:        vars,
+		interval:    *configReloadInterval,
+		lastContent: content,
+		l:           l,
+		reload:      make(chan os.Signal, 1),
+		sighup:      *configReloadOnSighup,
+	}
+	if mt, ok := w.modTime(); ok {
+		w.lastModTime, w.includes = mt, w.hasIncludes()
+	}
+	return w
+}
+
+// modTime returns the modification time of the local config file. It
+// returns false if config doesn't come from a local file.
+func (w *Watcher) modTime() (time.Time, bool) {
+	src := ConfigSource(w.confFile)
+	if src == "" || file.IsRemote(src) {
+		return time.Time{}, false
+	}
+	mt, err := file.ModTime(src)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return mt, true
+}
+
+// hasIncludes returns true if the local config file has include directives.
+func (w *Watcher) hasIncludes() bool {
+	b, err := file.ReadFile(ConfigSource(w.confFile))
+	return err == nil && includeRe.Match(b)
+}
+
+// check re-reads the config and returns the config update if it has changed.
+// If force is false, local config files that don't include other files are
+// re-read only if their modification time has changed.
+func (w *Watcher) check(ctx context.Context, force bool) (*Update, bool) {
+	mt, local := w.modTime()
+	if local && !force && !w.includes && mt.Equal(w.lastModTime) {
+		return nil, false
+	}
+
+	content, format, err := GetConfig(ctx, w.confFile, w.l)
+	if err != nil {
+		w.l.Errorf("Error re-reading config, keeping the current config: %v", err)
+		return nil, false
+	}
+	// Remember the modification time only after a successful read, so that a
+	// failed read is retried even if the file doesn't change again.
+	if local {
+		w.lastModTime, w.includes = mt, w.hasIncludes()
+	}
+	if content == w.lastContent {
+		return nil, false
+	}
+	// Remember the content even if it doesn't parse, to not keep re-parsing
+	// (and reporting) the same bad config.
+	w.lastContent = content
+
+	cfg, parsedConfig, err := ParseConfig(ctx, content, format, w.vars, w.l)
+	if err != nil {
+		w.l.Errorf("Error parsing the updated config, keeping the current config: %v", err)
+		return nil, false
+	}
+	return &Update{Config: cfg, Content: content, ParsedConfig: parsedConfig, Format: format}, true
+}
+
+// Watch checks the config for changes, and calls f for every new config. It
+// returns when the context is canceled.
+func (w *Watcher) Watch(ctx context.Context, f func(*Update)) {
+	if w.sighup {
+		signal.Notify(w.reload, syscall.SIGHUP)
+		defer signal.Stop(w.reload)
+	}
+
+	var tick <-chan time.Time
+	if w.interval > 0 {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		var force bool
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+		case <-w.reload:
+			w.l.Infof("Received SIGHUP, reloading config.")
+			force = true
+		}
+		if u, ok := w.check(ctx, force); ok {
+			f(u)
+		}
+	}
+}
+
+// Close is a no-op; it's there to implement the same interface as the other
+// watchable config sources.
+func (w *Watcher) Close() error {
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestConfig(t *testing.T, fileName, content string, mt time.Time) {
+	t.Helper()
+	assert.NoError(t, os.WriteFile(fileName, []byte(content), 0644))
+	assert.NoError(t, os.Chtimes(fileName, mt, mt))
+}
+
+func TestWatcherCheck(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), "cloudprober.cfg")
+	mt := time.Now().Add(-time.Hour)
+	content := "probe {\n  name: \"p1\"\n  type: PING\n}\n"
+	writeTestConfig(t, cfgFile, content, mt)
+
+	w := NewWatcher(cfgFile, content, nil, nil)
+
+	_, changed := w.check(context.Background(), false)
+	assert.False(t, changed, "no change")
+
+	// Content changes, but modification time doesn't: change is noticed only
+	// on a forced check.
+	content = "probe {\n  name: \"p2\"\n  type: PING\n}\n"
+	writeTestConfig(t, cfgFile, content, mt)
+	_, changed = w.check(context.Background(), false)
+	assert.False(t, changed, "same modification time")
+	u, changed := w.check(context.Background(), true)
+	assert.True(t, changed, "forced check")
+	assert.Equal(t, "p2", u.Config.GetProbe()[0].GetName())
+	assert.Equal(t, content, u.Content)
+	assert.Equal(t, content, u.ParsedConfig)
+	assert.Equal(t, "textpb", u.Format)
+
+	// Modification time changes, content doesn't.
+	mt = mt.Add(time.Minute)
+	writeTestConfig(t, cfgFile, content, mt)
+	_, changed = w.check(context.Background(), false)
+	assert.False(t, changed, "same content")
+
+	// Bad config is not applied, and it's parsed only once.
+	mt = mt.Add(time.Minute)
+	writeTestConfig(t, cfgFile, "probe {", mt)
+	_, changed = w.check(context.Background(), false)
+	assert.False(t, changed, "bad config")
+	assert.Equal(t, "probe {", w.lastContent)
+
+	// Both content and modification time change.
+	mt = mt.Add(time.Minute)
+	writeTestConfig(t, cfgFile, "probe {\n  name: \"p3\"\n  type: PING\n}\n", mt)
+	u, changed = w.check(context.Background(), false)
+	assert.True(t, changed, "new config")
+	assert.Equal(t, "p3", u.Config.GetProbe()[0].GetName())
+}
+
+func TestWatcherCheckIncludes(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "cloudprober.cfg")
+	mt := time.Now().Add(-time.Hour)
+	content := "probe {\n  name: \"p1\"\n  type: PING\n}\n"
+	writeTestConfig(t, cfgFile, content, mt)
+	w := NewWatcher(cfgFile, content, nil, nil)
+
+	// Main file now includes a file that doesn't exist yet. Read fails, and
+	// it's retried on the next check, even though main file doesn't change.
+	mt = mt.Add(time.Minute)
+	writeTestConfig(t, cfgFile, `{{include "probes.cfg"}}`, mt)
+	_, changed := w.check(context.Background(), false)
+	assert.False(t, changed, "missing included file")
+
+	writeTestConfig(t, filepath.Join(dir, "probes.cfg"), "probe {\n  name: \"p2\"\n  type: PING\n}\n", mt)
+	u, changed := w.check(context.Background(), false)
+	assert.True(t, changed, "included file created")
+	assert.Equal(t, "p2", u.Config.GetProbe()[0].GetName())
+
+	// Edits to the included file are noticed too.
+	writeTestConfig(t, filepath.Join(dir, "probes.cfg"), "probe {\n  name: \"p3\"\n  type: PING\n}\n", mt)
+	u, changed = w.check(context.Background(), false)
+	assert.True(t, changed, "included file edited")
+	assert.Equal(t, "p3", u.Config.GetProbe()[0].GetName())
+}
+
+func TestWatcherWatch(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), "cloudprober.cfg")
+	mt := time.Now().Add(-time.Hour)
+	content := "probe {\n  name: \"p1\"\n  type: PING\n}\n"
+	writeTestConfig(t, cfgFile, content, mt)
+
+	w := NewWatcher(cfgFile, content, nil, nil)
+	w.interval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan *configpb.ProberConfig, 10)
+	go w.Watch(ctx, func(u *Update) { updates <- u.Config })
+
+	writeTestConfig(t, cfgFile, "probe {\n  name: \"p2\"\n  type: PING\n}\n", mt.Add(time.Minute))
+	select {
+	case cfg := <-updates:
+		assert.Equal(t, "p2", cfg.GetProbe()[0].GetName())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the config update")
+	}
+
+	// SIGHUP reloads the config even if modification time hasn't changed.
+	writeTestConfig(t, cfgFile, "probe {\n  name: \"p3\"\n  type: PING\n}\n", mt.Add(time.Minute))
+	w.reload <- syscall.SIGHUP
+	select {
+	case cfg := <-updates:
+		assert.Equal(t, "p3", cfg.GetProbe()[0].GetName())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the config update")
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudprober
+
+import (
+	"context"
+	"embed"
+	"fmt"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/config/schema"
+)
+
+// Proto files are embedded to get the config docs for the schema, as the
+// compiled-in descriptors don't have the comments.
+//
+//go:embed */proto/*.proto */*/proto/*.proto */*/*/proto/*.proto
+var protoSources embed.FS
+
+// ConfigSchema returns the schema of the cloudprober config, in the given
+// format. Currently only "jsonschema" (JSON Schema) format is supported.
+func ConfigSchema(format string) ([]byte, error) {
+	if format != "jsonschema" {
+		return nil, fmt.Errorf("unknown schema format: %s, should be: jsonschema", format)
+	}
+	docs, err := schema.ParseDocs(context.Background(), protoSources, "config/proto/config.proto")
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the config proto files: %v", err)
+	}
+	return schema.JSONSchema((&configpb.ProberConfig{}).ProtoReflect().Descriptor(), docs)
+}
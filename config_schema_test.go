@@ -0,0 +1,41 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudprober
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigSchema(t *testing.T) {
+	_, err := ConfigSchema("cue")
+	assert.Error(t, err)
+
+	b, err := ConfigSchema("jsonschema")
+	assert.NoError(t, err)
+
+	// Make sure that the docs from the embedded proto files, including the
+	// deeper ones, are there.
+	var schema struct {
+		Defs map[string]struct {
+			Description string `json:"description"`
+		} `json:"$defs"`
+	}
+	assert.NoError(t, json.Unmarshal(b, &schema))
+	assert.NotEmpty(t, schema.Defs["cloudprober.ProberConfig"].Description)
+	assert.NotEmpty(t, schema.Defs["cloudprober.surfacer.otel.SurfacerConf"].Description)
+}
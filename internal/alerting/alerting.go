@@ -266,7 +266,14 @@ func (ah *AlertHandler) Record(ep endpoint.Endpoint, em *metrics.EventMetrics) {
 	}
 
 	if totalFailures >= int(ah.condition.Failures) {
-		ah.handleAlertCondition(ts, ep, em.Timestamp, totalFailures)
+		// During a maintenance window we keep tracking the failures, but don't
+		// notify. If target is still failing after the window, it's alerted
+		// as usual.
+		if mw, _ := activeWindow(ah.probeName, ep, em.Timestamp); mw != nil {
+			ah.l.Infof("ALERT (%s): target (%s) is in the maintenance window (%s), not notifying", ah.name, ep.Name, mw.name)
+		} else {
+			ah.handleAlertCondition(ts, ep, em.Timestamp, totalFailures)
+		}
 	} else if ts.alerted {
 		ah.resolveAlertCondition(ts, ep)
 	}
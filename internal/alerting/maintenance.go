@@ -0,0 +1,157 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alerting
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/internal/alerting/proto"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+)
+
+type maintenanceWindow struct {
+	name           string
+	start          time.Time
+	duration       time.Duration
+	repeatInterval time.Duration
+
+	probes       map[string]bool
+	targetRe     *regexp.Regexp
+	targetLabels map[string]string
+}
+
+// Maintenance windows are global, i.e. shared by all the alert handlers.
+var maintenance struct {
+	mu      sync.RWMutex
+	windows []*maintenanceWindow
+}
+
+func newMaintenanceWindow(c *configpb.MaintenanceWindow) (*maintenanceWindow, error) {
+	mw := &maintenanceWindow{
+		name:         c.GetName(),
+		targetLabels: c.GetTargetLabels(),
+	}
+
+	var err error
+	if mw.start, err = time.Parse(time.RFC3339, c.GetStartTime()); err != nil {
+		return nil, fmt.Errorf("invalid start_time (%s): %v", c.GetStartTime(), err)
+	}
+	if mw.duration, err = time.ParseDuration(c.GetDuration()); err != nil || mw.duration <= 0 {
+		return nil, fmt.Errorf("invalid duration (%s), should be a positive duration, e.g. 2h", c.GetDuration())
+	}
+	if c.GetRepeatInterval() != "" {
+		mw.repeatInterval, err = time.ParseDuration(c.GetRepeatInterval())
+		if err != nil || mw.repeatInterval <= mw.duration {
+			return nil, fmt.Errorf("invalid repeat_interval (%s), it should be longer than the duration (%s)", c.GetRepeatInterval(), c.GetDuration())
+		}
+	}
+
+	if len(c.GetProbe()) != 0 {
+		mw.probes = make(map[string]bool)
+		for _, p := range c.GetProbe() {
+			mw.probes[p] = true
+		}
+	}
+	if c.GetTargetRegex() != "" {
+		if mw.targetRe, err = regexp.Compile(c.GetTargetRegex()); err != nil {
+			return nil, fmt.Errorf("invalid target_regex (%s): %v", c.GetTargetRegex(), err)
+		}
+	}
+	return mw, nil
+}
+
+// matches returns true if the given probe's target is in the window's scope.
+func (mw *maintenanceWindow) matches(probeName string, ep endpoint.Endpoint) bool {
+	if mw.probes != nil && !mw.probes[probeName] {
+		return false
+	}
+	if mw.targetRe != nil && !mw.targetRe.MatchString(ep.Name) {
+		return false
+	}
+	for k, v := range mw.targetLabels {
+		if ep.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// active returns true if the window is active at the given time.
+func (mw *maintenanceWindow) active(t time.Time) bool {
+	if t.Before(mw.start) {
+		return false
+	}
+	elapsed := t.Sub(mw.start)
+	if mw.repeatInterval != 0 {
+		elapsed %= mw.repeatInterval
+	}
+	return elapsed < mw.duration
+}
+
+// SetMaintenanceWindows sets the maintenance windows for all the alert
+// handlers, replacing the existing windows.
+func SetMaintenanceWindows(confs []*configpb.MaintenanceWindow) error {
+	var windows []*maintenanceWindow
+	for i, c := range confs {
+		mw, err := newMaintenanceWindow(c)
+		if err != nil {
+			return fmt.Errorf("maintenance window %d (%s): %v", i, c.GetName(), err)
+		}
+		windows = append(windows, mw)
+	}
+
+	maintenance.mu.Lock()
+	defer maintenance.mu.Unlock()
+	maintenance.windows = windows
+	return nil
+}
+
+// activeWindow returns the maintenance window that covers the given probe's
+// target at time t, and whether the target is in the scope of any window at
+// all.
+func activeWindow(probeName string, ep endpoint.Endpoint, t time.Time) (*maintenanceWindow, bool) {
+	maintenance.mu.RLock()
+	defer maintenance.mu.RUnlock()
+
+	var inScope bool
+	for _, mw := range maintenance.windows {
+		if !mw.matches(probeName, ep) {
+			continue
+		}
+		inScope = true
+		if mw.active(t) {
+			return mw, true
+		}
+	}
+	return nil, inScope
+}
+
+// MaintenanceStatus reports whether the given probe's target is in an active
+// maintenance window at time t. inScope is true if the target is in the scope
+// of any of the windows, active or not.
+func MaintenanceStatus(probeName string, ep endpoint.Endpoint, t time.Time) (active, inScope bool) {
+	mw, inScope := activeWindow(probeName, ep, t)
+	return mw != nil, inScope
+}
+
+// HasMaintenanceWindows returns true if any maintenance windows are set.
+func HasMaintenanceWindows() bool {
+	maintenance.mu.RLock()
+	defer maintenance.mu.RUnlock()
+	return len(maintenance.windows) != 0
+}
@@ -0,0 +1,183 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/internal/alerting/alertinfo"
+	configpb "github.com/cloudprober/cloudprober/internal/alerting/proto"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+)
+
+var testWindowStart = time.Date(2023, 11, 5, 2, 0, 0, 0, time.UTC)
+
+func setTestMaintenanceWindows(t *testing.T, confs ...*configpb.MaintenanceWindow) {
+	t.Helper()
+	assert.NoError(t, SetMaintenanceWindows(confs))
+	t.Cleanup(func() { SetMaintenanceWindows(nil) })
+}
+
+func TestNewMaintenanceWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       *configpb.MaintenanceWindow
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			c:    &configpb.MaintenanceWindow{StartTime: "2023-11-05T02:00:00Z", Duration: "2h", RepeatInterval: "168h"},
+		},
+		{
+			name:    "invalid_start_time",
+			c:       &configpb.MaintenanceWindow{StartTime: "2023-11-05 02:00", Duration: "2h"},
+			wantErr: true,
+		},
+		{
+			name:    "no_duration",
+			c:       &configpb.MaintenanceWindow{StartTime: "2023-11-05T02:00:00Z"},
+			wantErr: true,
+		},
+		{
+			name:    "repeat_interval_shorter_than_duration",
+			c:       &configpb.MaintenanceWindow{StartTime: "2023-11-05T02:00:00Z", Duration: "2h", RepeatInterval: "1h"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_target_regex",
+			c:       &configpb.MaintenanceWindow{StartTime: "2023-11-05T02:00:00Z", Duration: "2h", TargetRegex: "db-["},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := newMaintenanceWindow(test.c)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestMaintenanceWindowActive(t *testing.T) {
+	tests := []struct {
+		name           string
+		repeatInterval string
+		at             time.Duration // Relative to the window start.
+		want           bool
+	}{
+		{name: "before_start", at: -time.Minute},
+		{name: "at_start", at: 0, want: true},
+		{name: "in_window", at: 2*time.Hour - time.Second, want: true},
+		{name: "after_window", at: 2 * time.Hour},
+		{name: "after_window_no_repeat", at: 24 * time.Hour},
+		{name: "repeat_in_window", repeatInterval: "24h", at: 49 * time.Hour, want: true},
+		{name: "repeat_out_of_window", repeatInterval: "24h", at: 47 * time.Hour},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mw, err := newMaintenanceWindow(&configpb.MaintenanceWindow{
+				StartTime:      testWindowStart.Format(time.RFC3339),
+				Duration:       "2h",
+				RepeatInterval: test.repeatInterval,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, mw.active(testWindowStart.Add(test.at)))
+		})
+	}
+}
+
+func TestMaintenanceStatus(t *testing.T) {
+	setTestMaintenanceWindows(t,
+		&configpb.MaintenanceWindow{
+			Name:         "db",
+			StartTime:    testWindowStart.Format(time.RFC3339),
+			Duration:     "1h",
+			Probe:        []string{"p1"},
+			TargetLabels: map[string]string{"role": "db"},
+		},
+		&configpb.MaintenanceWindow{
+			Name:        "web",
+			StartTime:   testWindowStart.Add(time.Hour).Format(time.RFC3339),
+			Duration:    "1h",
+			TargetRegex: "^web-",
+		},
+	)
+	assert.True(t, HasMaintenanceWindows())
+
+	dbTarget := endpoint.Endpoint{Name: "db-1", Labels: map[string]string{"role": "db"}}
+	webTarget := endpoint.Endpoint{Name: "web-1"}
+
+	tests := []struct {
+		name                  string
+		probe                 string
+		ep                    endpoint.Endpoint
+		at                    time.Duration
+		wantActive, wantScope bool
+	}{
+		{name: "db_in_window", probe: "p1", ep: dbTarget, wantActive: true, wantScope: true},
+		{name: "db_after_window", probe: "p1", ep: dbTarget, at: time.Hour, wantScope: true},
+		{name: "db_other_probe", probe: "p2", ep: dbTarget},
+		{name: "web_before_window", probe: "p2", ep: webTarget, wantScope: true},
+		{name: "web_in_window", probe: "p2", ep: webTarget, at: time.Hour, wantActive: true, wantScope: true},
+		{name: "unmatched", probe: "p1", ep: endpoint.Endpoint{Name: "cache-1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			active, inScope := MaintenanceStatus(test.probe, test.ep, testWindowStart.Add(test.at))
+			assert.Equal(t, test.wantActive, active, "active")
+			assert.Equal(t, test.wantScope, inScope, "inScope")
+		})
+	}
+}
+
+func TestAlertHandlerMaintenance(t *testing.T) {
+	setTestMaintenanceWindows(t, &configpb.MaintenanceWindow{
+		StartTime: testWindowStart.Format(time.RFC3339),
+		Duration:  "3s",
+	})
+
+	ah, err := NewAlertHandler(&configpb.AlertConf{}, "test-probe", nil)
+	assert.NoError(t, err)
+	ah.notifyCh = make(chan *alertinfo.AlertInfo, 10)
+
+	// Target fails all the time. Alert should fire once the window ends.
+	ep := endpoint.Endpoint{Name: "target-1"}
+	for i := 0; i < 5; i++ {
+		em := metrics.NewEventMetrics(testWindowStart.Add(time.Duration(i) * time.Second))
+		em.AddMetric("total", metrics.NewInt(int64(i+1)))
+		em.AddMetric("success", metrics.NewInt(0))
+		ah.Record(ep, em)
+
+		if i == 2 {
+			assert.False(t, ah.targets[ep.Key()].alerted, "alerted during maintenance")
+			assert.Len(t, ah.notifyCh, 0, "alerts during maintenance")
+		}
+	}
+
+	assert.True(t, ah.targets[ep.Key()].alerted, "alerted after maintenance")
+	assert.Len(t, ah.notifyCh, 1, "alerts after maintenance")
+	if len(ah.notifyCh) == 1 {
+		assert.Equal(t, testWindowStart.Add(3*time.Second), (<-ah.notifyCh).FailingSince)
+	}
+}
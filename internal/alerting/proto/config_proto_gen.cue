@@ -223,3 +223,46 @@ import "github.com/cloudprober/cloudprober/internal/httpreq/proto"
 	// To disable any kind of notification throttling, set this to 0.
 	repeatIntervalSec?: int32 @protobuf(8,int32,name=repeat_interval_sec) // Default: 1hr
 }
+
+// MaintenanceWindow suppresses alert notifications for the matching probes
+// and targets during the scheduled time ranges. Probes continue to run and
+// export metrics as usual. Example:
+//  maintenance_window {
+//    name: "weekly-db-patching"
+//    start_time: "2023-11-05T02:00:00Z"  # A Sunday
+//    duration: "2h"
+//    repeat_interval: "168h"
+//    target_labels {
+//      key: "role"
+//      value: "db"
+//    }
+//  }
+#MaintenanceWindow: {
+	// Name of the maintenance window, used in the logs.
+	name?: string @protobuf(1,string)
+
+	// Start of the (first) window, in RFC 3339 format,
+	// e.g. "2023-11-05T02:00:00Z".
+	startTime?: string @protobuf(2,string,name=start_time)
+
+	// Duration of the window, e.g. "2h".
+	duration?: string @protobuf(3,string)
+
+	// If set, window repeats at this interval after start_time, e.g. "24h"
+	// for a daily window and "168h" for a weekly window. It should be longer
+	// than the duration.
+	repeatInterval?: string @protobuf(4,string,name=repeat_interval)
+	// Scope of the window. Window applies to the targets that match all the
+	// specified selectors; unspecified selectors match everything.
+
+	// Probes that the window applies to.
+	probe?: [...string] @protobuf(5,string)
+
+	// Regex for the target names.
+	targetRegex?: string @protobuf(6,string,name=target_regex)
+
+	// Labels that the targets must have.
+	targetLabels?: {
+		[string]: string
+	} @protobuf(7,map[string]string,target_labels)
+}
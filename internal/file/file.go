@@ -43,25 +43,39 @@ var global = struct {
 	cache: make(map[string]cacheEntry),
 }
 
-type readFunc func(path string) ([]byte, error)
+type readFunc func(ctx context.Context, path string) ([]byte, error)
 type modTimeFunc func(path string) (time.Time, error)
 
 var prefixToReadfunc = map[string]readFunc{
 	"gs://": readFileFromGCS,
+	"s3://": readFileFromS3,
+}
+
+// configPrefixToReadfunc has the additional schemes supported only for the
+// config files (see ReadConfigFile). Other files, e.g. certificates and
+// credentials, are not fetched from arbitrary URLs.
+var configPrefixToReadfunc = map[string]readFunc{
+	"http://":  readFileFromHTTP("http://"),
+	"https://": readFileFromHTTP("https://"),
 }
 
 var prefixToModTimeFunc = map[string]modTimeFunc{
 	"gs://": modTimeGCS,
+	"s3://": modTimeNotImplemented("S3"),
 }
 
-func readFileFromGCS(objectPath string) ([]byte, error) {
-	hc, err := google.DefaultClient(context.Background())
+func readFileFromGCS(ctx context.Context, objectPath string) ([]byte, error) {
+	hc, err := google.DefaultClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	objURL := "https://storage.googleapis.com/" + objectPath
-	res, err := hc.Get(objURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, objURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := hc.Do(req)
 
 	if err != nil {
 		return nil, err
@@ -82,16 +96,33 @@ func modTimeGCS(objectPath string) (time.Time, error) {
 // ReadFile returns file contents as a slice of bytes. It's similar to ioutil's
 // ReadFile, but includes support for files on non-disk locations. For example,
 // files with paths starting with gs:// are assumed to be on GCS, and are read
-// from GCS.
+// from GCS. Similarly, s3:// paths are read from S3.
 func ReadFile(fname string) ([]byte, error) {
+	return ReadFileWithContext(context.Background(), fname)
+}
+
+// ReadFileWithContext is like ReadFile, but remote reads are canceled if ctx
+// is done. Local files are read as usual.
+func ReadFileWithContext(ctx context.Context, fname string) ([]byte, error) {
 	for prefix, f := range prefixToReadfunc {
 		if strings.HasPrefix(fname, prefix) {
-			return f(fname[len(prefix):])
+			return f(ctx, fname[len(prefix):])
 		}
 	}
 	return os.ReadFile(fname)
 }
 
+// ReadConfigFile is like ReadFileWithContext, but it also fetches http:// and
+// https:// paths over HTTP. It's meant for reading the config files.
+func ReadConfigFile(ctx context.Context, fname string) ([]byte, error) {
+	for prefix, f := range configPrefixToReadfunc {
+		if strings.HasPrefix(fname, prefix) {
+			return f(ctx, fname[len(prefix):])
+		}
+	}
+	return ReadFileWithContext(ctx, fname)
+}
+
 func ReadWithCache(fname string, refreshInterval time.Duration) ([]byte, error) {
 	global.mu.RLock()
 	fc, ok := global.cache[fname]
@@ -15,8 +15,12 @@
 package file
 
 import (
+	"context"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -38,7 +42,7 @@ func createTempFile(t *testing.T, b []byte) string {
 	return tmpfile.Name()
 }
 
-func testReadFile(path string) ([]byte, error) {
+func testReadFile(_ context.Context, path string) ([]byte, error) {
 	return []byte("content-for-" + path), nil
 }
 
@@ -108,3 +112,107 @@ func TestReadWithCache(t *testing.T) {
 	time.Sleep(time.Second)
 	readAndVerify(testContent+"-updated-2", 1*time.Second)
 }
+
+func TestReadFileFromHTTP(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); r.URL.Path == "/private.cfg" && (!ok || user != "u" || pass != "p") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/missing.cfg" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("content-for-" + r.URL.Path))
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	b, err := ReadConfigFile(ctx, ts.URL+"/config.cfg")
+	assert.NoError(t, err)
+	assert.Equal(t, "content-for-/config.cfg", string(b))
+
+	_, err = ReadConfigFile(ctx, ts.URL+"/missing.cfg")
+	assert.ErrorContains(t, err, "404")
+
+	_, err = ReadConfigFile(ctx, ts.URL+"/private.cfg")
+	assert.ErrorContains(t, err, "401")
+
+	b, err = ReadConfigFile(ctx, strings.Replace(ts.URL, "http://", "http://u:p@", 1)+"/private.cfg")
+	assert.NoError(t, err)
+	assert.Equal(t, "content-for-/private.cfg", string(b))
+
+	// URLs are fetched only for the config files.
+	_, err = ReadFile(ts.URL + "/config.cfg")
+	assert.Error(t, err)
+
+	defer func(n int64) { maxRemoteFileSize = n }(maxRemoteFileSize)
+	maxRemoteFileSize = 10
+	_, err = ReadConfigFile(ctx, ts.URL+"/config.cfg")
+	assert.ErrorContains(t, err, "larger than the maximum size")
+
+	assert.True(t, IsRemote(ts.URL+"/config.cfg"))
+	assert.False(t, IsRemote("/etc/cloudprober.cfg"))
+}
+
+func TestReadFileFromS3(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test-key-id")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test-secret")
+	t.Setenv("AWS_REGION", "us-west-2")
+	t.Setenv("AWS_CONFIG_FILE", "/nonexistent")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/nonexistent")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=test-key-id/") || !strings.Contains(auth, "/us-west-2/s3/") || r.Header.Get("X-Amz-Content-Sha256") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("content-for-" + r.URL.Path))
+	}))
+	defer ts.Close()
+
+	oldS3ObjectURL := s3ObjectURL
+	defer func() { s3ObjectURL = oldS3ObjectURL }()
+	s3ObjectURL = func(bucket, key, region string) string {
+		return ts.URL + "/" + bucket + "/" + key
+	}
+
+	b, err := ReadFile("s3://test-bucket/configs/cloudprober.cfg")
+	assert.NoError(t, err)
+	assert.Equal(t, "content-for-/test-bucket/configs/cloudprober.cfg", string(b))
+
+	for _, path := range []string{"s3://test-bucket", "s3://test-bucket/", "s3:///key"} {
+		_, err := ReadFile(path)
+		assert.Error(t, err, path)
+	}
+}
+
+func TestS3ObjectURL(t *testing.T) {
+	tests := []struct {
+		bucket, key string
+		want        string
+	}{
+		{
+			bucket: "test-bucket",
+			key:    "configs/cloudprober.cfg",
+			want:   "https://test-bucket.s3.us-west-2.amazonaws.com/configs/cloudprober.cfg",
+		},
+		{
+			bucket: "test-bucket",
+			key:    "my configs/a?b#c.cfg",
+			want:   "https://test-bucket.s3.us-west-2.amazonaws.com/my%20configs/a%3Fb%23c.cfg",
+		},
+		{
+			bucket: "test.example.com",
+			key:    "configs/cloudprober.cfg",
+			want:   "https://s3.us-west-2.amazonaws.com/test.example.com/configs/cloudprober.cfg",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.bucket+"/"+test.key, func(t *testing.T) {
+			assert.Equal(t, test.want, s3ObjectURL(test.bucket, test.key, "us-west-2"))
+		})
+	}
+}
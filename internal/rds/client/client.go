@@ -73,6 +73,11 @@ type Client struct {
 	lastModified  int64
 	resolver      *dnsRes.Resolver
 	l             *logger.Logger
+
+	// lastRefreshed is the time of the last successful refresh, and stale
+	// is set when the last refresh failed.
+	lastRefreshed time.Time
+	stale         bool
 }
 
 // ListResourcesFunc is a function that takes ListResourcesRequest and returns
@@ -87,14 +92,62 @@ func (client *Client) refreshState(timeout time.Duration) {
 	req := client.c.GetRequest()
 	req.IfModifiedSince = proto.Int64(client.lastModified)
 
-	response, err := client.listResources(ctx, req)
+	response, err := client.listResourcesWithRetry(ctx, req)
 	if err != nil {
 		client.l.Errorf("rds.client: error getting resources from RDS server: %v", err)
+		client.markStale()
 		return
 	}
 	client.updateState(response)
 }
 
+// listResourcesWithRetry calls listResources, retrying failed calls with
+// exponential backoff until max_retries or context's deadline.
+func (client *Client) listResourcesWithRetry(ctx context.Context, req *pb.ListResourcesRequest) (*pb.ListResourcesResponse, error) {
+	backoff := time.Duration(client.c.GetRetryInitialBackoffMsec()) * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		response, err := client.listResources(ctx, req)
+		if err == nil || attempt >= int(client.c.GetMaxRetries()) {
+			return response, err
+		}
+		client.l.Warningf("rds.client: error getting resources from RDS server: %v, retrying in %v", err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// markStale marks the client state stale after a failed refresh. Stale
+// resources are kept until they are older than max_staleness_sec.
+func (client *Client) markStale() {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	client.stale = true
+	maxStaleness := time.Duration(client.c.GetMaxStalenessSec()) * time.Second
+	if maxStaleness <= 0 || len(client.names) == 0 || time.Since(client.lastRefreshed) <= maxStaleness {
+		return
+	}
+
+	client.l.Warningf("rds.client: resources not refreshed since %s, more than max staleness (%v), dropping them.", client.lastRefreshed.Format(time.RFC3339), maxStaleness)
+	client.names = nil
+	client.cache = make(map[string]*cacheRecord)
+	// Reset last-modified so that the next response is not skipped.
+	client.lastModified = 0
+}
+
+// Stale reports whether the last resources refresh failed, i.e. the client
+// is serving the last successfully discovered resources.
+func (client *Client) Stale() bool {
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+	return client.stale
+}
+
 func parseIP(ipStr string) net.IP {
 	if strings.Contains(ipStr, "/") {
 		ip, _, err := net.ParseCIDR(ipStr)
@@ -110,6 +163,8 @@ func (client *Client) updateState(response *pb.ListResourcesResponse) {
 	client.mu.Lock()
 	defer client.mu.Unlock()
 
+	client.lastRefreshed, client.stale = time.Now(), false
+
 	// If server doesn't support caching, response's last_modified will be 0 and
 	// we'll skip the following block.
 	if response.GetLastModified() != 0 && response.GetLastModified() <= client.lastModified {
@@ -412,3 +412,64 @@ func TestOnDemandRefresh(t *testing.T) {
 	runCount++
 	tp.verifyRequestResponse(t, runCount, 0, 0)
 }
+
+func TestRefreshFailures(t *testing.T) {
+	srv := setupTestServer(context.Background(), t, map[string][]*pb.Resource{
+		testProviderName: testResources,
+	})
+
+	// listResources fails the next "failures" calls.
+	var calls, failures int
+	listResources := func(ctx context.Context, req *pb.ListResourcesRequest) (*pb.ListResourcesResponse, error) {
+		calls++
+		if failures > 0 {
+			failures--
+			return nil, fmt.Errorf("server unavailable")
+		}
+		return srv.ListResources(ctx, req)
+	}
+
+	c := &configpb.ClientConf{
+		Request: &pb.ListResourcesRequest{
+			Provider: proto.String(testProviderName),
+		},
+		ReEvalSec:               proto.Int32(0),
+		MaxRetries:              proto.Int32(2),
+		RetryInitialBackoffMsec: proto.Int32(1),
+		MaxStalenessSec:         proto.Int32(60),
+	}
+	client, err := New(c, listResources, &logger.Logger{})
+	if err != nil {
+		t.Fatalf("Got error initializing RDS client: %v", err)
+	}
+
+	client.refreshState(time.Second)
+	verifyEndpoints(t, client.ListEndpoints(), expectedList)
+	assert.False(t, client.Stale(), "stale after successful refresh")
+
+	// Failures within retries are not visible.
+	calls, failures = 0, 2
+	client.refreshState(time.Second)
+	assert.Equal(t, 3, calls, "listResources calls")
+	assert.False(t, client.Stale(), "stale after successful retry")
+
+	// Continued failures: resources are retained until max staleness.
+	calls, failures = 0, 100
+	client.refreshState(time.Second)
+	assert.Equal(t, 3, calls, "listResources calls")
+	assert.True(t, client.Stale(), "stale after failed refresh")
+	assert.Len(t, client.names, len(expectedList))
+
+	client.mu.Lock()
+	client.lastRefreshed = time.Now().Add(-61 * time.Second)
+	client.mu.Unlock()
+	client.refreshState(time.Second)
+	assert.True(t, client.Stale(), "stale after failed refresh")
+	assert.Empty(t, client.names, "resources after max staleness")
+
+	// Recovery
+	failures = 0
+	client.refreshState(time.Second)
+	assert.False(t, client.Stale(), "stale after recovery")
+	assert.Len(t, client.names, len(expectedList))
+}
@@ -7,7 +7,7 @@ import (
 )
 
 // ClientConf represents resource discovery service (RDS) based targets.
-// Next tag: 6
+// Next tag: 9
 #ClientConf: {
 
 	#ServerOptions: {
@@ -38,4 +38,15 @@ import (
 	// (specifically GCE instances/forwarding rules). This does not impact those
 	// caches.
 	reEvalSec?: int32 @protobuf(3,int32,name=re_eval_sec,"default=30")
+
+	// Number of times a failed resources refresh is retried, with exponential
+	// backoff starting at retry_initial_backoff_msec. Retries stop when the
+	// refresh times out (at re_eval_sec).
+	maxRetries?:              int32 @protobuf(6,int32,name=max_retries,"default=2")
+	retryInitialBackoffMsec?: int32 @protobuf(7,int32,name=retry_initial_backoff_msec,"default=500")
+
+	// If resources refresh keeps failing, last successfully discovered
+	// resources are retained for this long, after which they are dropped. 0
+	// means that they are retained until the next successful refresh.
+	maxStalenessSec?: int32 @protobuf(8,int32,name=max_staleness_sec)
 }
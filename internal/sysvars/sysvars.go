@@ -21,6 +21,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -32,6 +33,9 @@ import (
 	"github.com/cloudprober/cloudprober/metrics"
 )
 
+// buildInfoMetricName is the name of the metric that reports build info.
+const buildInfoMetricName = "cloudprober_build_info"
+
 var (
 	sysVarsMu sync.RWMutex
 	sysVars   map[string]string
@@ -39,14 +43,45 @@ var (
 	startTime time.Time
 )
 
-var cloudMetadataFlag = flag.String("cloud_metadata", "auto", "Collect cloud metadata for [auto|gce|ec2|none]")
+var cloudMetadataFlag = flag.String("cloud_metadata", "auto", "Collect cloud metadata for [auto|gce|ec2|azure|none]")
 
 var cloudProviders = struct {
-	auto, gce, ec2 string
+	auto, gce, ec2, azure string
 }{
-	auto: "auto",
-	gce:  "gce",
-	ec2:  "ec2",
+	auto:  "auto",
+	gce:   "gce",
+	ec2:   "ec2",
+	azure: "azure",
+}
+
+// CloudVarKeys are the variables that are always set, irrespective of the
+// cloud provider, so that configs can use them without checking the
+// environment they are running in. Off-cloud, these variables are empty.
+//
+//	cloud_provider: gce, ec2 or azure.
+//	region:         region of the instance, e.g. us-central1, us-east-1, eastus.
+//	zone:           zone of the instance, e.g. us-central1-a, us-east-1a, 1.
+//	instance_id:    provider assigned ID of the instance.
+var CloudVarKeys = []string{"cloud_provider", "region", "zone", "instance_id"}
+
+// cloudVarsSource maps the common cloud variables to the provider specific
+// variables they are populated from.
+var cloudVarsSource = map[string]map[string]string{
+	cloudProviders.gce: {
+		"region":      "region",
+		"zone":        "zone",
+		"instance_id": "instance_id",
+	},
+	cloudProviders.ec2: {
+		"region":      "EC2_Region",
+		"zone":        "EC2_AvailabilityZone",
+		"instance_id": "EC2_InstanceID",
+	},
+	cloudProviders.azure: {
+		"region":      "Azure_Location",
+		"zone":        "Azure_Zone",
+		"instance_id": "Azure_VMID",
+	},
 }
 
 // Vars returns a copy of the system variables map, if already initialized.
@@ -97,39 +132,62 @@ func providersToCheck(fv string) []string {
 	}
 	// Update this list when we add new providers
 	if fv == cloudProviders.auto {
-		return []string{cloudProviders.gce, cloudProviders.ec2}
+		return []string{cloudProviders.gce, cloudProviders.ec2, cloudProviders.azure}
 	}
 	return []string{fv}
 }
 
-func initCloudMetadata(fv string) error {
+// initCloudMetadata adds the cloud metadata to sysVars and returns the cloud
+// provider that we are running on, or an empty string if we are not running
+// on one of the supported cloud providers.
+func initCloudMetadata(fv string) (string, error) {
 	for _, provider := range providersToCheck(fv) {
 		switch provider {
 		case cloudProviders.gce:
 			onGCE, err := gceVars(sysVars, l)
 			// Once we know it's GCE, don't continue checking.
 			if onGCE {
-				return err
+				return provider, err
 			}
 		case cloudProviders.ec2:
 			tryHard := fv == cloudProviders.ec2
 			onEC2, err := ec2Vars(sysVars, tryHard, l)
 			// Once we know it's EC2, don't continue checking.
 			if onEC2 {
-				return err
+				return provider, err
+			}
+		case cloudProviders.azure:
+			tryHard := fv == cloudProviders.azure
+			onAzure, err := azureVars(sysVars, tryHard, l)
+			if onAzure {
+				return provider, err
 			}
 		default:
-			return fmt.Errorf("unknown cloud provider: %v", provider)
+			return "", fmt.Errorf("unknown cloud provider: %v", provider)
 		}
 	}
-	return nil
+	return "", nil
+}
+
+// setCloudVars sets the common cloud variables (CloudVarKeys) in vars, using
+// the provider specific variables. All of them are set, even if provider is
+// empty.
+func setCloudVars(vars map[string]string, provider string) {
+	vars["cloud_provider"] = provider
+	for _, k := range CloudVarKeys[1:] {
+		var v string
+		if src := cloudVarsSource[provider][k]; src != "" {
+			v = vars[src]
+		}
+		vars[k] = v
+	}
 }
 
 // Init initializes the sysvars module's global data structure. Init makes sure
 // to initialize only once, further calls are a no-op. If needed, userVars
 // can be passed to Init to add custom variables to sysVars. This can be useful
 // for tests which require sysvars that might not exist, or might have the wrong
-// value.
+// value. Variables set through the --var flag override userVars.
 func Init(ll *logger.Logger, userVars map[string]string) error {
 	sysVarsMu.Lock()
 	defer sysVarsMu.Unlock()
@@ -149,16 +207,35 @@ func Init(ll *logger.Logger, userVars map[string]string) error {
 	}
 	sysVars["hostname"] = hostname
 
-	if err := initCloudMetadata(*cloudMetadataFlag); err != nil {
+	provider, err := initCloudMetadata(*cloudMetadataFlag)
+	if err != nil {
 		return err
 	}
+	setCloudVars(sysVars, provider)
 
 	for k, v := range userVars {
 		sysVars[k] = v
 	}
+	for k, v := range cmdLineVars {
+		sysVars[k] = v
+	}
 	return nil
 }
 
+// buildInfoEM returns the EventMetrics that reports cloudprober's build info
+// through the labels of a constant (1) metric.
+func buildInfoEM(ts time.Time) *metrics.EventMetrics {
+	em := metrics.NewEventMetrics(ts).
+		AddMetric(buildInfoMetricName, metrics.NewInt(1)).
+		AddLabel("ptype", "sysvars").
+		AddLabel("probe", "sysvars").
+		AddLabel("version", runconfig.Version()).
+		AddLabel("commit", runconfig.BuildCommit()).
+		AddLabel("go_version", runtime.Version())
+	em.Kind = metrics.GAUGE
+	return em
+}
+
 // Start exports system variables at the given interval. It overlays variables with
 // variables passed through the envVarsName env variable.
 func Start(ctx context.Context, dataChan chan *metrics.EventMetrics, interval time.Duration, envVarsName string) {
@@ -196,6 +273,7 @@ func Start(ctx context.Context, dataChan chan *metrics.EventMetrics, interval ti
 		em.Timestamp = ts
 		dataChan <- em.Clone()
 		l.Debug(em.String())
+		dataChan <- buildInfoEM(ts)
 
 		runtimeVars(dataChan, l)
 	}
@@ -0,0 +1,84 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysvars
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cloudprober/cloudprober/logger"
+)
+
+// azureMetadataURL is the Azure Instance Metadata Service (IMDS) endpoint for
+// the compute metadata.
+var azureMetadataURL = "http://169.254.169.254/metadata/instance/compute?api-version=2021-02-01&format=json"
+
+type azureComputeMetadata struct {
+	Location          string `json:"location"`
+	Zone              string `json:"zone"`
+	VMID              string `json:"vmId"`
+	Name              string `json:"name"`
+	VMSize            string `json:"vmSize"`
+	ResourceGroupName string `json:"resourceGroupName"`
+	SubscriptionID    string `json:"subscriptionId"`
+}
+
+var azureVars = func(sysVars map[string]string, tryHard bool, l *logger.Logger) (bool, error) {
+	ctx := context.Background()
+
+	// If not trying hard (cloud_metadata != azure), use shorter timeout.
+	timeout := 100 * time.Millisecond
+	if tryHard {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, azureMetadataURL, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Metadata", "true")
+
+	resp, err := http.DefaultClient.Do(req)
+	// Similar to EC2, we use the error here to decide if we are running on
+	// Azure or not.
+	if err != nil {
+		return false, fmt.Errorf("sysvars_azure: could not get instance metadata: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("sysvars_azure: could not get instance metadata, status: %s", resp.Status)
+	}
+
+	var md azureComputeMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&md); err != nil {
+		return false, fmt.Errorf("sysvars_azure: error parsing instance metadata: %v", err)
+	}
+
+	sysVars["Azure_METADATA_Available"] = "true"
+	sysVars["Azure_Location"] = md.Location
+	sysVars["Azure_Zone"] = md.Zone
+	sysVars["Azure_VMID"] = md.VMID
+	sysVars["Azure_Name"] = md.Name
+	sysVars["Azure_VMSize"] = md.VMSize
+	sysVars["Azure_ResourceGroupName"] = md.ResourceGroupName
+	sysVars["Azure_SubscriptionID"] = md.SubscriptionID
+	return true, nil
+}
@@ -17,18 +17,25 @@ package sysvars
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"runtime"
 	"testing"
+	"time"
 
+	"github.com/cloudprober/cloudprober/config/runconfig"
 	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/metrics"
 )
 
 func TestProvidersToCheck(t *testing.T) {
 	flagToProviders := map[string][]string{
-		"auto": {"gce", "ec2"},
-		"gce":  {"gce"},
-		"ec2":  {"ec2"},
-		"none": nil,
+		"auto":  {"gce", "ec2", "azure"},
+		"gce":   {"gce"},
+		"ec2":   {"ec2"},
+		"azure": {"azure"},
+		"none":  nil,
 	}
 
 	for flagValue, expected := range flagToProviders {
@@ -51,6 +58,11 @@ var testEC2Vars = map[string]string{
 	"zone":     "ec2-zone-1",
 }
 
+var testAzureVars = map[string]string{
+	"platform": "azure",
+	"zone":     "azure-zone-1",
+}
+
 func testSetVars(vars, inVars map[string]string, onPlatform bool) (bool, error) {
 	if !onPlatform {
 		return onPlatform, nil
@@ -65,21 +77,34 @@ func TestInitCloudMetadata(t *testing.T) {
 	sysVars = map[string]string{}
 
 	tests := []struct {
-		mode         string
-		onGCE, onEC2 bool
-		expected     map[string]string
+		mode                  string
+		onGCE, onEC2, onAzure bool
+		expected              map[string]string
+		wantProvider          string
 	}{
 		{
-			mode:     "auto",
-			onGCE:    true,
-			onEC2:    true,
-			expected: testGCEVars,
+			mode:         "auto",
+			onGCE:        true,
+			onEC2:        true,
+			expected:     testGCEVars,
+			wantProvider: "gce",
+		},
+		{
+			mode:         "auto",
+			onGCE:        false,
+			onEC2:        true,
+			expected:     testEC2Vars,
+			wantProvider: "ec2",
+		},
+		{
+			mode:         "auto",
+			onAzure:      true,
+			expected:     testAzureVars,
+			wantProvider: "azure",
 		},
 		{
 			mode:     "auto",
-			onGCE:    false,
-			onEC2:    true,
-			expected: testEC2Vars,
+			expected: map[string]string{},
 		},
 		{
 			mode:     "gce",
@@ -94,13 +119,26 @@ func TestInitCloudMetadata(t *testing.T) {
 			expected: map[string]string{},
 		},
 		{
-			mode:     "ec2", // Get EC2 metadata
-			onGCE:    true,
-			onEC2:    true,
-			expected: testEC2Vars,
+			mode:         "ec2", // Get EC2 metadata
+			onGCE:        true,
+			onEC2:        true,
+			expected:     testEC2Vars,
+			wantProvider: "ec2",
+		},
+		{
+			mode:         "azure",
+			onGCE:        true,
+			onAzure:      true,
+			expected:     testAzureVars,
+			wantProvider: "azure",
 		},
 	}
 
+	origGCEVars, origEC2Vars, origAzureVars := gceVars, ec2Vars, azureVars
+	defer func() {
+		gceVars, ec2Vars, azureVars = origGCEVars, origEC2Vars, origAzureVars
+	}()
+
 	for _, test := range tests {
 		t.Run(fmt.Sprintf("%v", test), func(t *testing.T) {
 			sysVars = map[string]string{}
@@ -111,10 +149,17 @@ func TestInitCloudMetadata(t *testing.T) {
 			ec2Vars = func(vars map[string]string, tryHard bool, l *logger.Logger) (bool, error) {
 				return testSetVars(vars, testEC2Vars, test.onEC2)
 			}
+			azureVars = func(vars map[string]string, tryHard bool, l *logger.Logger) (bool, error) {
+				return testSetVars(vars, testAzureVars, test.onAzure)
+			}
 
-			if err := initCloudMetadata(test.mode); err != nil {
+			provider, err := initCloudMetadata(test.mode)
+			if err != nil {
 				t.Errorf("Got unexpected error: %v", err)
 			}
+			if provider != test.wantProvider {
+				t.Errorf("provider=%s, expected=%s", provider, test.wantProvider)
+			}
 			if !reflect.DeepEqual(sysVars, test.expected) {
 				t.Errorf("sysVars=%v, expected=%v", sysVars, test.expected)
 			}
@@ -122,6 +167,86 @@ func TestInitCloudMetadata(t *testing.T) {
 	}
 }
 
+func TestSetCloudVars(t *testing.T) {
+	tests := []struct {
+		provider string
+		vars     map[string]string
+		expected map[string]string
+	}{
+		{
+			provider: "",
+			vars:     map[string]string{"hostname": "host1"},
+			expected: map[string]string{"hostname": "host1", "cloud_provider": "", "region": "", "zone": "", "instance_id": ""},
+		},
+		{
+			provider: "gce",
+			vars:     map[string]string{"region": "us-central1", "zone": "us-central1-a", "instance_id": "123"},
+			expected: map[string]string{"cloud_provider": "gce", "region": "us-central1", "zone": "us-central1-a", "instance_id": "123"},
+		},
+		{
+			// On GKE, zone and region are not set.
+			provider: "gce",
+			vars:     map[string]string{"instance_id": "123"},
+			expected: map[string]string{"cloud_provider": "gce", "region": "", "zone": "", "instance_id": "123"},
+		},
+		{
+			provider: "ec2",
+			vars:     map[string]string{"EC2_Region": "us-east-1", "EC2_AvailabilityZone": "us-east-1a", "EC2_InstanceID": "i-123"},
+			expected: map[string]string{"EC2_Region": "us-east-1", "EC2_AvailabilityZone": "us-east-1a", "EC2_InstanceID": "i-123", "cloud_provider": "ec2", "region": "us-east-1", "zone": "us-east-1a", "instance_id": "i-123"},
+		},
+		{
+			provider: "azure",
+			vars:     map[string]string{"Azure_Location": "eastus", "Azure_Zone": "1", "Azure_VMID": "vm-123"},
+			expected: map[string]string{"Azure_Location": "eastus", "Azure_Zone": "1", "Azure_VMID": "vm-123", "cloud_provider": "azure", "region": "eastus", "zone": "1", "instance_id": "vm-123"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.provider, func(t *testing.T) {
+			setCloudVars(test.vars, test.provider)
+			if !reflect.DeepEqual(test.vars, test.expected) {
+				t.Errorf("vars=%v, expected=%v", test.vars, test.expected)
+			}
+		})
+	}
+}
+
+func TestAzureVars(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" || r.Header.Get("Metadata") != "true" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"location":"eastus","zone":"1","vmId":"vm-123","name":"vm1","vmSize":"Standard_B1s","resourceGroupName":"rg1","subscriptionId":"sub-1"}`))
+	}))
+	defer ts.Close()
+
+	defer func(url string) { azureMetadataURL = url }(azureMetadataURL)
+	azureMetadataURL = ts.URL
+
+	vars := map[string]string{}
+	onAzure, err := azureVars(vars, true, nil)
+	if err != nil || !onAzure {
+		t.Fatalf("azureVars()=%v, %v, expected=true, nil", onAzure, err)
+	}
+	for k, expected := range map[string]string{
+		"Azure_Location": "eastus",
+		"Azure_Zone":     "1",
+		"Azure_VMID":     "vm-123",
+		"Azure_Name":     "vm1",
+		"Azure_VMSize":   "Standard_B1s",
+	} {
+		if vars[k] != expected {
+			t.Errorf("%s=%s, expected=%s", k, vars[k], expected)
+		}
+	}
+
+	azureMetadataURL = ts.URL + "/notfound"
+	if onAzure, _ := azureVars(map[string]string{}, true, nil); onAzure {
+		t.Errorf("azureVars() returned true for a non-OK response")
+	}
+}
+
 func TestLoadAWSConfig(t *testing.T) {
 	// The aws.Config struct that is returned from loadAWSConfig
 	// is partially complete, which means the testing done around
@@ -160,3 +285,30 @@ func TestLoadAWSConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildInfoEM(t *testing.T) {
+	runconfig.SetVersion("v1.2.3")
+	runconfig.SetBuildCommit("abcdef0")
+	defer func() {
+		runconfig.SetVersion("")
+		runconfig.SetBuildCommit("")
+	}()
+
+	em := buildInfoEM(time.Now())
+	if em.Kind != metrics.GAUGE {
+		t.Errorf("buildInfoEM kind=%v, expected=%v", em.Kind, metrics.GAUGE)
+	}
+	if v := em.Metric(buildInfoMetricName).String(); v != "1" {
+		t.Errorf("%s=%s, expected=1", buildInfoMetricName, v)
+	}
+	for k, expected := range map[string]string{
+		"probe":      "sysvars",
+		"version":    "v1.2.3",
+		"commit":     "abcdef0",
+		"go_version": runtime.Version(),
+	} {
+		if got := em.Label(k); got != expected {
+			t.Errorf("label %s=%s, expected=%s", k, got, expected)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysvars
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// varsFlag implements flag.Value for a repeatable key=value flag.
+type varsFlag map[string]string
+
+func (vf varsFlag) String() string {
+	var kvs []string
+	for k, v := range vf {
+		kvs = append(kvs, k+"="+v)
+	}
+	sort.Strings(kvs)
+	return strings.Join(kvs, ",")
+}
+
+func (vf varsFlag) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("invalid variable %q, should be of the form key=value", s)
+	}
+	vf[k] = v
+	return nil
+}
+
+// cmdLineVars are the variables set through the --var flag. They override
+// all other variables, including the ones passed to Init.
+var cmdLineVars = varsFlag{}
+
+func init() {
+	flag.Var(cmdLineVars, "var", "Config template variable, as key=value, e.g. --var env=prod. Repeat the flag for multiple variables. These variables override system and cloud metadata variables.")
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysvars
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVarsFlag(t *testing.T) {
+	vf := varsFlag{}
+	for _, s := range []string{"env=prod", "region=us", "empty=", "url=http://a/?b=c", "env=staging"} {
+		if err := vf.Set(s); err != nil {
+			t.Errorf("Set(%q) returned error: %v", s, err)
+		}
+	}
+	want := varsFlag{"env": "staging", "region": "us", "empty": "", "url": "http://a/?b=c"}
+	if !reflect.DeepEqual(vf, want) {
+		t.Errorf("vars=%v, want=%v", vf, want)
+	}
+	if got, wantStr := vf.String(), "empty=,env=staging,region=us,url=http://a/?b=c"; got != wantStr {
+		t.Errorf("String()=%q, want=%q", got, wantStr)
+	}
+
+	for _, s := range []string{"env", "=prod", ""} {
+		if err := vf.Set(s); err == nil {
+			t.Errorf("Set(%q) didn't return error", s)
+		}
+	}
+}
+
+func TestInitWithCmdLineVars(t *testing.T) {
+	oldSysVars, oldCloudMetadata, oldCmdLineVars := sysVars, *cloudMetadataFlag, cmdLineVars
+	defer func() {
+		sysVars, *cloudMetadataFlag, cmdLineVars = oldSysVars, oldCloudMetadata, oldCmdLineVars
+	}()
+
+	sysVars, *cloudMetadataFlag = nil, "none"
+	cmdLineVars = varsFlag{"env": "prod", "zone": "cmdline-zone"}
+
+	if err := Init(nil, map[string]string{"env": "test", "team": "sre"}); err != nil {
+		t.Fatalf("Init() returned error: %v", err)
+	}
+	for k, want := range map[string]string{"env": "prod", "zone": "cmdline-zone", "team": "sre"} {
+		if got := sysVars[k]; got != want {
+			t.Errorf("sysVars[%q]=%q, want=%q", k, got, want)
+		}
+	}
+}
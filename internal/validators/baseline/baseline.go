@@ -0,0 +1,265 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package baseline provides a validator that learns a baseline distribution
+// of a response characteristic, and flags the deviations from it.
+package baseline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/internal/validators/baseline/proto"
+	"github.com/cloudprober/cloudprober/internal/validators/threshold"
+	thresholdpb "github.com/cloudprober/cloudprober/internal/validators/threshold/proto"
+	"github.com/cloudprober/cloudprober/logger"
+)
+
+const (
+	defaultWarmupSamples = 100
+	defaultMaxDeviation  = 3
+)
+
+// baseline keeps the running mean and variance of the samples, using
+// Welford's algorithm.
+type baseline struct {
+	Count int64   `json:"count"`
+	Mean  float64 `json:"mean"`
+	M2    float64 `json:"m2"`
+}
+
+func (b *baseline) add(x float64) {
+	b.Count++
+	d := x - b.Mean
+	b.Mean += d / float64(b.Count)
+	b.M2 += d * (x - b.Mean)
+}
+
+func (b *baseline) stddev() float64 {
+	if b.Count < 2 {
+		return 0
+	}
+	return math.Sqrt(b.M2 / float64(b.Count-1))
+}
+
+// deviation returns the deviation of x from the mean, in standard
+// deviations. If all the baseline samples were the same, any other value is
+// an infinite deviation.
+func (b *baseline) deviation(x float64) float64 {
+	diff := math.Abs(x - b.Mean)
+	sd := b.stddev()
+	if sd == 0 {
+		if diff == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return diff / sd
+}
+
+// Validator implements a baseline validator.
+type Validator struct {
+	metric        configpb.Validator_Metric
+	extractor     *threshold.Validator
+	warmupSamples int64
+	maxDeviation  float64
+	file          string
+	l             *logger.Logger
+
+	mu sync.Mutex
+	b  baseline
+}
+
+// Init initializes the baseline validator.
+func (v *Validator) Init(config interface{}, l *logger.Logger) error {
+	c, ok := config.(*configpb.Validator)
+	if !ok {
+		return fmt.Errorf("%v is not a valid baseline validator config", config)
+	}
+
+	v.metric = c.GetMetric()
+	if v.metric == configpb.Validator_VALUE {
+		tc := &thresholdpb.Validator{}
+		switch c.GetExtractor().(type) {
+		case *configpb.Validator_JqFilter:
+			tc.Extractor = &thresholdpb.Validator_JqFilter{JqFilter: c.GetJqFilter()}
+		case *configpb.Validator_Regex:
+			tc.Extractor = &thresholdpb.Validator_Regex{Regex: c.GetRegex()}
+		default:
+			return errors.New("baseline validator: one of jq_filter or regex is required for the VALUE metric")
+		}
+		v.extractor = &threshold.Validator{}
+		if err := v.extractor.Init(tc, l); err != nil {
+			return fmt.Errorf("baseline validator: %v", err)
+		}
+	} else if c.GetExtractor() != nil {
+		return fmt.Errorf("baseline validator: jq_filter and regex are supported only for the VALUE metric, not for %s", v.metric)
+	}
+
+	if c.GetWarmupSamples() < 0 || c.GetMaxDeviation() < 0 {
+		return errors.New("baseline validator: warmup_samples and max_deviation cannot be negative")
+	}
+	v.warmupSamples = int64(c.GetWarmupSamples())
+	if v.warmupSamples == 0 {
+		v.warmupSamples = defaultWarmupSamples
+	}
+	v.maxDeviation = c.GetMaxDeviation()
+	if v.maxDeviation == 0 {
+		v.maxDeviation = defaultMaxDeviation
+	}
+
+	v.l = l
+	v.file = c.GetBaselineFile()
+	if v.file != "" {
+		if err := v.load(); err != nil {
+			return fmt.Errorf("baseline validator: %v", err)
+		}
+	}
+	return nil
+}
+
+// load loads the baseline from the baseline file, if the file exists.
+func (v *Validator) load() error {
+	data, err := os.ReadFile(v.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := json.Unmarshal(data, &v.b); err != nil {
+		return fmt.Errorf("error parsing the baseline file (%s): %v", v.file, err)
+	}
+	v.l.Infof("Loaded baseline from %s: mean=%v, stddev=%v, samples=%d", v.file, v.b.Mean, v.b.stddev(), v.b.Count)
+	return nil
+}
+
+// save saves the baseline to the baseline file. File is written atomically,
+// through a temporary file in the same directory.
+func (v *Validator) save() error {
+	data, err := json.Marshal(v.b)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(v.file), filepath.Base(v.file)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), v.file)
+}
+
+// Sample returns the characteristic tracked by the validator, from the
+// provided responseBody and latency.
+func (v *Validator) Sample(responseBody []byte, latency time.Duration) (float64, error) {
+	switch v.metric {
+	case configpb.Validator_LATENCY:
+		if latency <= 0 {
+			return 0, errors.New("latency is not available")
+		}
+		return float64(latency) / float64(time.Millisecond), nil
+	case configpb.Validator_VALUE:
+		return v.extractor.Value(responseBody)
+	default:
+		return float64(len(responseBody)), nil
+	}
+}
+
+// Learned returns true if the baseline has been learned, i.e. the warmup is
+// over.
+func (v *Validator) Learned() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.b.Count >= v.warmupSamples
+}
+
+// Validate returns false if the tracked characteristic deviates from the
+// baseline by more than the max deviation, or if it cannot be determined.
+// During the warmup, it adds the characteristic to the baseline and always
+// returns true.
+func (v *Validator) Validate(responseBody []byte, latency time.Duration) (bool, error) {
+	return v.ValidateSample(v.Sample(responseBody, latency))
+}
+
+// ValidateSample is like Validate, but for an already extracted sample and
+// the error from its extraction, as returned by Sample.
+func (v *Validator) ValidateSample(x float64, sampleErr error) (bool, error) {
+	if sampleErr != nil {
+		v.l.Warningf("Baseline validation failure: %v", sampleErr)
+		return false, nil
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.b.Count < v.warmupSamples {
+		v.b.add(x)
+		if v.b.Count == v.warmupSamples {
+			v.l.Infof("Learned baseline: mean=%v, stddev=%v, samples=%d", v.b.Mean, v.b.stddev(), v.b.Count)
+			if v.file != "" {
+				if err := v.save(); err != nil {
+					v.l.Errorf("Error saving the baseline to %s: %v", v.file, err)
+				}
+			}
+		}
+		return true, nil
+	}
+
+	if dev := v.b.deviation(x); dev > v.maxDeviation {
+		v.l.Warningf("Baseline validation failure: value %v deviates from the baseline mean %v by %.2f standard deviations", x, v.b.Mean, dev)
+		return false, nil
+	}
+	return true, nil
+}
+
+// Deviation returns the deviation of the tracked characteristic from the
+// baseline, in standard deviations. It returns false if the baseline has not
+// been learned yet, or if characteristic cannot be determined.
+func (v *Validator) Deviation(responseBody []byte, latency time.Duration) (float64, bool) {
+	x, err := v.Sample(responseBody, latency)
+	if err != nil {
+		return 0, false
+	}
+	return v.SampleDeviation(x)
+}
+
+// SampleDeviation is like Deviation, but for an already extracted sample. It
+// also returns false if the deviation is infinite, i.e. if all the baseline
+// samples were the same and x is different, as some surfacers don't accept
+// infinite values.
+func (v *Validator) SampleDeviation(x float64) (float64, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.b.Count < v.warmupSamples {
+		return 0, false
+	}
+	dev := v.b.deviation(x)
+	if math.IsInf(dev, 0) {
+		return 0, false
+	}
+	return dev, true
+}
@@ -0,0 +1,219 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/internal/validators/baseline/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInit(t *testing.T) {
+	badFile := filepath.Join(t.TempDir(), "bad.json")
+	os.WriteFile(badFile, []byte("not json"), 0644)
+
+	tests := []struct {
+		name    string
+		conf    *configpb.Validator
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			conf: &configpb.Validator{},
+		},
+		{
+			name: "value_jq_filter",
+			conf: &configpb.Validator{Metric: configpb.Validator_VALUE, Extractor: &configpb.Validator_JqFilter{JqFilter: ".count"}},
+		},
+		{
+			name:    "value_no_extractor",
+			conf:    &configpb.Validator{Metric: configpb.Validator_VALUE},
+			wantErr: true,
+		},
+		{
+			name:    "value_bad_regex",
+			conf:    &configpb.Validator{Metric: configpb.Validator_VALUE, Extractor: &configpb.Validator_Regex{Regex: "count: ([0-9]+"}},
+			wantErr: true,
+		},
+		{
+			name:    "extractor_for_size",
+			conf:    &configpb.Validator{Extractor: &configpb.Validator_Regex{Regex: "count: ([0-9]+)"}},
+			wantErr: true,
+		},
+		{
+			name:    "negative_max_deviation",
+			conf:    &configpb.Validator{MaxDeviation: -1},
+			wantErr: true,
+		},
+		{
+			name:    "bad_baseline_file",
+			conf:    &configpb.Validator{BaselineFile: badFile},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := &Validator{}
+			err := v.Init(test.conf, nil)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, int64(defaultWarmupSamples), v.warmupSamples)
+			assert.Equal(t, float64(defaultMaxDeviation), v.maxDeviation)
+		})
+	}
+}
+
+func TestSample(t *testing.T) {
+	body := []byte(`{"count": 42}`)
+	tests := []struct {
+		name    string
+		conf    *configpb.Validator
+		latency time.Duration
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "size",
+			conf: &configpb.Validator{},
+			want: float64(len(body)),
+		},
+		{
+			name:    "latency",
+			conf:    &configpb.Validator{Metric: configpb.Validator_LATENCY},
+			latency: 1500 * time.Microsecond,
+			want:    1.5,
+		},
+		{
+			name:    "no_latency",
+			conf:    &configpb.Validator{Metric: configpb.Validator_LATENCY},
+			wantErr: true,
+		},
+		{
+			name: "value",
+			conf: &configpb.Validator{Metric: configpb.Validator_VALUE, Extractor: &configpb.Validator_JqFilter{JqFilter: ".count"}},
+			want: 42,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := &Validator{}
+			assert.NoError(t, v.Init(test.conf, nil))
+			got, err := v.Sample(body, test.latency)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	v := &Validator{}
+	assert.NoError(t, v.Init(&configpb.Validator{WarmupSamples: 4, MaxDeviation: 2}, nil))
+
+	// Warmup: sizes 8, 10, 10, 12 => mean: 10, stddev: ~1.63
+	for i, size := range []int{8, 10, 10, 12} {
+		assert.False(t, v.Learned())
+		_, ok := v.Deviation(make([]byte, size), 0)
+		assert.False(t, ok, "deviation during warmup")
+
+		// Even big outliers pass during warmup.
+		ok, err := v.Validate(make([]byte, size), 0)
+		assert.NoError(t, err)
+		assert.True(t, ok, "validation %d during warmup", i)
+	}
+	assert.True(t, v.Learned())
+	assert.Equal(t, 10.0, v.b.Mean)
+	assert.InDelta(t, 1.63, v.b.stddev(), 0.01)
+
+	for _, test := range []struct {
+		size int
+		want bool
+	}{
+		{size: 10, want: true},
+		{size: 13, want: true},
+		{size: 6, want: false},
+		{size: 14, want: false},
+	} {
+		ok, err := v.Validate(make([]byte, test.size), 0)
+		assert.NoError(t, err)
+		assert.Equal(t, test.want, ok, "size: %d", test.size)
+	}
+
+	// Baseline doesn't change after warmup.
+	assert.Equal(t, int64(4), v.b.Count)
+
+	dev, ok := v.Deviation(make([]byte, 14), 0)
+	assert.True(t, ok)
+	assert.InDelta(t, 2.45, dev, 0.01)
+}
+
+func TestConstantBaseline(t *testing.T) {
+	v := &Validator{}
+	assert.NoError(t, v.Init(&configpb.Validator{WarmupSamples: 2}, nil))
+	for i := 0; i < 2; i++ {
+		v.Validate([]byte("ok"), 0)
+	}
+
+	ok, _ := v.Validate([]byte("ok"), 0)
+	assert.True(t, ok)
+	ok, _ = v.Validate([]byte("not ok"), 0)
+	assert.False(t, ok)
+
+	// Infinite deviation is not exported.
+	_, ok = v.Deviation([]byte("not ok"), 0)
+	assert.False(t, ok)
+	dev, ok := v.Deviation([]byte("ok"), 0)
+	assert.True(t, ok)
+	assert.Equal(t, float64(0), dev)
+}
+
+func TestPersistBaseline(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "baseline.json")
+	conf := &configpb.Validator{Metric: configpb.Validator_LATENCY, WarmupSamples: 3, BaselineFile: file}
+
+	v := &Validator{}
+	assert.NoError(t, v.Init(conf, nil))
+	for _, latency := range []time.Duration{10, 20, 30} {
+		v.Validate(nil, latency*time.Millisecond)
+	}
+	data, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(string(data), `"mean":20`), "baseline file: %s", data)
+
+	// New validator, e.g. after a restart, loads the learned baseline.
+	v2 := &Validator{}
+	assert.NoError(t, v2.Init(conf, nil))
+	assert.True(t, v2.Learned())
+	assert.Equal(t, v.b, v2.b)
+	ok, _ := v2.Validate(nil, time.Second)
+	assert.False(t, ok)
+
+	// No leftover temporary files.
+	files, _ := os.ReadDir(filepath.Dir(file))
+	assert.Len(t, files, 1)
+}
@@ -0,0 +1,322 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.21.5
+// source: github.com/cloudprober/cloudprober/internal/validators/baseline/proto/config.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Validator_Metric int32
+
+const (
+	// Response size in bytes.
+	Validator_RESPONSE_SIZE Validator_Metric = 0
+	// Response latency in milliseconds. Currently supported only by the HTTP
+	// and external probes.
+	Validator_LATENCY Validator_Metric = 1
+	// Number extracted from the response, using jq_filter or regex.
+	Validator_VALUE Validator_Metric = 2
+)
+
+// Enum value maps for Validator_Metric.
+var (
+	Validator_Metric_name = map[int32]string{
+		0: "RESPONSE_SIZE",
+		1: "LATENCY",
+		2: "VALUE",
+	}
+	Validator_Metric_value = map[string]int32{
+		"RESPONSE_SIZE": 0,
+		"LATENCY":       1,
+		"VALUE":         2,
+	}
+)
+
+func (x Validator_Metric) Enum() *Validator_Metric {
+	p := new(Validator_Metric)
+	*p = x
+	return p
+}
+
+func (x Validator_Metric) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Validator_Metric) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_enumTypes[0].Descriptor()
+}
+
+func (Validator_Metric) Type() protoreflect.EnumType {
+	return &file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_enumTypes[0]
+}
+
+func (x Validator_Metric) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Validator_Metric.Descriptor instead.
+func (Validator_Metric) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_rawDescGZIP(), []int{0, 0}
+}
+
+// Baseline validator configuration. Validator learns the distribution (mean
+// and standard deviation) of a response characteristic during a warmup
+// period, and after that fails if the characteristic deviates from the
+// baseline by more than max_deviation standard deviations. All the validation
+// attempts pass during the warmup.
+//
+// Anomalies are reported as validation failures, with the "<name>:anomaly"
+// key in the validation_failure metric, e.g.
+// validation_failure{validator="size:anomaly"}. Failure to get the
+// characteristic (e.g. if jq_filter doesn't match) is reported with the
+// "<name>:no_value" key. HTTP probe also exports the current deviation (in
+// standard deviations) as the validator_value gauge metric, once the baseline
+// has been learned.
+//
+// Note that the baseline is shared by all the probe's targets.
+type Validator struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Metric Validator_Metric `protobuf:"varint,1,opt,name=metric,proto3,enum=cloudprober.validators.baseline.Validator_Metric" json:"metric,omitempty"`
+	// For VALUE metric: extractor to get the number from the response. See
+	// threshold validator for the details.
+	//
+	// Types that are assignable to Extractor:
+	//
+	//	*Validator_JqFilter
+	//	*Validator_Regex
+	Extractor isValidator_Extractor `protobuf_oneof:"extractor"`
+	// Number of samples to learn the baseline from. Default is 100.
+	WarmupSamples int32 `protobuf:"varint,4,opt,name=warmup_samples,json=warmupSamples,proto3" json:"warmup_samples,omitempty"`
+	// Maximum allowed deviation from the baseline mean, in standard
+	// deviations. Default is 3.
+	MaxDeviation float64 `protobuf:"fixed64,5,opt,name=max_deviation,json=maxDeviation,proto3" json:"max_deviation,omitempty"`
+	// File to persist the baseline to, so that it survives restarts. Baseline
+	// is saved once it has been learned, and loaded at startup if the file
+	// exists. To re-learn the baseline, remove the file.
+	BaselineFile string `protobuf:"bytes,6,opt,name=baseline_file,json=baselineFile,proto3" json:"baseline_file,omitempty"`
+}
+
+func (x *Validator) Reset() {
+	*x = Validator{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Validator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Validator) ProtoMessage() {}
+
+func (x *Validator) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Validator.ProtoReflect.Descriptor instead.
+func (*Validator) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Validator) GetMetric() Validator_Metric {
+	if x != nil {
+		return x.Metric
+	}
+	return Validator_RESPONSE_SIZE
+}
+
+func (m *Validator) GetExtractor() isValidator_Extractor {
+	if m != nil {
+		return m.Extractor
+	}
+	return nil
+}
+
+func (x *Validator) GetJqFilter() string {
+	if x, ok := x.GetExtractor().(*Validator_JqFilter); ok {
+		return x.JqFilter
+	}
+	return ""
+}
+
+func (x *Validator) GetRegex() string {
+	if x, ok := x.GetExtractor().(*Validator_Regex); ok {
+		return x.Regex
+	}
+	return ""
+}
+
+func (x *Validator) GetWarmupSamples() int32 {
+	if x != nil {
+		return x.WarmupSamples
+	}
+	return 0
+}
+
+func (x *Validator) GetMaxDeviation() float64 {
+	if x != nil {
+		return x.MaxDeviation
+	}
+	return 0
+}
+
+func (x *Validator) GetBaselineFile() string {
+	if x != nil {
+		return x.BaselineFile
+	}
+	return ""
+}
+
+type isValidator_Extractor interface {
+	isValidator_Extractor()
+}
+
+type Validator_JqFilter struct {
+	JqFilter string `protobuf:"bytes,2,opt,name=jq_filter,json=jqFilter,proto3,oneof"`
+}
+
+type Validator_Regex struct {
+	Regex string `protobuf:"bytes,3,opt,name=regex,proto3,oneof"`
+}
+
+func (*Validator_JqFilter) isValidator_Extractor() {}
+
+func (*Validator_Regex) isValidator_Extractor() {}
+
+var File_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto protoreflect.FileDescriptor
+
+var file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_rawDesc = []byte{
+	0x0a, 0x52, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x2f, 0x62, 0x61, 0x73, 0x65, 0x6c, 0x69, 0x6e,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65,
+	0x72, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x2e, 0x62, 0x61, 0x73,
+	0x65, 0x6c, 0x69, 0x6e, 0x65, 0x22, 0xc0, 0x02, 0x0a, 0x09, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x6f, 0x72, 0x12, 0x49, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x31, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65,
+	0x72, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x2e, 0x62, 0x61, 0x73,
+	0x65, 0x6c, 0x69, 0x6e, 0x65, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x2e,
+	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x1d,
+	0x0a, 0x09, 0x6a, 0x71, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x00, 0x52, 0x08, 0x6a, 0x71, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x16, 0x0a,
+	0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05,
+	0x72, 0x65, 0x67, 0x65, 0x78, 0x12, 0x25, 0x0a, 0x0e, 0x77, 0x61, 0x72, 0x6d, 0x75, 0x70, 0x5f,
+	0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x77,
+	0x61, 0x72, 0x6d, 0x75, 0x70, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d,
+	0x6d, 0x61, 0x78, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x44, 0x65, 0x76, 0x69, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x61, 0x73, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x66, 0x69,
+	0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x62, 0x61, 0x73, 0x65, 0x6c, 0x69,
+	0x6e, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x22, 0x33, 0x0a, 0x06, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x12, 0x11, 0x0a, 0x0d, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x53, 0x49, 0x5a,
+	0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x4c, 0x41, 0x54, 0x45, 0x4e, 0x43, 0x59, 0x10, 0x01,
+	0x12, 0x09, 0x0a, 0x05, 0x56, 0x41, 0x4c, 0x55, 0x45, 0x10, 0x02, 0x42, 0x0b, 0x0a, 0x09, 0x65,
+	0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x42, 0x47, 0x5a, 0x45, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62,
+	0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f,
+	0x72, 0x73, 0x2f, 0x62, 0x61, 0x73, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_rawDescOnce sync.Once
+	file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_rawDescData = file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_rawDesc
+)
+
+func file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_rawDescGZIP() []byte {
+	file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_rawDescOnce.Do(func() {
+		file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_rawDescData)
+	})
+	return file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_rawDescData
+}
+
+var file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_goTypes = []interface{}{
+	(Validator_Metric)(0), // 0: cloudprober.validators.baseline.Validator.Metric
+	(*Validator)(nil),     // 1: cloudprober.validators.baseline.Validator
+}
+var file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_depIdxs = []int32{
+	0, // 0: cloudprober.validators.baseline.Validator.metric:type_name -> cloudprober.validators.baseline.Validator.Metric
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() {
+	file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_init()
+}
+func file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_init() {
+	if File_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Validator); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*Validator_JqFilter)(nil),
+		(*Validator_Regex)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_goTypes,
+		DependencyIndexes: file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_depIdxs,
+		EnumInfos:         file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_enumTypes,
+		MessageInfos:      file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_msgTypes,
+	}.Build()
+	File_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto = out.File
+	file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_rawDesc = nil
+	file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_goTypes = nil
+	file_github_com_cloudprober_cloudprober_internal_validators_baseline_proto_config_proto_depIdxs = nil
+}
@@ -0,0 +1,59 @@
+package proto
+
+// Baseline validator configuration. Validator learns the distribution (mean
+// and standard deviation) of a response characteristic during a warmup
+// period, and after that fails if the characteristic deviates from the
+// baseline by more than max_deviation standard deviations. All the validation
+// attempts pass during the warmup.
+//
+// Anomalies are reported as validation failures, with the "<name>:anomaly"
+// key in the validation_failure metric, e.g.
+// validation_failure{validator="size:anomaly"}. Failure to get the
+// characteristic (e.g. if jq_filter doesn't match) is reported with the
+// "<name>:no_value" key. HTTP probe also exports the current deviation (in
+// standard deviations) as the validator_value gauge metric, once the baseline
+// has been learned.
+//
+// Note that the baseline is shared by all the probe's targets.
+#Validator: {
+	#Metric: {
+		// Response size in bytes.
+		"RESPONSE_SIZE"
+		#enumValue: 0
+	} | {
+		// Response latency in milliseconds. Currently supported only by the HTTP
+		// and external probes.
+		"LATENCY"
+		#enumValue: 1
+	} | {
+		// Number extracted from the response, using jq_filter or regex.
+		"VALUE"
+		#enumValue: 2
+	}
+
+	#Metric_value: {
+		RESPONSE_SIZE: 0
+		LATENCY:       1
+		VALUE:         2
+	}
+	metric?: #Metric @protobuf(1,Metric)
+	// For VALUE metric: extractor to get the number from the response. See
+	// threshold validator for the details.
+	{} | {
+		jqFilter: string @protobuf(2,string,name=jq_filter)
+	} | {
+		regex: string @protobuf(3,string)
+	}
+
+	// Number of samples to learn the baseline from. Default is 100.
+	warmupSamples?: int32 @protobuf(4,int32,name=warmup_samples)
+
+	// Maximum allowed deviation from the baseline mean, in standard
+	// deviations. Default is 3.
+	maxDeviation?: float64 @protobuf(5,double,name=max_deviation)
+
+	// File to persist the baseline to, so that it survives restarts. Baseline
+	// is saved once it has been learned, and loaded at startup if the file
+	// exists. To re-learn the baseline, remove the file.
+	baselineFile?: string @protobuf(6,string,name=baseline_file)
+}
@@ -0,0 +1,144 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package freshness provides a validator that checks the freshness of the
+// HTTP response content using its Last-Modified or ETag header.
+package freshness
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/internal/validators/freshness/proto"
+	"github.com/cloudprober/cloudprober/logger"
+)
+
+// observation is the last observation of a URL's content.
+type observation struct {
+	etag      string
+	changedAt time.Time
+	age       time.Duration
+}
+
+// Validator implements a content freshness validator.
+type Validator struct {
+	header configpb.Validator_Header
+	maxAge time.Duration
+	l      *logger.Logger
+
+	// Observations by URL, as a validator is shared by all the targets.
+	mu           sync.Mutex
+	observations map[string]*observation
+	now          func() time.Time
+}
+
+// Init initializes the freshness validator.
+func (v *Validator) Init(config interface{}, l *logger.Logger) error {
+	c, ok := config.(*configpb.Validator)
+	if !ok {
+		return fmt.Errorf("%v is not a valid freshness validator config", config)
+	}
+
+	v.header = c.GetHeader()
+	v.maxAge = time.Duration(c.GetMaxAgeSec()) * time.Second
+	v.observations = make(map[string]*observation)
+	if v.now == nil {
+		v.now = time.Now
+	}
+	v.l = l
+	return nil
+}
+
+func urlKey(res *http.Response) string {
+	if res.Request == nil || res.Request.URL == nil {
+		return ""
+	}
+	return res.Request.URL.String()
+}
+
+// observe records the response's content version and returns its age.
+func (v *Validator) observe(res *http.Response) (time.Duration, error) {
+	now := v.now()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	key := urlKey(res)
+	obs := v.observations[key]
+	if obs == nil {
+		obs = &observation{}
+	}
+
+	switch v.header {
+	case configpb.Validator_ETAG:
+		etag := res.Header.Get("ETag")
+		if etag == "" {
+			return 0, fmt.Errorf("ETag header not found")
+		}
+		if etag != obs.etag {
+			obs.etag, obs.changedAt = etag, now
+		}
+	default:
+		lastModified, err := http.ParseTime(res.Header.Get("Last-Modified"))
+		if err != nil {
+			return 0, fmt.Errorf("error parsing Last-Modified header: %v", err)
+		}
+		obs.changedAt = lastModified
+	}
+
+	obs.age = now.Sub(obs.changedAt)
+	v.observations[key] = obs
+	return obs.age, nil
+}
+
+// Validate records the content version of the provided input and returns
+// false if content is older than the maximum age, or if the configured header
+// is missing. Validate expects the input to be of the type: *http.Response.
+func (v *Validator) Validate(input interface{}) (bool, error) {
+	res, ok := input.(*http.Response)
+	if !ok || res == nil {
+		return false, fmt.Errorf("input %v is not of type http.Response", input)
+	}
+
+	age, err := v.observe(res)
+	if err != nil {
+		v.l.Warningf("Freshness validation failure: %v", err)
+		return false, nil
+	}
+
+	if v.maxAge != 0 && age > v.maxAge {
+		v.l.Warningf("Freshness validation failure: stale content at %s, age: %v, max age: %v", urlKey(res), age, v.maxAge)
+		return false, nil
+	}
+	return true, nil
+}
+
+// Age returns the content age for the provided input's URL, as per its last
+// validation.
+func (v *Validator) Age(input interface{}) (time.Duration, bool) {
+	res, ok := input.(*http.Response)
+	if !ok || res == nil {
+		return 0, false
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	obs := v.observations[urlKey(res)]
+	if obs == nil {
+		return 0, false
+	}
+	return obs.age, true
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package freshness
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/internal/validators/freshness/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testResponse(u string, headers map[string]string) *http.Response {
+	resp := &http.Response{
+		Header:  http.Header{},
+		Request: &http.Request{URL: &url.URL{Scheme: "http", Host: u}},
+	}
+	for k, v := range headers {
+		resp.Header.Set(k, v)
+	}
+	return resp
+}
+
+func TestLastModified(t *testing.T) {
+	now := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	v := &Validator{now: func() time.Time { return now }}
+	require.NoError(t, v.Init(&configpb.Validator{MaxAgeSec: 3600}, nil))
+
+	tests := []struct {
+		name         string
+		lastModified string
+		want         bool
+		wantAge      time.Duration
+	}{
+		{
+			name:         "fresh",
+			lastModified: now.Add(-30 * time.Minute).Format(http.TimeFormat),
+			want:         true,
+			wantAge:      30 * time.Minute,
+		},
+		{
+			name:         "stale",
+			lastModified: now.Add(-2 * time.Hour).Format(http.TimeFormat),
+			want:         false,
+			wantAge:      2 * time.Hour,
+		},
+		{
+			name: "missing_header",
+			want: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := testResponse(test.name+".example.com", map[string]string{"Last-Modified": test.lastModified})
+			ok, err := v.Validate(resp)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, ok)
+
+			age, found := v.Age(resp)
+			assert.Equal(t, test.lastModified != "", found)
+			assert.Equal(t, test.wantAge, age)
+		})
+	}
+}
+
+func TestETag(t *testing.T) {
+	now := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	v := &Validator{now: func() time.Time { return now }}
+	require.NoError(t, v.Init(&configpb.Validator{
+		Header:    configpb.Validator_ETAG,
+		MaxAgeSec: 600,
+	}, nil))
+
+	check := func(host, etag string, want bool, wantAge time.Duration) {
+		t.Helper()
+		resp := testResponse(host, map[string]string{"ETag": etag})
+		ok, err := v.Validate(resp)
+		assert.NoError(t, err)
+		assert.Equal(t, want, ok, "validation result for %s at %v", etag, now)
+		age, _ := v.Age(resp)
+		assert.Equal(t, wantAge, age)
+	}
+
+	check("a.example.com", `"v1"`, true, 0)
+	check("b.example.com", `"x1"`, true, 0)
+
+	now = now.Add(5 * time.Minute)
+	check("a.example.com", `"v1"`, true, 5*time.Minute)
+
+	// ETag unchanged for too long.
+	now = now.Add(10 * time.Minute)
+	check("a.example.com", `"v1"`, false, 15*time.Minute)
+
+	// Content changed, age starts again.
+	check("a.example.com", `"v2"`, true, 0)
+
+	// URLs are tracked independently.
+	check("b.example.com", `"x1"`, false, 15*time.Minute)
+
+	ok, err := v.Validate(testResponse("a.example.com", nil))
+	assert.NoError(t, err)
+	assert.False(t, ok, "validation result without ETag")
+}
+
+func TestValidateInvalidInput(t *testing.T) {
+	v := &Validator{}
+	require.NoError(t, v.Init(&configpb.Validator{}, nil))
+	_, err := v.Validate("not a response")
+	assert.Error(t, err)
+
+	_, err = v.Validate((*http.Response)(nil))
+	assert.Error(t, err)
+	_, ok := v.Age((*http.Response)(nil))
+	assert.False(t, ok)
+}
@@ -0,0 +1,230 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.21.5
+// source: github.com/cloudprober/cloudprober/internal/validators/freshness/proto/config.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Validator_Header int32
+
+const (
+	// Content's age is the time since its Last-Modified time.
+	Validator_LAST_MODIFIED Validator_Header = 0
+	// Content's age is the time since the current ETag value was first
+	// observed, i.e. ETag is compared with the previous observation. Note
+	// that age starts from zero after a cloudprober restart.
+	Validator_ETAG Validator_Header = 1
+)
+
+// Enum value maps for Validator_Header.
+var (
+	Validator_Header_name = map[int32]string{
+		0: "LAST_MODIFIED",
+		1: "ETAG",
+	}
+	Validator_Header_value = map[string]int32{
+		"LAST_MODIFIED": 0,
+		"ETAG":          1,
+	}
+)
+
+func (x Validator_Header) Enum() *Validator_Header {
+	p := new(Validator_Header)
+	*p = x
+	return p
+}
+
+func (x Validator_Header) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Validator_Header) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_enumTypes[0].Descriptor()
+}
+
+func (Validator_Header) Type() protoreflect.EnumType {
+	return &file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_enumTypes[0]
+}
+
+func (x Validator_Header) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Validator_Header.Descriptor instead.
+func (Validator_Header) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_rawDescGZIP(), []int{0, 0}
+}
+
+// Freshness validator configuration. Validator fails if the HTTP response's
+// content is older than max_age_sec, e.g. CDN-cached content that is not
+// being refreshed because of a broken cache invalidation pipeline. It also
+// fails if the response doesn't have the configured header. HTTP probe
+// exports the content's age, in seconds, as the content_age_sec metric.
+type Validator struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Header Validator_Header `protobuf:"varint,1,opt,name=header,proto3,enum=cloudprober.validators.freshness.Validator_Header" json:"header,omitempty"`
+	// Maximum age of the content. If not set, validator fails only if the
+	// header is missing, but content age is still exported.
+	MaxAgeSec uint64 `protobuf:"varint,2,opt,name=max_age_sec,json=maxAgeSec,proto3" json:"max_age_sec,omitempty"`
+}
+
+func (x *Validator) Reset() {
+	*x = Validator{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Validator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Validator) ProtoMessage() {}
+
+func (x *Validator) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Validator.ProtoReflect.Descriptor instead.
+func (*Validator) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Validator) GetHeader() Validator_Header {
+	if x != nil {
+		return x.Header
+	}
+	return Validator_LAST_MODIFIED
+}
+
+func (x *Validator) GetMaxAgeSec() uint64 {
+	if x != nil {
+		return x.MaxAgeSec
+	}
+	return 0
+}
+
+var File_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto protoreflect.FileDescriptor
+
+var file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_rawDesc = []byte{
+	0x0a, 0x53, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x2f, 0x66, 0x72, 0x65, 0x73, 0x68, 0x6e, 0x65,
+	0x73, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x20, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62,
+	0x65, 0x72, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x2e, 0x66, 0x72,
+	0x65, 0x73, 0x68, 0x6e, 0x65, 0x73, 0x73, 0x22, 0x9e, 0x01, 0x0a, 0x09, 0x56, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x4a, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x32, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f,
+	0x62, 0x65, 0x72, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x2e, 0x66,
+	0x72, 0x65, 0x73, 0x68, 0x6e, 0x65, 0x73, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x6f, 0x72, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x12, 0x1e, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x65, 0x63,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x41, 0x67, 0x65, 0x53, 0x65,
+	0x63, 0x22, 0x25, 0x0a, 0x06, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x11, 0x0a, 0x0d, 0x4c,
+	0x41, 0x53, 0x54, 0x5f, 0x4d, 0x4f, 0x44, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x08,
+	0x0a, 0x04, 0x45, 0x54, 0x41, 0x47, 0x10, 0x01, 0x42, 0x48, 0x5a, 0x46, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62,
+	0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f,
+	0x72, 0x73, 0x2f, 0x66, 0x72, 0x65, 0x73, 0x68, 0x6e, 0x65, 0x73, 0x73, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_rawDescOnce sync.Once
+	file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_rawDescData = file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_rawDesc
+)
+
+func file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_rawDescGZIP() []byte {
+	file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_rawDescOnce.Do(func() {
+		file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_rawDescData)
+	})
+	return file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_rawDescData
+}
+
+var file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_goTypes = []interface{}{
+	(Validator_Header)(0), // 0: cloudprober.validators.freshness.Validator.Header
+	(*Validator)(nil),     // 1: cloudprober.validators.freshness.Validator
+}
+var file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_depIdxs = []int32{
+	0, // 0: cloudprober.validators.freshness.Validator.header:type_name -> cloudprober.validators.freshness.Validator.Header
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() {
+	file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_init()
+}
+func file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_init() {
+	if File_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Validator); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_goTypes,
+		DependencyIndexes: file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_depIdxs,
+		EnumInfos:         file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_enumTypes,
+		MessageInfos:      file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_msgTypes,
+	}.Build()
+	File_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto = out.File
+	file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_rawDesc = nil
+	file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_goTypes = nil
+	file_github_com_cloudprober_cloudprober_internal_validators_freshness_proto_config_proto_depIdxs = nil
+}
@@ -0,0 +1,30 @@
+package proto
+
+// Freshness validator configuration. Validator fails if the HTTP response's
+// content is older than max_age_sec, e.g. CDN-cached content that is not
+// being refreshed because of a broken cache invalidation pipeline. It also
+// fails if the response doesn't have the configured header. HTTP probe
+// exports the content's age, in seconds, as the content_age_sec metric.
+#Validator: {
+	#Header: {
+		// Content's age is the time since its Last-Modified time.
+		"LAST_MODIFIED"
+		#enumValue: 0
+	} | {
+		// Content's age is the time since the current ETag value was first
+		// observed, i.e. ETag is compared with the previous observation. Note
+		// that age starts from zero after a cloudprober restart.
+		"ETAG"
+		#enumValue: 1
+	}
+
+	#Header_value: {
+		LAST_MODIFIED: 0
+		ETAG:          1
+	}
+	header?: #Header @protobuf(1,Header)
+
+	// Maximum age of the content. If not set, validator fails only if the
+	// header is missing, but content age is still exported.
+	maxAgeSec?: uint64 @protobuf(2,uint64,name=max_age_sec)
+}
@@ -0,0 +1,181 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonschema provides a validator that validates the probe output
+// against a JSON Schema.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cloudprober/cloudprober/internal/file"
+	configpb "github.com/cloudprober/cloudprober/internal/validators/jsonschema/proto"
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// InvalidJSON is the violation reported for the responses that are not a
+// valid JSON.
+const InvalidJSON = "invalid_json"
+
+// OtherViolation is the violation reported for the new violation locations
+// once a validator has seen maxViolationLocations distinct locations.
+const OtherViolation = "other"
+
+// maxViolationLocations caps the number of distinct violation locations
+// reported by a validator, as each of them becomes a validation_failure
+// metric key.
+const maxViolationLocations = 20
+
+// Validator implements a JSON Schema validator.
+type Validator struct {
+	schema *jsonschema.Schema
+	l      *logger.Logger
+
+	mu        sync.Mutex
+	locations map[string]bool
+}
+
+// Init initializes the JSON Schema validator. It loads and compiles the
+// configured schema, and returns an error if it's not a valid JSON Schema.
+func (v *Validator) Init(config interface{}, l *logger.Logger) error {
+	cfg, ok := config.(*configpb.Validator)
+	if !ok {
+		return fmt.Errorf("%v is not a valid JSON Schema validator config", config)
+	}
+
+	schemaURL, schemaJSON := "schema.json", []byte(cfg.GetSchema())
+	if cfg.GetSchemaFile() != "" {
+		b, err := file.ReadFile(cfg.GetSchemaFile())
+		if err != nil {
+			return fmt.Errorf("error reading JSON Schema file (%s): %v", cfg.GetSchemaFile(), err)
+		}
+		schemaURL, schemaJSON = cfg.GetSchemaFile(), b
+	}
+	if len(schemaJSON) == 0 {
+		return errors.New("JSON Schema validator needs a schema or schema_file")
+	}
+
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(schemaURL, bytes.NewReader(schemaJSON)); err != nil {
+		return fmt.Errorf("error parsing JSON Schema: %v", err)
+	}
+	schema, err := c.Compile(schemaURL)
+	if err != nil {
+		return fmt.Errorf("error compiling JSON Schema: %v", err)
+	}
+	v.schema = schema
+	v.locations = make(map[string]bool)
+
+	v.l = l
+	return nil
+}
+
+// normalizeLocation replaces the array indices in the JSON pointer with "*",
+// e.g. /items/1/id becomes /items/*/id, so that the same violation in
+// different array elements is reported under the same location.
+func normalizeLocation(loc string) string {
+	segments := strings.Split(loc, "/")
+	for i, s := range segments {
+		if s != "" && strings.Trim(s, "0123456789") == "" {
+			segments[i] = "*"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// reportedLocation returns the location to report for a violation: the
+// normalized location itself, or OtherViolation if the validator has already
+// seen maxViolationLocations other locations.
+func (v *Validator) reportedLocation(loc string) string {
+	loc = normalizeLocation(loc)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if !v.locations[loc] {
+		if len(v.locations) >= maxViolationLocations {
+			return OtherViolation
+		}
+		v.locations[loc] = true
+	}
+	return loc
+}
+
+// firstViolation returns the deepest error along the first chain of causes.
+func firstViolation(ve *jsonschema.ValidationError) *jsonschema.ValidationError {
+	for len(ve.Causes) > 0 {
+		ve = ve.Causes[0]
+	}
+	return ve
+}
+
+// Violation returns the location (JSON pointer) of the first violation in
+// the responseBody, InvalidJSON if responseBody is not a valid JSON, or an
+// empty string if there is no violation. Array indices in the location are
+// replaced by "*", and the number of distinct locations is capped (see
+// OtherViolation), to keep the validation_failure metric's cardinality in
+// check.
+func (v *Validator) Violation(responseBody []byte) string {
+	err := v.validate(responseBody)
+	if err == nil {
+		return ""
+	}
+
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return InvalidJSON
+	}
+	loc := firstViolation(ve).InstanceLocation
+	if loc == "" {
+		loc = "/"
+	}
+	return v.reportedLocation(loc)
+}
+
+func (v *Validator) validate(responseBody []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(responseBody))
+	dec.UseNumber()
+	var input interface{}
+	if err := dec.Decode(&input); err != nil {
+		return fmt.Errorf("response is not a valid JSON: %v", err)
+	}
+	if dec.More() {
+		return errors.New("response is not a valid JSON: unexpected data after the top-level value")
+	}
+	return v.schema.Validate(input)
+}
+
+// Validate validates the provided responseBody against the JSON Schema. It
+// returns false if responseBody is not a valid JSON, or if it doesn't conform
+// to the schema.
+func (v *Validator) Validate(responseBody []byte) (bool, error) {
+	err := v.validate(responseBody)
+	if err == nil {
+		return true, nil
+	}
+
+	var ve *jsonschema.ValidationError
+	if errors.As(err, &ve) {
+		fv := firstViolation(ve)
+		v.l.Warningf("JSON Schema validation failure: %s at %q", fv.Message, fv.InstanceLocation)
+	} else {
+		v.l.Warningf("JSON Schema validation failure: %v", err)
+	}
+	return false, nil
+}
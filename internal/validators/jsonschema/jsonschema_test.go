@@ -0,0 +1,137 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	configpb "github.com/cloudprober/cloudprober/internal/validators/jsonschema/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSchema = `{
+  "type": "object",
+  "required": ["status", "items"],
+  "properties": {
+    "status": {"enum": ["ok", "degraded"]},
+    "items": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["id"],
+        "properties": {"id": {"type": "integer"}}
+      }
+    }
+  }
+}`
+
+func TestInvalidConfig(t *testing.T) {
+	for name, cfg := range map[string]interface{}{
+		"wrong type":   "{}",
+		"empty":        &configpb.Validator{},
+		"bad json":     &configpb.Validator{SchemaSource: &configpb.Validator_Schema{Schema: "{"}},
+		"bad schema":   &configpb.Validator{SchemaSource: &configpb.Validator_Schema{Schema: `{"type": 5}`}},
+		"missing file": &configpb.Validator{SchemaSource: &configpb.Validator_SchemaFile{SchemaFile: "/does/not/exist.json"}},
+	} {
+		v := &Validator{}
+		assert.Error(t, v.Init(cfg, nil), name)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schemaFile := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(schemaFile, []byte(testSchema), 0644); err != nil {
+		t.Fatalf("error writing schema file: %v", err)
+	}
+
+	for _, cfg := range []*configpb.Validator{
+		{SchemaSource: &configpb.Validator_Schema{Schema: testSchema}},
+		{SchemaSource: &configpb.Validator_SchemaFile{SchemaFile: schemaFile}},
+	} {
+		v := &Validator{}
+		if err := v.Init(cfg, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		tests := []struct {
+			body          string
+			wantViolation string
+		}{
+			{
+				body: `{"status": "ok", "items": [{"id": 1}, {"id": 2}]}`,
+			},
+			{
+				body:          `{"status": "ok"}`,
+				wantViolation: "/",
+			},
+			{
+				body:          `{"status": "down", "items": []}`,
+				wantViolation: "/status",
+			},
+			{
+				body:          `{"status": "ok", "items": [{"id": 1}, {"id": "two"}]}`,
+				wantViolation: "/items/*/id",
+			},
+			{
+				body:          `{"status": "ok"`,
+				wantViolation: InvalidJSON,
+			},
+			{
+				body:          `{"status": "ok", "items": []} {}`,
+				wantViolation: InvalidJSON,
+			},
+		}
+
+		for _, test := range tests {
+			ok, err := v.Validate([]byte(test.body))
+			assert.NoError(t, err, test.body)
+			assert.Equal(t, test.wantViolation == "", ok, test.body)
+			assert.Equal(t, test.wantViolation, v.Violation([]byte(test.body)), test.body)
+		}
+	}
+}
+
+func TestViolationLocationsCapped(t *testing.T) {
+	v := &Validator{}
+	if err := v.Init(&configpb.Validator{SchemaSource: &configpb.Validator_Schema{Schema: `{"additionalProperties": false}`}}, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// additionalProperties violations are reported at the object.
+	assert.Equal(t, "/", v.Violation([]byte(`{"a": 1}`)))
+
+	for i := 0; i < maxViolationLocations+5; i++ {
+		v.reportedLocation(fmt.Sprintf("/key%d", i))
+	}
+	assert.Len(t, v.locations, maxViolationLocations)
+	assert.Equal(t, OtherViolation, v.reportedLocation("/new"))
+	// Locations seen before are still reported as such.
+	assert.Equal(t, "/", v.reportedLocation("/"))
+	assert.Equal(t, "/key0", v.reportedLocation("/key0"))
+}
+
+func TestNormalizeLocation(t *testing.T) {
+	for loc, want := range map[string]string{
+		"/":               "/",
+		"/status":         "/status",
+		"/items/1/id":     "/items/*/id",
+		"/items/12/0/id2": "/items/*/*/id2",
+	} {
+		assert.Equal(t, want, normalizeLocation(loc), loc)
+	}
+}
@@ -0,0 +1,210 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.21.5
+// source: github.com/cloudprober/cloudprober/internal/validators/jsonschema/proto/config.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// JSON Schema validator configuration. Validator fails if the probe output,
+// e.g. HTTP response body, is not a valid JSON, or if it doesn't conform to
+// the JSON Schema. Failures are recorded in the validation_failure metric
+// with the key "<validator_name>:<json_pointer>", where json_pointer is the
+// location of the first violation in the response, with the array indices
+// replaced by "*", e.g.:
+//
+//	validation_failure{validator="api_schema:/items/*/id"}
+//
+// For invalid JSON responses, key is "<validator_name>:invalid_json". To
+// bound the metric's cardinality, only the first 20 distinct locations are
+// reported as such; the rest are reported as "<validator_name>:other".
+type Validator struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to SchemaSource:
+	//
+	//	*Validator_Schema
+	//	*Validator_SchemaFile
+	SchemaSource isValidator_SchemaSource `protobuf_oneof:"schema_source"`
+}
+
+func (x *Validator) Reset() {
+	*x = Validator{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Validator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Validator) ProtoMessage() {}
+
+func (x *Validator) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Validator.ProtoReflect.Descriptor instead.
+func (*Validator) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (m *Validator) GetSchemaSource() isValidator_SchemaSource {
+	if m != nil {
+		return m.SchemaSource
+	}
+	return nil
+}
+
+func (x *Validator) GetSchema() string {
+	if x, ok := x.GetSchemaSource().(*Validator_Schema); ok {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *Validator) GetSchemaFile() string {
+	if x, ok := x.GetSchemaSource().(*Validator_SchemaFile); ok {
+		return x.SchemaFile
+	}
+	return ""
+}
+
+type isValidator_SchemaSource interface {
+	isValidator_SchemaSource()
+}
+
+type Validator_Schema struct {
+	// Inline JSON Schema.
+	Schema string `protobuf:"bytes,1,opt,name=schema,proto3,oneof"`
+}
+
+type Validator_SchemaFile struct {
+	// JSON Schema file. Remote files are supported as well, e.g.
+	// gs://my-bucket/schemas/api.json.
+	SchemaFile string `protobuf:"bytes,2,opt,name=schema_file,json=schemaFile,proto3,oneof"`
+}
+
+func (*Validator_Schema) isValidator_SchemaSource() {}
+
+func (*Validator_SchemaFile) isValidator_SchemaSource() {}
+
+var File_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto protoreflect.FileDescriptor
+
+var file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_rawDesc = []byte{
+	0x0a, 0x54, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x73, 0x63, 0x68,
+	0x65, 0x6d, 0x61, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x21, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f,
+	0x62, 0x65, 0x72, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x2e, 0x6a,
+	0x73, 0x6f, 0x6e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x22, 0x59, 0x0a, 0x09, 0x56, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x18, 0x0a, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61,
+	0x12, 0x21, 0x0a, 0x0b, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x46,
+	0x69, 0x6c, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x42, 0x49, 0x5a, 0x47, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x2f, 0x6a,
+	0x73, 0x6f, 0x6e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_rawDescOnce sync.Once
+	file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_rawDescData = file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_rawDesc
+)
+
+func file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_rawDescGZIP() []byte {
+	file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_rawDescOnce.Do(func() {
+		file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_rawDescData)
+	})
+	return file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_rawDescData
+}
+
+var file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_goTypes = []interface{}{
+	(*Validator)(nil), // 0: cloudprober.validators.jsonschema.Validator
+}
+var file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() {
+	file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_init()
+}
+func file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_init() {
+	if File_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Validator); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*Validator_Schema)(nil),
+		(*Validator_SchemaFile)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_goTypes,
+		DependencyIndexes: file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_depIdxs,
+		MessageInfos:      file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_msgTypes,
+	}.Build()
+	File_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto = out.File
+	file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_rawDesc = nil
+	file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_goTypes = nil
+	file_github_com_cloudprober_cloudprober_internal_validators_jsonschema_proto_config_proto_depIdxs = nil
+}
@@ -0,0 +1,22 @@
+package proto
+
+// JSON Schema validator configuration. Validator fails if the probe output,
+// e.g. HTTP response body, is not a valid JSON, or if it doesn't conform to
+// the JSON Schema. Failures are recorded in the validation_failure metric
+// with the key "<validator_name>:<json_pointer>", where json_pointer is the
+// location of the first violation in the response, with the array indices
+// replaced by "*", e.g.:
+//   validation_failure{validator="api_schema:/items/*/id"}
+// For invalid JSON responses, key is "<validator_name>:invalid_json". To
+// bound the metric's cardinality, only the first 20 distinct locations are
+// reported as such; the rest are reported as "<validator_name>:other".
+#Validator: {
+	{} | {
+		// Inline JSON Schema.
+		schema: string @protobuf(1,string)
+	} | {
+		// JSON Schema file. Remote files are supported as well, e.g.
+		// gs://my-bucket/schemas/api.json.
+		schemaFile: string @protobuf(2,string,name=schema_file)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notcontains provides a validator that fails if the probe output
+// contains any of the forbidden patterns.
+package notcontains
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+
+	configpb "github.com/cloudprober/cloudprober/internal/validators/notcontains/proto"
+	"github.com/cloudprober/cloudprober/logger"
+)
+
+// Validator implements a not-contains validator.
+type Validator struct {
+	literals [][]byte
+	regexes  []*regexp.Regexp
+	l        *logger.Logger
+}
+
+// Init initializes the not-contains validator. It compiles the configured
+// regexes and returns an error if any of them doesn't compile.
+func (v *Validator) Init(config interface{}, l *logger.Logger) error {
+	cfg, ok := config.(*configpb.Validator)
+	if !ok {
+		return fmt.Errorf("%v is not a valid not-contains validator config", config)
+	}
+	if len(cfg.GetLiteral()) == 0 && len(cfg.GetRegex()) == 0 {
+		return errors.New("not-contains validator needs at least one literal or regex")
+	}
+
+	for _, s := range cfg.GetLiteral() {
+		if s == "" {
+			return errors.New("not-contains validator literal cannot be empty")
+		}
+		v.literals = append(v.literals, []byte(s))
+	}
+
+	for _, s := range cfg.GetRegex() {
+		r, err := regexp.Compile(s)
+		if err != nil {
+			return fmt.Errorf("error compiling the given regex (%s): %v", s, err)
+		}
+		v.regexes = append(v.regexes, r)
+	}
+
+	v.l = l
+	return nil
+}
+
+// Patterns returns all the configured patterns.
+func (v *Validator) Patterns() []string {
+	var patterns []string
+	for _, lit := range v.literals {
+		patterns = append(patterns, string(lit))
+	}
+	for _, r := range v.regexes {
+		patterns = append(patterns, r.String())
+	}
+	return patterns
+}
+
+// Match returns the first forbidden pattern that appears in the
+// responseBody, or an empty string if there is none.
+func (v *Validator) Match(responseBody []byte) string {
+	for _, lit := range v.literals {
+		if bytes.Contains(responseBody, lit) {
+			return string(lit)
+		}
+	}
+	for _, r := range v.regexes {
+		if r.Match(responseBody) {
+			return r.String()
+		}
+	}
+	return ""
+}
+
+// Validate the provided responseBody and return true if it doesn't contain
+// any of the forbidden patterns.
+func (v *Validator) Validate(responseBody []byte) (bool, error) {
+	if pattern := v.Match(responseBody); pattern != "" {
+		v.l.Warningf("Not-contains validation failure: response %s contains the forbidden pattern %s", string(responseBody), pattern)
+		return false, nil
+	}
+	return true, nil
+}
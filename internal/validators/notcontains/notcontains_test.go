@@ -0,0 +1,71 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notcontains
+
+import (
+	"testing"
+
+	configpb "github.com/cloudprober/cloudprober/internal/validators/notcontains/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvalidConfig(t *testing.T) {
+	for name, cfg := range map[string]interface{}{
+		"wrong type":    "Exception",
+		"empty":         &configpb.Validator{},
+		"empty literal": &configpb.Validator{Literal: []string{""}},
+		"bad regex":     &configpb.Validator{Regex: []string{"(?!cloudprober)"}},
+	} {
+		v := &Validator{}
+		assert.Error(t, v.Init(cfg, nil), name)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	v := &Validator{}
+	err := v.Init(&configpb.Validator{
+		Literal: []string{"Exception"},
+		Regex:   []string{`at [a-z.]+\(\w+\.java:\d+\)`},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Exception", `at [a-z.]+\(\w+\.java:\d+\)`}, v.Patterns())
+
+	tests := []struct {
+		body        string
+		wantPattern string
+	}{
+		{
+			body: "all good",
+		},
+		{
+			body:        "java.lang.NullPointerException",
+			wantPattern: "Exception",
+		},
+		{
+			body:        "error\n  at com.example.foo(Foo.java:42)",
+			wantPattern: `at [a-z.]+\(\w+\.java:\d+\)`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.body, func(t *testing.T) {
+			assert.Equal(t, test.wantPattern, v.Match([]byte(test.body)))
+
+			ok, err := v.Validate([]byte(test.body))
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantPattern == "", ok)
+		})
+	}
+}
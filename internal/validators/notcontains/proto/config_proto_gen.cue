@@ -0,0 +1,14 @@
+package proto
+
+// Not-contains validator configuration. Validator fails if the probe output,
+// e.g. HTTP response body, contains any of the configured patterns. Failures
+// are recorded in the validation_failure metric with the key
+// "<validator_name>:<matched_pattern>", e.g.:
+//   validation_failure{validator="no_errors:Exception"}
+#Validator: {
+	// Literal strings that must not appear in the probe output.
+	literal?: [...string] @protobuf(1,string)
+
+	// Regexes that must not match the probe output.
+	regex?: [...string] @protobuf(2,string)
+}
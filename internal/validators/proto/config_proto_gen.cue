@@ -4,6 +4,11 @@ import (
 	"github.com/cloudprober/cloudprober/internal/validators/http/proto"
 	proto_1 "github.com/cloudprober/cloudprober/internal/validators/integrity/proto"
 	proto_5 "github.com/cloudprober/cloudprober/internal/validators/json/proto"
+	proto_A "github.com/cloudprober/cloudprober/internal/validators/notcontains/proto"
+	proto_8 "github.com/cloudprober/cloudprober/internal/validators/jsonschema/proto"
+	proto_E "github.com/cloudprober/cloudprober/internal/validators/freshness/proto"
+	proto_B "github.com/cloudprober/cloudprober/internal/validators/threshold/proto"
+	proto_36 "github.com/cloudprober/cloudprober/internal/validators/baseline/proto"
 )
 
 #Validator: {
@@ -19,5 +24,26 @@ import (
 	} | {
 		// Regex validator
 		regex: string @protobuf(4,string)
+	} | {
+		// Not-contains validator: fails if the probe output contains any of the
+		// given literal strings or regexes.
+		notContains: proto_A.#Validator @protobuf(6,notcontains.Validator,name=not_contains)
+	} | {
+		// JSON Schema validator: fails if the probe output doesn't conform to the
+		// given JSON Schema.
+		jsonSchema: proto_8.#Validator @protobuf(7,jsonschema.Validator,name=json_schema)
+	} | {
+		// Freshness validator: fails if the HTTP response content's Last-Modified
+		// or ETag header shows that it hasn't changed for too long.
+		freshness: proto_E.#Validator @protobuf(8,freshness.Validator)
+	} | {
+		// Threshold validator: fails if the number extracted from the probe
+		// output is out of the configured range.
+		threshold: proto_B.#Validator @protobuf(9,threshold.Validator)
+	} | {
+		// Baseline validator: learns the distribution of response size, latency
+		// or an extracted value during a warmup, and fails if it deviates from
+		// the learned baseline.
+		baseline: proto_36.#Validator @protobuf(10,baseline.Validator)
 	}
 }
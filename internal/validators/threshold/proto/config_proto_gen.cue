@@ -0,0 +1,25 @@
+package proto
+
+// Threshold validator configuration. Validator extracts a number from the
+// probe output, e.g. a health score from an HTTP API response, and fails if
+// it's outside the [min, max] range, or if a number cannot be extracted. HTTP
+// probe exports the extracted value as the validator_value gauge metric,
+// e.g. validator_value{validator="score"}.
+#Validator: {
+	{} | {
+		// jq filter to extract the value from a JSON output, e.g.
+		// ".health.score". Filter should return a number, or a string that
+		// parses as a number.
+		jqFilter: string @protobuf(1,string,name=jq_filter)
+	} | {
+		// Regex to extract the value. If regex has a capturing group, the first
+		// group's match is used, otherwise the whole match is used, e.g.
+		// "score: ([0-9.]+)".
+		regex: string @protobuf(2,string)
+	}
+
+	// Minimum and maximum allowed values (inclusive). Unset bounds are not
+	// checked.
+	min?: float64 @protobuf(3,double)
+	max?: float64 @protobuf(4,double)
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package threshold provides a validator that checks a number extracted from
+// the probe output against the configured bounds.
+package threshold
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	configpb "github.com/cloudprober/cloudprober/internal/validators/threshold/proto"
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/itchyny/gojq"
+)
+
+// Validator implements a threshold validator.
+type Validator struct {
+	jqQuery  *gojq.Query
+	re       *regexp.Regexp
+	min, max *float64
+	l        *logger.Logger
+}
+
+// Init initializes the threshold validator.
+func (v *Validator) Init(config interface{}, l *logger.Logger) error {
+	c, ok := config.(*configpb.Validator)
+	if !ok {
+		return fmt.Errorf("%v is not a valid threshold validator config", config)
+	}
+
+	switch {
+	case c.GetJqFilter() != "":
+		q, err := gojq.Parse(c.GetJqFilter())
+		if err != nil {
+			return fmt.Errorf("error parsing the given jq filter (%s): %v", c.GetJqFilter(), err)
+		}
+		v.jqQuery = q
+	case c.GetRegex() != "":
+		re, err := regexp.Compile(c.GetRegex())
+		if err != nil {
+			return fmt.Errorf("error compiling the given regex (%s): %v", c.GetRegex(), err)
+		}
+		v.re = re
+	default:
+		return errors.New("threshold validator: one of jq_filter or regex is required")
+	}
+
+	if c.Min != nil && c.Max != nil && c.GetMin() > c.GetMax() {
+		return fmt.Errorf("threshold validator: min (%v) is greater than max (%v)", c.GetMin(), c.GetMax())
+	}
+	v.min, v.max = c.Min, c.Max
+	v.l = l
+	return nil
+}
+
+func parseFloat(item interface{}) (float64, error) {
+	switch val := item.(type) {
+	case float64:
+		return val, nil
+	case int:
+		return float64(val), nil
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(val), 64)
+	}
+	return 0, fmt.Errorf("jq filter output (%v) is not a number", item)
+}
+
+// Value extracts the number from the provided responseBody.
+func (v *Validator) Value(responseBody []byte) (float64, error) {
+	if v.re != nil {
+		m := v.re.FindSubmatch(responseBody)
+		if m == nil {
+			return 0, fmt.Errorf("regex (%s) didn't match the response", v.re.String())
+		}
+		s := m[0]
+		if len(m) > 1 {
+			s = m[1]
+		}
+		return strconv.ParseFloat(string(s), 64)
+	}
+
+	var input interface{}
+	if err := json.Unmarshal(responseBody, &input); err != nil {
+		return 0, fmt.Errorf("response is not a valid JSON: %v", err)
+	}
+
+	// We use the last output of the jq filter, similar to the JSON validator.
+	var lastItem interface{}
+	iter := v.jqQuery.Run(input)
+	for {
+		item, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := item.(error); ok {
+			return 0, err
+		}
+		lastItem = item
+	}
+	return parseFloat(lastItem)
+}
+
+// Validate extracts the number from the provided responseBody and returns
+// false if it's out of the configured bounds, or if it cannot be extracted.
+func (v *Validator) Validate(responseBody []byte) (bool, error) {
+	val, err := v.Value(responseBody)
+	if err != nil {
+		v.l.Warningf("Threshold validation failure: %v", err)
+		return false, nil
+	}
+
+	if v.min != nil && val < *v.min {
+		v.l.Warningf("Threshold validation failure: value %v is less than the minimum %v", val, *v.min)
+		return false, nil
+	}
+	if v.max != nil && val > *v.max {
+		v.l.Warningf("Threshold validation failure: value %v is greater than the maximum %v", val, *v.max)
+		return false, nil
+	}
+	return true, nil
+}
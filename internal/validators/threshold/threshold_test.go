@@ -0,0 +1,188 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package threshold
+
+import (
+	"testing"
+
+	configpb "github.com/cloudprober/cloudprober/internal/validators/threshold/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestInit(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    *configpb.Validator
+		wantErr bool
+	}{
+		{
+			name: "jq_filter",
+			conf: &configpb.Validator{Extractor: &configpb.Validator_JqFilter{JqFilter: ".score"}},
+		},
+		{
+			name: "regex",
+			conf: &configpb.Validator{Extractor: &configpb.Validator_Regex{Regex: "score: ([0-9.]+)"}, Min: proto.Float64(1), Max: proto.Float64(1)},
+		},
+		{
+			name:    "no_extractor",
+			conf:    &configpb.Validator{},
+			wantErr: true,
+		},
+		{
+			name:    "bad_jq_filter",
+			conf:    &configpb.Validator{Extractor: &configpb.Validator_JqFilter{JqFilter: ".score["}},
+			wantErr: true,
+		},
+		{
+			name:    "bad_regex",
+			conf:    &configpb.Validator{Extractor: &configpb.Validator_Regex{Regex: "score: ([0-9.]+"}},
+			wantErr: true,
+		},
+		{
+			name:    "min_greater_than_max",
+			conf:    &configpb.Validator{Extractor: &configpb.Validator_JqFilter{JqFilter: ".score"}, Min: proto.Float64(2), Max: proto.Float64(1)},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := (&Validator{}).Init(test.conf, nil)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	jqConf := func(filter string) *configpb.Validator {
+		return &configpb.Validator{
+			Extractor: &configpb.Validator_JqFilter{JqFilter: filter},
+			Min:       proto.Float64(0.9),
+			Max:       proto.Float64(1),
+		}
+	}
+	regexConf := func(re string) *configpb.Validator {
+		return &configpb.Validator{
+			Extractor: &configpb.Validator_Regex{Regex: re},
+			Min:       proto.Float64(0.9),
+		}
+	}
+
+	tests := []struct {
+		name      string
+		conf      *configpb.Validator
+		body      string
+		want      bool
+		wantValue float64
+		wantErr   bool
+	}{
+		{
+			name:      "jq_in_range",
+			conf:      jqConf(".health.score"),
+			body:      `{"health": {"score": 0.95}}`,
+			want:      true,
+			wantValue: 0.95,
+		},
+		{
+			name:      "jq_below_min",
+			conf:      jqConf(".health.score"),
+			body:      `{"health": {"score": 0.5}}`,
+			wantValue: 0.5,
+		},
+		{
+			name:      "jq_above_max",
+			conf:      jqConf(".health.score"),
+			body:      `{"health": {"score": 1.5}}`,
+			wantValue: 1.5,
+		},
+		{
+			name:      "jq_string_value",
+			conf:      jqConf(".score"),
+			body:      `{"score": "0.99"}`,
+			want:      true,
+			wantValue: 0.99,
+		},
+		{
+			name:      "jq_int_value",
+			conf:      jqConf(".count | length"),
+			body:      `{"count": [1]}`,
+			want:      true,
+			wantValue: 1,
+		},
+		{
+			name:    "jq_not_a_number",
+			conf:    jqConf(".score"),
+			body:    `{"score": true}`,
+			wantErr: true,
+		},
+		{
+			name:    "jq_missing_field",
+			conf:    jqConf(".score"),
+			body:    `{}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid_json",
+			conf:    jqConf(".score"),
+			body:    `score: 1`,
+			wantErr: true,
+		},
+		{
+			name:      "regex_group",
+			conf:      regexConf("score: ([0-9.]+)"),
+			body:      "status: ok\nscore: 0.92\n",
+			want:      true,
+			wantValue: 0.92,
+		},
+		{
+			name:      "regex_whole_match",
+			conf:      regexConf("[0-9.]+"),
+			body:      "score is 0.2",
+			wantValue: 0.2,
+		},
+		{
+			name:    "regex_no_match",
+			conf:    regexConf("score: ([0-9.]+)"),
+			body:    "status: ok",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := &Validator{}
+			if err := v.Init(test.conf, nil); err != nil {
+				t.Fatalf("Init() error: %v", err)
+			}
+
+			ok, err := v.Validate([]byte(test.body))
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, ok)
+
+			val, err := v.Value([]byte(test.body))
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantValue, val)
+		})
+	}
+}
@@ -18,12 +18,19 @@ package validators
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/cloudprober/cloudprober/internal/validators/baseline"
+	baselinepb "github.com/cloudprober/cloudprober/internal/validators/baseline/proto"
+	"github.com/cloudprober/cloudprober/internal/validators/freshness"
 	"github.com/cloudprober/cloudprober/internal/validators/http"
 	"github.com/cloudprober/cloudprober/internal/validators/integrity"
 	"github.com/cloudprober/cloudprober/internal/validators/json"
+	"github.com/cloudprober/cloudprober/internal/validators/jsonschema"
+	"github.com/cloudprober/cloudprober/internal/validators/notcontains"
 	configpb "github.com/cloudprober/cloudprober/internal/validators/proto"
 	"github.com/cloudprober/cloudprober/internal/validators/regex"
+	"github.com/cloudprober/cloudprober/internal/validators/threshold"
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
 )
@@ -36,6 +43,25 @@ import (
 type Validator struct {
 	Name     string
 	Validate func(input *Input) (bool, error)
+
+	// RequiresBody is true if validator checks the response body, i.e. it
+	// cannot work with the responses that don't have a body, e.g. HTTP HEAD
+	// responses.
+	RequiresBody bool
+
+	// failureKey, if set, returns the validation failure map key for a
+	// failed input. It's used by validators that record the failure reason.
+	failureKey func(input *Input) string
+	// failureKeys are all the possible failure keys, used to initialize the
+	// validation failure map. Validator name is used if not set.
+	failureKeys []string
+
+	// contentAge, if set, returns the age of the validated content.
+	contentAge func(input *Input) (time.Duration, bool)
+
+	// value, if set, returns the value extracted from the input, e.g. by
+	// the threshold validator.
+	value func(input *Input) (float64, bool)
 }
 
 // Init initializes the validators defined in the config.
@@ -65,7 +91,15 @@ func Init(validatorConfs []*configpb.Validator, l *logger.Logger) ([]*Validator,
 }
 
 func initValidator(validatorConf *configpb.Validator, l *logger.Logger) (validator *Validator, err error) {
-	validator = &Validator{Name: validatorConf.Name}
+	// All validators except the HTTP and freshness validators check the
+	// response body.
+	var requiresBody bool
+	switch validatorConf.Type.(type) {
+	case *configpb.Validator_HttpValidator, *configpb.Validator_Freshness:
+	default:
+		requiresBody = true
+	}
+	validator = &Validator{Name: validatorConf.Name, RequiresBody: requiresBody}
 
 	switch validatorConf.Type.(type) {
 	case *configpb.Validator_HttpValidator:
@@ -107,6 +141,93 @@ func initValidator(validatorConf *configpb.Validator, l *logger.Logger) (validat
 			return v.Validate(input.ResponseBody)
 		}
 		return
+
+	case *configpb.Validator_NotContains:
+		v := &notcontains.Validator{}
+		if err := v.Init(validatorConf.GetNotContains(), l); err != nil {
+			return nil, err
+		}
+		validator.Validate = func(input *Input) (bool, error) {
+			return v.Validate(input.ResponseBody)
+		}
+		validator.failureKey = func(input *Input) string {
+			return validator.Name + ":" + v.Match(input.ResponseBody)
+		}
+		for _, pattern := range v.Patterns() {
+			validator.failureKeys = append(validator.failureKeys, validator.Name+":"+pattern)
+		}
+		return
+
+	case *configpb.Validator_JsonSchema:
+		v := &jsonschema.Validator{}
+		if err := v.Init(validatorConf.GetJsonSchema(), l); err != nil {
+			return nil, err
+		}
+		validator.Validate = func(input *Input) (bool, error) {
+			return v.Validate(input.ResponseBody)
+		}
+		validator.failureKey = func(input *Input) string {
+			return validator.Name + ":" + v.Violation(input.ResponseBody)
+		}
+		return
+
+	case *configpb.Validator_Freshness:
+		v := &freshness.Validator{}
+		if err := v.Init(validatorConf.GetFreshness(), l); err != nil {
+			return nil, err
+		}
+		validator.Validate = func(input *Input) (bool, error) {
+			return v.Validate(input.Response)
+		}
+		validator.contentAge = func(input *Input) (time.Duration, bool) {
+			return v.Age(input.Response)
+		}
+		return
+
+	case *configpb.Validator_Threshold:
+		v := &threshold.Validator{}
+		if err := v.Init(validatorConf.GetThreshold(), l); err != nil {
+			return nil, err
+		}
+		validator.Validate = func(input *Input) (bool, error) {
+			return v.Validate(input.ResponseBody)
+		}
+		validator.value = func(input *Input) (float64, bool) {
+			val, err := v.Value(input.ResponseBody)
+			return val, err == nil
+		}
+		return
+
+	case *configpb.Validator_Baseline:
+		v := &baseline.Validator{}
+		if err := v.Init(validatorConf.GetBaseline(), l); err != nil {
+			return nil, err
+		}
+		validator.RequiresBody = validatorConf.GetBaseline().GetMetric() != baselinepb.Validator_LATENCY
+		sample := func(input *Input) (float64, error) {
+			return input.sample(validator, func() (float64, error) {
+				return v.Sample(input.ResponseBody, input.Latency)
+			})
+		}
+		validator.Validate = func(input *Input) (bool, error) {
+			return v.ValidateSample(sample(input))
+		}
+		validator.failureKey = func(input *Input) string {
+			if _, err := sample(input); err != nil {
+				return validator.Name + ":no_value"
+			}
+			return validator.Name + ":anomaly"
+		}
+		validator.failureKeys = []string{validator.Name + ":anomaly", validator.Name + ":no_value"}
+		validator.value = func(input *Input) (float64, bool) {
+			x, err := sample(input)
+			if err != nil {
+				return 0, false
+			}
+			return v.SampleDeviation(x)
+		}
+		return
+
 	default:
 		err = fmt.Errorf("unknown validator type: %v", validatorConf.Type)
 		return
@@ -117,6 +238,33 @@ func initValidator(validatorConf *configpb.Validator, l *logger.Logger) (validat
 type Input struct {
 	Response     interface{}
 	ResponseBody []byte
+
+	// Latency is the probe latency, if available. It's used by the baseline
+	// validator.
+	Latency time.Duration
+
+	// samples caches the samples extracted by the validators, so that a
+	// validator extracts its sample only once per input.
+	samples map[*Validator]sample
+}
+
+type sample struct {
+	val float64
+	err error
+}
+
+// sample returns the validator's sample for the input, extracting it using
+// the extract function the first time.
+func (input *Input) sample(v *Validator, extract func() (float64, error)) (float64, error) {
+	if s, ok := input.samples[v]; ok {
+		return s.val, s.err
+	}
+	val, err := extract()
+	if input.samples == nil {
+		input.samples = make(map[*Validator]sample)
+	}
+	input.samples[v] = sample{val: val, err: err}
+	return val, err
 }
 
 // RunValidators runs the list of validators on the given response and
@@ -132,7 +280,11 @@ func RunValidators(vs []*Validator, input *Input, validationFailure *metrics.Map
 			continue
 		}
 		if !success {
-			validationFailure.IncKey(v.Name)
+			key := v.Name
+			if v.failureKey != nil {
+				key = v.failureKey(input)
+			}
+			validationFailure.IncKey(key)
 			failures = append(failures, v.Name)
 		}
 	}
@@ -146,7 +298,44 @@ func ValidationFailureMap(vs []*Validator) *metrics.Map[int64] {
 	// Initialize validation failure map with validator keys, so that we always
 	// export the metrics.
 	for _, v := range vs {
-		m.IncKeyBy(v.Name, 0)
+		if len(v.failureKeys) == 0 {
+			m.IncKeyBy(v.Name, 0)
+			continue
+		}
+		for _, key := range v.failureKeys {
+			m.IncKeyBy(key, 0)
+		}
 	}
 	return m
 }
+
+// ContentAge returns the age of the content validated by the first validator
+// that tracks it, e.g. the freshness validator. It should be called after
+// RunValidators.
+func ContentAge(vs []*Validator, input *Input) (time.Duration, bool) {
+	for _, v := range vs {
+		if v.contentAge != nil {
+			return v.contentAge(input)
+		}
+	}
+	return 0, false
+}
+
+// Values returns the values extracted from the input by the validators that
+// extract values, e.g. the threshold validator, keyed by the validator name.
+// It returns nil if none of the validators extract values.
+func Values(vs []*Validator, input *Input) map[string]float64 {
+	var values map[string]float64
+	for _, v := range vs {
+		if v.value == nil {
+			continue
+		}
+		if val, ok := v.value(input); ok {
+			if values == nil {
+				values = make(map[string]float64)
+			}
+			values[v.Name] = val
+		}
+	}
+	return values
+}
@@ -15,8 +15,10 @@
 package validators
 
 import (
+	"net/http"
 	"reflect"
 	"testing"
+	"time"
 
 	configpb "github.com/cloudprober/cloudprober/internal/validators/proto"
 	"github.com/stretchr/testify/assert"
@@ -94,6 +96,18 @@ func TestInit(t *testing.T) {
 			},
 			wantNames: []string{"http_status_200s", "found_string", "valid_json", "integrity"},
 		},
+		{
+			name: "not_contains",
+			validatorConfs: []string{
+				`
+					name: "no_errors"
+					not_contains {
+						literal: "Exception"
+					}
+				`,
+			},
+			wantNames: []string{"no_errors"},
+		},
 		{
 			name: "missing name",
 			validatorConfs: []string{
@@ -145,3 +159,160 @@ func TestInit(t *testing.T) {
 		})
 	}
 }
+
+func TestNotContainsFailureKeys(t *testing.T) {
+	vc := &configpb.Validator{}
+	prototext.Unmarshal([]byte(`
+		name: "no_errors"
+		not_contains {
+			literal: "Exception"
+			regex: "[Ee]rror"
+		}
+	`), vc)
+
+	vs, err := Init([]*configpb.Validator{vc}, nil)
+	assert.NoError(t, err)
+
+	vfMap := ValidationFailureMap(vs)
+	assert.Equal(t, []string{"no_errors:Exception", "no_errors:[Ee]rror"}, vfMap.Keys())
+
+	failures := RunValidators(vs, &Input{ResponseBody: []byte("Internal Error")}, vfMap, nil)
+	assert.Equal(t, []string{"no_errors"}, failures)
+	assert.Equal(t, int64(0), vfMap.GetKey("no_errors:Exception"))
+	assert.Equal(t, int64(1), vfMap.GetKey("no_errors:[Ee]rror"))
+
+	failures = RunValidators(vs, &Input{ResponseBody: []byte("OK")}, vfMap, nil)
+	assert.Empty(t, failures)
+}
+
+func TestJSONSchemaFailureKeys(t *testing.T) {
+	vc := &configpb.Validator{}
+	prototext.Unmarshal([]byte(`
+		name: "api_schema"
+		json_schema {
+			schema: '{"type": "object", "properties": {"id": {"type": "integer"}}}'
+		}
+	`), vc)
+
+	vs, err := Init([]*configpb.Validator{vc}, nil)
+	assert.NoError(t, err)
+
+	vfMap := ValidationFailureMap(vs)
+	assert.Equal(t, []string{"api_schema"}, vfMap.Keys())
+
+	failures := RunValidators(vs, &Input{ResponseBody: []byte(`{"id": "x"}`)}, vfMap, nil)
+	assert.Equal(t, []string{"api_schema"}, failures)
+	failures = RunValidators(vs, &Input{ResponseBody: []byte(`not json`)}, vfMap, nil)
+	assert.Equal(t, []string{"api_schema"}, failures)
+	assert.Equal(t, int64(1), vfMap.GetKey("api_schema:/id"))
+	assert.Equal(t, int64(1), vfMap.GetKey("api_schema:invalid_json"))
+
+	failures = RunValidators(vs, &Input{ResponseBody: []byte(`{"id": 1}`)}, vfMap, nil)
+	assert.Empty(t, failures)
+}
+
+func TestRequiresBody(t *testing.T) {
+	var vcs []*configpb.Validator
+	for _, s := range []string{
+		`name: "status" http_validator { success_status_codes: "200" }`,
+		`name: "regex" regex: "ok"`,
+		`name: "fresh" freshness { max_age_sec: 3600 }`,
+	} {
+		vc := &configpb.Validator{}
+		if err := prototext.Unmarshal([]byte(s), vc); err != nil {
+			t.Fatalf("Error parsing validator config: %v", err)
+		}
+		vcs = append(vcs, vc)
+	}
+
+	vs, err := Init(vcs, nil)
+	if err != nil {
+		t.Fatalf("Error initializing validators: %v", err)
+	}
+	assert.False(t, vs[0].RequiresBody, "http validator requires body")
+	assert.True(t, vs[1].RequiresBody, "regex validator requires body")
+	assert.False(t, vs[2].RequiresBody, "freshness validator requires body")
+}
+
+func TestContentAge(t *testing.T) {
+	vc := &configpb.Validator{}
+	prototext.Unmarshal([]byte(`
+		name: "fresh"
+		freshness {
+			max_age_sec: 3600
+		}
+	`), vc)
+
+	vs, err := Init([]*configpb.Validator{vc}, nil)
+	assert.NoError(t, err)
+
+	_, ok := ContentAge(testValidators, &Input{})
+	assert.False(t, ok, "content age without the freshness validator")
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Last-Modified", time.Now().Add(-2*time.Hour).UTC().Format(http.TimeFormat))
+	input := &Input{Response: resp}
+	assert.Equal(t, []string{"fresh"}, RunValidators(vs, input, ValidationFailureMap(vs), nil))
+
+	age, ok := ContentAge(vs, input)
+	assert.True(t, ok)
+	assert.InDelta(t, (2 * time.Hour).Seconds(), age.Seconds(), 5)
+}
+
+func TestValues(t *testing.T) {
+	var vcs []*configpb.Validator
+	for _, s := range []string{
+		`name: "score" threshold { jq_filter: ".score" min: 0.9 }`,
+		`name: "regex" regex: "score"`,
+	} {
+		vc := &configpb.Validator{}
+		if err := prototext.Unmarshal([]byte(s), vc); err != nil {
+			t.Fatalf("Error parsing validator config: %v", err)
+		}
+		vcs = append(vcs, vc)
+	}
+
+	vs, err := Init(vcs, nil)
+	assert.NoError(t, err)
+
+	assert.Nil(t, Values(testValidators, &Input{}), "values without the threshold validator")
+
+	input := &Input{ResponseBody: []byte(`{"score": 0.75}`)}
+	assert.Equal(t, []string{"score"}, RunValidators(vs, input, ValidationFailureMap(vs), nil))
+	assert.Equal(t, map[string]float64{"score": 0.75}, Values(vs, input))
+
+	assert.Nil(t, Values(vs, &Input{ResponseBody: []byte("not json")}), "values for invalid input")
+}
+
+func TestBaselineFailureKeys(t *testing.T) {
+	vc := &configpb.Validator{}
+	prototext.Unmarshal([]byte(`
+		name: "latency"
+		baseline {
+			metric: LATENCY
+			warmup_samples: 2
+		}
+	`), vc)
+
+	vs, err := Init([]*configpb.Validator{vc}, nil)
+	assert.NoError(t, err)
+	assert.False(t, vs[0].RequiresBody, "baseline latency validator requires body")
+
+	vfMap := ValidationFailureMap(vs)
+	assert.Equal(t, []string{"latency:anomaly", "latency:no_value"}, vfMap.Keys())
+
+	for _, latency := range []time.Duration{10 * time.Millisecond, 12 * time.Millisecond} {
+		assert.Empty(t, RunValidators(vs, &Input{Latency: latency}, vfMap, nil), "warmup")
+	}
+	assert.Empty(t, RunValidators(vs, &Input{Latency: 11 * time.Millisecond}, vfMap, nil))
+
+	input := &Input{Latency: 100 * time.Millisecond}
+	assert.Equal(t, []string{"latency"}, RunValidators(vs, input, vfMap, nil))
+	assert.Equal(t, []string{"latency"}, RunValidators(vs, &Input{}, vfMap, nil))
+	assert.Equal(t, int64(1), vfMap.GetKey("latency:anomaly"))
+	assert.Equal(t, int64(1), vfMap.GetKey("latency:no_value"))
+
+	assert.InDelta(t, 62.93, Values(vs, input)["latency"], 0.01)
+	// Sample is extracted once and reused across the validation steps.
+	assert.Equal(t, sample{val: 100}, input.samples[vs[0]])
+}
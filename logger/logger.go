@@ -28,6 +28,7 @@ import (
 	"runtime"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"flag"
@@ -340,11 +341,78 @@ func (l *Logger) gcpLogEntry(r *slog.Record) logging.Entry {
 	}
 }
 
+// MaskedSecret replaces the secret values in the log messages.
+const MaskedSecret = "<redacted>"
+
+// minMaskedSecretLen is the minimum length of a secret value to be masked.
+// Masking very short values would mangle the log messages, without hiding
+// much.
+const minMaskedSecretLen = 4
+
+var secrets = struct {
+	sync.RWMutex
+	values   map[string]bool
+	replacer *strings.Replacer
+}{values: make(map[string]bool)}
+
+// MaskSecret adds the value to the secret values that are masked in the log
+// messages and the string attributes, e.g. secrets resolved while loading the
+// config.
+func MaskSecret(value string) {
+	if len(value) < minMaskedSecretLen {
+		return
+	}
+
+	secrets.Lock()
+	defer secrets.Unlock()
+	if secrets.values[value] {
+		return
+	}
+	secrets.values[value] = true
+
+	// Longer values first, so that a secret containing another secret is
+	// masked as a whole.
+	var values []string
+	for v := range secrets.values {
+		values = append(values, v)
+	}
+	slices.SortFunc(values, func(a, b string) int { return len(b) - len(a) })
+	var oldnew []string
+	for _, v := range values {
+		oldnew = append(oldnew, v, MaskedSecret)
+	}
+	secrets.replacer = strings.NewReplacer(oldnew...)
+}
+
+func maskSecrets(s string) string {
+	secrets.RLock()
+	defer secrets.RUnlock()
+	if secrets.replacer == nil {
+		return s
+	}
+	return secrets.replacer.Replace(s)
+}
+
 // logAttrs logs the message to stderr with the given attributes. If
 // running on GCE, logs are also sent to GCE or cloud logging.
 func (l *Logger) logAttrs(level slog.Level, depth int, msg string, attrs ...slog.Attr) {
 	depth++
 
+	msg = maskSecrets(msg)
+	cloned := false
+	for i, a := range attrs {
+		if a.Value.Kind() != slog.KindString {
+			continue
+		}
+		if masked := maskSecrets(a.Value.String()); masked != a.Value.String() {
+			// Don't modify the caller's attributes.
+			if !cloned {
+				attrs, cloned = slices.Clone(attrs), true
+			}
+			attrs[i].Value = slog.StringValue(masked)
+		}
+	}
+
 	if len(msg) > MaxLogEntrySize {
 		truncateMsg := "... (truncated)"
 		truncateMsgLen := len(truncateMsg)
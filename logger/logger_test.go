@@ -436,3 +436,19 @@ func TestGCPLogEntry(t *testing.T) {
 		})
 	}
 }
+
+func TestMaskSecret(t *testing.T) {
+	MaskSecret("abc")
+	MaskSecret("s3cr3t")
+	MaskSecret("s3cr3t-long")
+
+	var buf bytes.Buffer
+	l := New(WithWriter(&buf))
+	attrs := []slog.Attr{slog.String("token", "s3cr3t"), slog.Int("port", 80)}
+	l.InfoAttrs("token: s3cr3t-long, abc", attrs...)
+
+	assert.NotContains(t, buf.String(), "s3cr3t")
+	assert.Contains(t, buf.String(), "msg=\"token: "+MaskedSecret+", abc\"", "short values are not masked")
+	assert.Contains(t, buf.String(), "token="+MaskedSecret)
+	assert.Equal(t, "s3cr3t", attrs[0].Value.String(), "caller's attributes shouldn't change")
+}
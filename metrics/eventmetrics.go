@@ -121,6 +121,18 @@ func (em *EventMetrics) AddLabel(name string, val string) *EventMetrics {
 	return em
 }
 
+// SetLabel sets the value of a label, adding the label if it doesn't exist
+// already. It returns the receiver EventMetrics to allow for chaining.
+func (em *EventMetrics) SetLabel(name string, val string) *EventMetrics {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	if _, ok := em.labels[name]; !ok {
+		em.labelsKeys = append(em.labelsKeys, name)
+	}
+	em.labels[name] = val
+	return em
+}
+
 // Label returns an EventMetrics label value by name. Label will return a
 // zero-string ("") for a non-existent label.
 func (em *EventMetrics) Label(name string) string {
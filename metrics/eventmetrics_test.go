@@ -167,6 +167,21 @@ func TestKey(t *testing.T) {
 	}
 }
 
+func TestSetLabel(t *testing.T) {
+	m := NewEventMetrics(time.Now()).
+		AddLabel("probe", "p1").
+		AddLabel("dst", "web-1.prod.internal")
+
+	m.SetLabel("dst", "web-1").SetLabel("zone", "z1")
+
+	if got := m.Label("dst"); got != "web-1" {
+		t.Errorf("Label(dst)=%s, wanted: web-1", got)
+	}
+	if key, wantKey := m.Key(), "probe=p1,dst=web-1,zone=z1"; key != wantKey {
+		t.Errorf("Got key: %s, wanted: %s", key, wantKey)
+	}
+}
+
 func BenchmarkEventMetricsStringer(b *testing.B) {
 	em := newEventMetrics(32, 22, 220100, map[string]int64{
 		"200": 22,
@@ -0,0 +1,119 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	pb "github.com/cloudprober/cloudprober/prober/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// adminHTTPPathPrefix is the path prefix for the JSON over HTTP interface of
+// the Cloudprober gRPC service. Methods are served at the
+// adminHTTPPathPrefix + <method name> paths.
+const adminHTTPPathPrefix = "/admin/v1/"
+
+// maxAdminRequestSize is the maximum size of the admin HTTP request body.
+const maxAdminRequestSize = 1 << 20
+
+// adminMethod returns the handler function for a gRPC method, that takes and
+// returns JSON encoded messages.
+func adminMethod[Req, Resp proto.Message](newReq func() Req, call func(context.Context, Req) (Resp, error)) func(context.Context, []byte) ([]byte, error) {
+	return func(ctx context.Context, body []byte) ([]byte, error) {
+		req := newReq()
+		if len(body) > 0 {
+			if err := protojson.Unmarshal(body, req); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "error parsing the request: %v", err)
+			}
+		}
+		resp, err := call(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return protojson.Marshal(resp)
+	}
+}
+
+// httpStatus returns the HTTP status code corresponding to the gRPC status
+// code of the error.
+func httpStatus(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// adminHTTPHandler returns the handler for the JSON over HTTP interface of
+// the Cloudprober gRPC service. As the HTTP interface is not authenticated,
+// the methods that change the running probes (AddProbe, RemoveProbe,
+// PauseProbe and ResumeProbe) are available only if explicitly enabled in the
+// config.
+func (pr *Prober) adminHTTPHandler() http.Handler {
+	methods := map[string]func(context.Context, []byte) ([]byte, error){
+		"ListProbes": adminMethod(func() *pb.ListProbesRequest { return &pb.ListProbesRequest{} }, pr.ListProbes),
+		"RunProbe":   adminMethod(func() *pb.RunProbeRequest { return &pb.RunProbeRequest{} }, pr.RunProbe),
+		"GetConfig":  adminMethod(func() *pb.GetConfigRequest { return &pb.GetConfigRequest{} }, pr.GetConfig),
+	}
+	if pr.c.GetAdminHttpApiAllowAddRemove() {
+		methods["AddProbe"] = adminMethod(func() *pb.AddProbeRequest { return &pb.AddProbeRequest{} }, pr.AddProbe)
+		methods["RemoveProbe"] = adminMethod(func() *pb.RemoveProbeRequest { return &pb.RemoveProbeRequest{} }, pr.RemoveProbe)
+		methods["PauseProbe"] = adminMethod(func() *pb.PauseProbeRequest { return &pb.PauseProbeRequest{} }, pr.PauseProbe)
+		methods["ResumeProbe"] = adminMethod(func() *pb.ResumeProbeRequest { return &pb.ResumeProbeRequest{} }, pr.ResumeProbe)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := methods[strings.TrimPrefix(r.URL.Path, adminHTTPPathPrefix)]
+		if method == nil {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "only POST requests are supported", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxAdminRequestSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp, err := method(r.Context(), body)
+		if err != nil {
+			http.Error(w, status.Convert(err).Message(), httpStatus(err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(resp)
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAdminHTTPHandler(t *testing.T) {
+	pr := testProber()
+	pr.ActiveConfig = func() string { return "probe {}" }
+	h := pr.adminHTTPHandler()
+
+	if err := pr.addProbe(testProbeDef("test-probe")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		desc, method, path, body string
+		wantCode                 int
+		wantBody                 string
+	}{
+		{
+			desc:     "get_config",
+			method:   http.MethodPost,
+			path:     "/admin/v1/GetConfig",
+			wantCode: http.StatusOK,
+			wantBody: `"config":"probe {}"`,
+		},
+		{
+			desc:     "list_probes",
+			method:   http.MethodPost,
+			path:     "/admin/v1/ListProbes",
+			body:     "{}",
+			wantCode: http.StatusOK,
+			wantBody: `"name":"test-probe"`,
+		},
+		{
+			desc:     "not_found_probe",
+			method:   http.MethodPost,
+			path:     "/admin/v1/RunProbe",
+			body:     `{"probe_name": "unknown"}`,
+			wantCode: http.StatusNotFound,
+			wantBody: "probe unknown not found",
+		},
+		{
+			desc:     "bad_request",
+			method:   http.MethodPost,
+			path:     "/admin/v1/RunProbe",
+			body:     `{"probe": "test-probe"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc:     "get_request",
+			method:   http.MethodGet,
+			path:     "/admin/v1/ListProbes",
+			wantCode: http.StatusMethodNotAllowed,
+		},
+		{
+			desc:     "pause_probe_not_allowed",
+			method:   http.MethodPost,
+			path:     "/admin/v1/PauseProbe",
+			body:     `{"probe_name": "test-probe"}`,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			desc:     "remove_probe_not_allowed",
+			method:   http.MethodPost,
+			path:     "/admin/v1/RemoveProbe",
+			body:     `{"probe_name": "test-probe"}`,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			desc:     "unknown_method",
+			method:   http.MethodPost,
+			path:     "/admin/v1/DeleteEverything",
+			wantCode: http.StatusNotFound,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(test.method, test.path, strings.NewReader(test.body)))
+			assert.Equal(t, test.wantCode, w.Code, "body: %s", w.Body.String())
+			// protojson output may have random spaces, remove them.
+			assert.Contains(t, strings.ReplaceAll(w.Body.String(), " ", ""), strings.ReplaceAll(test.wantBody, " ", ""))
+		})
+	}
+}
+
+func TestAdminHTTPHandlerAddRemove(t *testing.T) {
+	pr := testProber()
+	pr.c = &configpb.ProberConfig{AdminHttpApiAllowAddRemove: proto.Bool(true)}
+	h := pr.adminHTTPHandler()
+
+	if err := pr.addProbe(testProbeDef("test-probe")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, method := range []string{"PauseProbe", "ResumeProbe", "RemoveProbe"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/v1/"+method, strings.NewReader(`{"probe_name": "test-probe"}`)))
+		assert.Equal(t, http.StatusOK, w.Code, "method: %s, body: %s", method, w.Body.String())
+	}
+	assert.Empty(t, pr.Probes)
+}
@@ -0,0 +1,183 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes"
+)
+
+const (
+	availabilityMetricName     = "probe_availability_ratio"
+	reportingTargetsMetricName = "probe_targets_reporting"
+)
+
+// targetResult is the latest result of a probe's target.
+type targetResult struct {
+	total, success int64
+	ok             bool
+	lastSeen       time.Time
+}
+
+// availabilityTracker tracks the probes' per-target results, to compute the
+// per-probe availability.
+type availabilityTracker struct {
+	mu      sync.Mutex
+	results map[string]map[string]*targetResult
+
+	// tokens identify the current registration of the probes. A probe can be
+	// re-registered (e.g. re-added with the same name) before its previous
+	// registration is removed.
+	tokens    map[string]uint64
+	lastToken uint64
+}
+
+func newAvailabilityTracker() *availabilityTracker {
+	return &availabilityTracker{
+		results: make(map[string]map[string]*targetResult),
+		tokens:  make(map[string]uint64),
+	}
+}
+
+// register starts tracking the given probe. It returns a token that should
+// be passed to unregister.
+func (at *availabilityTracker) register(probe string) uint64 {
+	at.mu.Lock()
+	defer at.mu.Unlock()
+	at.lastToken++
+	at.results[probe] = make(map[string]*targetResult)
+	at.tokens[probe] = at.lastToken
+	return at.lastToken
+}
+
+// unregister stops tracking the given probe, if it's still registered with
+// the given token, i.e. it was not re-registered since.
+func (at *availabilityTracker) unregister(probe string, token uint64) {
+	at.mu.Lock()
+	defer at.mu.Unlock()
+	if at.tokens[probe] != token {
+		return
+	}
+	delete(at.results, probe)
+	delete(at.tokens, probe)
+}
+
+// observe records the target's result in the EventMetrics, if it belongs to
+// a tracked probe. A target's result is successful if all its probe runs
+// since the last EventMetrics succeeded.
+func (at *availabilityTracker) observe(em *metrics.EventMetrics) {
+	at.mu.Lock()
+	defer at.mu.Unlock()
+
+	probe, dst := em.Label("probe"), em.Label("dst")
+	results, ok := at.results[probe]
+	if !ok || dst == "" {
+		return
+	}
+	totalV, ok := em.Metric("total").(metrics.NumValue)
+	if !ok {
+		return
+	}
+	total, success := totalV.Int64(), int64(0)
+	if sv, ok := em.Metric("success").(metrics.NumValue); ok {
+		success = sv.Int64()
+	}
+
+	r := results[dst]
+	if r == nil {
+		r = &targetResult{}
+		results[dst] = r
+	}
+	dTotal, dSuccess := total-r.total, success-r.success
+	// Counters went back, probably because the probe was restarted.
+	if dTotal < 0 {
+		dTotal, dSuccess = total, success
+	}
+	// If there were no new runs, target's last result still holds.
+	if dTotal > 0 {
+		r.ok = dSuccess >= dTotal
+	}
+	r.total, r.success, r.lastSeen = total, success, em.Timestamp
+}
+
+// availability returns the number of probe's targets with successful results
+// and the number of targets that have reported within maxAge of ts. Targets
+// that haven't reported within maxAge are forgotten.
+func (at *availabilityTracker) availability(probe string, ts time.Time, maxAge time.Duration) (successful, reporting int) {
+	at.mu.Lock()
+	defer at.mu.Unlock()
+
+	for dst, r := range at.results[probe] {
+		if ts.Sub(r.lastSeen) > maxAge || r.total == 0 {
+			delete(at.results[probe], dst)
+			continue
+		}
+		reporting++
+		if r.ok {
+			successful++
+		}
+	}
+	return successful, reporting
+}
+
+// availabilityEM returns the EventMetrics that reports probe's availability.
+// Availability ratio is not reported if no targets reported results, as it's
+// undefined in that case.
+func availabilityEM(p *probes.ProbeInfo, successful, reporting int, ts time.Time) *metrics.EventMetrics {
+	em := metrics.NewEventMetrics(ts).
+		AddMetric(reportingTargetsMetricName, metrics.NewInt(int64(reporting))).
+		AddLabel("ptype", strings.ToLower(p.Type)).
+		AddLabel("probe", p.Name)
+	if reporting > 0 {
+		em.AddMetric(availabilityMetricName, metrics.NewFloat(float64(successful)/float64(reporting)))
+	}
+	em.Kind = metrics.GAUGE
+	return em
+}
+
+// checkAvailability exports the probe's availability at the stats export
+// interval, until the context is canceled. It does nothing if availability
+// export is not enabled.
+func (pr *Prober) checkAvailability(ctx context.Context, p *probes.ProbeInfo) {
+	if pr.availability == nil {
+		return
+	}
+	token := pr.availability.register(p.Name)
+	defer pr.availability.unregister(p.Name, token)
+
+	interval := p.Options.StatsExportInterval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ts := <-ticker.C:
+			// Allow for some delay in the targets' results.
+			successful, reporting := pr.availability.availability(p.Name, ts, 2*interval)
+			select {
+			case pr.dataChan <- availabilityEM(p, successful, reporting, ts):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
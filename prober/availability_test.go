@@ -0,0 +1,155 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTargetEM(probe, dst string, total, success int64, ts time.Time) *metrics.EventMetrics {
+	return metrics.NewEventMetrics(ts).
+		AddMetric("total", metrics.NewInt(total)).
+		AddMetric("success", metrics.NewInt(success)).
+		AddLabel("probe", probe).
+		AddLabel("dst", dst)
+}
+
+func TestAvailabilityTracker(t *testing.T) {
+	at := newAvailabilityTracker()
+	token := at.register("p1")
+	ts := time.Now()
+
+	// Not tracked probe.
+	at.observe(testTargetEM("p2", "t1", 1, 1, ts))
+	s, r := at.availability("p2", ts, time.Minute)
+	assert.Equal(t, [2]int{0, 0}, [2]int{s, r})
+
+	// Zero targets.
+	s, r = at.availability("p1", ts, time.Minute)
+	assert.Equal(t, [2]int{0, 0}, [2]int{s, r})
+
+	at.observe(testTargetEM("p1", "t1", 2, 2, ts))
+	at.observe(testTargetEM("p1", "t2", 2, 1, ts))
+	at.observe(testTargetEM("p1", "t3", 0, 0, ts)) // No runs yet.
+	s, r = at.availability("p1", ts, time.Minute)
+	assert.Equal(t, [2]int{1, 2}, [2]int{s, r}, "first interval")
+
+	// t1 fails, t2 recovers.
+	ts = ts.Add(30 * time.Second)
+	at.observe(testTargetEM("p1", "t1", 4, 3, ts))
+	at.observe(testTargetEM("p1", "t2", 4, 3, ts))
+	s, r = at.availability("p1", ts, time.Minute)
+	assert.Equal(t, [2]int{1, 2}, [2]int{s, r}, "second interval")
+	assert.False(t, at.results["p1"]["t1"].ok)
+	assert.True(t, at.results["p1"]["t2"].ok)
+
+	// t1 has no new runs, its last result holds. t2's counters are reset.
+	ts = ts.Add(30 * time.Second)
+	at.observe(testTargetEM("p1", "t1", 4, 3, ts))
+	at.observe(testTargetEM("p1", "t2", 1, 1, ts))
+	s, r = at.availability("p1", ts, time.Minute)
+	assert.Equal(t, [2]int{1, 2}, [2]int{s, r}, "third interval")
+
+	// t2 stops reporting and is forgotten after maxAge.
+	at.observe(testTargetEM("p1", "t1", 5, 4, ts.Add(2*time.Minute)))
+	s, r = at.availability("p1", ts.Add(2*time.Minute), time.Minute)
+	assert.Equal(t, [2]int{1, 1}, [2]int{s, r}, "stale target")
+
+	at.unregister("p1", token)
+	at.observe(testTargetEM("p1", "t1", 6, 6, ts))
+	assert.Len(t, at.results, 0)
+}
+
+func TestAvailabilityTrackerReRegister(t *testing.T) {
+	at := newAvailabilityTracker()
+	oldToken := at.register("p1")
+	newToken := at.register("p1")
+
+	// Old registration going away doesn't affect the new one.
+	at.unregister("p1", oldToken)
+	at.observe(testTargetEM("p1", "t1", 1, 1, time.Now()))
+	assert.Len(t, at.results["p1"], 1)
+
+	at.unregister("p1", newToken)
+	assert.Len(t, at.results, 0)
+}
+
+func TestAvailabilityEM(t *testing.T) {
+	p := &probes.ProbeInfo{Name: "test-probe", Type: "PING"}
+	ts := time.Now()
+
+	em := availabilityEM(p, 3, 4, ts)
+	assert.True(t, em.Kind == metrics.GAUGE, "metrics kind")
+	assert.Equal(t, "ping", em.Label("ptype"))
+	assert.Equal(t, "test-probe", em.Label("probe"))
+	assert.Equal(t, int64(4), em.Metric(reportingTargetsMetricName).(*metrics.Int).Int64())
+	assert.Equal(t, 0.75, em.Metric(availabilityMetricName).(*metrics.Float).Float64())
+
+	// No targets: ratio is not reported.
+	em = availabilityEM(p, 0, 0, ts)
+	assert.Equal(t, int64(0), em.Metric(reportingTargetsMetricName).(*metrics.Int).Int64())
+	assert.Nil(t, em.Metric(availabilityMetricName))
+}
+
+func TestCheckAvailability(t *testing.T) {
+	p := &probes.ProbeInfo{
+		Options: &options.Options{
+			StatsExportInterval: 10 * time.Millisecond,
+			// Prefix is added by the surfacers, results use the plain names.
+			MetricPrefix: "pfx_",
+		},
+		Name: "test-probe",
+		Type: "HTTP",
+	}
+	pr := &Prober{dataChan: make(chan *metrics.EventMetrics, 10)}
+
+	// Not enabled.
+	pr.checkAvailability(context.Background(), p)
+	assert.Len(t, pr.dataChan, 0)
+
+	pr.availability = newAvailabilityTracker()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pr.checkAvailability(ctx, p)
+
+	// Wait for the probe to be registered.
+	for {
+		pr.availability.mu.Lock()
+		_, ok := pr.availability.results["test-probe"]
+		pr.availability.mu.Unlock()
+		if ok {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	pr.availability.observe(testTargetEM("test-probe", "t1", 1, 0, time.Now()))
+
+	for {
+		em := <-pr.dataChan
+		if em.Metric(reportingTargetsMetricName).(*metrics.Int).Int64() == 0 {
+			continue
+		}
+		assert.Equal(t, int64(1), em.Metric(reportingTargetsMetricName).(*metrics.Int).Int64())
+		assert.Equal(t, 0.0, em.Metric(availabilityMetricName).(*metrics.Float).Float64())
+		break
+	}
+}
@@ -17,6 +17,9 @@
 //
 // go run ./cmd/client.go --server localhost:9314 --add_probe newprobe.cfg
 // go run ./cmd/client.go --server localhost:9314 --rm_probe newprobe
+// go run ./cmd/client.go --server localhost:9314 --list_probes
+// go run ./cmd/client.go --server localhost:9314 --pause_probe newprobe
+// go run ./cmd/client.go --server localhost:9314 --run_probe newprobe --target www.example.com
 package main
 
 import (
@@ -37,6 +40,12 @@ var (
 	server   = flag.String("server", "", "gRPC server address")
 	addProbe = flag.String("add_probe", "", "Path to probe config to add")
 	rmProbe  = flag.String("rm_probe", "", "Probe name to remove")
+
+	listProbes  = flag.Bool("list_probes", false, "List probes")
+	pauseProbe  = flag.String("pause_probe", "", "Probe name to pause")
+	resumeProbe = flag.String("resume_probe", "", "Probe name to resume")
+	runProbe    = flag.String("run_probe", "", "Probe name to run once, against the --target")
+	target      = flag.String("target", "", "Target for --run_probe")
 )
 
 func main() {
@@ -78,4 +87,34 @@ func main() {
 			log.Fatal(err)
 		}
 	}
+
+	if *pauseProbe != "" {
+		if _, err := client.PauseProbe(context.Background(), &pb.PauseProbeRequest{ProbeName: pauseProbe}); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *resumeProbe != "" {
+		if _, err := client.ResumeProbe(context.Background(), &pb.ResumeProbeRequest{ProbeName: resumeProbe}); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *runProbe != "" {
+		resp, err := client.RunProbe(context.Background(), &pb.RunProbeRequest{ProbeName: runProbe, Target: target})
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Success: %v, metrics: %s", resp.GetSuccess(), resp.GetMetrics())
+	}
+
+	if *listProbes {
+		resp, err := client.ListProbes(context.Background(), &pb.ListProbesRequest{})
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, p := range resp.GetProbe() {
+			log.Printf("%s: %s, targets: %v", p.GetName(), p.GetStatus(), p.GetTarget())
+		}
+	}
 }
@@ -0,0 +1,57 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/probeutils"
+)
+
+const connLimiterWaitMetricName = "conn_limiter_wait_msec"
+
+// connLimiterEM returns the EventMetrics that reports the current wait time
+// for new connections, as per the global connection rate limit.
+func connLimiterEM(ts time.Time) *metrics.EventMetrics {
+	wait := probeutils.ConnLimiterWaitTime()
+	em := metrics.NewEventMetrics(ts).
+		AddMetric(connLimiterWaitMetricName, metrics.NewFloat(float64(wait)/float64(time.Millisecond))).
+		AddLabel("ptype", "sysvars").
+		AddLabel("probe", "conn_limiter")
+	em.Kind = metrics.GAUGE
+	return em
+}
+
+// exportConnLimiterWait exports the connection rate limiter wait time at the
+// given interval.
+func (pr *Prober) exportConnLimiterWait(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ts := <-ticker.C:
+			select {
+			case pr.dataChan <- connLimiterEM(ts):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/config/runconfig"
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/probeutils"
+	surfacerpb "github.com/cloudprober/cloudprober/surfacers/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestConnRateLimit(t *testing.T) {
+	defer probeutils.SetConnRateLimit(0, 0)
+	runconfig.SetDefaultHTTPServeMux(http.NewServeMux())
+
+	noSurfacers := []*surfacerpb.SurfacerDef{{}}
+	pr := &Prober{}
+	cfg := &configpb.ProberConfig{
+		Surfacer: noSurfacers,
+		ConnRateLimit: &configpb.ConnRateLimit{
+			Rate:  proto.Float32(0.01),
+			Burst: proto.Int32(1),
+		},
+	}
+	if err := pr.Init(context.Background(), cfg, &logger.Logger{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assert.True(t, probeutils.ConnRateLimited())
+
+	em := connLimiterEM(time.Now())
+	assert.Equal(t, metrics.Kind(metrics.GAUGE), em.Kind)
+	assert.Equal(t, "0.000", em.Metric(connLimiterWaitMetricName).String())
+
+	// Use up the burst.
+	probeutils.WaitForConnRateLimit(context.Background())
+	wait := em.Metric(connLimiterWaitMetricName)
+	em = connLimiterEM(time.Now())
+	assert.NotEqual(t, wait.String(), em.Metric(connLimiterWaitMetricName).String())
+
+	// Rate limit is removed if it's not configured anymore.
+	runconfig.SetDefaultHTTPServeMux(http.NewServeMux())
+	if err := pr.Init(context.Background(), &configpb.ProberConfig{Surfacer: noSurfacers}, &logger.Logger{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assert.False(t, probeutils.ConnRateLimited())
+}
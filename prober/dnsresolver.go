@@ -0,0 +1,44 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudprober/cloudprober/targets"
+)
+
+// exportDNSResolverMetrics exports the DNS-over-HTTPS resolvers' query
+// metrics at the given interval.
+func (pr *Prober) exportDNSResolverMetrics(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ts := <-ticker.C:
+			for _, em := range targets.DNSResolverMetrics(ts) {
+				select {
+				case pr.dataChan <- em:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
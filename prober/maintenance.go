@@ -0,0 +1,73 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cloudprober/cloudprober/internal/alerting"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+)
+
+const maintenanceMetricName = "in_maintenance"
+
+// maintenanceEM returns the EventMetrics that reports whether probe's target
+// is in an active maintenance window.
+func maintenanceEM(p *probes.ProbeInfo, ep endpoint.Endpoint, active bool, ts time.Time) *metrics.EventMetrics {
+	var v int64
+	if active {
+		v = 1
+	}
+	em := metrics.NewEventMetrics(ts).
+		AddMetric(maintenanceMetricName, metrics.NewInt(v)).
+		AddLabel("ptype", strings.ToLower(p.Type)).
+		AddLabel("probe", p.Name).
+		AddLabel("dst", probes.DstLabel(p, ep))
+	em.Kind = metrics.GAUGE
+	return em
+}
+
+// checkMaintenance exports the maintenance status of the probe's targets that
+// are in the scope of a maintenance window, at the stats export interval,
+// until the context is canceled.
+func (pr *Prober) checkMaintenance(ctx context.Context, p *probes.ProbeInfo) {
+	if !alerting.HasMaintenanceWindows() {
+		return
+	}
+
+	ticker := time.NewTicker(p.Options.StatsExportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ts := <-ticker.C:
+			for _, ep := range p.Options.Targets.ListEndpoints() {
+				if active, inScope := alerting.MaintenanceStatus(p.Name, ep, ts); inScope {
+					select {
+					case pr.dataChan <- maintenanceEM(p, ep, active, ts):
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}
+}
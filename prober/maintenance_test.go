@@ -0,0 +1,93 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/internal/alerting"
+	alertpb "github.com/cloudprober/cloudprober/internal/alerting/proto"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes"
+	httpprobe "github.com/cloudprober/cloudprober/probes/http"
+	"github.com/cloudprober/cloudprober/probes/options"
+	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckMaintenance(t *testing.T) {
+	// An active window for db targets, and an inactive window for web
+	// targets.
+	assert.NoError(t, alerting.SetMaintenanceWindows([]*alertpb.MaintenanceWindow{
+		{
+			StartTime:   time.Now().Add(-time.Minute).Format(time.RFC3339),
+			Duration:    "1h",
+			TargetRegex: "^db-",
+		},
+		{
+			StartTime:   time.Now().Add(time.Hour).Format(time.RFC3339),
+			Duration:    "1h",
+			TargetRegex: "^web-",
+		},
+	}))
+	defer alerting.SetMaintenanceWindows(nil)
+
+	// HTTP probe's dst label doesn't include the port.
+	p := &probes.ProbeInfo{
+		Probe:    &httpprobe.Probe{},
+		ProbeDef: &probes_configpb.ProbeDef{Type: probes_configpb.ProbeDef_HTTP.Enum()},
+		Options: &options.Options{
+			Targets: &testTargets{endpoints: []endpoint.Endpoint{
+				{Name: "db-1", Port: 5432}, {Name: "web-1"}, {Name: "cache-1"},
+			}},
+			StatsExportInterval: 10 * time.Millisecond,
+		},
+		Name: "test-probe",
+		Type: "HTTP",
+	}
+	pr := &Prober{dataChan: make(chan *metrics.EventMetrics, 10)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pr.checkMaintenance(ctx, p)
+
+	// Targets out of the windows' scope (cache-1) are not reported.
+	got := make(map[string]int64)
+	for i := 0; i < 2; i++ {
+		em := <-pr.dataChan
+		assert.True(t, em.Kind == metrics.GAUGE, "metrics kind")
+		assert.Equal(t, "http", em.Label("ptype"))
+		assert.Equal(t, "test-probe", em.Label("probe"))
+		got[em.Label("dst")] = em.Metric(maintenanceMetricName).(*metrics.Int).Int64()
+	}
+	assert.Equal(t, map[string]int64{"db-1": 1, "web-1": 0}, got)
+}
+
+func TestCheckMaintenanceNoWindows(t *testing.T) {
+	p := &probes.ProbeInfo{
+		Options: &options.Options{
+			Targets:             &testTargets{endpoints: []endpoint.Endpoint{{Name: "db-1"}}},
+			StatsExportInterval: time.Millisecond,
+		},
+	}
+	pr := &Prober{dataChan: make(chan *metrics.EventMetrics, 10)}
+
+	// Returns right away if there are no maintenance windows.
+	pr.checkMaintenance(context.Background(), p)
+	assert.Len(t, pr.dataChan, 0)
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes"
+	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
+)
+
+const noTargetsMetricName = "no_targets"
+
+// noTargetsChecker implements probe's no_targets_policy.
+type noTargetsChecker struct {
+	p         *probes.ProbeInfo
+	noTargets bool
+}
+
+// noTargetsEM returns the EventMetrics that reports whether probe has targets
+// or not.
+func (c *noTargetsChecker) noTargetsEM(ts time.Time) *metrics.EventMetrics {
+	var v int64
+	if c.noTargets {
+		v = 1
+	}
+	em := metrics.NewEventMetrics(ts).
+		AddMetric(noTargetsMetricName, metrics.NewInt(v)).
+		AddLabel("ptype", strings.ToLower(c.p.Type)).
+		AddLabel("probe", c.p.Name)
+	em.Kind = metrics.GAUGE
+	return em
+}
+
+// check checks probe's targets and returns the EventMetrics to export, if
+// any, as per the probe's no_targets_policy.
+func (c *noTargetsChecker) check(ts time.Time) *metrics.EventMetrics {
+	noTargets := len(c.p.Options.Targets.ListEndpoints()) == 0
+	becameEmpty := noTargets && !c.noTargets
+	c.noTargets = noTargets
+
+	l := c.p.Options.Logger
+	switch c.p.ProbeDef.GetNoTargetsPolicy() {
+	case probes_configpb.ProbeDef_WARN:
+		if becameEmpty {
+			l.Warningf("Probe %s has no targets", c.p.Name)
+		}
+	case probes_configpb.ProbeDef_ERROR:
+		if becameEmpty {
+			l.Errorf("Probe %s has no targets", c.p.Name)
+		}
+		return c.noTargetsEM(ts)
+	case probes_configpb.ProbeDef_EMIT_ZERO:
+		return c.noTargetsEM(ts)
+	}
+	return nil
+}
+
+// checkNoTargets checks probe's targets at the stats export interval, as per
+// the probe's no_targets_policy, until the context is canceled.
+func (pr *Prober) checkNoTargets(ctx context.Context, p *probes.ProbeInfo) {
+	if p.ProbeDef.GetNoTargetsPolicy() == probes_configpb.ProbeDef_IGNORE {
+		return
+	}
+
+	c := &noTargetsChecker{p: p}
+	ticker := time.NewTicker(p.Options.StatsExportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ts := <-ticker.C:
+			if em := c.check(ts); em != nil {
+				select {
+				case pr.dataChan <- em:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
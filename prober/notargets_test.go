@@ -0,0 +1,83 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes"
+	"github.com/cloudprober/cloudprober/probes/options"
+	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTargets struct {
+	endpoints []endpoint.Endpoint
+}
+
+func (tt *testTargets) ListEndpoints() []endpoint.Endpoint {
+	return tt.endpoints
+}
+
+func (tt *testTargets) Resolve(name string, ipVer int) (net.IP, error) {
+	return nil, nil
+}
+
+func TestNoTargetsChecker(t *testing.T) {
+	for _, policy := range []probes_configpb.ProbeDef_NoTargetsPolicy{
+		probes_configpb.ProbeDef_WARN,
+		probes_configpb.ProbeDef_ERROR,
+		probes_configpb.ProbeDef_EMIT_ZERO,
+	} {
+		t.Run(policy.String(), func(t *testing.T) {
+			tgts := &testTargets{}
+			c := &noTargetsChecker{
+				p: &probes.ProbeInfo{
+					ProbeDef: &probes_configpb.ProbeDef{NoTargetsPolicy: policy.Enum()},
+					Options:  &options.Options{Targets: tgts},
+					Name:     "test-probe",
+					Type:     "HTTP",
+				},
+			}
+
+			wantEM := policy != probes_configpb.ProbeDef_WARN
+			for _, endpoints := range [][]endpoint.Endpoint{nil, {{Name: "t1"}}} {
+				tgts.endpoints = endpoints
+
+				em := c.check(time.Now())
+				if !wantEM {
+					assert.Nil(t, em)
+					continue
+				}
+				if !assert.NotNil(t, em) {
+					continue
+				}
+
+				var want int64
+				if len(endpoints) == 0 {
+					want = 1
+				}
+				assert.Equal(t, want, em.Metric(noTargetsMetricName).(*metrics.Int).Int64())
+				assert.Equal(t, metrics.Kind(metrics.GAUGE), em.Kind)
+				assert.Equal(t, "http", em.Label("ptype"))
+				assert.Equal(t, "test-probe", em.Label("probe"))
+			}
+		})
+	}
+}
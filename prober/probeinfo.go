@@ -0,0 +1,96 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes"
+	"google.golang.org/protobuf/proto"
+)
+
+const probeInfoMetricName = "probe_info"
+
+// probeConfigVersion returns a short hash of the probe definition. It changes
+// whenever probe's configuration changes.
+func probeConfigVersion(p *probes.ProbeInfo) string {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(p.ProbeDef)
+	if err != nil {
+		return ""
+	}
+	h := fnv.New32a()
+	h.Write(b)
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// probeInfoEM returns an info-style EventMetrics for the given probe.
+func probeInfoEM(p *probes.ProbeInfo, ts time.Time) *metrics.EventMetrics {
+	numTargets := 0
+	if p.Options != nil && p.Options.Targets != nil {
+		numTargets = len(p.Options.Targets.ListEndpoints())
+	}
+
+	em := metrics.NewEventMetrics(ts).
+		AddLabel("ptype", strings.ToLower(p.Type)).
+		AddLabel("probe", p.Name).
+		AddLabel("interval", p.Interval).
+		AddLabel("timeout", p.Timeout).
+		AddLabel("num_targets", strconv.Itoa(numTargets)).
+		AddLabel("config_version", probeConfigVersion(p)).
+		AddMetric(probeInfoMetricName, metrics.NewInt(1))
+	em.Kind = metrics.GAUGE
+	return em
+}
+
+// exportProbeInfo exports probe info metrics for all probes at the given
+// interval.
+func (pr *Prober) exportProbeInfo(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ts := <-ticker.C:
+			pr.mu.Lock()
+			var names []string
+			for name := range pr.Probes {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			var probeInfos []*probes.ProbeInfo
+			for _, name := range names {
+				probeInfos = append(probeInfos, pr.Probes[name])
+			}
+			pr.mu.Unlock()
+
+			for _, p := range probeInfos {
+				select {
+				case pr.dataChan <- probeInfoEM(p, ts):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
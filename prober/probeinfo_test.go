@@ -0,0 +1,117 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes"
+	"github.com/cloudprober/cloudprober/probes/options"
+	configpb "github.com/cloudprober/cloudprober/probes/proto"
+	"github.com/cloudprober/cloudprober/targets"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func testProbeInfo(name, interval string) *probes.ProbeInfo {
+	return &probes.ProbeInfo{
+		Name:     name,
+		Type:     "HTTP",
+		Interval: interval,
+		Timeout:  "1s",
+		ProbeDef: &configpb.ProbeDef{
+			Name:     proto.String(name),
+			Type:     configpb.ProbeDef_HTTP.Enum(),
+			Interval: proto.String(interval),
+		},
+		Options: &options.Options{
+			Targets: targets.StaticTargets("host1,host2"),
+		},
+	}
+}
+
+func TestProbeInfoEM(t *testing.T) {
+	ts := time.Now()
+	em := probeInfoEM(testProbeInfo("http1", "10s"), ts)
+
+	assert.Equal(t, metrics.Kind(metrics.GAUGE), em.Kind)
+	assert.Equal(t, ts, em.Timestamp)
+	assert.Equal(t, "1", em.Metric(probeInfoMetricName).String())
+
+	for k, v := range map[string]string{
+		"ptype":       "http",
+		"probe":       "http1",
+		"interval":    "10s",
+		"timeout":     "1s",
+		"num_targets": "2",
+	} {
+		assert.Equal(t, v, em.Label(k), k)
+	}
+	assert.Len(t, em.Label("config_version"), 8)
+}
+
+func TestProbeConfigVersion(t *testing.T) {
+	v1 := probeConfigVersion(testProbeInfo("http1", "10s"))
+	assert.Equal(t, v1, probeConfigVersion(testProbeInfo("http1", "10s")), "same config")
+	assert.NotEqual(t, v1, probeConfigVersion(testProbeInfo("http1", "20s")), "different config")
+}
+
+func TestExportProbeInfo(t *testing.T) {
+	pr := &Prober{
+		Probes: map[string]*probes.ProbeInfo{
+			"p2": testProbeInfo("p2", "10s"),
+			"p1": testProbeInfo("p1", "10s"),
+		},
+		dataChan: make(chan *metrics.EventMetrics, 10),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pr.exportProbeInfo(ctx, 10*time.Millisecond)
+
+	var gotProbes []string
+	for i := 0; i < 2; i++ {
+		em := <-pr.dataChan
+		gotProbes = append(gotProbes, em.Label("probe"))
+	}
+	assert.Equal(t, []string{"p1", "p2"}, gotProbes)
+}
+
+func TestExportProbeInfoCanceledWhileBlocked(t *testing.T) {
+	pr := &Prober{
+		Probes:   map[string]*probes.ProbeInfo{"p1": testProbeInfo("p1", "10s")},
+		dataChan: make(chan *metrics.EventMetrics),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		pr.exportProbeInfo(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	// Nobody reads dataChan, so the export blocks until the context is
+	// canceled.
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("exportProbeInfo didn't return after the context was canceled")
+	}
+}
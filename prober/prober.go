@@ -23,14 +23,18 @@ package prober
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"math/rand"
 	"regexp"
 	"sync"
 	"time"
 
+	"github.com/cloudprober/cloudprober/config"
 	configpb "github.com/cloudprober/cloudprober/config/proto"
 	"github.com/cloudprober/cloudprober/config/runconfig"
+	"github.com/cloudprober/cloudprober/internal/alerting"
 	rdsserver "github.com/cloudprober/cloudprober/internal/rds/server"
 	"github.com/cloudprober/cloudprober/internal/servers"
 	"github.com/cloudprober/cloudprober/internal/sysvars"
@@ -39,13 +43,17 @@ import (
 	spb "github.com/cloudprober/cloudprober/prober/proto"
 	"github.com/cloudprober/cloudprober/probes"
 	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/probes/probeutils"
 	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
 	"github.com/cloudprober/cloudprober/surfacers"
+	surfacerspb "github.com/cloudprober/cloudprober/surfacers/proto"
 	"github.com/cloudprober/cloudprober/targets"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
 	"github.com/cloudprober/cloudprober/targets/lameduck"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 var randGenerator = rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -60,18 +68,51 @@ type Prober struct {
 	ldLister  endpoint.Lister
 	Surfacers []*surfacers.SurfacerInfo
 
+	// surfacersMu protects Surfacers, which may be updated after Start.
+	surfacersMu sync.RWMutex
+
 	// Probe channel to handle starting of the new probes.
 	grpcStartProbeCh chan string
 
 	// Per-probe cancelFunc map.
 	probeCancelFunc map[string]context.CancelFunc
 
+	// Probes paused through the PauseProbe gRPC method. Protected by mu.
+	paused map[string]bool
+
 	// dataChan for passing metrics between probes and main goroutine.
 	dataChan chan *metrics.EventMetrics
 
+	// loopDone is closed once the main goroutine exits, after flushing the
+	// surfacers, whose error is saved in flushErr.
+	loopDone chan struct{}
+	flushErr error
+
+	// startupCheck, if set, tracks the probes' results for StartupCheck.
+	startupCheck *startupCheck
+
+	// availability, if set, tracks the probes' results for the per-probe
+	// availability metrics.
+	availability *availabilityTracker
+
+	// Per-probe surfacers allow-list. Metrics from the probes that are not in
+	// this map go to all surfacers.
+	probeSurfacers   map[string]map[string]bool
+	probeSurfacersMu sync.RWMutex
+
+	// Per-probe metric name prefixes, protected by probeSurfacersMu. Prefix is
+	// added by the surfacers, so that the metrics generated by the prober for
+	// the probe, e.g. availability, get the same prefix.
+	probePrefixes map[string]string
+
 	// Used by GetConfig for /config handler.
 	TextConfig string
 
+	// ActiveConfig, if set, returns the active config for the GetConfig gRPC
+	// method. It's used instead of TextConfig, which doesn't change after
+	// the config reloads.
+	ActiveConfig func() string
+
 	// Required for all gRPC server implementations.
 	spb.UnimplementedCloudproberServer
 }
@@ -104,19 +145,40 @@ func (pr *Prober) addProbe(p *probes_configpb.ProbeDef) error {
 		return status.Errorf(codes.AlreadyExists, "probe %s is already defined", p.GetName())
 	}
 
-	opts, err := options.BuildProbeOptions(p, pr.ldLister, pr.c.GetGlobalTargetsOptions(), pr.l)
+	probeInfo, err := pr.newProbe(p)
+	if err != nil {
+		return err
+	}
+	pr.Probes[p.GetName()] = probeInfo
+
+	return nil
+}
+
+// newProbe creates a probe from its definition. It should be called with
+// pr.mu held.
+func (pr *Prober) newProbe(p *probes_configpb.ProbeDef) (*probes.ProbeInfo, error) {
+	if err := pr.setProbeSurfacers(p); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	// Global additional labels are merged with the probe's own labels while
+	// building the probe options.
+	optsDef := options.WithGlobalLabels(p, pr.c.GetAdditionalLabel())
+	opts, err := options.BuildProbeOptions(optsDef, pr.ldLister, pr.c.GetGlobalTargetsOptions(), pr.l)
 	if err != nil {
-		return status.Errorf(codes.Unknown, err.Error())
+		return nil, status.Errorf(codes.Unknown, err.Error())
+	}
+	if p.MetricPrefix == nil {
+		opts.MetricPrefix = pr.c.GetMetricPrefix()
 	}
+	pr.setProbeMetricPrefix(p.GetName(), opts.MetricPrefix)
 
 	pr.l.Infof("Creating a %s probe: %s", p.GetType(), p.GetName())
 	probeInfo, err := probes.CreateProbe(p, opts)
 	if err != nil {
-		return status.Errorf(codes.Unknown, err.Error())
+		return nil, status.Errorf(codes.Unknown, err.Error())
 	}
-	pr.Probes[p.GetName()] = probeInfo
-
-	return nil
+	return probeInfo, nil
 }
 
 // Init initialize prober with the given config file.
@@ -129,6 +191,10 @@ func (pr *Prober) Init(ctx context.Context, cfg *configpb.ProberConfig, l *logge
 	if srv != nil {
 		pr.grpcStartProbeCh = make(chan string)
 		spb.RegisterCloudproberServer(srv, pr)
+
+		if mux := runconfig.DefaultHTTPServeMux(); mux != nil && pr.c.GetEnableAdminHttpApi() {
+			mux.Handle(adminHTTPPathPrefix, pr.adminHTTPHandler())
+		}
 	}
 
 	// Initialize RDS server, if configured and attach to the default gRPC server.
@@ -163,8 +229,21 @@ func (pr *Prober) Init(ctx context.Context, cfg *configpb.ProberConfig, l *logge
 		}
 	}
 
+	if pr.c.MetricPrefix != nil {
+		if err := options.ValidateMetricPrefix(pr.c.GetMetricPrefix()); err != nil {
+			return err
+		}
+	}
+
 	var err error
 
+	// Set up (or remove) the global connection rate limit before probes are
+	// initialized.
+	crl := pr.c.GetConnRateLimit()
+	if err := probeutils.SetConnRateLimit(float64(crl.GetRate()), int(crl.GetBurst())); err != nil {
+		return err
+	}
+
 	// Initialize shared targets
 	for _, st := range pr.c.GetSharedTargets() {
 		tgts, err := targets.New(st.GetTargets(), pr.ldLister, globalTargetsOpts, pr.l, pr.l)
@@ -174,6 +253,22 @@ func (pr *Prober) Init(ctx context.Context, cfg *configpb.ProberConfig, l *logge
 		targets.SetSharedTargets(st.GetName(), tgts)
 	}
 
+	// Set maintenance windows before probes, so that they apply to the first
+	// probe results.
+	if err := alerting.SetMaintenanceWindows(pr.c.GetMaintenanceWindow()); err != nil {
+		return err
+	}
+
+	// Initialize surfacers before probes, as probes may refer to surfacers.
+	pr.Surfacers, err = surfacers.Init(ctx, pr.c.GetSurfacer())
+	if err != nil {
+		return err
+	}
+
+	if pr.c.GetExportAvailabilityRatio() {
+		pr.availability = newAvailabilityTracker()
+	}
+
 	// Initiliaze probes
 	pr.Probes = make(map[string]*probes.ProbeInfo)
 	pr.probeCancelFunc = make(map[string]context.CancelFunc)
@@ -189,29 +284,146 @@ func (pr *Prober) Init(ctx context.Context, cfg *configpb.ProberConfig, l *logge
 		return err
 	}
 
-	pr.Surfacers, err = surfacers.Init(ctx, pr.c.GetSurfacer())
-	if err != nil {
-		return err
+	return nil
+}
+
+// setProbeSurfacers sets the surfacers allow-list for the probe. It returns
+// an error if probe refers to a surfacer that doesn't exist.
+func (pr *Prober) setProbeSurfacers(p *probes_configpb.ProbeDef) error {
+	pr.probeSurfacersMu.Lock()
+	defer pr.probeSurfacersMu.Unlock()
+
+	if len(p.GetSurfacers()) == 0 {
+		delete(pr.probeSurfacers, p.GetName())
+		return nil
+	}
+
+	known := make(map[string]bool)
+	pr.surfacersMu.RLock()
+	for _, s := range pr.Surfacers {
+		known[config.SurfacerRefName(s.Def)] = true
 	}
+	pr.surfacersMu.RUnlock()
 
+	allowed := make(map[string]bool)
+	for _, name := range p.GetSurfacers() {
+		if !known[name] {
+			return fmt.Errorf("probe %s: unknown surfacer %s", p.GetName(), name)
+		}
+		allowed[name] = true
+	}
+
+	if pr.probeSurfacers == nil {
+		pr.probeSurfacers = make(map[string]map[string]bool)
+	}
+	pr.probeSurfacers[p.GetName()] = allowed
 	return nil
 }
 
+// setProbeMetricPrefix sets the metric name prefix for the probe.
+func (pr *Prober) setProbeMetricPrefix(probe, prefix string) {
+	pr.probeSurfacersMu.Lock()
+	defer pr.probeSurfacersMu.Unlock()
+
+	if prefix == "" {
+		delete(pr.probePrefixes, probe)
+		return
+	}
+	if pr.probePrefixes == nil {
+		pr.probePrefixes = make(map[string]string)
+	}
+	pr.probePrefixes[probe] = prefix
+}
+
+// deleteProbeSurfacers deletes the surfacers allow-list and the metric name
+// prefix of a removed probe.
+func (pr *Prober) deleteProbeSurfacers(probe string) {
+	pr.probeSurfacersMu.Lock()
+	defer pr.probeSurfacersMu.Unlock()
+
+	delete(pr.probeSurfacers, probe)
+	delete(pr.probePrefixes, probe)
+}
+
+// writeToSurfacers replicates the EventMetrics to the surfacers. If the
+// EventMetrics belong to a probe with a surfacers allow-list, it's written
+// only to those surfacers. Metric names are prefixed with the probe's metric
+// prefix, if any.
+func (pr *Prober) writeToSurfacers(ctx context.Context, em *metrics.EventMetrics) {
+	pr.probeSurfacersMu.RLock()
+	allowed := pr.probeSurfacers[em.Label("probe")]
+	prefix := pr.probePrefixes[em.Label("probe")]
+	pr.probeSurfacersMu.RUnlock()
+
+	pr.surfacersMu.RLock()
+	ss := pr.Surfacers
+	pr.surfacersMu.RUnlock()
+
+	// Note that s.Write() is expected to be non-blocking to avoid blocking of
+	// EventMetrics message processing.
+	for _, s := range ss {
+		if allowed != nil && !allowed[config.SurfacerRefName(s.Def)] {
+			continue
+		}
+		s.WriteWithMetricPrefix(ctx, em, prefix)
+	}
+}
+
+// flushSurfacers writes the EventMetrics remaining in the data channel to the
+// surfacers, and flushes the surfacers, so that the buffered EventMetrics are
+// exported before the process exits.
+func (pr *Prober) flushSurfacers() error {
+	pending := len(pr.dataChan)
+	for n := pending; n > 0; n-- {
+		pr.writeToSurfacers(context.Background(), <-pr.dataChan)
+	}
+	pr.l.Infof("Flushing surfacers, pending EventMetrics: %d", pending)
+	pr.surfacersMu.RLock()
+	defer pr.surfacersMu.RUnlock()
+	return surfacers.Flush(context.Background(), pr.Surfacers)
+}
+
+// GetSurfacers returns a copy of the surfacers list. Surfacers may be updated
+// after Start, see UpdateSurfacers.
+func (pr *Prober) GetSurfacers() []*surfacers.SurfacerInfo {
+	pr.surfacersMu.RLock()
+	defer pr.surfacersMu.RUnlock()
+	return append([]*surfacers.SurfacerInfo(nil), pr.Surfacers...)
+}
+
+// UpdateSurfacers updates the surfacers to match the given surfacer
+// definitions. Unchanged surfacers keep running, removed surfacers are
+// stopped, and new or changed surfacers are started using the given context.
+// See surfacers.Update for the surfacers that require a restart to change.
+func (pr *Prober) UpdateSurfacers(ctx context.Context, sDefs []*surfacerspb.SurfacerDef) error {
+	pr.surfacersMu.Lock()
+	defer pr.surfacersMu.Unlock()
+
+	ss, err := surfacers.Update(ctx, pr.Surfacers, sDefs, pr.l)
+	pr.Surfacers = ss
+	return err
+}
+
 // Start starts a previously initialized Cloudprober.
 func (pr *Prober) Start(ctx context.Context) {
 	pr.dataChan = make(chan *metrics.EventMetrics, 100000)
+	pr.loopDone = make(chan struct{})
 
 	go func() {
-		var em *metrics.EventMetrics
+		defer close(pr.loopDone)
 		for {
-			em = <-pr.dataChan
-
-			// Replicate the surfacer message to every surfacer we have
-			// registered. Note that s.Write() is expected to be
-			// non-blocking to avoid blocking of EventMetrics message
-			// processing.
-			for _, surfacer := range pr.Surfacers {
-				surfacer.Write(context.Background(), em)
+			select {
+			case em := <-pr.dataChan:
+				if pr.startupCheck != nil {
+					pr.startupCheck.observe(em)
+				}
+				if pr.availability != nil {
+					pr.availability.observe(em)
+				}
+				pr.writeToSurfacers(context.Background(), em)
+			case <-ctx.Done():
+				pr.flushErr = pr.flushSurfacers()
+				return
 			}
 		}
 	}()
@@ -219,6 +431,17 @@ func (pr *Prober) Start(ctx context.Context) {
 	// Start a goroutine to export system variables
 	go sysvars.Start(ctx, pr.dataChan, time.Millisecond*time.Duration(pr.c.GetSysvarsIntervalMsec()), pr.c.GetSysvarsEnvVar())
 
+	if pr.c.GetExportProbeInfo() {
+		go pr.exportProbeInfo(ctx, time.Millisecond*time.Duration(pr.c.GetSysvarsIntervalMsec()))
+	}
+
+	if probeutils.ConnRateLimited() {
+		go pr.exportConnLimiterWait(ctx, time.Millisecond*time.Duration(pr.c.GetSysvarsIntervalMsec()))
+	}
+
+	// DNS-over-HTTPS resolvers may be created later, along with new probes.
+	go pr.exportDNSResolverMetrics(ctx, time.Millisecond*time.Duration(pr.c.GetSysvarsIntervalMsec()))
+
 	// Start servers, each in its own goroutine
 	for _, s := range pr.Servers {
 		go s.Start(ctx, pr.dataChan)
@@ -254,6 +477,73 @@ func (pr *Prober) startProbe(ctx context.Context, name string) {
 	probeCtx, cancelFunc := context.WithCancel(ctx)
 	pr.probeCancelFunc[name] = cancelFunc
 	go pr.Probes[name].Start(probeCtx, pr.dataChan)
+	go pr.checkNoTargets(probeCtx, pr.Probes[name])
+	go pr.checkStaleTargets(probeCtx, pr.Probes[name])
+	go pr.checkMaintenance(probeCtx, pr.Probes[name])
+	go pr.checkAvailability(probeCtx, pr.Probes[name])
+}
+
+// sameProbeDef returns true if the probe definition hasn't changed. Probe
+// options building sets dummy targets for probes that don't need targets, so
+// we account for that while comparing.
+func sameProbeDef(current, updated *probes_configpb.ProbeDef) bool {
+	_, dummy := current.GetTargets().GetType().(*targetspb.TargetsDef_DummyTargets)
+	if updated.GetTargets() == nil && dummy {
+		updated = proto.Clone(updated).(*probes_configpb.ProbeDef)
+		updated.Targets = current.GetTargets()
+	}
+	return proto.Equal(current, updated)
+}
+
+// UpdateProbes updates the running probes to match the given probe
+// definitions: probes that are no longer defined are stopped and removed,
+// changed probes are re-created, and new probes are added and started using
+// the given context.
+func (pr *Prober) UpdateProbes(ctx context.Context, probeDefs []*probes_configpb.ProbeDef) error {
+	updated := make(map[string]*probes_configpb.ProbeDef)
+	for _, p := range probeDefs {
+		updated[p.GetName()] = p
+	}
+
+	var toAdd []*probes_configpb.ProbeDef
+
+	pr.mu.Lock()
+	for name, p := range pr.Probes {
+		if newDef := updated[name]; newDef != nil && sameProbeDef(p.ProbeDef, newDef) {
+			continue
+		}
+		pr.l.Infof("Removing probe: %s", name)
+		if cancel := pr.probeCancelFunc[name]; cancel != nil {
+			cancel()
+		}
+		delete(pr.probeCancelFunc, name)
+		delete(pr.Probes, name)
+		delete(pr.paused, name)
+		pr.deleteProbeSurfacers(name)
+	}
+	for _, p := range probeDefs {
+		if pr.Probes[p.GetName()] == nil {
+			toAdd = append(toAdd, p)
+		}
+	}
+	pr.mu.Unlock()
+
+	var errs []error
+	for _, p := range toAdd {
+		if err := pr.addProbe(p); err != nil {
+			errs = append(errs, fmt.Errorf("error adding probe %s: %v", p.GetName(), err))
+			continue
+		}
+		// addProbe skips probes that are not supposed to run on this host.
+		pr.mu.Lock()
+		added := pr.Probes[p.GetName()] != nil
+		pr.mu.Unlock()
+		if added {
+			pr.startProbe(ctx, p.GetName())
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
 func randomDuration(duration, ceiling time.Duration) time.Duration {
@@ -15,11 +15,22 @@
 package prober
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/config/runconfig"
+	"github.com/cloudprober/cloudprober/metrics"
+	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
+	"github.com/cloudprober/cloudprober/surfacers"
+	surfacerspb "github.com/cloudprober/cloudprober/surfacers/proto"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestRandomDuration(t *testing.T) {
@@ -72,3 +83,234 @@ func TestInterProbeWait(t *testing.T) {
 		})
 	}
 }
+
+func TestSameProbeDef(t *testing.T) {
+	current := testProbeDef("p1")
+	noTargets := proto.Clone(current).(*probes_configpb.ProbeDef)
+	noTargets.Targets = nil
+	hostTargets := proto.Clone(current).(*probes_configpb.ProbeDef)
+	hostTargets.Targets = &targetspb.TargetsDef{Type: &targetspb.TargetsDef_HostNames{HostNames: "host1"}}
+	newInterval := proto.Clone(current).(*probes_configpb.ProbeDef)
+	newInterval.IntervalMsec = proto.Int32(20000)
+
+	assert.True(t, sameProbeDef(current, testProbeDef("p1")), "same")
+	assert.True(t, sameProbeDef(current, noTargets), "no targets")
+	assert.False(t, sameProbeDef(current, hostTargets), "host targets")
+	assert.False(t, sameProbeDef(current, newInterval), "new interval")
+	assert.Nil(t, noTargets.Targets, "updated def should not be modified")
+}
+
+func TestUpdateProbes(t *testing.T) {
+	pr := testProber()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p1Def := testProbeDef("p1")
+	p1Def.MetricPrefix = proto.String("p1_")
+	assert.NoError(t, pr.UpdateProbes(ctx, []*probes_configpb.ProbeDef{p1Def, testProbeDef("p2")}))
+	assert.Equal(t, "p1_", pr.probePrefixes["p1"])
+	p1 := pr.Probes["p1"].Probe.(*testProbe)
+	p2 := pr.Probes["p2"].Probe.(*testProbe)
+	verifyProbeRunningStatus(t, p1, true)
+	verifyProbeRunningStatus(t, p2, true)
+
+	// Remove p1, change p2 and add p3.
+	p2Def := testProbeDef("p2")
+	p2Def.IntervalMsec = proto.Int32(20000)
+	assert.NoError(t, pr.UpdateProbes(ctx, []*probes_configpb.ProbeDef{p2Def, testProbeDef("p3")}))
+
+	verifyProbeRunningStatus(t, p1, false)
+	verifyProbeRunningStatus(t, p2, false)
+	assert.Nil(t, pr.Probes["p1"], "p1 not removed")
+	assert.NotContains(t, pr.probePrefixes, "p1", "p1's metric prefix not removed")
+
+	newP2 := pr.Probes["p2"].Probe.(*testProbe)
+	assert.NotSame(t, p2, newP2, "p2 not re-created")
+	verifyProbeRunningStatus(t, newP2, true)
+	p3 := pr.Probes["p3"].Probe.(*testProbe)
+	verifyProbeRunningStatus(t, p3, true)
+
+	// Unchanged config should leave the probes alone.
+	assert.NoError(t, pr.UpdateProbes(ctx, []*probes_configpb.ProbeDef{p2Def, testProbeDef("p3")}))
+	assert.Same(t, newP2, pr.Probes["p2"].Probe.(*testProbe))
+	assert.Same(t, p3, pr.Probes["p3"].Probe.(*testProbe))
+
+	// Invalid probe results in an error, but other updates still apply.
+	assert.Error(t, pr.UpdateProbes(ctx, []*probes_configpb.ProbeDef{{Name: proto.String("invalid")}, testProbeDef("p3")}))
+	verifyProbeRunningStatus(t, newP2, false)
+	assert.Len(t, pr.Probes, 1)
+}
+
+func TestMetricPrefix(t *testing.T) {
+	pr := testProber()
+	pr.c = &configpb.ProberConfig{MetricPrefix: proto.String("global_")}
+
+	p2Def := testProbeDef("p2")
+	p2Def.MetricPrefix = proto.String("p2_")
+	for _, p := range []*probes_configpb.ProbeDef{testProbeDef("p1"), p2Def} {
+		assert.NoError(t, pr.addProbe(p))
+	}
+	assert.Equal(t, "global_", pr.Probes["p1"].Options.MetricPrefix)
+	assert.Equal(t, "p2_", pr.Probes["p2"].Options.MetricPrefix)
+
+	// Prefix is added while writing to the surfacers, to the probes' own
+	// metrics as well as to the metrics generated by the prober, e.g.
+	// availability.
+	ts := &testSurfacer{}
+	pr.Surfacers = []*surfacers.SurfacerInfo{{Surfacer: ts, Type: "USER_DEFINED"}}
+	ts1 := time.Now()
+	pr.writeToSurfacers(context.Background(), availabilityEM(pr.Probes["p1"], 1, 1, ts1))
+	pr.writeToSurfacers(context.Background(), metrics.NewEventMetrics(ts1).AddMetric("total", metrics.NewInt(1)).AddLabel("probe", "p2"))
+	pr.writeToSurfacers(context.Background(), metrics.NewEventMetrics(ts1).AddMetric("uptime", metrics.NewInt(1)).AddLabel("probe", "sysvars"))
+
+	var got []string
+	for _, em := range ts.ems {
+		got = append(got, em.MetricsKeys()...)
+	}
+	assert.Equal(t, []string{"global_" + reportingTargetsMetricName, "global_" + availabilityMetricName, "p2_total", "uptime"}, got)
+}
+
+func TestGlobalAdditionalLabels(t *testing.T) {
+	pr := testProber()
+	pr.c = &configpb.ProberConfig{
+		AdditionalLabel: []*probes_configpb.AdditionalLabel{
+			{Key: proto.String("tags"), Value: proto.String("global")},
+			{Key: proto.String("env"), Value: proto.String("prod")},
+		},
+	}
+
+	p2Def := testProbeDef("p2")
+	p2Def.AdditionalLabel = []*probes_configpb.AdditionalLabel{
+		{Key: proto.String("tags"), Value: proto.String("p2"), MergePolicy: probes_configpb.AdditionalLabel_CONCAT.Enum()},
+		{Key: proto.String("env"), Value: proto.String("staging")},
+	}
+	p3Def := testProbeDef("p3")
+	p3Def.AdditionalLabel = []*probes_configpb.AdditionalLabel{
+		{Key: proto.String("env"), Value: proto.String("staging"), MergePolicy: probes_configpb.AdditionalLabel_ERROR.Enum()},
+	}
+
+	assert.NoError(t, pr.addProbe(testProbeDef("p1")))
+	assert.NoError(t, pr.addProbe(p2Def))
+	assert.Error(t, pr.addProbe(p3Def))
+
+	labels := func(name string) map[string]string {
+		m := make(map[string]string)
+		for _, al := range pr.Probes[name].Options.AdditionalLabels {
+			k, v := al.KeyValueForTarget(endpoint.Endpoint{Name: "target1"})
+			m[k] = v
+		}
+		return m
+	}
+	assert.Equal(t, map[string]string{"tags": "global", "env": "prod"}, labels("p1"))
+	assert.Equal(t, map[string]string{"tags": "global,p2", "env": "staging"}, labels("p2"))
+
+	// Probe definition is not modified.
+	assert.Len(t, pr.Probes["p2"].ProbeDef.GetAdditionalLabel(), 2)
+}
+
+type testSurfacer struct {
+	ems []*metrics.EventMetrics
+}
+
+func (ts *testSurfacer) Write(_ context.Context, em *metrics.EventMetrics) {
+	ts.ems = append(ts.ems, em)
+}
+
+func TestProbeSurfacers(t *testing.T) {
+	pr := testProber()
+	fileSurfacer, promSurfacer := &testSurfacer{}, &testSurfacer{}
+	pr.Surfacers = []*surfacers.SurfacerInfo{
+		{Surfacer: fileSurfacer, Type: "FILE", Def: &surfacerspb.SurfacerDef{Type: surfacerspb.Type_FILE.Enum()}},
+		{Surfacer: promSurfacer, Type: "PROMETHEUS", Name: "prom-prod", Def: &surfacerspb.SurfacerDef{Type: surfacerspb.Type_PROMETHEUS.Enum(), Name: proto.String("prom-prod")}},
+	}
+
+	debugProbe := testProbeDef("debug")
+	debugProbe.Surfacers = []string{"file"}
+	prodProbe := testProbeDef("prod")
+	prodProbe.Surfacers = []string{"prom-prod"}
+
+	for _, p := range []*probes_configpb.ProbeDef{debugProbe, prodProbe, testProbeDef("all")} {
+		assert.NoError(t, pr.addProbe(p), "error adding probe %s", p.GetName())
+	}
+
+	badProbe := testProbeDef("bad")
+	badProbe.Surfacers = []string{"prometheus"}
+	assert.Error(t, pr.addProbe(badProbe), "expected error for unknown surfacer")
+
+	emForProbe := func(probe string) *metrics.EventMetrics {
+		em := metrics.NewEventMetrics(time.Now()).AddMetric("total", metrics.NewInt(1))
+		if probe != "" {
+			em.AddLabel("probe", probe)
+		}
+		return em
+	}
+
+	for _, probe := range []string{"debug", "prod", "all", ""} {
+		pr.writeToSurfacers(context.Background(), emForProbe(probe))
+	}
+
+	var fileProbes, promProbes []string
+	for _, em := range fileSurfacer.ems {
+		fileProbes = append(fileProbes, em.Label("probe"))
+	}
+	for _, em := range promSurfacer.ems {
+		promProbes = append(promProbes, em.Label("probe"))
+	}
+	assert.Equal(t, []string{"debug", "all", ""}, fileProbes, "file surfacer probes")
+	assert.Equal(t, []string{"prod", "all", ""}, promProbes, "prometheus surfacer probes")
+}
+
+func TestUpdateSurfacers(t *testing.T) {
+	pr := testProber()
+	s1, s2 := &testSurfacer{}, &testSurfacer{}
+	surfacers.Register("update-s1", s1)
+	surfacers.Register("update-s2", s2)
+	sDef := func(name string) *surfacerspb.SurfacerDef {
+		return &surfacerspb.SurfacerDef{Name: proto.String(name), Type: surfacerspb.Type_USER_DEFINED.Enum()}
+	}
+	// Required probestatus surfacer registers its handlers with the default
+	// HTTP server mux.
+	runconfig.SetDefaultHTTPServeMux(http.NewServeMux())
+
+	var err error
+	pr.Surfacers, err = surfacers.Init(context.Background(), []*surfacerspb.SurfacerDef{sDef("update-s1")})
+	assert.NoError(t, err)
+
+	// Probe referring to a surfacer that doesn't exist yet.
+	p := testProbeDef("p1")
+	p.Surfacers = []string{"update-s2"}
+	assert.Error(t, pr.addProbe(p))
+
+	assert.NoError(t, pr.UpdateSurfacers(context.Background(), []*surfacerspb.SurfacerDef{sDef("update-s1"), sDef("update-s2")}))
+	assert.NoError(t, pr.addProbe(p))
+
+	pr.writeToSurfacers(context.Background(), metrics.NewEventMetrics(time.Now()).AddMetric("total", metrics.NewInt(1)).AddLabel("probe", "p1"))
+	assert.Len(t, s1.ems, 0, "probe's metrics should go only to update-s2")
+	assert.Len(t, s2.ems, 1)
+}
+
+type flushingSurfacer struct {
+	testSurfacer
+	flushedEMs int
+}
+
+func (fs *flushingSurfacer) Flush(_ context.Context) error {
+	fs.flushedEMs = len(fs.ems)
+	return nil
+}
+
+func TestFlushSurfacers(t *testing.T) {
+	pr := testProber()
+	s := &flushingSurfacer{}
+	pr.Surfacers = []*surfacers.SurfacerInfo{{Surfacer: s, Type: "USER_DEFINED"}}
+
+	pr.dataChan = make(chan *metrics.EventMetrics, 10)
+	for i := 0; i < 3; i++ {
+		pr.dataChan <- metrics.NewEventMetrics(time.Now()).AddMetric("total", metrics.NewInt(int64(i)))
+	}
+
+	pr.flushSurfacers()
+	assert.Len(t, s.ems, 3, "EventMetrics written to the surfacer")
+	assert.Equal(t, 3, s.flushedEMs, "EventMetrics written before flush")
+	assert.Len(t, pr.dataChan, 0)
+}
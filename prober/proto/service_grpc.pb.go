@@ -22,6 +22,10 @@ const (
 	Cloudprober_AddProbe_FullMethodName    = "/cloudprober.Cloudprober/AddProbe"
 	Cloudprober_RemoveProbe_FullMethodName = "/cloudprober.Cloudprober/RemoveProbe"
 	Cloudprober_ListProbes_FullMethodName  = "/cloudprober.Cloudprober/ListProbes"
+	Cloudprober_PauseProbe_FullMethodName  = "/cloudprober.Cloudprober/PauseProbe"
+	Cloudprober_ResumeProbe_FullMethodName = "/cloudprober.Cloudprober/ResumeProbe"
+	Cloudprober_RunProbe_FullMethodName    = "/cloudprober.Cloudprober/RunProbe"
+	Cloudprober_GetConfig_FullMethodName   = "/cloudprober.Cloudprober/GetConfig"
 )
 
 // CloudproberClient is the client API for Cloudprober service.
@@ -33,8 +37,22 @@ type CloudproberClient interface {
 	AddProbe(ctx context.Context, in *AddProbeRequest, opts ...grpc.CallOption) (*AddProbeResponse, error)
 	// RemoveProbe stops the probe and removes it from the in-memory database.
 	RemoveProbe(ctx context.Context, in *RemoveProbeRequest, opts ...grpc.CallOption) (*RemoveProbeResponse, error)
-	// ListProbes lists active probes.
+	// ListProbes lists active probes, along with their status and current
+	// targets.
 	ListProbes(ctx context.Context, in *ListProbesRequest, opts ...grpc.CallOption) (*ListProbesResponse, error)
+	// PauseProbe stops running the probe, without removing it. Paused probe
+	// keeps showing up in ListProbes, and can be resumed using ResumeProbe.
+	// Pausing an already paused probe is a no-op.
+	PauseProbe(ctx context.Context, in *PauseProbeRequest, opts ...grpc.CallOption) (*PauseProbeResponse, error)
+	// ResumeProbe starts running a paused probe again. Resuming a probe that is
+	// not paused is a no-op.
+	ResumeProbe(ctx context.Context, in *ResumeProbeRequest, opts ...grpc.CallOption) (*ResumeProbeResponse, error)
+	// RunProbe runs the probe once against the given target, outside of its
+	// regular schedule, and returns the result. Result is not sent to the
+	// surfacers. It works for paused probes as well.
+	RunProbe(ctx context.Context, in *RunProbeRequest, opts ...grpc.CallOption) (*RunProbeResponse, error)
+	// GetConfig returns the active config.
+	GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*GetConfigResponse, error)
 }
 
 type cloudproberClient struct {
@@ -72,6 +90,42 @@ func (c *cloudproberClient) ListProbes(ctx context.Context, in *ListProbesReques
 	return out, nil
 }
 
+func (c *cloudproberClient) PauseProbe(ctx context.Context, in *PauseProbeRequest, opts ...grpc.CallOption) (*PauseProbeResponse, error) {
+	out := new(PauseProbeResponse)
+	err := c.cc.Invoke(ctx, Cloudprober_PauseProbe_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudproberClient) ResumeProbe(ctx context.Context, in *ResumeProbeRequest, opts ...grpc.CallOption) (*ResumeProbeResponse, error) {
+	out := new(ResumeProbeResponse)
+	err := c.cc.Invoke(ctx, Cloudprober_ResumeProbe_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudproberClient) RunProbe(ctx context.Context, in *RunProbeRequest, opts ...grpc.CallOption) (*RunProbeResponse, error) {
+	out := new(RunProbeResponse)
+	err := c.cc.Invoke(ctx, Cloudprober_RunProbe_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudproberClient) GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*GetConfigResponse, error) {
+	out := new(GetConfigResponse)
+	err := c.cc.Invoke(ctx, Cloudprober_GetConfig_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CloudproberServer is the server API for Cloudprober service.
 // All implementations must embed UnimplementedCloudproberServer
 // for forward compatibility
@@ -81,8 +135,22 @@ type CloudproberServer interface {
 	AddProbe(context.Context, *AddProbeRequest) (*AddProbeResponse, error)
 	// RemoveProbe stops the probe and removes it from the in-memory database.
 	RemoveProbe(context.Context, *RemoveProbeRequest) (*RemoveProbeResponse, error)
-	// ListProbes lists active probes.
+	// ListProbes lists active probes, along with their status and current
+	// targets.
 	ListProbes(context.Context, *ListProbesRequest) (*ListProbesResponse, error)
+	// PauseProbe stops running the probe, without removing it. Paused probe
+	// keeps showing up in ListProbes, and can be resumed using ResumeProbe.
+	// Pausing an already paused probe is a no-op.
+	PauseProbe(context.Context, *PauseProbeRequest) (*PauseProbeResponse, error)
+	// ResumeProbe starts running a paused probe again. Resuming a probe that is
+	// not paused is a no-op.
+	ResumeProbe(context.Context, *ResumeProbeRequest) (*ResumeProbeResponse, error)
+	// RunProbe runs the probe once against the given target, outside of its
+	// regular schedule, and returns the result. Result is not sent to the
+	// surfacers. It works for paused probes as well.
+	RunProbe(context.Context, *RunProbeRequest) (*RunProbeResponse, error)
+	// GetConfig returns the active config.
+	GetConfig(context.Context, *GetConfigRequest) (*GetConfigResponse, error)
 	mustEmbedUnimplementedCloudproberServer()
 }
 
@@ -99,6 +167,18 @@ func (UnimplementedCloudproberServer) RemoveProbe(context.Context, *RemoveProbeR
 func (UnimplementedCloudproberServer) ListProbes(context.Context, *ListProbesRequest) (*ListProbesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListProbes not implemented")
 }
+func (UnimplementedCloudproberServer) PauseProbe(context.Context, *PauseProbeRequest) (*PauseProbeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseProbe not implemented")
+}
+func (UnimplementedCloudproberServer) ResumeProbe(context.Context, *ResumeProbeRequest) (*ResumeProbeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeProbe not implemented")
+}
+func (UnimplementedCloudproberServer) RunProbe(context.Context, *RunProbeRequest) (*RunProbeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunProbe not implemented")
+}
+func (UnimplementedCloudproberServer) GetConfig(context.Context, *GetConfigRequest) (*GetConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
+}
 func (UnimplementedCloudproberServer) mustEmbedUnimplementedCloudproberServer() {}
 
 // UnsafeCloudproberServer may be embedded to opt out of forward compatibility for this service.
@@ -166,6 +246,78 @@ func _Cloudprober_ListProbes_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Cloudprober_PauseProbe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseProbeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudproberServer).PauseProbe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cloudprober_PauseProbe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudproberServer).PauseProbe(ctx, req.(*PauseProbeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cloudprober_ResumeProbe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeProbeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudproberServer).ResumeProbe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cloudprober_ResumeProbe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudproberServer).ResumeProbe(ctx, req.(*ResumeProbeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cloudprober_RunProbe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunProbeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudproberServer).RunProbe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cloudprober_RunProbe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudproberServer).RunProbe(ctx, req.(*RunProbeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cloudprober_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudproberServer).GetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cloudprober_GetConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudproberServer).GetConfig(ctx, req.(*GetConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Cloudprober_ServiceDesc is the grpc.ServiceDesc for Cloudprober service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -185,6 +337,22 @@ var Cloudprober_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListProbes",
 			Handler:    _Cloudprober_ListProbes_Handler,
 		},
+		{
+			MethodName: "PauseProbe",
+			Handler:    _Cloudprober_PauseProbe_Handler,
+		},
+		{
+			MethodName: "ResumeProbe",
+			Handler:    _Cloudprober_ResumeProbe_Handler,
+		},
+		{
+			MethodName: "RunProbe",
+			Handler:    _Cloudprober_RunProbe_Handler,
+		},
+		{
+			MethodName: "GetConfig",
+			Handler:    _Cloudprober_GetConfig_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "github.com/cloudprober/cloudprober/prober/proto/service.proto",
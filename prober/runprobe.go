@@ -0,0 +1,131 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	pb "github.com/cloudprober/cloudprober/prober/proto"
+	"github.com/cloudprober/cloudprober/probes"
+	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
+	"github.com/cloudprober/cloudprober/targets"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// runProbeSlack is the time allowed for a one-off probe run, over the probe's
+// interval and timeout, before it's considered timed out.
+const runProbeSlack = 5 * time.Second
+
+// oneOffTargets lists only the target of a one-off probe run. Names are
+// resolved using the probe's own targets.
+type oneOffTargets struct {
+	targets.Targets
+	ep endpoint.Endpoint
+}
+
+func (t *oneOffTargets) ListEndpoints() []endpoint.Endpoint {
+	return []endpoint.Endpoint{t.ep}
+}
+
+// runTarget returns the endpoint for a one-off probe run against the given
+// target: matching probe's target if there is one, otherwise a new endpoint.
+func runTarget(p *probes.ProbeInfo, target string) (endpoint.Endpoint, error) {
+	if target == "" {
+		if _, ok := p.ProbeDef.GetTargets().GetType().(*targetspb.TargetsDef_DummyTargets); ok {
+			return endpoint.Endpoint{}, nil
+		}
+		return endpoint.Endpoint{}, status.Errorf(codes.InvalidArgument, "target is required for the probe %s", p.Name)
+	}
+	for _, ep := range p.Options.Targets.ListEndpoints() {
+		if ep.Dst() == target || ep.Name == target {
+			return ep, nil
+		}
+	}
+	return endpoint.Endpoint{Name: target}, nil
+}
+
+// RunProbe gRPC method runs the probe once against the given target. It uses
+// a separate instance of the probe, created from the probe's definition, so
+// that the probe's own state, e.g. its counters, is not affected. Probe
+// run's metrics are returned in the response, instead of being sent to the
+// surfacers, and alerts are not evaluated for them.
+func (pr *Prober) RunProbe(ctx context.Context, req *pb.RunProbeRequest) (*pb.RunProbeResponse, error) {
+	pr.mu.Lock()
+	err := pr.checkProbeName(req.GetProbeName())
+	p := pr.Probes[req.GetProbeName()]
+	pr.mu.Unlock()
+	if err != nil {
+		return &pb.RunProbeResponse{}, err
+	}
+
+	// User defined probes are registered as instances, we can't create a new
+	// instance for them.
+	if p.ProbeDef.GetType() == probes_configpb.ProbeDef_USER_DEFINED {
+		return &pb.RunProbeResponse{}, status.Errorf(codes.Unimplemented, "one-off runs are not supported for USER_DEFINED probes")
+	}
+
+	ep, err := runTarget(p, req.GetTarget())
+	if err != nil {
+		return &pb.RunProbeResponse{}, err
+	}
+
+	opts := *p.Options
+	opts.Targets = &oneOffTargets{Targets: p.Options.Targets, ep: ep}
+	opts.StatsExportInterval = opts.Interval
+	opts.TargetLabelTransform = nil
+	opts.AlertHandlers = nil
+
+	probeInfo, err := probes.CreateProbe(p.ProbeDef, &opts)
+	if err != nil {
+		return &pb.RunProbeResponse{}, status.Errorf(codes.Unknown, err.Error())
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, opts.Interval+opts.Timeout+runProbeSlack)
+	defer cancel()
+
+	// Buffer is large enough for the metrics the probe may send before it
+	// notices the context cancelation.
+	dataChan := make(chan *metrics.EventMetrics, 100)
+	go probeInfo.Start(runCtx, dataChan)
+
+	for {
+		select {
+		case <-runCtx.Done():
+			return &pb.RunProbeResponse{}, status.Errorf(codes.DeadlineExceeded, "probe %s didn't report results for the target %s in time", p.Name, ep.Dst())
+		case em := <-dataChan:
+			if em.Label("dst") != probes.DstLabel(probeInfo, ep) {
+				continue
+			}
+			total, ok := em.Metric("total").(metrics.NumValue)
+			if !ok {
+				continue
+			}
+			var success int64
+			if sv, ok := em.Metric("success").(metrics.NumValue); ok {
+				success = sv.Int64()
+			}
+			return &pb.RunProbeResponse{
+				Success: proto.Bool(total.Int64() > 0 && success == total.Int64()),
+				Metrics: proto.String(em.String()),
+			}, nil
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	pb "github.com/cloudprober/cloudprober/prober/proto"
+	httppb "github.com/cloudprober/cloudprober/probes/http/proto"
+	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRunProbe(t *testing.T) {
+	serverPort := func(ts *httptest.Server) int32 {
+		u, _ := url.Parse(ts.URL)
+		port, _ := strconv.Atoi(u.Port())
+		return int32(port)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+	// Nothing listens on the closed server's port.
+	closedTS := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closedTS.Close()
+
+	pr := testProber()
+	pr.c = &configpb.ProberConfig{}
+	for name, port := range map[string]int32{"httpok": serverPort(ts), "httpfail": serverPort(closedTS)} {
+		assert.NoError(t, pr.addProbe(&probes_configpb.ProbeDef{
+			Name: proto.String(name),
+			Type: probes_configpb.ProbeDef_HTTP.Enum(),
+			Targets: &targetspb.TargetsDef{
+				Type: &targetspb.TargetsDef_HostNames{HostNames: "localhost"},
+			},
+			Interval: proto.String("1s"),
+			Probe: &probes_configpb.ProbeDef_HttpProbe{HttpProbe: &httppb.ProbeConf{
+				Port: proto.Int32(port),
+			}},
+		}))
+	}
+	// Target with a port. HTTP probe's dst label doesn't include the port.
+	portedTarget := fmt.Sprintf("localhost:%d", serverPort(ts))
+	assert.NoError(t, pr.addProbe(&probes_configpb.ProbeDef{
+		Name: proto.String("httpported"),
+		Type: probes_configpb.ProbeDef_HTTP.Enum(),
+		Targets: &targetspb.TargetsDef{
+			Type: &targetspb.TargetsDef_HostNames{HostNames: portedTarget},
+		},
+		Interval: proto.String("1s"),
+	}))
+	assert.NoError(t, pr.addProbe(testProbeDef("test-probe")))
+
+	for _, test := range []struct {
+		probe, target string
+		wantDst       string
+		wantSuccess   bool
+		wantCode      codes.Code
+	}{
+		{probe: "httpok", target: "localhost", wantSuccess: true},
+		{probe: "httpported", target: portedTarget, wantDst: "localhost", wantSuccess: true},
+		{probe: "httpfail", target: "localhost"},
+		// Target that is not one of probe's targets.
+		{probe: "httpok", target: "127.0.0.1", wantSuccess: true},
+		{probe: "httpok", wantCode: codes.InvalidArgument},
+		{probe: "unknown", target: "localhost", wantCode: codes.NotFound},
+		// testProbe never reports results.
+		{probe: "test-probe", wantCode: codes.DeadlineExceeded},
+	} {
+		t.Run(test.probe+"_"+test.target, func(t *testing.T) {
+			ctx := context.Background()
+			if test.wantCode == codes.DeadlineExceeded {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(ctx)
+				cancel()
+			}
+			resp, err := pr.RunProbe(ctx, &pb.RunProbeRequest{ProbeName: proto.String(test.probe), Target: proto.String(test.target)})
+			if test.wantCode != codes.OK {
+				assert.Equal(t, test.wantCode, status.Code(err), "error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantSuccess, resp.GetSuccess(), "metrics: %s", resp.GetMetrics())
+			wantDst := test.wantDst
+			if wantDst == "" {
+				wantDst = test.target
+			}
+			assert.Contains(t, resp.GetMetrics(), "dst="+wantDst)
+		})
+	}
+
+	// Probe's own state is not affected.
+	pr.mu.Lock()
+	assert.Empty(t, pr.probeCancelFunc)
+	pr.mu.Unlock()
+}
@@ -16,7 +16,10 @@ package prober
 
 import (
 	"context"
+	"sort"
 
+	"github.com/cloudprober/cloudprober/config"
+	"github.com/cloudprober/cloudprober/config/runconfig"
 	pb "github.com/cloudprober/cloudprober/prober/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -31,6 +34,12 @@ func (pr *Prober) AddProbe(ctx context.Context, req *pb.AddProbeRequest) (*pb.Ad
 		return &pb.AddProbeResponse{}, status.Errorf(codes.InvalidArgument, "probe config cannot be nil")
 	}
 
+	// Validate the probe, and resolve its validator sets, as we do for the
+	// probes in the config.
+	if err := config.CheckProbe(pr.c, p); err != nil {
+		return &pb.AddProbeResponse{}, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
 	if err := pr.addProbe(p); err != nil {
 		return &pb.AddProbeResponse{}, err
 	}
@@ -49,17 +58,16 @@ func (pr *Prober) RemoveProbe(ctx context.Context, req *pb.RemoveProbeRequest) (
 	defer pr.mu.Unlock()
 
 	name := req.GetProbeName()
-
-	if name == "" {
-		return &pb.RemoveProbeResponse{}, status.Errorf(codes.InvalidArgument, "probe name cannot be empty")
+	if err := pr.checkProbeName(name); err != nil {
+		return &pb.RemoveProbeResponse{}, err
 	}
 
-	if pr.Probes[name] == nil {
-		return &pb.RemoveProbeResponse{}, status.Errorf(codes.NotFound, "probe %s not found", name)
+	if cancel := pr.probeCancelFunc[name]; cancel != nil {
+		cancel()
 	}
-
-	pr.probeCancelFunc[name]()
 	delete(pr.Probes, name)
+	delete(pr.paused, name)
+	pr.deleteProbeSurfacers(name)
 
 	return &pb.RemoveProbeResponse{}, nil
 }
@@ -69,14 +77,113 @@ func (pr *Prober) ListProbes(ctx context.Context, req *pb.ListProbesRequest) (*p
 	pr.mu.Lock()
 	defer pr.mu.Unlock()
 
+	var names []string
+	for name := range pr.Probes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
 	resp := &pb.ListProbesResponse{}
 
-	for name, p := range pr.Probes {
-		resp.Probe = append(resp.Probe, &pb.Probe{
+	for _, name := range names {
+		p := pr.Probes[name]
+		probe := &pb.Probe{
 			Name:   proto.String(name),
 			Config: p.ProbeDef,
-		})
+			Status: pb.Probe_RUNNING.Enum(),
+		}
+		if pr.paused[name] {
+			probe.Status = pb.Probe_PAUSED.Enum()
+		}
+		if p.Options != nil && p.Options.Targets != nil {
+			for _, ep := range p.Options.Targets.ListEndpoints() {
+				probe.Target = append(probe.Target, ep.Dst())
+			}
+		}
+		resp.Probe = append(resp.Probe, probe)
 	}
 
 	return resp, nil
 }
+
+// PauseProbe gRPC method stops the given probe, while keeping it in the
+// prober's internal database.
+func (pr *Prober) PauseProbe(ctx context.Context, req *pb.PauseProbeRequest) (*pb.PauseProbeResponse, error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	name := req.GetProbeName()
+	if err := pr.checkProbeName(name); err != nil {
+		return &pb.PauseProbeResponse{}, err
+	}
+	if pr.paused[name] {
+		return &pb.PauseProbeResponse{}, nil
+	}
+
+	pr.l.Infof("Pausing probe: %s", name)
+	if cancel := pr.probeCancelFunc[name]; cancel != nil {
+		cancel()
+	}
+	delete(pr.probeCancelFunc, name)
+
+	if pr.paused == nil {
+		pr.paused = make(map[string]bool)
+	}
+	pr.paused[name] = true
+
+	return &pb.PauseProbeResponse{}, nil
+}
+
+// ResumeProbe gRPC method starts a paused probe again. Since probes are not
+// expected to be started more than once, probe is re-created from its
+// definition. If that fails, probe stays paused.
+func (pr *Prober) ResumeProbe(ctx context.Context, req *pb.ResumeProbeRequest) (*pb.ResumeProbeResponse, error) {
+	pr.mu.Lock()
+	name := req.GetProbeName()
+	if err := pr.checkProbeName(name); err != nil {
+		pr.mu.Unlock()
+		return &pb.ResumeProbeResponse{}, err
+	}
+	if !pr.paused[name] {
+		pr.mu.Unlock()
+		return &pb.ResumeProbeResponse{}, nil
+	}
+
+	pr.l.Infof("Resuming probe: %s", name)
+	probeInfo, err := pr.newProbe(pr.Probes[name].ProbeDef)
+	if err != nil {
+		pr.mu.Unlock()
+		return &pb.ResumeProbeResponse{}, err
+	}
+	pr.Probes[name] = probeInfo
+	delete(pr.paused, name)
+	pr.mu.Unlock()
+
+	pr.grpcStartProbeCh <- name
+
+	return &pb.ResumeProbeResponse{}, nil
+}
+
+// checkProbeName returns a gRPC error if probe name is empty, or if there is
+// no such probe. It should be called with pr.mu held.
+func (pr *Prober) checkProbeName(name string) error {
+	if name == "" {
+		return status.Errorf(codes.InvalidArgument, "probe name cannot be empty")
+	}
+	if pr.Probes[name] == nil {
+		return status.Errorf(codes.NotFound, "probe %s not found", name)
+	}
+	return nil
+}
+
+// GetConfig gRPC method returns the active config.
+func (pr *Prober) GetConfig(ctx context.Context, req *pb.GetConfigRequest) (*pb.GetConfigResponse, error) {
+	cfg := pr.TextConfig
+	if pr.ActiveConfig != nil {
+		cfg = pr.ActiveConfig()
+	}
+	return &pb.GetConfigResponse{
+		Config:   proto.String(cfg),
+		Checksum: proto.String(runconfig.ConfigChecksum()),
+	}, nil
+}
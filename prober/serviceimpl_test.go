@@ -21,6 +21,9 @@ import (
 	"testing"
 	"time"
 
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	httpvalidatorpb "github.com/cloudprober/cloudprober/internal/validators/http/proto"
+	validatorspb "github.com/cloudprober/cloudprober/internal/validators/proto"
 	"github.com/cloudprober/cloudprober/metrics"
 	pb "github.com/cloudprober/cloudprober/prober/proto"
 	"github.com/cloudprober/cloudprober/probes"
@@ -28,6 +31,9 @@ import (
 	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
 	testdatapb "github.com/cloudprober/cloudprober/probes/testdata"
 	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -159,6 +165,41 @@ func TestAddProbe(t *testing.T) {
 	}
 }
 
+func TestAddProbeInvalid(t *testing.T) {
+	pr := testProber()
+	pr.c = &configpb.ProberConfig{
+		ValidatorSet: []*configpb.ValidatorSet{
+			{
+				Name: proto.String("checks"),
+				Validator: []*validatorspb.Validator{
+					{
+						Name: "status-ok",
+						Type: &validatorspb.Validator_HttpValidator{
+							HttpValidator: &httpvalidatorpb.Validator{SuccessStatusCodes: proto.String("200")},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Probe referring to an unknown validator set is rejected.
+	probeDef := testProbeDef("test-probe")
+	probeDef.ValidatorSet = []string{"checks-typo"}
+	_, err := pr.AddProbe(context.Background(), &pb.AddProbeRequest{ProbeConfig: probeDef})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "unknown validator set")
+	assert.Nil(t, pr.Probes["test-probe"])
+
+	// Known validator set is resolved into the probe's validators.
+	probeDef.ValidatorSet = []string{"checks"}
+	_, err = pr.AddProbe(context.Background(), &pb.AddProbeRequest{ProbeConfig: probeDef})
+	assert.NoError(t, err)
+	if assert.NotNil(t, pr.Probes["test-probe"]) {
+		assert.Empty(t, pr.Probes["test-probe"].ProbeDef.GetValidatorSet())
+		assert.Len(t, pr.Probes["test-probe"].ProbeDef.GetValidator(), 1)
+	}
+}
+
 func TestListProbes(t *testing.T) {
 	pr := testProber()
 
@@ -222,6 +263,78 @@ func TestRemoveProbes(t *testing.T) {
 	verifyProbeRunningStatus(t, p, false)
 }
 
+func TestPauseResumeProbe(t *testing.T) {
+	pr := testProber()
+	testProbeName := "test-probe"
+
+	_, err := pr.PauseProbe(context.Background(), &pb.PauseProbeRequest{ProbeName: &testProbeName})
+	assert.Equal(t, codes.NotFound, status.Code(err), "pausing non-existent probe")
+
+	_, err = pr.AddProbe(context.Background(), &pb.AddProbeRequest{ProbeConfig: testProbeDef(testProbeName)})
+	assert.NoError(t, err)
+	p := pr.Probes[testProbeName].Probe.(*testProbe)
+	verifyProbeRunningStatus(t, p, true)
+
+	listStatus := func() pb.Probe_Status {
+		t.Helper()
+		resp, err := pr.ListProbes(context.Background(), &pb.ListProbesRequest{})
+		assert.NoError(t, err)
+		assert.Len(t, resp.GetProbe(), 1)
+		return resp.GetProbe()[0].GetStatus()
+	}
+	assert.Equal(t, pb.Probe_RUNNING, listStatus())
+
+	// Pausing twice is a no-op.
+	for i := 0; i < 2; i++ {
+		_, err = pr.PauseProbe(context.Background(), &pb.PauseProbeRequest{ProbeName: &testProbeName})
+		assert.NoError(t, err)
+	}
+	verifyProbeRunningStatus(t, p, false)
+	assert.Equal(t, pb.Probe_PAUSED, listStatus())
+
+	// Probe is re-created on resume.
+	_, err = pr.ResumeProbe(context.Background(), &pb.ResumeProbeRequest{ProbeName: &testProbeName})
+	assert.NoError(t, err)
+	pr.mu.Lock()
+	newP := pr.Probes[testProbeName].Probe.(*testProbe)
+	pr.mu.Unlock()
+	assert.NotSame(t, p, newP)
+	verifyProbeRunningStatus(t, newP, true)
+	assert.Equal(t, pb.Probe_RUNNING, listStatus())
+
+	// Resuming a running probe is a no-op.
+	_, err = pr.ResumeProbe(context.Background(), &pb.ResumeProbeRequest{ProbeName: &testProbeName})
+	assert.NoError(t, err)
+
+	// Probe stays paused if it can't be re-created.
+	_, err = pr.PauseProbe(context.Background(), &pb.PauseProbeRequest{ProbeName: &testProbeName})
+	assert.NoError(t, err)
+	verifyProbeRunningStatus(t, newP, false)
+	pr.Probes[testProbeName].ProbeDef.Interval = proto.String("bad")
+	_, err = pr.ResumeProbe(context.Background(), &pb.ResumeProbeRequest{ProbeName: &testProbeName})
+	assert.Error(t, err)
+	assert.Equal(t, pb.Probe_PAUSED, listStatus())
+
+	// Paused probe can be removed.
+	_, err = pr.RemoveProbe(context.Background(), &pb.RemoveProbeRequest{ProbeName: &testProbeName})
+	assert.NoError(t, err)
+	assert.Empty(t, pr.paused)
+}
+
+func TestGetConfig(t *testing.T) {
+	pr := testProber()
+	pr.TextConfig = "probe {}"
+
+	resp, err := pr.GetConfig(context.Background(), &pb.GetConfigRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, "probe {}", resp.GetConfig())
+
+	pr.ActiveConfig = func() string { return "surfacer {}" }
+	resp, err = pr.GetConfig(context.Background(), &pb.GetConfigRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, "surfacer {}", resp.GetConfig())
+}
+
 func init() {
 	// Register extension probe.
 	probes.RegisterProbeType(200, func() probes.Probe {
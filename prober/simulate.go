@@ -0,0 +1,306 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cloudprober/cloudprober/config"
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/logger"
+	grpcpb "github.com/cloudprober/cloudprober/probes/grpc/proto"
+	"github.com/cloudprober/cloudprober/probes/options"
+	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
+	"github.com/cloudprober/cloudprober/targets"
+	"google.golang.org/protobuf/proto"
+)
+
+// Request sizes used to estimate the probes' outbound bandwidth. These are
+// rough estimates that include the IPv4 and transport headers, but not the
+// connection setup or the TLS handshakes, unless noted otherwise.
+const (
+	ipv4HeaderBytes      = 20
+	udpHeaderBytes       = 8
+	tcpHeaderBytes       = 20
+	icmpHeaderBytes      = 8
+	httpBaseRequestBytes = 200 // Request line and the default headers.
+	dnsBaseQueryBytes    = 18  // DNS header, and the query type and class.
+	udpMessageBytes      = 64  // Cloudprober's UDP message without a payload.
+	grpcBaseRequestBytes = 256
+	tcpConnectBytes      = 3 * (ipv4HeaderBytes + tcpHeaderBytes) // SYN, ACK and FIN.
+)
+
+// ProbeLoad is the estimated load generated by a probe.
+type ProbeLoad struct {
+	Name                string
+	Type                string
+	Targets             int
+	Interval            time.Duration
+	StatsExportInterval time.Duration
+
+	// Number of probe runs, i.e. runs for a target, per second.
+	ProbesPerSec float64
+	// Number of outbound requests (or packets for PING and UDP probes) per
+	// second.
+	RequestsPerSec float64
+	// Estimated outbound bandwidth in bytes per second. It's valid only if
+	// BandwidthKnown is true, as it cannot be estimated for all probe types,
+	// e.g. for EXTERNAL probes.
+	BytesPerSec    float64
+	BandwidthKnown bool
+	// Number of EventMetrics sent to the surfacers per second.
+	EventMetricsPerSec float64
+
+	surfacers []string
+}
+
+// SurfacerLoad is the estimated write rate of a surfacer.
+type SurfacerLoad struct {
+	Name               string
+	EventMetricsPerSec float64
+	BufferSize         int64
+}
+
+// SimulationReport is the estimated load for a config, see Simulate.
+type SimulationReport struct {
+	Probes    []*ProbeLoad
+	Surfacers []*SurfacerLoad
+	Warnings  []string
+}
+
+// probeRequests returns the number of requests a probe run sends to a target,
+// and their estimated size in bytes. Size is not estimated if ok is false.
+func probeRequests(p *probes_configpb.ProbeDef) (requests, bytes int64, ok bool) {
+	switch p.GetType() {
+	case probes_configpb.ProbeDef_PING:
+		c := p.GetPingProbe()
+		requests = int64(c.GetPacketsPerProbe())
+		return requests, requests * int64(c.GetPayloadSize()+icmpHeaderBytes+ipv4HeaderBytes), true
+
+	case probes_configpb.ProbeDef_HTTP:
+		c := p.GetHttpProbe()
+		size := httpBaseRequestBytes + ipv4HeaderBytes + tcpHeaderBytes + len(c.GetRelativeUrl())
+		for _, h := range c.GetHeaders() {
+			size += len(h.GetName()) + len(h.GetValue()) + 4
+		}
+		for k, v := range c.GetHeader() {
+			size += len(k) + len(v) + 4
+		}
+		for _, b := range c.GetBody() {
+			size += len(b)
+		}
+		requests = int64(c.GetRequestsPerProbe())
+		return requests, requests * int64(size), true
+
+	case probes_configpb.ProbeDef_DNS:
+		return 1, int64(dnsBaseQueryBytes + len(p.GetDnsProbe().GetResolvedDomain()) + 2 + ipv4HeaderBytes + udpHeaderBytes), true
+
+	case probes_configpb.ProbeDef_UDP:
+		c := p.GetUdpProbe()
+		requests = 1
+		if c.GetUseAllTxPortsPerProbe() {
+			requests = int64(c.GetNumTxPorts())
+		}
+		return requests, requests * int64(udpMessageBytes+c.GetPayloadSize()+ipv4HeaderBytes+udpHeaderBytes), true
+
+	case probes_configpb.ProbeDef_TCP:
+		return 1, tcpConnectBytes, true
+
+	case probes_configpb.ProbeDef_GRPC:
+		c := p.GetGrpcProbe()
+		size := grpcBaseRequestBytes + ipv4HeaderBytes + tcpHeaderBytes
+		if m := c.GetMethod(); m == grpcpb.ProbeConf_ECHO || m == grpcpb.ProbeConf_WRITE {
+			size += int(c.GetBlobSize())
+		}
+		return 1, int64(size), true
+	}
+	return 1, 0, false
+}
+
+// waitForTargets returns the number of targets. If there are no targets yet,
+// it waits for the discovered targets to show up, until the context is done.
+func waitForTargets(ctx context.Context, tgts targets.Targets) int {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if n := len(tgts.ListEndpoints()); n > 0 {
+			return n
+		}
+		select {
+		case <-ctx.Done():
+			return 0
+		case <-ticker.C:
+		}
+	}
+}
+
+// simulateProbe estimates the probe's load. It waits for the probe's
+// discovered targets until the discoveryWait timeout.
+func simulateProbe(ctx context.Context, cfg *configpb.ProberConfig, p *probes_configpb.ProbeDef, discoveryWait time.Duration, l *logger.Logger) (*ProbeLoad, error) {
+	// BuildProbeOptions may set the default targets, so we use a copy.
+	p = proto.Clone(p).(*probes_configpb.ProbeDef)
+	opts, err := options.BuildProbeOptions(options.WithGlobalLabels(p, cfg.GetAdditionalLabel()), nil, cfg.GetGlobalTargetsOptions(), l)
+	if err != nil {
+		return nil, fmt.Errorf("probe %s: %v", p.GetName(), err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, discoveryWait)
+	defer cancel()
+
+	pl := &ProbeLoad{
+		Name:                p.GetName(),
+		Type:                p.GetType().String(),
+		Targets:             waitForTargets(waitCtx, opts.Targets),
+		Interval:            opts.Interval,
+		StatsExportInterval: opts.StatsExportInterval,
+		surfacers:           p.GetSurfacers(),
+	}
+
+	requests, bytes, ok := probeRequests(p)
+	pl.ProbesPerSec = float64(pl.Targets) / opts.Interval.Seconds()
+	pl.RequestsPerSec = pl.ProbesPerSec * float64(requests)
+	pl.BytesPerSec, pl.BandwidthKnown = pl.ProbesPerSec*float64(bytes), ok
+	pl.EventMetricsPerSec = float64(pl.Targets) / opts.StatsExportInterval.Seconds()
+	return pl, nil
+}
+
+// Simulate estimates the load generated by the given config, without running
+// any probes: number of targets, probe runs, requests and bandwidth per
+// second for every probe, and the EventMetrics write rate for every surfacer.
+// Targets are expanded like they would be for the probes, including the
+// shared targets and the targets discovery. Probes are simulated
+// concurrently, and each probe waits for its discovered targets until the
+// discoveryWait timeout.
+func Simulate(ctx context.Context, cfg *configpb.ProberConfig, discoveryWait time.Duration, l *logger.Logger) (*SimulationReport, error) {
+	// Shared targets are set up like in Prober.Init, as probes may refer to
+	// them.
+	for _, st := range cfg.GetSharedTargets() {
+		tgts, err := targets.New(st.GetTargets(), nil, cfg.GetGlobalTargetsOptions(), l, l)
+		if err != nil {
+			return nil, fmt.Errorf("shared_targets %s: %v", st.GetName(), err)
+		}
+		targets.SetSharedTargets(st.GetName(), tgts)
+	}
+
+	loads := make([]*ProbeLoad, len(cfg.GetProbe()))
+	errs := make([]error, len(cfg.GetProbe()))
+	var wg sync.WaitGroup
+	for i, p := range cfg.GetProbe() {
+		wg.Add(1)
+		go func(i int, p *probes_configpb.ProbeDef) {
+			defer wg.Done()
+			loads[i], errs[i] = simulateProbe(ctx, cfg, p, discoveryWait, l)
+		}(i, p)
+	}
+	wg.Wait()
+
+	r := &SimulationReport{}
+	var maxBurst int
+	for i, pl := range loads {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if pl.Targets == 0 {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("probe %s: no targets found, targets discovery may take longer than %v", pl.Name, discoveryWait))
+		}
+		// All the targets' EventMetrics are sent at the same time.
+		maxBurst = max(maxBurst, pl.Targets)
+		r.Probes = append(r.Probes, pl)
+	}
+
+	for _, s := range config.ExpandedConfig(cfg).GetSurfacer() {
+		name := config.SurfacerRefName(s)
+		if name == "" {
+			continue
+		}
+		sl := &SurfacerLoad{
+			Name:       name,
+			BufferSize: s.GetMetricsBufferSize(),
+		}
+		for _, pl := range r.Probes {
+			if len(pl.surfacers) == 0 || slices.Contains(pl.surfacers, name) {
+				sl.EventMetricsPerSec += pl.EventMetricsPerSec
+			}
+		}
+		if sl.BufferSize < int64(maxBurst) {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("surfacer %s: metrics_buffer_size (%d) is smaller than the largest probe's number of targets (%d), EventMetrics may be dropped", name, sl.BufferSize, maxBurst))
+		}
+		r.Surfacers = append(r.Surfacers, sl)
+	}
+	sort.Slice(r.Surfacers, func(i, j int) bool { return r.Surfacers[i].Name < r.Surfacers[j].Name })
+
+	return r, nil
+}
+
+// formatBandwidth formats the bandwidth in bytes per second, using the
+// decimal (SI) unit prefixes.
+func formatBandwidth(bytesPerSec float64) string {
+	for _, unit := range []string{"B/s", "kB/s", "MB/s"} {
+		if bytesPerSec < 1000 {
+			return fmt.Sprintf("%.1f %s", bytesPerSec, unit)
+		}
+		bytesPerSec /= 1000
+	}
+	return fmt.Sprintf("%.1f GB/s", bytesPerSec)
+}
+
+// Write writes the report in a tabular text format.
+func (r *SimulationReport) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "PROBE\tTYPE\tTARGETS\tINTERVAL\tPROBES/S\tREQUESTS/S\tBANDWIDTH\tEVENTMETRICS/S")
+	var total ProbeLoad
+	total.BandwidthKnown = true
+	for _, pl := range r.Probes {
+		bw := "n/a"
+		if pl.BandwidthKnown {
+			bw = formatBandwidth(pl.BytesPerSec)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%v\t%.2f\t%.2f\t%s\t%.2f\n", pl.Name, pl.Type, pl.Targets, pl.Interval, pl.ProbesPerSec, pl.RequestsPerSec, bw, pl.EventMetricsPerSec)
+
+		total.Targets += pl.Targets
+		total.ProbesPerSec += pl.ProbesPerSec
+		total.RequestsPerSec += pl.RequestsPerSec
+		total.BytesPerSec += pl.BytesPerSec
+		total.BandwidthKnown = total.BandwidthKnown && pl.BandwidthKnown
+		total.EventMetricsPerSec += pl.EventMetricsPerSec
+	}
+	totalBW := formatBandwidth(total.BytesPerSec)
+	if !total.BandwidthKnown {
+		totalBW = ">" + totalBW
+	}
+	fmt.Fprintf(tw, "TOTAL\t\t%d\t\t%.2f\t%.2f\t%s\t%.2f\n", total.Targets, total.ProbesPerSec, total.RequestsPerSec, totalBW, total.EventMetricsPerSec)
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "SURFACER\tEVENTMETRICS/S\tBUFFER_SIZE")
+	for _, sl := range r.Surfacers {
+		fmt.Fprintf(tw, "%s\t%.2f\t%d\n", sl.Name, sl.EventMetricsPerSec, sl.BufferSize)
+	}
+
+	if len(r.Warnings) > 0 {
+		fmt.Fprintln(tw)
+		for _, warning := range r.Warnings {
+			fmt.Fprintf(tw, "WARNING: %s\n", warning)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,170 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestProbeRequests(t *testing.T) {
+	for _, test := range []struct {
+		conf         string
+		wantRequests int64
+		wantBytes    int64
+		wantOK       bool
+	}{
+		{
+			conf:         `type: PING`,
+			wantRequests: 2,
+			wantBytes:    2 * (56 + 8 + 20),
+			wantOK:       true,
+		},
+		{
+			conf:         `type: HTTP  http_probe { relative_url: "/health"  body: "abc"  requests_per_probe: 3 }`,
+			wantRequests: 3,
+			wantBytes:    3 * (200 + 40 + 7 + 3),
+			wantOK:       true,
+		},
+		{
+			conf:         `type: UDP  udp_probe { use_all_tx_ports_per_probe: true  num_tx_ports: 4  payload_size: 100 }`,
+			wantRequests: 4,
+			wantBytes:    4 * (64 + 100 + 28),
+			wantOK:       true,
+		},
+		{
+			conf:         `type: TCP`,
+			wantRequests: 1,
+			wantBytes:    120,
+			wantOK:       true,
+		},
+		{
+			conf:         `type: EXTERNAL`,
+			wantRequests: 1,
+		},
+	} {
+		t.Run(test.conf, func(t *testing.T) {
+			p := &probes_configpb.ProbeDef{}
+			assert.NoError(t, prototext.Unmarshal([]byte(`name: "p" `+test.conf), p))
+			requests, bytes, ok := probeRequests(p)
+			assert.Equal(t, test.wantRequests, requests, "requests")
+			assert.Equal(t, test.wantBytes, bytes, "bytes")
+			assert.Equal(t, test.wantOK, ok, "ok")
+		})
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	cfg := &configpb.ProberConfig{}
+	assert.NoError(t, prototext.Unmarshal([]byte(`
+		probe {
+			name: "p1"
+			type: PING
+			targets { host_names: "t1,t2,t3,t4" }
+			interval: "4s"
+			stats_export_interval_msec: 8000
+			surfacers: "prometheus"
+		}
+		probe {
+			name: "p2"
+			type: EXTERNAL
+			external_probe { command: "/bin/true" }
+		}
+		surfacer { type: PROMETHEUS }
+		surfacer { type: FILE  metrics_buffer_size: 2 }
+	`), cfg))
+
+	r, err := Simulate(context.Background(), cfg, time.Second, nil)
+	assert.NoError(t, err)
+
+	assert.Len(t, r.Probes, 2)
+	p1, p2 := r.Probes[0], r.Probes[1]
+	assert.Equal(t, 4, p1.Targets)
+	assert.Equal(t, 1.0, p1.ProbesPerSec)
+	assert.Equal(t, 2.0, p1.RequestsPerSec)
+	assert.Equal(t, 168.0, p1.BytesPerSec)
+	assert.True(t, p1.BandwidthKnown)
+	assert.Equal(t, 0.5, p1.EventMetricsPerSec)
+
+	assert.Equal(t, 1, p2.Targets, "dummy target")
+	assert.False(t, p2.BandwidthKnown)
+
+	var gotSurfacers []SurfacerLoad
+	for _, sl := range r.Surfacers {
+		gotSurfacers = append(gotSurfacers, *sl)
+	}
+	p2EMRate := p2.EventMetricsPerSec
+	assert.Equal(t, []SurfacerLoad{
+		{Name: "file", EventMetricsPerSec: p2EMRate, BufferSize: 2},
+		{Name: "probestatus", EventMetricsPerSec: p2EMRate, BufferSize: 10000},
+		{Name: "prometheus", EventMetricsPerSec: 0.5 + p2EMRate, BufferSize: 10000},
+	}, gotSurfacers)
+	assert.Len(t, r.Warnings, 1, "buffer size warning")
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.Write(&buf))
+	assert.Contains(t, buf.String(), "p1     PING      4        4s        1.00      2.00        168.0 B/s")
+	assert.Contains(t, buf.String(), "WARNING: surfacer file: metrics_buffer_size (2)")
+
+	// Invalid probe config.
+	cfg.Probe[0].Timeout = proto.String("10s")
+	_, err = Simulate(context.Background(), cfg, time.Second, nil)
+	assert.ErrorContains(t, err, "probe p1")
+
+	// Probe's additional label conflicts with a global label.
+	cfg.Probe[0].Timeout = nil
+	cfg.AdditionalLabel = []*probes_configpb.AdditionalLabel{{Key: proto.String("env"), Value: proto.String("prod")}}
+	cfg.Probe[0].AdditionalLabel = []*probes_configpb.AdditionalLabel{
+		{Key: proto.String("env"), Value: proto.String("dev"), MergePolicy: probes_configpb.AdditionalLabel_ERROR.Enum()},
+	}
+	_, err = Simulate(context.Background(), cfg, time.Second, nil)
+	assert.ErrorContains(t, err, "additional label env is defined more than once")
+}
+
+func TestSimulateSharedTargets(t *testing.T) {
+	cfg := &configpb.ProberConfig{}
+	assert.NoError(t, prototext.Unmarshal([]byte(`
+		probe {
+			name: "p1"
+			type: HTTP
+			targets { shared_targets: "web" }
+		}
+		shared_targets {
+			name: "web"
+			targets { host_names: "w1,w2,w3" }
+		}
+	`), cfg))
+
+	r, err := Simulate(context.Background(), cfg, time.Second, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, r.Probes, 1) {
+		assert.Equal(t, 3, r.Probes[0].Targets)
+	}
+}
+
+func TestFormatBandwidth(t *testing.T) {
+	assert.Equal(t, "512.0 B/s", formatBandwidth(512))
+	assert.Equal(t, "1.5 kB/s", formatBandwidth(1500))
+	assert.Equal(t, "2.5 MB/s", formatBandwidth(2.5e6))
+	assert.Equal(t, "3.0 GB/s", formatBandwidth(3e9))
+}
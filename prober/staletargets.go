@@ -0,0 +1,69 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes"
+	"github.com/cloudprober/cloudprober/targets"
+)
+
+const staleTargetsMetricName = "targets_stale"
+
+// staleTargetsEM returns the EventMetrics that reports whether probe's
+// targets are stale, i.e. target discovery is failing and probe is using the
+// last known good targets.
+func staleTargetsEM(p *probes.ProbeInfo, stale bool, ts time.Time) *metrics.EventMetrics {
+	var v int64
+	if stale {
+		v = 1
+	}
+	em := metrics.NewEventMetrics(ts).
+		AddMetric(staleTargetsMetricName, metrics.NewInt(v)).
+		AddLabel("ptype", strings.ToLower(p.Type)).
+		AddLabel("probe", p.Name)
+	em.Kind = metrics.GAUGE
+	return em
+}
+
+// checkStaleTargets exports the targets staleness at the stats export
+// interval, until the context is canceled. It does nothing if probe's targets
+// don't track staleness.
+func (pr *Prober) checkStaleTargets(ctx context.Context, p *probes.ProbeInfo) {
+	if _, ok := targets.StaleStatus(p.Options.Targets); !ok {
+		return
+	}
+
+	ticker := time.NewTicker(p.Options.StatsExportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ts := <-ticker.C:
+			stale, _ := targets.StaleStatus(p.Options.Targets)
+			select {
+			case pr.dataChan <- staleTargetsEM(p, stale, ts):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/stretchr/testify/assert"
+)
+
+type staleTestTargets struct {
+	testTargets
+	stale atomic.Bool
+}
+
+func (st *staleTestTargets) Stale() bool {
+	return st.stale.Load()
+}
+
+func TestCheckStaleTargets(t *testing.T) {
+	tgts := &staleTestTargets{}
+	p := &probes.ProbeInfo{
+		Options: &options.Options{
+			Targets:             tgts,
+			StatsExportInterval: 10 * time.Millisecond,
+		},
+		Name: "test-probe",
+		Type: "HTTP",
+	}
+	pr := &Prober{dataChan: make(chan *metrics.EventMetrics, 10)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pr.checkStaleTargets(ctx, p)
+
+	for _, stale := range []bool{false, true, false} {
+		tgts.stale.Store(stale)
+
+		// Skip the EventMetrics generated before the update.
+		var em *metrics.EventMetrics
+		for i := 0; i < 3; i++ {
+			em = <-pr.dataChan
+		}
+
+		var want int64
+		if stale {
+			want = 1
+		}
+		assert.Equal(t, want, em.Metric(staleTargetsMetricName).(*metrics.Int).Int64())
+		assert.True(t, em.Kind == metrics.GAUGE, "metrics kind")
+		assert.Equal(t, "http", em.Label("ptype"))
+		assert.Equal(t, "test-probe", em.Label("probe"))
+	}
+}
+
+func TestCheckStaleTargetsNotSupported(t *testing.T) {
+	p := &probes.ProbeInfo{
+		Options: &options.Options{
+			Targets:             &testTargets{},
+			StatsExportInterval: time.Millisecond,
+		},
+	}
+	pr := &Prober{dataChan: make(chan *metrics.EventMetrics, 10)}
+
+	// Returns right away as targets don't track staleness.
+	pr.checkStaleTargets(context.Background(), p)
+	assert.Len(t, pr.dataChan, 0)
+}
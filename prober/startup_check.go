@@ -0,0 +1,172 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudprober/cloudprober/metrics"
+)
+
+// startupCheck tracks the probes' results until each probe has reported
+// once for all its targets.
+type startupCheck struct {
+	mu       sync.Mutex
+	pending  map[string]bool
+	reported map[string]map[string]bool // probe -> dst -> reported
+	failures map[string]string
+	finished bool
+	done     chan struct{}
+
+	// numTargets returns the current number of targets of a probe.
+	numTargets func(probe string) int
+}
+
+func newStartupCheck(probeNames []string, numTargets func(probe string) int) *startupCheck {
+	sc := &startupCheck{
+		pending:    make(map[string]bool),
+		reported:   make(map[string]map[string]bool),
+		failures:   make(map[string]string),
+		done:       make(chan struct{}),
+		numTargets: numTargets,
+	}
+	for _, name := range probeNames {
+		sc.pending[name] = true
+	}
+	if len(sc.pending) == 0 {
+		sc.finished = true
+		close(sc.done)
+	}
+	return sc
+}
+
+// observe records the probe results in the EventMetrics, until all the probes
+// have reported once for each of their current targets. Probes without
+// targets are done after their first report.
+func (sc *startupCheck) observe(em *metrics.EventMetrics) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	probe := em.Label("probe")
+	if _, ok := sc.pending[probe]; !ok || sc.finished {
+		return
+	}
+
+	total, ok := em.Metric("total").(metrics.NumValue)
+	if !ok || total.Int64() == 0 {
+		return
+	}
+	var success int64
+	if sv, ok := em.Metric("success").(metrics.NumValue); ok {
+		success = sv.Int64()
+	}
+	if success < total.Int64() {
+		sc.failures[probe+"/"+em.Label("dst")] = fmt.Sprintf("probe %s: %d of %d runs failed (target: %s)", probe, total.Int64()-success, total.Int64(), em.Label("dst"))
+	}
+
+	if sc.reported[probe] == nil {
+		sc.reported[probe] = make(map[string]bool)
+	}
+	sc.reported[probe][em.Label("dst")] = true
+	if len(sc.reported[probe]) < sc.numTargets(probe) {
+		return
+	}
+
+	sc.pending[probe] = false
+	for _, p := range sc.pending {
+		if p {
+			return
+		}
+	}
+	sc.finished = true
+	close(sc.done)
+}
+
+// err returns the startup check error: the failed probes and the probes
+// that have not reported yet.
+func (sc *startupCheck) err() error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	var errs []string
+	for _, f := range sc.failures {
+		errs = append(errs, f)
+	}
+	for probe, pending := range sc.pending {
+		if !pending {
+			continue
+		}
+		if n := len(sc.reported[probe]); n > 0 {
+			errs = append(errs, fmt.Sprintf("probe %s: results for %d of %d targets", probe, n, sc.numTargets(probe)))
+			continue
+		}
+		errs = append(errs, fmt.Sprintf("probe %s: no results", probe))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.Strings(errs)
+	return errors.New(strings.Join(errs, "; "))
+}
+
+// EnableStartupCheck makes the prober track the probes' results for
+// WaitForStartupCheck. It should be called before Start.
+func (pr *Prober) EnableStartupCheck() {
+	var names []string
+	for name := range pr.Probes {
+		names = append(names, name)
+	}
+	pr.startupCheck = newStartupCheck(names, func(probe string) int {
+		p := pr.Probes[probe]
+		if p == nil || p.Options == nil || p.Options.Targets == nil {
+			return 0
+		}
+		return len(p.Options.Targets.ListEndpoints())
+	})
+}
+
+// WaitForStartupCheck waits until all the probes have reported their results
+// once for all their targets, or until ctx is done. It returns an error if a probe failed, or if it
+// didn't report in time.
+func (pr *Prober) WaitForStartupCheck(ctx context.Context) error {
+	if pr.startupCheck == nil {
+		return errors.New("startup check is not enabled")
+	}
+	select {
+	case <-pr.startupCheck.done:
+	case <-ctx.Done():
+	}
+	return pr.startupCheck.err()
+}
+
+// Wait waits for the prober to stop after Start's context is canceled, and
+// returns the surfacers' flush error. If ctx is done first, it returns ctx's
+// error.
+func (pr *Prober) Wait(ctx context.Context) error {
+	if pr.loopDone == nil {
+		return nil
+	}
+	select {
+	case <-pr.loopDone:
+		return pr.flushErr
+	case <-ctx.Done():
+		return fmt.Errorf("surfacers didn't finish flushing in time: %w", ctx.Err())
+	}
+}
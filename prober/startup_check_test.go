@@ -0,0 +1,109 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+)
+
+func testResultEM(probe, dst string, total, success int64) *metrics.EventMetrics {
+	return metrics.NewEventMetrics(time.Now()).
+		AddMetric("total", metrics.NewInt(total)).
+		AddMetric("success", metrics.NewInt(success)).
+		AddLabel("probe", probe).
+		AddLabel("dst", dst)
+}
+
+func TestStartupCheck(t *testing.T) {
+	pr := &Prober{
+		Probes: map[string]*probes.ProbeInfo{
+			"p1": {Options: &options.Options{}},
+			// Metric prefix is added by the surfacers, it doesn't change
+			// the names of the metrics seen by the prober.
+			"p2": {Options: &options.Options{MetricPrefix: "p2_"}},
+		},
+	}
+
+	assert.Error(t, pr.WaitForStartupCheck(context.Background()), "startup check not enabled")
+
+	pr.EnableStartupCheck()
+	sc := pr.startupCheck
+
+	// Ignored: unknown probe, no results yet, and no total metric.
+	sc.observe(testResultEM("p3", "t1", 1, 0))
+	sc.observe(testResultEM("p1", "t1", 0, 0))
+	sc.observe(metrics.NewEventMetrics(time.Now()).AddLabel("probe", "p1"))
+
+	sc.observe(testResultEM("p1", "t1", 1, 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.EqualError(t, pr.WaitForStartupCheck(ctx), "probe p2: no results")
+
+	sc.observe(testResultEM("p2", "t1", 2, 1))
+	assert.EqualError(t, pr.WaitForStartupCheck(context.Background()), "probe p2: 1 of 2 runs failed (target: t1)")
+
+	// Results after all the probes have reported are ignored.
+	sc.observe(testResultEM("p1", "t2", 1, 0))
+	assert.EqualError(t, pr.WaitForStartupCheck(context.Background()), "probe p2: 1 of 2 runs failed (target: t1)")
+}
+
+func TestStartupCheckSuccess(t *testing.T) {
+	pr := &Prober{Probes: map[string]*probes.ProbeInfo{"p1": {Options: &options.Options{}}}}
+	pr.EnableStartupCheck()
+	pr.startupCheck.observe(testResultEM("p1", "t1", 1, 1))
+	assert.NoError(t, pr.WaitForStartupCheck(context.Background()))
+
+	// Wait returns immediately if the prober was never started.
+	assert.NoError(t, pr.Wait(context.Background()))
+}
+
+func TestStartupCheckAllTargets(t *testing.T) {
+	tgts := &testTargets{endpoints: []endpoint.Endpoint{{Name: "t1"}, {Name: "t2"}}}
+	pr := &Prober{Probes: map[string]*probes.ProbeInfo{"p1": {Options: &options.Options{Targets: tgts}}}}
+	pr.EnableStartupCheck()
+
+	// First good target doesn't finish the check.
+	pr.startupCheck.observe(testResultEM("p1", "t1", 1, 1))
+	pr.startupCheck.observe(testResultEM("p1", "t1", 2, 2))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.EqualError(t, pr.WaitForStartupCheck(ctx), "probe p1: results for 1 of 2 targets")
+
+	pr.startupCheck.observe(testResultEM("p1", "t2", 1, 0))
+	assert.EqualError(t, pr.WaitForStartupCheck(context.Background()), "probe p1: 1 of 1 runs failed (target: t2)")
+}
+
+func TestWait(t *testing.T) {
+	pr := &Prober{loopDone: make(chan struct{})}
+
+	// Prober loop hasn't finished yet.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, pr.Wait(ctx), context.DeadlineExceeded)
+
+	pr.flushErr = errors.New("flush error")
+	close(pr.loopDone)
+	assert.EqualError(t, pr.Wait(context.Background()), "flush error")
+}
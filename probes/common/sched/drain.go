@@ -0,0 +1,98 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sched
+
+import (
+	"context"
+	"time"
+)
+
+type drainingTarget struct {
+	timer     *time.Timer
+	removedAt time.Time
+}
+
+// TargetDrainer delays stopping the probe loops of the removed targets by a
+// grace period, so that these targets continue to be probed for a while
+// after their removal. Probes use it while refreshing targets; it's not
+// concurrency safe.
+type TargetDrainer struct {
+	GracePeriod time.Duration
+
+	draining  map[string]*drainingTarget
+	stopFuncs map[string]context.CancelFunc
+}
+
+// LoopContext returns the context, derived from the probe loop's context
+// ctx, that the target's probe loop should check before each probe run. It's
+// canceled once the grace period of the removed target is over, stopping the
+// probe loop at its next run without canceling the in-flight probe. Probe
+// loops should cancel ctx themselves on exit.
+func (td *TargetDrainer) LoopContext(ctx context.Context, key string) context.Context {
+	loopCtx, stopF := context.WithCancel(ctx)
+	if td.stopFuncs == nil {
+		td.stopFuncs = make(map[string]context.CancelFunc)
+	}
+	td.stopFuncs[key] = stopF
+	return loopCtx
+}
+
+// Remove is called for a target that is no longer in the targets list. If
+// there is no grace period, it cancels the target's probe loop right away
+// using cancelF, otherwise it stops the probe loop through its LoopContext
+// once the grace period is over. It returns "DELETE" if the probe loop has
+// been stopped and caller should forget the target, "DRAIN" if the target
+// has just started draining, and an empty string if the target is still
+// draining.
+func (td *TargetDrainer) Remove(key string, cancelF context.CancelFunc) string {
+	if td.GracePeriod <= 0 {
+		cancelF()
+		delete(td.stopFuncs, key)
+		return "DELETE"
+	}
+
+	if dt, ok := td.draining[key]; ok {
+		if time.Since(dt.removedAt) < td.GracePeriod {
+			return ""
+		}
+		// Timer has fired, or is about to.
+		dt.timer.Stop()
+		td.stopFuncs[key]()
+		delete(td.draining, key)
+		delete(td.stopFuncs, key)
+		return "DELETE"
+	}
+
+	if td.draining == nil {
+		td.draining = make(map[string]*drainingTarget)
+	}
+	td.draining[key] = &drainingTarget{
+		timer:     time.AfterFunc(td.GracePeriod, td.stopFuncs[key]),
+		removedAt: time.Now(),
+	}
+	return "DRAIN"
+}
+
+// Restore is called for a target that is in the targets list. If the target
+// was draining, it stops its removal. It returns false if the target's probe
+// loop has been stopped already, i.e. caller should start a new one.
+func (td *TargetDrainer) Restore(key string) bool {
+	dt, ok := td.draining[key]
+	if !ok {
+		return true
+	}
+	delete(td.draining, key)
+	return dt.timer.Stop()
+}
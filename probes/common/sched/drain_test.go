@@ -0,0 +1,67 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sched
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetDrainer(t *testing.T) {
+	t.Run("no_grace_period", func(t *testing.T) {
+		td := &TargetDrainer{}
+		ctx, cancelF := context.WithCancel(context.Background())
+		assert.Equal(t, "DELETE", td.Remove("t1", cancelF))
+		assert.Error(t, ctx.Err(), "context not canceled")
+		assert.True(t, td.Restore("t2"))
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		td := &TargetDrainer{GracePeriod: time.Hour}
+		ctx := td.LoopContext(context.Background(), "t1")
+		cancelF := func() { t.Error("unexpected cancelation") }
+		assert.Equal(t, "DRAIN", td.Remove("t1", cancelF))
+		assert.Equal(t, "", td.Remove("t1", cancelF))
+		assert.True(t, td.Restore("t1"))
+		assert.NoError(t, ctx.Err())
+		assert.Empty(t, td.draining)
+	})
+
+	t.Run("grace_period_over", func(t *testing.T) {
+		td := &TargetDrainer{GracePeriod: 10 * time.Millisecond}
+		probeCtx, cancelF := context.WithCancel(context.Background())
+		defer cancelF()
+		loopCtx := td.LoopContext(probeCtx, "t1")
+		assert.Equal(t, "DRAIN", td.Remove("t1", cancelF))
+		time.Sleep(20 * time.Millisecond)
+		assert.Error(t, loopCtx.Err(), "loop context not canceled")
+		assert.Equal(t, "DELETE", td.Remove("t1", cancelF))
+		assert.NoError(t, probeCtx.Err(), "probe context canceled")
+		assert.Empty(t, td.draining)
+		assert.Empty(t, td.stopFuncs)
+	})
+
+	t.Run("restore_after_grace_period", func(t *testing.T) {
+		td := &TargetDrainer{GracePeriod: 10 * time.Millisecond}
+		td.LoopContext(context.Background(), "t1")
+		cancelF := func() { t.Error("unexpected cancelation") }
+		assert.Equal(t, "DRAIN", td.Remove("t1", cancelF))
+		time.Sleep(20 * time.Millisecond)
+		assert.False(t, td.Restore("t1"))
+	})
+}
@@ -59,12 +59,14 @@ type Scheduler struct {
 	targets               []endpoint.Endpoint
 	waitGroup             sync.WaitGroup
 	cancelFuncs           map[string]context.CancelFunc
+	drainer               TargetDrainer
 }
 
 func (s *Scheduler) init() {
 	if s.cancelFuncs == nil {
 		s.cancelFuncs = make(map[string]context.CancelFunc)
 	}
+	s.drainer.GracePeriod = s.Opts.TargetRemovalGracePeriod
 
 	s.statsExportFrequency = s.Opts.StatsExportInterval.Nanoseconds() / s.Opts.Interval.Nanoseconds()
 	if s.statsExportFrequency == 0 {
@@ -92,7 +94,9 @@ func (s *Scheduler) gapBetweenTargets() time.Duration {
 	return interTargetGap
 }
 
-func (s *Scheduler) startForTarget(ctx context.Context, target endpoint.Endpoint) {
+// startForTarget runs the probe loop for the target. Probes run with ctx,
+// while loopCtx decides whether to run the next probe.
+func (s *Scheduler) startForTarget(ctx, loopCtx context.Context, target endpoint.Endpoint) {
 	s.Opts.Logger.Debug("Starting probing for the target ", target.Name)
 
 	// We use this counter to decide when to export stats.
@@ -105,7 +109,7 @@ func (s *Scheduler) startForTarget(ctx context.Context, target endpoint.Endpoint
 
 	for ts := time.Now(); true; ts = <-ticker.C {
 		// Don't run another probe if context is canceled already.
-		if ctxDone(ctx) {
+		if ctxDone(loopCtx) {
 			return
 		}
 		s.RunProbeForTarget(ctx, target, result)
@@ -149,14 +153,25 @@ func (s *Scheduler) refreshTargets(ctx context.Context) {
 		activeTargets[key] = target
 	}
 
-	// Stop probing for deleted targets by invoking cancelFunc.
+	// Stop probing for deleted targets by invoking cancelFunc, after the
+	// grace period if it's configured.
 	for targetKey, cancelF := range s.cancelFuncs {
 		if _, ok := activeTargets[targetKey]; ok {
+			// If target came back after its probe loop was stopped, forget
+			// it so that a new probe loop is started below.
+			if !s.drainer.Restore(targetKey) {
+				delete(s.cancelFuncs, targetKey)
+			}
 			continue
 		}
-		cancelF()
-		updatedTargets[targetKey] = "DELETE"
-		delete(s.cancelFuncs, targetKey)
+		action := s.drainer.Remove(targetKey, cancelF)
+		if action == "" {
+			continue
+		}
+		updatedTargets[targetKey] = action
+		if action == "DELETE" {
+			delete(s.cancelFuncs, targetKey)
+		}
 	}
 
 	gapBetweenTargets := s.gapBetweenTargets()
@@ -171,10 +186,12 @@ func (s *Scheduler) refreshTargets(ctx context.Context) {
 		updatedTargets[key] = "ADD"
 
 		probeCtx, cancelF := context.WithCancel(ctx)
+		loopCtx := s.drainer.LoopContext(probeCtx, key)
 		s.waitGroup.Add(1)
 
 		go func(target endpoint.Endpoint, waitTime time.Duration) {
 			defer s.waitGroup.Done()
+			defer cancelF()
 			if waitTime > 0 {
 				// For random padding using 1/10th of the gap.
 				jitterMaxUsec := gapBetweenTargets.Microseconds() / 10
@@ -185,7 +202,7 @@ func (s *Scheduler) refreshTargets(ctx context.Context) {
 				// Wait for wait time + some jitter before starting this probe loop.
 				time.Sleep(waitTime + time.Duration(rand.Int63n(jitterMaxUsec))*time.Microsecond)
 			}
-			s.startForTarget(probeCtx, target)
+			s.startForTarget(probeCtx, loopCtx, target)
 		}(target, startWaitTime)
 
 		startWaitTime += gapBetweenTargets
@@ -109,3 +109,112 @@ func TestUpdateTargetsAndStartProbes(t *testing.T) {
 	cancelF()
 	s.Wait()
 }
+
+func TestTargetRemovalGracePeriod(t *testing.T) {
+	testTargets := [2]string{"test1.com", "test2.com"}
+
+	opts := &options.Options{
+		Targets:                  targets.StaticTargets(fmt.Sprintf("%s,%s", testTargets[0], testTargets[1])),
+		Interval:                 10 * time.Millisecond,
+		StatsExportInterval:      10 * time.Millisecond,
+		TargetRemovalGracePeriod: 300 * time.Millisecond,
+		LogMetrics:               func(_ *metrics.EventMetrics) {},
+		Logger:                   &logger.Logger{},
+	}
+
+	s := &Scheduler{
+		Opts:              opts,
+		DataChan:          make(chan *metrics.EventMetrics, 1000),
+		NewResult:         func() ProbeResult { return &testProbeResult{} },
+		RunProbeForTarget: func(ctx context.Context, ep endpoint.Endpoint, r ProbeResult) { r.(*testProbeResult).total++ },
+	}
+	s.init()
+
+	ctx, cancelF := context.WithCancel(context.Background())
+	defer func() {
+		cancelF()
+		s.Wait()
+	}()
+	s.refreshTargets(ctx)
+
+	// Remove the second target, it should continue to be probed during the
+	// grace period.
+	opts.Targets = targets.StaticTargets(testTargets[0])
+	s.refreshTargets(ctx)
+	if len(s.cancelFuncs) != 2 {
+		t.Errorf("len(s.cancelFunc)=%d, want=2", len(s.cancelFuncs))
+	}
+	ems, _ := testutils.MetricsFromChannel(s.DataChan, 1000, 100*time.Millisecond)
+	if n := len(testutils.MetricsMapByTarget(ems).Filter("total")[testTargets[1]]); n == 0 {
+		t.Errorf("Got no metrics for the draining target %s", testTargets[1])
+	}
+
+	// Bring it back within the grace period, probing should continue with the
+	// same probe loop.
+	opts.Targets = targets.StaticTargets(fmt.Sprintf("%s,%s", testTargets[0], testTargets[1]))
+	s.refreshTargets(ctx)
+	if len(s.cancelFuncs) != 2 || len(s.drainer.draining) != 0 {
+		t.Errorf("len(s.cancelFunc)=%d, len(draining)=%d, want=2, 0", len(s.cancelFuncs), len(s.drainer.draining))
+	}
+
+	// Remove it again and wait for the grace period to be over.
+	opts.Targets = targets.StaticTargets(testTargets[0])
+	s.refreshTargets(ctx)
+	time.Sleep(opts.TargetRemovalGracePeriod + 50*time.Millisecond)
+	s.refreshTargets(ctx)
+	if len(s.cancelFuncs) != 1 {
+		t.Errorf("len(s.cancelFunc)=%d, want=1", len(s.cancelFuncs))
+	}
+	testutils.MetricsFromChannel(s.DataChan, 1000, 10*time.Millisecond)
+	ems, _ = testutils.MetricsFromChannel(s.DataChan, 1000, 100*time.Millisecond)
+	if n := len(testutils.MetricsMapByTarget(ems).Filter("total")[testTargets[1]]); n != 0 {
+		t.Errorf("Got %d metrics for the removed target %s, want=0", n, testTargets[1])
+	}
+}
+
+func TestTargetRemovalGracePeriodInFlight(t *testing.T) {
+	opts := &options.Options{
+		Targets:                  targets.StaticTargets("test1.com"),
+		Interval:                 10 * time.Millisecond,
+		StatsExportInterval:      10 * time.Millisecond,
+		TargetRemovalGracePeriod: 50 * time.Millisecond,
+		LogMetrics:               func(_ *metrics.EventMetrics) {},
+		Logger:                   &logger.Logger{},
+	}
+
+	started := make(chan struct{})
+	runErr := make(chan error, 1)
+	s := &Scheduler{
+		Opts:      opts,
+		DataChan:  make(chan *metrics.EventMetrics, 100),
+		NewResult: func() ProbeResult { return &testProbeResult{} },
+		// Probe run that is in-flight when the grace period is over.
+		RunProbeForTarget: func(ctx context.Context, ep endpoint.Endpoint, r ProbeResult) {
+			r.(*testProbeResult).total++
+			close(started)
+			time.Sleep(2 * opts.TargetRemovalGracePeriod)
+			runErr <- ctx.Err()
+		},
+	}
+	s.init()
+
+	ctx, cancelF := context.WithCancel(context.Background())
+	defer cancelF()
+	s.refreshTargets(ctx)
+	<-started
+
+	opts.Targets = targets.StaticTargets("")
+	s.refreshTargets(ctx)
+	time.Sleep(opts.TargetRemovalGracePeriod + 10*time.Millisecond)
+	s.refreshTargets(ctx)
+	if len(s.cancelFuncs) != 0 {
+		t.Errorf("len(s.cancelFunc)=%d, want=0", len(s.cancelFuncs))
+	}
+
+	// In-flight probe finishes without its context being canceled, and the
+	// probe loop exits without running another probe.
+	if err := <-runErr; err != nil {
+		t.Errorf("In-flight probe's context canceled: %v", err)
+	}
+	s.Wait()
+}
@@ -82,6 +82,9 @@ type Probe struct {
 	queryType uint16
 	fqdn      string
 	client    Client
+
+	// Trust anchors, per zone, if DNSSEC validation is enabled.
+	trustAnchors map[string][]dns.RR
 }
 
 // probeRunResult captures the results of a single probe run. The way we work with
@@ -95,17 +98,22 @@ type probeRunResult struct {
 	latency           metrics.LatencyValue
 	timeouts          metrics.Int
 	validationFailure *metrics.Map[int64]
+	dnssecStatus      *metrics.Map[int64]
 	latencyMetricName string
 }
 
 // Metrics converts probeRunResult into metrics.EventMetrics object
 func (prr probeRunResult) Metrics() *metrics.EventMetrics {
-	return metrics.NewEventMetrics(time.Now()).
+	em := metrics.NewEventMetrics(time.Now()).
 		AddMetric("total", &prr.total).
 		AddMetric("success", &prr.success).
 		AddMetric(prr.latencyMetricName, prr.latency.Clone()).
 		AddMetric("timeouts", &prr.timeouts).
 		AddMetric("validation_failure", prr.validationFailure)
+	if prr.dnssecStatus != nil {
+		em.AddMetric("dnssec_status", prr.dnssecStatus)
+	}
+	return em
 }
 
 // Target returns the p.target.
@@ -134,6 +142,15 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 	p.queryType = uint16(queryType)
 	p.fqdn = dns.Fqdn(p.c.GetResolvedDomain())
 
+	p.trustAnchors = nil
+	if p.c.GetDnssecValidation() != nil {
+		anchors, err := parseTrustAnchors(p.c.GetDnssecValidation().GetTrustAnchor())
+		if err != nil {
+			return fmt.Errorf("dns_probe(%v): %v", name, err)
+		}
+		p.trustAnchors = anchors
+	}
+
 	// I believe the client is safe for concurrent use by multiple goroutines
 	// (although the documentation doesn't explicitly say so). It uses locks
 	// internally and the underlying net.Conn declares that multiple goroutines
@@ -192,6 +209,26 @@ func (p *Probe) validateResponse(resp *dns.Msg, target string, result *probeRunR
 	return true
 }
 
+// validateDNSSEC validates the DNSSEC signatures in the response, if DNSSEC
+// validation is enabled, and returns true if the response is secure.
+func (p *Probe) validateDNSSEC(resp *dns.Msg, target string, result *probeRunResult) bool {
+	if p.trustAnchors == nil {
+		return true
+	}
+
+	v := newDNSSECValidator(p.trustAnchors, func(msg *dns.Msg) (*dns.Msg, error) {
+		resp, _, err := p.client.Exchange(msg, target)
+		return resp, err
+	})
+	status, err := v.validate(resp)
+	result.dnssecStatus.IncKey(status)
+	if err != nil {
+		p.l.Warningf("Target(%s): DNSSEC validation failed, status: %s, err: %v", target, status, err)
+		return false
+	}
+	return true
+}
+
 func (p *Probe) runProbe(resultsChan chan<- statskeeper.ProbeResult) {
 	// Refresh the list of targets to probe.
 	p.targets = p.opts.Targets.ListEndpoints()
@@ -210,6 +247,9 @@ func (p *Probe) runProbe(resultsChan chan<- statskeeper.ProbeResult) {
 				latencyMetricName: p.opts.LatencyMetricName,
 				validationFailure: validators.ValidationFailureMap(p.opts.Validators),
 			}
+			if p.trustAnchors != nil {
+				result.dnssecStatus = newDNSSECStatusMap()
+			}
 
 			if p.opts.LatencyDist != nil {
 				result.latency = p.opts.LatencyDist.CloneDist()
@@ -250,6 +290,9 @@ func (p *Probe) runProbe(resultsChan chan<- statskeeper.ProbeResult) {
 			// Generate a new question for each probe so transaction IDs aren't repeated.
 			msg := new(dns.Msg)
 			msg.SetQuestion(p.fqdn, p.queryType)
+			if p.trustAnchors != nil {
+				setDNSSECOptions(msg)
+			}
 
 			resp, latency, err := p.client.Exchange(msg, fullTarget)
 
@@ -260,7 +303,7 @@ func (p *Probe) runProbe(resultsChan chan<- statskeeper.ProbeResult) {
 				} else {
 					p.l.Warningf("Target(%s): client.Exchange: %v", fullTarget, err)
 				}
-			} else if p.validateResponse(resp, fullTarget, &result) {
+			} else if p.validateResponse(resp, fullTarget, &result) && p.validateDNSSEC(resp, fullTarget, &result) {
 				result.success.Inc()
 				result.latency.AddFloat64(latency.Seconds() / p.opts.LatencyUnit.Seconds())
 			}
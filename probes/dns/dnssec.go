@@ -0,0 +1,308 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/miekg/dns"
+)
+
+// DNSSEC validation statuses, as defined in RFC 4035, section 4.3.
+const (
+	dnssecSecure   = "secure"
+	dnssecInsecure = "insecure"
+	dnssecBogus    = "bogus"
+)
+
+// Root zone KSK-2017, used if no trust anchor is configured.
+const rootTrustAnchor = ". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D"
+
+// Maximum number of zones in a chain of trust. It protects against loops
+// in broken delegations.
+const maxChainLength = 32
+
+// errInsecure is returned if signatures are missing, or if the chain of
+// trust is broken by an unsigned delegation.
+var errInsecure = errors.New("insecure")
+
+func newDNSSECStatusMap() *metrics.Map[int64] {
+	m := metrics.NewMap("status")
+	for _, status := range []string{dnssecSecure, dnssecInsecure, dnssecBogus} {
+		m.IncKeyBy(status, 0)
+	}
+	return m
+}
+
+// parseTrustAnchors parses the trust anchors into a map of zone name to the
+// zone's DS and DNSKEY records.
+func parseTrustAnchors(anchors []string) (map[string][]dns.RR, error) {
+	if len(anchors) == 0 {
+		anchors = []string{rootTrustAnchor}
+	}
+
+	m := make(map[string][]dns.RR)
+	for _, s := range anchors {
+		rr, err := dns.NewRR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trust anchor (%s): %v", s, err)
+		}
+		switch rr.(type) {
+		case *dns.DS, *dns.DNSKEY:
+		default:
+			return nil, fmt.Errorf("invalid trust anchor (%s): should be a DS or DNSKEY record", s)
+		}
+		zone := dns.CanonicalName(rr.Header().Name)
+		m[zone] = append(m[zone], rr)
+	}
+	return m, nil
+}
+
+// setDNSSECOptions sets the DO bit on the query. It also sets the CD bit so
+// that validating resolvers return bogus responses as well, instead of
+// SERVFAIL.
+func setDNSSECOptions(msg *dns.Msg) {
+	msg.SetEdns0(4096, true)
+	msg.CheckingDisabled = true
+}
+
+type rrsetKey struct {
+	name   string
+	rrtype uint16
+}
+
+// splitRRsets groups the records into RRsets, and returns them along with
+// the signatures covering them.
+func splitRRsets(rrs []dns.RR) (map[rrsetKey][]dns.RR, map[rrsetKey][]*dns.RRSIG) {
+	rrsets := make(map[rrsetKey][]dns.RR)
+	sigs := make(map[rrsetKey][]*dns.RRSIG)
+	for _, rr := range rrs {
+		name := dns.CanonicalName(rr.Header().Name)
+		if sig, ok := rr.(*dns.RRSIG); ok {
+			k := rrsetKey{name, sig.TypeCovered}
+			sigs[k] = append(sigs[k], sig)
+			continue
+		}
+		k := rrsetKey{name, rr.Header().Rrtype}
+		rrsets[k] = append(rrsets[k], rr)
+	}
+	return rrsets, sigs
+}
+
+// dnssecValidator validates DNSSEC signatures of the responses, following
+// the chain of trust up to a trust anchor. The DNSKEY and DS records that
+// are needed for it are queried using the exchange function.
+type dnssecValidator struct {
+	anchors  map[string][]dns.RR
+	exchange func(*dns.Msg) (*dns.Msg, error)
+	now      time.Time
+
+	// Validated DNSKEYs, per zone.
+	zoneKeys map[string][]*dns.DNSKEY
+}
+
+func newDNSSECValidator(anchors map[string][]dns.RR, exchange func(*dns.Msg) (*dns.Msg, error)) *dnssecValidator {
+	return &dnssecValidator{
+		anchors:  anchors,
+		exchange: exchange,
+		now:      time.Now(),
+		zoneKeys: make(map[string][]*dns.DNSKEY),
+	}
+}
+
+// validate validates all RRsets in the answer section of the response, and
+// returns the validation status.
+func (v *dnssecValidator) validate(resp *dns.Msg) (string, error) {
+	rrsets, sigs := splitRRsets(resp.Answer)
+	if len(rrsets) == 0 {
+		return dnssecInsecure, errors.New("no answers to validate")
+	}
+
+	for k, rrset := range rrsets {
+		err := v.verifyRRset(rrset, sigs[k], 0)
+		if errors.Is(err, errInsecure) {
+			return dnssecInsecure, err
+		}
+		if err != nil {
+			return dnssecBogus, err
+		}
+	}
+	return dnssecSecure, nil
+}
+
+func (v *dnssecValidator) query(name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(name, qtype)
+	setDNSSECOptions(msg)
+
+	resp, err := v.exchange(msg)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s query error: %v", name, dns.TypeToString[qtype], err)
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("%s %s query error: %s", name, dns.TypeToString[qtype], dns.RcodeToString[resp.Rcode])
+	}
+	return resp, nil
+}
+
+// verifyRRset verifies that at least one of the signatures is valid and is
+// made by a trusted key. depth is the position of the RRset's zone in the chain
+// of trust.
+func (v *dnssecValidator) verifyRRset(rrset []dns.RR, sigs []*dns.RRSIG, depth int) error {
+	hdr := rrset[0].Header()
+	if len(sigs) == 0 {
+		return fmt.Errorf("%w: no signatures for %s %s", errInsecure, hdr.Name, dns.TypeToString[hdr.Rrtype])
+	}
+
+	var lastErr error
+	for _, sig := range sigs {
+		signer := dns.CanonicalName(sig.SignerName)
+		// Signer should be the owner's zone. DS records are signed by the
+		// parent zone.
+		if !dns.IsSubDomain(signer, hdr.Name) || (hdr.Rrtype == dns.TypeDS && signer == dns.CanonicalName(hdr.Name)) {
+			lastErr = fmt.Errorf("invalid signer %s for %s %s", signer, hdr.Name, dns.TypeToString[hdr.Rrtype])
+			continue
+		}
+		keys, err := v.trustedZoneKeys(signer, depth+1)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if lastErr = v.verifySig(sig, keys, rrset); lastErr == nil {
+			return nil
+		}
+	}
+	return lastErr
+}
+
+func (v *dnssecValidator) verifySig(sig *dns.RRSIG, keys []*dns.DNSKEY, rrset []dns.RR) error {
+	hdr := rrset[0].Header()
+	if !sig.ValidityPeriod(v.now) {
+		return fmt.Errorf("signature (key tag: %d) for %s %s is expired or not yet valid", sig.KeyTag, hdr.Name, dns.TypeToString[hdr.Rrtype])
+	}
+	for _, k := range keys {
+		if k.KeyTag() == sig.KeyTag && k.Algorithm == sig.Algorithm && sig.Verify(k, rrset) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature (key tag: %d) for %s %s could not be verified", sig.KeyTag, hdr.Name, dns.TypeToString[hdr.Rrtype])
+}
+
+// trustedZoneKeys returns the zone's DNSKEYs, after verifying that the DNSKEY
+// RRset is signed by a key that is either a trust anchor, or is authenticated
+// by the zone's DS records in the parent zone.
+func (v *dnssecValidator) trustedZoneKeys(zone string, depth int) ([]*dns.DNSKEY, error) {
+	if keys, ok := v.zoneKeys[zone]; ok {
+		return keys, nil
+	}
+	if depth > maxChainLength {
+		return nil, fmt.Errorf("chain of trust for %s is too long", zone)
+	}
+
+	resp, err := v.query(zone, dns.TypeDNSKEY)
+	if err != nil {
+		return nil, err
+	}
+	rrsets, sigs := splitRRsets(resp.Answer)
+	k := rrsetKey{zone, dns.TypeDNSKEY}
+	keysRRset := rrsets[k]
+	if len(keysRRset) == 0 {
+		return nil, fmt.Errorf("no DNSKEY records for %s", zone)
+	}
+	var keys []*dns.DNSKEY
+	for _, rr := range keysRRset {
+		keys = append(keys, rr.(*dns.DNSKEY))
+	}
+
+	sepKeys, err := v.secureEntryKeys(zone, keys, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error = fmt.Errorf("no signatures for %s DNSKEY", zone)
+	for _, sig := range sigs[k] {
+		if lastErr = v.verifySig(sig, sepKeys, keysRRset); lastErr == nil {
+			v.zoneKeys[zone] = keys
+			return keys, nil
+		}
+	}
+	return nil, lastErr
+}
+
+// secureEntryKeys returns the zone's keys that match the zone's trust
+// anchors, or if there is no trust anchor for the zone, its DS records.
+func (v *dnssecValidator) secureEntryKeys(zone string, keys []*dns.DNSKEY, depth int) ([]*dns.DNSKEY, error) {
+	anchors := v.anchors[zone]
+	if len(anchors) == 0 {
+		if zone == "." {
+			return nil, errors.New("no trust anchor found")
+		}
+		ds, err := v.delegationSigners(zone, depth)
+		if err != nil {
+			return nil, err
+		}
+		anchors = ds
+	}
+
+	var sepKeys []*dns.DNSKEY
+	for _, k := range keys {
+		for _, anchor := range anchors {
+			if keyMatchesAnchor(k, anchor) {
+				sepKeys = append(sepKeys, k)
+				break
+			}
+		}
+	}
+	if len(sepKeys) == 0 {
+		return nil, fmt.Errorf("no DNSKEY for %s matches its trust anchors or DS records", zone)
+	}
+	return sepKeys, nil
+}
+
+// delegationSigners returns the zone's DS records, after verifying them
+// using the parent zone's keys.
+func (v *dnssecValidator) delegationSigners(zone string, depth int) ([]dns.RR, error) {
+	resp, err := v.query(zone, dns.TypeDS)
+	if err != nil {
+		return nil, err
+	}
+	rrsets, sigs := splitRRsets(resp.Answer)
+	k := rrsetKey{zone, dns.TypeDS}
+	if len(rrsets[k]) == 0 {
+		return nil, fmt.Errorf("%w: no DS records for %s", errInsecure, zone)
+	}
+	if err := v.verifyRRset(rrsets[k], sigs[k], depth); err != nil {
+		return nil, err
+	}
+	return rrsets[k], nil
+}
+
+func keyMatchesAnchor(k *dns.DNSKEY, anchor dns.RR) bool {
+	switch a := anchor.(type) {
+	case *dns.DS:
+		if k.KeyTag() != a.KeyTag || k.Algorithm != a.Algorithm {
+			return false
+		}
+		ds := k.ToDS(a.DigestType)
+		return ds != nil && strings.EqualFold(ds.Digest, a.Digest)
+	case *dns.DNSKEY:
+		return k.Algorithm == a.Algorithm && k.Flags == a.Flags && k.PublicKey == a.PublicKey
+	}
+	return false
+}
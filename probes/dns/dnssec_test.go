@@ -0,0 +1,193 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"crypto"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/probes/common/statskeeper"
+	configpb "github.com/cloudprober/cloudprober/probes/dns/proto"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+type testZoneKey struct {
+	key  *dns.DNSKEY
+	priv crypto.Signer
+}
+
+func newTestZoneKey(t *testing.T, zone string) *testZoneKey {
+	t.Helper()
+	k := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: zone, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	priv, err := k.Generate(256)
+	if err != nil {
+		t.Fatalf("error generating key for %s: %v", zone, err)
+	}
+	return &testZoneKey{key: k, priv: priv.(crypto.Signer)}
+}
+
+func (zk *testZoneKey) sign(t *testing.T, rrset []dns.RR, now time.Time) *dns.RRSIG {
+	t.Helper()
+	hdr := rrset[0].Header()
+	sig := &dns.RRSIG{
+		Hdr:        dns.RR_Header{Name: hdr.Name, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: hdr.Ttl},
+		KeyTag:     zk.key.KeyTag(),
+		SignerName: zk.key.Hdr.Name,
+		Algorithm:  zk.key.Algorithm,
+		Inception:  uint32(now.Add(-time.Hour).Unix()),
+		Expiration: uint32(now.Add(time.Hour).Unix()),
+	}
+	if err := sig.Sign(zk.priv, rrset); err != nil {
+		t.Fatalf("error signing %s: %v", hdr.Name, err)
+	}
+	return sig
+}
+
+func testRR(t *testing.T, s string) dns.RR {
+	t.Helper()
+	rr, err := dns.NewRR(s)
+	if err != nil {
+		t.Fatalf("error parsing RR (%s): %v", s, err)
+	}
+	return rr
+}
+
+// dnssecMockClient answers queries from a static set of answers. It strips
+// the signatures if DO bit is not set on the query.
+type dnssecMockClient struct {
+	answers map[rrsetKey][]dns.RR
+}
+
+func (c *dnssecMockClient) Exchange(in *dns.Msg, fullTarget string) (*dns.Msg, time.Duration, error) {
+	out := new(dns.Msg)
+	out.SetReply(in)
+	q := in.Question[0]
+	do := in.IsEdns0() != nil && in.IsEdns0().Do()
+	for _, rr := range c.answers[rrsetKey{dns.CanonicalName(q.Name), q.Qtype}] {
+		if _, ok := rr.(*dns.RRSIG); ok && !do {
+			continue
+		}
+		out.Answer = append(out.Answer, rr)
+	}
+	return out, time.Millisecond, nil
+}
+func (*dnssecMockClient) setReadTimeout(time.Duration) {}
+func (*dnssecMockClient) setSourceIP(net.IP)           {}
+
+// testDNSSECClient returns a mock client serving the following zones:
+//   - example.: trust anchor.
+//   - sub.example.: signed, with DS records in example.
+//   - nods.example.: signed, but with no DS records in example.
+func testDNSSECClient(t *testing.T) (*dnssecMockClient, string) {
+	now := time.Now()
+	c := &dnssecMockClient{answers: make(map[rrsetKey][]dns.RR)}
+	add := func(signer *testZoneKey, rrset ...dns.RR) {
+		hdr := rrset[0].Header()
+		k := rrsetKey{hdr.Name, hdr.Rrtype}
+		c.answers[k] = append(c.answers[k], rrset...)
+		if signer != nil {
+			c.answers[k] = append(c.answers[k], signer.sign(t, rrset, now))
+		}
+	}
+
+	zoneKeys := map[string]*testZoneKey{}
+	for _, zone := range []string{"example.", "sub.example.", "nods.example."} {
+		zk := newTestZoneKey(t, zone)
+		zoneKeys[zone] = zk
+		add(zk, zk.key)
+	}
+	add(zoneKeys["example."], zoneKeys["sub.example."].key.ToDS(dns.SHA256))
+
+	add(zoneKeys["sub.example."], testRR(t, "www.sub.example. 300 IN A 192.168.0.1"))
+	add(zoneKeys["nods.example."], testRR(t, "www.nods.example. 300 IN A 192.168.0.2"))
+	add(nil, testRR(t, "unsigned.example. 300 IN A 192.168.0.3"))
+
+	// Signature made for a different address.
+	add(zoneKeys["example."], testRR(t, "bad.example. 300 IN A 192.168.0.4"))
+	c.answers[rrsetKey{"bad.example.", dns.TypeA}][0] = testRR(t, "bad.example. 300 IN A 192.168.0.5")
+
+	return c, zoneKeys["example."].key.ToDS(dns.SHA256).String()
+}
+
+func TestParseTrustAnchors(t *testing.T) {
+	anchors, err := parseTrustAnchors(nil)
+	assert.NoError(t, err)
+	assert.Len(t, anchors["."], 1, "default root trust anchor")
+
+	_, err = parseTrustAnchors([]string{"example. 300 IN A 192.168.0.1"})
+	assert.Error(t, err, "A record as trust anchor")
+	_, err = parseTrustAnchors([]string{"bad anchor"})
+	assert.Error(t, err, "bad trust anchor")
+}
+
+func TestDNSSECValidation(t *testing.T) {
+	client, trustAnchor := testDNSSECClient(t)
+
+	tests := []struct {
+		domain      string
+		wantStatus  string
+		wantSuccess int64
+	}{
+		{domain: "www.sub.example.", wantStatus: dnssecSecure, wantSuccess: 1},
+		{domain: "www.nods.example.", wantStatus: dnssecInsecure},
+		{domain: "unsigned.example.", wantStatus: dnssecInsecure},
+		{domain: "nosuchname.example.", wantStatus: dnssecInsecure},
+		{domain: "bad.example.", wantStatus: dnssecBogus},
+	}
+
+	for _, test := range tests {
+		t.Run(test.domain, func(t *testing.T) {
+			aType := configpb.QueryType_A
+			opts := options.DefaultOptions()
+			opts.Targets = targets.StaticTargets("8.8.8.8")
+			opts.ProbeConf = &configpb.ProbeConf{
+				ResolvedDomain: proto.String(test.domain),
+				QueryType:      &aType,
+				DnssecValidation: &configpb.ProbeConf_DNSSECValidation{
+					TrustAnchor: []string{trustAnchor},
+				},
+			}
+			p := &Probe{}
+			if err := p.Init("dnssec_test", opts); err != nil {
+				t.Fatalf("Error creating probe: %v", err)
+			}
+			p.client = client
+
+			resultsChan := make(chan statskeeper.ProbeResult, 1)
+			p.runProbe(resultsChan)
+			result := (<-resultsChan).(probeRunResult)
+
+			assert.Equal(t, test.wantSuccess, result.success.Int64(), "success")
+			for _, status := range []string{dnssecSecure, dnssecInsecure, dnssecBogus} {
+				want := int64(0)
+				if status == test.wantStatus {
+					want = 1
+				}
+				assert.Equal(t, want, result.dnssecStatus.GetKey(status), status)
+			}
+		})
+	}
+}
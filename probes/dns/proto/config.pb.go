@@ -209,6 +209,13 @@ type ProbeConf struct {
 	// default we resolve first if it's a discovered resource, e.g., a k8s
 	// endpoint.
 	ResolveFirst *bool `protobuf:"varint,5,opt,name=resolve_first,json=resolveFirst" json:"resolve_first,omitempty"`
+	// If set, probe requests DNSSEC records (DO bit), and validates the RRSIG
+	// chain of the answers up to a trust anchor. Probe fails if signatures are
+	// missing or validation fails. Validation status is exported as the
+	// dnssec_status metric, with "secure", "insecure" and "bogus" keys.
+	// Note that denial of existence (NSEC/NSEC3) is not validated; negative
+	// responses and unsigned delegations are reported as "insecure".
+	DnssecValidation *ProbeConf_DNSSECValidation `protobuf:"bytes,6,opt,name=dnssec_validation,json=dnssecValidation" json:"dnssec_validation,omitempty"`
 }
 
 // Default values for ProbeConf fields.
@@ -278,6 +285,62 @@ func (x *ProbeConf) GetResolveFirst() bool {
 	return false
 }
 
+func (x *ProbeConf) GetDnssecValidation() *ProbeConf_DNSSECValidation {
+	if x != nil {
+		return x.DnssecValidation
+	}
+	return nil
+}
+
+type ProbeConf_DNSSECValidation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Trust anchors, as DS or DNSKEY records in the zone file format, e.g.
+	// ". IN DS 20326 8 2 E06D44B8...". Default is the root zone KSK.
+	TrustAnchor []string `protobuf:"bytes,1,rep,name=trust_anchor,json=trustAnchor" json:"trust_anchor,omitempty"`
+}
+
+func (x *ProbeConf_DNSSECValidation) Reset() {
+	*x = ProbeConf_DNSSECValidation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProbeConf_DNSSECValidation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeConf_DNSSECValidation) ProtoMessage() {}
+
+func (x *ProbeConf_DNSSECValidation) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeConf_DNSSECValidation.ProtoReflect.Descriptor instead.
+func (*ProbeConf_DNSSECValidation) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *ProbeConf_DNSSECValidation) GetTrustAnchor() []string {
+	if x != nil {
+		return x.TrustAnchor
+	}
+	return nil
+}
+
 var File_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto protoreflect.FileDescriptor
 
 var file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDesc = []byte{
@@ -286,7 +349,7 @@ var file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDes
 	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x64, 0x6e, 0x73, 0x2f,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x12, 0x16, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e,
-	0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x64, 0x6e, 0x73, 0x22, 0xd4, 0x01, 0x0a, 0x09, 0x50,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x64, 0x6e, 0x73, 0x22, 0xec, 0x02, 0x0a, 0x09, 0x50,
 	0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x12, 0x38, 0x0a, 0x0f, 0x72, 0x65, 0x73, 0x6f,
 	0x6c, 0x76, 0x65, 0x64, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x09, 0x3a, 0x0f, 0x77, 0x77, 0x77, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x6f,
@@ -300,36 +363,46 @@ var file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDes
 	0x52, 0x0a, 0x6d, 0x69, 0x6e, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x73, 0x12, 0x23, 0x0a, 0x0d,
 	0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x5f, 0x66, 0x69, 0x72, 0x73, 0x74, 0x18, 0x05, 0x20,
 	0x01, 0x28, 0x08, 0x52, 0x0c, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x46, 0x69, 0x72, 0x73,
-	0x74, 0x2a, 0xa4, 0x03, 0x0a, 0x09, 0x51, 0x75, 0x65, 0x72, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12,
-	0x08, 0x0a, 0x04, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x05, 0x0a, 0x01, 0x41, 0x10, 0x01,
-	0x12, 0x06, 0x0a, 0x02, 0x4e, 0x53, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x43, 0x4e, 0x41, 0x4d,
-	0x45, 0x10, 0x05, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x4f, 0x41, 0x10, 0x06, 0x12, 0x07, 0x0a, 0x03,
-	0x50, 0x54, 0x52, 0x10, 0x0c, 0x12, 0x06, 0x0a, 0x02, 0x4d, 0x58, 0x10, 0x0f, 0x12, 0x07, 0x0a,
-	0x03, 0x54, 0x58, 0x54, 0x10, 0x10, 0x12, 0x06, 0x0a, 0x02, 0x52, 0x50, 0x10, 0x11, 0x12, 0x09,
-	0x0a, 0x05, 0x41, 0x46, 0x53, 0x44, 0x42, 0x10, 0x12, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x49, 0x47,
-	0x10, 0x18, 0x12, 0x07, 0x0a, 0x03, 0x4b, 0x45, 0x59, 0x10, 0x19, 0x12, 0x08, 0x0a, 0x04, 0x41,
-	0x41, 0x41, 0x41, 0x10, 0x1c, 0x12, 0x07, 0x0a, 0x03, 0x4c, 0x4f, 0x43, 0x10, 0x1d, 0x12, 0x07,
-	0x0a, 0x03, 0x53, 0x52, 0x56, 0x10, 0x21, 0x12, 0x09, 0x0a, 0x05, 0x4e, 0x41, 0x50, 0x54, 0x52,
-	0x10, 0x23, 0x12, 0x06, 0x0a, 0x02, 0x4b, 0x58, 0x10, 0x24, 0x12, 0x08, 0x0a, 0x04, 0x43, 0x45,
-	0x52, 0x54, 0x10, 0x25, 0x12, 0x09, 0x0a, 0x05, 0x44, 0x4e, 0x41, 0x4d, 0x45, 0x10, 0x27, 0x12,
-	0x07, 0x0a, 0x03, 0x41, 0x50, 0x4c, 0x10, 0x2a, 0x12, 0x06, 0x0a, 0x02, 0x44, 0x53, 0x10, 0x2b,
-	0x12, 0x09, 0x0a, 0x05, 0x53, 0x53, 0x48, 0x46, 0x50, 0x10, 0x2c, 0x12, 0x0c, 0x0a, 0x08, 0x49,
-	0x50, 0x53, 0x45, 0x43, 0x4b, 0x45, 0x59, 0x10, 0x2d, 0x12, 0x09, 0x0a, 0x05, 0x52, 0x52, 0x53,
-	0x49, 0x47, 0x10, 0x2e, 0x12, 0x08, 0x0a, 0x04, 0x4e, 0x53, 0x45, 0x43, 0x10, 0x2f, 0x12, 0x0a,
-	0x0a, 0x06, 0x44, 0x4e, 0x53, 0x4b, 0x45, 0x59, 0x10, 0x30, 0x12, 0x09, 0x0a, 0x05, 0x44, 0x48,
-	0x43, 0x49, 0x44, 0x10, 0x31, 0x12, 0x09, 0x0a, 0x05, 0x4e, 0x53, 0x45, 0x43, 0x33, 0x10, 0x32,
-	0x12, 0x0e, 0x0a, 0x0a, 0x4e, 0x53, 0x45, 0x43, 0x33, 0x50, 0x41, 0x52, 0x41, 0x4d, 0x10, 0x33,
-	0x12, 0x08, 0x0a, 0x04, 0x54, 0x4c, 0x53, 0x41, 0x10, 0x34, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x49,
-	0x50, 0x10, 0x37, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x44, 0x53, 0x10, 0x3b, 0x12, 0x0b, 0x0a, 0x07,
-	0x43, 0x44, 0x4e, 0x53, 0x4b, 0x45, 0x59, 0x10, 0x3c, 0x12, 0x0e, 0x0a, 0x0a, 0x4f, 0x50, 0x45,
-	0x4e, 0x50, 0x47, 0x50, 0x4b, 0x45, 0x59, 0x10, 0x3d, 0x12, 0x09, 0x0a, 0x04, 0x54, 0x4b, 0x45,
-	0x59, 0x10, 0xf9, 0x01, 0x12, 0x09, 0x0a, 0x04, 0x54, 0x53, 0x49, 0x47, 0x10, 0xfa, 0x01, 0x12,
-	0x08, 0x0a, 0x03, 0x55, 0x52, 0x49, 0x10, 0x80, 0x02, 0x12, 0x08, 0x0a, 0x03, 0x43, 0x41, 0x41,
-	0x10, 0x81, 0x02, 0x12, 0x08, 0x0a, 0x02, 0x54, 0x41, 0x10, 0x80, 0x80, 0x02, 0x12, 0x09, 0x0a,
-	0x03, 0x44, 0x4c, 0x56, 0x10, 0x81, 0x80, 0x02, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62,
-	0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70,
-	0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x64, 0x6e, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x74, 0x12, 0x5f, 0x0a, 0x11, 0x64, 0x6e, 0x73, 0x73, 0x65, 0x63, 0x5f, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65,
+	0x73, 0x2e, 0x64, 0x6e, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x2e,
+	0x44, 0x4e, 0x53, 0x53, 0x45, 0x43, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x10, 0x64, 0x6e, 0x73, 0x73, 0x65, 0x63, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x1a, 0x35, 0x0a, 0x10, 0x44, 0x4e, 0x53, 0x53, 0x45, 0x43, 0x56, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x72, 0x75, 0x73, 0x74, 0x5f,
+	0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x72,
+	0x75, 0x73, 0x74, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x2a, 0xa4, 0x03, 0x0a, 0x09, 0x51, 0x75,
+	0x65, 0x72, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x4e, 0x4f, 0x4e, 0x45, 0x10,
+	0x00, 0x12, 0x05, 0x0a, 0x01, 0x41, 0x10, 0x01, 0x12, 0x06, 0x0a, 0x02, 0x4e, 0x53, 0x10, 0x02,
+	0x12, 0x09, 0x0a, 0x05, 0x43, 0x4e, 0x41, 0x4d, 0x45, 0x10, 0x05, 0x12, 0x07, 0x0a, 0x03, 0x53,
+	0x4f, 0x41, 0x10, 0x06, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x54, 0x52, 0x10, 0x0c, 0x12, 0x06, 0x0a,
+	0x02, 0x4d, 0x58, 0x10, 0x0f, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x58, 0x54, 0x10, 0x10, 0x12, 0x06,
+	0x0a, 0x02, 0x52, 0x50, 0x10, 0x11, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x46, 0x53, 0x44, 0x42, 0x10,
+	0x12, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x49, 0x47, 0x10, 0x18, 0x12, 0x07, 0x0a, 0x03, 0x4b, 0x45,
+	0x59, 0x10, 0x19, 0x12, 0x08, 0x0a, 0x04, 0x41, 0x41, 0x41, 0x41, 0x10, 0x1c, 0x12, 0x07, 0x0a,
+	0x03, 0x4c, 0x4f, 0x43, 0x10, 0x1d, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x52, 0x56, 0x10, 0x21, 0x12,
+	0x09, 0x0a, 0x05, 0x4e, 0x41, 0x50, 0x54, 0x52, 0x10, 0x23, 0x12, 0x06, 0x0a, 0x02, 0x4b, 0x58,
+	0x10, 0x24, 0x12, 0x08, 0x0a, 0x04, 0x43, 0x45, 0x52, 0x54, 0x10, 0x25, 0x12, 0x09, 0x0a, 0x05,
+	0x44, 0x4e, 0x41, 0x4d, 0x45, 0x10, 0x27, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x50, 0x4c, 0x10, 0x2a,
+	0x12, 0x06, 0x0a, 0x02, 0x44, 0x53, 0x10, 0x2b, 0x12, 0x09, 0x0a, 0x05, 0x53, 0x53, 0x48, 0x46,
+	0x50, 0x10, 0x2c, 0x12, 0x0c, 0x0a, 0x08, 0x49, 0x50, 0x53, 0x45, 0x43, 0x4b, 0x45, 0x59, 0x10,
+	0x2d, 0x12, 0x09, 0x0a, 0x05, 0x52, 0x52, 0x53, 0x49, 0x47, 0x10, 0x2e, 0x12, 0x08, 0x0a, 0x04,
+	0x4e, 0x53, 0x45, 0x43, 0x10, 0x2f, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x4e, 0x53, 0x4b, 0x45, 0x59,
+	0x10, 0x30, 0x12, 0x09, 0x0a, 0x05, 0x44, 0x48, 0x43, 0x49, 0x44, 0x10, 0x31, 0x12, 0x09, 0x0a,
+	0x05, 0x4e, 0x53, 0x45, 0x43, 0x33, 0x10, 0x32, 0x12, 0x0e, 0x0a, 0x0a, 0x4e, 0x53, 0x45, 0x43,
+	0x33, 0x50, 0x41, 0x52, 0x41, 0x4d, 0x10, 0x33, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x4c, 0x53, 0x41,
+	0x10, 0x34, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x49, 0x50, 0x10, 0x37, 0x12, 0x07, 0x0a, 0x03, 0x43,
+	0x44, 0x53, 0x10, 0x3b, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x44, 0x4e, 0x53, 0x4b, 0x45, 0x59, 0x10,
+	0x3c, 0x12, 0x0e, 0x0a, 0x0a, 0x4f, 0x50, 0x45, 0x4e, 0x50, 0x47, 0x50, 0x4b, 0x45, 0x59, 0x10,
+	0x3d, 0x12, 0x09, 0x0a, 0x04, 0x54, 0x4b, 0x45, 0x59, 0x10, 0xf9, 0x01, 0x12, 0x09, 0x0a, 0x04,
+	0x54, 0x53, 0x49, 0x47, 0x10, 0xfa, 0x01, 0x12, 0x08, 0x0a, 0x03, 0x55, 0x52, 0x49, 0x10, 0x80,
+	0x02, 0x12, 0x08, 0x0a, 0x03, 0x43, 0x41, 0x41, 0x10, 0x81, 0x02, 0x12, 0x08, 0x0a, 0x02, 0x54,
+	0x41, 0x10, 0x80, 0x80, 0x02, 0x12, 0x09, 0x0a, 0x03, 0x44, 0x4c, 0x56, 0x10, 0x81, 0x80, 0x02,
+	0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x64, 0x6e,
+	0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 }
 
 var (
@@ -345,18 +418,20 @@ func file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDe
 }
 
 var file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_goTypes = []interface{}{
-	(QueryType)(0),    // 0: cloudprober.probes.dns.QueryType
-	(*ProbeConf)(nil), // 1: cloudprober.probes.dns.ProbeConf
+	(QueryType)(0),                     // 0: cloudprober.probes.dns.QueryType
+	(*ProbeConf)(nil),                  // 1: cloudprober.probes.dns.ProbeConf
+	(*ProbeConf_DNSSECValidation)(nil), // 2: cloudprober.probes.dns.ProbeConf.DNSSECValidation
 }
 var file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_depIdxs = []int32{
 	0, // 0: cloudprober.probes.dns.ProbeConf.query_type:type_name -> cloudprober.probes.dns.QueryType
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2, // 1: cloudprober.probes.dns.ProbeConf.dnssec_validation:type_name -> cloudprober.probes.dns.ProbeConf.DNSSECValidation
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_init() }
@@ -377,6 +452,18 @@ func file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_init(
 				return nil
 			}
 		}
+		file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProbeConf_DNSSECValidation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -384,7 +471,7 @@ func file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_init(
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -102,4 +102,18 @@ package proto
 	// default we resolve first if it's a discovered resource, e.g., a k8s
 	// endpoint.
 	resolveFirst?: bool @protobuf(5,bool,name=resolve_first)
+
+	#DNSSECValidation: {
+		// Trust anchors, as DS or DNSKEY records in the zone file format, e.g.
+		// ". IN DS 20326 8 2 E06D44B8...". Default is the root zone KSK.
+		trustAnchor?: [...string] @protobuf(1,string,name=trust_anchor)
+	}
+
+	// If set, probe requests DNSSEC records (DO bit), and validates the RRSIG
+	// chain of the answers up to a trust anchor. Probe fails if signatures are
+	// missing or validation fails. Validation status is exported as the
+	// dnssec_status metric, with "secure", "insecure" and "bogus" keys.
+	// Note that denial of existence (NSEC/NSEC3) is not validated; negative
+	// responses and unsigned delegations are reported as "insecure".
+	dnssecValidation?: #DNSSECValidation @protobuf(6,DNSSECValidation,name=dnssec_validation)
 }
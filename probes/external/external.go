@@ -20,6 +20,12 @@ can have two modes: "once" and "server". In "once" mode, the external process is
 started for each probe run cycle, while in "server" mode, external process is
 started only if it's not running already and Cloudprober communicates with it
 over stdin/stdout for each probe cycle.
+
+External process gets the probe context through the environment:
+CLOUDPROBER_PROBE is set to the probe name, and, in "once" mode,
+CLOUDPROBER_TARGET is set to the target name. In "server" mode, as the same
+process serves all the targets, target name is sent in each ProbeRequest.
+Configured env_var variables are added to the environment as well.
 */
 package external
 
@@ -68,6 +74,12 @@ var (
 	validLabelRe        = regexp.MustCompile(`@(target|address|port|probe|target\.label\.[^@]+)@`)
 )
 
+// Environment variables set by cloudprober for the external probe process.
+const (
+	probeEnvVar  = "CLOUDPROBER_PROBE"
+	targetEnvVar = "CLOUDPROBER_TARGET"
+)
+
 type result struct {
 	total, success    int64
 	latency           metrics.LatencyValue
@@ -98,6 +110,11 @@ type Probe struct {
 	results      map[string]*result // probe results keyed by targets
 	dataChan     chan *metrics.EventMetrics
 
+	// Sweeps truncated because of max_run_duration, and the targets skipped
+	// in them.
+	sweepsTruncated     int64
+	sweepSkippedTargets int64
+
 	// This is used for overriding run command logic for testing.
 	runCommandFunc func(ctx context.Context, cmd string, args, envVars []string) ([]byte, []byte, error)
 
@@ -106,6 +123,18 @@ type Probe struct {
 	payloadParser *payload.Parser
 }
 
+// cmdEnv returns the environment variables for the external probe process:
+// configured variables, followed by the probe context variables, so that
+// the context variables take precedence. Target is nil for the server mode.
+func (p *Probe) cmdEnv(target *endpoint.Endpoint) []string {
+	env := append([]string{}, p.envVars...)
+	env = append(env, probeEnvVar+"="+p.name)
+	if target != nil {
+		env = append(env, targetEnvVar+"="+target.Name)
+	}
+	return env
+}
+
 func (p *Probe) updateLabelKeys() {
 	p.labelKeys = make(map[string]bool)
 
@@ -244,9 +273,7 @@ func (p *Probe) startCmdIfNotRunning(startCtx context.Context) error {
 	if p.cmdStderr, err = cmd.StderrPipe(); err != nil {
 		return err
 	}
-	if len(p.envVars) > 0 {
-		cmd.Env = append(cmd.Env, p.envVars...)
-	}
+	cmd.Env = append(os.Environ(), p.cmdEnv(nil)...)
 
 	go func() {
 		scanner := bufio.NewScanner(p.cmdStderr)
@@ -289,7 +316,7 @@ func (p *Probe) readProbeReplies(done chan struct{}) error {
 			return nil
 		default:
 		}
-		rep, err := serverutils.ReadProbeReply(bufReader)
+		replies, err := serverutils.ReadProbeReplies(bufReader)
 		if err != nil {
 			// Return if external probe process pipe has closed. We get:
 			//  io.EOF: when other process has closed the pipe.
@@ -303,7 +330,9 @@ func (p *Probe) readProbeReplies(done chan struct{}) error {
 			p.l.Errorf("Error reading probe reply: %s", err.Error())
 			continue
 		}
-		p.replyChan <- rep
+		for _, rep := range replies {
+			p.replyChan <- rep
+		}
 	}
 
 }
@@ -341,6 +370,7 @@ func (p *Probe) sendRequest(requestID int32, ep endpoint.Endpoint) error {
 		RequestId: proto.Int32(requestID),
 		TimeLimit: proto.Int32(int32(p.opts.Timeout / time.Millisecond)),
 		Options:   []*serverpb.ProbeRequest_Option{},
+		Target:    proto.String(ep.Name),
 	}
 	for _, opt := range p.c.GetOptions() {
 		value := opt.GetValue()
@@ -378,7 +408,7 @@ type probeStatus struct {
 
 func (p *Probe) processProbeResult(ps *probeStatus, result *result) {
 	if ps.success && p.opts.Validators != nil {
-		failedValidations := validators.RunValidators(p.opts.Validators, &validators.Input{ResponseBody: []byte(ps.payload)}, result.validationFailure, p.l)
+		failedValidations := validators.RunValidators(p.opts.Validators, &validators.Input{ResponseBody: []byte(ps.payload), Latency: ps.latency}, result.validationFailure, p.l)
 
 		// If any validation failed, log and set success to false.
 		if len(failedValidations) > 0 {
@@ -414,6 +444,27 @@ func (p *Probe) processProbeResult(ps *probeStatus, result *result) {
 	}
 }
 
+// sweepOverBudget reports whether the sweep started at the given time has
+// run over the max_run_duration.
+func (p *Probe) sweepOverBudget(start time.Time) bool {
+	return p.opts.MaxRunDuration > 0 && time.Since(start) > p.opts.MaxRunDuration
+}
+
+// recordTruncatedSweep records a sweep that was stopped with the given number
+// of targets left to probe.
+func (p *Probe) recordTruncatedSweep(skipped int) {
+	p.l.Warningf("Sweep didn't finish within max_run_duration (%v), skipped %d targets", p.opts.MaxRunDuration, skipped)
+	p.sweepsTruncated++
+	p.sweepSkippedTargets += int64(skipped)
+
+	em := metrics.NewEventMetrics(time.Now()).
+		AddMetric("sweep_truncated", metrics.NewInt(p.sweepsTruncated)).
+		AddMetric("sweep_skipped_targets", metrics.NewInt(p.sweepSkippedTargets)).
+		AddLabel("ptype", "external").
+		AddLabel("probe", p.name)
+	p.opts.RecordMetrics(endpoint.Endpoint{}, em, p.dataChan, options.WithNoAlert())
+}
+
 func (p *Probe) runServerProbe(ctx, startCtx context.Context) {
 	outstandingReqs := make(map[int32]requestInfo)
 	var outstandingReqsMu sync.RWMutex
@@ -475,7 +526,12 @@ func (p *Probe) runServerProbe(ctx, startCtx context.Context) {
 	}()
 
 	// Send probe requests
-	for _, target := range p.targets {
+	sweepStart := time.Now()
+	for i, target := range p.targets {
+		if p.sweepOverBudget(sweepStart) {
+			p.recordTruncatedSweep(len(p.targets) - i)
+			break
+		}
 		p.requestID++
 		p.results[target.Key()].total++
 		outstandingReqsMu.Lock()
@@ -503,6 +559,9 @@ func (p *Probe) runServerProbe(ctx, startCtx context.Context) {
 	}
 }
 
+// runOnceProbe runs the command for all the targets concurrently. There is no
+// sweep budget check here: all commands are started right away and are
+// bounded by the probe timeout.
 func (p *Probe) runOnceProbe(ctx context.Context) {
 	var wg sync.WaitGroup
 
@@ -528,10 +587,11 @@ func (p *Probe) runOnceProbe(ctx context.Context) {
 
 			var stdout, stderr []byte
 			var err error
+			envVars := p.cmdEnv(&target)
 			if p.runCommandFunc != nil {
-				stdout, stderr, err = p.runCommandFunc(ctx, p.cmdName, args, p.envVars)
+				stdout, stderr, err = p.runCommandFunc(ctx, p.cmdName, args, envVars)
 			} else {
-				stdout, stderr, err = p.runCommand(ctx, p.cmdName, args, p.envVars)
+				stdout, stderr, err = p.runCommand(ctx, p.cmdName, args, envVars)
 			}
 
 			success := true
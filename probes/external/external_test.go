@@ -103,6 +103,10 @@ func startProbeServer(t *testing.T, testPayload string, r io.Reader, w io.WriteC
 			w.Close()
 			return
 		}
+		if action == "payload_batch" {
+			serverutils.WriteProbeReplies([]*serverpb.ProbeReply{actionToResponse["payload"]}, w)
+			continue
+		}
 		if res, ok := actionToResponse[action]; ok {
 			serverutils.WriteMessage(res, w)
 		}
@@ -244,6 +248,17 @@ func TestProbeServerMode(t *testing.T) {
 		runAndVerifyServerProbe(t, p, "payload", tgts, total, success, 1*2)
 	})
 
+	// Payload in a batch of replies
+	tgts = []string{"target1", "target3"}
+	for _, tgt := range tgts {
+		total[tgt]++
+		success[tgt]++
+	}
+	t.Run("payload_batch", func(t *testing.T) {
+		// 2 targets, 2 EMs per target
+		runAndVerifyServerProbe(t, p, "payload_batch", tgts, total, success, 2*2)
+	})
+
 	// Payload with error
 	tgts = []string{"target2", "target3"}
 	for _, tgt := range tgts {
@@ -315,7 +330,38 @@ func TestProbeServerLocalPipeClose(t *testing.T) {
 	}
 }
 
-func testProbeOnceMode(t *testing.T, cmd string, tgts []string, envVars map[string]string, wantCmd, wantEnv string, wantArgs []string) {
+func TestProbeServerMaxRunDuration(t *testing.T) {
+	p, _, doneChan := testProbeServerSetup(t, nil)
+	defer close(doneChan)
+
+	// Make sure that sweeps run over max_run_duration after the first target.
+	defer func(d time.Duration) { TimeBetweenRequests = d }(TimeBetweenRequests)
+	TimeBetweenRequests = 20 * time.Millisecond
+	p.opts.MaxRunDuration = 10 * time.Millisecond
+
+	setProbeOptions(p, "action", "nopayload")
+	p.opts.Targets = targets.StaticTargets("target1,target2,target3")
+	p.updateTargets()
+
+	for run := int64(1); run <= 2; run++ {
+		p.runProbe(context.Background())
+
+		for i, wantTotal := range []int64{run, 0, 0} {
+			assert.Equal(t, wantTotal, p.results[p.targets[i].Key()].total, "total for %s", p.targets[i].Name)
+		}
+
+		ems, err := testutils.MetricsFromChannel(p.dataChan, 2, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mmap := testutils.MetricsMapByTarget(ems)
+		assert.Equal(t, run, mmap.LastValueInt64("target1", "total"))
+		assert.Equal(t, run, mmap.LastValueInt64("", "sweep_truncated"))
+		assert.Equal(t, 2*run, mmap.LastValueInt64("", "sweep_skipped_targets"))
+	}
+}
+
+func testProbeOnceMode(t *testing.T, cmd string, tgts []string, envVars map[string]string, wantCmd string, wantEnv, wantArgs []string) {
 	t.Helper()
 
 	p := createTestProbe(cmd, envVars)
@@ -372,7 +418,7 @@ func testProbeOnceMode(t *testing.T, cmd string, tgts []string, envVars map[stri
 
 		// Verify values for each output metric
 		assert.Equal(t, "\""+wantArgs[i]+"\"", mmap[tgt]["args"][0].String(), "Wrong value for args metric")
-		assert.Equal(t, "\""+wantEnv+"\"", mmap[tgt]["env"][0].String(), "Wrong value for env metric")
+		assert.Equal(t, "\""+wantEnv[i]+"\"", mmap[tgt]["env"][0].String(), "Wrong value for env metric")
 		assert.Equal(t, "\""+wantCmd+"\"", mmap[tgt]["cmd"][0].String(), "Wrong value for cmd metric")
 	}
 }
@@ -401,7 +447,10 @@ func TestProbeOnceMode(t *testing.T) {
 	for _, tt := range tests {
 		envVars := map[string]string{"key": "secret", "client": "client2"}
 		wantCmd := "/test/cmd"
-		wantEnv := "client=client2 key=secret"
+		var wantEnv []string
+		for _, tgt := range tt.tgts {
+			wantEnv = append(wantEnv, "client=client2 key=secret CLOUDPROBER_PROBE=testProbe CLOUDPROBER_TARGET="+tgt)
+		}
 		t.Run(tt.name, func(t *testing.T) {
 			testProbeOnceMode(t, tt.cmd, tt.tgts, envVars, wantCmd, wantEnv, tt.wantArgs)
 		})
@@ -507,6 +556,40 @@ func TestSendRequest(t *testing.T) {
 	if got, want := opts[0].GetValue(), target; got != target {
 		t.Errorf("opts[0].GetValue() = %q, want %q", got, want)
 	}
+	if got, want := req.GetTarget(), target; got != want {
+		t.Errorf("req.GetTarget() = %q, want %q", got, want)
+	}
+}
+
+func TestCmdEnv(t *testing.T) {
+	p := createTestProbe("./testCommand", map[string]string{
+		"key":               "secret",
+		"CLOUDPROBER_PROBE": "override",
+	})
+
+	assert.Equal(t, []string{"CLOUDPROBER_PROBE=override", "key=secret", "CLOUDPROBER_PROBE=testProbe"}, p.cmdEnv(nil), "server mode")
+	assert.Equal(t, []string{"CLOUDPROBER_PROBE=override", "key=secret", "CLOUDPROBER_PROBE=testProbe", "CLOUDPROBER_TARGET=target1"}, p.cmdEnv(&endpoint.Endpoint{Name: "target1"}), "once mode")
+}
+
+// TestShellProcessEnv is a helper function that we use to test the actual
+// command environment. It prints the probe context variables.
+func TestShellProcessEnv(t *testing.T) {
+	// Ignore this test if it's not being run as a subprocess for another test.
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+	fmt.Printf("probe %s\ntarget %s\n", os.Getenv("CLOUDPROBER_PROBE"), os.Getenv("CLOUDPROBER_TARGET"))
+	os.Exit(0)
+}
+
+func TestRunCommandEnv(t *testing.T) {
+	p := createTestProbe("/testCommand", map[string]string{"GO_TEST_PROCESS": "1"})
+
+	stdout, _, err := p.runCommand(context.Background(), os.Args[0], []string{"-test.run=TestShellProcessEnv"}, p.cmdEnv(&endpoint.Endpoint{Name: "target1"}))
+	if err != nil {
+		t.Fatalf("runCommand() error: %v", err)
+	}
+	assert.Equal(t, "probe testProbe\ntarget target1\n", string(stdout))
 }
 
 func TestUpdateTargets(t *testing.T) {
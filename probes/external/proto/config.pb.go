@@ -96,7 +96,16 @@ type ProbeConf struct {
 	// will get converted to: /tools/recreate_vm -vm ig-us-central1-a
 	Command *string `protobuf:"bytes,2,req,name=command" json:"command,omitempty"`
 	// Command environment variables. These are passed on to the external probe
-	// process as environment variables.
+	// process as environment variables, in addition to cloudprober's own
+	// environment.
+	//
+	// Cloudprober also sets the following variables for the external probe
+	// process. These take precedence over the variables configured here:
+	// CLOUDPROBER_PROBE   Name of the probe
+	// CLOUDPROBER_TARGET  Name of the target (ONCE mode only). In SERVER mode,
+	//
+	//	one process serves all the targets, and target name
+	//	is sent as part of each ProbeRequest.
 	EnvVar  map[string]string   `protobuf:"bytes,6,rep,name=env_var,json=envVar" json:"env_var,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	Options []*ProbeConf_Option `protobuf:"bytes,3,rep,name=options" json:"options,omitempty"`
 	// Export output as metrics, where output is the output returned by the
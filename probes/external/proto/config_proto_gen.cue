@@ -26,7 +26,15 @@ import "github.com/cloudprober/cloudprober/metrics/payload/proto"
 	command?: string @protobuf(2,string)
 
 	// Command environment variables. These are passed on to the external probe
-	// process as environment variables.
+	// process as environment variables, in addition to cloudprober's own
+	// environment.
+	//
+	// Cloudprober also sets the following variables for the external probe
+	// process. These take precedence over the variables configured here:
+	// CLOUDPROBER_PROBE   Name of the probe
+	// CLOUDPROBER_TARGET  Name of the target (ONCE mode only). In SERVER mode,
+	//                     one process serves all the targets, and target name
+	//                     is sent as part of each ProbeRequest.
 	envVar?: {
 		[string]: string
 	} @protobuf(6,map[string]string,env_var)
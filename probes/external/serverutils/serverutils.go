@@ -29,40 +29,57 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-func readPayload(r *bufio.Reader) ([]byte, error) {
-	// header format is: "\nContent-Length: %d\n\n"
-	const prefix = "Content-Length: "
-	var line string
+// Message headers. A single message is sent with the Content-Length header,
+// while a batch of probe replies (ProbeReplyBatch) is sent with the
+// Batch-Length header.
+const (
+	contentLengthHeader = "Content-Length: "
+	batchLengthHeader   = "Batch-Length: "
+)
+
+// readFrame reads a message with one of the given headers, and returns the
+// header and the message payload.
+func readFrame(r *bufio.Reader, headers ...string) (string, []byte, error) {
+	// header format is: "\n<header>%d\n\n"
+	var line, header string
 	var length int
 	var err error
 
 	// Read lines until header line is found
-	for {
+	for header == "" {
 		line, err = r.ReadString('\n')
 		if err != nil {
-			return nil, err
+			return "", nil, err
 		}
-		if strings.HasPrefix(line, prefix) {
-			break
+		for _, h := range headers {
+			if strings.HasPrefix(line, h) {
+				header = h
+				break
+			}
 		}
 	}
 
 	// Parse content length from the header
-	length, err = strconv.Atoi(line[len(prefix) : len(line)-1])
+	length, err = strconv.Atoi(line[len(header) : len(line)-1])
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 	// Consume the blank line following the header line
 	if _, err = r.ReadSlice('\n'); err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
 	// Slurp in the payload
 	buf := make([]byte, length)
 	if _, err = io.ReadFull(r, buf); err != nil {
-		return nil, err
+		return "", nil, err
 	}
-	return buf, nil
+	return header, buf, nil
+}
+
+func readPayload(r *bufio.Reader) ([]byte, error) {
+	_, buf, err := readFrame(r, contentLengthHeader)
+	return buf, err
 }
 
 // ReadProbeReply reads ProbeReply from the supplied bufio.Reader and returns it to
@@ -76,6 +93,27 @@ func ReadProbeReply(r *bufio.Reader) (*serverpb.ProbeReply, error) {
 	return rep, proto.Unmarshal(buf, rep)
 }
 
+// ReadProbeReplies reads the next ProbeReply, or the next batch of probe
+// replies, from the supplied bufio.Reader and returns them to the caller.
+func ReadProbeReplies(r *bufio.Reader) ([]*serverpb.ProbeReply, error) {
+	header, buf, err := readFrame(r, contentLengthHeader, batchLengthHeader)
+	if err != nil {
+		return nil, err
+	}
+	if header == batchLengthHeader {
+		batch := new(serverpb.ProbeReplyBatch)
+		if err := proto.Unmarshal(buf, batch); err != nil {
+			return nil, err
+		}
+		return batch.GetReply(), nil
+	}
+	rep := new(serverpb.ProbeReply)
+	if err := proto.Unmarshal(buf, rep); err != nil {
+		return nil, err
+	}
+	return []*serverpb.ProbeReply{rep}, nil
+}
+
 // ReadProbeRequest reads and parses ProbeRequest protocol buffers from the given
 // bufio.Reader.
 func ReadProbeRequest(r *bufio.Reader) (*serverpb.ProbeRequest, error) {
@@ -87,19 +125,29 @@ func ReadProbeRequest(r *bufio.Reader) (*serverpb.ProbeRequest, error) {
 	return req, proto.Unmarshal(buf, req)
 }
 
-// WriteMessage marshals the a proto message and writes it to the writer "w"
-// with appropriate Content-Length header.
-func WriteMessage(pb proto.Message, w io.Writer) error {
+func writeFrame(header string, pb proto.Message, w io.Writer) error {
 	buf, err := proto.Marshal(pb)
 	if err != nil {
 		return fmt.Errorf("Failed marshalling proto message: %v", err)
 	}
-	if _, err := fmt.Fprintf(w, "\nContent-Length: %d\n\n%s", len(buf), buf); err != nil {
+	if _, err := fmt.Fprintf(w, "\n%s%d\n\n%s", header, len(buf), buf); err != nil {
 		return fmt.Errorf("Failed writing response: %v", err)
 	}
 	return nil
 }
 
+// WriteMessage marshals the a proto message and writes it to the writer "w"
+// with appropriate Content-Length header.
+func WriteMessage(pb proto.Message, w io.Writer) error {
+	return writeFrame(contentLengthHeader, pb, w)
+}
+
+// WriteProbeReplies writes a batch of probe replies to the writer "w", with
+// the Batch-Length header. Cloudprober reads these replies as a unit.
+func WriteProbeReplies(replies []*serverpb.ProbeReply, w io.Writer) error {
+	return writeFrame(batchLengthHeader, &serverpb.ProbeReplyBatch{Reply: replies}, w)
+}
+
 // Serve blocks indefinitely, servicing probe requests. Note that this function is
 // provided mainly to help external probe server implementations. Cloudprober doesn't
 // make use of it. Example usage:
@@ -119,19 +167,75 @@ func WriteMessage(pb proto.Message, w io.Writer) error {
 //			}
 //		})
 func Serve(probeFunc func(*serverpb.ProbeRequest, *serverpb.ProbeReply)) {
-	stdin := bufio.NewReader(os.Stdin)
-
-	repliesChan := make(chan *serverpb.ProbeReply)
-
-	// Write replies to stdout. These are not required to be in-order.
-	go func() {
+	serve(probeFunc, func(repliesChan <-chan *serverpb.ProbeReply) {
 		for rep := range repliesChan {
 			if err := WriteMessage(rep, os.Stdout); err != nil {
 				log.Fatal(err)
 			}
+		}
+	})
+}
+
+// ServeWithBatching is like Serve, but it writes the probe replies in
+// batches, reducing the per-reply overhead for high-frequency probes. A batch
+// is written once it has maxBatchSize replies, or maxDelay after its first
+// reply, whichever comes first.
+func ServeWithBatching(probeFunc func(*serverpb.ProbeRequest, *serverpb.ProbeReply), maxBatchSize int, maxDelay time.Duration) {
+	serve(probeFunc, func(repliesChan <-chan *serverpb.ProbeReply) {
+		if err := writeBatches(repliesChan, os.Stdout, maxBatchSize, maxDelay); err != nil {
+			log.Fatal(err)
+		}
+	})
+}
+
+// writeBatches writes replies from repliesChan to the writer "w" in batches,
+// until repliesChan is closed.
+func writeBatches(repliesChan <-chan *serverpb.ProbeReply, w io.Writer, maxBatchSize int, maxDelay time.Duration) error {
+	var batch []*serverpb.ProbeReply
+	var timer <-chan time.Time
+
+	flush := func() error {
+		timer = nil
+		if len(batch) == 0 {
+			return nil
+		}
+		err := WriteProbeReplies(batch, w)
+		batch = nil
+		return err
+	}
 
+	for {
+		select {
+		case rep, ok := <-repliesChan:
+			if !ok {
+				return flush()
+			}
+			batch = append(batch, rep)
+			if len(batch) == 1 {
+				timer = time.After(maxDelay)
+			}
+			if len(batch) >= maxBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		case <-timer:
+			if err := flush(); err != nil {
+				return err
+			}
 		}
-	}()
+	}
+}
+
+// serve services probe requests, using writeReplies to write the probe
+// replies to stdout.
+func serve(probeFunc func(*serverpb.ProbeRequest, *serverpb.ProbeReply), writeReplies func(<-chan *serverpb.ProbeReply)) {
+	stdin := bufio.NewReader(os.Stdin)
+
+	repliesChan := make(chan *serverpb.ProbeReply)
+
+	// Write replies to stdout. These are not required to be in-order.
+	go writeReplies(repliesChan)
 
 	// Read requests from stdin, and dispatch probes to service them.
 	for {
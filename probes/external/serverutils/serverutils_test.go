@@ -0,0 +1,79 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serverutils
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	serverpb "github.com/cloudprober/cloudprober/probes/external/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func testReply(id int32) *serverpb.ProbeReply {
+	return &serverpb.ProbeReply{RequestId: proto.Int32(id), Payload: proto.String("op_latency 1.2")}
+}
+
+func readAllReplies(t *testing.T, r io.Reader) [][]int32 {
+	t.Helper()
+
+	var ids [][]int32
+	br := bufio.NewReader(r)
+	for {
+		replies, err := ReadProbeReplies(br)
+		if err == io.EOF {
+			return ids
+		}
+		assert.NoError(t, err)
+		var batchIDs []int32
+		for _, rep := range replies {
+			assert.Equal(t, "op_latency 1.2", rep.GetPayload())
+			batchIDs = append(batchIDs, rep.GetRequestId())
+		}
+		ids = append(ids, batchIDs)
+	}
+}
+
+func TestReadProbeReplies(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteMessage(testReply(1), &buf))
+	assert.NoError(t, WriteProbeReplies([]*serverpb.ProbeReply{testReply(2), testReply(3)}, &buf))
+	assert.NoError(t, WriteMessage(testReply(4), &buf))
+
+	assert.Equal(t, [][]int32{{1}, {2, 3}, {4}}, readAllReplies(t, &buf))
+}
+
+func TestWriteBatches(t *testing.T) {
+	var buf bytes.Buffer
+	repliesChan := make(chan *serverpb.ProbeReply)
+	errCh := make(chan error)
+	go func() { errCh <- writeBatches(repliesChan, &buf, 2, 50*time.Millisecond) }()
+
+	// First batch is written once it's full, second one after the delay, and
+	// the last one when the channel is closed.
+	for i := int32(1); i <= 3; i++ {
+		repliesChan <- testReply(i)
+	}
+	time.Sleep(100 * time.Millisecond)
+	repliesChan <- testReply(4)
+	close(repliesChan)
+	assert.NoError(t, <-errCh)
+
+	assert.Equal(t, [][]int32{{1, 2}, {3}, {4}}, readAllReplies(t, &buf))
+}
@@ -26,6 +26,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -51,6 +52,7 @@ import (
 	"github.com/fullstorydev/grpcurl"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/alts"
 	"google.golang.org/grpc/credentials/insecure"
@@ -60,6 +62,7 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/status"
 
 	// Import grpclb module so it can be used by name for DirectPath connections.
 	_ "google.golang.org/grpc/balancer/grpclb"
@@ -67,6 +70,21 @@ import (
 
 const loadBalancingPolicy = `{"loadBalancingConfig":[{"grpclb":{"childPolicy":[{"pick_first":{}}]}}]}`
 
+// errRequestTimeout is the cause of the request context cancellation when the
+// probe timeout is reached.
+var errRequestTimeout = errors.New("probe timeout")
+
+// statusCodeNames are the canonical names of the gRPC status codes, indexed by
+// the code. These names are used in the config and in the exported metrics.
+var statusCodeNames = []string{"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED", "NOT_FOUND", "ALREADY_EXISTS", "PERMISSION_DENIED", "RESOURCE_EXHAUSTED", "FAILED_PRECONDITION", "ABORTED", "OUT_OF_RANGE", "UNIMPLEMENTED", "INTERNAL", "UNAVAILABLE", "DATA_LOSS", "UNAUTHENTICATED"}
+
+func statusCodeName(code codes.Code) string {
+	if int(code) < len(statusCodeNames) {
+		return statusCodeNames[code]
+	}
+	return code.String()
+}
+
 // TargetsUpdateInterval controls frequency of target updates.
 var (
 	TargetsUpdateInterval = 1 * time.Minute
@@ -90,6 +108,9 @@ type Probe struct {
 	// Results by target.
 	results map[string]*probeRunResult
 
+	// Status codes that count as success.
+	expectedCodes map[codes.Code]bool
+
 	// This is used only for testing.
 	healthCheckFunc func() (*grpc_health_v1.HealthCheckResponse, error)
 }
@@ -104,6 +125,9 @@ type probeRunResult struct {
 	success           metrics.Int
 	latency           metrics.LatencyValue
 	connectErrors     metrics.Int
+	timeouts          metrics.Int
+	serverDeadlines   metrics.Int
+	statusCodes       *metrics.Map[int64]
 	validationFailure *metrics.Map[int64]
 }
 
@@ -192,9 +216,33 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 		}
 	}
 
+	p.expectedCodes = map[codes.Code]bool{codes.OK: true}
+	if len(p.c.GetExpectedStatusCodes()) > 0 {
+		p.expectedCodes = make(map[codes.Code]bool)
+		for _, s := range p.c.GetExpectedStatusCodes() {
+			code := slices.Index(statusCodeNames, s)
+			if code == -1 {
+				return fmt.Errorf("invalid expected_status_codes (%s): unknown gRPC status code", s)
+			}
+			p.expectedCodes[codes.Code(code)] = true
+		}
+	}
+
 	return nil
 }
 
+// expectedStatus returns true if the request's status matches the expected
+// status codes and message.
+func (p *Probe) expectedStatus(st *status.Status) bool {
+	if !p.expectedCodes[st.Code()] {
+		return false
+	}
+	if msg := p.c.GetExpectedStatusMessage(); msg != "" && !strings.Contains(st.Message(), msg) {
+		return false
+	}
+	return true
+}
+
 func (p *Probe) updateTargetsAndStartProbes(ctx context.Context) {
 	newTargets := p.opts.Targets.ListEndpoints()
 	numNewTargets := len(newTargets)
@@ -277,6 +325,14 @@ func (p *Probe) connectWithRetry(ctx context.Context, target endpoint.Endpoint,
 			return nil
 		default:
 		}
+
+		// gRPC dials in the background, so we apply the connection rate limit
+		// before creating the client connection, and before starting the
+		// connect timeout.
+		if err := probeutils.WaitForConnRateLimit(ctx); err != nil {
+			p.l.WarningAttrs(err.Error(), logAttrs...)
+			continue
+		}
 		connCtx, cancelFunc := context.WithTimeout(ctx, connectTimeout)
 
 		if uriScheme := p.c.GetUriScheme(); uriScheme != "" {
@@ -358,7 +414,7 @@ func (p *Probe) oneTargetLoop(ctx context.Context, tgt endpoint.Endpoint, index
 		case <-ticker.C:
 		}
 
-		reqCtx, cancelFunc := context.WithTimeout(ctx, timeout)
+		reqCtx, cancelFunc := p.requestContext(ctx, timeout)
 
 		reqCtx = p.ctxWithHeaders(reqCtx)
 
@@ -390,22 +446,28 @@ func (p *Probe) oneTargetLoop(ctx context.Context, tgt endpoint.Endpoint, index
 			p.l.Criticalf("Method %v not implemented", method)
 		}
 
+		timedOut := errors.Is(context.Cause(reqCtx), errRequestTimeout)
 		cancelFunc()
 
 		p.l.DebugAttrs("Response: "+r.String(), logAttrs...)
 
-		if err != nil {
+		st := status.Convert(err)
+		if !p.expectedStatus(st) {
 			peerAddr := "unknown"
 			if peer.Addr != nil {
 				peerAddr = peer.Addr.String()
 			}
-			p.l.WarningAttrs(fmt.Sprintf("Request failed: %v. ConnState: %v", err, conn.GetState()), append(logAttrs, slog.String("peer", peerAddr))...)
+			if err != nil {
+				p.l.WarningAttrs(fmt.Sprintf("Request failed: %v. ConnState: %v", err, conn.GetState()), append(logAttrs, slog.String("peer", peerAddr))...)
+			} else {
+				p.l.WarningAttrs("Request failed: unexpected status: OK", append(logAttrs, slog.String("peer", peerAddr))...)
+			}
 		} else {
 			success = true
 			delta = time.Since(start)
 		}
 
-		if p.opts.Validators != nil {
+		if p.opts.Validators != nil && err == nil {
 			failedValidations := validators.RunValidators(p.opts.Validators, &validators.Input{ResponseBody: []byte(r.String())}, result.validationFailure, p.l)
 
 			if len(failedValidations) > 0 {
@@ -416,14 +478,42 @@ func (p *Probe) oneTargetLoop(ctx context.Context, tgt endpoint.Endpoint, index
 
 		result.Lock()
 		result.total.Inc()
+		if result.statusCodes != nil {
+			result.statusCodes.IncKey(statusCodeName(st.Code()))
+		}
 		if success {
 			result.success.Inc()
+		} else if err != nil {
+			// Server may return DEADLINE_EXCEEDED before our timeout, e.g.
+			// if it reserves some time for itself.
+			if timedOut {
+				result.timeouts.Inc()
+			} else if status.Code(err) == codes.DeadlineExceeded {
+				result.serverDeadlines.Inc()
+			}
 		}
 		result.latency.AddFloat64(delta.Seconds() / p.opts.LatencyUnit.Seconds())
 		result.Unlock()
 	}
 }
 
+// requestContext returns the context for a request, which is canceled with
+// the errRequestTimeout cause after the timeout. If propagate_deadline is
+// enabled, timeout is set as the context deadline, and hence sent to the
+// server as the RPC deadline.
+func (p *Probe) requestContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if p.c.GetPropagateDeadline() {
+		return context.WithTimeoutCause(ctx, timeout, errRequestTimeout)
+	}
+
+	reqCtx, cancel := context.WithCancelCause(ctx)
+	timer := time.AfterFunc(timeout, func() { cancel(errRequestTimeout) })
+	return reqCtx, func() {
+		timer.Stop()
+		cancel(context.Canceled)
+	}
+}
+
 func (p *Probe) newResult(tgt string) *probeRunResult {
 	var latencyValue metrics.LatencyValue
 	if p.opts.LatencyDist != nil {
@@ -434,9 +524,15 @@ func (p *Probe) newResult(tgt string) *probeRunResult {
 
 	validationFailure := validators.ValidationFailureMap(p.opts.Validators)
 
+	var statusCodes *metrics.Map[int64]
+	if p.c.GetExportStatusCodeMetric() {
+		statusCodes = metrics.NewMap("code")
+	}
+
 	return &probeRunResult{
 		target:            tgt,
 		latency:           latencyValue,
+		statusCodes:       statusCodes,
 		validationFailure: validationFailure,
 	}
 }
@@ -488,11 +584,16 @@ func (p *Probe) Start(ctx context.Context, dataChan chan *metrics.EventMetrics)
 				AddMetric("success", result.success.Clone()).
 				AddMetric(p.opts.LatencyMetricName, result.latency.Clone()).
 				AddMetric("connecterrors", result.connectErrors.Clone()).
+				AddMetric("timeouts", result.timeouts.Clone()).
+				AddMetric("server_deadline_exceeded", result.serverDeadlines.Clone()).
 				AddLabel("ptype", "grpc").
 				AddLabel("probe", p.name).
 				AddLabel("dst", target.Dst())
 			result.Unlock()
 
+			if result.statusCodes != nil {
+				em.AddMetric("status_code", result.statusCodes.Clone())
+			}
 			if result.validationFailure != nil {
 				em.AddMetric("validation_failure", result.validationFailure)
 			}
@@ -42,8 +42,10 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -219,6 +221,7 @@ func TestGRPCSuccess(t *testing.T) {
 				expectedMinCount := int64((i + 1) * (iters + 1))
 				assert.GreaterOrEqual(t, em.Metric("total").(*metrics.Int).Int64(), expectedMinCount, "message#: %d, total, em: %s", i, em.String())
 				assert.GreaterOrEqual(t, em.Metric("success").(*metrics.Int).Int64(), expectedMinCount, "message#: %d, success, em: %s", i, em.String())
+				assert.Nil(t, em.Metric("status_code"), "status_code metric should be exported only if enabled, em: %s", em.String())
 				gotLabels := make(map[string]string)
 				for _, k := range em.LabelsKeys() {
 					gotLabels[k] = em.Label(k)
@@ -331,12 +334,118 @@ func TestProbeTimeouts(t *testing.T) {
 		assert.GreaterOrEqual(t, em.Metric("total").(*metrics.Int).Int64(), expectedMinCount, "message#: %d, total, em: %s", i, em.String())
 		// 0 success
 		assert.Equal(t, int64(0), em.Metric("success").(*metrics.Int).Int64(), "message#: %d, success, em: %s", i, em.String())
+		// All failures are client side timeouts.
+		assert.Equal(t, em.Metric("total").(*metrics.Int).Int64(), em.Metric("timeouts").(*metrics.Int).Int64(), "message#: %d, timeouts, em: %s", i, em.String())
+		assert.Equal(t, int64(0), em.Metric("server_deadline_exceeded").(*metrics.Int).Int64(), "message#: %d, server_deadline_exceeded, em: %s", i, em.String())
 	}
 
 	cancel()
 	wg.Wait()
 }
 
+// deadlineServer returns DEADLINE_EXCEEDED for all Echo requests, and records
+// if the requests had a deadline.
+type deadlineServer struct {
+	mu          sync.Mutex
+	hadDeadline []bool
+
+	spb.UnimplementedProberServer
+}
+
+func (s *deadlineServer) Echo(ctx context.Context, req *pb.EchoMessage) (*pb.EchoMessage, error) {
+	_, ok := ctx.Deadline()
+	s.mu.Lock()
+	s.hadDeadline = append(s.hadDeadline, ok)
+	s.mu.Unlock()
+	return nil, status.Error(codes.DeadlineExceeded, "not enough time left")
+}
+
+func TestServerDeadlineExceeded(t *testing.T) {
+	for _, propagate := range []bool{true, false} {
+		t.Run(fmt.Sprintf("propagate_deadline=%v", propagate), func(t *testing.T) {
+			ln, err := net.Listen("tcp", "localhost:0")
+			if err != nil {
+				t.Fatalf("Error starting listener: %v", err)
+			}
+			srv := &deadlineServer{}
+			grpcSrv := grpc.NewServer()
+			spb.RegisterProberServer(grpcSrv, srv)
+			go grpcSrv.Serve(ln)
+			defer grpcSrv.Stop()
+
+			interval := 100 * time.Millisecond
+			statsExportInterval := 5 * interval
+			p := &Probe{}
+			if err := p.Init("grpc-deadline", &options.Options{
+				Targets:  targets.StaticTargets(ln.Addr().String()),
+				Interval: interval,
+				Timeout:  time.Second,
+				ProbeConf: &configpb.ProbeConf{
+					NumConns:          proto.Int32(1),
+					InsecureTransport: proto.Bool(true),
+					PropagateDeadline: proto.Bool(propagate),
+				},
+				Logger:              &logger.Logger{},
+				LatencyUnit:         time.Millisecond,
+				StatsExportInterval: statsExportInterval,
+				LogMetrics:          func(em *metrics.EventMetrics) {},
+			}); err != nil {
+				t.Fatalf("Error initializing probe: %v", err)
+			}
+			dataChan := make(chan *metrics.EventMetrics, 5)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p.Start(ctx, dataChan)
+			}()
+
+			ems, err := testutils.MetricsFromChannel(dataChan, 1, 3*statsExportInterval)
+			cancel()
+			wg.Wait()
+			if err != nil || len(ems) != 1 {
+				t.Fatalf("Err: %v", err)
+			}
+
+			em := ems[0]
+			total := em.Metric("total").(*metrics.Int).Int64()
+			assert.Greater(t, total, int64(0), "total, em: %s", em.String())
+			assert.Equal(t, int64(0), em.Metric("success").(*metrics.Int).Int64(), "success, em: %s", em.String())
+			assert.Equal(t, int64(0), em.Metric("timeouts").(*metrics.Int).Int64(), "timeouts, em: %s", em.String())
+			assert.Equal(t, total, em.Metric("server_deadline_exceeded").(*metrics.Int).Int64(), "server_deadline_exceeded, em: %s", em.String())
+
+			srv.mu.Lock()
+			defer srv.mu.Unlock()
+			for _, hadDeadline := range srv.hadDeadline {
+				assert.Equal(t, propagate, hadDeadline, "request had deadline")
+			}
+		})
+	}
+}
+
+func TestRequestContext(t *testing.T) {
+	for _, propagate := range []bool{true, false} {
+		t.Run(fmt.Sprintf("propagate_deadline=%v", propagate), func(t *testing.T) {
+			p := &Probe{c: &configpb.ProbeConf{PropagateDeadline: proto.Bool(propagate)}}
+
+			ctx, cancel := p.requestContext(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			_, hasDeadline := ctx.Deadline()
+			assert.Equal(t, propagate, hasDeadline, "has deadline")
+
+			<-ctx.Done()
+			assert.ErrorIs(t, context.Cause(ctx), errRequestTimeout)
+
+			// Canceling ourselves is not a timeout.
+			ctx, cancel = p.requestContext(context.Background(), time.Minute)
+			cancel()
+			assert.NotErrorIs(t, context.Cause(ctx), errRequestTimeout)
+		})
+	}
+}
+
 type testTargets struct {
 	r *resolver.Resolver
 
@@ -584,3 +693,116 @@ func TestTransportCreds(t *testing.T) {
 		})
 	}
 }
+
+// notFoundServer returns NOT_FOUND for all Echo requests.
+type notFoundServer struct {
+	spb.UnimplementedProberServer
+}
+
+func (s *notFoundServer) Echo(ctx context.Context, req *pb.EchoMessage) (*pb.EchoMessage, error) {
+	return nil, status.Error(codes.NotFound, "no such item")
+}
+
+func TestExpectedStatusCodes(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Error starting listener: %v", err)
+	}
+	grpcSrv := grpc.NewServer()
+	spb.RegisterProberServer(grpcSrv, &notFoundServer{})
+	go grpcSrv.Serve(ln)
+	defer grpcSrv.Stop()
+
+	tests := []struct {
+		name          string
+		expectedCodes []string
+		expectedMsg   string
+		wantSuccess   bool
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:          "not_found",
+			expectedCodes: []string{"OK", "NOT_FOUND"},
+			wantSuccess:   true,
+		},
+		{
+			name:          "not_found_with_message",
+			expectedCodes: []string{"NOT_FOUND"},
+			expectedMsg:   "no such",
+			wantSuccess:   true,
+		},
+		{
+			name:          "not_found_message_mismatch",
+			expectedCodes: []string{"NOT_FOUND"},
+			expectedMsg:   "permission",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			interval := 100 * time.Millisecond
+			statsExportInterval := 5 * interval
+			p := &Probe{}
+			if err := p.Init("grpc-status", &options.Options{
+				Targets:  targets.StaticTargets(ln.Addr().String()),
+				Interval: interval,
+				Timeout:  time.Second,
+				ProbeConf: &configpb.ProbeConf{
+					NumConns:               proto.Int32(1),
+					InsecureTransport:      proto.Bool(true),
+					ExpectedStatusCodes:    test.expectedCodes,
+					ExpectedStatusMessage:  proto.String(test.expectedMsg),
+					ExportStatusCodeMetric: proto.Bool(true),
+				},
+				Logger:              &logger.Logger{},
+				LatencyUnit:         time.Millisecond,
+				StatsExportInterval: statsExportInterval,
+				LogMetrics:          func(em *metrics.EventMetrics) {},
+			}); err != nil {
+				t.Fatalf("Error initializing probe: %v", err)
+			}
+			dataChan := make(chan *metrics.EventMetrics, 5)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p.Start(ctx, dataChan)
+			}()
+
+			ems, err := testutils.MetricsFromChannel(dataChan, 1, 3*statsExportInterval)
+			cancel()
+			wg.Wait()
+			if err != nil || len(ems) != 1 {
+				t.Fatalf("Err: %v", err)
+			}
+
+			em := ems[0]
+			total := em.Metric("total").(*metrics.Int).Int64()
+			assert.Greater(t, total, int64(0), "total, em: %s", em.String())
+			wantSuccess := int64(0)
+			if test.wantSuccess {
+				wantSuccess = total
+			}
+			assert.Equal(t, wantSuccess, em.Metric("success").(*metrics.Int).Int64(), "success, em: %s", em.String())
+
+			statusCodes := em.Metric("status_code").(*metrics.Map[int64])
+			assert.Equal(t, []string{"NOT_FOUND"}, statusCodes.Keys())
+			assert.Equal(t, total, statusCodes.GetKey("NOT_FOUND"))
+		})
+	}
+}
+
+func TestInitExpectedStatusCodes(t *testing.T) {
+	p := &Probe{}
+	err := p.Init("grpc-status", &options.Options{
+		Targets: targets.StaticTargets("localhost:9313"),
+		ProbeConf: &configpb.ProbeConf{
+			ExpectedStatusCodes: []string{"NOT_FOUND", "NOT_A_CODE"},
+		},
+	})
+	assert.ErrorContains(t, err, "invalid expected_status_codes (NOT_A_CODE)")
+}
@@ -32,7 +32,7 @@ import (
 	body?: string @protobuf(6,string)
 }
 
-// Next tag: 14
+// Next tag: 19
 #ProbeConf: {
 	// Optional oauth config. For GOOGLE_DEFAULT_CREDENTIALS, use:
 	// oauth_config: { bearer_token { gce_service_account: "default" } }
@@ -114,4 +114,27 @@ import (
 		value?: string @protobuf(2,string)
 	}
 	headers?: [...#Header] @protobuf(13,Header)
+
+	// Propagate the probe timeout to the server as the RPC deadline (sent in the
+	// grpc-timeout header), so that the server can give up early. If disabled,
+	// RPCs are still canceled by the probe after the timeout, but no deadline
+	// is sent to the server.
+	// Client side timeouts are exported as the "timeouts" metric, while the
+	// DEADLINE_EXCEEDED errors returned by the server before the timeout are
+	// exported as the "server_deadline_exceeded" metric.
+	propagateDeadline?: bool @protobuf(15,bool,name=propagate_deadline,default)
+
+	// gRPC status codes that count as success, e.g. "NOT_FOUND" for a negative
+	// test. Codes are specified by their names, as defined in
+	// https://grpc.github.io/grpc/core/md_doc_statuscodes.html. Default is OK.
+	expectedStatusCodes?: [...string] @protobuf(16,string,name=expected_status_codes)
+
+	// If set, status message should also contain this string for the request
+	// to count as success. It's mostly useful with expected_status_codes, as
+	// the OK status doesn't carry a message.
+	expectedStatusMessage?: string @protobuf(17,string,name=expected_status_message)
+
+	// Export the returned status codes as the "status_code" metric, e.g.
+	// status_code{code="NOT_FOUND"}.
+	exportStatusCodeMetric?: bool @protobuf(18,bool,name=export_status_code_metric,"default=false")
 }
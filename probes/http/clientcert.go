@@ -0,0 +1,133 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"crypto/tls"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/cloudprober/cloudprober/internal/file"
+	configpb "github.com/cloudprober/cloudprober/probes/http/proto"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+)
+
+// clientCert is a client certificate that is reloaded when the cert or key
+// file changes.
+type clientCert struct {
+	name              string
+	certFile, keyFile string
+	targetNameRe      *regexp.Regexp
+
+	mu          sync.Mutex
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+func newClientCert(c *configpb.ProbeConf_ClientCert) (*clientCert, error) {
+	cc := &clientCert{
+		name:     c.GetName(),
+		certFile: c.GetTlsCertFile(),
+		keyFile:  c.GetTlsKeyFile(),
+	}
+	if c.GetTargetNameRegex() != "" {
+		re, err := regexp.Compile(c.GetTargetNameRegex())
+		if err != nil {
+			return nil, fmt.Errorf("invalid target_name_regex (%s) for client_cert %s: %v", c.GetTargetNameRegex(), cc.name, err)
+		}
+		cc.targetNameRe = re
+	}
+	return cc, nil
+}
+
+// getCert returns the certificate, (re)loading it if it has not been loaded
+// yet, or if the cert or key file has changed since the last load.
+func (cc *clientCert) getCert() (*tls.Certificate, error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	certModTime, certErr := file.ModTime(cc.certFile)
+	keyModTime, keyErr := file.ModTime(cc.keyFile)
+	// If we can't get the modification time, we use the cached cert.
+	changed := certErr == nil && keyErr == nil && (!certModTime.Equal(cc.certModTime) || !keyModTime.Equal(cc.keyModTime))
+
+	if cc.cert != nil && !changed {
+		return cc.cert, nil
+	}
+
+	certPEMBlock, err := file.ReadFile(cc.certFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading client cert file (%s): %v", cc.certFile, err)
+	}
+	keyPEMBlock, err := file.ReadFile(cc.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading client key file (%s): %v", cc.keyFile, err)
+	}
+	cert, err := tls.X509KeyPair(certPEMBlock, keyPEMBlock)
+	if err != nil {
+		return nil, fmt.Errorf("error loading client cert %s: %v", cc.name, err)
+	}
+
+	cc.cert, cc.certModTime, cc.keyModTime = &cert, certModTime, keyModTime
+	return cc.cert, nil
+}
+
+func (p *Probe) initClientCerts() error {
+	for _, c := range p.c.GetClientCert() {
+		cc, err := newClientCert(c)
+		if err != nil {
+			return err
+		}
+		for _, existing := range p.clientCerts {
+			if existing.name == cc.name {
+				return fmt.Errorf("duplicate client_cert name: %s", cc.name)
+			}
+		}
+		// Verify early that we can load the certificate.
+		if _, err := cc.getCert(); err != nil {
+			return err
+		}
+		p.clientCerts = append(p.clientCerts, cc)
+	}
+	return nil
+}
+
+// clientCertForTarget returns the client certificate to use for the target,
+// or nil if there is no per-target client certificate for it.
+func (p *Probe) clientCertForTarget(target endpoint.Endpoint) *clientCert {
+	if len(p.clientCerts) == 0 {
+		return nil
+	}
+
+	if name, ok := target.Labels[p.c.GetClientCertLabel()]; ok {
+		for _, cc := range p.clientCerts {
+			if cc.name == name {
+				return cc
+			}
+		}
+		p.l.Warningf("Target %s: client_cert %s not found", target.Name, name)
+		return nil
+	}
+
+	for _, cc := range p.clientCerts {
+		if cc.targetNameRe != nil && cc.targetNameRe.MatchString(target.Name) {
+			return cc
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/logger"
+	configpb "github.com/cloudprober/cloudprober/probes/http/proto"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// writeTestCert writes a self-signed cert with the given common name, and its
+// key, to the given files.
+func writeTestCert(t *testing.T, certFile, keyFile, cn string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Error creating certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Error marshaling key: %v", err)
+	}
+
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644))
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+}
+
+func certCN(t *testing.T, cert *tls.Certificate) string {
+	t.Helper()
+	c, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("Error parsing certificate: %v", err)
+	}
+	return c.Subject.CommonName
+}
+
+func TestClientCertReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "a.crt"), filepath.Join(dir, "a.key")
+	writeTestCert(t, certFile, keyFile, "cert1")
+
+	cc, err := newClientCert(&configpb.ProbeConf_ClientCert{
+		Name:        proto.String("a"),
+		TlsCertFile: proto.String(certFile),
+		TlsKeyFile:  proto.String(keyFile),
+	})
+	assert.NoError(t, err)
+
+	cert, err := cc.getCert()
+	assert.NoError(t, err)
+	assert.Equal(t, "cert1", certCN(t, cert))
+
+	// Unchanged files, cached cert.
+	cert2, err := cc.getCert()
+	assert.NoError(t, err)
+	assert.Same(t, cert, cert2)
+
+	// Update files and make sure modification time changes.
+	writeTestCert(t, certFile, keyFile, "cert2")
+	future := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(certFile, future, future))
+	assert.NoError(t, os.Chtimes(keyFile, future, future))
+
+	cert, err = cc.getCert()
+	assert.NoError(t, err)
+	assert.Equal(t, "cert2", certCN(t, cert))
+}
+
+func TestClientCertForTarget(t *testing.T) {
+	dir := t.TempDir()
+	var certConfs []*configpb.ProbeConf_ClientCert
+	for _, name := range []string{"team-a", "team-b"} {
+		certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+		writeTestCert(t, certFile, keyFile, name)
+		certConfs = append(certConfs, &configpb.ProbeConf_ClientCert{
+			Name:            proto.String(name),
+			TlsCertFile:     proto.String(certFile),
+			TlsKeyFile:      proto.String(keyFile),
+			TargetNameRegex: proto.String(`.*\.` + name + `\.svc`),
+		})
+	}
+
+	p := &Probe{
+		c: &configpb.ProbeConf{
+			ClientCert:       certConfs,
+			RequestsPerProbe: proto.Int32(2),
+		},
+		l:             &logger.Logger{},
+		baseTransport: http.DefaultTransport.(*http.Transport).Clone(),
+	}
+	assert.NoError(t, p.initClientCerts())
+
+	tests := []struct {
+		target   endpoint.Endpoint
+		wantCert string
+	}{
+		{target: endpoint.Endpoint{Name: "web.team-a.svc"}, wantCert: "team-a"},
+		{target: endpoint.Endpoint{Name: "web.team-b.svc"}, wantCert: "team-b"},
+		{target: endpoint.Endpoint{Name: "web.team-a.svc", Labels: map[string]string{"client_cert": "team-b"}}, wantCert: "team-b"},
+		{target: endpoint.Endpoint{Name: "web.team-a.svc", Labels: map[string]string{"client_cert": "team-c"}}},
+		{target: endpoint.Endpoint{Name: "web.team-c.svc"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.target.Dst(), func(t *testing.T) {
+			cc := p.clientCertForTarget(test.target)
+			if test.wantCert == "" {
+				assert.Nil(t, cc)
+			} else if assert.NotNil(t, cc) {
+				assert.Equal(t, test.wantCert, cc.name)
+			}
+
+			for _, client := range p.clientsForTarget(test.target) {
+				tlsConfig := client.Transport.(*http.Transport).TLSClientConfig
+				if test.wantCert == "" {
+					assert.True(t, tlsConfig == nil || tlsConfig.GetClientCertificate == nil, "unexpected client cert callback")
+					continue
+				}
+				cert, err := tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+				assert.NoError(t, err)
+				assert.Equal(t, test.wantCert, certCN(t, cert))
+			}
+		})
+	}
+}
+
+func TestInitClientCertsErrors(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "a.crt"), filepath.Join(dir, "a.key")
+	writeTestCert(t, certFile, keyFile, "a")
+
+	certConf := func(name, certFile, regex string) *configpb.ProbeConf_ClientCert {
+		return &configpb.ProbeConf_ClientCert{
+			Name:            proto.String(name),
+			TlsCertFile:     proto.String(certFile),
+			TlsKeyFile:      proto.String(keyFile),
+			TargetNameRegex: proto.String(regex),
+		}
+	}
+
+	for name, certs := range map[string][]*configpb.ProbeConf_ClientCert{
+		"missing_file":   {certConf("a", filepath.Join(dir, "missing.crt"), "")},
+		"invalid_regex":  {certConf("a", certFile, "(")},
+		"duplicate_name": {certConf("a", certFile, ""), certConf("a", certFile, "")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := &Probe{c: &configpb.ProbeConf{ClientCert: certs}}
+			assert.Error(t, p.initClientCerts())
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// acceptEncoding is the Accept-Encoding header value for the probes that
+// decompress responses.
+const acceptEncoding = "gzip, deflate, br"
+
+func decompressReader(r io.Reader, encoding string) (io.Reader, error) {
+	switch encoding {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(r)
+	// HTTP deflate is the zlib format (RFC 9110).
+	case "deflate":
+		return zlib.NewReader(r)
+	case "br":
+		return brotli.NewReader(r), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %s", encoding)
+	}
+}
+
+// decompressBody decompresses the response body as per the Content-Encoding
+// header value. Encodings are listed in the order they were applied, so we
+// decode them in the reverse order. It returns an error if the decompressed
+// body is bigger than maxSize.
+func decompressBody(body []byte, contentEncoding string, maxSize int64) ([]byte, error) {
+	encodings := strings.Split(contentEncoding, ",")
+	for i := len(encodings) - 1; i >= 0; i-- {
+		encoding := strings.ToLower(strings.TrimSpace(encodings[i]))
+		if encoding == "" || encoding == "identity" {
+			continue
+		}
+
+		r, err := decompressReader(bytes.NewReader(body), encoding)
+		if err != nil {
+			return nil, err
+		}
+		// Read one more byte than maxSize to find out if body is bigger.
+		if body, err = io.ReadAll(io.LimitReader(r, maxSize+1)); err != nil {
+			return nil, fmt.Errorf("error decompressing response body (%s): %v", encoding, err)
+		}
+		if int64(len(body)) > maxSize {
+			return nil, fmt.Errorf("decompressed response body is bigger than %d bytes", maxSize)
+		}
+	}
+	return body, nil
+}
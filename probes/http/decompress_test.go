@@ -0,0 +1,141 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+)
+
+func compressForTest(t *testing.T, b []byte, encoding string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "br":
+		w = brotli.NewWriter(&buf)
+	default:
+		t.Fatalf("unknown encoding: %s", encoding)
+	}
+	if _, err := w.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecompressBody(t *testing.T) {
+	body := []byte(strings.Repeat("cloudprober ", 100))
+
+	tests := []struct {
+		name            string
+		body            []byte
+		contentEncoding string
+		maxSize         int64
+		want            []byte
+		wantErr         bool
+	}{
+		{
+			name: "no_encoding",
+			body: body,
+			want: body,
+		},
+		{
+			name:            "identity",
+			body:            body,
+			contentEncoding: "identity",
+			want:            body,
+		},
+		{
+			name:            "gzip",
+			body:            compressForTest(t, body, "gzip"),
+			contentEncoding: "gzip",
+			want:            body,
+		},
+		{
+			name:            "deflate",
+			body:            compressForTest(t, body, "deflate"),
+			contentEncoding: "deflate",
+			want:            body,
+		},
+		{
+			name:            "br",
+			body:            compressForTest(t, body, "br"),
+			contentEncoding: "BR",
+			want:            body,
+		},
+		{
+			name:            "multiple",
+			body:            compressForTest(t, compressForTest(t, body, "gzip"), "br"),
+			contentEncoding: "gzip, br",
+			want:            body,
+		},
+		{
+			name:            "too_big",
+			body:            compressForTest(t, body, "gzip"),
+			contentEncoding: "gzip",
+			maxSize:         int64(len(body) - 1),
+			wantErr:         true,
+		},
+		{
+			name:            "exact_size",
+			body:            compressForTest(t, body, "gzip"),
+			contentEncoding: "gzip",
+			maxSize:         int64(len(body)),
+			want:            body,
+		},
+		{
+			name:            "unsupported",
+			body:            body,
+			contentEncoding: "zstd",
+			wantErr:         true,
+		},
+		{
+			name:            "corrupt",
+			body:            body,
+			contentEncoding: "gzip",
+			wantErr:         true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.maxSize == 0 {
+				test.maxSize = 1 << 20
+			}
+			got, err := decompressBody(test.body, test.contentEncoding, test.maxSize)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"net/http"
 	"net/http/httptrace"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -39,9 +40,13 @@ import (
 	"github.com/cloudprober/cloudprober/internal/validators"
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/common/sched"
 	configpb "github.com/cloudprober/cloudprober/probes/http/proto"
 	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/probes/probeutils"
+	"github.com/cloudprober/cloudprober/targets"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/expr-lang/expr/vm"
 	"golang.org/x/oauth2"
 )
 
@@ -56,6 +61,9 @@ const (
 	largeBodyThreshold        = bytes.MinRead // 512.
 )
 
+// validMethodRe matches the valid HTTP methods, i.e. HTTP tokens.
+var validMethodRe = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
 // Probe holds aggregate information about all probe runs, per-target.
 type Probe struct {
 	name string
@@ -65,12 +73,20 @@ type Probe struct {
 
 	baseTransport http.RoundTripper
 	redirectFunc  func(req *http.Request, via []*http.Request) error
+	clientCerts   []*clientCert
 
 	// book-keeping params
 	targets []endpoint.Endpoint
 	method  string
 	url     string
 	oauthTS oauth2.TokenSource
+	sigV4   *sigV4Signer
+
+	// resolveFirstAlways is set if targets must be resolved before probing,
+	// e.g. if they use a DNS-over-HTTPS resolver.
+	resolveFirstAlways bool
+
+	successExpr *vm.Program
 
 	// How often to resolve targets (in probe counts), it's the minimum of
 	targetsUpdateInterval time.Duration
@@ -82,6 +98,7 @@ type Probe struct {
 
 	// Cancel functions for per-target probe loop
 	cancelFuncs map[string]context.CancelFunc
+	drainer     sched.TargetDrainer
 	waitGroup   sync.WaitGroup
 
 	requestBody *httpreq.RequestBody
@@ -89,12 +106,31 @@ type Probe struct {
 
 type probeResult struct {
 	total, success, timeouts     int64
+	connectTimeouts              int64
 	connEvent                    int64
+	signFailures                 int64
+	successExprErrors            int64
 	latency                      metrics.LatencyValue
 	respCodes                    *metrics.Map[int64]
 	respBodies                   *metrics.Map[int64]
 	validationFailure            *metrics.Map[int64]
+	ocspStatus                   *metrics.Map[int64]
 	sslEarliestExpirationSeconds int64
+	sctCount                     int64
+	contentAgeSec                int64
+	failureCategory              *metrics.Map[int64]
+	protocols                    *metrics.Map[int64]
+	respBytes                    int64
+	respDecompressedBytes        int64
+	validatorValues              map[string]float64
+}
+
+// failed counts a failed request in the given failure category, if failure
+// categories are being exported.
+func (result *probeResult) failed(category string) {
+	if result.failureCategory != nil {
+		result.failureCategory.IncKey(category)
+	}
 }
 
 func (p *Probe) getTransport() (*http.Transport, error) {
@@ -109,6 +145,23 @@ func (p *Probe) getTransport() (*http.Transport, error) {
 		}
 	}
 	transport.DialContext = dialer.DialContext
+	if p.c.GetDialer() != "" {
+		d, err := probeutils.GetDialer(p.c.GetDialer())
+		if err != nil {
+			return nil, err
+		}
+		transport.DialContext = d.DialContext
+	}
+	if p.opts.ConnectTimeout != 0 {
+		transport.DialContext = probeutils.WithConnectTimeout(transport.DialContext, p.opts.ConnectTimeout)
+	}
+	// Without keep-alive, every request dials a new connection and runProbe
+	// waits for the connection rate limiter before starting the requests.
+	// With keep-alive, connections are dialed only occasionally, so we apply
+	// the rate limit at the time of dialing.
+	if p.c.GetKeepAlive() {
+		transport.DialContext = probeutils.LimitDial(transport.DialContext)
+	}
 	transport.MaxIdleConns = int(p.c.GetMaxIdleConns())
 	transport.TLSHandshakeTimeout = p.opts.Timeout
 
@@ -149,11 +202,29 @@ func (p *Probe) getTransport() (*http.Transport, error) {
 		}
 	}
 
+	// Go transport decompresses gzip responses transparently, if it added the
+	// Accept-Encoding header itself. We don't want that if we are
+	// decompressing responses ourselves, as we need the response as received.
+	if p.c.GetDecompressResponse() {
+		transport.DisableCompression = true
+	}
+
 	if p.c.GetDisableHttp2() {
 		// HTTP/2 is enabled by default if server supports it. Setting
 		// TLSNextProto to an empty dict is the only way to disable it.
 		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
 		transport.ForceAttemptHTTP2 = false
+		// TLS config cloned from the default transport may already advertise
+		// h2 through ALPN. Remove it so that servers don't negotiate HTTP/2.
+		if transport.TLSClientConfig != nil {
+			var nextProtos []string
+			for _, np := range transport.TLSClientConfig.NextProtos {
+				if np != "h2" {
+					nextProtos = append(nextProtos, np)
+				}
+			}
+			transport.TLSClientConfig.NextProtos = nextProtos
+		}
 	}
 
 	return transport, nil
@@ -183,7 +254,46 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 			totalDuration, p.opts.Interval)
 	}
 
+	if targets.RequiresResolveFirst(p.opts.Targets) {
+		if p.c.ResolveFirst != nil && !p.c.GetResolveFirst() {
+			return fmt.Errorf("resolve_first cannot be false for targets resolved through DNS-over-HTTPS")
+		}
+		p.resolveFirstAlways = true
+	}
+
 	p.method = p.c.GetMethod().String()
+	if p.c.GetCustomMethod() != "" {
+		if !validMethodRe.MatchString(p.c.GetCustomMethod()) {
+			return fmt.Errorf("invalid custom_method (%s): should be a valid HTTP token", p.c.GetCustomMethod())
+		}
+		p.method = p.c.GetCustomMethod()
+	}
+	if p.method == http.MethodHead {
+		for _, v := range p.opts.Validators {
+			if v.RequiresBody {
+				return fmt.Errorf("invalid config - validator %s checks the response body, but HEAD responses don't have a body", v.Name)
+			}
+		}
+	}
+
+	if p.c.GetSuccessExpr() != "" {
+		program, err := compileSuccessExpr(p.c.GetSuccessExpr())
+		if err != nil {
+			return err
+		}
+		p.successExpr = program
+	}
+
+	if p.c.GetCertificateTransparency().GetMinScts() < 0 {
+		return fmt.Errorf("invalid certificate_transparency.min_scts (%d): cannot be negative", p.c.GetCertificateTransparency().GetMinScts())
+	}
+	if p.c.GetCertificateTransparency() != nil && p.c.SchemeType != nil && p.schemeForTarget(endpoint.Endpoint{}) == "http" {
+		return fmt.Errorf("invalid config - certificate_transparency requires the https scheme")
+	}
+
+	if p.c.GetDecompressResponse() && p.c.GetMaxDecompressedSizeBytes() <= 0 {
+		return fmt.Errorf("invalid max_decompressed_size_bytes (%d): should be positive", p.c.GetMaxDecompressedSizeBytes())
+	}
 
 	p.url = p.c.GetRelativeUrl()
 	if len(p.url) > 0 && p.url[0] != '/' {
@@ -200,11 +310,23 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 		p.oauthTS = oauthTS
 	}
 
+	if p.c.GetAwsSigv4() != nil {
+		sigV4, err := newSigV4Signer(context.Background(), p.c.GetAwsSigv4(), p.requestBody.Reader())
+		if err != nil {
+			return err
+		}
+		p.sigV4 = sigV4
+	}
+
 	transport, err := p.getTransport()
 	if err != nil {
 		return err
 	}
 
+	if err := p.initClientCerts(); err != nil {
+		return err
+	}
+
 	p.baseTransport = transport
 
 	if p.c.MaxRedirects != nil {
@@ -224,6 +346,7 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 
 	p.targets = p.opts.Targets.ListEndpoints()
 	p.cancelFuncs = make(map[string]context.CancelFunc, len(p.targets))
+	p.drainer.GracePeriod = p.opts.TargetRemovalGracePeriod
 
 	p.targetsUpdateInterval = DefaultTargetsUpdateInterval
 	// There is no point refreshing targets before probe interval.
@@ -249,7 +372,19 @@ func isClientTimeout(err error) bool {
 
 // httpRequest executes an HTTP request and updates the provided result struct.
 func (p *Probe) doHTTPRequest(req *http.Request, client *http.Client, targetName string, result *probeResult, resultMu *sync.Mutex) {
-	req = p.prepareRequest(req)
+	req, err := p.prepareRequest(req)
+	if err != nil {
+		p.l.WarningAttrs(err.Error(), slog.String("target", targetName))
+		if resultMu != nil {
+			resultMu.Lock()
+			defer resultMu.Unlock()
+		}
+		// Signing failures are not HTTP failures, we count them separately.
+		result.total++
+		result.signFailures++
+		result.failed(probeutils.FailureOther)
+		return
+	}
 
 	var connEvent atomic.Int32
 	if p.c.GetKeepAlive() {
@@ -280,18 +415,27 @@ func (p *Probe) doHTTPRequest(req *http.Request, client *http.Client, targetName
 	result.connEvent += int64(connEvent.Load())
 
 	if err != nil {
+		if probeutils.IsConnectTimeout(err) {
+			p.l.WarningAttrs(err.Error(), slog.String("target", targetName), slog.String("url", req.URL.String()))
+			result.connectTimeouts++
+			result.failed(probeutils.FailureConnect)
+			return
+		}
 		if isClientTimeout(err) {
 			p.l.WarningAttrs(err.Error(), slog.String("target", targetName), slog.String("url", req.URL.String()))
 			result.timeouts++
+			result.failed(probeutils.FailureTimeout)
 			return
 		}
 		p.l.WarningAttrs(err.Error(), slog.String("target", targetName), slog.String("url", req.URL.String()))
+		result.failed(probeutils.ErrorFailureCategory(err))
 		return
 	}
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		p.l.WarningAttrs(err.Error(), slog.String("target", targetName), slog.String("url", req.URL.String()))
+		result.failed(probeutils.ErrorFailureCategory(err))
 		return
 	}
 
@@ -300,6 +444,20 @@ func (p *Probe) doHTTPRequest(req *http.Request, client *http.Client, targetName
 	// Calling Body.Close() allows the TCP connection to be reused.
 	resp.Body.Close()
 	result.respCodes.IncKey(strconv.FormatInt(int64(resp.StatusCode), 10))
+	if result.protocols != nil {
+		result.protocols.IncKey(resp.Proto)
+	}
+
+	if p.c.GetDecompressResponse() {
+		result.respBytes += int64(len(respBody))
+		respBody, err = decompressBody(respBody, resp.Header.Get("Content-Encoding"), p.c.GetMaxDecompressedSizeBytes())
+		if err != nil {
+			p.l.WarningAttrs(err.Error(), slog.String("target", targetName), slog.String("url", req.URL.String()))
+			result.failed(probeutils.FailureOther)
+			return
+		}
+		result.respDecompressedBytes += int64(len(respBody))
+	}
 
 	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
 		now := time.Now()
@@ -314,13 +472,63 @@ func (p *Probe) doHTTPRequest(req *http.Request, client *http.Client, targetName
 		result.sslEarliestExpirationSeconds = int64(minExpirySeconds)
 	}
 
+	if result.ocspStatus != nil {
+		status, err := ocspStatus(resp.TLS, time.Now())
+		result.ocspStatus.IncKey(status)
+		if err != nil {
+			p.l.WarningAttrs(err.Error(), slog.String("target", targetName), slog.String("url", req.URL.String()))
+			result.failed(probeutils.FailureTLS)
+			return
+		}
+	}
+
+	if ct := p.c.GetCertificateTransparency(); ct != nil {
+		n, err := checkSCTs(resp.TLS, int(ct.GetMinScts()))
+		result.sctCount = int64(n)
+		if err != nil {
+			p.l.WarningAttrs(err.Error(), slog.String("target", targetName), slog.String("url", req.URL.String()))
+			result.failed(probeutils.FailureTLS)
+			return
+		}
+	}
+
 	if p.opts.Validators != nil {
-		failedValidations := validators.RunValidators(p.opts.Validators, &validators.Input{Response: resp, ResponseBody: respBody}, result.validationFailure, p.l)
+		input := &validators.Input{Response: resp, ResponseBody: respBody, Latency: latency}
+		failedValidations := validators.RunValidators(p.opts.Validators, input, result.validationFailure, p.l)
+		if age, ok := validators.ContentAge(p.opts.Validators, input); ok {
+			result.contentAgeSec = int64(age.Seconds())
+		}
+		for name, val := range validators.Values(p.opts.Validators, input) {
+			if result.validatorValues == nil {
+				result.validatorValues = make(map[string]float64)
+			}
+			result.validatorValues[name] = val
+		}
 
 		// If any validation failed, return now, leaving the success and latency
 		// counters unchanged.
 		if len(failedValidations) > 0 {
 			p.l.Debug("Target:", targetName, ", URL:", req.URL.String(), ", http.doHTTPRequest: failed validations: ", strings.Join(failedValidations, ","))
+			if resp.StatusCode >= http.StatusBadRequest {
+				result.failed(probeutils.FailureStatus)
+			} else {
+				result.failed(probeutils.FailureValidation)
+			}
+			return
+		}
+	}
+
+	if p.successExpr != nil {
+		ok, err := evalSuccessExpr(p.successExpr, resp, respBody, latency)
+		if err != nil {
+			result.successExprErrors++
+			p.l.WarningAttrs(err.Error(), slog.String("target", targetName), slog.String("url", req.URL.String()))
+			result.failed(probeutils.FailureValidation)
+			return
+		}
+		if !ok {
+			p.l.Debug("Target:", targetName, ", URL:", req.URL.String(), ", http.doHTTPRequest: success_expr evaluated to false")
+			result.failed(probeutils.FailureValidation)
 			return
 		}
 	}
@@ -333,6 +541,18 @@ func (p *Probe) doHTTPRequest(req *http.Request, client *http.Client, targetName
 }
 
 func (p *Probe) runProbe(ctx context.Context, target endpoint.Endpoint, clients []*http.Client, req *http.Request, result *probeResult) {
+	// Wait for the connection rate limiter before the request timeout and the
+	// latency measurement start, so that the wait doesn't show up as
+	// timeouts or latency.
+	if !p.c.GetKeepAlive() {
+		for i := 0; i < int(p.c.GetRequestsPerProbe()); i++ {
+			if err := probeutils.WaitForConnRateLimit(ctx); err != nil {
+				p.l.WarningAttrs(err.Error(), slog.String("target", target.Name))
+				return
+			}
+		}
+	}
+
 	reqCtx, cancelReqCtx := context.WithTimeout(ctx, p.opts.Timeout)
 	defer cancelReqCtx()
 
@@ -364,12 +584,22 @@ func (p *Probe) newResult() *probeResult {
 	result := &probeResult{
 		respCodes:                    metrics.NewMap("code"),
 		sslEarliestExpirationSeconds: -1,
+		sctCount:                     -1,
+		contentAgeSec:                -1,
 	}
 
 	if p.opts.Validators != nil {
 		result.validationFailure = validators.ValidationFailureMap(p.opts.Validators)
 	}
 
+	if p.c.GetExportFailureCategory() {
+		result.failureCategory = probeutils.NewFailureCategoryMap()
+	}
+
+	if p.c.GetExportProtocol() {
+		result.protocols = metrics.NewMap("proto")
+	}
+
 	if p.opts.LatencyDist != nil {
 		result.latency = p.opts.LatencyDist.CloneDist()
 	} else {
@@ -380,6 +610,10 @@ func (p *Probe) newResult() *probeResult {
 		result.respBodies = metrics.NewMap("resp")
 	}
 
+	if p.c.GetCheckOcspStapling() {
+		result.ocspStatus = newOCSPStatusMap()
+	}
+
 	return result
 }
 
@@ -395,28 +629,83 @@ func (p *Probe) exportMetrics(ts time.Time, result *probeResult, target endpoint
 		em.AddMetric("resp-body", result.respBodies.Clone())
 	}
 
+	if p.opts.ConnectTimeout != 0 {
+		em.AddMetric("connect_timeouts", metrics.NewInt(result.connectTimeouts))
+	}
+
 	if p.c.GetKeepAlive() {
 		em.AddMetric("connect_event", metrics.NewInt(result.connEvent))
 	}
 
+	if p.sigV4 != nil {
+		em.AddMetric("sign_failures", metrics.NewInt(result.signFailures))
+	}
+
+	if p.successExpr != nil {
+		em.AddMetric("success_expr_errors", metrics.NewInt(result.successExprErrors))
+	}
+
 	if result.validationFailure != nil {
 		em.AddMetric("validation_failure", result.validationFailure)
 	}
 
-	em.AddLabel("ptype", "http").AddLabel("probe", p.name).AddLabel("dst", target.Name)
+	if result.failureCategory != nil {
+		em.AddMetric("failure_category", result.failureCategory.Clone())
+	}
+
+	if result.ocspStatus != nil {
+		em.AddMetric("ocsp_status", result.ocspStatus.Clone())
+	}
+
+	if result.protocols != nil {
+		em.AddMetric("protocol", result.protocols.Clone())
+	}
+
+	if p.c.GetDecompressResponse() {
+		em.AddMetric("resp_bytes", metrics.NewInt(result.respBytes))
+		em.AddMetric("resp_decompressed_bytes", metrics.NewInt(result.respDecompressedBytes))
+	}
+
+	p.addLabels(em, target)
+	if cc := p.clientCertForTarget(target); cc != nil {
+		em.AddLabel("client_cert", cc.name)
+	}
 	p.opts.RecordMetrics(target, em, dataChan)
 
-	// SSL earliest cert expiry is exported in an independent EM as it's a
-	// GAUGE metrics.
-	if result.sslEarliestExpirationSeconds >= 0 {
-		em := metrics.NewEventMetrics(ts).
-			AddMetric("ssl_earliest_cert_expiry_sec", metrics.NewInt(result.sslEarliestExpirationSeconds))
+	// SSL earliest cert expiry, SCT count, content age and validator values
+	// are exported in an independent EM as these are GAUGE metrics.
+	if result.sslEarliestExpirationSeconds >= 0 || result.sctCount >= 0 || result.contentAgeSec >= 0 || len(result.validatorValues) > 0 {
+		em := metrics.NewEventMetrics(ts)
+		if result.sslEarliestExpirationSeconds >= 0 {
+			em.AddMetric("ssl_earliest_cert_expiry_sec", metrics.NewInt(result.sslEarliestExpirationSeconds))
+		}
+		if result.sctCount >= 0 {
+			em.AddMetric("sct_count", metrics.NewInt(result.sctCount))
+		}
+		if result.contentAgeSec >= 0 {
+			em.AddMetric("content_age_sec", metrics.NewInt(result.contentAgeSec))
+		}
+		if len(result.validatorValues) > 0 {
+			values := metrics.NewMapFloat("validator")
+			for name, val := range result.validatorValues {
+				values.IncKeyBy(name, val)
+			}
+			em.AddMetric("validator_value", values)
+		}
 		em.Kind = metrics.GAUGE
-		em.AddLabel("ptype", "http").AddLabel("probe", p.name).AddLabel("dst", target.Name)
+		p.addLabels(em, target)
 		p.opts.RecordMetrics(target, em, dataChan, options.WithNoAlert())
 	}
 }
 
+// addLabels adds the probe's standard labels to the EventMetrics.
+func (p *Probe) addLabels(em *metrics.EventMetrics, target endpoint.Endpoint) {
+	em.AddLabel("ptype", "http").AddLabel("probe", p.name).AddLabel("dst", target.Name)
+	if p.c.GetExportMethodLabel() {
+		em.AddLabel("method", p.method)
+	}
+}
+
 // Returns clients for a target. We use a different HTTP client (transport) for
 // each request within a probe cycle. For example, if you configure
 // requests_per_probe as 100, we'll create and use 100 HTTP clients. This
@@ -426,6 +715,7 @@ func (p *Probe) exportMetrics(ts time.Time, result *probeResult, target endpoint
 // want to hit as many backends as possible, behind a single VIP.
 func (p *Probe) clientsForTarget(target endpoint.Endpoint) []*http.Client {
 	clients := make([]*http.Client, p.c.GetRequestsPerProbe())
+	cc := p.clientCertForTarget(target)
 	for i := range clients {
 		// We check for http.Transport because tests use a custom
 		// RoundTripper implementation.
@@ -444,6 +734,16 @@ func (p *Probe) clientsForTarget(target endpoint.Endpoint) []*http.Client {
 				}
 			}
 
+			if cc != nil {
+				if t.TLSClientConfig == nil {
+					t.TLSClientConfig = &tls.Config{}
+				}
+				t.TLSClientConfig.Certificates = nil
+				t.TLSClientConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+					return cc.getCert()
+				}
+			}
+
 			clients[i] = &http.Client{Transport: t}
 		} else {
 			clients[i] = &http.Client{Transport: p.baseTransport}
@@ -454,7 +754,9 @@ func (p *Probe) clientsForTarget(target endpoint.Endpoint) []*http.Client {
 	return clients
 }
 
-func (p *Probe) startForTarget(ctx context.Context, target endpoint.Endpoint, dataChan chan *metrics.EventMetrics) {
+// startForTarget runs the probe loop for the target. Probes run with ctx,
+// while loopCtx decides whether to run the next probe.
+func (p *Probe) startForTarget(ctx, loopCtx context.Context, target endpoint.Endpoint, dataChan chan *metrics.EventMetrics) {
 	p.l.Debug("Starting probing for the target ", target.Name)
 
 	// We use this counter to decide when to export stats.
@@ -468,7 +770,7 @@ func (p *Probe) startForTarget(ctx context.Context, target endpoint.Endpoint, da
 	clients := p.clientsForTarget(target)
 	for ts := time.Now(); true; ts = <-ticker.C {
 		// Don't run another probe if context is canceled already.
-		if ctxDone(ctx) {
+		if ctxDone(loopCtx) {
 			return
 		}
 
@@ -531,14 +833,25 @@ func (p *Probe) updateTargetsAndStartProbes(ctx context.Context, dataChan chan *
 		activeTargets[key] = target
 	}
 
-	// Stop probing for deleted targets by invoking cancelFunc.
+	// Stop probing for deleted targets by invoking cancelFunc, after the
+	// grace period if it's configured.
 	for targetKey, cancelF := range p.cancelFuncs {
 		if _, ok := activeTargets[targetKey]; ok {
+			// If target came back after its probe loop was stopped, forget
+			// it so that a new probe loop is started below.
+			if !p.drainer.Restore(targetKey) {
+				delete(p.cancelFuncs, targetKey)
+			}
+			continue
+		}
+		action := p.drainer.Remove(targetKey, cancelF)
+		if action == "" {
 			continue
 		}
-		cancelF()
-		updatedTargets[targetKey] = "DELETE"
-		delete(p.cancelFuncs, targetKey)
+		updatedTargets[targetKey] = action
+		if action == "DELETE" {
+			delete(p.cancelFuncs, targetKey)
+		}
 	}
 
 	gapBetweenTargets := p.gapBetweenTargets()
@@ -553,10 +866,12 @@ func (p *Probe) updateTargetsAndStartProbes(ctx context.Context, dataChan chan *
 		updatedTargets[key] = "ADD"
 
 		probeCtx, cancelF := context.WithCancel(ctx)
+		loopCtx := p.drainer.LoopContext(probeCtx, key)
 		p.waitGroup.Add(1)
 
 		go func(target endpoint.Endpoint, waitTime time.Duration) {
 			defer p.waitGroup.Done()
+			defer cancelF()
 
 			// To evenly spread out target probes, wait for a randomized
 			// duration before starting the target go-routine.
@@ -570,7 +885,7 @@ func (p *Probe) updateTargetsAndStartProbes(ctx context.Context, dataChan chan *
 				time.Sleep(waitTime + time.Duration(rand.Int63n(jitterMaxUsec))*time.Microsecond)
 			}
 
-			p.startForTarget(probeCtx, target, dataChan)
+			p.startForTarget(probeCtx, loopCtx, target, dataChan)
 		}(target, startWaitTime)
 
 		startWaitTime += gapBetweenTargets
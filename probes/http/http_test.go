@@ -22,6 +22,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"strconv"
@@ -30,11 +31,16 @@ import (
 	"testing"
 	"time"
 
+	tlsconfigpb "github.com/cloudprober/cloudprober/internal/tlsconfig/proto"
+	"github.com/cloudprober/cloudprober/internal/validators"
+	validatorpb "github.com/cloudprober/cloudprober/internal/validators/proto"
+	thresholdpb "github.com/cloudprober/cloudprober/internal/validators/threshold/proto"
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
 	"github.com/cloudprober/cloudprober/metrics/testutils"
 	configpb "github.com/cloudprober/cloudprober/probes/http/proto"
 	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/probes/probeutils"
 	"github.com/cloudprober/cloudprober/targets"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
 	"github.com/stretchr/testify/assert"
@@ -53,7 +59,7 @@ type testTransport struct {
 
 func patchWithTestTransport(p *Probe) {
 	keepAuthHeader := false
-	if p.oauthTS != nil {
+	if p.oauthTS != nil || p.sigV4 != nil {
 		keepAuthHeader = true
 	}
 	p.baseTransport = &testTransport{
@@ -170,6 +176,55 @@ func TestProbeInitError(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			desc: "negative_min_scts",
+			c: &configpb.ProbeConf{
+				CertificateTransparency: &configpb.ProbeConf_CertificateTransparency{
+					MinScts: proto.Int32(-1),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			desc: "certificate_transparency_http_scheme",
+			c: &configpb.ProbeConf{
+				SchemeType: &configpb.ProbeConf_Scheme_{
+					Scheme: configpb.ProbeConf_HTTP,
+				},
+				CertificateTransparency: &configpb.ProbeConf_CertificateTransparency{},
+			},
+			wantErr: true,
+		},
+		{
+			desc: "custom_method",
+			c: &configpb.ProbeConf{
+				CustomMethod: proto.String("PROPFIND"),
+			},
+		},
+		{
+			desc: "invalid_custom_method",
+			c: &configpb.ProbeConf{
+				CustomMethod: proto.String("GET STATUS"),
+			},
+			wantErr: true,
+		},
+		{
+			desc: "head_with_body_validator",
+			opts: func() *options.Options {
+				opts := opts(&configpb.ProbeConf{Method: configpb.ProbeConf_HEAD.Enum()})
+				opts.Validators = []*validators.Validator{{Name: "regex", RequiresBody: true}}
+				return opts
+			}(),
+			wantErr: true,
+		},
+		{
+			desc: "head_with_http_validator",
+			opts: func() *options.Options {
+				opts := opts(&configpb.ProbeConf{Method: configpb.ProbeConf_HEAD.Enum()})
+				opts.Validators = []*validators.Validator{{Name: "status"}}
+				return opts
+			}(),
+		},
 	}
 
 	for _, test := range tests {
@@ -718,6 +773,33 @@ func TestGetTransport(t *testing.T) {
 	}
 }
 
+type testDialer struct {
+	addrs []string
+}
+
+func (d *testDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	d.addrs = append(d.addrs, addr)
+	return nil, errors.New("test dialer")
+}
+
+func TestGetTransportCustomDialer(t *testing.T) {
+	d := &testDialer{}
+	probeutils.RegisterDialer("http-test-dialer", d)
+
+	p := &Probe{opts: options.DefaultOptions()}
+	p.c = &configpb.ProbeConf{Dialer: proto.String("unknown-dialer")}
+	_, err := p.getTransport()
+	assert.Error(t, err, "unregistered dialer")
+
+	p.c = &configpb.ProbeConf{Dialer: proto.String("http-test-dialer")}
+	transport, err := p.getTransport()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	transport.DialContext(context.Background(), "tcp", "test.com:80")
+	assert.Equal(t, []string{"test.com:80"}, d.addrs)
+}
+
 func TestProbeInitRedirects(t *testing.T) {
 	p := &Probe{}
 	maxRedirects := 10
@@ -859,3 +941,313 @@ func TestClientsForTarget(t *testing.T) {
 		})
 	}
 }
+
+func TestProbeMethodLabel(t *testing.T) {
+	for _, test := range []struct {
+		c          *configpb.ProbeConf
+		wantMethod string
+		wantLabel  string
+	}{
+		{c: &configpb.ProbeConf{}, wantMethod: "GET"},
+		{c: &configpb.ProbeConf{ExportMethodLabel: proto.Bool(true)}, wantMethod: "GET", wantLabel: "GET"},
+		{c: &configpb.ProbeConf{Method: configpb.ProbeConf_OPTIONS.Enum(), ExportMethodLabel: proto.Bool(true)}, wantMethod: "OPTIONS", wantLabel: "OPTIONS"},
+		{c: &configpb.ProbeConf{Method: configpb.ProbeConf_POST.Enum(), CustomMethod: proto.String("PURGE"), ExportMethodLabel: proto.Bool(true)}, wantMethod: "PURGE", wantLabel: "PURGE"},
+	} {
+		t.Run(test.wantMethod+"_"+test.wantLabel, func(t *testing.T) {
+			opts := options.DefaultOptions()
+			opts.ProbeConf = test.c
+			p := &Probe{}
+			if err := p.Init("http_test", opts); err != nil {
+				t.Fatalf("Error initializing probe: %v", err)
+			}
+			patchWithTestTransport(p)
+
+			target := endpoint.Endpoint{Name: "test.com"}
+			req := p.httpRequestForTarget(target)
+			assert.Equal(t, test.wantMethod, req.Method)
+
+			result := p.newResult()
+			p.runProbe(context.Background(), target, p.clientsForTarget(target), req, result)
+			assert.Equal(t, int64(1), result.success)
+
+			dataChan := make(chan *metrics.EventMetrics, 10)
+			p.exportMetrics(time.Now(), result, target, dataChan)
+			assert.Equal(t, test.wantLabel, (<-dataChan).Label("method"))
+		})
+	}
+}
+
+// errTransport returns the given error for all requests.
+type errTransport struct {
+	err error
+}
+
+func (et *errTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, et.err
+}
+
+func TestFailureCategory(t *testing.T) {
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{
+		ExportFailureCategory: proto.Bool(true),
+	}
+	opts.Validators = []*validators.Validator{
+		{
+			Name: "status-2xx",
+			Validate: func(input *validators.Input) (bool, error) {
+				return input.Response.(*http.Response).StatusCode < 300, nil
+			},
+		},
+		{
+			Name:     "has-ok",
+			Validate: func(input *validators.Input) (bool, error) { return string(input.ResponseBody) == "ok", nil },
+		},
+	}
+	p := &Probe{}
+	if err := p.Init("http_test", opts); err != nil {
+		t.Fatalf("Error initializing probe: %v", err)
+	}
+
+	target := endpoint.Endpoint{Name: "test.com"}
+	req := p.httpRequestForTarget(target)
+	result := p.newResult()
+
+	for _, rt := range []http.RoundTripper{
+		&bodyTransport{statusCode: 200, body: "ok"},
+		&bodyTransport{statusCode: 200, body: "not-ok"},
+		&bodyTransport{statusCode: 503, body: "ok"},
+		&errTransport{err: &net.DNSError{Err: "no such host", Name: "test.com", IsNotFound: true}},
+		&errTransport{err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", errors.New("connection refused"))}},
+		&errTransport{err: os.ErrDeadlineExceeded},
+		&errTransport{err: errors.New("tls: handshake failure")},
+	} {
+		p.baseTransport = rt
+		p.runProbe(context.Background(), target, p.clientsForTarget(target), req, result)
+	}
+
+	assert.Equal(t, int64(7), result.total, "total")
+	assert.Equal(t, int64(1), result.success, "success")
+
+	dataChan := make(chan *metrics.EventMetrics, 10)
+	p.exportMetrics(time.Now(), result, target, dataChan)
+	em := <-dataChan
+	got := make(map[string]int64)
+	fc := em.Metric("failure_category").(*metrics.Map[int64])
+	for _, k := range fc.Keys() {
+		got[k] = fc.GetKey(k)
+	}
+	assert.Equal(t, map[string]int64{
+		"dns":        1,
+		"connect":    1,
+		"tls":        1,
+		"timeout":    1,
+		"validation": 1,
+		"status":     1,
+		"other":      0,
+	}, got)
+}
+
+func TestExportProtocol(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	port, _ := strconv.Atoi(u.Port())
+	target := endpoint.Endpoint{Name: u.Hostname(), Port: port}
+
+	for _, test := range []struct {
+		disableHTTP2 bool
+		wantProto    string
+	}{
+		{wantProto: "HTTP/2.0"},
+		{disableHTTP2: true, wantProto: "HTTP/1.1"},
+	} {
+		t.Run(test.wantProto, func(t *testing.T) {
+			opts := options.DefaultOptions()
+			opts.ProbeConf = &configpb.ProbeConf{
+				SchemeType:     &configpb.ProbeConf_Scheme_{Scheme: configpb.ProbeConf_HTTPS},
+				TlsConfig:      &tlsconfigpb.TLSConfig{DisableCertValidation: proto.Bool(true)},
+				DisableHttp2:   proto.Bool(test.disableHTTP2),
+				ExportProtocol: proto.Bool(true),
+			}
+			p := &Probe{}
+			if err := p.Init("http_test", opts); err != nil {
+				t.Fatalf("Error initializing probe: %v", err)
+			}
+
+			result := p.newResult()
+			p.runProbe(context.Background(), target, p.clientsForTarget(target), p.httpRequestForTarget(target), result)
+			assert.Equal(t, int64(1), result.success)
+
+			dataChan := make(chan *metrics.EventMetrics, 10)
+			p.exportMetrics(time.Now(), result, target, dataChan)
+			protocols := (<-dataChan).Metric("protocol").(*metrics.Map[int64])
+			assert.Equal(t, []string{test.wantProto}, protocols.Keys())
+			assert.Equal(t, int64(1), protocols.GetKey(test.wantProto))
+		})
+	}
+}
+
+func TestDecompressResponse(t *testing.T) {
+	body := strings.Repeat("hello ", 10)
+	compressed := compressForTest(t, []byte(body), "gzip")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != acceptEncoding {
+			w.Write([]byte(body))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed)
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	port, _ := strconv.Atoi(u.Port())
+	target := endpoint.Endpoint{Name: u.Hostname(), Port: port}
+
+	for _, test := range []struct {
+		name        string
+		maxSize     int64
+		wantSuccess int64
+	}{
+		{name: "default_max_size", wantSuccess: 1},
+		{name: "too_big", maxSize: int64(len(body) - 1)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			opts := options.DefaultOptions()
+			conf := &configpb.ProbeConf{
+				DecompressResponse:      proto.Bool(true),
+				ExportResponseAsMetrics: proto.Bool(true),
+			}
+			if test.maxSize != 0 {
+				conf.MaxDecompressedSizeBytes = proto.Int64(test.maxSize)
+			}
+			opts.ProbeConf = conf
+			p := &Probe{}
+			if err := p.Init("http_test", opts); err != nil {
+				t.Fatalf("Error initializing probe: %v", err)
+			}
+
+			result := p.newResult()
+			p.runProbe(context.Background(), target, p.clientsForTarget(target), p.httpRequestForTarget(target), result)
+			assert.Equal(t, test.wantSuccess, result.success)
+			if test.wantSuccess > 0 {
+				assert.Equal(t, int64(1), result.respBodies.GetKey(body))
+			}
+
+			dataChan := make(chan *metrics.EventMetrics, 10)
+			p.exportMetrics(time.Now(), result, target, dataChan)
+			em := <-dataChan
+			assert.Equal(t, int64(len(compressed)), em.Metric("resp_bytes").(*metrics.Int).Int64())
+			if test.wantSuccess > 0 {
+				assert.Equal(t, int64(len(body)), em.Metric("resp_decompressed_bytes").(*metrics.Int).Int64())
+			}
+		})
+	}
+
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{
+		DecompressResponse:       proto.Bool(true),
+		MaxDecompressedSizeBytes: proto.Int64(0),
+	}
+	assert.Error(t, (&Probe{}).Init("http_test", opts), "zero max_decompressed_size_bytes")
+}
+
+func TestValidatorValue(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"health": {"score": 0.5}}`))
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	port, _ := strconv.Atoi(u.Port())
+	target := endpoint.Endpoint{Name: u.Hostname(), Port: port}
+
+	vs, err := validators.Init([]*validatorpb.Validator{
+		{
+			Name: "score",
+			Type: &validatorpb.Validator_Threshold{
+				Threshold: &thresholdpb.Validator{
+					Extractor: &thresholdpb.Validator_JqFilter{JqFilter: ".health.score"},
+					Min:       proto.Float64(0.9),
+				},
+			},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Error initializing validators: %v", err)
+	}
+
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{}
+	opts.Validators = vs
+	p := &Probe{}
+	if err := p.Init("http_test", opts); err != nil {
+		t.Fatalf("Error initializing probe: %v", err)
+	}
+
+	result := p.newResult()
+	p.runProbe(context.Background(), target, p.clientsForTarget(target), p.httpRequestForTarget(target), result)
+	assert.Equal(t, int64(0), result.success)
+	assert.Equal(t, int64(1), result.validationFailure.GetKey("score"))
+
+	dataChan := make(chan *metrics.EventMetrics, 10)
+	p.exportMetrics(time.Now(), result, target, dataChan)
+	<-dataChan
+	em := <-dataChan
+	assert.True(t, em.Kind == metrics.GAUGE, "validator value metric kind")
+	assert.Equal(t, 0.5, em.Metric("validator_value").(*metrics.Map[float64]).GetKey("score"))
+}
+
+// blockingDialer blocks until the context is done, like a dial to an
+// unreachable host.
+type blockingDialer struct{}
+
+func (blockingDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	<-ctx.Done()
+	return nil, &net.OpError{Op: "dial", Net: network, Err: ctx.Err()}
+}
+
+func TestConnectTimeout(t *testing.T) {
+	probeutils.RegisterDialer("http-blocking-dialer", blockingDialer{})
+	target := endpoint.Endpoint{Name: "test.com"}
+
+	for _, connectTimeout := range []time.Duration{0, 10 * time.Millisecond} {
+		t.Run(connectTimeout.String(), func(t *testing.T) {
+			opts := options.DefaultOptions()
+			opts.Timeout = 100 * time.Millisecond
+			opts.ConnectTimeout = connectTimeout
+			opts.ProbeConf = &configpb.ProbeConf{
+				Dialer:                proto.String("http-blocking-dialer"),
+				ExportFailureCategory: proto.Bool(true),
+			}
+			p := &Probe{}
+			if err := p.Init("http_test", opts); err != nil {
+				t.Fatalf("Error initializing probe: %v", err)
+			}
+
+			result := p.newResult()
+			p.runProbe(context.Background(), target, p.clientsForTarget(target), p.httpRequestForTarget(target), result)
+
+			dataChan := make(chan *metrics.EventMetrics, 10)
+			p.exportMetrics(time.Now(), result, target, dataChan)
+			em := <-dataChan
+
+			if connectTimeout == 0 {
+				assert.Equal(t, int64(1), result.timeouts, "timeouts")
+				assert.Equal(t, int64(1), result.failureCategory.GetKey(probeutils.FailureTimeout))
+				assert.Nil(t, em.Metric("connect_timeouts"), "connect_timeouts metric")
+				return
+			}
+			assert.Equal(t, int64(0), result.timeouts, "timeouts")
+			assert.Equal(t, int64(1), result.connectTimeouts, "connect_timeouts")
+			assert.Equal(t, int64(1), result.failureCategory.GetKey(probeutils.FailureConnect))
+			assert.Equal(t, "1", em.Metric("connect_timeouts").String())
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"golang.org/x/crypto/ocsp"
+)
+
+// OCSP stapling statuses, exported as keys of the ocsp_status metric.
+const (
+	ocspGood    = "good"
+	ocspRevoked = "revoked"
+	ocspUnknown = "unknown"
+	ocspMissing = "missing"
+	ocspInvalid = "invalid"
+)
+
+func newOCSPStatusMap() *metrics.Map[int64] {
+	m := metrics.NewMap("status")
+	for _, status := range []string{ocspGood, ocspRevoked, ocspUnknown, ocspMissing, ocspInvalid} {
+		m.IncKeyBy(status, 0)
+	}
+	return m
+}
+
+// ocspIssuer returns the issuer of the leaf certificate, preferring the
+// verified chain over the certificates presented by the server.
+func ocspIssuer(cs *tls.ConnectionState) *x509.Certificate {
+	if len(cs.VerifiedChains) > 0 && len(cs.VerifiedChains[0]) > 1 {
+		return cs.VerifiedChains[0][1]
+	}
+	if len(cs.PeerCertificates) > 1 {
+		return cs.PeerCertificates[1]
+	}
+	return nil
+}
+
+// ocspStatus returns the status of the server's certificate as per the OCSP
+// response stapled in the TLS handshake. Error is returned for all statuses
+// other than "good".
+func ocspStatus(cs *tls.ConnectionState, now time.Time) (string, error) {
+	if cs == nil || len(cs.PeerCertificates) == 0 {
+		return ocspMissing, errors.New("OCSP: no TLS connection state")
+	}
+	if len(cs.OCSPResponse) == 0 {
+		return ocspMissing, errors.New("OCSP: no stapled response")
+	}
+
+	issuer := ocspIssuer(cs)
+	if issuer == nil {
+		return ocspInvalid, errors.New("OCSP: issuer certificate not found")
+	}
+	resp, err := ocsp.ParseResponseForCert(cs.OCSPResponse, cs.PeerCertificates[0], issuer)
+	if err != nil {
+		return ocspInvalid, fmt.Errorf("OCSP: error parsing stapled response: %v", err)
+	}
+	if !resp.NextUpdate.IsZero() && now.After(resp.NextUpdate) {
+		return ocspInvalid, fmt.Errorf("OCSP: stapled response expired at %s", resp.NextUpdate)
+	}
+
+	switch resp.Status {
+	case ocsp.Good:
+		return ocspGood, nil
+	case ocsp.Revoked:
+		return ocspRevoked, fmt.Errorf("OCSP: certificate revoked at %s", resp.RevokedAt)
+	default:
+		return ocspUnknown, errors.New("OCSP: certificate status unknown")
+	}
+}
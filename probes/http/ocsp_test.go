@@ -0,0 +1,236 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/probes/http/proto"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ocsp"
+	"google.golang.org/protobuf/proto"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+func newTestCert(t *testing.T, cn string, serial int64, issuer *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+	}
+	parent, parentKey := tmpl, crypto.Signer(key)
+	if issuer == nil {
+		tmpl.IsCA = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		parent, parentKey = issuer.cert, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, key.Public(), parentKey)
+	if err != nil {
+		t.Fatalf("error creating certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("error parsing certificate: %v", err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+func testOCSPResponse(t *testing.T, issuer, leaf *testCert, status int, nextUpdate time.Time) []byte {
+	t.Helper()
+	tmpl := ocsp.Response{
+		Status:       status,
+		SerialNumber: leaf.cert.SerialNumber,
+		ThisUpdate:   time.Now().Add(-time.Hour),
+		NextUpdate:   nextUpdate,
+	}
+	if status == ocsp.Revoked {
+		tmpl.RevokedAt = time.Now().Add(-time.Minute)
+	}
+	b, err := ocsp.CreateResponse(issuer.cert, issuer.cert, tmpl, issuer.key)
+	if err != nil {
+		t.Fatalf("error creating OCSP response: %v", err)
+	}
+	return b
+}
+
+func TestOCSPStatus(t *testing.T) {
+	ca := newTestCert(t, "test-ca", 1, nil)
+	otherCA := newTestCert(t, "other-ca", 2, nil)
+	leaf := newTestCert(t, "test.com", 100, ca)
+	nextUpdate := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		cs         *tls.ConnectionState
+		wantStatus string
+	}{
+		{
+			name:       "no_tls",
+			wantStatus: ocspMissing,
+		},
+		{
+			name:       "no_staple",
+			cs:         &tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf.cert, ca.cert}},
+			wantStatus: ocspMissing,
+		},
+		{
+			name: "good",
+			cs: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{leaf.cert, ca.cert},
+				OCSPResponse:     testOCSPResponse(t, ca, leaf, ocsp.Good, nextUpdate),
+			},
+			wantStatus: ocspGood,
+		},
+		{
+			name: "good_verified_chain",
+			cs: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{leaf.cert},
+				VerifiedChains:   [][]*x509.Certificate{{leaf.cert, ca.cert}},
+				OCSPResponse:     testOCSPResponse(t, ca, leaf, ocsp.Good, nextUpdate),
+			},
+			wantStatus: ocspGood,
+		},
+		{
+			name: "revoked",
+			cs: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{leaf.cert, ca.cert},
+				OCSPResponse:     testOCSPResponse(t, ca, leaf, ocsp.Revoked, nextUpdate),
+			},
+			wantStatus: ocspRevoked,
+		},
+		{
+			name: "unknown",
+			cs: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{leaf.cert, ca.cert},
+				OCSPResponse:     testOCSPResponse(t, ca, leaf, ocsp.Unknown, nextUpdate),
+			},
+			wantStatus: ocspUnknown,
+		},
+		{
+			name: "expired",
+			cs: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{leaf.cert, ca.cert},
+				OCSPResponse:     testOCSPResponse(t, ca, leaf, ocsp.Good, time.Now().Add(-time.Minute)),
+			},
+			wantStatus: ocspInvalid,
+		},
+		{
+			name: "wrong_signer",
+			cs: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{leaf.cert, ca.cert},
+				OCSPResponse:     testOCSPResponse(t, otherCA, leaf, ocsp.Good, nextUpdate),
+			},
+			wantStatus: ocspInvalid,
+		},
+		{
+			name: "no_issuer",
+			cs: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{leaf.cert},
+				OCSPResponse:     testOCSPResponse(t, ca, leaf, ocsp.Good, nextUpdate),
+			},
+			wantStatus: ocspInvalid,
+		},
+		{
+			name: "garbage",
+			cs: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{leaf.cert, ca.cert},
+				OCSPResponse:     []byte("not-an-ocsp-response"),
+			},
+			wantStatus: ocspInvalid,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, err := ocspStatus(test.cs, time.Now())
+			assert.Equal(t, test.wantStatus, status)
+			if test.wantStatus == ocspGood {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// tlsStateTransport returns responses with the given TLS connection state.
+type tlsStateTransport struct {
+	cs *tls.ConnectionState
+}
+
+func (tt *tlsStateTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, TLS: tt.cs}, nil
+}
+
+func TestProbeWithOCSPStapling(t *testing.T) {
+	ca := newTestCert(t, "test-ca", 1, nil)
+	leaf := newTestCert(t, "test.com", 100, ca)
+
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{
+		CheckOcspStapling: proto.Bool(true),
+	}
+	p := &Probe{}
+	if err := p.Init("http_test", opts); err != nil {
+		t.Fatalf("Error initializing probe: %v", err)
+	}
+
+	target := endpoint.Endpoint{Name: "test.com"}
+	req := p.httpRequestForTarget(target)
+	result := p.newResult()
+
+	for _, resp := range [][]byte{
+		testOCSPResponse(t, ca, leaf, ocsp.Good, time.Now().Add(time.Hour)),
+		testOCSPResponse(t, ca, leaf, ocsp.Revoked, time.Now().Add(time.Hour)),
+		nil,
+	} {
+		p.baseTransport = &tlsStateTransport{cs: &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{leaf.cert, ca.cert},
+			OCSPResponse:     resp,
+		}}
+		p.runProbe(context.Background(), target, p.clientsForTarget(target), req, result)
+	}
+
+	assert.Equal(t, int64(3), result.total, "total")
+	assert.Equal(t, int64(1), result.success, "success")
+	for status, want := range map[string]int64{ocspGood: 1, ocspRevoked: 1, ocspMissing: 1, ocspUnknown: 0, ocspInvalid: 0} {
+		assert.Equal(t, want, result.ocspStatus.GetKey(status), status)
+	}
+}
@@ -149,7 +149,7 @@ func (ProbeConf_Method) EnumDescriptor() ([]byte, []int) {
 	return file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDescGZIP(), []int{0, 1}
 }
 
-// Next tag: 21
+// Next tag: 35
 type ProbeConf struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -179,8 +179,17 @@ type ProbeConf struct {
 	ResolveFirst *bool `protobuf:"varint,4,opt,name=resolve_first,json=resolveFirst" json:"resolve_first,omitempty"`
 	// Export response (body) count as a metric
 	ExportResponseAsMetrics *bool `protobuf:"varint,5,opt,name=export_response_as_metrics,json=exportResponseAsMetrics,def=0" json:"export_response_as_metrics,omitempty"`
-	// HTTP request method
+	// HTTP request method. Note that responses to HEAD requests don't have a
+	// body, so validators that check the response body (i.e. all validators
+	// except http_validator) can't be used with HEAD.
 	Method *ProbeConf_Method `protobuf:"varint,7,opt,name=method,enum=cloudprober.probes.http.ProbeConf_Method,def=0" json:"method,omitempty"`
+	// Custom HTTP request method, e.g. "PROPFIND". If set, it's used in place
+	// of the method above. It should be a valid HTTP token (RFC 9110).
+	CustomMethod *string `protobuf:"bytes,27,opt,name=custom_method,json=customMethod" json:"custom_method,omitempty"`
+	// If set, request method is exported as the "method" label of the probe
+	// metrics. It's off by default, as adding a label to the existing metrics
+	// creates new time series in most of the metrics backends.
+	ExportMethodLabel *bool `protobuf:"varint,34,opt,name=export_method_label,json=exportMethodLabel" json:"export_method_label,omitempty"`
 	// HTTP request headers
 	// It is recommended to use "header" instead of "headers" for new configs.
 	//
@@ -212,6 +221,18 @@ type ProbeConf struct {
 	KeepAlive *bool `protobuf:"varint,10,opt,name=keep_alive,json=keepAlive" json:"keep_alive,omitempty"`
 	// OAuth Config
 	OauthConfig *proto.Config `protobuf:"bytes,11,opt,name=oauth_config,json=oauthConfig" json:"oauth_config,omitempty"`
+	// Sign requests using AWS Signature Version 4, with the credentials from
+	// the default AWS credentials chain: environment, shared config and
+	// credentials files, and the instance or task role. Signing failures, e.g.
+	// if credentials can't be retrieved, are counted as "sign_failures", and
+	// the request is not sent.
+	// Example:
+	//
+	//	aws_sigv4 {
+	//	  service: "execute-api"
+	//	  region: "us-east-1"
+	//	}
+	AwsSigv4 *ProbeConf_AWSSigV4 `protobuf:"bytes,25,opt,name=aws_sigv4,json=awsSigv4" json:"aws_sigv4,omitempty"`
 	// Disable HTTP2
 	// Golang HTTP client automatically enables HTTP/2 if server supports it. This
 	// option disables that behavior to enforce HTTP/1.1 for testing purpose.
@@ -227,6 +248,90 @@ type ProbeConf struct {
 	DisableCertValidation *bool `protobuf:"varint,14,opt,name=disable_cert_validation,json=disableCertValidation" json:"disable_cert_validation,omitempty"`
 	// TLS config
 	TlsConfig *proto1.TLSConfig `protobuf:"bytes,15,opt,name=tls_config,json=tlsConfig" json:"tls_config,omitempty"`
+	// Check the OCSP response stapled by the server during the TLS handshake.
+	// If enabled, probe fails unless the stapled response is valid (signed by
+	// the certificate's issuer) and certificate's status in it is "good". OCSP
+	// statuses are exported as the "ocsp_status" metric, with the following
+	// keys: good, revoked, unknown, missing (no stapled response) and invalid
+	// (response couldn't be parsed or verified).
+	CheckOcspStapling       *bool                              `protobuf:"varint,26,opt,name=check_ocsp_stapling,json=checkOcspStapling" json:"check_ocsp_stapling,omitempty"`
+	CertificateTransparency *ProbeConf_CertificateTransparency `protobuf:"bytes,28,opt,name=certificate_transparency,json=certificateTransparency" json:"certificate_transparency,omitempty"`
+	// Success expression, for the success conditions that are hard to express
+	// using validators. It's an expr-lang/expr (https://expr-lang.org) boolean
+	// expression that's evaluated after the validators, with the following
+	// variables:
+	//
+	//	status_code: response status code, e.g. 200.
+	//	latency_ms:  request latency in milliseconds.
+	//	headers:     response headers, keyed by the canonical header name, e.g.
+	//	             headers["Content-Type"]. Multiple values are joined with
+	//	             a comma.
+	//	body:        response body, as a string.
+	//	json:        response body parsed as JSON, or nil if it's not a JSON.
+	//
+	// Request is successful only if the expression evaluates to true. Example:
+	//
+	//	success_expr: "status_code == 200 && json.status == 'ok' && latency_ms < 500"
+	//
+	// Expression errors at run time, e.g. accessing a field of nil, fail the
+	// request and are counted in the "success_expr_errors" metric.
+	SuccessExpr *string `protobuf:"bytes,29,opt,name=success_expr,json=successExpr" json:"success_expr,omitempty"`
+	// If set, failed requests are counted by their failure category, in the
+	// "failure_category" metric, e.g. failure_category{category="dns"}.
+	// Categories are:
+	//
+	//	dns:        DNS resolution failures.
+	//	connect:    connection failures, e.g. connection refused.
+	//	tls:        TLS handshake and certificate failures, including OCSP
+	//	            stapling and certificate transparency checks.
+	//	timeout:    request timeouts.
+	//	status:     validation failures with an HTTP error status (>= 400).
+	//	validation: other validation and success_expr failures.
+	//	other:      all other failures, e.g. request signing failures.
+	ExportFailureCategory *bool `protobuf:"varint,30,opt,name=export_failure_category,json=exportFailureCategory" json:"export_failure_category,omitempty"`
+	// If set, responses are counted by their negotiated protocol, in the
+	// "protocol" metric, e.g. protocol{proto="HTTP/2.0"}. It's useful to verify
+	// that targets are probed over the expected protocol, e.g. when testing
+	// HTTP/2 behind a load balancer that negotiates it through ALPN.
+	//
+	// Note that connections are never coalesced across targets: each target
+	// (and each of its requests_per_probe clients) uses its own transport, and
+	// Go's HTTP/2 client doesn't reuse a connection for a different hostname,
+	// even if it resolves to the same IP. Connections are reused across probe
+	// runs only if keep_alive is set.
+	ExportProtocol *bool `protobuf:"varint,31,opt,name=export_protocol,json=exportProtocol" json:"export_protocol,omitempty"`
+	// If set, response body is decompressed, as per its Content-Encoding
+	// header, before being used by the validators, success_expr and response
+	// metrics. Supported encodings are gzip, deflate and br. If request doesn't
+	// set the Accept-Encoding header, it's set to "gzip, deflate, br".
+	//
+	// Response size, as received, and decompressed response size are exported
+	// as the "resp_bytes" and "resp_decompressed_bytes" cumulative metrics.
+	DecompressResponse *bool `protobuf:"varint,32,opt,name=decompress_response,json=decompressResponse" json:"decompress_response,omitempty"`
+	// Maximum decompressed response size. Responses that decompress to a bigger
+	// size, e.g. decompression bombs, are considered failures.
+	MaxDecompressedSizeBytes *int64 `protobuf:"varint,33,opt,name=max_decompressed_size_bytes,json=maxDecompressedSizeBytes,def=10485760" json:"max_decompressed_size_bytes,omitempty"`
+	// Per-target client certificates. Certificate for a target is selected
+	// using the target's client_cert_label label, or by matching target's name
+	// against target_name_regex. If no certificate is selected, client
+	// certificate from tls_config (if any) is used.
+	// Certificates are loaded when the probe is initialized, so that errors are
+	// caught early, and reloaded when cert or key file changes.
+	// Example:
+	//
+	//	client_cert {
+	//	  name: "team-a"
+	//	  tls_cert_file: "/certs/team-a.crt"
+	//	  tls_key_file: "/certs/team-a.key"
+	//	  target_name_regex: ".*\\.team-a\\.svc"
+	//	}
+	ClientCert []*ProbeConf_ClientCert `protobuf:"bytes,22,rep,name=client_cert,json=clientCert" json:"client_cert,omitempty"`
+	// Target label that specifies the name of the client certificate to use.
+	ClientCertLabel *string `protobuf:"bytes,23,opt,name=client_cert_label,json=clientCertLabel,def=client_cert" json:"client_cert_label,omitempty"`
+	// Name of the custom dialer to make connections with. Custom dialers are
+	// registered using probes.RegisterDialer(), e.g. by the programs that embed
+	// cloudprober. Note that source IP setting doesn't apply to custom dialers.
+	Dialer *string `protobuf:"bytes,24,opt,name=dialer" json:"dialer,omitempty"`
 	// Proxy URL, e.g. http://myproxy:3128
 	ProxyUrl *string `protobuf:"bytes,16,opt,name=proxy_url,json=proxyUrl" json:"proxy_url,omitempty"`
 	// User agent. Default user agent is Go's default user agent.
@@ -260,6 +365,8 @@ const (
 	Default_ProbeConf_Scheme                     = ProbeConf_HTTP
 	Default_ProbeConf_ExportResponseAsMetrics    = bool(false)
 	Default_ProbeConf_Method                     = ProbeConf_GET
+	Default_ProbeConf_MaxDecompressedSizeBytes   = int64(10485760)
+	Default_ProbeConf_ClientCertLabel            = string("client_cert")
 	Default_ProbeConf_MaxIdleConns               = int32(256)
 	Default_ProbeConf_IntervalBetweenTargetsMsec = int32(10)
 	Default_ProbeConf_RequestsPerProbe           = int32(1)
@@ -354,6 +461,20 @@ func (x *ProbeConf) GetMethod() ProbeConf_Method {
 	return Default_ProbeConf_Method
 }
 
+func (x *ProbeConf) GetCustomMethod() string {
+	if x != nil && x.CustomMethod != nil {
+		return *x.CustomMethod
+	}
+	return ""
+}
+
+func (x *ProbeConf) GetExportMethodLabel() bool {
+	if x != nil && x.ExportMethodLabel != nil {
+		return *x.ExportMethodLabel
+	}
+	return false
+}
+
 func (x *ProbeConf) GetHeaders() []*ProbeConf_Header {
 	if x != nil {
 		return x.Headers
@@ -389,6 +510,13 @@ func (x *ProbeConf) GetOauthConfig() *proto.Config {
 	return nil
 }
 
+func (x *ProbeConf) GetAwsSigv4() *ProbeConf_AWSSigV4 {
+	if x != nil {
+		return x.AwsSigv4
+	}
+	return nil
+}
+
 func (x *ProbeConf) GetDisableHttp2() bool {
 	if x != nil && x.DisableHttp2 != nil {
 		return *x.DisableHttp2
@@ -410,6 +538,76 @@ func (x *ProbeConf) GetTlsConfig() *proto1.TLSConfig {
 	return nil
 }
 
+func (x *ProbeConf) GetCheckOcspStapling() bool {
+	if x != nil && x.CheckOcspStapling != nil {
+		return *x.CheckOcspStapling
+	}
+	return false
+}
+
+func (x *ProbeConf) GetCertificateTransparency() *ProbeConf_CertificateTransparency {
+	if x != nil {
+		return x.CertificateTransparency
+	}
+	return nil
+}
+
+func (x *ProbeConf) GetSuccessExpr() string {
+	if x != nil && x.SuccessExpr != nil {
+		return *x.SuccessExpr
+	}
+	return ""
+}
+
+func (x *ProbeConf) GetExportFailureCategory() bool {
+	if x != nil && x.ExportFailureCategory != nil {
+		return *x.ExportFailureCategory
+	}
+	return false
+}
+
+func (x *ProbeConf) GetExportProtocol() bool {
+	if x != nil && x.ExportProtocol != nil {
+		return *x.ExportProtocol
+	}
+	return false
+}
+
+func (x *ProbeConf) GetDecompressResponse() bool {
+	if x != nil && x.DecompressResponse != nil {
+		return *x.DecompressResponse
+	}
+	return false
+}
+
+func (x *ProbeConf) GetMaxDecompressedSizeBytes() int64 {
+	if x != nil && x.MaxDecompressedSizeBytes != nil {
+		return *x.MaxDecompressedSizeBytes
+	}
+	return Default_ProbeConf_MaxDecompressedSizeBytes
+}
+
+func (x *ProbeConf) GetClientCert() []*ProbeConf_ClientCert {
+	if x != nil {
+		return x.ClientCert
+	}
+	return nil
+}
+
+func (x *ProbeConf) GetClientCertLabel() string {
+	if x != nil && x.ClientCertLabel != nil {
+		return *x.ClientCertLabel
+	}
+	return Default_ProbeConf_ClientCertLabel
+}
+
+func (x *ProbeConf) GetDialer() string {
+	if x != nil && x.Dialer != nil {
+		return *x.Dialer
+	}
+	return ""
+}
+
 func (x *ProbeConf) GetProxyUrl() string {
 	if x != nil && x.ProxyUrl != nil {
 		return *x.ProxyUrl
@@ -530,6 +728,203 @@ func (x *ProbeConf_Header) GetValue() string {
 	return ""
 }
 
+type ProbeConf_AWSSigV4 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// AWS service to sign the requests for, e.g. "execute-api" for the API
+	// Gateway endpoints.
+	Service *string `protobuf:"bytes,1,req,name=service" json:"service,omitempty"`
+	// AWS region of the endpoint, e.g. "us-east-1". If not specified, region
+	// is taken from the ambient AWS config, e.g. AWS_REGION environment
+	// variable.
+	Region *string `protobuf:"bytes,2,opt,name=region" json:"region,omitempty"`
+}
+
+func (x *ProbeConf_AWSSigV4) Reset() {
+	*x = ProbeConf_AWSSigV4{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProbeConf_AWSSigV4) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeConf_AWSSigV4) ProtoMessage() {}
+
+func (x *ProbeConf_AWSSigV4) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeConf_AWSSigV4.ProtoReflect.Descriptor instead.
+func (*ProbeConf_AWSSigV4) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDescGZIP(), []int{0, 2}
+}
+
+func (x *ProbeConf_AWSSigV4) GetService() string {
+	if x != nil && x.Service != nil {
+		return *x.Service
+	}
+	return ""
+}
+
+func (x *ProbeConf_AWSSigV4) GetRegion() string {
+	if x != nil && x.Region != nil {
+		return *x.Region
+	}
+	return ""
+}
+
+// Certificate Transparency (CT) check. If configured, probe verifies that
+// the server's certificate comes with at least min_scts Signed Certificate
+// Timestamps (SCTs), either embedded in the certificate or delivered in the
+// TLS handshake. The number of SCTs found is exported as the "sct_count"
+// metric. Set min_scts to 0 to only export the metric.
+// It cannot be used with the http scheme.
+// Note that SCT signatures are not verified against the CT logs' keys.
+type ProbeConf_CertificateTransparency struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MinScts *int32 `protobuf:"varint,1,opt,name=min_scts,json=minScts,def=1" json:"min_scts,omitempty"`
+}
+
+// Default values for ProbeConf_CertificateTransparency fields.
+const (
+	Default_ProbeConf_CertificateTransparency_MinScts = int32(1)
+)
+
+func (x *ProbeConf_CertificateTransparency) Reset() {
+	*x = ProbeConf_CertificateTransparency{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProbeConf_CertificateTransparency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeConf_CertificateTransparency) ProtoMessage() {}
+
+func (x *ProbeConf_CertificateTransparency) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeConf_CertificateTransparency.ProtoReflect.Descriptor instead.
+func (*ProbeConf_CertificateTransparency) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDescGZIP(), []int{0, 3}
+}
+
+func (x *ProbeConf_CertificateTransparency) GetMinScts() int32 {
+	if x != nil && x.MinScts != nil {
+		return *x.MinScts
+	}
+	return Default_ProbeConf_CertificateTransparency_MinScts
+}
+
+// Client certificate for mutual TLS, selected per target.
+type ProbeConf_ClientCert struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Certificate name. It's used to refer to the certificate from the
+	// target's label, and is exported as the "client_cert" label for the
+	// targets that use this certificate.
+	Name *string `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	// Certificate and private key files.
+	TlsCertFile *string `protobuf:"bytes,2,req,name=tls_cert_file,json=tlsCertFile" json:"tls_cert_file,omitempty"`
+	TlsKeyFile  *string `protobuf:"bytes,3,req,name=tls_key_file,json=tlsKeyFile" json:"tls_key_file,omitempty"`
+	// Targets, matched by their name, that should use this certificate.
+	// Target label (see client_cert_label below) takes precedence over this.
+	TargetNameRegex *string `protobuf:"bytes,4,opt,name=target_name_regex,json=targetNameRegex" json:"target_name_regex,omitempty"`
+}
+
+func (x *ProbeConf_ClientCert) Reset() {
+	*x = ProbeConf_ClientCert{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProbeConf_ClientCert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeConf_ClientCert) ProtoMessage() {}
+
+func (x *ProbeConf_ClientCert) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeConf_ClientCert.ProtoReflect.Descriptor instead.
+func (*ProbeConf_ClientCert) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDescGZIP(), []int{0, 4}
+}
+
+func (x *ProbeConf_ClientCert) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *ProbeConf_ClientCert) GetTlsCertFile() string {
+	if x != nil && x.TlsCertFile != nil {
+		return *x.TlsCertFile
+	}
+	return ""
+}
+
+func (x *ProbeConf_ClientCert) GetTlsKeyFile() string {
+	if x != nil && x.TlsKeyFile != nil {
+		return *x.TlsKeyFile
+	}
+	return ""
+}
+
+func (x *ProbeConf_ClientCert) GetTargetNameRegex() string {
+	if x != nil && x.TargetNameRegex != nil {
+		return *x.TargetNameRegex
+	}
+	return ""
+}
+
 var File_github_com_cloudprober_cloudprober_probes_http_proto_config_proto protoreflect.FileDescriptor
 
 var file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDesc = []byte{
@@ -547,7 +942,7 @@ var file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDe
 	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64,
 	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f,
 	0x74, 0x6c, 0x73, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f,
-	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd7, 0x0a, 0x0a,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc8, 0x12, 0x0a,
 	0x09, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x12, 0x4d, 0x0a, 0x08, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x29, 0x2e, 0x63,
 	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65,
@@ -572,71 +967,134 @@ var file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDe
 	0x20, 0x01, 0x28, 0x0e, 0x32, 0x29, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62,
 	0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x68, 0x74, 0x74, 0x70, 0x2e, 0x50,
 	0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x3a,
-	0x03, 0x47, 0x45, 0x54, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x43, 0x0a, 0x07,
-	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e,
-	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62,
-	0x65, 0x73, 0x2e, 0x68, 0x74, 0x74, 0x70, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e,
-	0x66, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
-	0x73, 0x12, 0x46, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x14, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x2e, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e,
+	0x03, 0x47, 0x45, 0x54, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x23, 0x0a, 0x0d,
+	0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x1b, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x4d, 0x65, 0x74, 0x68, 0x6f,
+	0x64, 0x12, 0x2e, 0x0a, 0x13, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x6d, 0x65, 0x74, 0x68,
+	0x6f, 0x64, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x22, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11,
+	0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4c, 0x61, 0x62, 0x65,
+	0x6c, 0x12, 0x43, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x08, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x29, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
+	0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x68, 0x74, 0x74, 0x70, 0x2e, 0x50, 0x72, 0x6f,
+	0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x07, 0x68,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x46, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x18, 0x14, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x68, 0x74, 0x74, 0x70,
+	0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x12,
+	0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f,
+	0x64, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x6b, 0x65, 0x65, 0x70, 0x5f, 0x61, 0x6c, 0x69, 0x76, 0x65,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x6b, 0x65, 0x65, 0x70, 0x41, 0x6c, 0x69, 0x76,
+	0x65, 0x12, 0x3c, 0x0a, 0x0c, 0x6f, 0x61, 0x75, 0x74, 0x68, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70,
+	0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x6f, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x52, 0x0b, 0x6f, 0x61, 0x75, 0x74, 0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x48, 0x0a, 0x09, 0x61, 0x77, 0x73, 0x5f, 0x73, 0x69, 0x67, 0x76, 0x34, 0x18, 0x19, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
+	0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x68, 0x74, 0x74, 0x70, 0x2e, 0x50, 0x72, 0x6f,
+	0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x2e, 0x41, 0x57, 0x53, 0x53, 0x69, 0x67, 0x56, 0x34, 0x52,
+	0x08, 0x61, 0x77, 0x73, 0x53, 0x69, 0x67, 0x76, 0x34, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x69, 0x73,
+	0x61, 0x62, 0x6c, 0x65, 0x5f, 0x68, 0x74, 0x74, 0x70, 0x32, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0c, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x48, 0x74, 0x74, 0x70, 0x32, 0x12, 0x36,
+	0x0a, 0x17, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x5f, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x15, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x43, 0x65, 0x72, 0x74, 0x56, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3f, 0x0a, 0x0a, 0x74, 0x6c, 0x73, 0x5f, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x74, 0x6c, 0x73, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x2e, 0x54, 0x4c, 0x53, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x09, 0x74, 0x6c,
+	0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x2e, 0x0a, 0x13, 0x63, 0x68, 0x65, 0x63, 0x6b,
+	0x5f, 0x6f, 0x63, 0x73, 0x70, 0x5f, 0x73, 0x74, 0x61, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x18, 0x1a,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x4f, 0x63, 0x73, 0x70, 0x53,
+	0x74, 0x61, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x12, 0x75, 0x0a, 0x18, 0x63, 0x65, 0x72, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x61, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3a, 0x2e, 0x63, 0x6c, 0x6f, 0x75,
+	0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x68,
+	0x74, 0x74, 0x70, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x2e, 0x43, 0x65,
+	0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x61,
+	0x72, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x17, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x21,
+	0x0a, 0x0c, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x65, 0x78, 0x70, 0x72, 0x18, 0x1d,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x45, 0x78, 0x70,
+	0x72, 0x12, 0x36, 0x0a, 0x17, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x66, 0x61, 0x69, 0x6c,
+	0x75, 0x72, 0x65, 0x5f, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x1e, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x15, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72,
+	0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x78, 0x70,
+	0x6f, 0x72, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x1f, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0e, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x6f, 0x6c, 0x12, 0x2f, 0x0a, 0x13, 0x64, 0x65, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x20, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x12, 0x64, 0x65, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x1b, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x65, 0x63, 0x6f, 0x6d,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x21, 0x20, 0x01, 0x28, 0x03, 0x3a, 0x08, 0x31, 0x30, 0x34, 0x38, 0x35, 0x37,
+	0x36, 0x30, 0x52, 0x18, 0x6d, 0x61, 0x78, 0x44, 0x65, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73,
+	0x73, 0x65, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x4e, 0x0a, 0x0b,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x18, 0x16, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x2d, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e,
 	0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x68, 0x74, 0x74, 0x70, 0x2e, 0x50, 0x72, 0x6f, 0x62,
-	0x65, 0x43, 0x6f, 0x6e, 0x66, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72,
-	0x79, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64,
-	0x79, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x12, 0x1d, 0x0a,
-	0x0a, 0x6b, 0x65, 0x65, 0x70, 0x5f, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x09, 0x6b, 0x65, 0x65, 0x70, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x12, 0x3c, 0x0a, 0x0c,
-	0x6f, 0x61, 0x75, 0x74, 0x68, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x0b, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
-	0x2e, 0x6f, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0b, 0x6f,
-	0x61, 0x75, 0x74, 0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x69,
-	0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x68, 0x74, 0x74, 0x70, 0x32, 0x18, 0x0d, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x0c, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x48, 0x74, 0x74, 0x70, 0x32, 0x12,
-	0x36, 0x0a, 0x17, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x5f,
-	0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x15, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x43, 0x65, 0x72, 0x74, 0x56, 0x61, 0x6c,
-	0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3f, 0x0a, 0x0a, 0x74, 0x6c, 0x73, 0x5f, 0x63,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x63, 0x6c,
-	0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x74, 0x6c, 0x73, 0x63, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x2e, 0x54, 0x4c, 0x53, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x09, 0x74,
-	0x6c, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x78,
-	0x79, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f,
-	0x78, 0x79, 0x55, 0x72, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x61, 0x67,
-	0x65, 0x6e, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x75, 0x73, 0x65, 0x72, 0x41,
-	0x67, 0x65, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x0e, 0x6d, 0x61, 0x78, 0x5f, 0x69, 0x64, 0x6c, 0x65,
-	0x5f, 0x63, 0x6f, 0x6e, 0x6e, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x03, 0x32, 0x35,
-	0x36, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x49, 0x64, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x73, 0x12,
-	0x23, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x73,
-	0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x52, 0x65, 0x64, 0x69, 0x72,
-	0x65, 0x63, 0x74, 0x73, 0x12, 0x45, 0x0a, 0x1d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c,
-	0x5f, 0x62, 0x65, 0x74, 0x77, 0x65, 0x65, 0x6e, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73,
-	0x5f, 0x6d, 0x73, 0x65, 0x63, 0x18, 0x61, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x02, 0x31, 0x30, 0x52,
-	0x1a, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x42, 0x65, 0x74, 0x77, 0x65, 0x65, 0x6e,
-	0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x4d, 0x73, 0x65, 0x63, 0x12, 0x2f, 0x0a, 0x12, 0x72,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x70, 0x72, 0x6f, 0x62,
-	0x65, 0x18, 0x62, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x01, 0x31, 0x52, 0x10, 0x72, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x73, 0x50, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x37, 0x0a, 0x16,
-	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
-	0x6c, 0x5f, 0x6d, 0x73, 0x65, 0x63, 0x18, 0x63, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x01, 0x30, 0x52,
-	0x14, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
-	0x6c, 0x4d, 0x73, 0x65, 0x63, 0x1a, 0x32, 0x0a, 0x06, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12,
-	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x1a, 0x39, 0x0a, 0x0b, 0x48, 0x65, 0x61,
-	0x64, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x3a, 0x02, 0x38, 0x01, 0x22, 0x1d, 0x0a, 0x06, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x12, 0x08,
-	0x0a, 0x04, 0x48, 0x54, 0x54, 0x50, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x48, 0x54, 0x54, 0x50,
-	0x53, 0x10, 0x01, 0x22, 0x52, 0x0a, 0x06, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x07, 0x0a,
-	0x03, 0x47, 0x45, 0x54, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x50, 0x4f, 0x53, 0x54, 0x10, 0x01,
-	0x12, 0x07, 0x0a, 0x03, 0x50, 0x55, 0x54, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x45, 0x41,
-	0x44, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x10, 0x04, 0x12,
-	0x09, 0x0a, 0x05, 0x50, 0x41, 0x54, 0x43, 0x48, 0x10, 0x05, 0x12, 0x0b, 0x0a, 0x07, 0x4f, 0x50,
-	0x54, 0x49, 0x4f, 0x4e, 0x53, 0x10, 0x06, 0x42, 0x0d, 0x0a, 0x0b, 0x73, 0x63, 0x68, 0x65, 0x6d,
-	0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x42, 0x36, 0x5a, 0x34, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
-	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
-	0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f,
-	0x62, 0x65, 0x73, 0x2f, 0x68, 0x74, 0x74, 0x70, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x65, 0x43, 0x6f, 0x6e, 0x66, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x65, 0x72, 0x74,
+	0x52, 0x0a, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x65, 0x72, 0x74, 0x12, 0x37, 0x0a, 0x11,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x5f, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x18, 0x17, 0x20, 0x01, 0x28, 0x09, 0x3a, 0x0b, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f,
+	0x63, 0x65, 0x72, 0x74, 0x52, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x65, 0x72, 0x74,
+	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x61, 0x6c, 0x65, 0x72, 0x18,
+	0x18, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x69, 0x61, 0x6c, 0x65, 0x72, 0x12, 0x1b, 0x0a,
+	0x09, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x55, 0x72, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x73,
+	0x65, 0x72, 0x5f, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x75, 0x73, 0x65, 0x72, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x0e, 0x6d, 0x61, 0x78,
+	0x5f, 0x69, 0x64, 0x6c, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x6e, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28,
+	0x05, 0x3a, 0x03, 0x32, 0x35, 0x36, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x49, 0x64, 0x6c, 0x65, 0x43,
+	0x6f, 0x6e, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x64, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x73, 0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x6d, 0x61, 0x78,
+	0x52, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x73, 0x12, 0x45, 0x0a, 0x1d, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x62, 0x65, 0x74, 0x77, 0x65, 0x65, 0x6e, 0x5f, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x73, 0x5f, 0x6d, 0x73, 0x65, 0x63, 0x18, 0x61, 0x20, 0x01, 0x28, 0x05,
+	0x3a, 0x02, 0x31, 0x30, 0x52, 0x1a, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x42, 0x65,
+	0x74, 0x77, 0x65, 0x65, 0x6e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x4d, 0x73, 0x65, 0x63,
+	0x12, 0x2f, 0x0a, 0x12, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x5f, 0x70, 0x65, 0x72,
+	0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x62, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x01, 0x31, 0x52,
+	0x10, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x50, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x62,
+	0x65, 0x12, 0x37, 0x0a, 0x16, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x5f, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x6d, 0x73, 0x65, 0x63, 0x18, 0x63, 0x20, 0x01, 0x28,
+	0x05, 0x3a, 0x01, 0x30, 0x52, 0x14, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x65, 0x63, 0x1a, 0x32, 0x0a, 0x06, 0x48, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x1a, 0x39,
+	0x0a, 0x0b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3c, 0x0a, 0x08, 0x41, 0x57, 0x53,
+	0x53, 0x69, 0x67, 0x56, 0x34, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x1a, 0x37, 0x0a, 0x17, 0x43, 0x65, 0x72, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x61, 0x72, 0x65, 0x6e,
+	0x63, 0x79, 0x12, 0x1c, 0x0a, 0x08, 0x6d, 0x69, 0x6e, 0x5f, 0x73, 0x63, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x3a, 0x01, 0x31, 0x52, 0x07, 0x6d, 0x69, 0x6e, 0x53, 0x63, 0x74, 0x73,
+	0x1a, 0x92, 0x01, 0x0a, 0x0a, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x65, 0x72, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0d, 0x74, 0x6c, 0x73, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x5f,
+	0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x02, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x6c, 0x73, 0x43,
+	0x65, 0x72, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x0c, 0x74, 0x6c, 0x73, 0x5f, 0x6b,
+	0x65, 0x79, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x02, 0x28, 0x09, 0x52, 0x0a, 0x74,
+	0x6c, 0x73, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65,
+	0x52, 0x65, 0x67, 0x65, 0x78, 0x22, 0x1d, 0x0a, 0x06, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x12,
+	0x08, 0x0a, 0x04, 0x48, 0x54, 0x54, 0x50, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x48, 0x54, 0x54,
+	0x50, 0x53, 0x10, 0x01, 0x22, 0x52, 0x0a, 0x06, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x07,
+	0x0a, 0x03, 0x47, 0x45, 0x54, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x50, 0x4f, 0x53, 0x54, 0x10,
+	0x01, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x55, 0x54, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x45,
+	0x41, 0x44, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x10, 0x04,
+	0x12, 0x09, 0x0a, 0x05, 0x50, 0x41, 0x54, 0x43, 0x48, 0x10, 0x05, 0x12, 0x0b, 0x0a, 0x07, 0x4f,
+	0x50, 0x54, 0x49, 0x4f, 0x4e, 0x53, 0x10, 0x06, 0x42, 0x0d, 0x0a, 0x0b, 0x73, 0x63, 0x68, 0x65,
+	0x6d, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x42, 0x36, 0x5a, 0x34, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65,
+	0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x73, 0x2f, 0x68, 0x74, 0x74, 0x70, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 }
 
 var (
@@ -652,29 +1110,35 @@ func file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawD
 }
 
 var file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_goTypes = []interface{}{
-	(ProbeConf_Scheme)(0),    // 0: cloudprober.probes.http.ProbeConf.Scheme
-	(ProbeConf_Method)(0),    // 1: cloudprober.probes.http.ProbeConf.Method
-	(*ProbeConf)(nil),        // 2: cloudprober.probes.http.ProbeConf
-	(*ProbeConf_Header)(nil), // 3: cloudprober.probes.http.ProbeConf.Header
-	nil,                      // 4: cloudprober.probes.http.ProbeConf.HeaderEntry
-	(*proto.Config)(nil),     // 5: cloudprober.oauth.Config
-	(*proto1.TLSConfig)(nil), // 6: cloudprober.tlsconfig.TLSConfig
+	(ProbeConf_Scheme)(0),      // 0: cloudprober.probes.http.ProbeConf.Scheme
+	(ProbeConf_Method)(0),      // 1: cloudprober.probes.http.ProbeConf.Method
+	(*ProbeConf)(nil),          // 2: cloudprober.probes.http.ProbeConf
+	(*ProbeConf_Header)(nil),   // 3: cloudprober.probes.http.ProbeConf.Header
+	nil,                        // 4: cloudprober.probes.http.ProbeConf.HeaderEntry
+	(*ProbeConf_AWSSigV4)(nil), // 5: cloudprober.probes.http.ProbeConf.AWSSigV4
+	(*ProbeConf_CertificateTransparency)(nil), // 6: cloudprober.probes.http.ProbeConf.CertificateTransparency
+	(*ProbeConf_ClientCert)(nil),              // 7: cloudprober.probes.http.ProbeConf.ClientCert
+	(*proto.Config)(nil),                      // 8: cloudprober.oauth.Config
+	(*proto1.TLSConfig)(nil),                  // 9: cloudprober.tlsconfig.TLSConfig
 }
 var file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_depIdxs = []int32{
-	0, // 0: cloudprober.probes.http.ProbeConf.protocol:type_name -> cloudprober.probes.http.ProbeConf.Scheme
-	0, // 1: cloudprober.probes.http.ProbeConf.scheme:type_name -> cloudprober.probes.http.ProbeConf.Scheme
-	1, // 2: cloudprober.probes.http.ProbeConf.method:type_name -> cloudprober.probes.http.ProbeConf.Method
-	3, // 3: cloudprober.probes.http.ProbeConf.headers:type_name -> cloudprober.probes.http.ProbeConf.Header
-	4, // 4: cloudprober.probes.http.ProbeConf.header:type_name -> cloudprober.probes.http.ProbeConf.HeaderEntry
-	5, // 5: cloudprober.probes.http.ProbeConf.oauth_config:type_name -> cloudprober.oauth.Config
-	6, // 6: cloudprober.probes.http.ProbeConf.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
-	7, // [7:7] is the sub-list for method output_type
-	7, // [7:7] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	7, // [7:7] is the sub-list for extension extendee
-	0, // [0:7] is the sub-list for field type_name
+	0,  // 0: cloudprober.probes.http.ProbeConf.protocol:type_name -> cloudprober.probes.http.ProbeConf.Scheme
+	0,  // 1: cloudprober.probes.http.ProbeConf.scheme:type_name -> cloudprober.probes.http.ProbeConf.Scheme
+	1,  // 2: cloudprober.probes.http.ProbeConf.method:type_name -> cloudprober.probes.http.ProbeConf.Method
+	3,  // 3: cloudprober.probes.http.ProbeConf.headers:type_name -> cloudprober.probes.http.ProbeConf.Header
+	4,  // 4: cloudprober.probes.http.ProbeConf.header:type_name -> cloudprober.probes.http.ProbeConf.HeaderEntry
+	8,  // 5: cloudprober.probes.http.ProbeConf.oauth_config:type_name -> cloudprober.oauth.Config
+	5,  // 6: cloudprober.probes.http.ProbeConf.aws_sigv4:type_name -> cloudprober.probes.http.ProbeConf.AWSSigV4
+	9,  // 7: cloudprober.probes.http.ProbeConf.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
+	6,  // 8: cloudprober.probes.http.ProbeConf.certificate_transparency:type_name -> cloudprober.probes.http.ProbeConf.CertificateTransparency
+	7,  // 9: cloudprober.probes.http.ProbeConf.client_cert:type_name -> cloudprober.probes.http.ProbeConf.ClientCert
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_init() }
@@ -707,6 +1171,42 @@ func file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_init
 				return nil
 			}
 		}
+		file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProbeConf_AWSSigV4); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProbeConf_CertificateTransparency); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProbeConf_ClientCert); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_msgTypes[0].OneofWrappers = []interface{}{
 		(*ProbeConf_Protocol)(nil),
@@ -718,7 +1218,7 @@ func file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_init
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDesc,
 			NumEnums:      2,
-			NumMessages:   3,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
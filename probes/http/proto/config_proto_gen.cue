@@ -5,7 +5,7 @@ import (
 	proto_1 "github.com/cloudprober/cloudprober/internal/tlsconfig/proto"
 )
 
-// Next tag: 21
+// Next tag: 35
 #ProbeConf: {
 	#Scheme: {"HTTP", #enumValue: 0} |
 		{"HTTPS", #enumValue: 1}
@@ -66,9 +66,20 @@ import (
 	// Export response (body) count as a metric
 	exportResponseAsMetrics?: bool @protobuf(5,bool,name=export_response_as_metrics,"default=false")
 
-	// HTTP request method
+	// HTTP request method. Note that responses to HEAD requests don't have a
+	// body, so validators that check the response body (i.e. all validators
+	// except http_validator) can't be used with HEAD.
 	method?: #Method @protobuf(7,Method,"default=GET")
 
+	// Custom HTTP request method, e.g. "PROPFIND". If set, it's used in place
+	// of the method above. It should be a valid HTTP token (RFC 9110).
+	customMethod?: string @protobuf(27,string,name=custom_method)
+
+	// If set, request method is exported as the "method" label of the probe
+	// metrics. It's off by default, as adding a label to the existing metrics
+	// creates new time series in most of the metrics backends.
+	exportMethodLabel?: bool @protobuf(34,bool,name=export_method_label)
+
 	// HTTP request headers
 	// It is recommended to use "header" instead of "headers" for new configs.
 	// header {
@@ -103,6 +114,29 @@ import (
 	// OAuth Config
 	oauthConfig?: proto.#Config @protobuf(11,oauth.Config,name=oauth_config)
 
+	#AWSSigV4: {
+		// AWS service to sign the requests for, e.g. "execute-api" for the API
+		// Gateway endpoints.
+		service?: string @protobuf(1,string)
+
+		// AWS region of the endpoint, e.g. "us-east-1". If not specified, region
+		// is taken from the ambient AWS config, e.g. AWS_REGION environment
+		// variable.
+		region?: string @protobuf(2,string)
+	}
+
+	// Sign requests using AWS Signature Version 4, with the credentials from
+	// the default AWS credentials chain: environment, shared config and
+	// credentials files, and the instance or task role. Signing failures, e.g.
+	// if credentials can't be retrieved, are counted as "sign_failures", and
+	// the request is not sent.
+	// Example:
+	//   aws_sigv4 {
+	//     service: "execute-api"
+	//     region: "us-east-1"
+	//   }
+	awsSigv4?: #AWSSigV4 @protobuf(25,AWSSigV4,name=aws_sigv4)
+
 	// Disable HTTP2
 	// Golang HTTP client automatically enables HTTP/2 if server supports it. This
 	// option disables that behavior to enforce HTTP/1.1 for testing purpose.
@@ -120,6 +154,120 @@ import (
 	// TLS config
 	tlsConfig?: proto_1.#TLSConfig @protobuf(15,tlsconfig.TLSConfig,name=tls_config)
 
+	// Check the OCSP response stapled by the server during the TLS handshake.
+	// If enabled, probe fails unless the stapled response is valid (signed by
+	// the certificate's issuer) and certificate's status in it is "good". OCSP
+	// statuses are exported as the "ocsp_status" metric, with the following
+	// keys: good, revoked, unknown, missing (no stapled response) and invalid
+	// (response couldn't be parsed or verified).
+	checkOcspStapling?: bool @protobuf(26,bool,name=check_ocsp_stapling)
+
+	// Certificate Transparency (CT) check. If configured, probe verifies that
+	// the server's certificate comes with at least min_scts Signed Certificate
+	// Timestamps (SCTs), either embedded in the certificate or delivered in the
+	// TLS handshake. The number of SCTs found is exported as the "sct_count"
+	// metric. Set min_scts to 0 to only export the metric.
+	// It cannot be used with the http scheme.
+	// Note that SCT signatures are not verified against the CT logs' keys.
+	#CertificateTransparency: {
+		minScts?: int32 @protobuf(1,int32,name=min_scts,"default=1")
+	}
+	certificateTransparency?: #CertificateTransparency @protobuf(28,CertificateTransparency,name=certificate_transparency)
+
+	// Success expression, for the success conditions that are hard to express
+	// using validators. It's an expr-lang/expr (https://expr-lang.org) boolean
+	// expression that's evaluated after the validators, with the following
+	// variables:
+	//   status_code: response status code, e.g. 200.
+	//   latency_ms:  request latency in milliseconds.
+	//   headers:     response headers, keyed by the canonical header name, e.g.
+	//                headers["Content-Type"]. Multiple values are joined with
+	//                a comma.
+	//   body:        response body, as a string.
+	//   json:        response body parsed as JSON, or nil if it's not a JSON.
+	// Request is successful only if the expression evaluates to true. Example:
+	//   success_expr: "status_code == 200 && json.status == 'ok' && latency_ms < 500"
+	// Expression errors at run time, e.g. accessing a field of nil, fail the
+	// request and are counted in the "success_expr_errors" metric.
+	successExpr?: string @protobuf(29,string,name=success_expr)
+
+	// If set, failed requests are counted by their failure category, in the
+	// "failure_category" metric, e.g. failure_category{category="dns"}.
+	// Categories are:
+	//   dns:        DNS resolution failures.
+	//   connect:    connection failures, e.g. connection refused.
+	//   tls:        TLS handshake and certificate failures, including OCSP
+	//               stapling and certificate transparency checks.
+	//   timeout:    request timeouts.
+	//   status:     validation failures with an HTTP error status (>= 400).
+	//   validation: other validation and success_expr failures.
+	//   other:      all other failures, e.g. request signing failures.
+	exportFailureCategory?: bool @protobuf(30,bool,name=export_failure_category)
+
+	// If set, responses are counted by their negotiated protocol, in the
+	// "protocol" metric, e.g. protocol{proto="HTTP/2.0"}. It's useful to verify
+	// that targets are probed over the expected protocol, e.g. when testing
+	// HTTP/2 behind a load balancer that negotiates it through ALPN.
+	//
+	// Note that connections are never coalesced across targets: each target
+	// (and each of its requests_per_probe clients) uses its own transport, and
+	// Go's HTTP/2 client doesn't reuse a connection for a different hostname,
+	// even if it resolves to the same IP. Connections are reused across probe
+	// runs only if keep_alive is set.
+	exportProtocol?: bool @protobuf(31,bool,name=export_protocol)
+
+	// If set, response body is decompressed, as per its Content-Encoding
+	// header, before being used by the validators, success_expr and response
+	// metrics. Supported encodings are gzip, deflate and br. If request doesn't
+	// set the Accept-Encoding header, it's set to "gzip, deflate, br".
+	//
+	// Response size, as received, and decompressed response size are exported
+	// as the "resp_bytes" and "resp_decompressed_bytes" cumulative metrics.
+	decompressResponse?: bool @protobuf(32,bool,name=decompress_response)
+
+	// Maximum decompressed response size. Responses that decompress to a bigger
+	// size, e.g. decompression bombs, are considered failures.
+	maxDecompressedSizeBytes?: int64 @protobuf(33,int64,name=max_decompressed_size_bytes,"default=10485760")
+
+	// Client certificate for mutual TLS, selected per target.
+	#ClientCert: {
+		// Certificate name. It's used to refer to the certificate from the
+		// target's label, and is exported as the "client_cert" label for the
+		// targets that use this certificate.
+		name?: string @protobuf(1,string)
+
+		// Certificate and private key files.
+		tlsCertFile?: string @protobuf(2,string,name=tls_cert_file)
+		tlsKeyFile?:  string @protobuf(3,string,name=tls_key_file)
+
+		// Targets, matched by their name, that should use this certificate.
+		// Target label (see client_cert_label below) takes precedence over this.
+		targetNameRegex?: string @protobuf(4,string,name=target_name_regex)
+	}
+
+	// Per-target client certificates. Certificate for a target is selected
+	// using the target's client_cert_label label, or by matching target's name
+	// against target_name_regex. If no certificate is selected, client
+	// certificate from tls_config (if any) is used.
+	// Certificates are loaded when the probe is initialized, so that errors are
+	// caught early, and reloaded when cert or key file changes.
+	// Example:
+	//   client_cert {
+	//     name: "team-a"
+	//     tls_cert_file: "/certs/team-a.crt"
+	//     tls_key_file: "/certs/team-a.key"
+	//     target_name_regex: ".*\\.team-a\\.svc"
+	//   }
+	clientCert?: [...#ClientCert] @protobuf(22,ClientCert,name=client_cert)
+
+	// Target label that specifies the name of the client certificate to use.
+	clientCertLabel?: string @protobuf(23,string,name=client_cert_label,#"default="client_cert""#)
+
+	// Name of the custom dialer to make connections with. Custom dialers are
+	// registered using probes.RegisterDialer(), e.g. by the programs that embed
+	// cloudprober. Note that source IP setting doesn't apply to custom dialers.
+	dialer?: string @protobuf(24,string)
+
 	// Proxy URL, e.g. http://myproxy:3128
 	proxyUrl?: string @protobuf(16,string,name=proxy_url)
 
@@ -98,7 +98,7 @@ func (p *Probe) resolveFirst(target endpoint.Endpoint) bool {
 	if p.c.ResolveFirst != nil {
 		return p.c.GetResolveFirst()
 	}
-	return target.IP != nil
+	return p.resolveFirstAlways || target.IP != nil
 }
 
 // setHeaders computes setHeaders for a target. Host header is computed slightly
@@ -178,6 +178,9 @@ func (p *Probe) httpRequestForTarget(target endpoint.Endpoint) *http.Request {
 	if p.c.GetUserAgent() != "" {
 		req.Header.Set("User-Agent", p.c.GetUserAgent())
 	}
+	if p.c.GetDecompressResponse() && req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
 
 	return req
 }
@@ -201,15 +204,17 @@ func getToken(ts oauth2.TokenSource, l *logger.Logger) (string, error) {
 	return "", fmt.Errorf("got unknown token: %v", tok)
 }
 
-func (p *Probe) prepareRequest(req *http.Request) *http.Request {
+func (p *Probe) prepareRequest(req *http.Request) (*http.Request, error) {
 	// We clone the request for the cases where we modify the request:
 	//   -- if request has a body, each request gets its own Body
 	//      as HTTP transport reads body in a streaming fashion, and we can't
 	//      share it across multiple requests.
 	//   -- if OAuth token is used, each request gets its own Authorization
 	//      header.
-	if p.oauthTS == nil && p.requestBody.Len() == 0 {
-		return req
+	//   -- if requests are signed, each request gets its own signature
+	//      headers.
+	if p.oauthTS == nil && p.sigV4 == nil && p.requestBody.Len() == 0 {
+		return req, nil
 	}
 
 	req = req.Clone(req.Context())
@@ -228,5 +233,12 @@ func (p *Probe) prepareRequest(req *http.Request) *http.Request {
 
 	req.Body = p.requestBody.Reader()
 
-	return req
+	// Signing should be the last step, as signature covers the headers.
+	if p.sigV4 != nil {
+		if err := p.sigV4.sign(req); err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
 }
@@ -392,7 +392,8 @@ func TestPrepareRequest(t *testing.T) {
 			}
 
 			inReq, _ := httpreq.NewRequest("GET", "http://cloudprober.org", p.requestBody)
-			got := p.prepareRequest(inReq)
+			got, err := p.prepareRequest(inReq)
+			assert.NoError(t, err)
 
 			if tt.wantIsCloned != (inReq != got) {
 				t.Errorf("wantIsCloned=%v, (inReq != got) is %v", tt.wantIsCloned, inReq != got)
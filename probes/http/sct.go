@@ -0,0 +1,81 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/cryptobyte"
+)
+
+// oidSCTList is the OID of the X.509 extension that carries the embedded
+// SCTs (RFC 6962, section 3.3).
+var oidSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+
+// parseSCTList returns the SCTs in a TLS encoded SignedCertificateTimestampList.
+func parseSCTList(b []byte) ([][]byte, error) {
+	var list cryptobyte.String
+	s := cryptobyte.String(b)
+	if !s.ReadUint16LengthPrefixed(&list) || !s.Empty() {
+		return nil, errors.New("malformed SCT list")
+	}
+
+	var scts [][]byte
+	for !list.Empty() {
+		var sct cryptobyte.String
+		if !list.ReadUint16LengthPrefixed(&sct) || sct.Empty() {
+			return nil, errors.New("malformed SCT in the SCT list")
+		}
+		scts = append(scts, sct)
+	}
+	return scts, nil
+}
+
+// embeddedSCTs returns the SCTs embedded in the certificate.
+func embeddedSCTs(cert *x509.Certificate) ([][]byte, error) {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidSCTList) {
+			continue
+		}
+		var b []byte
+		if rest, err := asn1.Unmarshal(ext.Value, &b); err != nil || len(rest) != 0 {
+			return nil, errors.New("malformed SCT list extension")
+		}
+		return parseSCTList(b)
+	}
+	return nil, nil
+}
+
+// checkSCTs returns the number of SCTs for the server's certificate, embedded
+// in the certificate and received in the TLS handshake. Error is returned if
+// there are fewer than minSCTs SCTs.
+func checkSCTs(cs *tls.ConnectionState, minSCTs int) (int, error) {
+	if cs == nil || len(cs.PeerCertificates) == 0 {
+		return 0, errors.New("CT: no TLS connection state")
+	}
+	scts, err := embeddedSCTs(cs.PeerCertificates[0])
+	if err != nil {
+		return 0, fmt.Errorf("CT: %v", err)
+	}
+	n := len(scts) + len(cs.SignedCertificateTimestamps)
+	if n < minSCTs {
+		return n, fmt.Errorf("CT: found %d SCTs, need at least %d", n, minSCTs)
+	}
+	return n, nil
+}
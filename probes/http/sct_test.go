@@ -0,0 +1,212 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/probes/http/proto"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/cryptobyte"
+)
+
+// testSCTList returns a TLS encoded SCT list with the given SCTs.
+func testSCTList(scts ...[]byte) []byte {
+	var b cryptobyte.Builder
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		for _, sct := range scts {
+			b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+				b.AddBytes(sct)
+			})
+		}
+	})
+	return b.BytesOrPanic()
+}
+
+// newTestCertWithSCTs returns a certificate, issued by the issuer, with the
+// given SCT list extension value. Extension is not added if sctExt is nil.
+func newTestCertWithSCTs(t *testing.T, issuer *testCert, sctExt []byte) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(200),
+		Subject:      pkix.Name{CommonName: "test.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	if sctExt != nil {
+		extValue, err := asn1.Marshal(sctExt)
+		if err != nil {
+			t.Fatalf("error marshaling SCT list: %v", err)
+		}
+		tmpl.ExtraExtensions = []pkix.Extension{{Id: oidSCTList, Value: extValue}}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, issuer.cert, key.Public(), issuer.key)
+	if err != nil {
+		t.Fatalf("error creating certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("error parsing certificate: %v", err)
+	}
+	return cert
+}
+
+func TestParseSCTList(t *testing.T) {
+	scts, err := parseSCTList(testSCTList([]byte("sct1"), []byte("sct2")))
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("sct1"), []byte("sct2")}, scts)
+
+	scts, err = parseSCTList(testSCTList())
+	assert.NoError(t, err)
+	assert.Len(t, scts, 0)
+
+	for _, b := range [][]byte{
+		nil,
+		{0x00, 0x05, 0x00, 0x01}, // list length more than the data
+		testSCTList([]byte{}),    // empty SCT
+		append(testSCTList([]byte("sct1")), 0x00), // trailing data
+	} {
+		_, err := parseSCTList(b)
+		assert.Error(t, err, "SCT list: %v", b)
+	}
+}
+
+func TestCheckSCTs(t *testing.T) {
+	ca := newTestCert(t, "test-ca", 1, nil)
+
+	tests := []struct {
+		desc    string
+		cs      *tls.ConnectionState
+		minSCTs int
+		want    int
+		wantErr bool
+	}{
+		{
+			desc:    "no_tls",
+			minSCTs: 1,
+			wantErr: true,
+		},
+		{
+			desc: "embedded",
+			cs: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{newTestCertWithSCTs(t, ca, testSCTList([]byte("sct1"), []byte("sct2")))},
+			},
+			minSCTs: 2,
+			want:    2,
+		},
+		{
+			desc: "embedded_and_tls_extension",
+			cs: &tls.ConnectionState{
+				PeerCertificates:            []*x509.Certificate{newTestCertWithSCTs(t, ca, testSCTList([]byte("sct1")))},
+				SignedCertificateTimestamps: [][]byte{[]byte("sct2")},
+			},
+			minSCTs: 2,
+			want:    2,
+		},
+		{
+			desc: "too_few",
+			cs: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{newTestCertWithSCTs(t, ca, testSCTList([]byte("sct1")))},
+			},
+			minSCTs: 2,
+			want:    1,
+			wantErr: true,
+		},
+		{
+			desc: "none",
+			cs: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{newTestCertWithSCTs(t, ca, nil)},
+			},
+			minSCTs: 1,
+			wantErr: true,
+		},
+		{
+			desc: "none_min_zero",
+			cs: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{newTestCertWithSCTs(t, ca, nil)},
+			},
+		},
+		{
+			desc: "malformed",
+			cs: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{newTestCertWithSCTs(t, ca, []byte{0x00, 0x05})},
+			},
+			minSCTs: 1,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			n, err := checkSCTs(test.cs, test.minSCTs)
+			assert.Equal(t, test.want, n)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProbeWithCertificateTransparency(t *testing.T) {
+	ca := newTestCert(t, "test-ca", 1, nil)
+
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{
+		CertificateTransparency: &configpb.ProbeConf_CertificateTransparency{},
+	}
+	p := &Probe{}
+	if err := p.Init("http_test", opts); err != nil {
+		t.Fatalf("Error initializing probe: %v", err)
+	}
+
+	target := endpoint.Endpoint{Name: "test.com"}
+	req := p.httpRequestForTarget(target)
+	result := p.newResult()
+
+	for _, sctExt := range [][]byte{
+		testSCTList([]byte("sct1"), []byte("sct2"), []byte("sct3")),
+		nil,
+	} {
+		p.baseTransport = &tlsStateTransport{cs: &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{newTestCertWithSCTs(t, ca, sctExt), ca.cert},
+		}}
+		p.runProbe(context.Background(), target, p.clientsForTarget(target), req, result)
+		if sctExt != nil {
+			assert.Equal(t, int64(3), result.sctCount, "sct_count")
+		}
+	}
+
+	assert.Equal(t, int64(2), result.total, "total")
+	assert.Equal(t, int64(1), result.success, "success")
+	assert.Equal(t, int64(0), result.sctCount, "sct_count")
+}
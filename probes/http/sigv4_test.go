@@ -0,0 +1,136 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/cloudprober/cloudprober/metrics"
+	configpb "github.com/cloudprober/cloudprober/probes/http/proto"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// setTestAWSEnv sets up AWS credentials in the environment, and makes sure
+// that the AWS config files on the host are not used.
+func setTestAWSEnv(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test-secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_REGION", "us-west-2")
+}
+
+func testSigV4Probe(t *testing.T, sigV4 *configpb.ProbeConf_AWSSigV4) *Probe {
+	t.Helper()
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{
+		Body:     []string{"test-body"},
+		AwsSigv4: sigV4,
+	}
+	p := &Probe{}
+	if err := p.Init("http_test", opts); err != nil {
+		t.Fatalf("Error initializing probe: %v", err)
+	}
+	patchWithTestTransport(p)
+	return p
+}
+
+func TestNewSigV4Signer(t *testing.T) {
+	setTestAWSEnv(t)
+
+	_, err := newSigV4Signer(context.Background(), &configpb.ProbeConf_AWSSigV4{}, nil)
+	assert.Error(t, err, "missing service")
+
+	s, err := newSigV4Signer(context.Background(), &configpb.ProbeConf_AWSSigV4{Service: proto.String("execute-api")}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "us-west-2", s.region, "region from environment")
+	// SHA256 of the empty payload.
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", s.payloadHash)
+
+	s, err = newSigV4Signer(context.Background(), &configpb.ProbeConf_AWSSigV4{Service: proto.String("execute-api"), Region: proto.String("eu-west-1")}, strings.NewReader("test-body"))
+	assert.NoError(t, err)
+	assert.Equal(t, "eu-west-1", s.region, "configured region")
+	assert.NotEqual(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", s.payloadHash)
+}
+
+func TestRunProbeWithSigV4(t *testing.T) {
+	setTestAWSEnv(t)
+
+	p := testSigV4Probe(t, &configpb.ProbeConf_AWSSigV4{Service: proto.String("execute-api")})
+
+	target := endpoint.Endpoint{Name: "test.com"}
+	req := p.httpRequestForTarget(target)
+	result := p.newResult()
+	clients := p.clientsForTarget(target)
+	p.runProbe(context.Background(), target, clients, req, result)
+
+	assert.Equal(t, int64(1), result.total, "total")
+	assert.Equal(t, int64(1), result.success, "success")
+	assert.Equal(t, int64(0), result.signFailures, "sign failures")
+
+	tt := clients[0].Transport.(*testTransport)
+	assert.True(t, strings.HasPrefix(tt.lastAuthHeader, "AWS4-HMAC-SHA256 Credential=AKIDTEST/"), "auth header: %s", tt.lastAuthHeader)
+	assert.Contains(t, tt.lastAuthHeader, "/us-west-2/execute-api/aws4_request")
+	assert.Equal(t, "test-body", string(tt.lastRequestBody))
+
+	// Original request should stay unsigned.
+	assert.Empty(t, req.Header.Get("Authorization"))
+
+	signedReq, err := p.prepareRequest(req)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, signedReq.Header.Get("X-Amz-Date"))
+	assert.Equal(t, p.sigV4.payloadHash, signedReq.Header.Get("X-Amz-Content-Sha256"))
+}
+
+func TestRunProbeWithSigV4Failure(t *testing.T) {
+	setTestAWSEnv(t)
+
+	p := testSigV4Probe(t, &configpb.ProbeConf_AWSSigV4{Service: proto.String("execute-api")})
+	p.sigV4.creds = aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{}, errors.New("no credentials")
+	})
+
+	target := endpoint.Endpoint{Name: "test.com"}
+	req := p.httpRequestForTarget(target)
+	result := p.newResult()
+	clients := p.clientsForTarget(target)
+	p.runProbe(context.Background(), target, clients, req, result)
+
+	assert.Equal(t, int64(1), result.total, "total")
+	assert.Equal(t, int64(0), result.success, "success")
+	assert.Equal(t, int64(1), result.signFailures, "sign failures")
+
+	// Request should not have been sent.
+	tt := clients[0].Transport.(*testTransport)
+	assert.Nil(t, tt.lastRequestBody)
+
+	dataChan := make(chan *metrics.EventMetrics, 10)
+	p.exportMetrics(time.Now(), result, target, dataChan)
+	em := <-dataChan
+	assert.Equal(t, "1", em.Metric("sign_failures").String())
+}
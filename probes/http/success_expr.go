@@ -0,0 +1,72 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// successExprEnv is the environment that the success expression is evaluated
+// in. Keep it in sync with the success_expr documentation in the config.
+type successExprEnv struct {
+	StatusCode int               `expr:"status_code"`
+	LatencyMs  float64           `expr:"latency_ms"`
+	Headers    map[string]string `expr:"headers"`
+	Body       string            `expr:"body"`
+	JSON       any               `expr:"json"`
+}
+
+// compileSuccessExpr compiles the success expression. It returns an error if
+// the expression is invalid, uses unknown variables, or doesn't evaluate to a
+// boolean.
+func compileSuccessExpr(s string) (*vm.Program, error) {
+	program, err := expr.Compile(s, expr.Env(successExprEnv{}), expr.AsBool())
+	if err != nil {
+		return nil, fmt.Errorf("invalid success_expr (%s): %v", s, err)
+	}
+	return program, nil
+}
+
+func newSuccessExprEnv(resp *http.Response, respBody []byte, latency time.Duration) successExprEnv {
+	env := successExprEnv{
+		StatusCode: resp.StatusCode,
+		LatencyMs:  float64(latency) / float64(time.Millisecond),
+		Headers:    make(map[string]string, len(resp.Header)),
+		Body:       string(respBody),
+	}
+	for k, v := range resp.Header {
+		env.Headers[k] = strings.Join(v, ",")
+	}
+	if err := json.Unmarshal(respBody, &env.JSON); err != nil {
+		env.JSON = nil
+	}
+	return env
+}
+
+// evalSuccessExpr evaluates the success expression for the response.
+func evalSuccessExpr(program *vm.Program, resp *http.Response, respBody []byte, latency time.Duration) (bool, error) {
+	out, err := expr.Run(program, newSuccessExprEnv(resp, respBody, latency))
+	if err != nil {
+		return false, fmt.Errorf("error evaluating success_expr: %v", err)
+	}
+	return out.(bool), nil
+}
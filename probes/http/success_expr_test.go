@@ -0,0 +1,141 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/probes/http/proto"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCompileSuccessExpr(t *testing.T) {
+	for _, s := range []string{
+		"status_code == 200",
+		`headers["Content-Type"] startsWith "application/json" && json.status == "ok"`,
+		"latency_ms < 100 || body contains 'cached'",
+	} {
+		_, err := compileSuccessExpr(s)
+		assert.NoError(t, err, s)
+	}
+
+	for _, s := range []string{
+		"status_code ==",  // Syntax error
+		"status == 200",   // Unknown variable
+		"status_code + 1", // Not a boolean
+		`body == 1`,       // Type mismatch
+	} {
+		_, err := compileSuccessExpr(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestEvalSuccessExpr(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": {"application/json"}, "X-Values": {"a", "b"}},
+	}
+	jsonBody := []byte(`{"status": "ok", "items": [1, 2, 3]}`)
+
+	tests := []struct {
+		expr    string
+		body    []byte
+		want    bool
+		wantErr bool
+	}{
+		{expr: "status_code == 200", want: true},
+		{expr: "status_code != 200"},
+		{expr: "latency_ms == 1500", want: true},
+		{expr: `headers["Content-Type"] == "application/json"`, want: true},
+		{expr: `headers["X-Values"] == "a,b"`, want: true},
+		{expr: `headers["X-Missing"] == ""`, want: true},
+		{expr: `body contains "ok"`, body: jsonBody, want: true},
+		{expr: `json.status == "ok" && len(json.items) == 3`, body: jsonBody, want: true},
+		{expr: `json == nil`, body: []byte("not json"), want: true},
+		{expr: `json.items[5] == 1`, body: jsonBody, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			program, err := compileSuccessExpr(test.expr)
+			if err != nil {
+				t.Fatalf("Error compiling success_expr: %v", err)
+			}
+			got, err := evalSuccessExpr(program, resp, test.body, 1500*time.Millisecond)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+// bodyTransport returns responses with the given status code and body.
+type bodyTransport struct {
+	statusCode int
+	body       string
+}
+
+func (bt *bodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: bt.statusCode, Body: io.NopCloser(strings.NewReader(bt.body))}, nil
+}
+
+func TestProbeWithSuccessExpr(t *testing.T) {
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{
+		SuccessExpr: proto.String(`status_code == 200 && json.status == "ok"`),
+	}
+	p := &Probe{}
+	if err := p.Init("http_test", opts); err != nil {
+		t.Fatalf("Error initializing probe: %v", err)
+	}
+
+	target := endpoint.Endpoint{Name: "test.com"}
+	req := p.httpRequestForTarget(target)
+	result := p.newResult()
+
+	for _, bt := range []*bodyTransport{
+		{statusCode: 200, body: `{"status": "ok"}`},
+		{statusCode: 200, body: `{"status": "down"}`},
+		{statusCode: 500, body: `{"status": "ok"}`},
+		{statusCode: 200, body: `[1, 2]`}, // Runtime error: json is not a map.
+	} {
+		p.baseTransport = bt
+		p.runProbe(context.Background(), target, p.clientsForTarget(target), req, result)
+	}
+
+	assert.Equal(t, int64(4), result.total, "total")
+	assert.Equal(t, int64(1), result.success, "success")
+	assert.Equal(t, int64(1), result.successExprErrors, "success_expr_errors")
+}
+
+func TestProbeInitWithInvalidSuccessExpr(t *testing.T) {
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{
+		SuccessExpr: proto.String("status_code"),
+	}
+	p := &Probe{}
+	assert.Error(t, p.Init("http_test", opts))
+}
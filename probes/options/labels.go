@@ -15,6 +15,8 @@
 package options
 
 import (
+	"fmt"
+	"net"
 	"regexp"
 	"strconv"
 	"strings"
@@ -23,6 +25,7 @@ import (
 	"github.com/cloudprober/cloudprober/targets/endpoint"
 
 	configpb "github.com/cloudprober/cloudprober/probes/proto"
+	"google.golang.org/protobuf/proto"
 )
 
 // targetLabelType for target based additional labels
@@ -63,10 +66,20 @@ type AdditionalLabel struct {
 
 	// Target based substitution tokens.
 	tokens []targetToken
+
+	// Labels with the same key, merged with this label as per the CONCAT
+	// merge policy. Their values are appended to this label's value, each
+	// after its own separator.
+	concat []*AdditionalLabel
+	sep    string
 }
 
 // UpdateForTarget updates addtional label based on target's name and labels.
 func (al *AdditionalLabel) UpdateForTarget(ep endpoint.Endpoint, ipAddr string, probePort int) {
+	for _, c := range al.concat {
+		c.UpdateForTarget(ep, ipAddr, probePort)
+	}
+
 	al.mu.Lock()
 	defer al.mu.Unlock()
 
@@ -101,13 +114,28 @@ func (al *AdditionalLabel) UpdateForTarget(ep endpoint.Endpoint, ipAddr string,
 
 // KeyValueForTarget returns key, value pair for the given target.
 func (al *AdditionalLabel) KeyValueForTarget(ep endpoint.Endpoint) (key, val string) {
+	val = al.ownValue(ep)
+	for _, c := range al.concat {
+		if cv := c.ownValue(ep); cv != "" {
+			if val != "" {
+				val += c.sep
+			}
+			val += cv
+		}
+	}
+	return al.Key, val
+}
+
+// ownValue returns label's own value for the given target, i.e.
+// without the merged labels.
+func (al *AdditionalLabel) ownValue(ep endpoint.Endpoint) string {
 	al.mu.RLock()
 	defer al.mu.RUnlock()
 
 	if al.staticValue != "" {
-		return al.Key, al.staticValue
+		return al.staticValue
 	}
-	return al.Key, al.valueForTarget[ep.Key()]
+	return al.valueForTarget[ep.Key()]
 }
 
 // ParseAdditionalLabel parses an additional label proto message into an
@@ -167,12 +195,109 @@ func ParseAdditionalLabel(alpb *configpb.AdditionalLabel) *AdditionalLabel {
 	return al
 }
 
-func parseAdditionalLabels(p *configpb.ProbeDef) []*AdditionalLabel {
+// parseAdditionalLabels parses the given additional labels. Labels with the
+// same key are merged, as per the later label's merge policy.
+func parseAdditionalLabels(alpbs []*configpb.AdditionalLabel) ([]*AdditionalLabel, error) {
 	var aLabels []*AdditionalLabel
+	index := make(map[string]int)
 
-	for _, pb := range p.GetAdditionalLabel() {
-		aLabels = append(aLabels, ParseAdditionalLabel(pb))
+	for _, pb := range alpbs {
+		al := ParseAdditionalLabel(pb)
+		i, ok := index[al.Key]
+		if !ok {
+			index[al.Key] = len(aLabels)
+			aLabels = append(aLabels, al)
+			continue
+		}
+
+		switch pb.GetMergePolicy() {
+		case configpb.AdditionalLabel_OVERRIDE:
+			aLabels[i] = al
+		case configpb.AdditionalLabel_CONCAT:
+			al.sep = pb.GetSeparator()
+			aLabels[i].concat = append(aLabels[i].concat, al)
+		case configpb.AdditionalLabel_ERROR:
+			return nil, fmt.Errorf("additional label %s is defined more than once", al.Key)
+		}
+	}
+
+	return aLabels, nil
+}
+
+// WithGlobalLabels returns the probe definition with the global additional
+// labels merged ahead of the probe's own labels. If there are global labels,
+// a copy of the probe definition is returned, so that the probe definition
+// stays as configured.
+func WithGlobalLabels(p *configpb.ProbeDef, global []*configpb.AdditionalLabel) *configpb.ProbeDef {
+	if len(global) == 0 {
+		return p
 	}
+	p = proto.Clone(p).(*configpb.ProbeDef)
+	p.AdditionalLabel = append(append([]*configpb.AdditionalLabel{}, global...), p.GetAdditionalLabel()...)
+	return p
+}
 
-	return aLabels
+// shortHostname returns the first component of the domain name. IP addresses
+// are returned as is. If target name includes a port, it's retained.
+func shortHostname(target string) string {
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		host, port = target, ""
+	}
+	if net.ParseIP(host) == nil {
+		host, _, _ = strings.Cut(host, ".")
+	}
+	if port == "" {
+		return host
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// parseTargetLabelTransform returns a function to transform target names into
+// "dst" label values, as per the probe's target_label_transform. It returns
+// nil if no transform is configured.
+func parseTargetLabelTransform(p *configpb.ProbeDef) (func(string) string, error) {
+	tlt := p.GetTargetLabelTransform()
+	if tlt == nil {
+		return nil, nil
+	}
+
+	var fn func(string) string
+	switch tlt.GetFunction() {
+	case configpb.TargetLabelTransform_NONE:
+	case configpb.TargetLabelTransform_LOWERCASE:
+		fn = strings.ToLower
+	case configpb.TargetLabelTransform_SHORT_HOSTNAME:
+		fn = shortHostname
+	default:
+		return nil, fmt.Errorf("unknown target_label_transform function: %v", tlt.GetFunction())
+	}
+
+	var re *regexp.Regexp
+	if tlt.GetRegex() != "" {
+		var err error
+		if re, err = regexp.Compile(tlt.GetRegex()); err != nil {
+			return nil, fmt.Errorf("invalid target_label_transform regex (%s): %v", tlt.GetRegex(), err)
+		}
+	} else if tlt.Replacement != nil {
+		return nil, fmt.Errorf("target_label_transform replacement (%s) specified without regex", tlt.GetReplacement())
+	}
+
+	if fn == nil && re == nil {
+		return nil, fmt.Errorf("target_label_transform needs at least one of function and regex")
+	}
+
+	return func(target string) string {
+		v := target
+		if fn != nil {
+			v = fn(v)
+		}
+		if re != nil && re.MatchString(v) {
+			v = re.ReplaceAllString(v, tlt.GetReplacement())
+		}
+		if v == "" {
+			return target
+		}
+		return v
+	}, nil
 }
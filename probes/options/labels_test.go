@@ -20,6 +20,7 @@ import (
 
 	configpb "github.com/cloudprober/cloudprober/probes/proto"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -114,7 +115,10 @@ func TestParseAdditionalLabel(t *testing.T) {
 }
 
 func TestUpdateAdditionalLabel(t *testing.T) {
-	aLabels := parseAdditionalLabels(configWithAdditionalLabels)
+	aLabels, err := parseAdditionalLabels(configWithAdditionalLabels.GetAdditionalLabel())
+	if err != nil {
+		t.Fatalf("Error parsing additional labels: %v", err)
+	}
 
 	endpoints := map[string]endpoint.Endpoint{
 		"target1": {Name: "target1", Labels: map[string]string{}, Port: 80},
@@ -176,3 +180,195 @@ func TestUpdateAdditionalLabel(t *testing.T) {
 		}
 	}
 }
+
+func TestMergeAdditionalLabels(t *testing.T) {
+	alpb := func(key, value string, policy configpb.AdditionalLabel_MergePolicy, sep string) *configpb.AdditionalLabel {
+		al := &configpb.AdditionalLabel{Key: proto.String(key), Value: proto.String(value), MergePolicy: policy.Enum()}
+		if sep != "" {
+			al.Separator = proto.String(sep)
+		}
+		return al
+	}
+
+	tests := []struct {
+		name    string
+		labels  []*configpb.AdditionalLabel
+		want    [][2]string
+		wantErr bool
+	}{
+		{
+			name: "override",
+			labels: []*configpb.AdditionalLabel{
+				alpb("env", "prod", configpb.AdditionalLabel_OVERRIDE, ""),
+				alpb("team", "a", configpb.AdditionalLabel_OVERRIDE, ""),
+				alpb("env", "staging", configpb.AdditionalLabel_OVERRIDE, ""),
+			},
+			want: [][2]string{{"env", "staging"}, {"team", "a"}},
+		},
+		{
+			name: "concat",
+			labels: []*configpb.AdditionalLabel{
+				alpb("tags", "team-a", configpb.AdditionalLabel_OVERRIDE, ""),
+				alpb("env", "prod", configpb.AdditionalLabel_OVERRIDE, ""),
+				alpb("tags", "@target.label.tier@", configpb.AdditionalLabel_CONCAT, ""),
+				alpb("tags", "critical", configpb.AdditionalLabel_CONCAT, "|"),
+			},
+			want: [][2]string{{"tags", "team-a,web|critical"}, {"env", "prod"}},
+		},
+		{
+			name: "concat_empty_value",
+			labels: []*configpb.AdditionalLabel{
+				alpb("tags", "@target.label.unknown@", configpb.AdditionalLabel_OVERRIDE, ""),
+				alpb("tags", "critical", configpb.AdditionalLabel_CONCAT, ""),
+			},
+			want: [][2]string{{"tags", "critical"}},
+		},
+		{
+			name: "error",
+			labels: []*configpb.AdditionalLabel{
+				alpb("env", "prod", configpb.AdditionalLabel_OVERRIDE, ""),
+				alpb("env", "staging", configpb.AdditionalLabel_ERROR, ""),
+			},
+			wantErr: true,
+		},
+		{
+			name: "error_policy_for_unique_key",
+			labels: []*configpb.AdditionalLabel{
+				alpb("env", "prod", configpb.AdditionalLabel_ERROR, ""),
+			},
+			want: [][2]string{{"env", "prod"}},
+		},
+	}
+
+	ep := endpoint.Endpoint{Name: "target1", Labels: map[string]string{"tier": "web"}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aLabels, err := parseAdditionalLabels(test.labels)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			var got [][2]string
+			for _, al := range aLabels {
+				al.UpdateForTarget(ep, "", 0)
+				k, v := al.KeyValueForTarget(ep)
+				got = append(got, [2]string{k, v})
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestWithGlobalLabels(t *testing.T) {
+	p := &configpb.ProbeDef{
+		AdditionalLabel: []*configpb.AdditionalLabel{{Key: proto.String("env"), Value: proto.String("dev")}},
+	}
+	assert.Same(t, p, WithGlobalLabels(p, nil))
+
+	global := []*configpb.AdditionalLabel{{Key: proto.String("team"), Value: proto.String("a")}}
+	got := WithGlobalLabels(p, global)
+	var keys []string
+	for _, al := range got.GetAdditionalLabel() {
+		keys = append(keys, al.GetKey())
+	}
+	assert.Equal(t, []string{"team", "env"}, keys)
+	assert.Len(t, p.GetAdditionalLabel(), 1, "probe definition is not modified")
+}
+
+func TestParseTargetLabelTransform(t *testing.T) {
+	tests := []struct {
+		name    string
+		tlt     *configpb.TargetLabelTransform
+		wantErr bool
+		inOut   map[string]string
+	}{
+		{
+			name: "no_transform",
+		},
+		{
+			name: "lowercase",
+			tlt:  &configpb.TargetLabelTransform{Function: configpb.TargetLabelTransform_LOWERCASE.Enum()},
+			inOut: map[string]string{
+				"Web-1.Prod.Internal": "web-1.prod.internal",
+			},
+		},
+		{
+			name: "short_hostname",
+			tlt:  &configpb.TargetLabelTransform{Function: configpb.TargetLabelTransform_SHORT_HOSTNAME.Enum()},
+			inOut: map[string]string{
+				"web-1.prod.internal":      "web-1",
+				"web-1.prod.internal:8080": "web-1:8080",
+				"web-1":                    "web-1",
+				"10.0.0.5":                 "10.0.0.5",
+				"[2001:db8::1]:80":         "[2001:db8::1]:80",
+			},
+		},
+		{
+			name: "function_and_regex",
+			tlt: &configpb.TargetLabelTransform{
+				Function:    configpb.TargetLabelTransform_SHORT_HOSTNAME.Enum(),
+				Regex:       proto.String(`^10\.0\.0\.\d+$`),
+				Replacement: proto.String("prod-subnet"),
+			},
+			inOut: map[string]string{
+				"web-1.prod.internal": "web-1",
+				"10.0.0.5":            "prod-subnet",
+				"10.0.1.5":            "10.0.1.5",
+			},
+		},
+		{
+			name: "regex_capture_group",
+			tlt: &configpb.TargetLabelTransform{
+				Regex:       proto.String(`^(web-\d+)-[a-z0-9]+$`),
+				Replacement: proto.String("$1"),
+			},
+			inOut: map[string]string{
+				"web-1-x7f2k": "web-1",
+				"db-1-x7f2k":  "db-1-x7f2k",
+			},
+		},
+		{
+			name: "empty_result",
+			tlt: &configpb.TargetLabelTransform{
+				Regex:       proto.String(`^web-1$`),
+				Replacement: proto.String(""),
+			},
+			inOut: map[string]string{
+				"web-1": "web-1",
+			},
+		},
+		{
+			name:    "invalid_regex",
+			tlt:     &configpb.TargetLabelTransform{Regex: proto.String(`(`)},
+			wantErr: true,
+		},
+		{
+			name:    "replacement_without_regex",
+			tlt:     &configpb.TargetLabelTransform{Replacement: proto.String("x")},
+			wantErr: true,
+		},
+		{
+			name:    "empty_transform",
+			tlt:     &configpb.TargetLabelTransform{},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transform, err := parseTargetLabelTransform(&configpb.ProbeDef{TargetLabelTransform: test.tlt})
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseTargetLabelTransform() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.tlt == nil || test.wantErr {
+				assert.Nil(t, transform)
+				return
+			}
+			for in, want := range test.inOut {
+				assert.Equal(t, want, transform(in), "transform(%s)", in)
+			}
+		})
+	}
+}
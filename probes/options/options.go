@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net"
+	"regexp"
 	"time"
 
 	"github.com/cloudprober/cloudprober/common/iputils"
@@ -51,17 +52,86 @@ type Options struct {
 	AdditionalLabels    []*AdditionalLabel
 	NegativeTest        bool
 	AlertHandlers       []*alerting.AlertHandler
+
+	// TargetLabelTransform, if set, transforms the "dst" label values.
+	TargetLabelTransform func(string) string
+
+	// TargetRemovalGracePeriod is how long the removed targets continue to be
+	// probed before their probe loops are stopped.
+	TargetRemovalGracePeriod time.Duration
+
+	// MetricPrefix, if set, is prepended to the names of the probe's metrics
+	// by the surfacers (see surfacers.SurfacerInfo.WriteWithMetricPrefix).
+	MetricPrefix string
+
+	// MaxRunDuration is the maximum duration of a probe sweep over all
+	// the targets. Probes stop launching new target probes once it's over.
+	MaxRunDuration time.Duration
+
+	// ConnectTimeout, if set, bounds the connection establishment phase of a
+	// probe run, while Timeout bounds the whole run.
+	ConnectTimeout time.Duration
 }
 
 const defaultStatsExtportIntv = 10 * time.Second
-const defaultIntervalPeriod = 2 * time.Second
-const defaultTimeoutPeriod = 1 * time.Second
+
+// Default probe interval and timeout.
+const (
+	DefaultInterval = 2 * time.Second
+	DefaultTimeout  = 1 * time.Second
+)
+
+// ProbeDuration returns the duration set through either the msec or the
+// string field of a probe, e.g. interval_msec or interval, or def if neither
+// is set. Field is the string field's name, used in the error messages.
+func ProbeDuration(msec int32, str string, def time.Duration, field string) (time.Duration, error) {
+	switch {
+	case msec != 0 && str != "":
+		return 0, fmt.Errorf("both %s (%s) and %s_msec (%d) are specified", field, str, field, msec)
+	case msec != 0:
+		return time.Duration(msec) * time.Millisecond, nil
+	case str != "":
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse %s (%s): %v", field, str, err)
+		}
+		return d, nil
+	}
+	return def, nil
+}
 
 var negativeTestSupported = map[configpb.ProbeDef_Type]bool{
 	configpb.ProbeDef_TCP:  true,
 	configpb.ProbeDef_PING: true,
 }
 
+// metricPrefixRe follows the Prometheus metric naming rules, which are the
+// strictest among the supported surfacers.
+var metricPrefixRe = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// ValidateMetricPrefix verifies that the metric names generated using the
+// given prefix are valid.
+func ValidateMetricPrefix(prefix string) error {
+	if !metricPrefixRe.MatchString(prefix) {
+		return fmt.Errorf("invalid metric_prefix (%s), it should match %s", prefix, metricPrefixRe.String())
+	}
+	return nil
+}
+
+var targetRemovalGracePeriodSupported = map[configpb.ProbeDef_Type]bool{
+	configpb.ProbeDef_HTTP: true,
+	configpb.ProbeDef_TCP:  true,
+}
+
+var maxRunDurationSupported = map[configpb.ProbeDef_Type]bool{
+	configpb.ProbeDef_EXTERNAL: true,
+}
+
+var connectTimeoutSupported = map[configpb.ProbeDef_Type]bool{
+	configpb.ProbeDef_HTTP: true,
+	configpb.ProbeDef_TCP:  true,
+}
+
 func defaultStatsExportInterval(p *configpb.ProbeDef, opts *Options) time.Duration {
 	minIntv := opts.Interval
 	if opts.Timeout > opts.Interval {
@@ -121,33 +191,26 @@ func getSourceIPFromConfig(p *configpb.ProbeDef, l *logger.Logger) (net.IP, erro
 	}
 }
 
+// staticTargets returns true if targets are defined statically in the config,
+// i.e. their list doesn't depend on discovery.
+func staticTargets(td *targetspb.TargetsDef) bool {
+	switch td.GetType().(type) {
+	case *targetspb.TargetsDef_HostNames, nil:
+		return true
+	}
+	return false
+}
+
 // BuildProbeOptions builds probe's options using the provided config and some
 // global params.
 func BuildProbeOptions(p *configpb.ProbeDef, ldLister endpoint.Lister, globalTargetsOpts *targetspb.GlobalTargetsOptions, l *logger.Logger) (*Options, error) {
-	intervalDuration := defaultIntervalPeriod
-	timeoutDuration := defaultTimeoutPeriod
-	var err error
-
-	if p.GetIntervalMsec() != 0 && p.GetInterval() != "" {
-		return nil, fmt.Errorf("both interval (%s) and interval_msec (%d) are specified", p.GetInterval(), p.GetIntervalMsec())
-	} else if p.GetIntervalMsec() != 0 {
-		intervalDuration = time.Duration(p.GetIntervalMsec()) * time.Millisecond
-	} else if p.GetInterval() != "" {
-		intervalDuration, err = time.ParseDuration(p.GetInterval())
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse interval (%s): %v", p.GetInterval(), err)
-		}
+	intervalDuration, err := ProbeDuration(p.GetIntervalMsec(), p.GetInterval(), DefaultInterval, "interval")
+	if err != nil {
+		return nil, err
 	}
-
-	if p.GetTimeoutMsec() != 0 && p.GetTimeout() != "" {
-		return nil, fmt.Errorf("both timeout (%s) and timeout_msec (%d) are specified", p.GetTimeout(), p.GetTimeoutMsec())
-	} else if p.GetTimeoutMsec() != 0 {
-		timeoutDuration = time.Duration(p.GetTimeoutMsec()) * time.Millisecond
-	} else if p.GetTimeout() != "" {
-		timeoutDuration, err = time.ParseDuration(p.GetTimeout())
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse timeout (%s): %v", p.GetTimeout(), err)
-		}
+	timeoutDuration, err := ProbeDuration(p.GetTimeoutMsec(), p.GetTimeout(), DefaultTimeout, "timeout")
+	if err != nil {
+		return nil, err
 	}
 
 	if intervalDuration < timeoutDuration && p.GetType() != configpb.ProbeDef_UDP {
@@ -167,6 +230,56 @@ func BuildProbeOptions(p *configpb.ProbeDef, ldLister endpoint.Lister, globalTar
 		Logger:            logger.NewWithAttrs(slog.String("probe", p.GetName())),
 	}
 
+	if p.GetTargetRemovalGracePeriod() != "" {
+		if !targetRemovalGracePeriodSupported[p.GetType()] {
+			return nil, fmt.Errorf("target_removal_grace_period is not supported by %s probes", p.GetType().String())
+		}
+		opts.TargetRemovalGracePeriod, err = time.ParseDuration(p.GetTargetRemovalGracePeriod())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse target_removal_grace_period (%s): %v", p.GetTargetRemovalGracePeriod(), err)
+		}
+		if opts.TargetRemovalGracePeriod < 0 {
+			return nil, fmt.Errorf("target_removal_grace_period (%s) cannot be negative", p.GetTargetRemovalGracePeriod())
+		}
+	}
+
+	opts.MaxRunDuration = opts.Interval
+	if p.GetMaxRunDuration() != "" {
+		if !maxRunDurationSupported[p.GetType()] {
+			return nil, fmt.Errorf("max_run_duration is not supported by %s probes", p.GetType().String())
+		}
+		opts.MaxRunDuration, err = time.ParseDuration(p.GetMaxRunDuration())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse max_run_duration (%s): %v", p.GetMaxRunDuration(), err)
+		}
+		if opts.MaxRunDuration <= 0 {
+			return nil, fmt.Errorf("max_run_duration (%s) should be positive", p.GetMaxRunDuration())
+		}
+	}
+
+	if p.GetConnectTimeout() != "" {
+		if !connectTimeoutSupported[p.GetType()] {
+			return nil, fmt.Errorf("connect_timeout is not supported by %s probes", p.GetType().String())
+		}
+		opts.ConnectTimeout, err = time.ParseDuration(p.GetConnectTimeout())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse connect_timeout (%s): %v", p.GetConnectTimeout(), err)
+		}
+		if opts.ConnectTimeout <= 0 {
+			return nil, fmt.Errorf("connect_timeout (%s) should be positive", p.GetConnectTimeout())
+		}
+		if opts.ConnectTimeout > opts.Timeout {
+			return nil, fmt.Errorf("connect_timeout (%v) cannot be longer than the timeout (%v)", opts.ConnectTimeout, opts.Timeout)
+		}
+	}
+
+	if p.MetricPrefix != nil {
+		if err := ValidateMetricPrefix(p.GetMetricPrefix()); err != nil {
+			return nil, err
+		}
+		opts.MetricPrefix = p.GetMetricPrefix()
+	}
+
 	if p.GetTargets() == nil {
 		if p.GetType() != configpb.ProbeDef_USER_DEFINED && p.GetType() != configpb.ProbeDef_EXTERNAL && p.GetType() != configpb.ProbeDef_EXTENSION {
 			return nil, fmt.Errorf("targets requied for probe type: %s", p.GetType().String())
@@ -181,6 +294,10 @@ func BuildProbeOptions(p *configpb.ProbeDef, ldLister endpoint.Lister, globalTar
 		return nil, err
 	}
 
+	if p.GetNoTargetsPolicy() == configpb.ProbeDef_ERROR && staticTargets(p.GetTargets()) && len(opts.Targets.ListEndpoints()) == 0 {
+		return nil, fmt.Errorf("probe has no targets (no_targets_policy: %s)", p.GetNoTargetsPolicy())
+	}
+
 	if latencyDist := p.GetLatencyDistribution(); latencyDist != nil {
 		var d *metrics.Distribution
 		if d, err = metrics.NewDistributionFromProto(latencyDist); err != nil {
@@ -221,7 +338,13 @@ func BuildProbeOptions(p *configpb.ProbeDef, ldLister endpoint.Lister, globalTar
 		}
 	}
 
-	opts.AdditionalLabels = parseAdditionalLabels(p)
+	if opts.AdditionalLabels, err = parseAdditionalLabels(p.GetAdditionalLabel()); err != nil {
+		return nil, err
+	}
+
+	if opts.TargetLabelTransform, err = parseTargetLabelTransform(p); err != nil {
+		return nil, err
+	}
 
 	for _, alertConf := range p.GetAlert() {
 		ah, err := alerting.NewAlertHandler(alertConf, p.GetName(), opts.Logger)
@@ -277,6 +400,11 @@ func WithNoAlert() RecordOptions {
 
 func (opts *Options) RecordMetrics(ep endpoint.Endpoint, em *metrics.EventMetrics, dataChan chan<- *metrics.EventMetrics, ropts ...RecordOptions) {
 	em.LatencyUnit = opts.LatencyUnit
+	if opts.TargetLabelTransform != nil {
+		if dst := em.Label("dst"); dst != "" {
+			em.SetLabel("dst", opts.TargetLabelTransform(dst))
+		}
+	}
 	for _, al := range opts.AdditionalLabels {
 		em.AddLabel(al.KeyValueForTarget(ep))
 	}
@@ -485,3 +485,260 @@ func TestNilTargets(t *testing.T) {
 		})
 	}
 }
+
+func TestNoTargetsPolicyError(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets *targetspb.TargetsDef
+		policy  configpb.ProbeDef_NoTargetsPolicy
+		wantErr bool
+	}{
+		{
+			name:    "empty_host_names_ignore",
+			targets: &targetspb.TargetsDef{Type: &targetspb.TargetsDef_HostNames{HostNames: ""}},
+			policy:  configpb.ProbeDef_IGNORE,
+		},
+		{
+			name:    "empty_host_names_error",
+			targets: &targetspb.TargetsDef{Type: &targetspb.TargetsDef_HostNames{HostNames: ""}},
+			policy:  configpb.ProbeDef_ERROR,
+			wantErr: true,
+		},
+		{
+			name:    "empty_endpoints_error",
+			targets: &targetspb.TargetsDef{},
+			policy:  configpb.ProbeDef_ERROR,
+			wantErr: true,
+		},
+		{
+			name:    "host_names_error",
+			targets: &targetspb.TargetsDef{Type: &targetspb.TargetsDef_HostNames{HostNames: "testHost"}},
+			policy:  configpb.ProbeDef_ERROR,
+		},
+		{
+			name:    "empty_host_names_emit_zero",
+			targets: &targetspb.TargetsDef{Type: &targetspb.TargetsDef_HostNames{HostNames: ""}},
+			policy:  configpb.ProbeDef_EMIT_ZERO,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &configpb.ProbeDef{
+				Type:            configpb.ProbeDef_PING.Enum(),
+				Name:            proto.String("test-probe"),
+				Targets:         tt.targets,
+				NoTargetsPolicy: tt.policy.Enum(),
+			}
+			_, err := BuildProbeOptions(cfg, nil, nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BuildProbeOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecordMetricsTargetLabelTransform(t *testing.T) {
+	opts := DefaultOptions()
+	opts.TargetLabelTransform = shortHostname
+
+	dataChan := make(chan *metrics.EventMetrics, 2)
+	ep := endpoint.Endpoint{Name: "web-1.prod.internal"}
+
+	opts.RecordMetrics(ep, metrics.NewEventMetrics(time.Now()).
+		AddMetric("total", metrics.NewInt(1)).
+		AddLabel("dst", ep.Name), dataChan)
+	assert.Equal(t, "web-1", (<-dataChan).Label("dst"))
+
+	// EventMetrics without dst label are left alone.
+	opts.RecordMetrics(ep, metrics.NewEventMetrics(time.Now()).
+		AddMetric("total", metrics.NewInt(1)), dataChan)
+	em := <-dataChan
+	assert.Equal(t, "", em.Label("dst"))
+	assert.NotContains(t, em.LabelsKeys(), "dst")
+}
+
+func TestTargetRemovalGracePeriod(t *testing.T) {
+	tests := []struct {
+		ptype       configpb.ProbeDef_Type
+		gracePeriod string
+		want        time.Duration
+		wantErr     bool
+	}{
+		{ptype: configpb.ProbeDef_HTTP},
+		{ptype: configpb.ProbeDef_HTTP, gracePeriod: "30s", want: 30 * time.Second},
+		{ptype: configpb.ProbeDef_TCP, gracePeriod: "1m", want: time.Minute},
+		{ptype: configpb.ProbeDef_HTTP, gracePeriod: "30", wantErr: true},
+		{ptype: configpb.ProbeDef_HTTP, gracePeriod: "-1s", wantErr: true},
+		{ptype: configpb.ProbeDef_PING, gracePeriod: "30s", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.ptype.String()+"_"+test.gracePeriod, func(t *testing.T) {
+			p := &configpb.ProbeDef{
+				Type:    test.ptype.Enum(),
+				Targets: testTargets,
+			}
+			if test.gracePeriod != "" {
+				p.TargetRemovalGracePeriod = proto.String(test.gracePeriod)
+			}
+			opts, err := BuildProbeOptions(p, nil, nil, nil)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("BuildProbeOptions() error: %v, want error: %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if opts.TargetRemovalGracePeriod != test.want {
+				t.Errorf("TargetRemovalGracePeriod=%v, want=%v", opts.TargetRemovalGracePeriod, test.want)
+			}
+		})
+	}
+}
+
+func TestMaxRunDuration(t *testing.T) {
+	tests := []struct {
+		ptype          configpb.ProbeDef_Type
+		interval       string
+		maxRunDuration string
+		want           time.Duration
+		wantErr        bool
+	}{
+		{ptype: configpb.ProbeDef_EXTERNAL, want: DefaultInterval},
+		{ptype: configpb.ProbeDef_EXTERNAL, interval: "30s", want: 30 * time.Second},
+		{ptype: configpb.ProbeDef_HTTP, interval: "30s", want: 30 * time.Second},
+		{ptype: configpb.ProbeDef_EXTERNAL, interval: "30s", maxRunDuration: "25s", want: 25 * time.Second},
+		{ptype: configpb.ProbeDef_EXTERNAL, maxRunDuration: "25", wantErr: true},
+		{ptype: configpb.ProbeDef_EXTERNAL, maxRunDuration: "0s", wantErr: true},
+		{ptype: configpb.ProbeDef_HTTP, maxRunDuration: "25s", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.ptype.String()+"_"+test.interval+"_"+test.maxRunDuration, func(t *testing.T) {
+			p := &configpb.ProbeDef{
+				Type:    test.ptype.Enum(),
+				Targets: testTargets,
+			}
+			if test.interval != "" {
+				p.Interval = proto.String(test.interval)
+			}
+			if test.maxRunDuration != "" {
+				p.MaxRunDuration = proto.String(test.maxRunDuration)
+			}
+			opts, err := BuildProbeOptions(p, nil, nil, nil)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("BuildProbeOptions() error: %v, want error: %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if opts.MaxRunDuration != test.want {
+				t.Errorf("MaxRunDuration=%v, want=%v", opts.MaxRunDuration, test.want)
+			}
+		})
+	}
+}
+
+func TestConnectTimeout(t *testing.T) {
+	tests := []struct {
+		ptype          configpb.ProbeDef_Type
+		timeout        string
+		connectTimeout string
+		want           time.Duration
+		wantErr        bool
+	}{
+		{ptype: configpb.ProbeDef_TCP},
+		{ptype: configpb.ProbeDef_TCP, connectTimeout: "200ms", want: 200 * time.Millisecond},
+		{ptype: configpb.ProbeDef_HTTP, timeout: "5s", connectTimeout: "5s", want: 5 * time.Second},
+		{ptype: configpb.ProbeDef_HTTP, timeout: "5s", connectTimeout: "6s", wantErr: true},
+		{ptype: configpb.ProbeDef_HTTP, connectTimeout: "0s", wantErr: true},
+		{ptype: configpb.ProbeDef_HTTP, connectTimeout: "200", wantErr: true},
+		{ptype: configpb.ProbeDef_PING, connectTimeout: "200ms", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.ptype.String()+"_"+test.timeout+"_"+test.connectTimeout, func(t *testing.T) {
+			p := &configpb.ProbeDef{
+				Type:    test.ptype.Enum(),
+				Targets: testTargets,
+			}
+			if test.timeout != "" {
+				p.Timeout = proto.String(test.timeout)
+				p.Interval = proto.String("10s")
+			}
+			if test.connectTimeout != "" {
+				p.ConnectTimeout = proto.String(test.connectTimeout)
+			}
+			opts, err := BuildProbeOptions(p, nil, nil, nil)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("BuildProbeOptions() error: %v, want error: %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if opts.ConnectTimeout != test.want {
+				t.Errorf("ConnectTimeout=%v, want=%v", opts.ConnectTimeout, test.want)
+			}
+		})
+	}
+}
+
+func TestMetricPrefix(t *testing.T) {
+	tests := []struct {
+		prefix  *string
+		want    string
+		wantErr bool
+	}{
+		{},
+		{prefix: proto.String("team_a_"), want: "team_a_"},
+		{prefix: proto.String("ns:team_a_"), want: "ns:team_a_"},
+		{prefix: proto.String(""), wantErr: true},
+		{prefix: proto.String("1team_"), wantErr: true},
+		{prefix: proto.String("team-a_"), wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			p := &configpb.ProbeDef{
+				Type:         configpb.ProbeDef_HTTP.Enum(),
+				Targets:      testTargets,
+				MetricPrefix: test.prefix,
+			}
+			opts, err := BuildProbeOptions(p, nil, nil, nil)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("BuildProbeOptions() error: %v, want error: %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if opts.MetricPrefix != test.want {
+				t.Errorf("MetricPrefix=%v, want=%v", opts.MetricPrefix, test.want)
+			}
+		})
+	}
+}
+
+func TestProbeDuration(t *testing.T) {
+	tests := []struct {
+		msec    int32
+		str     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{want: DefaultInterval},
+		{msec: 500, want: 500 * time.Millisecond},
+		{str: "30s", want: 30 * time.Second},
+		{msec: 500, str: "30s", wantErr: true},
+		{str: "30", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := ProbeDuration(test.msec, test.str, DefaultInterval, "interval")
+		if (err != nil) != test.wantErr {
+			t.Fatalf("ProbeDuration(%d, %q) error: %v, want error: %v", test.msec, test.str, err, test.wantErr)
+		}
+		if got != test.want {
+			t.Errorf("ProbeDuration(%d, %q)=%v, want=%v", test.msec, test.str, got, test.want)
+		}
+	}
+}
@@ -29,10 +29,13 @@ import (
 	httpprobe "github.com/cloudprober/cloudprober/probes/http"
 	"github.com/cloudprober/cloudprober/probes/options"
 	"github.com/cloudprober/cloudprober/probes/ping"
+	"github.com/cloudprober/cloudprober/probes/probeutils"
 	configpb "github.com/cloudprober/cloudprober/probes/proto"
+	"github.com/cloudprober/cloudprober/probes/redfish"
 	"github.com/cloudprober/cloudprober/probes/tcp"
 	"github.com/cloudprober/cloudprober/probes/udp"
 	"github.com/cloudprober/cloudprober/probes/udplistener"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
 	"github.com/cloudprober/cloudprober/web/formatutils"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
@@ -59,6 +62,37 @@ type Probe interface {
 	Start(ctx context.Context, dataChan chan *metrics.EventMetrics)
 }
 
+// DstLabeler is implemented by the probes that don't use endpoint's Dst() as
+// the "dst" label value of their metrics. It lets other modules, e.g.
+// maintenance status, export metrics that join with the probe's metrics.
+type DstLabeler interface {
+	DstLabel(ep endpoint.Endpoint) string
+}
+
+// nameDstProbeTypes are the built-in probe types that use the target name,
+// without the port, as the "dst" label value.
+var nameDstProbeTypes = map[configpb.ProbeDef_Type]bool{
+	configpb.ProbeDef_HTTP:     true,
+	configpb.ProbeDef_PING:     true,
+	configpb.ProbeDef_UDP:      true,
+	configpb.ProbeDef_EXTERNAL: true,
+}
+
+// DstLabel returns the "dst" label value that the probe uses for the given
+// target, after applying the target label transform, if any.
+func DstLabel(p *ProbeInfo, ep endpoint.Endpoint) string {
+	dst := ep.Dst()
+	if p.ProbeDef != nil && nameDstProbeTypes[p.ProbeDef.GetType()] {
+		dst = ep.Name
+	} else if dl, ok := p.Probe.(DstLabeler); ok {
+		dst = dl.DstLabel(ep)
+	}
+	if p.Options != nil && p.Options.TargetLabelTransform != nil && dst != "" {
+		dst = p.Options.TargetLabelTransform(dst)
+	}
+	return dst
+}
+
 // ProbeInfo encapsulates the probe and associated information.
 type ProbeInfo struct {
 	Probe
@@ -153,6 +187,9 @@ func initProbe(p *configpb.ProbeDef, opts *options.Options) (probe Probe, probeC
 	case configpb.ProbeDef_GRPC:
 		probe = &grpcprobe.Probe{}
 		probeConf = p.GetGrpcProbe()
+	case configpb.ProbeDef_REDFISH:
+		probe = &redfish.Probe{}
+		probeConf = p.GetRedfishProbe()
 	case configpb.ProbeDef_EXTENSION:
 		probe, probeConf, err = getExtensionProbe(p)
 		if err != nil {
@@ -198,6 +235,21 @@ func RegisterUserDefined(name string, probe Probe) {
 	userDefinedProbes[name] = probe
 }
 
+// RegisterDialer allows you to register a custom dialer, e.g. a service mesh
+// client, with cloudprober. TCP and HTTP probes use it for their connections
+// if it's referred to by the name in their config (the "dialer" field).
+// Example usage:
+//
+//	d := &MeshDialer{}
+//	probes.RegisterDialer("mesh", d)
+//	pr, err := cloudprober.InitFromConfig(*configFile)
+//	if err != nil {
+//		log.Exitf("Error initializing cloudprober. Err: %v", err)
+//	}
+func RegisterDialer(name string, d probeutils.ProbeDialer) {
+	probeutils.RegisterDialer(name, d)
+}
+
 // RegisterProbeType registers a new probe-type. New probe types are integrated
 // with the config subsystem using the protobuf extensions.
 //
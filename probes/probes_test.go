@@ -23,10 +23,13 @@ import (
 
 	"github.com/cloudprober/cloudprober/metrics"
 	"github.com/cloudprober/cloudprober/probes"
+	httpprobe "github.com/cloudprober/cloudprober/probes/http"
 	"github.com/cloudprober/cloudprober/probes/options"
 	configpb "github.com/cloudprober/cloudprober/probes/proto"
 	testdatapb "github.com/cloudprober/cloudprober/probes/testdata"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
 	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -86,3 +89,19 @@ func TestGetExtensionProbe(t *testing.T) {
 		t.Errorf("Extensions probe's Init() called %d times, should be called exactly once.", testProbeIntialized)
 	}
 }
+
+func TestDstLabel(t *testing.T) {
+	ep := endpoint.Endpoint{Name: "web-1", Port: 8080}
+
+	// Default is endpoint's Dst().
+	assert.Equal(t, "web-1:8080", probes.DstLabel(&probes.ProbeInfo{Probe: &testProbe{}}, ep))
+
+	// HTTP probe uses the target name without the port.
+	p := &probes.ProbeInfo{Probe: &httpprobe.Probe{}, ProbeDef: &configpb.ProbeDef{Type: configpb.ProbeDef_HTTP.Enum()}}
+	assert.Equal(t, "web-1", probes.DstLabel(p, ep))
+
+	p.Options = &options.Options{
+		TargetLabelTransform: func(s string) string { return "prod-" + s },
+	}
+	assert.Equal(t, "prod-web-1", probes.DstLabel(p, ep))
+}
@@ -0,0 +1,175 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probeutils
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+)
+
+// DialFunc is the function that probes use to make new connections.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// connLimiter is a token bucket rate limiter for new connections. Tokens
+// can go negative, which represents the connections waiting for their turn.
+type connLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // Tokens per second.
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newConnLimiter(rate float64, burst int, now time.Time) *connLimiter {
+	return &connLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now,
+	}
+}
+
+// refill adds the tokens accumulated since the last refill. It should be
+// called with the lock held.
+func (cl *connLimiter) refill(now time.Time) {
+	if now.After(cl.last) {
+		cl.tokens = math.Min(cl.burst, cl.tokens+now.Sub(cl.last).Seconds()*cl.rate)
+		cl.last = now
+	}
+}
+
+// waitTime returns how long a new connection has to wait.
+func (cl *connLimiter) waitTime(now time.Time) time.Duration {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.refill(now)
+	if cl.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - cl.tokens) / cl.rate * float64(time.Second))
+}
+
+// reserve takes a token and returns how long to wait before using it.
+func (cl *connLimiter) reserve(now time.Time) time.Duration {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.refill(now)
+	cl.tokens--
+	if cl.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-cl.tokens / cl.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token.
+func (cl *connLimiter) cancel() {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.tokens = math.Min(cl.burst, cl.tokens+1)
+}
+
+func (cl *connLimiter) wait(ctx context.Context) error {
+	d := cl.reserve(time.Now())
+	if d == 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		cl.cancel()
+		return ctx.Err()
+	}
+}
+
+var (
+	globalConnLimiter   *connLimiter
+	globalConnLimiterMu sync.RWMutex
+)
+
+// SetConnRateLimit sets the global rate limit on new connections, shared by
+// all the dial functions wrapped using LimitDial. A zero rate removes the
+// rate limit.
+func SetConnRateLimit(rate float64, burst int) error {
+	if rate < 0 {
+		return fmt.Errorf("connection rate limit (%v) cannot be negative", rate)
+	}
+	if rate > 0 && burst < 1 {
+		return fmt.Errorf("connection rate limit burst (%d) should be at least 1", burst)
+	}
+
+	globalConnLimiterMu.Lock()
+	defer globalConnLimiterMu.Unlock()
+	if rate == 0 {
+		globalConnLimiter = nil
+		return nil
+	}
+	globalConnLimiter = newConnLimiter(rate, burst, time.Now())
+	return nil
+}
+
+func getConnLimiter() *connLimiter {
+	globalConnLimiterMu.RLock()
+	defer globalConnLimiterMu.RUnlock()
+	return globalConnLimiter
+}
+
+// ConnRateLimited returns true if the global connection rate limit is set.
+func ConnRateLimited() bool {
+	return getConnLimiter() != nil
+}
+
+// ConnLimiterWaitTime returns the current wait time for new connections, or
+// zero if the connection rate limit is not set.
+func ConnLimiterWaitTime() time.Duration {
+	cl := getConnLimiter()
+	if cl == nil {
+		return 0
+	}
+	return cl.waitTime(time.Now())
+}
+
+// WaitForConnRateLimit waits until a new connection can be made as per the
+// global connection rate limit, if it's set. Probes should call it before
+// starting the probe timeout and the latency measurement, so that the wait
+// doesn't count towards them. LimitDial applies it at the time of dialing,
+// which is useful when connections are dialed only occasionally.
+func WaitForConnRateLimit(ctx context.Context) error {
+	cl := getConnLimiter()
+	if cl == nil {
+		return nil
+	}
+	if err := cl.wait(ctx); err != nil {
+		return fmt.Errorf("waiting for connection rate limiter: %v", err)
+	}
+	return nil
+}
+
+// LimitDial wraps the dial function to apply the global connection rate
+// limit, if it's set at the time of dialing.
+func LimitDial(dial DialFunc) DialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if err := WaitForConnRateLimit(ctx); err != nil {
+			return nil, err
+		}
+		return dial(ctx, network, addr)
+	}
+}
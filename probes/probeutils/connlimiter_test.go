@@ -0,0 +1,89 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probeutils
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnLimiter(t *testing.T) {
+	now := time.Now()
+	cl := newConnLimiter(10, 2, now)
+
+	// Burst of 2 goes through, next ones wait 100ms each.
+	assert.Equal(t, time.Duration(0), cl.reserve(now))
+	assert.Equal(t, time.Duration(0), cl.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, cl.waitTime(now))
+	assert.Equal(t, 100*time.Millisecond, cl.reserve(now))
+	assert.Equal(t, 200*time.Millisecond, cl.reserve(now))
+
+	// Refilled after 300ms: 1 token, minus the 2 reserved.
+	now = now.Add(300 * time.Millisecond)
+	assert.Equal(t, time.Duration(0), cl.waitTime(now))
+	cl.cancel()
+	assert.Equal(t, time.Duration(0), cl.reserve(now))
+
+	// Tokens don't exceed the burst.
+	now = now.Add(time.Hour)
+	cl.refill(now)
+	assert.Equal(t, float64(2), cl.tokens)
+}
+
+func TestSetConnRateLimit(t *testing.T) {
+	defer SetConnRateLimit(0, 0)
+
+	assert.Error(t, SetConnRateLimit(-1, 1), "negative rate")
+	assert.Error(t, SetConnRateLimit(10, 0), "zero burst")
+
+	assert.NoError(t, SetConnRateLimit(0, 0))
+	assert.False(t, ConnRateLimited())
+	assert.Equal(t, time.Duration(0), ConnLimiterWaitTime())
+
+	assert.NoError(t, SetConnRateLimit(10, 1))
+	assert.True(t, ConnRateLimited())
+}
+
+func TestLimitDial(t *testing.T) {
+	defer SetConnRateLimit(0, 0)
+
+	numDials := 0
+	dial := LimitDial(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		numDials++
+		return nil, nil
+	})
+
+	// Rate of 1 per 100s: first dial goes through, second one times out.
+	assert.NoError(t, SetConnRateLimit(0.01, 1))
+	_, err := dial(context.Background(), "tcp", "test:80")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = dial(ctx, "tcp", "test:80")
+	assert.Error(t, err)
+	assert.Equal(t, 1, numDials)
+
+	// No rate limit.
+	assert.NoError(t, SetConnRateLimit(0, 0))
+	for i := 0; i < 5; i++ {
+		dial(context.Background(), "tcp", "test:80")
+	}
+	assert.Equal(t, 6, numDials)
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probeutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// ProbeDialer is the interface for custom dialers, e.g. a service mesh
+// client, that probes can use to make connections.
+type ProbeDialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+var (
+	dialers   = make(map[string]ProbeDialer)
+	dialersMu sync.RWMutex
+)
+
+// RegisterDialer registers a custom dialer with the given name. Probes
+// refer to the dialer by this name in their config.
+func RegisterDialer(name string, d ProbeDialer) {
+	dialersMu.Lock()
+	defer dialersMu.Unlock()
+	dialers[name] = d
+}
+
+// GetDialer returns the custom dialer registered with the given name.
+func GetDialer(name string) (ProbeDialer, error) {
+	dialersMu.RLock()
+	defer dialersMu.RUnlock()
+	d, ok := dialers[name]
+	if !ok {
+		return nil, fmt.Errorf("unregistered dialer: %s", name)
+	}
+	return d, nil
+}
+
+// connectTimeoutError is returned by the dial functions wrapped with
+// WithConnectTimeout, if the dial doesn't complete within the connect
+// timeout. It implements net.Error, so that it's treated as a timeout by the
+// code that doesn't care about the phase, e.g. HTTP client.
+type connectTimeoutError struct {
+	timeout time.Duration
+	err     error
+}
+
+func (e *connectTimeoutError) Error() string {
+	return fmt.Sprintf("connect timeout (%v): %v", e.timeout, e.err)
+}
+
+func (e *connectTimeoutError) Unwrap() error   { return e.err }
+func (e *connectTimeoutError) Timeout() bool   { return true }
+func (e *connectTimeoutError) Temporary() bool { return true }
+
+// IsConnectTimeout returns true if the error was caused by the connect
+// timeout of a dial function wrapped with WithConnectTimeout.
+func IsConnectTimeout(err error) bool {
+	var ctErr *connectTimeoutError
+	return errors.As(err, &ctErr)
+}
+
+// WithConnectTimeout wraps the dial function to bound the dial by the given
+// timeout, independent of the overall deadline in the context. Use
+// IsConnectTimeout to find out if a dial error was caused by this timeout.
+func WithConnectTimeout(dial DialFunc, timeout time.Duration) DialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		conn, err := dial(dialCtx, network, addr)
+		// If parent context is done as well, overall timeout takes precedence.
+		if err != nil && ctx.Err() == nil && errors.Is(dialCtx.Err(), context.DeadlineExceeded) {
+			return nil, &connectTimeoutError{timeout: timeout, err: err}
+		}
+		return conn, err
+	}
+}
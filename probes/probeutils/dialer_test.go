@@ -0,0 +1,69 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probeutils
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDialer(t *testing.T) {
+	d := &net.Dialer{}
+	RegisterDialer("test-dialer", d)
+
+	got, err := GetDialer("test-dialer")
+	assert.NoError(t, err)
+	assert.Equal(t, d, got)
+
+	_, err = GetDialer("unknown-dialer")
+	assert.Error(t, err)
+}
+
+func TestWithConnectTimeout(t *testing.T) {
+	// blockingDial blocks until the context is done.
+	blockingDial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		<-ctx.Done()
+		return nil, &net.OpError{Op: "dial", Net: network, Err: ctx.Err()}
+	}
+
+	t.Run("connect_timeout", func(t *testing.T) {
+		_, err := WithConnectTimeout(blockingDial, 10*time.Millisecond)(context.Background(), "tcp", "test.com:80")
+		assert.True(t, IsConnectTimeout(err), "IsConnectTimeout(%v)", err)
+
+		var netErr net.Error
+		assert.True(t, errors.As(err, &netErr) && netErr.Timeout(), "net.Error timeout")
+	})
+
+	t.Run("overall_timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := WithConnectTimeout(blockingDial, time.Second)(ctx, "tcp", "test.com:80")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.False(t, IsConnectTimeout(err))
+	})
+
+	t.Run("other_error", func(t *testing.T) {
+		dialErr := errors.New("connection refused")
+		_, err := WithConnectTimeout(func(context.Context, string, string) (net.Conn, error) {
+			return nil, dialErr
+		}, time.Second)(context.Background(), "tcp", "test.com:80")
+		assert.Equal(t, dialErr, err)
+	})
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probeutils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/cloudprober/cloudprober/metrics"
+)
+
+// Probe failure categories.
+const (
+	FailureDNS        = "dns"
+	FailureConnect    = "connect"
+	FailureTLS        = "tls"
+	FailureTimeout    = "timeout"
+	FailureValidation = "validation"
+	FailureStatus     = "status"
+	FailureOther      = "other"
+)
+
+// FailureCategories are all the probe failure categories.
+var FailureCategories = []string{FailureDNS, FailureConnect, FailureTLS, FailureTimeout, FailureValidation, FailureStatus, FailureOther}
+
+// NewFailureCategoryMap returns a map for counting failures by category,
+// initialized with all the categories so that they are always exported.
+func NewFailureCategoryMap() *metrics.Map[int64] {
+	m := metrics.NewMap("category")
+	for _, c := range FailureCategories {
+		m.IncKeyBy(c, 0)
+	}
+	return m
+}
+
+func isTLSError(err error) bool {
+	var (
+		recordHeaderErr  tls.RecordHeaderError
+		alertErr         tls.AlertError
+		verificationErr  *tls.CertificateVerificationError
+		unknownAuthority x509.UnknownAuthorityError
+		hostnameErr      x509.HostnameError
+		certInvalidErr   x509.CertificateInvalidError
+	)
+	if errors.As(err, &recordHeaderErr) || errors.As(err, &alertErr) || errors.As(err, &verificationErr) ||
+		errors.As(err, &unknownAuthority) || errors.As(err, &hostnameErr) || errors.As(err, &certInvalidErr) {
+		return true
+	}
+	// Handshake errors are not always typed, e.g. "tls: handshake failure".
+	return strings.Contains(err.Error(), "tls: ")
+}
+
+// ErrorFailureCategory classifies a network error, e.g. one returned by a
+// dialer or an HTTP client, into one of the DNS, connect, TLS, timeout or
+// other failure categories.
+func ErrorFailureCategory(err error) string {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return FailureDNS
+	}
+
+	// Connect timeout is a connection failure, overall timeout is a timeout.
+	if IsConnectTimeout(err) {
+		return FailureConnect
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return FailureTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return FailureTimeout
+	}
+
+	if isTLSError(err) {
+		return FailureTLS
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return FailureConnect
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EHOSTUNREACH) || errors.Is(err, syscall.ENETUNREACH) {
+		return FailureConnect
+	}
+
+	return FailureOther
+}
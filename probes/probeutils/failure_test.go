@@ -0,0 +1,98 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probeutils
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorFailureCategory(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "dns",
+			err:  &url.Error{Op: "Get", URL: "http://test.com", Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}},
+			want: FailureDNS,
+		},
+		{
+			name: "connection_refused",
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+			want: FailureConnect,
+		},
+		{
+			name: "connection_reset",
+			err:  fmt.Errorf("read: %w", syscall.ECONNRESET),
+			want: FailureConnect,
+		},
+		{
+			name: "deadline_exceeded",
+			err:  fmt.Errorf("request failed: %w", context.DeadlineExceeded),
+			want: FailureTimeout,
+		},
+		{
+			name: "connect_timeout",
+			err:  &url.Error{Op: "Get", URL: "http://test.com", Err: &connectTimeoutError{timeout: time.Second, err: &net.OpError{Op: "dial", Err: context.DeadlineExceeded}}},
+			want: FailureConnect,
+		},
+		{
+			name: "io_timeout",
+			err:  &net.OpError{Op: "read", Err: os.ErrDeadlineExceeded},
+			want: FailureTimeout,
+		},
+		{
+			name: "unknown_authority",
+			err:  &url.Error{Op: "Get", URL: "https://test.com", Err: x509.UnknownAuthorityError{}},
+			want: FailureTLS,
+		},
+		{
+			name: "tls_handshake",
+			err:  errors.New("remote error: tls: handshake failure"),
+			want: FailureTLS,
+		},
+		{
+			name: "other",
+			err:  errors.New("unexpected EOF"),
+			want: FailureOther,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, ErrorFailureCategory(test.err))
+		})
+	}
+}
+
+func TestNewFailureCategoryMap(t *testing.T) {
+	m := NewFailureCategoryMap()
+	assert.ElementsMatch(t, FailureCategories, m.Keys())
+	for _, k := range m.Keys() {
+		assert.Equal(t, int64(0), m.GetKey(k), k)
+	}
+}
@@ -23,6 +23,13 @@ import (
 	"fmt"
 )
 
+// Socket buffer size limits. Sizes outside these limits are either too small
+// to be useful or likely a configuration mistake.
+const (
+	MinSocketBufferSize = 1024
+	MaxSocketBufferSize = 64 * 1024 * 1024
+)
+
 // PatternPayload builds a payload that can be verified using VerifyPayloadPattern.
 // It repeats the pattern to fill the payload []byte slice. Last remaining
 // bytes (len(payload) mod patternSize) are left unpopulated (hence set to 0
@@ -54,3 +61,38 @@ func VerifyPayloadPattern(payload, pattern []byte) error {
 
 	return nil
 }
+
+// ValidateSocketBufferSize returns an error if a non-zero socket buffer size
+// is outside the allowed limits.
+func ValidateSocketBufferSize(field string, size int32) error {
+	if size == 0 {
+		return nil
+	}
+	if size < MinSocketBufferSize || size > MaxSocketBufferSize {
+		return fmt.Errorf("%s (%d) should be between %d and %d", field, size, MinSocketBufferSize, MaxSocketBufferSize)
+	}
+	return nil
+}
+
+// SocketBufferSetter is implemented by connections that support setting
+// socket buffer sizes, e.g. *net.TCPConn and *net.UDPConn.
+type SocketBufferSetter interface {
+	SetReadBuffer(bytes int) error
+	SetWriteBuffer(bytes int) error
+}
+
+// SetSocketBuffers sets socket receive and send buffer sizes on the given
+// connection. Zero sizes are ignored, leaving the system defaults in place.
+func SetSocketBuffers(conn SocketBufferSetter, readSize, writeSize int32) error {
+	if readSize != 0 {
+		if err := conn.SetReadBuffer(int(readSize)); err != nil {
+			return fmt.Errorf("error setting read buffer size: %v", err)
+		}
+	}
+	if writeSize != 0 {
+		if err := conn.SetWriteBuffer(int(writeSize)); err != nil {
+			return fmt.Errorf("error setting write buffer size: %v", err)
+		}
+	}
+	return nil
+}
@@ -15,6 +15,7 @@
 package probeutils
 
 import (
+	"net"
 	"reflect"
 	"testing"
 )
@@ -61,3 +62,53 @@ func BenchmarkVerifyPayloadPattern56(b *testing.B)   { benchmarkVerifyPayloadPat
 func BenchmarkVerifyPayloadPattern256(b *testing.B)  { benchmarkVerifyPayloadPattern(256, b) }
 func BenchmarkVerifyPayloadPattern1999(b *testing.B) { benchmarkVerifyPayloadPattern(1999, b) }
 func BenchmarkVerifyPayloadPattern9999(b *testing.B) { benchmarkVerifyPayloadPattern(9999, b) }
+
+func TestValidateSocketBufferSize(t *testing.T) {
+	for size, wantErr := range map[int32]bool{
+		0:                       false,
+		MinSocketBufferSize:     false,
+		1 << 20:                 false,
+		MaxSocketBufferSize:     false,
+		MinSocketBufferSize - 1: true,
+		MaxSocketBufferSize + 1: true,
+		-1:                      true,
+	} {
+		err := ValidateSocketBufferSize("read_buffer_size", size)
+		if (err != nil) != wantErr {
+			t.Errorf("ValidateSocketBufferSize(%d): err=%v, wantErr=%v", size, err, wantErr)
+		}
+	}
+}
+
+type testBufferSetter struct {
+	read, write int
+}
+
+func (bs *testBufferSetter) SetReadBuffer(bytes int) error {
+	bs.read = bytes
+	return nil
+}
+
+func (bs *testBufferSetter) SetWriteBuffer(bytes int) error {
+	bs.write = bytes
+	return nil
+}
+
+func TestSetSocketBuffers(t *testing.T) {
+	bs := &testBufferSetter{}
+	if err := SetSocketBuffers(bs, 0, 8192); err != nil {
+		t.Errorf("SetSocketBuffers() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(bs, &testBufferSetter{write: 8192}) {
+		t.Errorf("SetSocketBuffers(): got %+v, want write=8192 only", bs)
+	}
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("error creating UDP socket: %v", err)
+	}
+	defer conn.Close()
+	if err := SetSocketBuffers(conn, 65536, 65536); err != nil {
+		t.Errorf("SetSocketBuffers() unexpected error for UDP conn: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package probeutils
+
+import (
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestSocketBuffersControl(t *testing.T) {
+	if SocketBuffersControl(0, 0) != nil {
+		t.Errorf("SocketBuffersControl(0, 0): expected nil control function")
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error starting listener: %v", err)
+	}
+	defer ln.Close()
+
+	dialer := &net.Dialer{Control: SocketBuffersControl(65536, 0)}
+	conn, err := dialer.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("error connecting to the listener: %v", err)
+	}
+	defer conn.Close()
+
+	rawConn, err := conn.(*net.TCPConn).SyscallConn()
+	if err != nil {
+		t.Fatalf("error getting raw connection: %v", err)
+	}
+	var rcvBuf int
+	rawConn.Control(func(fd uintptr) {
+		rcvBuf, err = syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_RCVBUF)
+	})
+	if err != nil {
+		t.Fatalf("error getting SO_RCVBUF: %v", err)
+	}
+	// Linux doubles the requested size to account for the bookkeeping
+	// overhead.
+	if rcvBuf < 65536 {
+		t.Errorf("SO_RCVBUF=%d, want >= 65536", rcvBuf)
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package probeutils
+
+import "syscall"
+
+// SocketBuffersControl is not supported on this platform, it always returns
+// nil. Socket buffer sizes can still be set on the established connections,
+// using SetSocketBuffers.
+func SocketBuffersControl(readSize, writeSize int32) func(network, address string, c syscall.RawConn) error {
+	return nil
+}
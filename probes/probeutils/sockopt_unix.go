@@ -0,0 +1,54 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package probeutils
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// SocketBuffersControl returns a net.Dialer Control function that sets the
+// socket receive and send buffer sizes before the connection is
+// established, so that the receive buffer size is taken into account for
+// the TCP window negotiation. Zero sizes are ignored. It returns nil if
+// there is nothing to set.
+func SocketBuffersControl(readSize, writeSize int32) func(network, address string, c syscall.RawConn) error {
+	if readSize == 0 && writeSize == 0 {
+		return nil
+	}
+	return func(_, _ string, c syscall.RawConn) error {
+		var sockErr error
+		err := c.Control(func(fd uintptr) {
+			if readSize != 0 {
+				if err := syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_RCVBUF, int(readSize)); err != nil {
+					sockErr = fmt.Errorf("error setting read buffer size: %v", err)
+					return
+				}
+			}
+			if writeSize != 0 {
+				if err := syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_SNDBUF, int(writeSize)); err != nil {
+					sockErr = fmt.Errorf("error setting write buffer size: %v", err)
+				}
+			}
+		})
+		if err != nil {
+			return err
+		}
+		return sockErr
+	}
+}
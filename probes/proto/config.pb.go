@@ -15,6 +15,7 @@ import (
 	proto10 "github.com/cloudprober/cloudprober/probes/grpc/proto"
 	proto5 "github.com/cloudprober/cloudprober/probes/http/proto"
 	proto4 "github.com/cloudprober/cloudprober/probes/ping/proto"
+	proto12 "github.com/cloudprober/cloudprober/probes/redfish/proto"
 	proto11 "github.com/cloudprober/cloudprober/probes/tcp/proto"
 	proto8 "github.com/cloudprober/cloudprober/probes/udp/proto"
 	proto9 "github.com/cloudprober/cloudprober/probes/udplistener/proto"
@@ -43,6 +44,7 @@ const (
 	ProbeDef_UDP_LISTENER ProbeDef_Type = 5
 	ProbeDef_GRPC         ProbeDef_Type = 6
 	ProbeDef_TCP          ProbeDef_Type = 7
+	ProbeDef_REDFISH      ProbeDef_Type = 8
 	// One of the extension probe types. See "extensions" below for more
 	// details.
 	ProbeDef_EXTENSION ProbeDef_Type = 98
@@ -64,6 +66,7 @@ var (
 		5:  "UDP_LISTENER",
 		6:  "GRPC",
 		7:  "TCP",
+		8:  "REDFISH",
 		98: "EXTENSION",
 		99: "USER_DEFINED",
 	}
@@ -76,6 +79,7 @@ var (
 		"UDP_LISTENER": 5,
 		"GRPC":         6,
 		"TCP":          7,
+		"REDFISH":      8,
 		"EXTENSION":    98,
 		"USER_DEFINED": 99,
 	}
@@ -189,7 +193,207 @@ func (ProbeDef_IPVersion) EnumDescriptor() ([]byte, []int) {
 	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZIP(), []int{0, 1}
 }
 
-// Next tag: 101
+// What to do if probe has no targets, e.g. because static targets list is
+// empty or targets discovery returned nothing.
+type ProbeDef_NoTargetsPolicy int32
+
+const (
+	// Do nothing. Probe runs silently without any targets.
+	ProbeDef_IGNORE ProbeDef_NoTargetsPolicy = 0
+	// Log a warning whenever probe's targets list becomes empty.
+	ProbeDef_WARN ProbeDef_NoTargetsPolicy = 1
+	// Fail probe initialization if probe has static targets (host_names or
+	// endpoints) and the list is empty. For other targets, we log an error
+	// and export the "no_targets" metric, as for EMIT_ZERO.
+	ProbeDef_ERROR ProbeDef_NoTargetsPolicy = 2
+	// Export a "no_targets" gauge metric at the stats export interval: 1 if
+	// probe has no targets, 0 otherwise. This makes it possible to tell
+	// discovery failures apart from all targets being down.
+	ProbeDef_EMIT_ZERO ProbeDef_NoTargetsPolicy = 3
+)
+
+// Enum value maps for ProbeDef_NoTargetsPolicy.
+var (
+	ProbeDef_NoTargetsPolicy_name = map[int32]string{
+		0: "IGNORE",
+		1: "WARN",
+		2: "ERROR",
+		3: "EMIT_ZERO",
+	}
+	ProbeDef_NoTargetsPolicy_value = map[string]int32{
+		"IGNORE":    0,
+		"WARN":      1,
+		"ERROR":     2,
+		"EMIT_ZERO": 3,
+	}
+)
+
+func (x ProbeDef_NoTargetsPolicy) Enum() *ProbeDef_NoTargetsPolicy {
+	p := new(ProbeDef_NoTargetsPolicy)
+	*p = x
+	return p
+}
+
+func (x ProbeDef_NoTargetsPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProbeDef_NoTargetsPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_enumTypes[2].Descriptor()
+}
+
+func (ProbeDef_NoTargetsPolicy) Type() protoreflect.EnumType {
+	return &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_enumTypes[2]
+}
+
+func (x ProbeDef_NoTargetsPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *ProbeDef_NoTargetsPolicy) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = ProbeDef_NoTargetsPolicy(num)
+	return nil
+}
+
+// Deprecated: Use ProbeDef_NoTargetsPolicy.Descriptor instead.
+func (ProbeDef_NoTargetsPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZIP(), []int{0, 2}
+}
+
+type TargetLabelTransform_Function int32
+
+const (
+	TargetLabelTransform_NONE TargetLabelTransform_Function = 0
+	// Convert to lower case.
+	TargetLabelTransform_LOWERCASE TargetLabelTransform_Function = 1
+	// Keep only the first component of domain names, e.g.
+	// "web-1.prod.internal" becomes "web-1". IP addresses are left as is.
+	TargetLabelTransform_SHORT_HOSTNAME TargetLabelTransform_Function = 2
+)
+
+// Enum value maps for TargetLabelTransform_Function.
+var (
+	TargetLabelTransform_Function_name = map[int32]string{
+		0: "NONE",
+		1: "LOWERCASE",
+		2: "SHORT_HOSTNAME",
+	}
+	TargetLabelTransform_Function_value = map[string]int32{
+		"NONE":           0,
+		"LOWERCASE":      1,
+		"SHORT_HOSTNAME": 2,
+	}
+)
+
+func (x TargetLabelTransform_Function) Enum() *TargetLabelTransform_Function {
+	p := new(TargetLabelTransform_Function)
+	*p = x
+	return p
+}
+
+func (x TargetLabelTransform_Function) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TargetLabelTransform_Function) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_enumTypes[3].Descriptor()
+}
+
+func (TargetLabelTransform_Function) Type() protoreflect.EnumType {
+	return &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_enumTypes[3]
+}
+
+func (x TargetLabelTransform_Function) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *TargetLabelTransform_Function) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = TargetLabelTransform_Function(num)
+	return nil
+}
+
+// Deprecated: Use TargetLabelTransform_Function.Descriptor instead.
+func (TargetLabelTransform_Function) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZIP(), []int{1, 0}
+}
+
+// How to merge this label with an earlier label with the same key, e.g. a
+// global additional label (see additional_label in ProberConfig), or a
+// probe template's label for the template instances.
+type AdditionalLabel_MergePolicy int32
+
+const (
+	// This label's value replaces the earlier value (last one wins).
+	AdditionalLabel_OVERRIDE AdditionalLabel_MergePolicy = 0
+	// This label's value is appended to the earlier value, with the
+	// separator in between, e.g. "team-a,critical" for tags.
+	AdditionalLabel_CONCAT AdditionalLabel_MergePolicy = 1
+	// Probe configuration is rejected.
+	AdditionalLabel_ERROR AdditionalLabel_MergePolicy = 2
+)
+
+// Enum value maps for AdditionalLabel_MergePolicy.
+var (
+	AdditionalLabel_MergePolicy_name = map[int32]string{
+		0: "OVERRIDE",
+		1: "CONCAT",
+		2: "ERROR",
+	}
+	AdditionalLabel_MergePolicy_value = map[string]int32{
+		"OVERRIDE": 0,
+		"CONCAT":   1,
+		"ERROR":    2,
+	}
+)
+
+func (x AdditionalLabel_MergePolicy) Enum() *AdditionalLabel_MergePolicy {
+	p := new(AdditionalLabel_MergePolicy)
+	*p = x
+	return p
+}
+
+func (x AdditionalLabel_MergePolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AdditionalLabel_MergePolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_enumTypes[4].Descriptor()
+}
+
+func (AdditionalLabel_MergePolicy) Type() protoreflect.EnumType {
+	return &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_enumTypes[4]
+}
+
+func (x AdditionalLabel_MergePolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *AdditionalLabel_MergePolicy) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = AdditionalLabel_MergePolicy(num)
+	return nil
+}
+
+// Deprecated: Use AdditionalLabel_MergePolicy.Descriptor instead.
+func (AdditionalLabel_MergePolicy) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZIP(), []int{2, 0}
+}
+
+// Next tag: 109
 type ProbeDef struct {
 	state           protoimpl.MessageState
 	sizeCache       protoimpl.SizeCache
@@ -204,6 +408,7 @@ type ProbeDef struct {
 	// Default interval is 2s.
 	IntervalMsec *int32 `protobuf:"varint,4,opt,name=interval_msec,json=intervalMsec" json:"interval_msec,omitempty"`
 	// Interval between two probe runs in string format, e.g. 10s.
+	// Unit is required: bare numbers, e.g. "10", are rejected.
 	// Only one of "interval" and "inteval_msec" should be defined.
 	// Default interval is 2s.
 	Interval *string `protobuf:"bytes,16,opt,name=interval" json:"interval,omitempty"`
@@ -212,6 +417,7 @@ type ProbeDef struct {
 	// Default timeout is 1s.
 	TimeoutMsec *int32 `protobuf:"varint,5,opt,name=timeout_msec,json=timeoutMsec" json:"timeout_msec,omitempty"`
 	// Timeout for each probe in string format, e.g. 10s.
+	// Unit is required: bare numbers, e.g. "10", are rejected.
 	// Only one of "timeout" and "timeout_msec" should be defined.
 	// Default timeout is 1s.
 	Timeout *string `protobuf:"bytes,17,opt,name=timeout" json:"timeout,omitempty"`
@@ -242,6 +448,10 @@ type ProbeDef struct {
 	// Validators for this probe. Validators are run on the data returned by the
 	// probe. See https://cloudprober.org/docs/how-to/validators/ for more info.
 	Validator []*proto2.Validator `protobuf:"bytes,9,rep,name=validator" json:"validator,omitempty"`
+	// Names of the validator sets (see ProberConfig's validator_set) to use for
+	// this probe. Validators from the sets are added before the validators
+	// defined above. Validator names should be unique across all of them.
+	ValidatorSet []string `protobuf:"bytes,104,rep,name=validator_set,json=validatorSet" json:"validator_set,omitempty"`
 	// Set the source IP to send packets from, either by providing an IP address
 	// directly, or a network interface.
 	//
@@ -300,6 +510,62 @@ type ProbeDef struct {
 	//	  notify { ... }
 	//	}
 	Alert []*proto3.AlertConf `protobuf:"bytes,19,rep,name=alert" json:"alert,omitempty"`
+	// Names of the surfacers that this probe's metrics should go to. If not
+	// specified, probe's metrics go to all surfacers. Surfacers are referred to
+	// by their name, or by their type in lower case (e.g. "file") if they don't
+	// have a name.
+	// Example:
+	//
+	//	surfacers: "local-file"
+	//	surfacers: "prometheus"
+	Surfacers       []string                  `protobuf:"bytes,101,rep,name=surfacers" json:"surfacers,omitempty"`
+	NoTargetsPolicy *ProbeDef_NoTargetsPolicy `protobuf:"varint,102,opt,name=no_targets_policy,json=noTargetsPolicy,enum=cloudprober.probes.ProbeDef_NoTargetsPolicy,def=0" json:"no_targets_policy,omitempty"`
+	// Transform to normalize target names into stable "dst" label values, e.g.
+	// to map "web-1.prod.internal" to "web-1". See TargetLabelTransform below.
+	TargetLabelTransform *TargetLabelTransform `protobuf:"bytes,103,opt,name=target_label_transform,json=targetLabelTransform" json:"target_label_transform,omitempty"`
+	// Grace period for the removed targets, in string format, e.g. 30s. If
+	// set, targets removed from the targets list (e.g. by discovery during a
+	// scale-down) continue to be probed for this duration before their probe
+	// loops are stopped, so that in-flight probes are not abruptly canceled. If
+	// a target comes back within the grace period, probing continues as before.
+	// Once the grace period is over, the target's probe loop stops after the
+	// in-flight probe, if any, finishes.
+	//
+	// This is currently implemented only by HTTP and TCP probes.
+	TargetRemovalGracePeriod *string `protobuf:"bytes,105,opt,name=target_removal_grace_period,json=targetRemovalGracePeriod" json:"target_removal_grace_period,omitempty"`
+	// Prefix for the names of the metrics exported by this probe, e.g. with
+	// metric_prefix "team_a_", "total" is exported as "team_a_total". This is
+	// useful to avoid metric name collisions when multiple cloudprober instances
+	// export to the same backend. Prefix should be a valid Prometheus metric
+	// name prefix, i.e. it should match [a-zA-Z_:][a-zA-Z0-9_:]*. If not set,
+	// the global metric_prefix (in ProberConfig) is used.
+	// Prefix is also added to the metrics that cloudprober generates for the
+	// probe, e.g. availability. It's added by the surfacers right before the
+	// export, so the surfacer settings, e.g. allow_metrics_with_name and
+	// rate_metric, refer to the unprefixed names.
+	MetricPrefix *string `protobuf:"bytes,106,opt,name=metric_prefix,json=metricPrefix" json:"metric_prefix,omitempty"`
+	// Maximum run duration for a probe sweep over all the targets, in string
+	// format, e.g. 25s. If a sweep doesn't finish within this duration, probe
+	// stops sending requests to the remaining targets for that sweep, instead
+	// of letting the sweeps pile up. Skipped targets are exported through the
+	// "sweep_truncated" and "sweep_skipped_targets" metrics. Default is the
+	// probe interval.
+	//
+	// This is currently implemented only by EXTERNAL probes in the SERVER
+	// mode, where requests are sent to the targets one after another. In the
+	// ONCE mode, commands for all the targets are started together, bounded
+	// by the probe timeout, so this option has no effect.
+	MaxRunDuration *string `protobuf:"bytes,107,opt,name=max_run_duration,json=maxRunDuration" json:"max_run_duration,omitempty"`
+	// Connect timeout, in string format, e.g. 500ms. It bounds only the
+	// connection establishment (dial) phase, while the timeout bounds the
+	// whole probe run. It's useful to fail fast on the unreachable targets,
+	// while still allowing slow responses from the reachable ones. Probe runs
+	// that fail because of the connect timeout are counted in the
+	// "connect_timeouts" metric, instead of the "timeouts" metric. It should
+	// not be longer than the timeout. Default is to use the probe timeout.
+	//
+	// This is currently implemented only by TCP and HTTP probes.
+	ConnectTimeout *string `protobuf:"bytes,108,opt,name=connect_timeout,json=connectTimeout" json:"connect_timeout,omitempty"`
 	// Types that are assignable to Probe:
 	//
 	//	*ProbeDef_PingProbe
@@ -310,6 +576,7 @@ type ProbeDef struct {
 	//	*ProbeDef_UdpListenerProbe
 	//	*ProbeDef_GrpcProbe
 	//	*ProbeDef_TcpProbe
+	//	*ProbeDef_RedfishProbe
 	//	*ProbeDef_UserDefinedProbe
 	Probe isProbeDef_Probe `protobuf_oneof:"probe"`
 	// Which machines this probe should run on. If defined, cloudprober will run
@@ -325,6 +592,7 @@ type ProbeDef struct {
 const (
 	Default_ProbeDef_LatencyUnit       = string("us")
 	Default_ProbeDef_LatencyMetricName = string("latency")
+	Default_ProbeDef_NoTargetsPolicy   = ProbeDef_IGNORE
 )
 
 func (x *ProbeDef) Reset() {
@@ -436,6 +704,13 @@ func (x *ProbeDef) GetValidator() []*proto2.Validator {
 	return nil
 }
 
+func (x *ProbeDef) GetValidatorSet() []string {
+	if x != nil {
+		return x.ValidatorSet
+	}
+	return nil
+}
+
 func (m *ProbeDef) GetSourceIpConfig() isProbeDef_SourceIpConfig {
 	if m != nil {
 		return m.SourceIpConfig
@@ -492,6 +767,55 @@ func (x *ProbeDef) GetAlert() []*proto3.AlertConf {
 	return nil
 }
 
+func (x *ProbeDef) GetSurfacers() []string {
+	if x != nil {
+		return x.Surfacers
+	}
+	return nil
+}
+
+func (x *ProbeDef) GetNoTargetsPolicy() ProbeDef_NoTargetsPolicy {
+	if x != nil && x.NoTargetsPolicy != nil {
+		return *x.NoTargetsPolicy
+	}
+	return Default_ProbeDef_NoTargetsPolicy
+}
+
+func (x *ProbeDef) GetTargetLabelTransform() *TargetLabelTransform {
+	if x != nil {
+		return x.TargetLabelTransform
+	}
+	return nil
+}
+
+func (x *ProbeDef) GetTargetRemovalGracePeriod() string {
+	if x != nil && x.TargetRemovalGracePeriod != nil {
+		return *x.TargetRemovalGracePeriod
+	}
+	return ""
+}
+
+func (x *ProbeDef) GetMetricPrefix() string {
+	if x != nil && x.MetricPrefix != nil {
+		return *x.MetricPrefix
+	}
+	return ""
+}
+
+func (x *ProbeDef) GetMaxRunDuration() string {
+	if x != nil && x.MaxRunDuration != nil {
+		return *x.MaxRunDuration
+	}
+	return ""
+}
+
+func (x *ProbeDef) GetConnectTimeout() string {
+	if x != nil && x.ConnectTimeout != nil {
+		return *x.ConnectTimeout
+	}
+	return ""
+}
+
 func (m *ProbeDef) GetProbe() isProbeDef_Probe {
 	if m != nil {
 		return m.Probe
@@ -555,6 +879,13 @@ func (x *ProbeDef) GetTcpProbe() *proto11.ProbeConf {
 	return nil
 }
 
+func (x *ProbeDef) GetRedfishProbe() *proto12.ProbeConf {
+	if x, ok := x.GetProbe().(*ProbeDef_RedfishProbe); ok {
+		return x.RedfishProbe
+	}
+	return nil
+}
+
 func (x *ProbeDef) GetUserDefinedProbe() string {
 	if x, ok := x.GetProbe().(*ProbeDef_UserDefinedProbe); ok {
 		return x.UserDefinedProbe
@@ -628,6 +959,10 @@ type ProbeDef_TcpProbe struct {
 	TcpProbe *proto11.ProbeConf `protobuf:"bytes,27,opt,name=tcp_probe,json=tcpProbe,oneof"`
 }
 
+type ProbeDef_RedfishProbe struct {
+	RedfishProbe *proto12.ProbeConf `protobuf:"bytes,28,opt,name=redfish_probe,json=redfishProbe,oneof"`
+}
+
 type ProbeDef_UserDefinedProbe struct {
 	// This field's contents are passed on to the user defined probe,
 	// registered for this probe's name through probes.RegisterUserDefined().
@@ -650,8 +985,83 @@ func (*ProbeDef_GrpcProbe) isProbeDef_Probe() {}
 
 func (*ProbeDef_TcpProbe) isProbeDef_Probe() {}
 
+func (*ProbeDef_RedfishProbe) isProbeDef_Probe() {}
+
 func (*ProbeDef_UserDefinedProbe) isProbeDef_Probe() {}
 
+// TargetLabelTransform is applied to the "dst" label value of the probe's
+// metrics. If both function and regex are specified, function is applied
+// first. If transform results in an empty string, original value is used.
+type TargetLabelTransform struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Function *TargetLabelTransform_Function `protobuf:"varint,1,opt,name=function,enum=cloudprober.probes.TargetLabelTransform_Function" json:"function,omitempty"`
+	// Regex to match the target name against. If it matches, target name is
+	// replaced with the replacement (which may refer to the regex's capture
+	// groups, e.g. "$1"). Target names that don't match are left as is.
+	// Example, to map IP addresses of a subnet to a stable name:
+	//
+	//	regex: "^10\\.0\\.0\\.\\d+$"
+	//	replacement: "prod-subnet"
+	Regex       *string `protobuf:"bytes,2,opt,name=regex" json:"regex,omitempty"`
+	Replacement *string `protobuf:"bytes,3,opt,name=replacement" json:"replacement,omitempty"`
+}
+
+func (x *TargetLabelTransform) Reset() {
+	*x = TargetLabelTransform{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TargetLabelTransform) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TargetLabelTransform) ProtoMessage() {}
+
+func (x *TargetLabelTransform) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TargetLabelTransform.ProtoReflect.Descriptor instead.
+func (*TargetLabelTransform) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TargetLabelTransform) GetFunction() TargetLabelTransform_Function {
+	if x != nil && x.Function != nil {
+		return *x.Function
+	}
+	return TargetLabelTransform_NONE
+}
+
+func (x *TargetLabelTransform) GetRegex() string {
+	if x != nil && x.Regex != nil {
+		return *x.Regex
+	}
+	return ""
+}
+
+func (x *TargetLabelTransform) GetReplacement() string {
+	if x != nil && x.Replacement != nil {
+		return *x.Replacement
+	}
+	return ""
+}
+
 type AdditionalLabel struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -661,13 +1071,22 @@ type AdditionalLabel struct {
 	// Value can either be a static value or can be derived from target's labels.
 	// To get value from target's labels, use target.labels.<target's label key>
 	// as value.
-	Value *string `protobuf:"bytes,2,req,name=value" json:"value,omitempty"`
+	Value       *string                      `protobuf:"bytes,2,req,name=value" json:"value,omitempty"`
+	MergePolicy *AdditionalLabel_MergePolicy `protobuf:"varint,3,opt,name=merge_policy,json=mergePolicy,enum=cloudprober.probes.AdditionalLabel_MergePolicy,def=0" json:"merge_policy,omitempty"`
+	// Separator for the CONCAT merge policy.
+	Separator *string `protobuf:"bytes,4,opt,name=separator,def=," json:"separator,omitempty"`
 }
 
+// Default values for AdditionalLabel fields.
+const (
+	Default_AdditionalLabel_MergePolicy = AdditionalLabel_OVERRIDE
+	Default_AdditionalLabel_Separator   = string(",")
+)
+
 func (x *AdditionalLabel) Reset() {
 	*x = AdditionalLabel{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[1]
+		mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -680,7 +1099,7 @@ func (x *AdditionalLabel) String() string {
 func (*AdditionalLabel) ProtoMessage() {}
 
 func (x *AdditionalLabel) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[1]
+	mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -693,7 +1112,7 @@ func (x *AdditionalLabel) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AdditionalLabel.ProtoReflect.Descriptor instead.
 func (*AdditionalLabel) Descriptor() ([]byte, []int) {
-	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZIP(), []int{1}
+	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *AdditionalLabel) GetKey() string {
@@ -710,6 +1129,20 @@ func (x *AdditionalLabel) GetValue() string {
 	return ""
 }
 
+func (x *AdditionalLabel) GetMergePolicy() AdditionalLabel_MergePolicy {
+	if x != nil && x.MergePolicy != nil {
+		return *x.MergePolicy
+	}
+	return Default_AdditionalLabel_MergePolicy
+}
+
+func (x *AdditionalLabel) GetSeparator() string {
+	if x != nil && x.Separator != nil {
+		return *x.Separator
+	}
+	return Default_AdditionalLabel_Separator
+}
+
 type DebugOptions struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -722,7 +1155,7 @@ type DebugOptions struct {
 func (x *DebugOptions) Reset() {
 	*x = DebugOptions{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[2]
+		mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -735,7 +1168,7 @@ func (x *DebugOptions) String() string {
 func (*DebugOptions) ProtoMessage() {}
 
 func (x *DebugOptions) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[2]
+	mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -748,7 +1181,7 @@ func (x *DebugOptions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DebugOptions.ProtoReflect.Descriptor instead.
 func (*DebugOptions) Descriptor() ([]byte, []int) {
-	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZIP(), []int{2}
+	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *DebugOptions) GetLogMetrics() bool {
@@ -795,120 +1228,157 @@ var file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDesc =
 	0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f,
 	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62,
 	0x65, 0x73, 0x2f, 0x70, 0x69, 0x6e, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x40, 0x67, 0x69, 0x74, 0x68,
+	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x44, 0x67, 0x69, 0x74, 0x68,
 	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62,
 	0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70,
-	0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x74, 0x63, 0x70, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f,
-	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x40, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
-	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
-	0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x75, 0x64, 0x70, 0x2f, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x48,
+	0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x72, 0x65, 0x64, 0x66, 0x69, 0x73, 0x68, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x40, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x74, 0x63, 0x70, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x40, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x75, 0x64,
+	0x70, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x48, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f,
+	0x75, 0x64, 0x70, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x3e,
 	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64,
 	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62,
-	0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x75, 0x64, 0x70, 0x6c, 0x69, 0x73,
-	0x74, 0x65, 0x6e, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
-	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
-	0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x74, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x61, 0x72, 0x67, 0x65,
-	0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x49, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
-	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
-	0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74,
-	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73,
-	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x22, 0xb0, 0x0e, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44, 0x65, 0x66,
-	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x02,
-	0x28, 0x0e, 0x32, 0x21, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
-	0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44, 0x65, 0x66,
-	0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x69,
-	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x6d, 0x73, 0x65, 0x63, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x65, 0x63,
-	0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x10, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x21, 0x0a, 0x0c,
-	0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x6d, 0x73, 0x65, 0x63, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x4d, 0x73, 0x65, 0x63, 0x12,
-	0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x39, 0x0a, 0x07, 0x74, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x63, 0x6c, 0x6f,
-	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73,
-	0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x44, 0x65, 0x66, 0x52, 0x07, 0x74, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x73, 0x12, 0x4c, 0x0a, 0x14, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f,
-	0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
-	0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x52, 0x13, 0x6c,
-	0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0c, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x75, 0x6e,
-	0x69, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x3a, 0x02, 0x75, 0x73, 0x52, 0x0b, 0x6c, 0x61,
-	0x74, 0x65, 0x6e, 0x63, 0x79, 0x55, 0x6e, 0x69, 0x74, 0x12, 0x37, 0x0a, 0x13, 0x6c, 0x61, 0x74,
-	0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x3a, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x52,
-	0x11, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x4e, 0x61,
-	0x6d, 0x65, 0x12, 0x3f, 0x0a, 0x09, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x18,
-	0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f,
-	0x62, 0x65, 0x72, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x2e, 0x56,
-	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x09, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
-	0x74, 0x6f, 0x72, 0x12, 0x1d, 0x0a, 0x09, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x69, 0x70,
-	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x08, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
-	0x49, 0x70, 0x12, 0x2b, 0x0a, 0x10, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x74,
-	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0f,
-	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12,
-	0x45, 0x0a, 0x0a, 0x69, 0x70, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0c, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x26, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65,
+	0x65, 0x72, 0x2f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x49,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62,
+	0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8a, 0x13, 0x0a, 0x08, 0x50, 0x72,
+	0x6f, 0x62, 0x65, 0x44, 0x65, 0x66, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x02, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x02, 0x20, 0x02, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x50, 0x72,
+	0x6f, 0x62, 0x65, 0x44, 0x65, 0x66, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x6d, 0x73,
+	0x65, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x4d, 0x73, 0x65, 0x63, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x6d, 0x73,
+	0x65, 0x63, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75,
+	0x74, 0x4d, 0x73, 0x65, 0x63, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12,
+	0x39, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1f, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x44, 0x65,
+	0x66, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x4c, 0x0a, 0x14, 0x6c, 0x61,
+	0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x44,
+	0x69, 0x73, 0x74, 0x52, 0x13, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x44, 0x69, 0x73, 0x74,
+	0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0c, 0x6c, 0x61, 0x74, 0x65,
+	0x6e, 0x63, 0x79, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x3a, 0x02,
+	0x75, 0x73, 0x52, 0x0b, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x55, 0x6e, 0x69, 0x74, 0x12,
+	0x37, 0x0a, 0x13, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x3a, 0x07, 0x6c, 0x61,
+	0x74, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x11, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x3f, 0x0a, 0x09, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x63, 0x6c,
+	0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x6f, 0x72, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x09,
+	0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x5f, 0x73, 0x65, 0x74, 0x18, 0x68, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0c, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x53, 0x65, 0x74, 0x12, 0x1d,
+	0x0a, 0x09, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x69, 0x70, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x00, 0x52, 0x08, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x49, 0x70, 0x12, 0x2b, 0x0a,
+	0x10, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63,
+	0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0f, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x45, 0x0a, 0x0a, 0x69, 0x70,
+	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x26,
+	0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f,
+	0x62, 0x65, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44, 0x65, 0x66, 0x2e, 0x49, 0x50, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x69, 0x70, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x3b, 0x0a, 0x1a, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x65, 0x78, 0x70, 0x6f, 0x72,
+	0x74, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x6d, 0x73, 0x65, 0x63, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x17, 0x73, 0x74, 0x61, 0x74, 0x73, 0x45, 0x78, 0x70, 0x6f,
+	0x72, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x65, 0x63, 0x12, 0x4e,
+	0x0a, 0x10, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x5f, 0x6c, 0x61, 0x62,
+	0x65, 0x6c, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x41, 0x64,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x0f, 0x61,
+	0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x23,
+	0x0a, 0x0d, 0x6e, 0x65, 0x67, 0x61, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x74, 0x65, 0x73, 0x74, 0x18,
+	0x12, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x6e, 0x65, 0x67, 0x61, 0x74, 0x69, 0x76, 0x65, 0x54,
+	0x65, 0x73, 0x74, 0x12, 0x35, 0x0a, 0x05, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x18, 0x13, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
+	0x2e, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x2e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x43,
+	0x6f, 0x6e, 0x66, 0x52, 0x05, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x75,
+	0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x73, 0x18, 0x65, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x73,
+	0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x73, 0x12, 0x60, 0x0a, 0x11, 0x6e, 0x6f, 0x5f, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x66, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x2c, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65,
 	0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44, 0x65,
-	0x66, 0x2e, 0x49, 0x50, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x69, 0x70, 0x56,
-	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3b, 0x0a, 0x1a, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f,
-	0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f,
-	0x6d, 0x73, 0x65, 0x63, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x17, 0x73, 0x74, 0x61, 0x74,
-	0x73, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d,
-	0x73, 0x65, 0x63, 0x12, 0x4e, 0x0a, 0x10, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61,
-	0x6c, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e,
-	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62,
-	0x65, 0x73, 0x2e, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x4c, 0x61, 0x62,
-	0x65, 0x6c, 0x52, 0x0f, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x4c, 0x61,
-	0x62, 0x65, 0x6c, 0x12, 0x23, 0x0a, 0x0d, 0x6e, 0x65, 0x67, 0x61, 0x74, 0x69, 0x76, 0x65, 0x5f,
-	0x74, 0x65, 0x73, 0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x6e, 0x65, 0x67, 0x61,
-	0x74, 0x69, 0x76, 0x65, 0x54, 0x65, 0x73, 0x74, 0x12, 0x35, 0x0a, 0x05, 0x61, 0x6c, 0x65, 0x72,
-	0x74, 0x18, 0x13, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70,
-	0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x2e, 0x41,
-	0x6c, 0x65, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x52, 0x05, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x12,
-	0x43, 0x0a, 0x0a, 0x70, 0x69, 0x6e, 0x67, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x14, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65,
-	0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x70, 0x69, 0x6e, 0x67, 0x2e, 0x50, 0x72,
-	0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x48, 0x01, 0x52, 0x09, 0x70, 0x69, 0x6e, 0x67, 0x50,
-	0x72, 0x6f, 0x62, 0x65, 0x12, 0x43, 0x0a, 0x0a, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x70, 0x72, 0x6f,
-	0x62, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64,
-	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x68, 0x74,
-	0x74, 0x70, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x48, 0x01, 0x52, 0x09,
-	0x68, 0x74, 0x74, 0x70, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x40, 0x0a, 0x09, 0x64, 0x6e, 0x73,
-	0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x63,
-	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65,
-	0x73, 0x2e, 0x64, 0x6e, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x48,
-	0x01, 0x52, 0x08, 0x64, 0x6e, 0x73, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x4f, 0x0a, 0x0e, 0x65,
-	0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x17, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65,
-	0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61,
-	0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x48, 0x01, 0x52, 0x0d, 0x65,
-	0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x40, 0x0a, 0x09,
-	0x75, 0x64, 0x70, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x21, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72,
-	0x6f, 0x62, 0x65, 0x73, 0x2e, 0x75, 0x64, 0x70, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f,
-	0x6e, 0x66, 0x48, 0x01, 0x52, 0x08, 0x75, 0x64, 0x70, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x59,
-	0x0a, 0x12, 0x75, 0x64, 0x70, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x5f, 0x70,
-	0x72, 0x6f, 0x62, 0x65, 0x18, 0x19, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x63, 0x6c, 0x6f,
+	0x66, 0x2e, 0x4e, 0x6f, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x3a, 0x06, 0x49, 0x47, 0x4e, 0x4f, 0x52, 0x45, 0x52, 0x0f, 0x6e, 0x6f, 0x54, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x73, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x5e, 0x0a, 0x16, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x66, 0x6f, 0x72, 0x6d, 0x18, 0x67, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x63, 0x6c, 0x6f,
 	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e,
-	0x75, 0x64, 0x70, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x62,
-	0x65, 0x43, 0x6f, 0x6e, 0x66, 0x48, 0x01, 0x52, 0x10, 0x75, 0x64, 0x70, 0x4c, 0x69, 0x73, 0x74,
-	0x65, 0x6e, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x43, 0x0a, 0x0a, 0x67, 0x72, 0x70,
-	0x63, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e,
-	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62,
-	0x65, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e,
-	0x66, 0x48, 0x01, 0x52, 0x09, 0x67, 0x72, 0x70, 0x63, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x40,
-	0x0a, 0x09, 0x74, 0x63, 0x70, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x1b, 0x20, 0x01, 0x28,
+	0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x66, 0x6f, 0x72, 0x6d, 0x52, 0x14, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65,
+	0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x3d, 0x0a, 0x1b, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x5f, 0x67, 0x72, 0x61,
+	0x63, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x69, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x18, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x47, 0x72,
+	0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x6a, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x28,
+	0x0a, 0x10, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x75, 0x6e, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x6b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x52, 0x75, 0x6e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x6e, 0x6e,
+	0x65, 0x63, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x6c, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75,
+	0x74, 0x12, 0x43, 0x0a, 0x0a, 0x70, 0x69, 0x6e, 0x67, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18,
+	0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f,
+	0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x70, 0x69, 0x6e, 0x67, 0x2e,
+	0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x48, 0x01, 0x52, 0x09, 0x70, 0x69, 0x6e,
+	0x67, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x43, 0x0a, 0x0a, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x70,
+	0x72, 0x6f, 0x62, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e,
+	0x68, 0x74, 0x74, 0x70, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x48, 0x01,
+	0x52, 0x09, 0x68, 0x74, 0x74, 0x70, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x40, 0x0a, 0x09, 0x64,
+	0x6e, 0x73, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21,
+	0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f,
+	0x62, 0x65, 0x73, 0x2e, 0x64, 0x6e, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e,
+	0x66, 0x48, 0x01, 0x52, 0x08, 0x64, 0x6e, 0x73, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x4f, 0x0a,
+	0x0e, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18,
+	0x17, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f,
+	0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x65, 0x78, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x48, 0x01, 0x52,
+	0x0d, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x40,
+	0x0a, 0x09, 0x75, 0x64, 0x70, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28,
 	0x0b, 0x32, 0x21, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e,
-	0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x74, 0x63, 0x70, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65,
-	0x43, 0x6f, 0x6e, 0x66, 0x48, 0x01, 0x52, 0x08, 0x74, 0x63, 0x70, 0x50, 0x72, 0x6f, 0x62, 0x65,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x75, 0x64, 0x70, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65,
+	0x43, 0x6f, 0x6e, 0x66, 0x48, 0x01, 0x52, 0x08, 0x75, 0x64, 0x70, 0x50, 0x72, 0x6f, 0x62, 0x65,
+	0x12, 0x59, 0x0a, 0x12, 0x75, 0x64, 0x70, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72,
+	0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x19, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65,
+	0x73, 0x2e, 0x75, 0x64, 0x70, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x50, 0x72,
+	0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x48, 0x01, 0x52, 0x10, 0x75, 0x64, 0x70, 0x4c, 0x69,
+	0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x43, 0x0a, 0x0a, 0x67,
+	0x72, 0x70, 0x63, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x22, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x48, 0x01, 0x52, 0x09, 0x67, 0x72, 0x70, 0x63, 0x50, 0x72, 0x6f, 0x62, 0x65,
+	0x12, 0x40, 0x0a, 0x09, 0x74, 0x63, 0x70, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x1b, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65,
+	0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x74, 0x63, 0x70, 0x2e, 0x50, 0x72, 0x6f,
+	0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x48, 0x01, 0x52, 0x08, 0x74, 0x63, 0x70, 0x50, 0x72, 0x6f,
+	0x62, 0x65, 0x12, 0x4c, 0x0a, 0x0d, 0x72, 0x65, 0x64, 0x66, 0x69, 0x73, 0x68, 0x5f, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x63, 0x6c, 0x6f, 0x75,
+	0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x72,
+	0x65, 0x64, 0x66, 0x69, 0x73, 0x68, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66,
+	0x48, 0x01, 0x52, 0x0c, 0x72, 0x65, 0x64, 0x66, 0x69, 0x73, 0x68, 0x50, 0x72, 0x6f, 0x62, 0x65,
 	0x12, 0x2e, 0x0a, 0x12, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x64, 0x65, 0x66, 0x69, 0x6e, 0x65, 0x64,
 	0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x63, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x10,
 	0x75, 0x73, 0x65, 0x72, 0x44, 0x65, 0x66, 0x69, 0x6e, 0x65, 0x64, 0x50, 0x72, 0x6f, 0x62, 0x65,
@@ -917,32 +1387,62 @@ var file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDesc =
 	0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x64, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20,
 	0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f,
 	0x62, 0x65, 0x73, 0x2e, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
-	0x52, 0x0c, 0x64, 0x65, 0x62, 0x75, 0x67, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x80,
+	0x52, 0x0c, 0x64, 0x65, 0x62, 0x75, 0x67, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x8d,
 	0x01, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x50, 0x49, 0x4e, 0x47, 0x10,
 	0x00, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x54, 0x54, 0x50, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x44,
 	0x4e, 0x53, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x45, 0x58, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c,
 	0x10, 0x03, 0x12, 0x07, 0x0a, 0x03, 0x55, 0x44, 0x50, 0x10, 0x04, 0x12, 0x10, 0x0a, 0x0c, 0x55,
 	0x44, 0x50, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x45, 0x4e, 0x45, 0x52, 0x10, 0x05, 0x12, 0x08, 0x0a,
 	0x04, 0x47, 0x52, 0x50, 0x43, 0x10, 0x06, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x43, 0x50, 0x10, 0x07,
-	0x12, 0x0d, 0x0a, 0x09, 0x45, 0x58, 0x54, 0x45, 0x4e, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x62, 0x12,
-	0x10, 0x0a, 0x0c, 0x55, 0x53, 0x45, 0x52, 0x5f, 0x44, 0x45, 0x46, 0x49, 0x4e, 0x45, 0x44, 0x10,
-	0x63, 0x22, 0x3b, 0x0a, 0x09, 0x49, 0x50, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a,
-	0x0a, 0x16, 0x49, 0x50, 0x5f, 0x56, 0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x4e, 0x53,
-	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x50,
-	0x56, 0x34, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x50, 0x56, 0x36, 0x10, 0x02, 0x2a, 0x09,
+	0x12, 0x0b, 0x0a, 0x07, 0x52, 0x45, 0x44, 0x46, 0x49, 0x53, 0x48, 0x10, 0x08, 0x12, 0x0d, 0x0a,
+	0x09, 0x45, 0x58, 0x54, 0x45, 0x4e, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x62, 0x12, 0x10, 0x0a, 0x0c,
+	0x55, 0x53, 0x45, 0x52, 0x5f, 0x44, 0x45, 0x46, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x63, 0x22, 0x3b,
+	0x0a, 0x09, 0x49, 0x50, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x16, 0x49,
+	0x50, 0x5f, 0x56, 0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x50, 0x56, 0x34, 0x10,
+	0x01, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x50, 0x56, 0x36, 0x10, 0x02, 0x22, 0x41, 0x0a, 0x0f, 0x4e,
+	0x6f, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x0a,
+	0x0a, 0x06, 0x49, 0x47, 0x4e, 0x4f, 0x52, 0x45, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x57, 0x41,
+	0x52, 0x4e, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x02, 0x12,
+	0x0d, 0x0a, 0x09, 0x45, 0x4d, 0x49, 0x54, 0x5f, 0x5a, 0x45, 0x52, 0x4f, 0x10, 0x03, 0x2a, 0x09,
 	0x08, 0xc8, 0x01, 0x10, 0x80, 0x80, 0x80, 0x80, 0x02, 0x42, 0x12, 0x0a, 0x10, 0x73, 0x6f, 0x75,
 	0x72, 0x63, 0x65, 0x5f, 0x69, 0x70, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x42, 0x07, 0x0a,
-	0x05, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x22, 0x39, 0x0a, 0x0f, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69,
-	0x6f, 0x6e, 0x61, 0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
-	0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x02, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x22, 0x2f, 0x0a, 0x0c, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x6f, 0x67, 0x5f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x6c, 0x6f, 0x67, 0x4d, 0x65, 0x74, 0x72, 0x69,
-	0x63, 0x73, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f,
-	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f,
-	0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x05, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x22, 0xd6, 0x01, 0x0a, 0x14, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x6f, 0x72, 0x6d, 0x12,
+	0x4d, 0x0a, 0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x31, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x4c, 0x61, 0x62,
+	0x65, 0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x46, 0x75, 0x6e, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14,
+	0x0a, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72,
+	0x65, 0x67, 0x65, 0x78, 0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x61,
+	0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x37, 0x0a, 0x08, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x08, 0x0a, 0x04, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09,
+	0x4c, 0x4f, 0x57, 0x45, 0x52, 0x43, 0x41, 0x53, 0x45, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x53,
+	0x48, 0x4f, 0x52, 0x54, 0x5f, 0x48, 0x4f, 0x53, 0x54, 0x4e, 0x41, 0x4d, 0x45, 0x10, 0x02, 0x22,
+	0xec, 0x01, 0x0a, 0x0f, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x02, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x5c, 0x0a, 0x0c, 0x6d,
+	0x65, 0x72, 0x67, 0x65, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x2f, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61,
+	0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x2e, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x3a, 0x08, 0x4f, 0x56, 0x45, 0x52, 0x52, 0x49, 0x44, 0x45, 0x52, 0x0b, 0x6d, 0x65,
+	0x72, 0x67, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x1f, 0x0a, 0x09, 0x73, 0x65, 0x70,
+	0x61, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x3a, 0x01, 0x2c, 0x52,
+	0x09, 0x73, 0x65, 0x70, 0x61, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x22, 0x32, 0x0a, 0x0b, 0x4d, 0x65,
+	0x72, 0x67, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x0c, 0x0a, 0x08, 0x4f, 0x56, 0x45,
+	0x52, 0x52, 0x49, 0x44, 0x45, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4f, 0x4e, 0x43, 0x41,
+	0x54, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x02, 0x22, 0x2f,
+	0x0a, 0x0c, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x6c, 0x6f, 0x67, 0x5f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0a, 0x6c, 0x6f, 0x67, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x42,
+	0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c,
+	0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70,
+	0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f,
 }
 
 var (
@@ -957,49 +1457,59 @@ func file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZ
 	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescData
 }
 
-var file_github_com_cloudprober_cloudprober_probes_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_github_com_cloudprober_cloudprober_probes_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_github_com_cloudprober_cloudprober_probes_proto_config_proto_goTypes = []interface{}{
-	(ProbeDef_Type)(0),        // 0: cloudprober.probes.ProbeDef.Type
-	(ProbeDef_IPVersion)(0),   // 1: cloudprober.probes.ProbeDef.IPVersion
-	(*ProbeDef)(nil),          // 2: cloudprober.probes.ProbeDef
-	(*AdditionalLabel)(nil),   // 3: cloudprober.probes.AdditionalLabel
-	(*DebugOptions)(nil),      // 4: cloudprober.probes.DebugOptions
-	(*proto.TargetsDef)(nil),  // 5: cloudprober.targets.TargetsDef
-	(*proto1.Dist)(nil),       // 6: cloudprober.metrics.Dist
-	(*proto2.Validator)(nil),  // 7: cloudprober.validators.Validator
-	(*proto3.AlertConf)(nil),  // 8: cloudprober.alerting.AlertConf
-	(*proto4.ProbeConf)(nil),  // 9: cloudprober.probes.ping.ProbeConf
-	(*proto5.ProbeConf)(nil),  // 10: cloudprober.probes.http.ProbeConf
-	(*proto6.ProbeConf)(nil),  // 11: cloudprober.probes.dns.ProbeConf
-	(*proto7.ProbeConf)(nil),  // 12: cloudprober.probes.external.ProbeConf
-	(*proto8.ProbeConf)(nil),  // 13: cloudprober.probes.udp.ProbeConf
-	(*proto9.ProbeConf)(nil),  // 14: cloudprober.probes.udplistener.ProbeConf
-	(*proto10.ProbeConf)(nil), // 15: cloudprober.probes.grpc.ProbeConf
-	(*proto11.ProbeConf)(nil), // 16: cloudprober.probes.tcp.ProbeConf
+	(ProbeDef_Type)(0),                 // 0: cloudprober.probes.ProbeDef.Type
+	(ProbeDef_IPVersion)(0),            // 1: cloudprober.probes.ProbeDef.IPVersion
+	(ProbeDef_NoTargetsPolicy)(0),      // 2: cloudprober.probes.ProbeDef.NoTargetsPolicy
+	(TargetLabelTransform_Function)(0), // 3: cloudprober.probes.TargetLabelTransform.Function
+	(AdditionalLabel_MergePolicy)(0),   // 4: cloudprober.probes.AdditionalLabel.MergePolicy
+	(*ProbeDef)(nil),                   // 5: cloudprober.probes.ProbeDef
+	(*TargetLabelTransform)(nil),       // 6: cloudprober.probes.TargetLabelTransform
+	(*AdditionalLabel)(nil),            // 7: cloudprober.probes.AdditionalLabel
+	(*DebugOptions)(nil),               // 8: cloudprober.probes.DebugOptions
+	(*proto.TargetsDef)(nil),           // 9: cloudprober.targets.TargetsDef
+	(*proto1.Dist)(nil),                // 10: cloudprober.metrics.Dist
+	(*proto2.Validator)(nil),           // 11: cloudprober.validators.Validator
+	(*proto3.AlertConf)(nil),           // 12: cloudprober.alerting.AlertConf
+	(*proto4.ProbeConf)(nil),           // 13: cloudprober.probes.ping.ProbeConf
+	(*proto5.ProbeConf)(nil),           // 14: cloudprober.probes.http.ProbeConf
+	(*proto6.ProbeConf)(nil),           // 15: cloudprober.probes.dns.ProbeConf
+	(*proto7.ProbeConf)(nil),           // 16: cloudprober.probes.external.ProbeConf
+	(*proto8.ProbeConf)(nil),           // 17: cloudprober.probes.udp.ProbeConf
+	(*proto9.ProbeConf)(nil),           // 18: cloudprober.probes.udplistener.ProbeConf
+	(*proto10.ProbeConf)(nil),          // 19: cloudprober.probes.grpc.ProbeConf
+	(*proto11.ProbeConf)(nil),          // 20: cloudprober.probes.tcp.ProbeConf
+	(*proto12.ProbeConf)(nil),          // 21: cloudprober.probes.redfish.ProbeConf
 }
 var file_github_com_cloudprober_cloudprober_probes_proto_config_proto_depIdxs = []int32{
 	0,  // 0: cloudprober.probes.ProbeDef.type:type_name -> cloudprober.probes.ProbeDef.Type
-	5,  // 1: cloudprober.probes.ProbeDef.targets:type_name -> cloudprober.targets.TargetsDef
-	6,  // 2: cloudprober.probes.ProbeDef.latency_distribution:type_name -> cloudprober.metrics.Dist
-	7,  // 3: cloudprober.probes.ProbeDef.validator:type_name -> cloudprober.validators.Validator
+	9,  // 1: cloudprober.probes.ProbeDef.targets:type_name -> cloudprober.targets.TargetsDef
+	10, // 2: cloudprober.probes.ProbeDef.latency_distribution:type_name -> cloudprober.metrics.Dist
+	11, // 3: cloudprober.probes.ProbeDef.validator:type_name -> cloudprober.validators.Validator
 	1,  // 4: cloudprober.probes.ProbeDef.ip_version:type_name -> cloudprober.probes.ProbeDef.IPVersion
-	3,  // 5: cloudprober.probes.ProbeDef.additional_label:type_name -> cloudprober.probes.AdditionalLabel
-	8,  // 6: cloudprober.probes.ProbeDef.alert:type_name -> cloudprober.alerting.AlertConf
-	9,  // 7: cloudprober.probes.ProbeDef.ping_probe:type_name -> cloudprober.probes.ping.ProbeConf
-	10, // 8: cloudprober.probes.ProbeDef.http_probe:type_name -> cloudprober.probes.http.ProbeConf
-	11, // 9: cloudprober.probes.ProbeDef.dns_probe:type_name -> cloudprober.probes.dns.ProbeConf
-	12, // 10: cloudprober.probes.ProbeDef.external_probe:type_name -> cloudprober.probes.external.ProbeConf
-	13, // 11: cloudprober.probes.ProbeDef.udp_probe:type_name -> cloudprober.probes.udp.ProbeConf
-	14, // 12: cloudprober.probes.ProbeDef.udp_listener_probe:type_name -> cloudprober.probes.udplistener.ProbeConf
-	15, // 13: cloudprober.probes.ProbeDef.grpc_probe:type_name -> cloudprober.probes.grpc.ProbeConf
-	16, // 14: cloudprober.probes.ProbeDef.tcp_probe:type_name -> cloudprober.probes.tcp.ProbeConf
-	4,  // 15: cloudprober.probes.ProbeDef.debug_options:type_name -> cloudprober.probes.DebugOptions
-	16, // [16:16] is the sub-list for method output_type
-	16, // [16:16] is the sub-list for method input_type
-	16, // [16:16] is the sub-list for extension type_name
-	16, // [16:16] is the sub-list for extension extendee
-	0,  // [0:16] is the sub-list for field type_name
+	7,  // 5: cloudprober.probes.ProbeDef.additional_label:type_name -> cloudprober.probes.AdditionalLabel
+	12, // 6: cloudprober.probes.ProbeDef.alert:type_name -> cloudprober.alerting.AlertConf
+	2,  // 7: cloudprober.probes.ProbeDef.no_targets_policy:type_name -> cloudprober.probes.ProbeDef.NoTargetsPolicy
+	6,  // 8: cloudprober.probes.ProbeDef.target_label_transform:type_name -> cloudprober.probes.TargetLabelTransform
+	13, // 9: cloudprober.probes.ProbeDef.ping_probe:type_name -> cloudprober.probes.ping.ProbeConf
+	14, // 10: cloudprober.probes.ProbeDef.http_probe:type_name -> cloudprober.probes.http.ProbeConf
+	15, // 11: cloudprober.probes.ProbeDef.dns_probe:type_name -> cloudprober.probes.dns.ProbeConf
+	16, // 12: cloudprober.probes.ProbeDef.external_probe:type_name -> cloudprober.probes.external.ProbeConf
+	17, // 13: cloudprober.probes.ProbeDef.udp_probe:type_name -> cloudprober.probes.udp.ProbeConf
+	18, // 14: cloudprober.probes.ProbeDef.udp_listener_probe:type_name -> cloudprober.probes.udplistener.ProbeConf
+	19, // 15: cloudprober.probes.ProbeDef.grpc_probe:type_name -> cloudprober.probes.grpc.ProbeConf
+	20, // 16: cloudprober.probes.ProbeDef.tcp_probe:type_name -> cloudprober.probes.tcp.ProbeConf
+	21, // 17: cloudprober.probes.ProbeDef.redfish_probe:type_name -> cloudprober.probes.redfish.ProbeConf
+	8,  // 18: cloudprober.probes.ProbeDef.debug_options:type_name -> cloudprober.probes.DebugOptions
+	3,  // 19: cloudprober.probes.TargetLabelTransform.function:type_name -> cloudprober.probes.TargetLabelTransform.Function
+	4,  // 20: cloudprober.probes.AdditionalLabel.merge_policy:type_name -> cloudprober.probes.AdditionalLabel.MergePolicy
+	21, // [21:21] is the sub-list for method output_type
+	21, // [21:21] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_github_com_cloudprober_cloudprober_probes_proto_config_proto_init() }
@@ -1023,7 +1533,7 @@ func file_github_com_cloudprober_cloudprober_probes_proto_config_proto_init() {
 			}
 		}
 		file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AdditionalLabel); i {
+			switch v := v.(*TargetLabelTransform); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1035,6 +1545,18 @@ func file_github_com_cloudprober_cloudprober_probes_proto_config_proto_init() {
 			}
 		}
 		file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdditionalLabel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DebugOptions); i {
 			case 0:
 				return &v.state
@@ -1058,6 +1580,7 @@ func file_github_com_cloudprober_cloudprober_probes_proto_config_proto_init() {
 		(*ProbeDef_UdpListenerProbe)(nil),
 		(*ProbeDef_GrpcProbe)(nil),
 		(*ProbeDef_TcpProbe)(nil),
+		(*ProbeDef_RedfishProbe)(nil),
 		(*ProbeDef_UserDefinedProbe)(nil),
 	}
 	type x struct{}
@@ -1065,8 +1588,8 @@ func file_github_com_cloudprober_cloudprober_probes_proto_config_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDesc,
-			NumEnums:      2,
-			NumMessages:   3,
+			NumEnums:      5,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -13,9 +13,10 @@ import (
 	proto_3 "github.com/cloudprober/cloudprober/probes/udplistener/proto"
 	proto_A2 "github.com/cloudprober/cloudprober/probes/grpc/proto"
 	proto_F "github.com/cloudprober/cloudprober/probes/tcp/proto"
+	proto_D0 "github.com/cloudprober/cloudprober/probes/redfish/proto"
 )
 
-// Next tag: 101
+// Next tag: 109
 #ProbeDef: {
 	// Probe name. It should be unique across all probes.
 	name?: string @protobuf(1,string)
@@ -27,7 +28,8 @@ import (
 		{"UDP", #enumValue: 4} |
 		{"UDP_LISTENER", #enumValue: 5} |
 		{"GRPC", #enumValue: 6} |
-		{"TCP", #enumValue: 7} | {
+		{"TCP", #enumValue: 7} |
+		{"REDFISH", #enumValue: 8} | {
 			// One of the extension probe types. See "extensions" below for more
 			// details.
 			"EXTENSION"
@@ -50,6 +52,7 @@ import (
 		UDP_LISTENER: 5
 		GRPC:         6
 		TCP:          7
+		REDFISH:      8
 		EXTENSION:    98
 		USER_DEFINED: 99
 	}
@@ -61,6 +64,7 @@ import (
 	intervalMsec?: int32 @protobuf(4,int32,name=interval_msec)
 
 	// Interval between two probe runs in string format, e.g. 10s.
+	// Unit is required: bare numbers, e.g. "10", are rejected.
 	// Only one of "interval" and "inteval_msec" should be defined.
 	// Default interval is 2s.
 	interval?: string @protobuf(16,string)
@@ -71,6 +75,7 @@ import (
 	timeoutMsec?: int32 @protobuf(5,int32,name=timeout_msec)
 
 	// Timeout for each probe in string format, e.g. 10s.
+	// Unit is required: bare numbers, e.g. "10", are rejected.
 	// Only one of "timeout" and "timeout_msec" should be defined.
 	// Default timeout is 1s.
 	timeout?: string @protobuf(17,string)
@@ -105,6 +110,11 @@ import (
 	// Validators for this probe. Validators are run on the data returned by the
 	// probe. See https://cloudprober.org/docs/how-to/validators/ for more info.
 	validator?: [...proto_5.#Validator] @protobuf(9,validators.Validator)
+
+	// Names of the validator sets (see ProberConfig's validator_set) to use for
+	// this probe. Validators from the sets are added before the validators
+	// defined above. Validator names should be unique across all of them.
+	validatorSet?: [...string] @protobuf(104,string,name=validator_set)
 	// Set the source IP to send packets from, either by providing an IP address
 	// directly, or a network interface.
 	{} | {
@@ -182,6 +192,98 @@ import (
 	//    notify { ... }
 	//  }
 	alert?: [...proto_A.#AlertConf] @protobuf(19,alerting.AlertConf)
+
+	// Names of the surfacers that this probe's metrics should go to. If not
+	// specified, probe's metrics go to all surfacers. Surfacers are referred to
+	// by their name, or by their type in lower case (e.g. "file") if they don't
+	// have a name.
+	// Example:
+	//   surfacers: "local-file"
+	//   surfacers: "prometheus"
+	surfacers?: [...string] @protobuf(101,string)
+
+	// What to do if probe has no targets, e.g. because static targets list is
+	// empty or targets discovery returned nothing.
+	#NoTargetsPolicy: {
+		// Do nothing. Probe runs silently without any targets.
+		"IGNORE"
+		#enumValue: 0
+	} | {
+		// Log a warning whenever probe's targets list becomes empty.
+		"WARN"
+		#enumValue: 1
+	} | {
+		// Fail probe initialization if probe has static targets (host_names or
+		// endpoints) and the list is empty. For other targets, we log an error
+		// and export the "no_targets" metric, as for EMIT_ZERO.
+		"ERROR"
+		#enumValue: 2
+	} | {
+		// Export a "no_targets" gauge metric at the stats export interval: 1 if
+		// probe has no targets, 0 otherwise. This makes it possible to tell
+		// discovery failures apart from all targets being down.
+		"EMIT_ZERO"
+		#enumValue: 3
+	}
+
+	#NoTargetsPolicy_value: {
+		IGNORE:    0
+		WARN:      1
+		ERROR:     2
+		EMIT_ZERO: 3
+	}
+	noTargetsPolicy?: #NoTargetsPolicy @protobuf(102,NoTargetsPolicy,name=no_targets_policy,"default=IGNORE")
+
+	// Transform to normalize target names into stable "dst" label values, e.g.
+	// to map "web-1.prod.internal" to "web-1". See TargetLabelTransform below.
+	targetLabelTransform?: #TargetLabelTransform @protobuf(103,TargetLabelTransform,name=target_label_transform)
+
+	// Grace period for the removed targets, in string format, e.g. 30s. If
+	// set, targets removed from the targets list (e.g. by discovery during a
+	// scale-down) continue to be probed for this duration before their probe
+	// loops are stopped, so that in-flight probes are not abruptly canceled. If
+	// a target comes back within the grace period, probing continues as before.
+	// Once the grace period is over, the target's probe loop stops after the
+	// in-flight probe, if any, finishes.
+	//
+	// This is currently implemented only by HTTP and TCP probes.
+	targetRemovalGracePeriod?: string @protobuf(105,string,name=target_removal_grace_period)
+
+	// Prefix for the names of the metrics exported by this probe, e.g. with
+	// metric_prefix "team_a_", "total" is exported as "team_a_total". This is
+	// useful to avoid metric name collisions when multiple cloudprober instances
+	// export to the same backend. Prefix should be a valid Prometheus metric
+	// name prefix, i.e. it should match [a-zA-Z_:][a-zA-Z0-9_:]*. If not set,
+	// the global metric_prefix (in ProberConfig) is used.
+	// Prefix is also added to the metrics that cloudprober generates for the
+	// probe, e.g. availability. It's added by the surfacers right before the
+	// export, so the surfacer settings, e.g. allow_metrics_with_name and
+	// rate_metric, refer to the unprefixed names.
+	metricPrefix?: string @protobuf(106,string,name=metric_prefix)
+
+	// Maximum run duration for a probe sweep over all the targets, in string
+	// format, e.g. 25s. If a sweep doesn't finish within this duration, probe
+	// stops sending requests to the remaining targets for that sweep, instead
+	// of letting the sweeps pile up. Skipped targets are exported through the
+	// "sweep_truncated" and "sweep_skipped_targets" metrics. Default is the
+	// probe interval.
+	//
+	// This is currently implemented only by EXTERNAL probes in the SERVER
+	// mode, where requests are sent to the targets one after another. In the
+	// ONCE mode, commands for all the targets are started together, bounded
+	// by the probe timeout, so this option has no effect.
+	maxRunDuration?: string @protobuf(107,string,name=max_run_duration)
+
+	// Connect timeout, in string format, e.g. 500ms. It bounds only the
+	// connection establishment (dial) phase, while the timeout bounds the
+	// whole probe run. It's useful to fail fast on the unreachable targets,
+	// while still allowing slow responses from the reachable ones. Probe runs
+	// that fail because of the connect timeout are counted in the
+	// "connect_timeouts" metric, instead of the "timeouts" metric. It should
+	// not be longer than the timeout. Default is to use the probe timeout.
+	//
+	// This is currently implemented only by TCP and HTTP probes.
+	connectTimeout?: string @protobuf(108,string,name=connect_timeout)
 	{} | {
 		pingProbe: proto_8.#ProbeConf @protobuf(20,ping.ProbeConf,name=ping_probe)
 	} | {
@@ -198,6 +300,8 @@ import (
 		grpcProbe: proto_A2.#ProbeConf @protobuf(26,grpc.ProbeConf,name=grpc_probe)
 	} | {
 		tcpProbe: proto_F.#ProbeConf @protobuf(27,tcp.ProbeConf,name=tcp_probe)
+	} | {
+		redfishProbe: proto_D0.#ProbeConf @protobuf(28,redfish.ProbeConf,name=redfish_probe)
 	} | {
 		// This field's contents are passed on to the user defined probe,
 		// registered for this probe's name through probes.RegisterUserDefined().
@@ -214,6 +318,38 @@ import (
 	debugOptions?: #DebugOptions @protobuf(100,DebugOptions,name=debug_options)
 }
 
+// TargetLabelTransform is applied to the "dst" label value of the probe's
+// metrics. If both function and regex are specified, function is applied
+// first. If transform results in an empty string, original value is used.
+#TargetLabelTransform: {
+	#Function: {"NONE", #enumValue: 0} | {
+		// Convert to lower case.
+		"LOWERCASE"
+		#enumValue: 1
+	} | {
+		// Keep only the first component of domain names, e.g.
+		// "web-1.prod.internal" becomes "web-1". IP addresses are left as is.
+		"SHORT_HOSTNAME"
+		#enumValue: 2
+	}
+
+	#Function_value: {
+		NONE:           0
+		LOWERCASE:      1
+		SHORT_HOSTNAME: 2
+	}
+	function?: #Function @protobuf(1,Function)
+
+	// Regex to match the target name against. If it matches, target name is
+	// replaced with the replacement (which may refer to the regex's capture
+	// groups, e.g. "$1"). Target names that don't match are left as is.
+	// Example, to map IP addresses of a subnet to a stable name:
+	//   regex: "^10\\.0\\.0\\.\\d+$"
+	//   replacement: "prod-subnet"
+	regex?:       string @protobuf(2,string)
+	replacement?: string @protobuf(3,string)
+}
+
 #AdditionalLabel: {
 	key?: string @protobuf(1,string)
 
@@ -221,6 +357,34 @@ import (
 	// To get value from target's labels, use target.labels.<target's label key>
 	// as value.
 	value?: string @protobuf(2,string)
+
+	// How to merge this label with an earlier label with the same key, e.g. a
+	// global additional label (see additional_label in ProberConfig), or a
+	// probe template's label for the template instances.
+	#MergePolicy: {
+		// This label's value replaces the earlier value (last one wins).
+		"OVERRIDE"
+		#enumValue: 0
+	} | {
+		// This label's value is appended to the earlier value, with the
+		// separator in between, e.g. "team-a,critical" for tags.
+		"CONCAT"
+		#enumValue: 1
+	} | {
+		// Probe configuration is rejected.
+		"ERROR"
+		#enumValue: 2
+	}
+
+	#MergePolicy_value: {
+		OVERRIDE: 0
+		CONCAT:   1
+		ERROR:    2
+	}
+	mergePolicy?: #MergePolicy @protobuf(3,MergePolicy,name=merge_policy,"default=OVERRIDE")
+
+	// Separator for the CONCAT merge policy.
+	separator?: string @protobuf(4,string,#"default=",""#)
 }
 
 #DebugOptions: {
@@ -0,0 +1,275 @@
+// Configuration for the Redfish probe. Redfish probe checks the hardware
+// health of the servers through their BMCs' Redfish API. It reads the
+// computer systems from the systems collection (/redfish/v1/Systems by
+// default), and exports the health of each system and its components.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.21.5
+// source: github.com/cloudprober/cloudprober/probes/redfish/proto/config.proto
+
+package proto
+
+import (
+	proto "github.com/cloudprober/cloudprober/internal/tlsconfig/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Next tag: 10
+type ProbeConf struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Port for the Redfish requests. If not specified, port provided by the
+	// targets is used, falling back to the scheme's default port.
+	Port *int32 `protobuf:"varint,1,opt,name=port" json:"port,omitempty"`
+	// Whether to use HTTP instead of HTTPS. Redfish requires HTTPS, this is
+	// mostly useful for the Redfish emulators.
+	UseHttp *bool `protobuf:"varint,2,opt,name=use_http,json=useHttp" json:"use_http,omitempty"`
+	// Path of the systems collection.
+	SystemsPath *string `protobuf:"bytes,3,opt,name=systems_path,json=systemsPath,def=/redfish/v1/Systems" json:"systems_path,omitempty"`
+	// Username and password for the HTTP basic authentication. To avoid
+	// putting the password in the config, use envSecret, e.g.
+	//
+	//	password: "{{ envSecret "BMC_PASSWORD" }}"
+	Username *string `protobuf:"bytes,4,opt,name=username" json:"username,omitempty"`
+	Password *string `protobuf:"bytes,5,opt,name=password" json:"password,omitempty"`
+	// TLS config. If not specified, BMC's certificate is not validated as BMCs
+	// typically present self-signed certificates. To validate them, specify
+	// the CA certificate:
+	//
+	//	tls_config {
+	//	  ca_cert_file: "/etc/cloudprober/bmc-ca.pem"
+	//	}
+	TlsConfig *proto.TLSConfig `protobuf:"bytes,6,opt,name=tls_config,json=tlsConfig" json:"tls_config,omitempty"`
+	// System's linked resource collections to export the per-member health
+	// for, e.g. "Processors", "Memory", "Storage", "EthernetInterfaces". For
+	// processors and memory, summary health is always exported.
+	Subsystem []string `protobuf:"bytes,7,rep,name=subsystem" json:"subsystem,omitempty"`
+	// Whether to resolve the target before making the request. By default we
+	// resolve first if it's a discovered resource, e.g. a k8s endpoint.
+	ResolveFirst *bool `protobuf:"varint,8,opt,name=resolve_first,json=resolveFirst" json:"resolve_first,omitempty"`
+	// Interval between targets.
+	IntervalBetweenTargetsMsec *int32 `protobuf:"varint,9,opt,name=interval_between_targets_msec,json=intervalBetweenTargetsMsec,def=10" json:"interval_between_targets_msec,omitempty"`
+}
+
+// Default values for ProbeConf fields.
+const (
+	Default_ProbeConf_SystemsPath                = string("/redfish/v1/Systems")
+	Default_ProbeConf_IntervalBetweenTargetsMsec = int32(10)
+)
+
+func (x *ProbeConf) Reset() {
+	*x = ProbeConf{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProbeConf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeConf) ProtoMessage() {}
+
+func (x *ProbeConf) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeConf.ProtoReflect.Descriptor instead.
+func (*ProbeConf) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ProbeConf) GetPort() int32 {
+	if x != nil && x.Port != nil {
+		return *x.Port
+	}
+	return 0
+}
+
+func (x *ProbeConf) GetUseHttp() bool {
+	if x != nil && x.UseHttp != nil {
+		return *x.UseHttp
+	}
+	return false
+}
+
+func (x *ProbeConf) GetSystemsPath() string {
+	if x != nil && x.SystemsPath != nil {
+		return *x.SystemsPath
+	}
+	return Default_ProbeConf_SystemsPath
+}
+
+func (x *ProbeConf) GetUsername() string {
+	if x != nil && x.Username != nil {
+		return *x.Username
+	}
+	return ""
+}
+
+func (x *ProbeConf) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+func (x *ProbeConf) GetTlsConfig() *proto.TLSConfig {
+	if x != nil {
+		return x.TlsConfig
+	}
+	return nil
+}
+
+func (x *ProbeConf) GetSubsystem() []string {
+	if x != nil {
+		return x.Subsystem
+	}
+	return nil
+}
+
+func (x *ProbeConf) GetResolveFirst() bool {
+	if x != nil && x.ResolveFirst != nil {
+		return *x.ResolveFirst
+	}
+	return false
+}
+
+func (x *ProbeConf) GetIntervalBetweenTargetsMsec() int32 {
+	if x != nil && x.IntervalBetweenTargetsMsec != nil {
+		return *x.IntervalBetweenTargetsMsec
+	}
+	return Default_ProbeConf_IntervalBetweenTargetsMsec
+}
+
+var File_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto protoreflect.FileDescriptor
+
+var file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_rawDesc = []byte{
+	0x0a, 0x44, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x72, 0x65, 0x64, 0x66,
+	0x69, 0x73, 0x68, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1a, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f,
+	0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x72, 0x65, 0x64, 0x66, 0x69,
+	0x73, 0x68, 0x1a, 0x48, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f,
+	0x74, 0x6c, 0x73, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf5, 0x02, 0x0a,
+	0x09, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f,
+	0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x19,
+	0x0a, 0x08, 0x75, 0x73, 0x65, 0x5f, 0x68, 0x74, 0x74, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x75, 0x73, 0x65, 0x48, 0x74, 0x74, 0x70, 0x12, 0x36, 0x0a, 0x0c, 0x73, 0x79, 0x73,
+	0x74, 0x65, 0x6d, 0x73, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x3a,
+	0x13, 0x2f, 0x72, 0x65, 0x64, 0x66, 0x69, 0x73, 0x68, 0x2f, 0x76, 0x31, 0x2f, 0x53, 0x79, 0x73,
+	0x74, 0x65, 0x6d, 0x73, 0x52, 0x0b, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x50, 0x61, 0x74,
+	0x68, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x3f, 0x0a, 0x0a, 0x74, 0x6c, 0x73,
+	0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e,
+	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x74, 0x6c, 0x73, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x54, 0x4c, 0x53, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
+	0x09, 0x74, 0x6c, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x75,
+	0x62, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x73,
+	0x75, 0x62, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x73, 0x6f,
+	0x6c, 0x76, 0x65, 0x5f, 0x66, 0x69, 0x72, 0x73, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0c, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x46, 0x69, 0x72, 0x73, 0x74, 0x12, 0x45, 0x0a,
+	0x1d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x62, 0x65, 0x74, 0x77, 0x65, 0x65,
+	0x6e, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x5f, 0x6d, 0x73, 0x65, 0x63, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x05, 0x3a, 0x02, 0x31, 0x30, 0x52, 0x1a, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x42, 0x65, 0x74, 0x77, 0x65, 0x65, 0x6e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73,
+	0x4d, 0x73, 0x65, 0x63, 0x42, 0x39, 0x5a, 0x37, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65,
+	0x73, 0x2f, 0x72, 0x65, 0x64, 0x66, 0x69, 0x73, 0x68, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+}
+
+var (
+	file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_rawDescOnce sync.Once
+	file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_rawDescData = file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_rawDesc
+)
+
+func file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_rawDescGZIP() []byte {
+	file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_rawDescOnce.Do(func() {
+		file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_rawDescData)
+	})
+	return file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_rawDescData
+}
+
+var file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_goTypes = []interface{}{
+	(*ProbeConf)(nil),       // 0: cloudprober.probes.redfish.ProbeConf
+	(*proto.TLSConfig)(nil), // 1: cloudprober.tlsconfig.TLSConfig
+}
+var file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_depIdxs = []int32{
+	1, // 0: cloudprober.probes.redfish.ProbeConf.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_init() }
+func file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_init() {
+	if File_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProbeConf); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_goTypes,
+		DependencyIndexes: file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_depIdxs,
+		MessageInfos:      file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_msgTypes,
+	}.Build()
+	File_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto = out.File
+	file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_rawDesc = nil
+	file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_goTypes = nil
+	file_github_com_cloudprober_cloudprober_probes_redfish_proto_config_proto_depIdxs = nil
+}
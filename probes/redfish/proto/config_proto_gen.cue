@@ -0,0 +1,43 @@
+package proto
+
+import "github.com/cloudprober/cloudprober/internal/tlsconfig/proto"
+
+// Next tag: 10
+#ProbeConf: {
+	// Port for the Redfish requests. If not specified, port provided by the
+	// targets is used, falling back to the scheme's default port.
+	port?: int32 @protobuf(1,int32)
+
+	// Whether to use HTTP instead of HTTPS. Redfish requires HTTPS, this is
+	// mostly useful for the Redfish emulators.
+	useHttp?: bool @protobuf(2,bool,name=use_http)
+
+	// Path of the systems collection.
+	systemsPath?: string @protobuf(3,string,name=systems_path,#"default="/redfish/v1/Systems""#)
+
+	// Username and password for the HTTP basic authentication. To avoid
+	// putting the password in the config, use envSecret, e.g.
+	//   password: "{{ envSecret "BMC_PASSWORD" }}"
+	username?: string @protobuf(4,string)
+	password?: string @protobuf(5,string)
+
+	// TLS config. If not specified, BMC's certificate is not validated as BMCs
+	// typically present self-signed certificates. To validate them, specify
+	// the CA certificate:
+	//   tls_config {
+	//     ca_cert_file: "/etc/cloudprober/bmc-ca.pem"
+	//   }
+	tlsConfig?: proto.#TLSConfig @protobuf(6,tlsconfig.TLSConfig,name=tls_config)
+
+	// System's linked resource collections to export the per-member health
+	// for, e.g. "Processors", "Memory", "Storage", "EthernetInterfaces". For
+	// processors and memory, summary health is always exported.
+	subsystem?: [...string] @protobuf(7,string)
+
+	// Whether to resolve the target before making the request. By default we
+	// resolve first if it's a discovered resource, e.g. a k8s endpoint.
+	resolveFirst?: bool @protobuf(8,bool,name=resolve_first)
+
+	// Interval between targets.
+	intervalBetweenTargetsMsec?: int32 @protobuf(9,int32,name=interval_between_targets_msec,"default=10")
+}
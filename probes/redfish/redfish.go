@@ -0,0 +1,367 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package redfish implements a probe that checks the hardware health of the
+servers through the Redfish API of their BMCs.
+
+For each target, probe reads the systems collection and the systems in it,
+and exports their health as the "health" metric, keyed by "component":
+  - <system-id>: system's health rollup (health, if rollup is not available).
+  - <system-id>/Processors, <system-id>/Memory: processor and memory summary
+    health.
+  - <system-id>/<subsystem>/<member-id>: health of the members of the
+    configured subsystems, e.g. Storage.
+
+Health is exported as a number: 0 for OK, 1 for Warning, and 2 for Critical.
+Probe run is considered successful if all the Redfish requests succeed,
+irrespective of the health.
+*/
+package redfish
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudprober/cloudprober/internal/tlsconfig"
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/common/sched"
+	"github.com/cloudprober/cloudprober/probes/options"
+	configpb "github.com/cloudprober/cloudprober/probes/redfish/proto"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+)
+
+// maxResponseSize is the maximum size of a Redfish response that we read.
+const maxResponseSize = 10 * 1024 * 1024
+
+// healthValue maps the Redfish health values to the exported metric values.
+var healthValue = map[string]int64{
+	"OK":       0,
+	"Warning":  1,
+	"Critical": 2,
+}
+
+// Probe holds aggregate information about all probe runs, per-target.
+type Probe struct {
+	name     string
+	opts     *options.Options
+	c        *configpb.ProbeConf
+	l        *logger.Logger
+	client   *http.Client
+	dataChan chan *metrics.EventMetrics
+}
+
+type probeResult struct {
+	total, success int64
+	latency        metrics.LatencyValue
+}
+
+func (p *Probe) newResult() sched.ProbeResult {
+	result := &probeResult{}
+	if p.opts.LatencyDist != nil {
+		result.latency = p.opts.LatencyDist.CloneDist()
+	} else {
+		result.latency = metrics.NewFloat(0)
+	}
+	return result
+}
+
+func (result *probeResult) Metrics(ts time.Time, opts *options.Options) *metrics.EventMetrics {
+	return metrics.NewEventMetrics(ts).
+		AddMetric("total", metrics.NewInt(result.total)).
+		AddMetric("success", metrics.NewInt(result.success)).
+		AddMetric(opts.LatencyMetricName, result.latency.Clone()).
+		AddLabel("ptype", "redfish")
+}
+
+// Init initializes the probe with the given params.
+func (p *Probe) Init(name string, opts *options.Options) error {
+	if opts.ProbeConf == nil {
+		opts.ProbeConf = &configpb.ProbeConf{}
+	}
+
+	c, ok := opts.ProbeConf.(*configpb.ProbeConf)
+	if !ok {
+		return fmt.Errorf("not redfish probe config")
+	}
+	p.name = name
+	p.opts = opts
+	p.c = c
+	if p.l = opts.Logger; p.l == nil {
+		p.l = &logger.Logger{}
+	}
+
+	if !strings.HasPrefix(p.c.GetSystemsPath(), "/") {
+		return fmt.Errorf("systems_path (%s) should begin with a /", p.c.GetSystemsPath())
+	}
+
+	// BMCs typically present self-signed certificates, so we don't validate
+	// them unless TLS config is provided.
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	if p.c.GetTlsConfig() != nil {
+		tlsConfig = &tls.Config{}
+		if err := tlsconfig.UpdateTLSConfig(tlsConfig, p.c.GetTlsConfig()); err != nil {
+			return fmt.Errorf("error initializing TLS config (%+v): %v", p.c.GetTlsConfig(), err)
+		}
+	}
+
+	dialer := &net.Dialer{Timeout: p.opts.Timeout}
+	if p.opts.SourceIP != nil {
+		dialer.LocalAddr = &net.TCPAddr{IP: p.opts.SourceIP}
+	}
+	p.client = &http.Client{
+		Transport: &http.Transport{
+			DialContext:     dialer.DialContext,
+			TLSClientConfig: tlsConfig,
+			MaxIdleConns:    256,
+		},
+	}
+
+	return nil
+}
+
+// baseURL returns the base URL for the target, e.g. https://10.1.1.1:443.
+func (p *Probe) baseURL(target endpoint.Endpoint) (string, error) {
+	host := target.Name
+
+	resolveFirst := target.IP != nil
+	if p.c.ResolveFirst != nil {
+		resolveFirst = p.c.GetResolveFirst()
+	}
+	if resolveFirst {
+		ip, err := target.Resolve(p.opts.IPVersion, p.opts.Targets)
+		if err != nil {
+			return "", fmt.Errorf("resolve error: %v", err)
+		}
+		host = ip.String()
+	}
+
+	port := int(p.c.GetPort())
+	if port == 0 {
+		port = target.Port
+	}
+	if port != 0 {
+		host = net.JoinHostPort(host, strconv.Itoa(port))
+	} else if strings.Contains(host, ":") {
+		// IPv6 address without port.
+		host = "[" + host + "]"
+	}
+
+	scheme := "https"
+	if p.c.GetUseHttp() {
+		scheme = "http"
+	}
+	return scheme + "://" + host, nil
+}
+
+// get fetches the Redfish resource at the given path.
+func (p *Probe) get(ctx context.Context, baseURL, path string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("OData-Version", "4.0")
+	if p.c.GetUsername() != "" {
+		req.SetBasicAuth(p.c.GetUsername(), p.c.GetPassword())
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected status: %s", path, resp.Status)
+	}
+
+	var res map[string]interface{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&res); err != nil {
+		return nil, fmt.Errorf("%s: error parsing response: %v", path, err)
+	}
+	return res, nil
+}
+
+// link returns the path of the resource linked by the given property, e.g.
+// "Processors": {"@odata.id": "/redfish/v1/Systems/1/Processors"}.
+func link(v interface{}) string {
+	m, _ := v.(map[string]interface{})
+	s, _ := m["@odata.id"].(string)
+	return s
+}
+
+// members returns the paths of the collection members.
+func members(collection map[string]interface{}) []string {
+	items, _ := collection["Members"].([]interface{})
+	var paths []string
+	for _, item := range items {
+		if path := link(item); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// health returns the health from a Redfish Status object, preferring the
+// health rollup, which covers the dependent resources as well.
+func health(v interface{}) (string, bool) {
+	m, _ := v.(map[string]interface{})
+	status, _ := m["Status"].(map[string]interface{})
+	for _, field := range []string{"HealthRollup", "Health"} {
+		if h, ok := status[field].(string); ok && h != "" {
+			return h, true
+		}
+	}
+	return "", false
+}
+
+// resourceID returns the resource's Id, falling back to the last element of
+// its path.
+func resourceID(res map[string]interface{}, path string) string {
+	if id, ok := res["Id"].(string); ok && id != "" {
+		return id
+	}
+	return path[strings.LastIndex(strings.TrimSuffix(path, "/"), "/")+1:]
+}
+
+// systemHealth collects the health of a system and its components.
+func (p *Probe) systemHealth(ctx context.Context, baseURL, path string, hm map[string]string) error {
+	system, err := p.get(ctx, baseURL, path)
+	if err != nil {
+		return err
+	}
+	id := resourceID(system, path)
+
+	if h, ok := health(system); ok {
+		hm[id] = h
+	}
+	for component, field := range map[string]string{"Processors": "ProcessorSummary", "Memory": "MemorySummary"} {
+		if h, ok := health(system[field]); ok {
+			hm[id+"/"+component] = h
+		}
+	}
+
+	for _, subsystem := range p.c.GetSubsystem() {
+		collectionPath := link(system[subsystem])
+		if collectionPath == "" {
+			p.l.Warningf("System %s doesn't have the %s collection", id, subsystem)
+			continue
+		}
+		collection, err := p.get(ctx, baseURL, collectionPath)
+		if err != nil {
+			return err
+		}
+		for _, memberPath := range members(collection) {
+			member, err := p.get(ctx, baseURL, memberPath)
+			if err != nil {
+				return err
+			}
+			if h, ok := health(member); ok {
+				hm[id+"/"+subsystem+"/"+resourceID(member, memberPath)] = h
+			}
+		}
+	}
+	return nil
+}
+
+// targetHealth returns the health of all the systems and their components,
+// keyed by component.
+func (p *Probe) targetHealth(ctx context.Context, baseURL string) (map[string]string, error) {
+	systems, err := p.get(ctx, baseURL, p.c.GetSystemsPath())
+	if err != nil {
+		return nil, err
+	}
+
+	hm := make(map[string]string)
+	for _, path := range members(systems) {
+		if err := p.systemHealth(ctx, baseURL, path, hm); err != nil {
+			return nil, err
+		}
+	}
+	return hm, nil
+}
+
+func (p *Probe) healthEM(target endpoint.Endpoint, hm map[string]string) *metrics.EventMetrics {
+	healthMap := metrics.NewMap("component")
+	for component, h := range hm {
+		v, ok := healthValue[h]
+		if !ok {
+			p.l.Warningf("Target(%s): unknown health value %q for %s", target.Name, h, component)
+			continue
+		}
+		healthMap.IncKeyBy(component, v)
+	}
+
+	em := metrics.NewEventMetrics(time.Now()).
+		AddMetric("health", healthMap).
+		AddLabel("ptype", "redfish").
+		AddLabel("probe", p.name).
+		AddLabel("dst", target.Dst())
+	em.Kind = metrics.GAUGE
+	return em
+}
+
+func (p *Probe) runProbe(ctx context.Context, target endpoint.Endpoint, res sched.ProbeResult) {
+	ctx, cancelCtx := context.WithTimeout(ctx, p.opts.Timeout)
+	defer cancelCtx()
+
+	result := res.(*probeResult)
+	result.total++
+
+	baseURL, err := p.baseURL(target)
+	if err != nil {
+		p.l.Warningf("Target(%s): %v", target.Name, err)
+		return
+	}
+
+	start := time.Now()
+	hm, err := p.targetHealth(ctx, baseURL)
+	latency := time.Since(start)
+	if err != nil {
+		p.l.Warningf("Target(%s): error getting health from %s: %v", target.Name, baseURL, err)
+		return
+	}
+
+	result.success++
+	result.latency.AddFloat64(latency.Seconds() / p.opts.LatencyUnit.Seconds())
+
+	if p.dataChan != nil {
+		p.opts.RecordMetrics(target, p.healthEM(target, hm), p.dataChan, options.WithNoAlert())
+	}
+}
+
+// Start starts and runs the probe indefinitely.
+func (p *Probe) Start(ctx context.Context, dataChan chan *metrics.EventMetrics) {
+	p.dataChan = dataChan
+
+	s := &sched.Scheduler{
+		ProbeName:              p.name,
+		DataChan:               dataChan,
+		Opts:                   p.opts,
+		NewResult:              p.newResult,
+		RunProbeForTarget:      p.runProbe,
+		IntervalBetweenTargets: time.Duration(p.c.GetIntervalBetweenTargetsMsec()) * time.Millisecond,
+	}
+	s.UpdateTargetsAndStartProbes(ctx)
+}
@@ -0,0 +1,254 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/options"
+	configpb "github.com/cloudprober/cloudprober/probes/redfish/proto"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+var testResources = map[string]string{
+	"/redfish/v1/Systems": `{
+		"Members": [
+			{"@odata.id": "/redfish/v1/Systems/1"},
+			{"@odata.id": "/redfish/v1/Systems/2"}
+		]
+	}`,
+	"/redfish/v1/Systems/1": `{
+		"Id": "1",
+		"Status": {"Health": "OK", "HealthRollup": "Warning"},
+		"ProcessorSummary": {"Status": {"Health": "OK"}},
+		"MemorySummary": {"Status": {"HealthRollup": "Critical"}},
+		"Storage": {"@odata.id": "/redfish/v1/Systems/1/Storage"}
+	}`,
+	"/redfish/v1/Systems/1/Storage": `{
+		"Members": [{"@odata.id": "/redfish/v1/Systems/1/Storage/RAID.1"}]
+	}`,
+	"/redfish/v1/Systems/1/Storage/RAID.1": `{
+		"Status": {"Health": "Warning"}
+	}`,
+	"/redfish/v1/Systems/2": `{
+		"Id": "2",
+		"Status": {"Health": "OK"},
+		"ProcessorSummary": {"Status": {"Health": "Unknown"}}
+	}`,
+}
+
+func testServer(t *testing.T, tls bool) *httptest.Server {
+	t.Helper()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		res, ok := testResources[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(res))
+	})
+
+	var ts *httptest.Server
+	if tls {
+		ts = httptest.NewTLSServer(handler)
+	} else {
+		ts = httptest.NewServer(handler)
+	}
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func testTarget(t *testing.T, ts *httptest.Server) endpoint.Endpoint {
+	t.Helper()
+
+	u, _ := url.Parse(ts.URL)
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+	return endpoint.Endpoint{Name: host, Port: port}
+}
+
+func testProbe(t *testing.T, c *configpb.ProbeConf) *Probe {
+	t.Helper()
+
+	opts := options.DefaultOptions()
+	opts.ProbeConf = c
+	opts.Timeout = 2 * time.Second
+
+	p := &Probe{}
+	if err := p.Init("test-redfish", opts); err != nil {
+		t.Fatalf("Error initializing probe: %v", err)
+	}
+	return p
+}
+
+func TestInit(t *testing.T) {
+	p := &Probe{}
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{SystemsPath: proto.String("redfish/v1/Systems")}
+	assert.Error(t, p.Init("test-redfish", opts), "systems_path without leading slash")
+
+	p = testProbe(t, nil)
+	tlsConfig := p.client.Transport.(*http.Transport).TLSClientConfig
+	assert.True(t, tlsConfig.InsecureSkipVerify, "cert validation should be disabled by default")
+	assert.Equal(t, "/redfish/v1/Systems", p.c.GetSystemsPath())
+}
+
+func TestBaseURL(t *testing.T) {
+	tests := []struct {
+		desc   string
+		c      *configpb.ProbeConf
+		target endpoint.Endpoint
+		want   string
+	}{
+		{
+			desc:   "no_port",
+			c:      &configpb.ProbeConf{},
+			target: endpoint.Endpoint{Name: "bmc-1"},
+			want:   "https://bmc-1",
+		},
+		{
+			desc:   "target_port",
+			c:      &configpb.ProbeConf{},
+			target: endpoint.Endpoint{Name: "bmc-1", Port: 8443},
+			want:   "https://bmc-1:8443",
+		},
+		{
+			desc:   "config_port_http",
+			c:      &configpb.ProbeConf{Port: proto.Int32(8080), UseHttp: proto.Bool(true)},
+			target: endpoint.Endpoint{Name: "bmc-1", Port: 8443},
+			want:   "http://bmc-1:8080",
+		},
+		{
+			desc:   "target_ip",
+			c:      &configpb.ProbeConf{},
+			target: endpoint.Endpoint{Name: "bmc-1", IP: net.ParseIP("10.1.1.1")},
+			want:   "https://10.1.1.1",
+		},
+		{
+			desc:   "ipv6",
+			c:      &configpb.ProbeConf{},
+			target: endpoint.Endpoint{Name: "::1"},
+			want:   "https://[::1]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			p := testProbe(t, test.c)
+			got, err := p.baseURL(test.target)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestRunProbe(t *testing.T) {
+	tests := []struct {
+		desc        string
+		tls         bool
+		c           *configpb.ProbeConf
+		wantSuccess int64
+		wantHealth  map[string]int64
+	}{
+		{
+			desc: "self_signed_cert",
+			tls:  true,
+			c: &configpb.ProbeConf{
+				Username: proto.String("admin"),
+				Password: proto.String("secret"),
+			},
+			wantSuccess: 1,
+			wantHealth: map[string]int64{
+				"1":            1,
+				"1/Processors": 0,
+				"1/Memory":     2,
+				"2":            0,
+			},
+		},
+		{
+			desc: "http_with_subsystem",
+			c: &configpb.ProbeConf{
+				UseHttp:   proto.Bool(true),
+				Username:  proto.String("admin"),
+				Password:  proto.String("secret"),
+				Subsystem: []string{"Storage"},
+			},
+			wantSuccess: 1,
+			wantHealth: map[string]int64{
+				"1":                1,
+				"1/Processors":     0,
+				"1/Memory":         2,
+				"1/Storage/RAID.1": 1,
+				"2":                0,
+			},
+		},
+		{
+			desc: "bad_credentials",
+			tls:  true,
+			c: &configpb.ProbeConf{
+				Username: proto.String("admin"),
+				Password: proto.String("wrong"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ts := testServer(t, test.tls)
+			p := testProbe(t, test.c)
+			p.dataChan = make(chan *metrics.EventMetrics, 10)
+
+			target := testTarget(t, ts)
+			res := p.newResult().(*probeResult)
+			p.runProbe(context.Background(), target, res)
+
+			assert.Equal(t, int64(1), res.total, "total")
+			assert.Equal(t, test.wantSuccess, res.success, "success")
+
+			if test.wantHealth == nil {
+				assert.Len(t, p.dataChan, 0)
+				return
+			}
+
+			assert.Len(t, p.dataChan, 1)
+			em := <-p.dataChan
+			assert.True(t, em.Kind == metrics.GAUGE)
+			assert.Equal(t, target.Dst(), em.Label("dst"))
+
+			healthMap := em.Metric("health").(*metrics.Map[int64])
+			gotHealth := make(map[string]int64)
+			for _, k := range healthMap.Keys() {
+				gotHealth[k] = healthMap.GetKey(k)
+			}
+			assert.Equal(t, test.wantHealth, gotHealth)
+		})
+	}
+}
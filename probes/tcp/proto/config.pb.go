@@ -20,7 +20,7 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// Next tag: 4
+// Next tag: 10
 type ProbeConf struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -37,11 +37,35 @@ type ProbeConf struct {
 	ResolveFirst *bool `protobuf:"varint,2,opt,name=resolve_first,json=resolveFirst" json:"resolve_first,omitempty"`
 	// Interval between targets.
 	IntervalBetweenTargetsMsec *int32 `protobuf:"varint,3,opt,name=interval_between_targets_msec,json=intervalBetweenTargetsMsec,def=10" json:"interval_between_targets_msec,omitempty"`
+	// Socket receive and send buffer sizes in bytes. If not set, system
+	// defaults are used. Valid range is 1024 to 67108864 (64MiB).
+	ReadBufferSize  *int32 `protobuf:"varint,4,opt,name=read_buffer_size,json=readBufferSize" json:"read_buffer_size,omitempty"`
+	WriteBufferSize *int32 `protobuf:"varint,5,opt,name=write_buffer_size,json=writeBufferSize" json:"write_buffer_size,omitempty"`
+	// TCP keep-alive interval in seconds. Keep-alive probes are sent once the
+	// connection has been idle for this long, and then at this interval. Set
+	// it to a negative value to disable keep-alives.
+	KeepaliveIntervalSec *int32 `protobuf:"varint,6,opt,name=keepalive_interval_sec,json=keepaliveIntervalSec,def=30" json:"keepalive_interval_sec,omitempty"`
+	// If set, SO_LINGER is set to this many seconds on the connection. A value
+	// of 0 makes the probe close the connection with a RST, instead of the
+	// normal FIN handshake.
+	LingerSec *int32 `protobuf:"varint,7,opt,name=linger_sec,json=lingerSec" json:"linger_sec,omitempty"`
+	// How long to hold the connection open after it's established. While the
+	// connection is held, probe watches for it being reset, e.g. by a firewall
+	// dropping idle connections. Use it with a short keepalive_interval_sec to
+	// verify that keep-alives traverse the network path. It should be less
+	// than the probe timeout.
+	HoldConnectionMsec *int32 `protobuf:"varint,8,opt,name=hold_connection_msec,json=holdConnectionMsec" json:"hold_connection_msec,omitempty"`
+	// Name of the custom dialer to make connections with. Custom dialers are
+	// registered using probes.RegisterDialer(), e.g. by the programs that embed
+	// cloudprober. Note that source IP and keep-alive settings don't apply to
+	// custom dialers.
+	Dialer *string `protobuf:"bytes,9,opt,name=dialer" json:"dialer,omitempty"`
 }
 
 // Default values for ProbeConf fields.
 const (
 	Default_ProbeConf_IntervalBetweenTargetsMsec = int32(10)
+	Default_ProbeConf_KeepaliveIntervalSec       = int32(30)
 )
 
 func (x *ProbeConf) Reset() {
@@ -97,6 +121,48 @@ func (x *ProbeConf) GetIntervalBetweenTargetsMsec() int32 {
 	return Default_ProbeConf_IntervalBetweenTargetsMsec
 }
 
+func (x *ProbeConf) GetReadBufferSize() int32 {
+	if x != nil && x.ReadBufferSize != nil {
+		return *x.ReadBufferSize
+	}
+	return 0
+}
+
+func (x *ProbeConf) GetWriteBufferSize() int32 {
+	if x != nil && x.WriteBufferSize != nil {
+		return *x.WriteBufferSize
+	}
+	return 0
+}
+
+func (x *ProbeConf) GetKeepaliveIntervalSec() int32 {
+	if x != nil && x.KeepaliveIntervalSec != nil {
+		return *x.KeepaliveIntervalSec
+	}
+	return Default_ProbeConf_KeepaliveIntervalSec
+}
+
+func (x *ProbeConf) GetLingerSec() int32 {
+	if x != nil && x.LingerSec != nil {
+		return *x.LingerSec
+	}
+	return 0
+}
+
+func (x *ProbeConf) GetHoldConnectionMsec() int32 {
+	if x != nil && x.HoldConnectionMsec != nil {
+		return *x.HoldConnectionMsec
+	}
+	return 0
+}
+
+func (x *ProbeConf) GetDialer() string {
+	if x != nil && x.Dialer != nil {
+		return *x.Dialer
+	}
+	return ""
+}
+
 var File_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto protoreflect.FileDescriptor
 
 var file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_rawDesc = []byte{
@@ -105,7 +171,7 @@ var file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_rawDes
 	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x74, 0x63, 0x70, 0x2f,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x12, 0x16, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e,
-	0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x74, 0x63, 0x70, 0x22, 0x8b, 0x01, 0x0a, 0x09, 0x50,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x74, 0x63, 0x70, 0x22, 0x84, 0x03, 0x0a, 0x09, 0x50,
 	0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x23, 0x0a, 0x0d,
 	0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x5f, 0x66, 0x69, 0x72, 0x73, 0x74, 0x18, 0x02, 0x20,
@@ -114,10 +180,26 @@ var file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_rawDes
 	0x74, 0x77, 0x65, 0x65, 0x6e, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x5f, 0x6d, 0x73,
 	0x65, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x02, 0x31, 0x30, 0x52, 0x1a, 0x69, 0x6e,
 	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x42, 0x65, 0x74, 0x77, 0x65, 0x65, 0x6e, 0x54, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x73, 0x4d, 0x73, 0x65, 0x63, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62,
-	0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70,
-	0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x74, 0x63, 0x70, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x67, 0x65, 0x74, 0x73, 0x4d, 0x73, 0x65, 0x63, 0x12, 0x28, 0x0a, 0x10, 0x72, 0x65, 0x61, 0x64,
+	0x5f, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0e, 0x72, 0x65, 0x61, 0x64, 0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x62, 0x75, 0x66, 0x66,
+	0x65, 0x72, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x77,
+	0x72, 0x69, 0x74, 0x65, 0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x38,
+	0x0a, 0x16, 0x6b, 0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x02,
+	0x33, 0x30, 0x52, 0x14, 0x6b, 0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x65, 0x63, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x69, 0x6e, 0x67,
+	0x65, 0x72, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6c, 0x69,
+	0x6e, 0x67, 0x65, 0x72, 0x53, 0x65, 0x63, 0x12, 0x30, 0x0a, 0x14, 0x68, 0x6f, 0x6c, 0x64, 0x5f,
+	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x73, 0x65, 0x63, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x12, 0x68, 0x6f, 0x6c, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x65, 0x63, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x61,
+	0x6c, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x69, 0x61, 0x6c, 0x65,
+	0x72, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75,
+	0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x74,
+	0x63, 0x70, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 }
 
 var (
@@ -1,6 +1,6 @@
 package proto
 
-// Next tag: 4
+// Next tag: 10
 #ProbeConf: {
 	// Port for TCP requests. If not specfied, and port is provided by the
 	// targets (e.g. kubernetes endpoint or service), that port is used.
@@ -15,4 +15,32 @@ package proto
 
 	// Interval between targets.
 	intervalBetweenTargetsMsec?: int32 @protobuf(3,int32,name=interval_between_targets_msec,"default=10")
+
+	// Socket receive and send buffer sizes in bytes. If not set, system
+	// defaults are used. Valid range is 1024 to 67108864 (64MiB).
+	readBufferSize?:  int32 @protobuf(4,int32,name=read_buffer_size)
+	writeBufferSize?: int32 @protobuf(5,int32,name=write_buffer_size)
+
+	// TCP keep-alive interval in seconds. Keep-alive probes are sent once the
+	// connection has been idle for this long, and then at this interval. Set
+	// it to a negative value to disable keep-alives.
+	keepaliveIntervalSec?: int32 @protobuf(6,int32,name=keepalive_interval_sec,"default=30")
+
+	// If set, SO_LINGER is set to this many seconds on the connection. A value
+	// of 0 makes the probe close the connection with a RST, instead of the
+	// normal FIN handshake.
+	lingerSec?: int32 @protobuf(7,int32,name=linger_sec)
+
+	// How long to hold the connection open after it's established. While the
+	// connection is held, probe watches for it being reset, e.g. by a firewall
+	// dropping idle connections. Use it with a short keepalive_interval_sec to
+	// verify that keep-alives traverse the network path. It should be less
+	// than the probe timeout.
+	holdConnectionMsec?: int32 @protobuf(8,int32,name=hold_connection_msec)
+
+	// Name of the custom dialer to make connections with. Custom dialers are
+	// registered using probes.RegisterDialer(), e.g. by the programs that embed
+	// cloudprober. Note that source IP and keep-alive settings don't apply to
+	// custom dialers.
+	dialer?: string @protobuf(9,string)
 }
@@ -17,9 +17,12 @@ package tcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/cloudprober/cloudprober/internal/validators"
@@ -27,7 +30,9 @@ import (
 	"github.com/cloudprober/cloudprober/metrics"
 	"github.com/cloudprober/cloudprober/probes/common/sched"
 	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/probes/probeutils"
 	configpb "github.com/cloudprober/cloudprober/probes/tcp/proto"
+	"github.com/cloudprober/cloudprober/targets"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
 )
 
@@ -41,10 +46,24 @@ type Probe struct {
 	// book-keeping params
 	network     string
 	dialContext func(context.Context, string, string) (net.Conn, error) // Keeps some dialing related config
+
+	// setBuffersAfterConnect is true if socket buffer sizes could not be set
+	// through the dialer, e.g. for custom dialers.
+	setBuffersAfterConnect bool
+
+	// resolveFirstAlways is set if targets must be resolved before probing,
+	// e.g. if they use a DNS-over-HTTPS resolver.
+	resolveFirstAlways bool
 }
 
+// holdReadBufferSize is the size of the buffer used to read and discard data
+// while holding a connection, if read_buffer_size is not configured.
+const holdReadBufferSize = 4096
+
 type probeResult struct {
 	total, success    int64
+	timeouts, resets  int64
+	connectTimeouts   int64
 	latency           metrics.LatencyValue
 	validationFailure *metrics.Map[int64]
 }
@@ -69,9 +88,15 @@ func (result *probeResult) Metrics(ts time.Time, opts *options.Options) *metrics
 	em := metrics.NewEventMetrics(ts).
 		AddMetric("total", metrics.NewInt(result.total)).
 		AddMetric("success", metrics.NewInt(result.success)).
+		AddMetric("timeouts", metrics.NewInt(result.timeouts)).
+		AddMetric("resets", metrics.NewInt(result.resets)).
 		AddMetric(opts.LatencyMetricName, result.latency.Clone()).
 		AddLabel("ptype", "tcp")
 
+	if opts.ConnectTimeout != 0 {
+		em.AddMetric("connect_timeouts", metrics.NewInt(result.connectTimeouts))
+	}
+
 	if result.validationFailure != nil {
 		em.AddMetric("validation_failure", result.validationFailure)
 	}
@@ -100,27 +125,74 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 		p.c = &configpb.ProbeConf{}
 	}
 
+	if err := probeutils.ValidateSocketBufferSize("read_buffer_size", p.c.GetReadBufferSize()); err != nil {
+		return err
+	}
+	if err := probeutils.ValidateSocketBufferSize("write_buffer_size", p.c.GetWriteBufferSize()); err != nil {
+		return err
+	}
+	if p.c.GetHoldConnectionMsec() < 0 {
+		return fmt.Errorf("hold_connection_msec (%d) cannot be negative", p.c.GetHoldConnectionMsec())
+	}
+	if hold := time.Duration(p.c.GetHoldConnectionMsec()) * time.Millisecond; hold >= p.opts.Timeout {
+		return fmt.Errorf("hold_connection_msec (%v) should be less than the probe timeout (%v)", hold, p.opts.Timeout)
+	}
+	if p.c.LingerSec != nil && p.c.GetLingerSec() < 0 {
+		return fmt.Errorf("linger_sec (%d) cannot be negative", p.c.GetLingerSec())
+	}
+
+	if targets.RequiresResolveFirst(p.opts.Targets) {
+		if p.c.ResolveFirst != nil && !p.c.GetResolveFirst() {
+			return fmt.Errorf("resolve_first cannot be false for targets resolved through DNS-over-HTTPS")
+		}
+		p.resolveFirstAlways = true
+	}
+
 	p.network = "tcp"
 	if p.opts.IPVersion != 0 {
 		p.network += strconv.Itoa(p.opts.IPVersion)
 	}
 
-	// Create a dialer for our use.
+	// Create a dialer for our use. Negative KeepAlive disables keep-alives.
 	dialer := &net.Dialer{
 		Timeout:   p.opts.Timeout,
-		KeepAlive: 30 * time.Second, // TCP keep-alive
+		KeepAlive: time.Duration(p.c.GetKeepaliveIntervalSec()) * time.Second,
 	}
 	if p.opts.SourceIP != nil {
 		dialer.LocalAddr = &net.TCPAddr{
 			IP: p.opts.SourceIP,
 		}
 	}
+	// Set socket buffer sizes before connecting, so that the receive buffer
+	// size is taken into account for the TCP window negotiation.
+	dialer.Control = probeutils.SocketBuffersControl(p.c.GetReadBufferSize(), p.c.GetWriteBufferSize())
+	p.setBuffersAfterConnect = dialer.Control == nil
 	p.dialContext = dialer.DialContext
 
+	if p.c.GetDialer() != "" {
+		d, err := probeutils.GetDialer(p.c.GetDialer())
+		if err != nil {
+			return err
+		}
+		p.dialContext = d.DialContext
+		p.setBuffersAfterConnect = true
+	}
+	if p.opts.ConnectTimeout != 0 {
+		p.dialContext = probeutils.WithConnectTimeout(p.dialContext, p.opts.ConnectTimeout)
+	}
+
 	return nil
 }
 
 func (p *Probe) runProbe(ctx context.Context, target endpoint.Endpoint, res sched.ProbeResult) {
+	// Wait for the connection rate limiter before the probe timeout and the
+	// latency measurement start, so that the wait doesn't show up as
+	// timeouts or latency.
+	if err := probeutils.WaitForConnRateLimit(ctx); err != nil {
+		p.l.Warning("Target:", target.Name, ", ", err.Error())
+		return
+	}
+
 	ctx, cancelCtx := context.WithTimeout(ctx, p.opts.Timeout)
 	defer cancelCtx()
 
@@ -136,7 +208,7 @@ func (p *Probe) runProbe(ctx context.Context, target endpoint.Endpoint, res sche
 	if p.c.ResolveFirst != nil {
 		resolveFirst = p.c.GetResolveFirst()
 	} else {
-		resolveFirst = target.IP != nil
+		resolveFirst = p.resolveFirstAlways || target.IP != nil
 	}
 	if resolveFirst {
 		ip, err := target.Resolve(p.opts.IPVersion, p.opts.Targets)
@@ -175,13 +247,87 @@ func (p *Probe) runProbe(ctx context.Context, target endpoint.Endpoint, res sche
 	}
 
 	if err != nil {
+		result.recordError(err)
 		p.l.Warning("Target:", target.Name, ", doTCP: ", err.Error())
 		return
 	}
+	if bs, ok := conn.(probeutils.SocketBufferSetter); ok && p.setBuffersAfterConnect {
+		if err := probeutils.SetSocketBuffers(bs, p.c.GetReadBufferSize(), p.c.GetWriteBufferSize()); err != nil {
+			p.l.Warning("Target:", target.Name, ", doTCP: ", err.Error())
+		}
+	}
+	if tcpConn, ok := conn.(*net.TCPConn); ok && p.c.LingerSec != nil {
+		if err := tcpConn.SetLinger(int(p.c.GetLingerSec())); err != nil {
+			p.l.Warning("Target:", target.Name, ", error setting linger: ", err.Error())
+		}
+	}
+	if conn != nil && p.c.GetHoldConnectionMsec() > 0 {
+		if err := holdConnection(conn, time.Duration(p.c.GetHoldConnectionMsec())*time.Millisecond, p.holdBufferSize()); err != nil {
+			result.recordError(err)
+			p.l.Warning("Target:", target.Name, ", error while holding connection: ", err.Error())
+			return
+		}
+	}
 	result.success++
 	result.latency.AddFloat64(latency.Seconds() / p.opts.LatencyUnit.Seconds())
 }
 
+// recordError records connection resets and timeouts separately, so that
+// connections dropped by firewalls can be told apart from unreachable
+// targets. Connect timeouts (if configured) are recorded separately from the
+// overall timeouts.
+func (result *probeResult) recordError(err error) {
+	switch {
+	case isReset(err):
+		result.resets++
+	case probeutils.IsConnectTimeout(err):
+		result.connectTimeouts++
+	case isTimeout(err):
+		result.timeouts++
+	}
+}
+
+func isReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET)
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// holdBufferSize returns the size of the buffer used while holding a
+// connection: read_buffer_size if configured, holdReadBufferSize otherwise.
+func (p *Probe) holdBufferSize() int {
+	if p.c.GetReadBufferSize() > 0 {
+		return int(p.c.GetReadBufferSize())
+	}
+	return holdReadBufferSize
+}
+
+// holdConnection keeps the connection open for the given duration, reading
+// and discarding any data sent by the remote end. It returns an error if
+// the connection is reset or closed by the remote end before that.
+func holdConnection(conn net.Conn, d time.Duration, bufSize int) error {
+	if err := conn.SetReadDeadline(time.Now().Add(d)); err != nil {
+		return err
+	}
+	buf := make([]byte, bufSize)
+	for {
+		_, err := conn.Read(buf)
+		if err == nil {
+			continue
+		}
+		if isTimeout(err) {
+			return nil
+		}
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("connection closed by the remote end: %v", err)
+		}
+		return err
+	}
+}
+
 // Start starts and runs the probe indefinitely.
 func (p *Probe) Start(ctx context.Context, dataChan chan *metrics.EventMetrics) {
 	s := &sched.Scheduler{
@@ -20,10 +20,17 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/cloudprober/cloudprober/metrics"
 	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/probes/probeutils"
+	configpb "github.com/cloudprober/cloudprober/probes/tcp/proto"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 )
 
 type dialState struct {
@@ -114,3 +121,254 @@ func TestRunProbe(t *testing.T) {
 	}
 
 }
+
+func TestSocketBufferSizes(t *testing.T) {
+	p := &Probe{}
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{ReadBufferSize: proto.Int32(100)}
+	if err := p.Init("test-probe", opts); err == nil {
+		t.Errorf("expected error for read_buffer_size below the limit, got nil")
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error starting listener: %v", err)
+	}
+	defer ln.Close()
+
+	p = &Probe{}
+	opts = options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{
+		ReadBufferSize:  proto.Int32(65536),
+		WriteBufferSize: proto.Int32(65536),
+	}
+	if err := p.Init("test-probe", opts); err != nil {
+		t.Fatalf("error initializing probe: %v", err)
+	}
+
+	res := p.newResult()
+	port := ln.Addr().(*net.TCPAddr).Port
+	p.runProbe(context.Background(), endpoint.Endpoint{Name: "127.0.0.1", Port: port}, res)
+	if result := res.(*probeResult); result.success != 1 {
+		t.Errorf("Got success: %d, wanted: 1", result.success)
+	}
+}
+
+func TestInitConnectionOptions(t *testing.T) {
+	tests := []struct {
+		desc    string
+		conf    *configpb.ProbeConf
+		wantErr bool
+	}{
+		{
+			desc: "default",
+			conf: &configpb.ProbeConf{},
+		},
+		{
+			desc: "valid",
+			conf: &configpb.ProbeConf{
+				KeepaliveIntervalSec: proto.Int32(-1),
+				LingerSec:            proto.Int32(0),
+				HoldConnectionMsec:   proto.Int32(100),
+			},
+		},
+		{
+			desc:    "negative-linger",
+			conf:    &configpb.ProbeConf{LingerSec: proto.Int32(-1)},
+			wantErr: true,
+		},
+		{
+			desc:    "negative-hold",
+			conf:    &configpb.ProbeConf{HoldConnectionMsec: proto.Int32(-1)},
+			wantErr: true,
+		},
+		{
+			desc:    "hold-more-than-timeout",
+			conf:    &configpb.ProbeConf{HoldConnectionMsec: proto.Int32(5000)},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			opts := options.DefaultOptions()
+			opts.Timeout = time.Second
+			opts.ProbeConf = test.conf
+			err := (&Probe{}).Init("test-probe", opts)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRecordError(t *testing.T) {
+	result := &probeResult{}
+	result.recordError(&net.OpError{Op: "read", Err: syscall.ECONNRESET})
+	result.recordError(context.DeadlineExceeded)
+	result.recordError(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED})
+	assert.Equal(t, int64(1), result.resets, "resets")
+	assert.Equal(t, int64(1), result.timeouts, "timeouts")
+}
+
+func TestHoldConnection(t *testing.T) {
+	for _, reset := range []bool{false, true} {
+		t.Run(fmt.Sprintf("reset=%v", reset), func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("error starting listener: %v", err)
+			}
+			defer ln.Close()
+
+			go func() {
+				conn, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				if !reset {
+					time.Sleep(time.Second)
+					conn.Close()
+					return
+				}
+				// Zero linger makes Close send a RST.
+				conn.(*net.TCPConn).SetLinger(0)
+				conn.Close()
+			}()
+
+			p := &Probe{}
+			opts := options.DefaultOptions()
+			opts.Timeout = time.Second
+			opts.ProbeConf = &configpb.ProbeConf{
+				KeepaliveIntervalSec: proto.Int32(1),
+				LingerSec:            proto.Int32(0),
+				HoldConnectionMsec:   proto.Int32(200),
+			}
+			if err := p.Init("test-probe", opts); err != nil {
+				t.Fatalf("error initializing probe: %v", err)
+			}
+
+			res := p.newResult()
+			port := ln.Addr().(*net.TCPAddr).Port
+			p.runProbe(context.Background(), endpoint.Endpoint{Name: "127.0.0.1", Port: port}, res)
+
+			result := res.(*probeResult)
+			wantSuccess, wantResets := int64(1), int64(0)
+			if reset {
+				wantSuccess, wantResets = 0, 1
+			}
+			assert.Equal(t, wantSuccess, result.success, "success")
+			assert.Equal(t, wantResets, result.resets, "resets")
+			assert.Equal(t, int64(0), result.timeouts, "timeouts")
+		})
+	}
+}
+
+type testDialer struct {
+	addrs []string
+}
+
+func (d *testDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	d.addrs = append(d.addrs, addr)
+	return (&net.Dialer{}).DialContext(ctx, network, addr)
+}
+
+func TestCustomDialer(t *testing.T) {
+	d := &testDialer{}
+	probeutils.RegisterDialer("tcp-test-dialer", d)
+
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{Dialer: proto.String("unknown-dialer")}
+	assert.Error(t, (&Probe{}).Init("test-probe", opts), "unregistered dialer")
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error starting listener: %v", err)
+	}
+	defer ln.Close()
+
+	p := &Probe{}
+	opts = options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{Dialer: proto.String("tcp-test-dialer")}
+	if err := p.Init("test-probe", opts); err != nil {
+		t.Fatalf("error initializing probe: %v", err)
+	}
+
+	res := p.newResult()
+	port := ln.Addr().(*net.TCPAddr).Port
+	p.runProbe(context.Background(), endpoint.Endpoint{Name: "127.0.0.1", Port: port}, res)
+	assert.Equal(t, int64(1), res.(*probeResult).success, "success")
+	assert.Equal(t, []string{ln.Addr().String()}, d.addrs)
+}
+
+// blockingDialer blocks until the context is done, like a dial to an
+// unreachable host.
+type blockingDialer struct{}
+
+func (blockingDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	<-ctx.Done()
+	return nil, &net.OpError{Op: "dial", Net: network, Err: ctx.Err()}
+}
+
+func TestConnectTimeout(t *testing.T) {
+	probeutils.RegisterDialer("tcp-blocking-dialer", blockingDialer{})
+
+	for _, connectTimeout := range []time.Duration{0, 10 * time.Millisecond} {
+		t.Run(connectTimeout.String(), func(t *testing.T) {
+			p := &Probe{}
+			opts := options.DefaultOptions()
+			opts.Timeout = 50 * time.Millisecond
+			opts.ConnectTimeout = connectTimeout
+			opts.ProbeConf = &configpb.ProbeConf{Dialer: proto.String("tcp-blocking-dialer")}
+			if err := p.Init("test-probe", opts); err != nil {
+				t.Fatalf("error initializing probe: %v", err)
+			}
+
+			res := p.newResult().(*probeResult)
+			p.runProbe(context.Background(), endpoint.Endpoint{Name: "test.com", Port: 80}, res)
+
+			em := res.Metrics(time.Now(), opts)
+			if connectTimeout == 0 {
+				assert.Equal(t, int64(1), res.timeouts, "timeouts")
+				assert.Nil(t, em.Metric("connect_timeouts"), "connect_timeouts metric")
+				return
+			}
+			assert.Equal(t, int64(0), res.timeouts, "timeouts")
+			assert.Equal(t, int64(1), res.connectTimeouts, "connect_timeouts")
+			assert.Equal(t, "1", em.Metric("connect_timeouts").String())
+		})
+	}
+}
+
+type dialerFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+func (f dialerFunc) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return f(ctx, network, addr)
+}
+
+func TestConnRateLimitWait(t *testing.T) {
+	defer probeutils.SetConnRateLimit(0, 0)
+
+	ds := &dialState{}
+	probeutils.RegisterDialer("tcp-rate-limit-test-dialer", dialerFunc(testDialContext(ds)))
+
+	p := &Probe{}
+	opts := options.DefaultOptions()
+	opts.Timeout = 50 * time.Millisecond
+	opts.ProbeConf = &configpb.ProbeConf{Dialer: proto.String("tcp-rate-limit-test-dialer")}
+	if err := p.Init("test-probe", opts); err != nil {
+		t.Fatalf("error initializing probe: %v", err)
+	}
+
+	// Second connection has to wait for ~100ms, more than the probe timeout.
+	assert.NoError(t, probeutils.SetConnRateLimit(10, 1))
+
+	res := p.newResult().(*probeResult)
+	for i := 0; i < 2; i++ {
+		p.runProbe(context.Background(), endpoint.Endpoint{Name: "test.com", Port: 80}, res)
+	}
+	assert.Equal(t, int64(2), res.total, "total")
+	assert.Equal(t, int64(2), res.success, "success")
+	assert.Equal(t, int64(0), res.timeouts, "timeouts")
+	assert.Less(t, res.latency.(*metrics.Float).Float64(), float64(opts.Timeout/opts.LatencyUnit), "latency")
+}
@@ -0,0 +1,231 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package udp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cloudprober/cloudprober/internal/udpmessage"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/probeutils"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// multicastResult stores the multicast mode probe results for a target.
+type multicastResult struct {
+	total, success int64
+	responders     int64 // Distinct responders in the last probe run.
+	latency        metrics.LatencyValue
+	target         endpoint.Endpoint
+}
+
+func (res *multicastResult) eventMetrics(probeName, latencyMetricName string) *metrics.EventMetrics {
+	return metrics.NewEventMetrics(time.Now()).
+		AddMetric("total", metrics.NewInt(res.total)).
+		AddMetric("success", metrics.NewInt(res.success)).
+		AddMetric("responders", metrics.NewInt(res.responders)).
+		AddMetric(latencyMetricName, res.latency.Clone()).
+		AddLabel("ptype", "udp").
+		AddLabel("probe", probeName).
+		AddLabel("dst", res.target.Name)
+}
+
+// initMulticast initializes the probe for the multicast mode. Sockets are
+// created per probe run in this mode, so there is no connection pool.
+func (p *Probe) initMulticast() error {
+	mc := p.c.GetMulticast()
+	if mc.GetMinResponders() < 1 {
+		return fmt.Errorf("UDP probe: multicast.min_responders (%d) should be at least 1", mc.GetMinResponders())
+	}
+	if mc.GetTtl() < 1 || mc.GetTtl() > 255 {
+		return fmt.Errorf("UDP probe: multicast.ttl (%d) should be between 1 and 255", mc.GetTtl())
+	}
+	p.ipVer = p.opts.IPVersion
+	p.mcastRes = make(map[string]*multicastResult)
+	return nil
+}
+
+// newMulticastConn returns a new UDP socket for probing the given multicast
+// group or broadcast address. Note that Go enables SO_BROADCAST on UDP
+// sockets by default.
+func (p *Probe) newMulticastConn(dst net.IP) (*net.UDPConn, error) {
+	udpAddr := &net.UDPAddr{Port: 0}
+	if p.opts.SourceIP != nil {
+		udpAddr.IP = p.opts.SourceIP
+	}
+	conn, err := net.ListenUDP("udp", udpAddr)
+	if err != nil {
+		return nil, err
+	}
+	if err := probeutils.SetSocketBuffers(conn, p.c.GetReadBufferSize(), p.c.GetWriteBufferSize()); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if dst.IsMulticast() {
+		ttl := int(p.c.GetMulticast().GetTtl())
+		if dst.To4() != nil {
+			err = ipv4.NewPacketConn(conn).SetMulticastTTL(ttl)
+		} else {
+			err = ipv6.NewPacketConn(conn).SetMulticastHopLimit(ttl)
+		}
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error setting multicast TTL to %d: %v", ttl, err)
+		}
+	}
+	return conn, nil
+}
+
+// probeMulticastTarget sends a probe packet to the target and collects
+// responses until the probe timeout. It returns the number of distinct
+// responders and time to the first response.
+func (p *Probe) probeMulticastTarget(target endpoint.Endpoint) (int, time.Duration, error) {
+	ip, err := p.opts.Targets.Resolve(target.Name, p.ipVer)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to resolve %s: %v", target.Name, err)
+	}
+	dstPort := int(p.c.GetPort())
+	if p.c.Port == nil && target.Port != 0 {
+		dstPort = target.Port
+	}
+
+	conn, err := p.newMulticastConn(ip)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer conn.Close()
+
+	flowState := p.fsm.FlowState(p.src, "", target.Name)
+	txTS := time.Now()
+	msg, seq, err := flowState.CreateMessage(txTS, p.payload, int(p.c.GetMaxLength()))
+	if err != nil {
+		return 0, 0, fmt.Errorf("error creating new message to probe target(%s): %v", target.Name, err)
+	}
+
+	conn.SetDeadline(txTS.Add(p.opts.Timeout))
+	if _, err := conn.WriteToUDP(msg, &net.UDPAddr{IP: ip, Port: dstPort}); err != nil {
+		return 0, 0, fmt.Errorf("unable to send to %s(%v): %v", target.Name, ip, err)
+	}
+
+	responders := make(map[string]bool)
+	var firstResponse time.Duration
+	b := make([]byte, p.readChunkSize())
+	for {
+		msgLen, raddr, err := conn.ReadFromUDP(b)
+		if err != nil {
+			if !isClientTimeout(err) {
+				p.l.Errorf("Receive error on %s (from %v): %v", conn.LocalAddr(), raddr, err)
+			}
+			break
+		}
+		rmsg, err := udpmessage.NewMessage(b[:msgLen])
+		if err != nil {
+			p.l.Debugf("Incoming message error from %s: %v", raddr, err)
+			continue
+		}
+		if rmsg.Seq() != seq || rmsg.Dst() != target.Name {
+			continue
+		}
+		if len(responders) == 0 {
+			firstResponse = time.Since(txTS)
+		}
+		responders[raddr.String()] = true
+	}
+
+	return len(responders), firstResponse, nil
+}
+
+// runMulticastProbe probes all targets in parallel and waits for the
+// responses to be collected.
+func (p *Probe) runMulticastProbe() {
+	var wg sync.WaitGroup
+	for _, target := range p.targets {
+		res := p.mcastRes[target.Name]
+
+		wg.Add(1)
+		go func(target endpoint.Endpoint, res *multicastResult) {
+			defer wg.Done()
+
+			numResponders, latency, err := p.probeMulticastTarget(target)
+			if err != nil {
+				p.l.Errorf("Probing %s failed: %v", target.Name, err)
+			}
+			res.total++
+			res.responders = int64(numResponders)
+			if numResponders >= int(p.c.GetMulticast().GetMinResponders()) {
+				res.success++
+			}
+			if numResponders > 0 {
+				res.latency.AddFloat64(latency.Seconds() / p.opts.LatencyUnit.Seconds())
+			}
+		}(target, res)
+	}
+	wg.Wait()
+}
+
+func (p *Probe) updateMulticastTargets() {
+	p.targets = p.opts.Targets.ListEndpoints()
+	if len(p.targets) > int(p.c.GetMaxTargets()) {
+		p.l.Warningf("Number of targets (%d) > maxTargets (%d). Truncating the targets list.", len(p.targets), p.c.GetMaxTargets())
+		p.targets = p.targets[:p.c.GetMaxTargets()]
+	}
+	current := make(map[string]bool)
+	for _, target := range p.targets {
+		current[target.Name] = true
+		if p.mcastRes[target.Name] == nil {
+			res := p.newProbeResult(target)
+			p.mcastRes[target.Name] = &multicastResult{latency: res.latency, target: target}
+		}
+	}
+
+	// Forget the removed targets, so that we stop exporting their metrics.
+	for name := range p.mcastRes {
+		if !current[name] {
+			delete(p.mcastRes, name)
+		}
+	}
+}
+
+// startMulticast runs the probe in multicast mode indefinitely.
+func (p *Probe) startMulticast(ctx context.Context, dataChan chan *metrics.EventMetrics) {
+	p.updateMulticastTargets()
+
+	probeTicker := time.NewTicker(p.opts.Interval)
+	defer probeTicker.Stop()
+	statsExportTicker := time.NewTicker(p.opts.StatsExportInterval)
+	defer statsExportTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-probeTicker.C:
+			p.runMulticastProbe()
+		case <-statsExportTicker.C:
+			for _, res := range p.mcastRes {
+				em := res.eventMetrics(p.name, p.opts.LatencyMetricName)
+				p.opts.RecordMetrics(res.target, em, dataChan)
+			}
+			p.updateMulticastTargets()
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !skip_udp_probe_test
+// +build !skip_udp_probe_test
+
+package udp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/internal/sysvars"
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/probes/options"
+	configpb "github.com/cloudprober/cloudprober/probes/udp/proto"
+	"github.com/cloudprober/cloudprober/targets"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// startMultiResponder starts a UDP server that echoes every packet back from
+// numResponders different sockets, simulating multiple multicast responders.
+func startMultiResponder(ctx context.Context, t *testing.T, numResponders int) int {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("Starting UDP server failed: %v", err)
+	}
+	var responders []*net.UDPConn
+	for i := 0; i < numResponders; i++ {
+		rConn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		if err != nil {
+			t.Fatalf("Starting UDP responder failed: %v", err)
+		}
+		responders = append(responders, rConn)
+	}
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+		for _, rConn := range responders {
+			rConn.Close()
+		}
+	}()
+
+	go func() {
+		b := make([]byte, 1500)
+		for {
+			msgLen, addr, err := conn.ReadFromUDP(b)
+			if err != nil {
+				return
+			}
+			for _, rConn := range responders {
+				if _, err := rConn.WriteToUDP(b[:msgLen], addr); err != nil {
+					t.Logf("Error sending message to %s: %v", addr, err)
+				}
+			}
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func TestMulticastProbe(t *testing.T) {
+	sysvars.Init(&logger.Logger{}, nil)
+
+	tests := []struct {
+		name          string
+		numResponders int
+		minResponders int32
+		wantSuccess   int64
+	}{
+		{"enough_responders", 3, 3, 2},
+		{"not_enough_responders", 2, 3, 0},
+		{"no_responders", 0, 1, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			port := startMultiResponder(ctx, t, test.numResponders)
+
+			p := &Probe{}
+			opts := &options.Options{
+				IPVersion: 4,
+				Targets:   targets.StaticTargets("127.0.0.1"),
+				Interval:  time.Second,
+				Timeout:   200 * time.Millisecond,
+				ProbeConf: &configpb.ProbeConf{
+					Port: proto.Int32(int32(port)),
+					Multicast: &configpb.MulticastConf{
+						MinResponders: proto.Int32(test.minResponders),
+					},
+				},
+				StatsExportInterval: 10 * time.Second,
+				LatencyUnit:         time.Microsecond,
+				LatencyMetricName:   "latency",
+			}
+			if err := p.Init("udp-multicast", opts); err != nil {
+				t.Fatalf("Error initializing UDP probe: %v", err)
+			}
+			assert.Nil(t, p.connList, "connection pool in multicast mode")
+
+			p.updateMulticastTargets()
+			for i := 0; i < 2; i++ {
+				p.runMulticastProbe()
+			}
+
+			res := p.mcastRes["127.0.0.1"]
+			assert.Equal(t, int64(2), res.total, "total")
+			assert.Equal(t, test.wantSuccess, res.success, "success")
+			assert.Equal(t, int64(test.numResponders), res.responders, "responders")
+
+			em := res.eventMetrics(p.name, opts.LatencyMetricName)
+			assert.Equal(t, int64(test.numResponders), extractMetric(em, "responders"))
+			assert.Equal(t, "127.0.0.1", em.Label("dst"))
+
+			// Results for the removed targets are dropped.
+			opts.Targets = targets.StaticTargets("127.0.0.2")
+			p.updateMulticastTargets()
+			assert.NotContains(t, p.mcastRes, "127.0.0.1")
+			assert.Contains(t, p.mcastRes, "127.0.0.2")
+		})
+	}
+}
+
+func TestMulticastInitErrors(t *testing.T) {
+	for name, mc := range map[string]*configpb.MulticastConf{
+		"min_responders": {MinResponders: proto.Int32(0)},
+		"ttl":            {Ttl: proto.Int32(256)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := &Probe{}
+			opts := &options.Options{
+				Targets:             targets.StaticTargets("127.0.0.1"),
+				Interval:            time.Second,
+				Timeout:             200 * time.Millisecond,
+				ProbeConf:           &configpb.ProbeConf{Multicast: mc},
+				StatsExportInterval: 10 * time.Second,
+			}
+			assert.Error(t, p.Init("udp-multicast", opts))
+		})
+	}
+}
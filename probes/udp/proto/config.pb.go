@@ -20,6 +20,70 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type MulticastConf struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Minimum number of responders for a probe run to be counted as success.
+	MinResponders *int32 `protobuf:"varint,1,opt,name=min_responders,json=minResponders,def=1" json:"min_responders,omitempty"`
+	// TTL (hop limit for IPv6) of the multicast packets. Default of 1 keeps
+	// the packets on the local network segment. Not used for broadcast.
+	Ttl *int32 `protobuf:"varint,2,opt,name=ttl,def=1" json:"ttl,omitempty"`
+}
+
+// Default values for MulticastConf fields.
+const (
+	Default_MulticastConf_MinResponders = int32(1)
+	Default_MulticastConf_Ttl           = int32(1)
+)
+
+func (x *MulticastConf) Reset() {
+	*x = MulticastConf{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MulticastConf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MulticastConf) ProtoMessage() {}
+
+func (x *MulticastConf) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MulticastConf.ProtoReflect.Descriptor instead.
+func (*MulticastConf) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MulticastConf) GetMinResponders() int32 {
+	if x != nil && x.MinResponders != nil {
+		return *x.MinResponders
+	}
+	return Default_MulticastConf_MinResponders
+}
+
+func (x *MulticastConf) GetTtl() int32 {
+	if x != nil && x.Ttl != nil {
+		return *x.Ttl
+	}
+	return Default_MulticastConf_Ttl
+}
+
 type ProbeConf struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -54,6 +118,20 @@ type ProbeConf struct {
 	// list under maxTargets.  A large number of targets has impact on resource
 	// consumption.
 	MaxTargets *int32 `protobuf:"varint,9,opt,name=max_targets,json=maxTargets,def=500" json:"max_targets,omitempty"`
+	// Socket receive and send buffer sizes in bytes. If not set, system
+	// defaults are used. Valid range is 1024 to 67108864 (64MiB).
+	// read_buffer_size also caps the receive chunk size (65536 by default),
+	// so it should not be smaller than max_length.
+	ReadBufferSize  *int32 `protobuf:"varint,10,opt,name=read_buffer_size,json=readBufferSize" json:"read_buffer_size,omitempty"`
+	WriteBufferSize *int32 `protobuf:"varint,11,opt,name=write_buffer_size,json=writeBufferSize" json:"write_buffer_size,omitempty"`
+	// Multicast/broadcast mode. In this mode, targets are multicast group or
+	// broadcast addresses, and responses are collected from all responders
+	// until the probe timeout. Probe exports the number of distinct responders
+	// from the last probe run as "responders". Responders are expected to echo
+	// the probe packets back, as the UDP echo server does.
+	// Note: num_tx_ports, export_metrics_by_port and use_all_tx_ports_per_probe
+	// are not used in this mode.
+	Multicast *MulticastConf `protobuf:"bytes,12,opt,name=multicast" json:"multicast,omitempty"`
 }
 
 // Default values for ProbeConf fields.
@@ -69,7 +147,7 @@ const (
 func (x *ProbeConf) Reset() {
 	*x = ProbeConf{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_msgTypes[0]
+		mi := &file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -82,7 +160,7 @@ func (x *ProbeConf) String() string {
 func (*ProbeConf) ProtoMessage() {}
 
 func (x *ProbeConf) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_msgTypes[0]
+	mi := &file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -95,7 +173,7 @@ func (x *ProbeConf) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProbeConf.ProtoReflect.Descriptor instead.
 func (*ProbeConf) Descriptor() ([]byte, []int) {
-	return file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_rawDescGZIP(), []int{0}
+	return file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *ProbeConf) GetPort() int32 {
@@ -147,6 +225,27 @@ func (x *ProbeConf) GetMaxTargets() int32 {
 	return Default_ProbeConf_MaxTargets
 }
 
+func (x *ProbeConf) GetReadBufferSize() int32 {
+	if x != nil && x.ReadBufferSize != nil {
+		return *x.ReadBufferSize
+	}
+	return 0
+}
+
+func (x *ProbeConf) GetWriteBufferSize() int32 {
+	if x != nil && x.WriteBufferSize != nil {
+		return *x.WriteBufferSize
+	}
+	return 0
+}
+
+func (x *ProbeConf) GetMulticast() *MulticastConf {
+	if x != nil {
+		return x.Multicast
+	}
+	return nil
+}
+
 var File_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto protoreflect.FileDescriptor
 
 var file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_rawDesc = []byte{
@@ -155,7 +254,12 @@ var file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_rawDes
 	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x75, 0x64, 0x70, 0x2f,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x12, 0x16, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e,
-	0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x75, 0x64, 0x70, 0x22, 0xb8, 0x02, 0x0a, 0x09, 0x50,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x75, 0x64, 0x70, 0x22, 0x4e, 0x0a, 0x0d, 0x4d, 0x75,
+	0x6c, 0x74, 0x69, 0x63, 0x61, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x12, 0x28, 0x0a, 0x0e, 0x6d,
+	0x69, 0x6e, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x64, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x3a, 0x01, 0x31, 0x52, 0x0d, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x64, 0x65, 0x72, 0x73, 0x12, 0x13, 0x0a, 0x03, 0x74, 0x74, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x3a, 0x01, 0x31, 0x52, 0x03, 0x74, 0x74, 0x6c, 0x22, 0xd3, 0x03, 0x0a, 0x09, 0x50,
 	0x72, 0x6f, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x12, 0x19, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74,
 	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x05, 0x33, 0x31, 0x31, 0x32, 0x32, 0x52, 0x04, 0x70,
 	0x6f, 0x72, 0x74, 0x12, 0x24, 0x0a, 0x0c, 0x6e, 0x75, 0x6d, 0x5f, 0x74, 0x78, 0x5f, 0x70, 0x6f,
@@ -175,10 +279,20 @@ var file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_rawDes
 	0x54, 0x78, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x50, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x12,
 	0x24, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x09,
 	0x20, 0x01, 0x28, 0x05, 0x3a, 0x03, 0x35, 0x30, 0x30, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x54, 0x61,
-	0x72, 0x67, 0x65, 0x74, 0x73, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f,
-	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62,
-	0x65, 0x73, 0x2f, 0x75, 0x64, 0x70, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x62, 0x75,
+	0x66, 0x66, 0x65, 0x72, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0e, 0x72, 0x65, 0x61, 0x64, 0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x53, 0x69, 0x7a, 0x65, 0x12,
+	0x2a, 0x0a, 0x11, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x5f,
+	0x73, 0x69, 0x7a, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x77, 0x72, 0x69, 0x74,
+	0x65, 0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x43, 0x0a, 0x09, 0x6d,
+	0x75, 0x6c, 0x74, 0x69, 0x63, 0x61, 0x73, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25,
+	0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f,
+	0x62, 0x65, 0x73, 0x2e, 0x75, 0x64, 0x70, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x63, 0x61, 0x73,
+	0x74, 0x43, 0x6f, 0x6e, 0x66, 0x52, 0x09, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x63, 0x61, 0x73, 0x74,
+	0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2f, 0x75, 0x64,
+	0x70, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 }
 
 var (
@@ -193,16 +307,18 @@ func file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_rawDe
 	return file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_rawDescData
 }
 
-var file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_goTypes = []interface{}{
-	(*ProbeConf)(nil), // 0: cloudprober.probes.udp.ProbeConf
+	(*MulticastConf)(nil), // 0: cloudprober.probes.udp.MulticastConf
+	(*ProbeConf)(nil),     // 1: cloudprober.probes.udp.ProbeConf
 }
 var file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0, // 0: cloudprober.probes.udp.ProbeConf.multicast:type_name -> cloudprober.probes.udp.MulticastConf
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_init() }
@@ -212,6 +328,18 @@ func file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_init(
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MulticastConf); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ProbeConf); i {
 			case 0:
 				return &v.state
@@ -230,7 +358,7 @@ func file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_init(
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_github_com_cloudprober_cloudprober_probes_udp_proto_config_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -1,5 +1,14 @@
 package proto
 
+#MulticastConf: {
+	// Minimum number of responders for a probe run to be counted as success.
+	minResponders?: int32 @protobuf(1,int32,name=min_responders,"default=1")
+
+	// TTL (hop limit for IPv6) of the multicast packets. Default of 1 keeps
+	// the packets on the local network segment. Not used for broadcast.
+	ttl?: int32 @protobuf(2,int32,"default=1")
+}
+
 #ProbeConf: {
 	// Port to send UDP Ping to (UDP Echo).  If running with the UDP server that
 	// comes with cloudprober, it should be same as
@@ -36,4 +45,20 @@ package proto
 	// list under maxTargets.  A large number of targets has impact on resource
 	// consumption.
 	maxTargets?: int32 @protobuf(9,int32,name=max_targets,"default=500")
+
+	// Socket receive and send buffer sizes in bytes. If not set, system
+	// defaults are used. Valid range is 1024 to 67108864 (64MiB).
+	// read_buffer_size also caps the receive chunk size (65536 by default),
+	// so it should not be smaller than max_length.
+	readBufferSize?:  int32 @protobuf(10,int32,name=read_buffer_size)
+	writeBufferSize?: int32 @protobuf(11,int32,name=write_buffer_size)
+
+	// Multicast/broadcast mode. In this mode, targets are multicast group or
+	// broadcast addresses, and responses are collected from all responders
+	// until the probe timeout. Probe exports the number of distinct responders
+	// from the last probe run as "responders". Responders are expected to echo
+	// the probe packets back, as the UDP echo server does.
+	// Note: num_tx_ports, export_metrics_by_port and use_all_tx_ports_per_probe
+	// are not used in this mode.
+	multicast?: #MulticastConf @protobuf(12,MulticastConf)
 }
@@ -79,6 +79,9 @@ type Probe struct {
 	sPackets, rPackets       []packetID
 	highestSeq               map[flow]uint64
 	flushIntv                time.Duration
+
+	// Results by target, used only in the multicast mode.
+	mcastRes map[string]*multicastResult
 }
 
 // probeResult stores the probe results for a target. The way we work with
@@ -147,6 +150,20 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 		probeutils.PatternPayload(p.payload, []byte(payloadPattern))
 	}
 
+	if err := probeutils.ValidateSocketBufferSize("read_buffer_size", p.c.GetReadBufferSize()); err != nil {
+		return err
+	}
+	if err := probeutils.ValidateSocketBufferSize("write_buffer_size", p.c.GetWriteBufferSize()); err != nil {
+		return err
+	}
+	if rbs := p.c.GetReadBufferSize(); rbs != 0 && rbs < p.c.GetMaxLength() {
+		return fmt.Errorf("UDP probe: read_buffer_size (%d) should not be smaller than max_length (%d)", rbs, p.c.GetMaxLength())
+	}
+
+	if p.c.GetMulticast() != nil {
+		return p.initMulticast()
+	}
+
 	// Initialize intermediate buffers of sent and received packets
 	p.flushIntv = 2 * p.opts.Interval
 	if p.opts.Timeout > p.opts.Interval {
@@ -184,6 +201,10 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 			p.l.Warningf("Opening UDP socket failed: %v", err)
 			continue
 		}
+		if err := probeutils.SetSocketBuffers(udpConn, p.c.GetReadBufferSize(), p.c.GetWriteBufferSize()); err != nil {
+			udpConn.Close()
+			return fmt.Errorf("UDP probe: %v", err)
+		}
 		p.l.Infof("UDP socket id %d, addr %v", p.numConn, udpConn.LocalAddr())
 		p.connList[p.numConn] = udpConn
 		_, p.srcPortList[p.numConn], err = net.SplitHostPort(udpConn.LocalAddr().String())
@@ -326,10 +347,18 @@ func isClientTimeout(err error) bool {
 	return ok && e != nil && e.Timeout()
 }
 
+// readChunkSize returns the size of the buffer used to read packets.
+func (p *Probe) readChunkSize() int {
+	if rbs := int(p.c.GetReadBufferSize()); rbs != 0 && rbs < maxMsgSize {
+		return rbs
+	}
+	return maxMsgSize
+}
+
 // recvLoop receives all packets over a UDP socket and updates
 // flowStates accordingly.
 func (p *Probe) recvLoop(ctx context.Context, conn *net.UDPConn) {
-	b := make([]byte, maxMsgSize)
+	b := make([]byte, p.readChunkSize())
 	for {
 		select {
 		case <-ctx.Done():
@@ -452,6 +481,11 @@ func (p *Probe) updateTargets() {
 
 // Start starts and runs the probe indefinitely.
 func (p *Probe) Start(ctx context.Context, dataChan chan *metrics.EventMetrics) {
+	if p.c.GetMulticast() != nil {
+		p.startMulticast(ctx, dataChan)
+		return
+	}
+
 	p.updateTargets()
 
 	var recvLoopWG sync.WaitGroup
@@ -350,3 +350,66 @@ func TestLossAndDelayed(t *testing.T) {
 		})
 	}
 }
+
+func TestInitBufferSizes(t *testing.T) {
+	sysvars.Init(&logger.Logger{}, nil)
+
+	tests := []struct {
+		name          string
+		conf          *configpb.ProbeConf
+		wantErr       bool
+		wantChunkSize int
+	}{
+		{
+			name:          "default",
+			conf:          &configpb.ProbeConf{},
+			wantChunkSize: maxMsgSize,
+		},
+		{
+			name:          "small_read_buffer",
+			conf:          &configpb.ProbeConf{ReadBufferSize: proto.Int32(4096), WriteBufferSize: proto.Int32(8192)},
+			wantChunkSize: 4096,
+		},
+		{
+			name:          "large_read_buffer",
+			conf:          &configpb.ProbeConf{ReadBufferSize: proto.Int32(1 << 20)},
+			wantChunkSize: maxMsgSize,
+		},
+		{
+			name:    "read_buffer_too_small",
+			conf:    &configpb.ProbeConf{ReadBufferSize: proto.Int32(100)},
+			wantErr: true,
+		},
+		{
+			name:    "read_buffer_smaller_than_max_length",
+			conf:    &configpb.ProbeConf{ReadBufferSize: proto.Int32(1024), MaxLength: proto.Int32(1300)},
+			wantErr: true,
+		},
+		{
+			name:    "write_buffer_too_large",
+			conf:    &configpb.ProbeConf{WriteBufferSize: proto.Int32(128 << 20)},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.conf.NumTxPorts = proto.Int32(1)
+			p := &Probe{}
+			err := p.Init("udp", &options.Options{
+				Targets:             targets.StaticTargets("localhost"),
+				Interval:            time.Second,
+				Timeout:             time.Second,
+				ProbeConf:           test.conf,
+				StatsExportInterval: 10 * time.Second,
+			})
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			defer p.connList[0].Close()
+			assert.Equal(t, test.wantChunkSize, p.readChunkSize())
+		})
+	}
+}
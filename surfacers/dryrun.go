@@ -0,0 +1,106 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package surfacers
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/options"
+	"github.com/cloudprober/cloudprober/surfacers/internal/prometheus"
+	surfacerpb "github.com/cloudprober/cloudprober/surfacers/proto"
+)
+
+// redactedValue replaces the values of the secret-like labels in the dry run
+// output.
+const redactedValue = "<redacted>"
+
+var secretLabelRe = regexp.MustCompile(`(?i)(token|secret|passw(or)?d|api[_-]?key|auth|credential)`)
+
+// secretParamRe matches the secret-like key-value pairs inside the values,
+// e.g. "token=xyz" in a URL.
+var secretParamRe = regexp.MustCompile(`(?i)((token|secret|passw(or)?d|api[_-]?key|auth|credential)[\w-]*=)[^&\s",}]+`)
+
+// dryRunSurfacer logs the EventMetrics instead of exporting them. It's used
+// in place of the configured surfacer if dry_run is set.
+type dryRunSurfacer struct {
+	l *logger.Logger
+
+	// payload returns the EventMetrics as the surfacer would export them.
+	payload func(em *metrics.EventMetrics) string
+}
+
+// newDryRunSurfacer returns a dry run surfacer for the given surfacer config.
+// Surfacers that have a way to serialize the EventMetrics without being
+// started log their payload. Others log the EventMetrics as they are.
+func newDryRunSurfacer(s *surfacerpb.SurfacerDef, sType surfacerpb.Type, opts *options.Options, l *logger.Logger) (*dryRunSurfacer, error) {
+	drs := &dryRunSurfacer{
+		l: l,
+		payload: func(em *metrics.EventMetrics) string {
+			return em.String()
+		},
+	}
+	if sType == surfacerpb.Type_PROMETHEUS {
+		payload, err := prometheus.NewDryRunPayload(s.GetPrometheusSurfacer(), opts, l)
+		if err != nil {
+			return nil, err
+		}
+		drs.payload = payload
+	}
+	return drs, nil
+}
+
+func (s *dryRunSurfacer) Write(_ context.Context, em *metrics.EventMetrics) {
+	payload := s.payload(redactLabels(em))
+	s.l.Infof("dry_run, not exporting: %s", redactValues(strings.TrimSpace(payload)))
+}
+
+// redactValues redacts the secret-like key-value pairs in the payload, e.g.
+// the query parameters of the URLs in the label values.
+func redactValues(payload string) string {
+	return secretParamRe.ReplaceAllString(payload, "${1}"+redactedValue)
+}
+
+// redactLabels returns the EventMetrics with secret-like label values
+// redacted. EventMetrics is returned as is if there is nothing to redact.
+func redactLabels(em *metrics.EventMetrics) *metrics.EventMetrics {
+	redact := false
+	for _, k := range em.LabelsKeys() {
+		if secretLabelRe.MatchString(k) {
+			redact = true
+			break
+		}
+	}
+	if !redact {
+		return em
+	}
+
+	out := metrics.NewEventMetrics(em.Timestamp)
+	out.Kind = em.Kind
+	for _, k := range em.MetricsKeys() {
+		out.AddMetric(k, em.Metric(k))
+	}
+	for _, k := range em.LabelsKeys() {
+		v := em.Label(k)
+		if secretLabelRe.MatchString(k) {
+			v = redactedValue
+		}
+		out.AddLabel(k, v)
+	}
+	return out
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
 	configpb "github.com/cloudprober/cloudprober/surfacers/internal/bigquery/proto"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/flush"
 	"github.com/cloudprober/cloudprober/surfacers/internal/common/options"
 )
 
@@ -47,6 +48,8 @@ type Surfacer struct {
 
 	// Channel for incoming data.
 	writeChan chan *metrics.EventMetrics
+	loopDone  chan struct{}
+	inserter  iInserter
 
 	// Cloud logger
 	l *logger.Logger
@@ -293,8 +296,39 @@ func (s *Surfacer) writeToBQ(ctx context.Context, inserter iInserter) {
 	}
 }
 
+// Flush inserts the EventMetrics remaining in the write channel, once the
+// write loop has stopped, i.e. after the context passed to New is canceled.
+func (s *Surfacer) Flush(ctx context.Context) error {
+	if err := flush.WaitForStop(ctx, s.loopDone); err != nil {
+		return err
+	}
+
+	var rows []*bqrow
+	dropped := flush.Drain(ctx, s.writeChan, func(em *metrics.EventMetrics) {
+		bqMetrics, err := s.parseBQCols(em)
+		if err != nil {
+			s.l.Errorf("%v", err)
+			return
+		}
+		rows = append(rows, bqMetrics...)
+	})
+	if err := flush.DroppedError(ctx, dropped, "EventMetrics"); err != nil {
+		return err
+	}
+
+	batchSize := int(s.c.GetMetricsBatchSize())
+	for i := 0; i < len(rows); i += batchSize {
+		if err := s.inserter.Put(ctx, rows[i:min(len(rows), i+batchSize)]); err != nil {
+			s.l.Errorf("failed uploading rows to Bigquery: %v", err)
+			return flush.DroppedError(ctx, len(rows)-i, "rows")
+		}
+	}
+	return nil
+}
+
 func (s *Surfacer) init(ctx context.Context) error {
 	s.writeChan = make(chan *metrics.EventMetrics, s.c.GetMetricsBufferSize())
+	s.loopDone = make(chan struct{})
 
 	client, err := bigquery.NewClient(ctx, s.c.GetProjectName())
 	if err != nil {
@@ -307,10 +341,12 @@ func (s *Surfacer) init(ctx context.Context) error {
 	if inserter == nil {
 		return fmt.Errorf("error bigquery inserter cannot be created")
 	}
+	s.inserter = inserter
 
 	// Start a goroutine to run forever, polling on the writeChan. Allows
 	// for the surfacer to write asynchronously to the serial port.
 	go func() {
+		defer close(s.loopDone)
 		s.writeToBQ(ctx, inserter)
 	}()
 
@@ -359,3 +359,37 @@ func TestWriteToBQ(t *testing.T) {
 		t.Fatalf("Error in writeToBQ!")
 	}
 }
+
+func TestFlush(t *testing.T) {
+	inserter := &fakeInserter{}
+	s := &Surfacer{
+		c:         newSurfacerConfig(map[string]string{"id": "string"}),
+		l:         &logger.Logger{},
+		writeChan: make(chan *metrics.EventMetrics, 2500),
+		loopDone:  make(chan struct{}),
+		inserter:  inserter,
+	}
+
+	em := metrics.NewEventMetrics(time.Now()).AddLabel("id", "test").AddMetric("TestFlush", metrics.NewInt(1))
+	for i := 0; i < 2500; i++ {
+		s.Write(context.Background(), em)
+	}
+
+	// Write loop is still running.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Flush(ctx); err == nil {
+		t.Errorf("Expected error while the write loop is running, got nil")
+	}
+
+	close(s.loopDone)
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if inserter.batchCount != 3 {
+		t.Errorf("Number of put calls: %d, want: 3", inserter.batchCount)
+	}
+	if len(s.writeChan) != 0 {
+		t.Errorf("Remaining EventMetrics in write channel: %d, want: 0", len(s.writeChan))
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/cloudprober/cloudprober/metrics"
 
 	configpb "github.com/cloudprober/cloudprober/surfacers/internal/cloudwatch/proto"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/flush"
 	"github.com/cloudprober/cloudprober/surfacers/internal/common/options"
 )
 
@@ -42,6 +43,7 @@ type CWSurfacer struct {
 	c         *configpb.SurfacerConf
 	opts      *options.Options
 	writeChan chan *metrics.EventMetrics
+	loopDone  chan struct{}
 	session   *cloudwatch.Client
 	l         *logger.Logger
 
@@ -65,6 +67,7 @@ func New(ctx context.Context, conf *configpb.SurfacerConf, opts *options.Options
 		c:                conf,
 		opts:             opts,
 		writeChan:        make(chan *metrics.EventMetrics, opts.Config.GetMetricsBufferSize()), // incoming internal metrics buffer
+		loopDone:         make(chan struct{}),
 		session:          cloudwatch.NewFromConfig(cfg),
 		l:                l,
 		metricDatumCache: make([]types.MetricDatum, 0, int(conf.GetMetricsBatchSize())), // batching buffer between cloudprober and cloudwatch
@@ -87,6 +90,8 @@ func (cw *CWSurfacer) Write(ctx context.Context, em *metrics.EventMetrics) {
 }
 
 func (cw *CWSurfacer) processIncomingMetrics(ctx context.Context) {
+	defer close(cw.loopDone)
+
 	publishTimer := time.NewTicker(time.Duration(cw.c.GetBatchTimerSec()) * time.Second)
 	defer publishTimer.Stop()
 
@@ -105,6 +110,28 @@ func (cw *CWSurfacer) processIncomingMetrics(ctx context.Context) {
 	}
 }
 
+// Flush publishes the EventMetrics remaining in the write channel, along with
+// the buffered metric data, once the write loop has stopped, i.e. after the
+// context passed to New is canceled.
+func (cw *CWSurfacer) Flush(ctx context.Context) error {
+	if err := flush.WaitForStop(ctx, cw.loopDone); err != nil {
+		return err
+	}
+
+	publishTimer := time.NewTicker(time.Duration(cw.c.GetBatchTimerSec()) * time.Second)
+	defer publishTimer.Stop()
+	dropped := flush.Drain(ctx, cw.writeChan, func(em *metrics.EventMetrics) {
+		cw.recordEventMetrics(ctx, publishTimer, em)
+	})
+	if err := flush.DroppedError(ctx, dropped, "EventMetrics"); err != nil {
+		return err
+	}
+	if len(cw.metricDatumCache) != 0 {
+		cw.publishMetrics(ctx)
+	}
+	return nil
+}
+
 func recordMapValue[T int64 | float64](ctx context.Context, cw *CWSurfacer, key string, m *metrics.Map[T], d []types.Dimension, em *metrics.EventMetrics, publishTimer *time.Ticker) {
 	for _, mapKey := range m.Keys() {
 		newDimensions := append(d, types.Dimension{
@@ -169,7 +196,7 @@ func (cw *CWSurfacer) publishMetrics(ctx context.Context) {
 		MetricData: cw.metricDatumCache,
 	})
 	if err != nil {
-		cw.l.Errorf("Error publishing metrics to cloudwatch: %v", err)
+		cw.l.Errorf("Error publishing %d metrics to cloudwatch: %v", len(cw.metricDatumCache), err)
 	}
 
 	cw.metricDatumCache = cw.metricDatumCache[:0] // reset the buffer
@@ -0,0 +1,64 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package flush implements utilities to flush the surfacers' buffered
+// EventMetrics during shutdown.
+package flush
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudprober/cloudprober/metrics"
+)
+
+// WaitForStop waits for the done channel to be closed, i.e. for the
+// surfacer's write loop to stop. A nil done channel means that there is no
+// write loop running.
+func WaitForStop(ctx context.Context, done <-chan struct{}) error {
+	if done == nil {
+		return nil
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("write loop didn't stop: %v", ctx.Err())
+	}
+}
+
+// Drain calls process for each EventMetrics remaining in the channel, until
+// the channel is empty or ctx is done. It returns the number of EventMetrics
+// left unprocessed, i.e. dropped.
+func Drain(ctx context.Context, ch <-chan *metrics.EventMetrics, process func(*metrics.EventMetrics)) int {
+	for n := len(ch); n > 0; n-- {
+		if ctx.Err() != nil {
+			return n
+		}
+		process(<-ch)
+	}
+	return 0
+}
+
+// DroppedError returns the error to report if metrics were dropped while
+// flushing, or nil if nothing was dropped.
+func DroppedError(ctx context.Context, dropped int, what string) error {
+	if dropped == 0 {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("couldn't flush in time (%v), dropped %d %s", ctx.Err(), dropped, what)
+	}
+	return fmt.Errorf("dropped %d %s", dropped, what)
+}
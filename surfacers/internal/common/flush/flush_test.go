@@ -0,0 +1,60 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flush
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForStop(t *testing.T) {
+	assert.NoError(t, WaitForStop(context.Background(), nil), "nil done channel")
+
+	done := make(chan struct{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, WaitForStop(ctx, done), "loop not stopped")
+
+	close(done)
+	assert.NoError(t, WaitForStop(context.Background(), done))
+}
+
+func TestDrain(t *testing.T) {
+	ch := make(chan *metrics.EventMetrics, 10)
+	for i := 0; i < 5; i++ {
+		ch <- metrics.NewEventMetrics(time.Now())
+	}
+
+	processed := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	dropped := Drain(ctx, ch, func(*metrics.EventMetrics) {
+		processed++
+		if processed == 2 {
+			cancel()
+		}
+	})
+	assert.Equal(t, 2, processed, "processed")
+	assert.Equal(t, 3, dropped, "dropped")
+	assert.ErrorContains(t, DroppedError(ctx, dropped, "EventMetrics"), "dropped 3 EventMetrics")
+
+	dropped = Drain(context.Background(), ch, func(*metrics.EventMetrics) { processed++ })
+	assert.Equal(t, 0, dropped, "dropped")
+	assert.Equal(t, 5, processed, "processed")
+	assert.NoError(t, DroppedError(ctx, dropped, "EventMetrics"))
+}
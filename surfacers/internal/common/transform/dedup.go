@@ -0,0 +1,76 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+)
+
+type dedupState struct {
+	values   string
+	lastSent time.Time
+}
+
+// Deduplicator suppresses the EventMetrics that are identical, i.e. have the
+// same labels and metric values, to the last EventMetrics let through for the
+// same series. To guarantee a periodic refresh, an identical EventMetrics is
+// let through anyway if it's been maxSuppression since the last one. Time is
+// based on the EventMetrics timestamps.
+//
+// Deduplicator is not safe for concurrent use.
+type Deduplicator struct {
+	maxSuppression time.Duration
+	series         map[string]*dedupState
+}
+
+// NewDeduplicator returns a new Deduplicator.
+func NewDeduplicator(maxSuppression time.Duration) *Deduplicator {
+	return &Deduplicator{
+		maxSuppression: maxSuppression,
+		series:         make(map[string]*dedupState),
+	}
+}
+
+// valuesString returns the metric values of the EventMetrics as a string.
+func valuesString(em *metrics.EventMetrics) string {
+	var b strings.Builder
+	for _, k := range em.MetricsKeys() {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(em.Metric(k).String())
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// Allow reports whether the EventMetrics should be let through.
+func (d *Deduplicator) Allow(em *metrics.EventMetrics) bool {
+	key, values := em.Key(), valuesString(em)
+
+	s := d.series[key]
+	if s == nil {
+		d.series[key] = &dedupState{values: values, lastSent: em.Timestamp}
+		return true
+	}
+
+	if s.values == values && em.Timestamp.Sub(s.lastSent) < d.maxSuppression {
+		return false
+	}
+	s.values, s.lastSent = values, em.Timestamp
+	return true
+}
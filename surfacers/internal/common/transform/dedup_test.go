@@ -0,0 +1,58 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicator(t *testing.T) {
+	d := NewDeduplicator(time.Minute)
+	start := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	stateEM := func(sec int, dst, state string) *metrics.EventMetrics {
+		return metrics.NewEventMetrics(start.Add(time.Duration(sec)*time.Second)).
+			AddMetric("up", metrics.NewInt(1)).
+			AddMetric("state", metrics.NewString(state)).
+			AddLabel("probe", "p1").
+			AddLabel("dst", dst)
+	}
+
+	tests := []struct {
+		desc string
+		em   *metrics.EventMetrics
+		want bool
+	}{
+		{desc: "first", em: stateEM(0, "t1", "ok"), want: true},
+		{desc: "first_for_another_series", em: stateEM(0, "t2", "ok"), want: true},
+		{desc: "identical", em: stateEM(10, "t1", "ok"), want: false},
+		{desc: "identical_again", em: stateEM(20, "t1", "ok"), want: false},
+		{desc: "value_changed", em: stateEM(30, "t1", "down"), want: true},
+		{desc: "identical_to_changed", em: stateEM(40, "t1", "down"), want: false},
+		{desc: "other_series_identical", em: stateEM(50, "t2", "ok"), want: false},
+		{desc: "max_suppression_from_last_sent", em: stateEM(89, "t1", "down"), want: false},
+		{desc: "max_suppression", em: stateEM(90, "t1", "down"), want: true},
+		{desc: "identical_after_refresh", em: stateEM(100, "t1", "down"), want: false},
+		{desc: "max_suppression_other_series", em: stateEM(60, "t2", "ok"), want: true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, d.Allow(test.em), test.desc)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"github.com/cloudprober/cloudprober/metrics"
+)
+
+// Names of the metrics added by the FailingTargetsFilter.
+const (
+	RecoveredMetricName        = "recovered"
+	AggregateTotalMetricName   = "aggregate_total"
+	AggregateSuccessMetricName = "aggregate_success"
+)
+
+type counts struct {
+	total, success int64
+}
+
+type targetState struct {
+	dst     string
+	last    counts
+	failing bool
+}
+
+type probeState struct {
+	ptype   string
+	targets map[string]counts
+	seen    map[string]bool
+
+	// targetStates is keyed by the EventMetrics key, see Process.
+	targetStates map[string]*targetState
+}
+
+// FailingTargetsFilter filters per-target EventMetrics, letting through only
+// the ones for failing targets. A target is considered failing if it had
+// failures since its last EventMetrics. When a failing target recovers, its
+// next EventMetrics is let through with an additional "recovered" metric.
+//
+// To retain the overall success rate, it also generates aggregate total and
+// success counts for each probe, once per round of targets. Targets that
+// didn't report in the last round are forgotten, as they were probably
+// removed.
+//
+// EventMetrics without "dst" label, or without numeric "total" and "success"
+// metrics, are not per-target metrics and are always let through.
+//
+// FailingTargetsFilter is not safe for concurrent use.
+type FailingTargetsFilter struct {
+	probes map[string]*probeState
+}
+
+// NewFailingTargetsFilter returns a new FailingTargetsFilter.
+func NewFailingTargetsFilter() *FailingTargetsFilter {
+	return &FailingTargetsFilter{
+		probes: make(map[string]*probeState),
+	}
+}
+
+func numValue(em *metrics.EventMetrics, name string) (int64, bool) {
+	nv, ok := em.Metric(name).(metrics.NumValue)
+	if !ok {
+		return 0, false
+	}
+	return nv.Int64(), true
+}
+
+// aggregateEM returns the aggregate EventMetrics for the probe.
+func (ps *probeState) aggregateEM(probe string, em *metrics.EventMetrics) *metrics.EventMetrics {
+	var agg counts
+	for _, c := range ps.targets {
+		agg.total += c.total
+		agg.success += c.success
+	}
+	aggEM := metrics.NewEventMetrics(em.Timestamp).
+		AddMetric(AggregateTotalMetricName, metrics.NewInt(agg.total)).
+		AddMetric(AggregateSuccessMetricName, metrics.NewInt(agg.success)).
+		AddLabel("ptype", ps.ptype).
+		AddLabel("probe", probe)
+	aggEM.Kind = em.Kind
+	return aggEM
+}
+
+// updateAggregate updates the probe aggregate counts with the em. It returns
+// the probe state, and the aggregate EventMetrics if a new round of targets
+// just started.
+func (f *FailingTargetsFilter) updateAggregate(em *metrics.EventMetrics, c counts) (*probeState, *metrics.EventMetrics) {
+	probe, dst := em.Label("probe"), em.Label("dst")
+
+	ps := f.probes[probe]
+	if ps == nil {
+		ps = &probeState{
+			ptype:        em.Label("ptype"),
+			targets:      make(map[string]counts),
+			seen:         make(map[string]bool),
+			targetStates: make(map[string]*targetState),
+		}
+		f.probes[probe] = ps
+	}
+
+	var aggEM *metrics.EventMetrics
+	if ps.seen[dst] {
+		ps.pruneTargets()
+		aggEM = ps.aggregateEM(probe, em)
+		ps.seen = make(map[string]bool)
+	}
+	ps.targets[dst] = c
+	ps.seen[dst] = true
+
+	return ps, aggEM
+}
+
+// pruneTargets removes the state of the probe's targets that were not seen in
+// the last round of targets.
+func (ps *probeState) pruneTargets() {
+	for dst := range ps.targets {
+		if !ps.seen[dst] {
+			delete(ps.targets, dst)
+		}
+	}
+	for key, ts := range ps.targetStates {
+		if !ps.seen[ts.dst] {
+			delete(ps.targetStates, key)
+		}
+	}
+}
+
+// Process processes the given EventMetrics and returns the EventMetrics that
+// should be written out, if any.
+func (f *FailingTargetsFilter) Process(em *metrics.EventMetrics) []*metrics.EventMetrics {
+	if em.Label("dst") == "" {
+		return []*metrics.EventMetrics{em}
+	}
+	total, totalOK := numValue(em, "total")
+	success, successOK := numValue(em, "success")
+	if !totalOK || !successOK {
+		return []*metrics.EventMetrics{em}
+	}
+	cur := counts{total: total, success: success}
+
+	var out []*metrics.EventMetrics
+	ps, aggEM := f.updateAggregate(em, cur)
+	if aggEM != nil {
+		out = append(out, aggEM)
+	}
+
+	key := em.Key()
+	ts := ps.targetStates[key]
+	if ts == nil {
+		ts = &targetState{dst: em.Label("dst")}
+		ps.targetStates[key] = ts
+	}
+
+	last := ts.last
+	// Counters were reset, e.g. probe was re-created.
+	if cur.total < last.total || cur.success < last.success {
+		last = counts{}
+	}
+	ts.last = cur
+
+	failingNow := (cur.total - last.total) > (cur.success - last.success)
+	switch {
+	case failingNow:
+		ts.failing = true
+		out = append(out, em)
+	case ts.failing:
+		ts.failing = false
+		out = append(out, em.Clone().AddMetric(RecoveredMetricName, metrics.NewInt(1)))
+	}
+
+	return out
+}
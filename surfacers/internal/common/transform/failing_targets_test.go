@@ -0,0 +1,128 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func targetEM(dst string, total, success int64) *metrics.EventMetrics {
+	return metrics.NewEventMetrics(time.Now()).
+		AddMetric("total", metrics.NewInt(total)).
+		AddMetric("success", metrics.NewInt(success)).
+		AddLabel("ptype", "http").
+		AddLabel("probe", "p1").
+		AddLabel("dst", dst)
+}
+
+func TestFailingTargetsFilter(t *testing.T) {
+	f := NewFailingTargetsFilter()
+
+	// Each round has EventMetrics for t1 and t2. Aggregate is emitted at the
+	// start of every round after the first one.
+	rounds := []struct {
+		t1, t2         [2]int64
+		wantT1, wantT2 bool
+		wantRecovered  string
+		wantAgg        [2]int64
+	}{
+		{t1: [2]int64{10, 10}, t2: [2]int64{10, 8}, wantT2: true},
+		{t1: [2]int64{20, 20}, t2: [2]int64{20, 17}, wantT2: true, wantAgg: [2]int64{20, 18}},
+		// t2 recovers, t1 starts failing.
+		{t1: [2]int64{30, 29}, t2: [2]int64{30, 27}, wantT1: true, wantT2: true, wantRecovered: "t2", wantAgg: [2]int64{40, 37}},
+		// t1 recovers, nothing for t2.
+		{t1: [2]int64{40, 39}, t2: [2]int64{40, 37}, wantT1: true, wantRecovered: "t1", wantAgg: [2]int64{60, 56}},
+		// Nothing for either.
+		{t1: [2]int64{50, 49}, t2: [2]int64{50, 47}, wantAgg: [2]int64{80, 76}},
+	}
+
+	for i, r := range rounds {
+		var got []*metrics.EventMetrics
+		got = append(got, f.Process(targetEM("t1", r.t1[0], r.t1[1]))...)
+		got = append(got, f.Process(targetEM("t2", r.t2[0], r.t2[1]))...)
+
+		var gotAgg []int64
+		var gotTargets []string
+		var gotRecovered string
+		for _, em := range got {
+			if em.Label("dst") == "" {
+				gotAgg = []int64{em.Metric(AggregateTotalMetricName).(*metrics.Int).Int64(), em.Metric(AggregateSuccessMetricName).(*metrics.Int).Int64()}
+				assert.Equal(t, "p1", em.Label("probe"))
+				assert.Equal(t, "http", em.Label("ptype"))
+				continue
+			}
+			gotTargets = append(gotTargets, em.Label("dst"))
+			if em.Metric(RecoveredMetricName) != nil {
+				gotRecovered = em.Label("dst")
+			}
+		}
+
+		var wantTargets []string
+		if r.wantT1 {
+			wantTargets = append(wantTargets, "t1")
+		}
+		if r.wantT2 {
+			wantTargets = append(wantTargets, "t2")
+		}
+		assert.Equal(t, wantTargets, gotTargets, "round %d: targets", i)
+		assert.Equal(t, r.wantRecovered, gotRecovered, "round %d: recovered", i)
+		if r.wantAgg != [2]int64{} {
+			assert.Equal(t, []int64{r.wantAgg[0], r.wantAgg[1]}, gotAgg, "round %d: aggregate", i)
+		} else {
+			assert.Nil(t, gotAgg, "round %d: aggregate", i)
+		}
+	}
+}
+
+func TestFailingTargetsFilterPassThrough(t *testing.T) {
+	f := NewFailingTargetsFilter()
+
+	for _, em := range []*metrics.EventMetrics{
+		metrics.NewEventMetrics(time.Now()).AddMetric("num_goroutines", metrics.NewInt(10)).AddLabel("probe", "sysvars"),
+		metrics.NewEventMetrics(time.Now()).AddMetric("rtt", metrics.NewFloat(1.5)).AddLabel("dst", "t1"),
+	} {
+		assert.Equal(t, []*metrics.EventMetrics{em}, f.Process(em))
+	}
+}
+
+func TestFailingTargetsFilterCounterReset(t *testing.T) {
+	f := NewFailingTargetsFilter()
+	assert.Len(t, f.Process(targetEM("t1", 100, 100)), 0)
+	// Counters reset, e.g. probe re-created: new counts are used as is.
+	assert.Len(t, f.Process(targetEM("t1", 5, 5)), 1, "aggregate only")
+	got := f.Process(targetEM("t1", 10, 9))
+	assert.Equal(t, "t1", got[len(got)-1].Label("dst"))
+}
+
+func TestFailingTargetsFilterRemovedTarget(t *testing.T) {
+	f := NewFailingTargetsFilter()
+	f.Process(targetEM("t1", 10, 10))
+	f.Process(targetEM("t2", 10, 5))
+	assert.Len(t, f.probes["p1"].targetStates, 2)
+
+	// t2 is removed. Aggregate for the t1+t2 round still includes it.
+	got := f.Process(targetEM("t1", 20, 20))
+	assert.Equal(t, int64(20), got[0].Metric(AggregateTotalMetricName).(*metrics.Int).Int64())
+
+	// t2 didn't report in the last round, it's forgotten.
+	got = f.Process(targetEM("t1", 30, 30))
+	assert.Equal(t, int64(20), got[0].Metric(AggregateTotalMetricName).(*metrics.Int).Int64())
+	assert.Len(t, f.probes["p1"].targetStates, 1)
+	assert.Len(t, f.probes["p1"].targets, 1)
+}
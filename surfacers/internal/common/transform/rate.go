@@ -0,0 +1,150 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	surfacerpb "github.com/cloudprober/cloudprober/surfacers/proto"
+)
+
+type rateConfig struct {
+	metricName, rateMetricName string
+	window                     time.Duration
+}
+
+type sample struct {
+	ts  time.Time
+	val float64
+}
+
+// Rate is a rate metric computed by the RateCalculator.
+type Rate struct {
+	Name  string
+	Value float64
+}
+
+// RateCalculator computes per-second rates of cumulative metrics, over a
+// sliding window of the metric samples it sees.
+//
+// RateCalculator is not safe for concurrent use.
+type RateCalculator struct {
+	configs   []rateConfig
+	samples   map[string][]sample
+	maxWindow time.Duration
+	lastPrune time.Time
+}
+
+// NewRateCalculator returns a new RateCalculator for the given rate metrics
+// config.
+func NewRateCalculator(rms []*surfacerpb.RateMetric) (*RateCalculator, error) {
+	rc := &RateCalculator{
+		samples: make(map[string][]sample),
+	}
+	for _, rm := range rms {
+		if rm.GetMetricName() == "" {
+			return nil, fmt.Errorf("rate_metric: metric_name is required")
+		}
+		if rm.GetWindowSec() <= 0 {
+			return nil, fmt.Errorf("rate_metric (%s): window_sec (%d) should be positive", rm.GetMetricName(), rm.GetWindowSec())
+		}
+		conf := rateConfig{
+			metricName:     rm.GetMetricName(),
+			rateMetricName: rm.GetRateMetricName(),
+			window:         time.Duration(rm.GetWindowSec()) * time.Second,
+		}
+		if conf.rateMetricName == "" {
+			conf.rateMetricName = conf.metricName + "_per_second"
+		}
+		rc.configs = append(rc.configs, conf)
+		if conf.window > rc.maxWindow {
+			rc.maxWindow = conf.window
+		}
+	}
+	return rc, nil
+}
+
+// addSample adds a sample for the given key, and returns the rate over the
+// samples in the window. It returns false if there are not enough samples
+// yet to compute the rate.
+func (rc *RateCalculator) addSample(key string, s sample, window time.Duration) (float64, bool) {
+	samples := rc.samples[key]
+	// Counter was reset, e.g. probe was re-created.
+	if len(samples) > 0 && s.val < samples[len(samples)-1].val {
+		samples = nil
+	}
+	samples = append(samples, s)
+
+	// Keep only the latest sample at or before the window start, to compute
+	// the rate over the whole window.
+	windowStart := s.ts.Add(-window)
+	i := 0
+	for i+1 < len(samples) && !samples[i+1].ts.After(windowStart) {
+		i++
+	}
+	samples = samples[i:]
+	rc.samples[key] = samples
+
+	elapsed := s.ts.Sub(samples[0].ts).Seconds()
+	if len(samples) < 2 || elapsed <= 0 {
+		return 0, false
+	}
+	return (s.val - samples[0].val) / elapsed, true
+}
+
+// prune removes the samples for the keys that have not been updated for
+// twice the longest window, e.g. the targets that went away. It runs at
+// most once per longest window.
+func (rc *RateCalculator) prune(now time.Time) {
+	if now.Sub(rc.lastPrune) < rc.maxWindow {
+		return
+	}
+	rc.lastPrune = now
+	staleBefore := now.Add(-2 * rc.maxWindow)
+	for key, samples := range rc.samples {
+		if len(samples) == 0 || samples[len(samples)-1].ts.Before(staleBefore) {
+			delete(rc.samples, key)
+		}
+	}
+}
+
+// Rates updates the samples with the given EventMetrics, and returns the
+// rate metrics for it. Only cumulative EventMetrics are considered.
+func (rc *RateCalculator) Rates(em *metrics.EventMetrics) []Rate {
+	if em.Kind != metrics.CUMULATIVE {
+		return nil
+	}
+
+	rc.prune(em.Timestamp)
+
+	var rates []Rate
+	var emKey string
+	for _, conf := range rc.configs {
+		nv, ok := em.Metric(conf.metricName).(metrics.NumValue)
+		if !ok {
+			continue
+		}
+		if emKey == "" {
+			emKey = em.Key()
+		}
+		key := emKey + "," + conf.rateMetricName
+		if rate, ok := rc.addSample(key, sample{em.Timestamp, nv.Float64()}, conf.window); ok {
+			rates = append(rates, Rate{Name: conf.rateMetricName, Value: rate})
+		}
+	}
+	return rates
+}
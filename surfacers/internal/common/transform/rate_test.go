@@ -0,0 +1,107 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	surfacerpb "github.com/cloudprober/cloudprober/surfacers/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewRateCalculator(t *testing.T) {
+	_, err := NewRateCalculator([]*surfacerpb.RateMetric{{}})
+	assert.Error(t, err, "no metric_name")
+	_, err = NewRateCalculator([]*surfacerpb.RateMetric{{MetricName: proto.String("total"), WindowSec: proto.Int32(0)}})
+	assert.Error(t, err, "zero window_sec")
+
+	rc, err := NewRateCalculator([]*surfacerpb.RateMetric{
+		{MetricName: proto.String("total")},
+		{MetricName: proto.String("success"), RateMetricName: proto.String("successes_per_second")},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []rateConfig{
+		{metricName: "total", rateMetricName: "total_per_second", window: time.Minute},
+		{metricName: "success", rateMetricName: "successes_per_second", window: time.Minute},
+	}, rc.configs)
+}
+
+func TestRates(t *testing.T) {
+	rc, err := NewRateCalculator([]*surfacerpb.RateMetric{
+		{MetricName: proto.String("total"), WindowSec: proto.Int32(20)},
+		{MetricName: proto.String("missing")},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	start := time.Now()
+	tests := []struct {
+		offsetSec int
+		total     int64
+		wantRate  []Rate
+	}{
+		{offsetSec: 0, total: 0},
+		{offsetSec: 10, total: 10, wantRate: []Rate{{"total_per_second", 1}}},
+		{offsetSec: 20, total: 30, wantRate: []Rate{{"total_per_second", 1.5}}},
+		// Window moves: rate over the last 20s, i.e. (50-10)/20.
+		{offsetSec: 30, total: 50, wantRate: []Rate{{"total_per_second", 2}}},
+		// Counter reset.
+		{offsetSec: 40, total: 5},
+		{offsetSec: 50, total: 15, wantRate: []Rate{{"total_per_second", 1}}},
+	}
+
+	for _, test := range tests {
+		em := metrics.NewEventMetrics(start.Add(time.Duration(test.offsetSec)*time.Second)).
+			AddMetric("total", metrics.NewInt(test.total)).
+			AddLabel("probe", "p1")
+		assert.Equal(t, test.wantRate, rc.Rates(em), "offset: %ds", test.offsetSec)
+	}
+
+	// Gauge metrics are ignored.
+	em := metrics.NewEventMetrics(start.Add(time.Minute)).AddMetric("total", metrics.NewInt(100))
+	em.Kind = metrics.GAUGE
+	assert.Nil(t, rc.Rates(em))
+}
+
+func TestRatesPrune(t *testing.T) {
+	rc, err := NewRateCalculator([]*surfacerpb.RateMetric{
+		{MetricName: proto.String("total"), WindowSec: proto.Int32(20)},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	start := time.Now()
+	newEM := func(offsetSec int, target string) *metrics.EventMetrics {
+		return metrics.NewEventMetrics(start.Add(time.Duration(offsetSec)*time.Second)).
+			AddMetric("total", metrics.NewInt(int64(offsetSec))).
+			AddLabel("dst", target)
+	}
+
+	rc.Rates(newEM(0, "t1"))
+	rc.Rates(newEM(0, "t2"))
+	assert.Len(t, rc.samples, 2)
+
+	// t1 keeps reporting, t2 goes away.
+	for _, offsetSec := range []int{20, 40, 60} {
+		rc.Rates(newEM(offsetSec, "t1"))
+	}
+	assert.Len(t, rc.samples, 1)
+	assert.Equal(t, []Rate{{"total_per_second", 1}}, rc.Rates(newEM(80, "t1")))
+}
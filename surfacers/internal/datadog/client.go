@@ -23,37 +23,83 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 )
 
 const defaultServer = "api.datadoghq.com"
 
+// API paths for the series and distribution points submissions.
+const (
+	seriesPath       = "/api/v2/series"
+	distributionPath = "/api/v1/distribution_points"
+)
+
+// Backoff for the rate limited submissions, if Datadog doesn't report the rate
+// limit reset time.
+const (
+	defaultBaseBackoff = time.Second
+	maxBackoff         = time.Minute
+)
+
 type ddClient struct {
 	apiKey         string
 	appKey         string
 	server         string
 	c              http.Client
 	useCompression bool
+	maxRetries     int
+	baseBackoff    time.Duration
+}
+
+// Metric types, as defined by the Datadog v2 series API.
+const (
+	ddCount = 1
+	ddGauge = 3
+)
+
+// ddPoint is a single point of a Datadog series.
+type ddPoint struct {
+	// Timestamp in POSIX time in seconds. It cannot be more than ten minutes in
+	// the future or more than one hour in the past.
+	Timestamp int64   `json:"timestamp"`
+	Value     float64 `json:"value"`
 }
 
 // ddSeries A metric to submit to Datadog. See:
-// https://docs.datadoghq.com/developers/metrics/#custom-metrics-properties
+// https://docs.datadoghq.com/api/latest/metrics/#submit-metrics
 type ddSeries struct {
-	// The name of the host that produced the metric.
-	Host *string `json:"host,omitempty"`
 	// The name of the timeseries.
 	Metric string `json:"metric"`
-	// Points relating to a metric. All points must be tuples with timestamp and
-	// a scalar value (cannot be a string). Timestamps should be in POSIX time in
-	// seconds, and cannot be more than ten minutes in the future or more than
-	// one hour in the past.
-	Points [][]float64 `json:"points"`
+	// Points relating to the metric.
+	Points []ddPoint `json:"points"`
 	// A list of tags associated with the metric.
-	Tags *[]string `json:"tags,omitempty"`
-	// The type of the metric either `count`, `gauge`, or `rate`.
-	Type *string `json:"type,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+	// The type of the metric: ddCount or ddGauge.
+	Type int `json:"type"`
+}
+
+// ddDistPoint is a point of a Datadog distribution: values observed at the
+// timestamp. It's encoded as "[timestamp, [values...]]".
+type ddDistPoint struct {
+	Timestamp int64
+	Values    []float64
+}
+
+func (p ddDistPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{p.Timestamp, p.Values})
 }
 
-func newClient(server, apiKey, appKey string, disableCompression bool) *ddClient {
+// ddDistribution A distribution metric to submit to Datadog. See:
+// https://docs.datadoghq.com/api/latest/metrics/#submit-distribution-points
+type ddDistribution struct {
+	Metric string        `json:"metric"`
+	Points []ddDistPoint `json:"points"`
+	Tags   []string      `json:"tags,omitempty"`
+	Type   string        `json:"type"`
+}
+
+func newClient(server, apiKey, appKey string, disableCompression bool, maxRetries int) *ddClient {
 	// to avoid the double negative boolean evaluation in logic branches, and improve
 	// readability, flip the value of the configuration option from disabling compression to
 	// using compression.
@@ -65,6 +111,8 @@ func newClient(server, apiKey, appKey string, disableCompression bool) *ddClient
 		server:         server,
 		c:              http.Client{},
 		useCompression: useCompression,
+		maxRetries:     maxRetries,
+		baseBackoff:    defaultBaseBackoff,
 	}
 	if c.apiKey == "" {
 		c.apiKey = os.Getenv("DD_API_KEY")
@@ -81,30 +129,31 @@ func newClient(server, apiKey, appKey string, disableCompression bool) *ddClient
 	return c
 }
 
-func (c *ddClient) newRequest(series []ddSeries) (*http.Request, error) {
-	url := fmt.Sprintf("https://%s/api/v1/series", c.server)
-
-	// JSON encoding of the datadog series.
-	// {
-	//   "series": [{..},{..}]
-	// }
-	json_body, err := json.Marshal(map[string][]ddSeries{"series": series})
+// encodePayload encodes the payload in JSON, with "key" as the top level
+// key, e.g. for series:
+//
+//	{
+//	  "series": [{..},{..}]
+//	}
+func (c *ddClient) encodePayload(key string, data interface{}) ([]byte, error) {
+	jsonBody, err := json.Marshal(map[string]interface{}{key: data})
 	if err != nil {
 		return nil, err
 	}
-
-	var payload *bytes.Buffer
-
-	if c.useCompression {
-		payload, err = compressPayload(json_body)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		payload = bytes.NewBuffer(json_body)
+	if !c.useCompression {
+		return jsonBody, nil
+	}
+	payload, err := compressPayload(jsonBody)
+	if err != nil {
+		return nil, err
 	}
+	return payload.Bytes(), nil
+}
+
+func (c *ddClient) newRequest(path string, payload []byte) (*http.Request, error) {
+	url := fmt.Sprintf("https://%s%s", c.server, path)
 
-	req, err := http.NewRequest("POST", url, payload)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
@@ -120,23 +169,63 @@ func (c *ddClient) newRequest(series []ddSeries) (*http.Request, error) {
 	return req, nil
 }
 
-func (c *ddClient) submitMetrics(ctx context.Context, series []ddSeries) error {
-	req, err := c.newRequest(series)
-	if err != nil {
+// rateLimitBackoff returns how long to wait before retrying a rate limited
+// submission.
+func (c *ddClient) rateLimitBackoff(h http.Header, attempt int) time.Duration {
+	if reset, err := strconv.Atoi(h.Get("X-RateLimit-Reset")); err == nil && reset > 0 {
+		return min(time.Duration(reset)*time.Second, maxBackoff)
+	}
+	return min(c.baseBackoff<<attempt, maxBackoff)
+}
+
+// submit posts the payload to the given API path, retrying if the request
+// is rate limited.
+func (c *ddClient) submit(ctx context.Context, path string, payload []byte) error {
+	for attempt := 0; ; attempt++ {
+		req, err := c.newRequest(path, payload)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.c.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		b, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < c.maxRetries {
+			timer := time.NewTimer(c.rateLimitBackoff(resp.Header, attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("rate limited, context canceled while waiting to retry: %v", ctx.Err())
+			case <-timer.C:
+			}
+			continue
+		}
+
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("error, HTTP status: %d, full response: %s", resp.StatusCode, string(b))
+		}
 		return nil
 	}
+}
 
-	resp, err := c.c.Do(req.WithContext(ctx))
+func (c *ddClient) submitMetrics(ctx context.Context, series []ddSeries) error {
+	payload, err := c.encodePayload("series", series)
 	if err != nil {
 		return err
 	}
+	return c.submit(ctx, seriesPath, payload)
+}
 
-	if resp.StatusCode >= 300 {
-		b, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("error, HTTP status: %d, full response: %s", resp.StatusCode, string(b))
+func (c *ddClient) submitDistributions(ctx context.Context, dists []ddDistribution) error {
+	payload, err := c.encodePayload("series", dists)
+	if err != nil {
+		return err
 	}
-
-	return nil
+	return c.submit(ctx, distributionPath, payload)
 }
 
 func compressPayload(b []byte) (*bytes.Buffer, error) {
@@ -16,11 +16,16 @@ package datadog
 
 import (
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"io"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -49,6 +54,8 @@ func TestNewClient(t *testing.T) {
 				appKey:         cAppKey,
 				server:         defaultServer,
 				useCompression: true,
+				maxRetries:     3,
+				baseBackoff:    defaultBaseBackoff,
 			},
 		},
 		{
@@ -64,6 +71,8 @@ func TestNewClient(t *testing.T) {
 				appKey:         eAppKey,
 				server:         "test-server",
 				useCompression: true,
+				maxRetries:     3,
+				baseBackoff:    defaultBaseBackoff,
 			},
 		},
 		{
@@ -79,6 +88,8 @@ func TestNewClient(t *testing.T) {
 				appKey:         eAppKey,
 				server:         "test-server",
 				useCompression: false,
+				maxRetries:     3,
+				baseBackoff:    defaultBaseBackoff,
 			},
 		},
 		{
@@ -94,6 +105,8 @@ func TestNewClient(t *testing.T) {
 				appKey:         eAppKey,
 				server:         "test-server",
 				useCompression: true,
+				maxRetries:     3,
+				baseBackoff:    defaultBaseBackoff,
 			},
 		},
 	}
@@ -104,7 +117,7 @@ func TestNewClient(t *testing.T) {
 				os.Setenv(k, v)
 			}
 
-			c := newClient(test.server, test.apiKey, test.appKey, test.disableCompression)
+			c := newClient(test.server, test.apiKey, test.appKey, test.disableCompression, 3)
 			if !reflect.DeepEqual(c, test.wantClient) {
 				t.Errorf("got client: %v, want client: %v", c, test.wantClient)
 			}
@@ -115,7 +128,6 @@ func TestNewClient(t *testing.T) {
 func TestNewRequest(t *testing.T) {
 	ts := time.Now().Unix()
 	tags := []string{"probe:cloudprober_http"}
-	metricType := "count"
 
 	generateMetricsFunc := func(n int) []ddSeries {
 		var result []ddSeries
@@ -140,9 +152,9 @@ func TestNewRequest(t *testing.T) {
 
 			result = append(result, ddSeries{
 				Metric: metricName,
-				Points: [][]float64{{float64(ts), point}},
-				Tags:   &tags,
-				Type:   &metricType,
+				Points: []ddPoint{{Timestamp: ts, Value: point}},
+				Tags:   tags,
+				Type:   ddCount,
 			})
 		}
 
@@ -178,14 +190,18 @@ func TestNewRequest(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			testClient := newClient("", "test-api-key", "test-app-key", test.disableCompression)
-			req, err := testClient.newRequest(test.ddSeries)
+			testClient := newClient("", "test-api-key", "test-app-key", test.disableCompression, 3)
+			payload, err := testClient.encodePayload("series", test.ddSeries)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			req, err := testClient.newRequest(seriesPath, payload)
 			if err != nil {
 				t.Fatalf("Unexpected error: %v", err)
 			}
 
 			// Check URL
-			wantURL := "https://api.datadoghq.com/api/v1/series"
+			wantURL := "https://api.datadoghq.com/api/v2/series"
 			if req.URL.String() != wantURL {
 				t.Fatalf("Got URL: %s, wanted: %s", req.URL.String(), wantURL)
 			}
@@ -238,3 +254,86 @@ func TestNewRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestDistPointJSON(t *testing.T) {
+	b, err := json.Marshal(ddDistribution{
+		Metric: "cloudprober.latency",
+		Points: []ddDistPoint{{Timestamp: 1700000000, Values: []float64{1, 2.5}}},
+		Type:   "distribution",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := `{"metric":"cloudprober.latency","points":[[1700000000,[1,2.5]]],"type":"distribution"}`
+	if string(b) != want {
+		t.Errorf("Got JSON: %s, wanted: %s", string(b), want)
+	}
+}
+
+func TestRateLimitBackoff(t *testing.T) {
+	c := newClient("", "test-api-key", "test-app-key", false, 3)
+
+	tests := []struct {
+		reset   string
+		attempt int
+		want    time.Duration
+	}{
+		{reset: "5", want: 5 * time.Second},
+		{reset: "3600", want: maxBackoff},
+		{attempt: 0, want: time.Second},
+		{attempt: 2, want: 4 * time.Second},
+		{reset: "0", attempt: 1, want: 2 * time.Second},
+		{reset: "bad", attempt: 10, want: maxBackoff},
+	}
+	for _, test := range tests {
+		h := http.Header{}
+		if test.reset != "" {
+			h.Set("X-RateLimit-Reset", test.reset)
+		}
+		if got := c.rateLimitBackoff(h, test.attempt); got != test.want {
+			t.Errorf("rateLimitBackoff(reset=%s, attempt=%d)=%v, wanted: %v", test.reset, test.attempt, got, test.want)
+		}
+	}
+}
+
+func TestSubmitRateLimited(t *testing.T) {
+	tests := []struct {
+		desc        string
+		rateLimited int32
+		wantErr     bool
+		wantCalls   int32
+	}{
+		{desc: "no-rate-limit", rateLimited: 0, wantCalls: 1},
+		{desc: "rate-limited-twice", rateLimited: 2, wantCalls: 3},
+		{desc: "retries-exhausted", rateLimited: 5, wantErr: true, wantCalls: 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var calls atomic.Int32
+			ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != seriesPath {
+					t.Errorf("Got path: %s, wanted: %s", r.URL.Path, seriesPath)
+				}
+				if calls.Add(1) <= test.rateLimited {
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer ts.Close()
+
+			c := newClient(strings.TrimPrefix(ts.URL, "https://"), "test-api-key", "test-app-key", false, 3)
+			c.c = *ts.Client()
+			c.baseBackoff = time.Millisecond
+
+			err := c.submitMetrics(context.Background(), []ddSeries{{Metric: "cloudprober.total", Type: ddCount}})
+			if (err != nil) != test.wantErr {
+				t.Errorf("Got error: %v, wanted error: %v", err, test.wantErr)
+			}
+			if calls.Load() != test.wantCalls {
+				t.Errorf("Got %d calls, wanted: %d", calls.Load(), test.wantCalls)
+			}
+		})
+	}
+}
@@ -20,14 +20,16 @@ package datadog
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/flush"
 	"github.com/cloudprober/cloudprober/surfacers/internal/common/options"
 	configpb "github.com/cloudprober/cloudprober/surfacers/internal/datadog/proto"
-	"google.golang.org/protobuf/proto"
 )
 
 /*
@@ -35,24 +37,46 @@ import (
 	using the config passed in.
 
 	Some EventMetrics are not supported here, as the datadog SubmitMetrics API only
-	supports float64 type values as the metric value.
+	supports float64 type values as the metric value. Distributions are
+	submitted as Datadog distributions, using the distribution points API.
 */
 
-var datadogKind = map[metrics.Kind]string{
-	metrics.GAUGE:      "gauge",
-	metrics.CUMULATIVE: "count",
+// Cumulative distributions that have not been seen for this long are
+// forgotten, e.g. the distributions of the removed targets. It's well above
+// the probes' stats export intervals, as a distribution forgotten too early
+// would be submitted again with all its samples.
+const staleDistAge = time.Hour
+
+// lastDist is the last value of a cumulative distribution.
+type lastDist struct {
+	dist *metrics.Distribution
+	ts   time.Time
+}
+
+var datadogKind = map[metrics.Kind]int{
+	metrics.GAUGE:      ddGauge,
+	metrics.CUMULATIVE: ddCount,
 }
 
 // DDSurfacer implements a datadog surfacer for datadog metrics.
 type DDSurfacer struct {
 	c         *configpb.SurfacerConf
 	writeChan chan *metrics.EventMetrics
+	loopDone  chan struct{}
 	client    *ddClient
 	l         *logger.Logger
 	prefix    string
 
 	// A cache of []*ddSeries, used for batch writing to datadog
 	ddSeriesCache []ddSeries
+
+	// A cache of distributions, written to datadog along with the series.
+	ddDistCache []ddDistribution
+
+	// Last value of the cumulative distributions, keyed by the metric name
+	// and tags. Datadog distributions take the values observed since the last
+	// submission.
+	lastDists map[string]*lastDist
 }
 
 // New creates a new instance of a datadog surfacer, based on the config passed in. It then hands off
@@ -73,10 +97,12 @@ func New(ctx context.Context, config *configpb.SurfacerConf, opts *options.Optio
 	dd := &DDSurfacer{
 		c:             config,
 		writeChan:     make(chan *metrics.EventMetrics, config.GetMetricsBatchSize()),
-		client:        newClient(config.GetServer(), config.GetApiKey(), config.GetAppKey(), config.GetDisableCompression()),
+		loopDone:      make(chan struct{}),
+		client:        newClient(config.GetServer(), config.GetApiKey(), config.GetAppKey(), config.GetDisableCompression(), int(config.GetMaxRateLimitRetries())),
 		l:             l,
 		prefix:        p,
 		ddSeriesCache: make([]ddSeries, 0, config.GetMetricsBatchSize()),
+		lastDists:     make(map[string]*lastDist),
 	}
 
 	go dd.receiveMetricsFromEvent(ctx)
@@ -96,6 +122,8 @@ func (dd *DDSurfacer) Write(ctx context.Context, em *metrics.EventMetrics) {
 }
 
 func (dd *DDSurfacer) receiveMetricsFromEvent(ctx context.Context) {
+	defer close(dd.loopDone)
+
 	publishTimer := time.NewTicker(time.Duration(dd.c.GetBatchTimerSec()) * time.Second)
 	defer publishTimer.Stop()
 
@@ -106,14 +134,47 @@ func (dd *DDSurfacer) receiveMetricsFromEvent(ctx context.Context) {
 			return
 		case em := <-dd.writeChan:
 			dd.recordEventMetrics(ctx, publishTimer, em)
-		case <-publishTimer.C:
-			if len(dd.ddSeriesCache) != 0 {
+		case ts := <-publishTimer.C:
+			if dd.cacheSize() != 0 {
 				dd.publishMetrics(ctx)
 			}
+			dd.expireDists(ts.Add(-staleDistAge))
 		}
 	}
 }
 
+// expireDists forgets the cumulative distributions that have not been seen
+// since the given time.
+func (dd *DDSurfacer) expireDists(staleBefore time.Time) {
+	for key, ld := range dd.lastDists {
+		if ld.ts.Before(staleBefore) {
+			delete(dd.lastDists, key)
+		}
+	}
+}
+
+// Flush publishes the EventMetrics remaining in the write channel, along with
+// the buffered series, once the write loop has stopped, i.e. after the
+// context passed to New is canceled.
+func (dd *DDSurfacer) Flush(ctx context.Context) error {
+	if err := flush.WaitForStop(ctx, dd.loopDone); err != nil {
+		return err
+	}
+
+	publishTimer := time.NewTicker(time.Duration(dd.c.GetBatchTimerSec()) * time.Second)
+	defer publishTimer.Stop()
+	dropped := flush.Drain(ctx, dd.writeChan, func(em *metrics.EventMetrics) {
+		dd.recordEventMetrics(ctx, publishTimer, em)
+	})
+	if err := flush.DroppedError(ctx, dropped, "EventMetrics"); err != nil {
+		return err
+	}
+	if dd.cacheSize() != 0 {
+		dd.publishMetrics(ctx)
+	}
+	return nil
+}
+
 func recordMapValue[T int64 | float64](dd *DDSurfacer, m *metrics.Map[T], baseTags []string, key string, em *metrics.EventMetrics) []ddSeries {
 	var series []ddSeries
 	for _, k := range m.Keys() {
@@ -133,39 +194,65 @@ func (dd *DDSurfacer) recordEventMetrics(ctx context.Context, publishTimer *time
 		case *metrics.Map[float64]:
 			series = recordMapValue(dd, value, emLabelsToTags(em), metricKey, em)
 		case *metrics.Distribution:
-			series = dd.distToDDSeries(value.Data(), metricKey, emLabelsToTags(em), em.Timestamp, em.Kind)
+			tags := emLabelsToTags(em)
+			series = dd.distToDDSeries(value.Data(), metricKey, tags, em.Timestamp, em.Kind)
+			if dist := dd.distToDDDistribution(value, metricKey, tags, em.Timestamp, em.Kind); dist != nil {
+				dd.addDistAndPublish(ctx, publishTimer, *dist)
+			}
 		}
 		dd.addMetricsAndPublish(ctx, publishTimer, series...)
 	}
 }
 
+// cacheSize returns the number of series and distributions waiting to be
+// published.
+func (dd *DDSurfacer) cacheSize() int {
+	return len(dd.ddSeriesCache) + len(dd.ddDistCache)
+}
+
 // publish the metrics to datadog, buffering as necessary
 func (dd *DDSurfacer) addMetricsAndPublish(ctx context.Context, publishTimer *time.Ticker, series ...ddSeries) {
 	for i := range series {
-		if len(dd.ddSeriesCache) >= int(dd.c.GetMetricsBatchSize()) {
-			dd.publishMetrics(ctx)
-			publishTimer.Reset(time.Duration(dd.c.GetBatchTimerSec()) * time.Second)
-		}
-
+		dd.publishIfFull(ctx, publishTimer)
 		dd.ddSeriesCache = append(dd.ddSeriesCache, series[i])
 	}
 }
 
+func (dd *DDSurfacer) addDistAndPublish(ctx context.Context, publishTimer *time.Ticker, dist ddDistribution) {
+	dd.publishIfFull(ctx, publishTimer)
+	dd.ddDistCache = append(dd.ddDistCache, dist)
+}
+
+func (dd *DDSurfacer) publishIfFull(ctx context.Context, publishTimer *time.Ticker) {
+	if dd.cacheSize() >= int(dd.c.GetMetricsBatchSize()) {
+		dd.publishMetrics(ctx)
+		publishTimer.Reset(time.Duration(dd.c.GetBatchTimerSec()) * time.Second)
+	}
+}
+
 func (dd *DDSurfacer) publishMetrics(ctx context.Context) {
-	if err := dd.client.submitMetrics(ctx, dd.ddSeriesCache); err != nil {
-		dd.l.Errorf("Failed to publish %d series to datadog: %v", len(dd.ddSeriesCache), err)
+	if len(dd.ddSeriesCache) != 0 {
+		if err := dd.client.submitMetrics(ctx, dd.ddSeriesCache); err != nil {
+			dd.l.Errorf("Failed to publish %d series to datadog: %v", len(dd.ddSeriesCache), err)
+		}
+	}
+	if len(dd.ddDistCache) != 0 {
+		if err := dd.client.submitDistributions(ctx, dd.ddDistCache); err != nil {
+			dd.l.Errorf("Failed to publish %d distributions to datadog: %v", len(dd.ddDistCache), err)
+		}
 	}
 
 	dd.ddSeriesCache = dd.ddSeriesCache[:0]
+	dd.ddDistCache = dd.ddDistCache[:0]
 }
 
 // Create a new datadog series using the values passed in.
 func (dd *DDSurfacer) newDDSeries(metricName string, value float64, tags []string, timestamp time.Time, kind metrics.Kind) ddSeries {
 	return ddSeries{
 		Metric: dd.prefix + metricName,
-		Points: [][]float64{{float64(timestamp.Unix()), value}},
-		Tags:   &tags,
-		Type:   proto.String(datadogKind[kind]),
+		Points: []ddPoint{{Timestamp: timestamp.Unix(), Value: value}},
+		Tags:   tags,
+		Type:   datadogKind[kind],
 	}
 }
 
@@ -180,30 +267,64 @@ func emLabelsToTags(em *metrics.EventMetrics) []string {
 	return tags
 }
 
+// distToDDSeries returns the sum and count series for the distribution.
+// Distribution itself is submitted as a Datadog distribution, see
+// distToDDDistribution.
 func (dd *DDSurfacer) distToDDSeries(d *metrics.DistributionData, metricName string, tags []string, t time.Time, kind metrics.Kind) []ddSeries {
-	ret := []ddSeries{
-		{
-			Metric: dd.prefix + metricName + ".sum",
-			Points: [][]float64{{float64(t.Unix()), d.Sum}},
-			Tags:   &tags,
-			Type:   proto.String(datadogKind[kind]),
-		}, {
-			Metric: dd.prefix + metricName + ".count",
-			Points: [][]float64{{float64(t.Unix()), float64(d.Count)}},
-			Tags:   &tags,
-			Type:   proto.String(datadogKind[kind]),
-		},
-	}
-
-	// Add one point at the value of the Lower Bound per count in the bucket. Each point represents the
-	// minimum poissible value that it could have been.
-	var points [][]float64
+	return []ddSeries{
+		dd.newDDSeries(metricName+".sum", d.Sum, tags, t, kind),
+		dd.newDDSeries(metricName+".count", float64(d.Count), tags, t, kind),
+	}
+}
+
+// bucketValue returns the value that represents the samples in the bucket
+// i: the middle of the bucket, or its finite bound for the first and the last
+// buckets.
+func bucketValue(lowerBounds []float64, i int) float64 {
+	if math.IsInf(lowerBounds[i], -1) {
+		if i == len(lowerBounds)-1 {
+			return 0
+		}
+		return lowerBounds[i+1]
+	}
+	if i == len(lowerBounds)-1 {
+		return lowerBounds[i]
+	}
+	return (lowerBounds[i] + lowerBounds[i+1]) / 2
+}
+
+// distToDDDistribution converts the distribution into a Datadog
+// distribution. For cumulative distributions, only the samples observed since
+// the last call are included. It returns nil if there are no new samples.
+func (dd *DDSurfacer) distToDDDistribution(dist *metrics.Distribution, metricName string, tags []string, t time.Time, kind metrics.Kind) *ddDistribution {
+	delta := dist.CloneDist()
+	if kind == metrics.CUMULATIVE {
+		key := metricName + "," + strings.Join(tags, ",")
+		if last := dd.lastDists[key]; last != nil {
+			// Error means incompatible buckets, start over in that case.
+			if _, err := delta.SubtractCounter(last.dist); err != nil {
+				delta = dist.CloneDist()
+			}
+		}
+		dd.lastDists[key] = &lastDist{dist: dist.CloneDist(), ts: t}
+	}
+
+	d := delta.Data()
+	var values []float64
 	for i := range d.LowerBounds {
-		for n := 0; n < int(d.BucketCounts[i]); n++ {
-			points = append(points, []float64{float64(t.Unix()), d.LowerBounds[i]})
+		v := bucketValue(d.LowerBounds, i)
+		for n := int64(0); n < d.BucketCounts[i]; n++ {
+			values = append(values, v)
 		}
 	}
+	if len(values) == 0 {
+		return nil
+	}
 
-	ret = append(ret, ddSeries{Metric: dd.prefix + metricName, Points: points, Tags: &tags, Type: proto.String(datadogKind[kind])})
-	return ret
+	return &ddDistribution{
+		Metric: dd.prefix + metricName,
+		Points: []ddDistPoint{{Timestamp: t.Unix(), Values: values}},
+		Tags:   tags,
+		Type:   "distribution",
+	}
 }
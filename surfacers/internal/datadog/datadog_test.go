@@ -15,6 +15,7 @@
 package datadog
 
 import (
+	"math"
 	"reflect"
 	"testing"
 	"time"
@@ -55,3 +56,75 @@ func TestEmLabelsToTags(t *testing.T) {
 		})
 	}
 }
+
+func TestBucketValue(t *testing.T) {
+	lowerBounds := []float64{math.Inf(-1), 1, 5, 10}
+	for i, want := range []float64{1, 3, 7.5, 10} {
+		if got := bucketValue(lowerBounds, i); got != want {
+			t.Errorf("bucketValue(%v, %d)=%v, want: %v", lowerBounds, i, got, want)
+		}
+	}
+}
+
+func TestDistToDDDistribution(t *testing.T) {
+	dd := &DDSurfacer{
+		prefix:    "cloudprober.",
+		lastDists: make(map[string]*lastDist),
+	}
+	ts := time.Now()
+	tags := []string{"probe:p1"}
+
+	d := metrics.NewDistribution([]float64{1, 5})
+	d.AddSample(0.5)
+	d.AddSample(2)
+	d.AddSample(3)
+
+	got := dd.distToDDDistribution(d, "latency", tags, ts, metrics.CUMULATIVE)
+	want := &ddDistribution{
+		Metric: "cloudprober.latency",
+		Points: []ddDistPoint{{Timestamp: ts.Unix(), Values: []float64{1, 3, 3}}},
+		Tags:   tags,
+		Type:   "distribution",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+
+	// No new samples.
+	if got := dd.distToDDDistribution(d, "latency", tags, ts, metrics.CUMULATIVE); got != nil {
+		t.Errorf("got: %v, want: nil", got)
+	}
+
+	// Only new samples are included.
+	d.AddSample(10)
+	got = dd.distToDDDistribution(d, "latency", tags, ts, metrics.CUMULATIVE)
+	want.Points[0].Values = []float64{5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+
+	// Gauge distributions are submitted as is.
+	got = dd.distToDDDistribution(d, "latency", tags, ts, metrics.GAUGE)
+	want.Points[0].Values = []float64{1, 3, 3, 5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestExpireDists(t *testing.T) {
+	dd := &DDSurfacer{
+		prefix:    "cloudprober.",
+		lastDists: make(map[string]*lastDist),
+	}
+	ts := time.Now()
+
+	d := metrics.NewDistribution([]float64{1, 5})
+	d.AddSample(2)
+	dd.distToDDDistribution(d, "latency", []string{"dst:t1"}, ts.Add(-2*staleDistAge), metrics.CUMULATIVE)
+	dd.distToDDDistribution(d, "latency", []string{"dst:t2"}, ts, metrics.CUMULATIVE)
+
+	dd.expireDists(ts.Add(-staleDistAge))
+	if len(dd.lastDists) != 1 || dd.lastDists["latency,dst:t2"] == nil {
+		t.Errorf("got lastDists: %v, want only latency,dst:t2", dd.lastDists)
+	}
+}
@@ -20,7 +20,9 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// Surfacer config for datadog surfacer.
+// Surfacer config for datadog surfacer. Metrics are submitted using the
+// Datadog v2 series API, except distributions, which are submitted as Datadog
+// distributions using the distribution points API.
 type SurfacerConf struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -49,13 +51,19 @@ type SurfacerConf struct {
 	// Disable gzip compression of metric payload, when sending metrics to Datadog.
 	// Compression is enabled by default.
 	DisableCompression *bool `protobuf:"varint,7,opt,name=disable_compression,json=disableCompression" json:"disable_compression,omitempty"`
+	// How many times to retry a submission that is rejected because of the
+	// Datadog API rate limits (HTTP 429). Retries wait for the rate limit
+	// reset time reported by Datadog (X-RateLimit-Reset header), or back off
+	// exponentially if it's not reported.
+	MaxRateLimitRetries *int32 `protobuf:"varint,8,opt,name=max_rate_limit_retries,json=maxRateLimitRetries,def=3" json:"max_rate_limit_retries,omitempty"`
 }
 
 // Default values for SurfacerConf fields.
 const (
-	Default_SurfacerConf_Prefix           = string("cloudprober")
-	Default_SurfacerConf_MetricsBatchSize = int32(1000)
-	Default_SurfacerConf_BatchTimerSec    = int32(30)
+	Default_SurfacerConf_Prefix              = string("cloudprober")
+	Default_SurfacerConf_MetricsBatchSize    = int32(1000)
+	Default_SurfacerConf_BatchTimerSec       = int32(30)
+	Default_SurfacerConf_MaxRateLimitRetries = int32(3)
 )
 
 func (x *SurfacerConf) Reset() {
@@ -139,6 +147,13 @@ func (x *SurfacerConf) GetDisableCompression() bool {
 	return false
 }
 
+func (x *SurfacerConf) GetMaxRateLimitRetries() int32 {
+	if x != nil && x.MaxRateLimitRetries != nil {
+		return *x.MaxRateLimitRetries
+	}
+	return Default_SurfacerConf_MaxRateLimitRetries
+}
+
 var File_github_com_cloudprober_cloudprober_surfacers_internal_datadog_proto_config_proto protoreflect.FileDescriptor
 
 var file_github_com_cloudprober_cloudprober_surfacers_internal_datadog_proto_config_proto_rawDesc = []byte{
@@ -149,7 +164,7 @@ var file_github_com_cloudprober_cloudprober_surfacers_internal_datadog_proto_con
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x12, 0x1c, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e,
 	0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x64, 0x6f, 0x67,
-	0x22, 0x8e, 0x02, 0x0a, 0x0c, 0x53, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e,
+	0x22, 0xc6, 0x02, 0x0a, 0x0c, 0x53, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e,
 	0x66, 0x12, 0x23, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x09, 0x3a, 0x0b, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x52, 0x06,
 	0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x17, 0x0a, 0x07, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65,
@@ -166,11 +181,14 @@ var file_github_com_cloudprober_cloudprober_surfacers_internal_datadog_proto_con
 	0x12, 0x2f, 0x0a, 0x13, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x63, 0x6f, 0x6d, 0x70,
 	0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x64,
 	0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f,
-	0x6e, 0x42, 0x45, 0x5a, 0x43, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75,
-	0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72,
-	0x73, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x64, 0x61, 0x74, 0x61, 0x64,
-	0x6f, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x6e, 0x12, 0x36, 0x0a, 0x16, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x5f, 0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x05, 0x3a, 0x01, 0x33, 0x52, 0x13, 0x6d, 0x61, 0x78, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x42, 0x45, 0x5a, 0x43, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f,
+	0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f,
+	0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x73, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e,
+	0x61, 0x6c, 0x2f, 0x64, 0x61, 0x74, 0x61, 0x64, 0x6f, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 }
 
 var (
@@ -1,6 +1,8 @@
 package proto
 
-// Surfacer config for datadog surfacer.
+// Surfacer config for datadog surfacer. Metrics are submitted using the
+// Datadog v2 series API, except distributions, which are submitted as Datadog
+// distributions using the distribution points API.
 #SurfacerConf: {
 	// Prefix to add to all metrics.
 	prefix?: string @protobuf(1,string,#"default="cloudprober""#)
@@ -31,4 +33,10 @@ package proto
 	// Disable gzip compression of metric payload, when sending metrics to Datadog.
 	// Compression is enabled by default.
 	disableCompression?: bool @protobuf(7,bool,name=disable_compression)
+
+	// How many times to retry a submission that is rejected because of the
+	// Datadog API rate limits (HTTP 429). Retries wait for the rate limit
+	// reset time reported by Datadog (X-RateLimit-Reset header), or back off
+	// exponentially if it's not reported.
+	maxRateLimitRetries?: int32 @protobuf(8,int32,name=max_rate_limit_retries,"default=3")
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
 	"github.com/cloudprober/cloudprober/surfacers/internal/common/compress"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/flush"
 	"github.com/cloudprober/cloudprober/surfacers/internal/common/options"
 
 	configpb "github.com/cloudprober/cloudprober/surfacers/internal/file/proto"
@@ -43,6 +44,7 @@ type Surfacer struct {
 	// Channel for incoming data.
 	inChan         chan *metrics.EventMetrics
 	processInputWg sync.WaitGroup
+	loopDone       chan struct{}
 
 	// Output file for serializing to
 	outf *os.File
@@ -59,6 +61,7 @@ type Surfacer struct {
 
 func (s *Surfacer) processInput(ctx context.Context) {
 	defer s.processInputWg.Done()
+	defer close(s.loopDone)
 
 	for {
 		select {
@@ -67,22 +70,7 @@ func (s *Surfacer) processInput(ctx context.Context) {
 			if !ok {
 				return
 			}
-			var emStr strings.Builder
-			emStr.WriteString(s.c.GetPrefix())
-			emStr.WriteByte(' ')
-			emStr.WriteString(strconv.FormatInt(s.id, 10))
-			emStr.WriteByte(' ')
-			emStr.WriteString(em.String())
-			s.id++
-
-			// If compression is not enabled, write line to file and continue.
-			if !s.c.GetCompressionEnabled() {
-				if _, err := s.outf.WriteString(emStr.String() + "\n"); err != nil {
-					s.l.Errorf("Unable to write data to %s. Err: %v", s.c.GetFilePath(), err)
-				}
-			} else {
-				s.compressionBuffer.WriteLineToBuffer(emStr.String())
-			}
+			s.writeEM(em)
 
 		case <-ctx.Done():
 			return
@@ -90,8 +78,28 @@ func (s *Surfacer) processInput(ctx context.Context) {
 	}
 }
 
+func (s *Surfacer) writeEM(em *metrics.EventMetrics) {
+	var emStr strings.Builder
+	emStr.WriteString(s.c.GetPrefix())
+	emStr.WriteByte(' ')
+	emStr.WriteString(strconv.FormatInt(s.id, 10))
+	emStr.WriteByte(' ')
+	emStr.WriteString(em.String())
+	s.id++
+
+	// If compression is not enabled, write line to file and return.
+	if !s.c.GetCompressionEnabled() {
+		if _, err := s.outf.WriteString(emStr.String() + "\n"); err != nil {
+			s.l.Errorf("Unable to write data to %s. Err: %v", s.c.GetFilePath(), err)
+		}
+		return
+	}
+	s.compressionBuffer.WriteLineToBuffer(emStr.String())
+}
+
 func (s *Surfacer) init(ctx context.Context, id int64) error {
 	s.inChan = make(chan *metrics.EventMetrics, s.opts.MetricsBufferSize)
+	s.loopDone = make(chan struct{})
 	s.id = id
 
 	// File handle for the output file
@@ -134,6 +142,20 @@ func (s *Surfacer) close() {
 	s.outf.Close()
 }
 
+// Flush writes the EventMetrics remaining in the input channel, once the
+// input processing loop has stopped, i.e. after the context passed to New is
+// canceled.
+func (s *Surfacer) Flush(ctx context.Context) error {
+	if err := flush.WaitForStop(ctx, s.loopDone); err != nil {
+		return err
+	}
+	dropped := flush.Drain(ctx, s.inChan, s.writeEM)
+	if s.compressionBuffer != nil {
+		s.compressionBuffer.Close()
+	}
+	return flush.DroppedError(ctx, dropped, "EventMetrics")
+}
+
 // Write queues the incoming data into a channel. This channel is watched by a
 // goroutine that actually writes data to a file ((usually set as a GCE
 // instance's serial port).
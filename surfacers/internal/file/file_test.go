@@ -109,3 +109,46 @@ func testWrite(t *testing.T, compressionEnabled bool) {
 		}
 	}
 }
+
+func TestFlush(t *testing.T) {
+	f, err := os.CreateTemp("", "file_test")
+	if err != nil {
+		t.Fatalf("Unable to create a new file for testing: %v", err)
+	}
+	defer os.Remove(f.Name())
+
+	s := &Surfacer{
+		c: &configpb.SurfacerConf{
+			FilePath: proto.String(f.Name()),
+		},
+		opts: &options.Options{
+			MetricsBufferSize: 1000,
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	id := time.Now().UnixNano()
+	if err := s.init(ctx, id); err != nil {
+		t.Fatalf("Unable to create a new file surfacer: %v", err)
+	}
+
+	// Stop the input processing loop, so that EventMetrics stay in the input
+	// channel until flushed.
+	cancel()
+	<-s.loopDone
+	em := metrics.NewEventMetrics(time.Now()).AddMetric("flush-test", metrics.NewInt(1))
+	s.Write(context.Background(), em)
+
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected error while flushing: %v", err)
+	}
+	s.outf.Close()
+
+	dat, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatalf("Unable to open test output file for reading: %v", err)
+	}
+	expectedStr := fmt.Sprintf("%s %d %s\n", s.c.GetPrefix(), id, em.String())
+	if diff := pretty.Compare(expectedStr, string(dat)); diff != "" {
+		t.Errorf("Message written does not match expected output (-want +got):\n%s", diff)
+	}
+}
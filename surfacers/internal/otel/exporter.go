@@ -0,0 +1,118 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otel
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/cloudprober/cloudprober/internal/tlsconfig"
+	configpb "github.com/cloudprober/cloudprober/surfacers/internal/otel/proto"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"google.golang.org/grpc/credentials"
+)
+
+// retryConfig returns the exporter's retry config. Both the HTTP and gRPC
+// exporters use the same underlying retry config type.
+func retryConfig(c *configpb.RetryConfig) otlpmetrichttp.RetryConfig {
+	return otlpmetrichttp.RetryConfig{
+		Enabled:         !c.GetDisabled(),
+		InitialInterval: time.Duration(c.GetInitialIntervalMsec()) * time.Millisecond,
+		MaxInterval:     time.Duration(c.GetMaxIntervalMsec()) * time.Millisecond,
+		MaxElapsedTime:  time.Duration(c.GetMaxElapsedTimeSec()) * time.Second,
+	}
+}
+
+func newHTTPExporter(ctx context.Context, c *configpb.HTTPExporter, retry *configpb.RetryConfig) (sdkmetric.Exporter, error) {
+	opts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithRetry(retryConfig(retry)),
+	}
+
+	if c.GetEndpointUrl() != "" {
+		u, err := url.Parse(c.GetEndpointUrl())
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("invalid endpoint_url (%s), it should be like: https://otel-collector:4318/v1/metrics", c.GetEndpointUrl())
+		}
+		opts = append(opts, otlpmetrichttp.WithEndpoint(u.Host))
+		if u.Path != "" {
+			opts = append(opts, otlpmetrichttp.WithURLPath(u.Path))
+		}
+		if u.Scheme == "http" {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+	}
+
+	if c.GetTlsConfig() != nil {
+		tlsConfig := &tls.Config{}
+		if err := tlsconfig.UpdateTLSConfig(tlsConfig, c.GetTlsConfig()); err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsConfig))
+	}
+
+	if len(c.GetHttpHeader()) > 0 {
+		opts = append(opts, otlpmetrichttp.WithHeaders(c.GetHttpHeader()))
+	}
+
+	if c.GetCompression() == configpb.Compression_GZIP {
+		opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+	}
+
+	return otlpmetrichttp.New(ctx, opts...)
+}
+
+func newGRPCExporter(ctx context.Context, c *configpb.GRPCExporter, retry *configpb.RetryConfig) (sdkmetric.Exporter, error) {
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithRetry(otlpmetricgrpc.RetryConfig(retryConfig(retry))),
+	}
+
+	if c.GetEndpoint() != "" {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(c.GetEndpoint()))
+	}
+
+	if c.GetInsecure() {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	} else if c.GetTlsConfig() != nil {
+		tlsConfig := &tls.Config{}
+		if err := tlsconfig.UpdateTLSConfig(tlsConfig, c.GetTlsConfig()); err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+	}
+
+	if len(c.GetHttpHeader()) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(c.GetHttpHeader()))
+	}
+
+	if c.GetCompression() == configpb.Compression_GZIP {
+		opts = append(opts, otlpmetricgrpc.WithCompressor("gzip"))
+	}
+
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+// newExporter returns the OTLP exporter for the config. If no exporter is
+// configured, OTLP/HTTP exporter with the default settings is used.
+func newExporter(ctx context.Context, c *configpb.SurfacerConf) (sdkmetric.Exporter, error) {
+	if c.GetOtlpGrpcExporter() != nil {
+		return newGRPCExporter(ctx, c.GetOtlpGrpcExporter(), c.GetRetry())
+	}
+	return newHTTPExporter(ctx, c.GetOtlpHttpExporter(), c.GetRetry())
+}
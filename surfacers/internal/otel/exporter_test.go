@@ -0,0 +1,102 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otel
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/options"
+	configpb "github.com/cloudprober/cloudprober/surfacers/internal/otel/proto"
+	"github.com/stretchr/testify/assert"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestHTTPExport(t *testing.T) {
+	reqCh := make(chan *colmetricpb.ExportMetricsServiceRequest, 1)
+	var gotPath, gotHeader string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotHeader = r.URL.Path, r.Header.Get("X-Api-Key")
+		b, _ := io.ReadAll(r.Body)
+		req := &colmetricpb.ExportMetricsServiceRequest{}
+		if err := proto.Unmarshal(b, req); err != nil {
+			t.Errorf("error parsing the export request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		reqCh <- req
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s, err := New(ctx, &configpb.SurfacerConf{
+		Exporter: &configpb.SurfacerConf_OtlpHttpExporter{
+			OtlpHttpExporter: &configpb.HTTPExporter{
+				EndpointUrl: proto.String(ts.URL + "/otlp/v1/metrics"),
+				HttpHeader:  map[string]string{"X-Api-Key": "test-key"},
+			},
+		},
+		MetricsPrefix:     proto.String("cloudprober_"),
+		ResourceAttribute: []*configpb.Attribute{{Key: proto.String("env"), Value: proto.String("test")}},
+		HistogramType:     configpb.SurfacerConf_EXPONENTIAL.Enum(),
+	}, &options.Options{MetricsBufferSize: 10}, nil)
+	assert.NoError(t, err)
+
+	s.Write(ctx, metrics.NewEventMetrics(time.Now()).
+		AddMetric("total", metrics.NewInt(5)).
+		AddMetric("latency", testDist()).
+		AddLabel("probe", "p1"))
+	cancel()
+	assert.NoError(t, s.Flush(context.Background()))
+
+	var req *colmetricpb.ExportMetricsServiceRequest
+	select {
+	case req = <-reqCh:
+	default:
+		t.Fatal("no export request received")
+	}
+	assert.Equal(t, "/otlp/v1/metrics", gotPath)
+	assert.Equal(t, "test-key", gotHeader)
+
+	rm := req.GetResourceMetrics()[0]
+	resAttrs := make(map[string]string)
+	for _, kv := range rm.GetResource().GetAttributes() {
+		resAttrs[kv.GetKey()] = kv.GetValue().GetStringValue()
+	}
+	assert.Equal(t, "cloudprober", resAttrs["service.name"])
+	assert.Equal(t, "test", resAttrs["env"])
+
+	ms := rm.GetScopeMetrics()[0].GetMetrics()
+	assert.Len(t, ms, 2)
+	assert.Equal(t, "cloudprober_total", ms[0].GetName())
+	assert.Equal(t, int64(5), ms[0].GetSum().GetDataPoints()[0].GetAsInt())
+	assert.Equal(t, "p1", ms[0].GetSum().GetDataPoints()[0].GetAttributes()[0].GetValue().GetStringValue())
+	assert.Equal(t, "cloudprober_latency", ms[1].GetName())
+	assert.Equal(t, uint64(6), ms[1].GetExponentialHistogram().GetDataPoints()[0].GetCount())
+}
+
+func TestNewHTTPExporterEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"otel-collector:4318", "ftp://otel-collector:4318", "http://"} {
+		_, err := newHTTPExporter(context.Background(), &configpb.HTTPExporter{EndpointUrl: proto.String(endpoint)}, nil)
+		assert.Error(t, err, "endpoint: %s", endpoint)
+	}
+	_, err := newHTTPExporter(context.Background(), &configpb.HTTPExporter{EndpointUrl: proto.String("https://otel-collector:4318/v1/metrics")}, nil)
+	assert.NoError(t, err)
+}
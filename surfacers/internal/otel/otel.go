@@ -0,0 +1,553 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package otel implements a surfacer that exports metrics to an OpenTelemetry
+collector (or any other OTLP receiver), using OTLP/HTTP or OTLP/gRPC.
+*/
+package otel
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/flush"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/options"
+	configpb "github.com/cloudprober/cloudprober/surfacers/internal/otel/proto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+const (
+	scopeName = "github.com/cloudprober/cloudprober"
+
+	// Maximum number of buckets in the exponential histograms. Scale is
+	// reduced for the distributions that need more buckets. It's the same as
+	// the default max size of the OpenTelemetry SDK's exponential histograms.
+	maxExpBuckets = 160
+
+	// Maximum number of batches waiting to be exported. New batches are
+	// dropped if the exporter can't keep up.
+	exportQueueSize = 10
+
+	// Series that have not been seen for these many export intervals are
+	// forgotten, e.g. the series of the removed targets.
+	staleSeriesIntervals = 5
+)
+
+type valueType int
+
+const (
+	intValue valueType = iota
+	floatValue
+	distValue
+)
+
+// instrumentKey identifies an OpenTelemetry metric (instrument).
+type instrumentKey struct {
+	name  string
+	kind  metrics.Kind
+	vType valueType
+}
+
+// seriesKey identifies a time series, i.e. a metric and an attribute set.
+type seriesKey struct {
+	instrumentKey
+	attrs attribute.Distinct
+}
+
+// point is the latest data point of a series.
+type point struct {
+	attrs     attribute.Set
+	startTime time.Time
+	ts        time.Time
+	num       metrics.NumValue
+	dist      *metrics.DistributionData
+}
+
+// instrument buffers the latest data points of a metric.
+type instrument struct {
+	key    instrumentKey
+	points map[attribute.Distinct]*point
+	// Order of the series, to keep the export order stable.
+	order []attribute.Distinct
+}
+
+// seriesState is used to determine the start time of a series.
+type seriesState struct {
+	startTime time.Time
+	lastTS    time.Time
+	lastValue float64
+}
+
+// Surfacer implements the OpenTelemetry surfacer.
+type Surfacer struct {
+	c         *configpb.SurfacerConf
+	exporter  sdkmetric.Exporter
+	resource  *resource.Resource
+	writeChan chan *metrics.EventMetrics
+	loopDone  chan struct{}
+	l         *logger.Logger
+
+	// Batches are exported off the write loop, so that slow exports don't
+	// block recording of the new EventMetrics.
+	exportChan chan *metricdata.ResourceMetrics
+	exportDone chan struct{}
+
+	// Buffered metrics, in the order they were first seen.
+	instruments     map[instrumentKey]*instrument
+	instrumentOrder []instrumentKey
+	numPoints       int
+
+	series map[seriesKey]*seriesState
+}
+
+func newResource(c *configpb.SurfacerConf) (*resource.Resource, error) {
+	res, err := resource.Merge(resource.NewSchemaless(attribute.String("service.name", "cloudprober")), resource.Environment())
+	if err != nil {
+		return nil, err
+	}
+	var attrs []attribute.KeyValue
+	for _, a := range c.GetResourceAttribute() {
+		attrs = append(attrs, attribute.String(a.GetKey(), a.GetValue()))
+	}
+	return resource.Merge(res, resource.NewSchemaless(attrs...))
+}
+
+// New creates a new OpenTelemetry surfacer. Surfacer exports metrics until
+// the context is canceled.
+func New(ctx context.Context, config *configpb.SurfacerConf, opts *options.Options, l *logger.Logger) (*Surfacer, error) {
+	if config.GetExportIntervalSec() <= 0 {
+		return nil, fmt.Errorf("export_interval_sec (%d) should be positive", config.GetExportIntervalSec())
+	}
+	if scale := config.GetExponentialHistogramScale(); scale < -10 || scale > 20 {
+		return nil, fmt.Errorf("exponential_histogram_scale (%d) should be between -10 and 20", scale)
+	}
+
+	res, err := newResource(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the resource: %v", err)
+	}
+
+	exporter, err := newExporter(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the OTLP exporter: %v", err)
+	}
+
+	s := &Surfacer{
+		c:           config,
+		exporter:    exporter,
+		resource:    res,
+		writeChan:   make(chan *metrics.EventMetrics, opts.MetricsBufferSize),
+		loopDone:    make(chan struct{}),
+		l:           l,
+		exportChan:  make(chan *metricdata.ResourceMetrics, exportQueueSize),
+		exportDone:  make(chan struct{}),
+		instruments: make(map[instrumentKey]*instrument),
+		series:      make(map[seriesKey]*seriesState),
+	}
+
+	go s.exportLoop()
+	go s.writeLoop(ctx)
+
+	s.l.Info("Initialized OpenTelemetry surfacer")
+	return s, nil
+}
+
+// Write queues the EventMetrics for the export.
+func (s *Surfacer) Write(ctx context.Context, em *metrics.EventMetrics) {
+	select {
+	case s.writeChan <- em:
+	default:
+		s.l.Error("Surfacer's write channel is full, dropping new data.")
+	}
+}
+
+func (s *Surfacer) exportInterval() time.Duration {
+	return time.Duration(s.c.GetExportIntervalSec()) * time.Second
+}
+
+func (s *Surfacer) writeLoop(ctx context.Context) {
+	defer close(s.loopDone)
+	// Export loop exports the queued batches and exits.
+	defer close(s.exportChan)
+
+	exportTicker := time.NewTicker(s.exportInterval())
+	defer exportTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.l.Infof("Context canceled, stopping the surfacer write loop")
+			return
+		case em := <-s.writeChan:
+			s.record(em)
+			if s.numPoints >= int(s.c.GetMetricsBatchSize()) {
+				s.queueExport()
+				exportTicker.Reset(s.exportInterval())
+			}
+		case ts := <-exportTicker.C:
+			s.queueExport()
+			s.expireSeries(ts.Add(-staleSeriesIntervals * s.exportInterval()))
+		}
+	}
+}
+
+// exportLoop exports the queued batches, until the export channel is
+// closed.
+func (s *Surfacer) exportLoop() {
+	defer close(s.exportDone)
+
+	for rm := range s.exportChan {
+		ctx, cancel := context.WithTimeout(context.Background(), s.exportInterval())
+		s.exportBatch(ctx, rm)
+		cancel()
+	}
+}
+
+// Flush exports the EventMetrics remaining in the write channel, along with
+// the buffered data points, once the write loop has stopped, i.e. after the
+// context passed to New is canceled. It shuts down the exporter after that.
+func (s *Surfacer) Flush(ctx context.Context) error {
+	if err := flush.WaitForStop(ctx, s.loopDone); err != nil {
+		return err
+	}
+	if err := flush.WaitForStop(ctx, s.exportDone); err != nil {
+		return err
+	}
+
+	dropped := flush.Drain(ctx, s.writeChan, s.record)
+	if err := flush.DroppedError(ctx, dropped, "EventMetrics"); err != nil {
+		return err
+	}
+	if s.numPoints > 0 {
+		s.exportBatch(ctx, s.resourceMetrics())
+	}
+	return s.exporter.Shutdown(ctx)
+}
+
+// record adds the EventMetrics' metrics to the buffer.
+func (s *Surfacer) record(em *metrics.EventMetrics) {
+	var attrs []attribute.KeyValue
+	for _, k := range em.LabelsKeys() {
+		attrs = append(attrs, attribute.String(k, em.Label(k)))
+	}
+	// Map keys are added to a copy of the attributes, as attribute.NewSet
+	// sorts the given slice in place.
+	withKey := func(mapName, key string) attribute.Set {
+		return attribute.NewSet(append(attrs[:len(attrs):len(attrs)], attribute.String(mapName, key))...)
+	}
+
+	for _, name := range em.MetricsKeys() {
+		switch v := em.Metric(name).(type) {
+		case *metrics.Int:
+			s.addPoint(instrumentKey{name, em.Kind, intValue}, attribute.NewSet(attrs...), em.Timestamp, v, nil)
+		case metrics.NumValue:
+			s.addPoint(instrumentKey{name, em.Kind, floatValue}, attribute.NewSet(attrs...), em.Timestamp, v, nil)
+		case *metrics.Map[int64]:
+			for _, k := range v.Keys() {
+				s.addPoint(instrumentKey{name, em.Kind, intValue}, withKey(v.MapName, k), em.Timestamp, metrics.NewInt(v.GetKey(k)), nil)
+			}
+		case *metrics.Map[float64]:
+			for _, k := range v.Keys() {
+				s.addPoint(instrumentKey{name, em.Kind, floatValue}, withKey(v.MapName, k), em.Timestamp, metrics.NewFloat(v.GetKey(k)), nil)
+			}
+		case *metrics.Distribution:
+			s.addPoint(instrumentKey{name, em.Kind, distValue}, attribute.NewSet(attrs...), em.Timestamp, nil, v.CloneDist().Data())
+		}
+	}
+}
+
+// expireSeries forgets the series that have not been seen since the given
+// time.
+func (s *Surfacer) expireSeries(staleBefore time.Time) {
+	for key, st := range s.series {
+		if st.lastTS.Before(staleBefore) {
+			delete(s.series, key)
+		}
+	}
+}
+
+// startTime returns the start time for the series' data point. For the
+// cumulative metrics, it's the time the series was first seen, or was reset,
+// i.e. its value went down. For the gauges, it's the time of the previous
+// data point.
+func (s *Surfacer) startTime(key seriesKey, ts time.Time, value float64) time.Time {
+	st := s.series[key]
+	if st == nil {
+		st = &seriesState{startTime: ts, lastTS: ts}
+		s.series[key] = st
+	}
+	if key.kind == metrics.CUMULATIVE {
+		if value < st.lastValue {
+			st.startTime = ts
+		}
+	} else {
+		st.startTime = st.lastTS
+	}
+	st.lastTS, st.lastValue = ts, value
+	return st.startTime
+}
+
+func (s *Surfacer) addPoint(key instrumentKey, attrs attribute.Set, ts time.Time, num metrics.NumValue, dist *metrics.DistributionData) {
+	var value float64
+	if dist != nil {
+		value = float64(dist.Count)
+	} else {
+		value = num.Float64()
+	}
+
+	p := &point{
+		attrs:     attrs,
+		startTime: s.startTime(seriesKey{key, attrs.Equivalent()}, ts, value),
+		ts:        ts,
+		num:       num,
+		dist:      dist,
+	}
+
+	inst := s.instruments[key]
+	if inst == nil {
+		inst = &instrument{key: key, points: make(map[attribute.Distinct]*point)}
+		s.instruments[key] = inst
+		s.instrumentOrder = append(s.instrumentOrder, key)
+	}
+	if inst.points[attrs.Equivalent()] == nil {
+		inst.order = append(inst.order, attrs.Equivalent())
+		s.numPoints++
+	}
+	inst.points[attrs.Equivalent()] = p
+}
+
+// resourceMetrics returns the buffered data points as OpenTelemetry metrics,
+// and resets the buffer.
+func (s *Surfacer) resourceMetrics() *metricdata.ResourceMetrics {
+	var ms []metricdata.Metrics
+	for _, key := range s.instrumentOrder {
+		inst := s.instruments[key]
+		var points []*point
+		for _, k := range inst.order {
+			points = append(points, inst.points[k])
+		}
+		ms = append(ms, metricdata.Metrics{
+			Name: s.c.GetMetricsPrefix() + key.name,
+			Data: s.aggregation(key, points),
+		})
+	}
+
+	s.instruments = make(map[instrumentKey]*instrument)
+	s.instrumentOrder = nil
+	s.numPoints = 0
+
+	return &metricdata.ResourceMetrics{
+		Resource: s.resource,
+		ScopeMetrics: []metricdata.ScopeMetrics{
+			{
+				Scope:   instrumentation.Scope{Name: scopeName},
+				Metrics: ms,
+			},
+		},
+	}
+}
+
+func numAggregation[N int64 | float64](kind metrics.Kind, points []*point, value func(metrics.NumValue) N) metricdata.Aggregation {
+	var dps []metricdata.DataPoint[N]
+	for _, p := range points {
+		dp := metricdata.DataPoint[N]{Attributes: p.attrs, Time: p.ts, Value: value(p.num)}
+		if kind == metrics.CUMULATIVE {
+			dp.StartTime = p.startTime
+		}
+		dps = append(dps, dp)
+	}
+	if kind == metrics.CUMULATIVE {
+		return metricdata.Sum[N]{DataPoints: dps, Temporality: metricdata.CumulativeTemporality, IsMonotonic: true}
+	}
+	return metricdata.Gauge[N]{DataPoints: dps}
+}
+
+func (s *Surfacer) aggregation(key instrumentKey, points []*point) metricdata.Aggregation {
+	switch key.vType {
+	case intValue:
+		return numAggregation(key.kind, points, metrics.NumValue.Int64)
+	case floatValue:
+		return numAggregation(key.kind, points, metrics.NumValue.Float64)
+	}
+
+	// Gauge distributions have the samples observed since the last data
+	// point, as a delta histogram does.
+	temporality := metricdata.CumulativeTemporality
+	if key.kind == metrics.GAUGE {
+		temporality = metricdata.DeltaTemporality
+	}
+
+	if s.c.GetHistogramType() == configpb.SurfacerConf_EXPONENTIAL {
+		h := metricdata.ExponentialHistogram[float64]{Temporality: temporality}
+		for _, p := range points {
+			dp := expHistogramDataPoint(p.dist, s.c.GetExponentialHistogramScale())
+			dp.Attributes, dp.StartTime, dp.Time = p.attrs, p.startTime, p.ts
+			h.DataPoints = append(h.DataPoints, dp)
+		}
+		return h
+	}
+
+	h := metricdata.Histogram[float64]{Temporality: temporality}
+	for _, p := range points {
+		dp := histogramDataPoint(p.dist)
+		dp.Attributes, dp.StartTime, dp.Time = p.attrs, p.startTime, p.ts
+		h.DataPoints = append(h.DataPoints, dp)
+	}
+	return h
+}
+
+// histogramDataPoint converts the distribution to an explicit bucket
+// histogram data point. Distribution's buckets are defined by their lower
+// bounds, the first one being -Inf, while histogram's buckets are defined by
+// their upper bounds, the last one being +Inf.
+func histogramDataPoint(d *metrics.DistributionData) metricdata.HistogramDataPoint[float64] {
+	dp := metricdata.HistogramDataPoint[float64]{
+		Count: uint64(d.Count),
+		Sum:   d.Sum,
+	}
+	if len(d.LowerBounds) > 0 {
+		dp.Bounds = append([]float64{}, d.LowerBounds[1:]...)
+	}
+	for _, c := range d.BucketCounts {
+		dp.BucketCounts = append(dp.BucketCounts, uint64(c))
+	}
+	return dp
+}
+
+// bucketValue returns the value that represents the samples in the bucket
+// i: the middle of the bucket, or its finite bound for the first and the last
+// buckets.
+func bucketValue(lowerBounds []float64, i int) float64 {
+	if math.IsInf(lowerBounds[i], -1) {
+		if i == len(lowerBounds)-1 {
+			return 0
+		}
+		return lowerBounds[i+1]
+	}
+	if i == len(lowerBounds)-1 {
+		return lowerBounds[i]
+	}
+	return (lowerBounds[i] + lowerBounds[i+1]) / 2
+}
+
+// expBucketIndex returns the index of the exponential histogram bucket that
+// the positive value v belongs to. Bucket i holds the values in
+// (base^i, base^(i+1)], where base is 2^(2^-scale).
+func expBucketIndex(v float64, scale int32) int32 {
+	return int32(math.Ceil(math.Log2(v)*math.Exp2(float64(scale)))) - 1
+}
+
+// expIndexRange returns the lowest and the highest bucket indices for the
+// positive values, at the given scale. Values shouldn't be empty.
+func expIndexRange(values []float64, scale int32) (minIdx, maxIdx int32) {
+	minIdx, maxIdx = math.MaxInt32, math.MinInt32
+	for _, v := range values {
+		idx := expBucketIndex(v, scale)
+		minIdx, maxIdx = min(minIdx, idx), max(maxIdx, idx)
+	}
+	return minIdx, maxIdx
+}
+
+// numExpBuckets returns the number of buckets needed for the values, at the
+// given scale.
+func numExpBuckets(values []float64, scale int32) int32 {
+	if len(values) == 0 {
+		return 0
+	}
+	minIdx, maxIdx := expIndexRange(values, scale)
+	return maxIdx - minIdx + 1
+}
+
+// expBuckets returns the exponential buckets for the values and their
+// counts.
+func expBuckets(values []float64, counts []int64, scale int32) metricdata.ExponentialBucket {
+	var b metricdata.ExponentialBucket
+	if len(values) == 0 {
+		return b
+	}
+	minIdx, maxIdx := expIndexRange(values, scale)
+	b.Offset = minIdx
+	b.Counts = make([]uint64, maxIdx-minIdx+1)
+	for i, v := range values {
+		b.Counts[expBucketIndex(v, scale)-minIdx] += uint64(counts[i])
+	}
+	return b
+}
+
+// expHistogramDataPoint converts the distribution to an exponential
+// histogram data point. Samples in every distribution bucket are assumed to
+// have the bucket's representative value (see bucketValue). Scale is reduced
+// if needed, to keep the number of buckets within maxExpBuckets.
+func expHistogramDataPoint(d *metrics.DistributionData, scale int32) metricdata.ExponentialHistogramDataPoint[float64] {
+	dp := metricdata.ExponentialHistogramDataPoint[float64]{
+		Count: uint64(d.Count),
+		Sum:   d.Sum,
+	}
+
+	var posValues, negValues []float64
+	var posCounts, negCounts []int64
+	for i, c := range d.BucketCounts {
+		if c == 0 {
+			continue
+		}
+		v := bucketValue(d.LowerBounds, i)
+		switch {
+		case v > 0:
+			posValues, posCounts = append(posValues, v), append(posCounts, c)
+		case v < 0:
+			negValues, negCounts = append(negValues, -v), append(negCounts, c)
+		default:
+			dp.ZeroCount += uint64(c)
+		}
+	}
+
+	for scale > -10 && (numExpBuckets(posValues, scale) > maxExpBuckets || numExpBuckets(negValues, scale) > maxExpBuckets) {
+		scale--
+	}
+
+	dp.Scale = scale
+	dp.PositiveBucket = expBuckets(posValues, posCounts, scale)
+	dp.NegativeBucket = expBuckets(negValues, negCounts, scale)
+	return dp
+}
+
+// queueExport queues the buffered data points for the export, and resets the
+// buffer. Data points are dropped if the export queue is full.
+func (s *Surfacer) queueExport() {
+	if s.numPoints == 0 {
+		return
+	}
+	numPoints := s.numPoints
+	select {
+	case s.exportChan <- s.resourceMetrics():
+	default:
+		s.l.Errorf("Export queue is full, dropping %d data points", numPoints)
+	}
+}
+
+// exportBatch exports a batch of data points.
+func (s *Surfacer) exportBatch(ctx context.Context, rm *metricdata.ResourceMetrics) {
+	if err := s.exporter.Export(ctx, rm); err != nil {
+		s.l.Errorf("Failed to export metrics: %v", err)
+	}
+}
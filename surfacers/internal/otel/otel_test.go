@@ -0,0 +1,356 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otel
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	configpb "github.com/cloudprober/cloudprober/surfacers/internal/otel/proto"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/protobuf/proto"
+)
+
+type testExporter struct {
+	sdkmetric.Exporter
+
+	mu  sync.Mutex
+	rms []*metricdata.ResourceMetrics
+}
+
+func (te *testExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	te.rms = append(te.rms, rm)
+	return nil
+}
+
+func (te *testExporter) Shutdown(context.Context) error { return nil }
+
+func (te *testExporter) exported() []*metricdata.ResourceMetrics {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	return append([]*metricdata.ResourceMetrics{}, te.rms...)
+}
+
+func testSurfacer(c *configpb.SurfacerConf) (*Surfacer, *testExporter) {
+	te := &testExporter{}
+	res, _ := newResource(c)
+	return &Surfacer{
+		c:           c,
+		exporter:    te,
+		resource:    res,
+		writeChan:   make(chan *metrics.EventMetrics, 10),
+		loopDone:    make(chan struct{}),
+		exportChan:  make(chan *metricdata.ResourceMetrics, exportQueueSize),
+		exportDone:  make(chan struct{}),
+		instruments: make(map[instrumentKey]*instrument),
+		series:      make(map[seriesKey]*seriesState),
+	}, te
+}
+
+func testEM(ts time.Time, kind metrics.Kind, total int64, latency float64) *metrics.EventMetrics {
+	em := metrics.NewEventMetrics(ts).
+		AddMetric("total", metrics.NewInt(total)).
+		AddMetric("latency", metrics.NewFloat(latency)).
+		AddLabel("probe", "p1").
+		AddLabel("dst", "t1")
+	em.Kind = kind
+	return em
+}
+
+func metricByName(rm *metricdata.ResourceMetrics, name string) *metricdata.Metrics {
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name == name {
+			return &m
+		}
+	}
+	return nil
+}
+
+func TestRecordNumbers(t *testing.T) {
+	s, _ := testSurfacer(&configpb.SurfacerConf{MetricsPrefix: proto.String("cp_")})
+	ts := time.Now().Truncate(time.Second)
+	wantAttrs := attribute.NewSet(attribute.String("dst", "t1"), attribute.String("probe", "p1"))
+
+	s.record(testEM(ts, metrics.CUMULATIVE, 10, 25.5))
+	rm := s.resourceMetrics()
+	assert.Len(t, rm.ScopeMetrics[0].Metrics, 2)
+	assert.Equal(t, scopeName, rm.ScopeMetrics[0].Scope.Name)
+
+	sum, ok := metricByName(rm, "cp_total").Data.(metricdata.Sum[int64])
+	assert.True(t, ok, "total should be an int64 sum")
+	assert.True(t, sum.IsMonotonic)
+	assert.Equal(t, metricdata.CumulativeTemporality, sum.Temporality)
+	assert.Equal(t, []metricdata.DataPoint[int64]{{Attributes: wantAttrs, StartTime: ts, Time: ts, Value: 10}}, sum.DataPoints)
+
+	fsum, ok := metricByName(rm, "cp_latency").Data.(metricdata.Sum[float64])
+	assert.True(t, ok, "latency should be a float64 sum")
+	assert.Equal(t, 25.5, fsum.DataPoints[0].Value)
+
+	// Buffer is reset after every export.
+	assert.Len(t, s.resourceMetrics().ScopeMetrics[0].Metrics, 0)
+
+	// Start time stays the same as long as the counters don't go down.
+	s.record(testEM(ts.Add(10*time.Second), metrics.CUMULATIVE, 12, 30))
+	sum = metricByName(s.resourceMetrics(), "cp_total").Data.(metricdata.Sum[int64])
+	assert.Equal(t, ts, sum.DataPoints[0].StartTime)
+
+	// Counter reset.
+	s.record(testEM(ts.Add(20*time.Second), metrics.CUMULATIVE, 2, 30))
+	sum = metricByName(s.resourceMetrics(), "cp_total").Data.(metricdata.Sum[int64])
+	assert.Equal(t, ts.Add(20*time.Second), sum.DataPoints[0].StartTime)
+
+	// Only the latest data point of a series is exported.
+	s.record(testEM(ts.Add(30*time.Second), metrics.CUMULATIVE, 5, 30))
+	s.record(testEM(ts.Add(40*time.Second), metrics.CUMULATIVE, 7, 30))
+	assert.Equal(t, 2, s.numPoints)
+	sum = metricByName(s.resourceMetrics(), "cp_total").Data.(metricdata.Sum[int64])
+	assert.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(7), sum.DataPoints[0].Value)
+
+	// Gauges.
+	s.record(testEM(ts, metrics.GAUGE, 3, 1.5))
+	rm = s.resourceMetrics()
+	gauge, ok := metricByName(rm, "cp_total").Data.(metricdata.Gauge[int64])
+	assert.True(t, ok, "gauge total should be an int64 gauge")
+	assert.Equal(t, []metricdata.DataPoint[int64]{{Attributes: wantAttrs, Time: ts, Value: 3}}, gauge.DataPoints)
+}
+
+func TestRecordMaps(t *testing.T) {
+	s, _ := testSurfacer(&configpb.SurfacerConf{})
+	ts := time.Now()
+
+	m := metrics.NewMap("code")
+	m.IncKeyBy("200", 5)
+	m.IncKeyBy("500", 2)
+	fm := metrics.NewMapFloat("op")
+	fm.IncKeyBy("read", 1.5)
+
+	em := metrics.NewEventMetrics(ts).
+		AddMetric("resp_code", m).
+		AddMetric("op_latency", fm).
+		AddLabel("probe", "p1")
+	s.record(em)
+	rm := s.resourceMetrics()
+
+	sum := metricByName(rm, "resp_code").Data.(metricdata.Sum[int64])
+	assert.Equal(t, []metricdata.DataPoint[int64]{
+		{Attributes: attribute.NewSet(attribute.String("probe", "p1"), attribute.String("code", "200")), StartTime: ts, Time: ts, Value: 5},
+		{Attributes: attribute.NewSet(attribute.String("probe", "p1"), attribute.String("code", "500")), StartTime: ts, Time: ts, Value: 2},
+	}, sum.DataPoints)
+
+	fsum := metricByName(rm, "op_latency").Data.(metricdata.Sum[float64])
+	assert.Equal(t, attribute.NewSet(attribute.String("probe", "p1"), attribute.String("op", "read")), fsum.DataPoints[0].Attributes)
+	assert.Equal(t, 1.5, fsum.DataPoints[0].Value)
+}
+
+func testDist() *metrics.Distribution {
+	d := metrics.NewDistribution([]float64{1, 2, 4, 8})
+	for _, v := range []float64{0.5, 1.5, 3, 3, 5, 20} {
+		d.AddSample(v)
+	}
+	return d
+}
+
+func TestRecordDistribution(t *testing.T) {
+	ts := time.Now()
+	em := metrics.NewEventMetrics(ts).
+		AddMetric("latency", testDist()).
+		AddLabel("probe", "p1")
+
+	t.Run("explicit", func(t *testing.T) {
+		s, _ := testSurfacer(&configpb.SurfacerConf{})
+		s.record(em)
+		h, ok := metricByName(s.resourceMetrics(), "latency").Data.(metricdata.Histogram[float64])
+		assert.True(t, ok, "latency should be a histogram")
+		assert.Equal(t, metricdata.CumulativeTemporality, h.Temporality)
+		dp := h.DataPoints[0]
+		assert.Equal(t, uint64(6), dp.Count)
+		assert.Equal(t, 33.0, dp.Sum)
+		assert.Equal(t, []float64{1, 2, 4, 8}, dp.Bounds)
+		assert.Equal(t, []uint64{1, 1, 2, 1, 1}, dp.BucketCounts)
+	})
+
+	t.Run("exponential", func(t *testing.T) {
+		s, _ := testSurfacer(&configpb.SurfacerConf{
+			HistogramType:             configpb.SurfacerConf_EXPONENTIAL.Enum(),
+			ExponentialHistogramScale: proto.Int32(0),
+		})
+		s.record(em)
+		h, ok := metricByName(s.resourceMetrics(), "latency").Data.(metricdata.ExponentialHistogram[float64])
+		assert.True(t, ok, "latency should be an exponential histogram")
+		dp := h.DataPoints[0]
+		assert.Equal(t, uint64(6), dp.Count)
+		assert.Equal(t, 33.0, dp.Sum)
+		assert.Equal(t, int32(0), dp.Scale)
+		// Bucket values: 1 (-Inf,1), 1.5 [1,2), 3 [2,4), 6 [4,8), 8 [8,Inf).
+		// At scale 0, bucket i is (2^i, 2^(i+1)], so 6 and 8 share a bucket.
+		assert.Equal(t, metricdata.ExponentialBucket{Offset: -1, Counts: []uint64{1, 1, 2, 2}}, dp.PositiveBucket)
+	})
+
+	t.Run("gauge", func(t *testing.T) {
+		s, _ := testSurfacer(&configpb.SurfacerConf{})
+		gem := em.Clone()
+		gem.Kind = metrics.GAUGE
+		s.record(gem)
+		h := metricByName(s.resourceMetrics(), "latency").Data.(metricdata.Histogram[float64])
+		assert.Equal(t, metricdata.DeltaTemporality, h.Temporality)
+	})
+}
+
+func TestExpHistogramDataPoint(t *testing.T) {
+	d := metrics.NewDistribution([]float64{-2, 0, 1000})
+	d.AddSample(-5)
+	d.AddSample(-1)
+	d.AddSample(500)
+	d.AddSample(5000)
+
+	dp := expHistogramDataPoint(d.Data(), 0)
+	assert.Equal(t, uint64(4), dp.Count)
+	// Bucket values: -2 (-Inf,-2), -1 [-2, 0), 500 [0,1000), 1000 [1000,Inf).
+	assert.Equal(t, metricdata.ExponentialBucket{Offset: -1, Counts: []uint64{1, 1}}, dp.NegativeBucket)
+	assert.Equal(t, int32(8), dp.PositiveBucket.Offset)
+	assert.Equal(t, uint64(2), dp.PositiveBucket.Counts[0]+dp.PositiveBucket.Counts[len(dp.PositiveBucket.Counts)-1])
+
+	// Zero bucket value.
+	d = metrics.NewDistribution([]float64{0})
+	d.AddSample(-1)
+	dp = expHistogramDataPoint(d.Data(), 0)
+	assert.Equal(t, uint64(1), dp.ZeroCount)
+
+	// Scale is reduced to fit the values in maxExpBuckets.
+	d = metrics.NewDistribution([]float64{0.001, 1e6})
+	d.AddSample(0.0001)
+	d.AddSample(1e7)
+	dp = expHistogramDataPoint(d.Data(), 20)
+	assert.Less(t, dp.Scale, int32(20))
+	assert.LessOrEqual(t, len(dp.PositiveBucket.Counts), maxExpBuckets)
+	assert.Equal(t, uint64(2), dp.PositiveBucket.Counts[0]+dp.PositiveBucket.Counts[len(dp.PositiveBucket.Counts)-1])
+	assert.Equal(t, int32(2), dp.Scale, "largest scale that fits")
+	assert.Greater(t, numExpBuckets([]float64{0.001, 1e6}, dp.Scale+1), int32(maxExpBuckets))
+}
+
+func TestExpBucketIndex(t *testing.T) {
+	for _, test := range []struct {
+		v     float64
+		scale int32
+		want  int32
+	}{
+		{v: 1, scale: 0, want: -1},
+		{v: 1.5, scale: 0, want: 0},
+		{v: 2, scale: 0, want: 0},
+		{v: 2.1, scale: 0, want: 1},
+		{v: 0.5, scale: 0, want: -2},
+		{v: 2, scale: 1, want: 1},
+		{v: 1.5, scale: 1, want: 1},
+		{v: 16, scale: -1, want: 1},
+		{v: 17, scale: -1, want: 2},
+	} {
+		assert.Equal(t, test.want, expBucketIndex(test.v, test.scale), "value: %v, scale: %d", test.v, test.scale)
+	}
+}
+
+func TestWriteLoop(t *testing.T) {
+	s, te := testSurfacer(&configpb.SurfacerConf{
+		ExportIntervalSec: proto.Int32(3600),
+		MetricsBatchSize:  proto.Int32(4),
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.exportLoop()
+	go s.writeLoop(ctx)
+
+	ts := time.Now()
+	// Every EventMetrics has 2 data points. Batch size is reached with the
+	// second EventMetrics, as they are for different targets.
+	s.Write(ctx, testEM(ts, metrics.CUMULATIVE, 1, 1))
+	em := metrics.NewEventMetrics(ts).
+		AddMetric("total", metrics.NewInt(1)).
+		AddMetric("latency", metrics.NewFloat(1)).
+		AddLabel("probe", "p1").
+		AddLabel("dst", "t2")
+	s.Write(ctx, em)
+
+	assert.Eventually(t, func() bool { return len(te.exported()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	s.Write(ctx, testEM(ts.Add(time.Second), metrics.CUMULATIVE, 2, 1))
+	cancel()
+	assert.NoError(t, s.Flush(context.Background()))
+
+	rms := te.exported()
+	assert.Len(t, rms, 2)
+	assert.Len(t, metricByName(rms[0], "total").Data.(metricdata.Sum[int64]).DataPoints, 2)
+	assert.Equal(t, int64(2), metricByName(rms[1], "total").Data.(metricdata.Sum[int64]).DataPoints[0].Value)
+}
+
+func TestNewErrors(t *testing.T) {
+	for _, c := range []*configpb.SurfacerConf{
+		{ExportIntervalSec: proto.Int32(0)},
+		{ExponentialHistogramScale: proto.Int32(21)},
+		{Exporter: &configpb.SurfacerConf_OtlpHttpExporter{OtlpHttpExporter: &configpb.HTTPExporter{EndpointUrl: proto.String("otel-collector:4318")}}},
+	} {
+		_, err := New(context.Background(), c, nil, nil)
+		assert.Error(t, err, "config: %v", c)
+	}
+}
+
+func TestExpireSeries(t *testing.T) {
+	s, _ := testSurfacer(&configpb.SurfacerConf{})
+	ts := time.Now()
+
+	s.record(testEM(ts, metrics.CUMULATIVE, 1, 1))
+	em := metrics.NewEventMetrics(ts.Add(time.Minute)).
+		AddMetric("total", metrics.NewInt(1)).
+		AddLabel("probe", "p1").
+		AddLabel("dst", "t2")
+	s.record(em)
+	assert.Len(t, s.series, 3)
+
+	// t1's series (total and latency) were last seen before the cutoff.
+	s.expireSeries(ts.Add(30 * time.Second))
+	assert.Len(t, s.series, 1)
+
+	// Expired series start afresh.
+	s.record(testEM(ts.Add(2*time.Minute), metrics.CUMULATIVE, 5, 1))
+	sum := metricByName(s.resourceMetrics(), "total").Data.(metricdata.Sum[int64])
+	for _, dp := range sum.DataPoints {
+		if v, _ := dp.Attributes.Value("dst"); v.AsString() == "t1" {
+			assert.Equal(t, ts.Add(2*time.Minute), dp.StartTime)
+		}
+	}
+}
+
+func TestExportQueueFull(t *testing.T) {
+	s, te := testSurfacer(&configpb.SurfacerConf{})
+	ts := time.Now()
+
+	// Export loop is not running, so the queue fills up.
+	for i := 0; i < exportQueueSize+2; i++ {
+		s.record(testEM(ts.Add(time.Duration(i)*time.Second), metrics.CUMULATIVE, int64(i), 1))
+		s.queueExport()
+	}
+	assert.Len(t, s.exportChan, exportQueueSize)
+	assert.Equal(t, 0, s.numPoints, "buffer should be reset even if dropped")
+
+	close(s.exportChan)
+	s.exportLoop()
+	assert.Len(t, te.exported(), exportQueueSize)
+}
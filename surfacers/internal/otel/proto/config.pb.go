@@ -0,0 +1,877 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.21.5
+// source: github.com/cloudprober/cloudprober/surfacers/internal/otel/proto/config.proto
+
+package proto
+
+import (
+	proto "github.com/cloudprober/cloudprober/internal/tlsconfig/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Compression int32
+
+const (
+	Compression_NONE Compression = 0
+	Compression_GZIP Compression = 1
+)
+
+// Enum value maps for Compression.
+var (
+	Compression_name = map[int32]string{
+		0: "NONE",
+		1: "GZIP",
+	}
+	Compression_value = map[string]int32{
+		"NONE": 0,
+		"GZIP": 1,
+	}
+)
+
+func (x Compression) Enum() *Compression {
+	p := new(Compression)
+	*p = x
+	return p
+}
+
+func (x Compression) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Compression) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_enumTypes[0].Descriptor()
+}
+
+func (Compression) Type() protoreflect.EnumType {
+	return &file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_enumTypes[0]
+}
+
+func (x Compression) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *Compression) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = Compression(num)
+	return nil
+}
+
+// Deprecated: Use Compression.Descriptor instead.
+func (Compression) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+type SurfacerConf_HistogramType int32
+
+const (
+	SurfacerConf_EXPLICIT    SurfacerConf_HistogramType = 0
+	SurfacerConf_EXPONENTIAL SurfacerConf_HistogramType = 1
+)
+
+// Enum value maps for SurfacerConf_HistogramType.
+var (
+	SurfacerConf_HistogramType_name = map[int32]string{
+		0: "EXPLICIT",
+		1: "EXPONENTIAL",
+	}
+	SurfacerConf_HistogramType_value = map[string]int32{
+		"EXPLICIT":    0,
+		"EXPONENTIAL": 1,
+	}
+)
+
+func (x SurfacerConf_HistogramType) Enum() *SurfacerConf_HistogramType {
+	p := new(SurfacerConf_HistogramType)
+	*p = x
+	return p
+}
+
+func (x SurfacerConf_HistogramType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SurfacerConf_HistogramType) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_enumTypes[1].Descriptor()
+}
+
+func (SurfacerConf_HistogramType) Type() protoreflect.EnumType {
+	return &file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_enumTypes[1]
+}
+
+func (x SurfacerConf_HistogramType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *SurfacerConf_HistogramType) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = SurfacerConf_HistogramType(num)
+	return nil
+}
+
+// Deprecated: Use SurfacerConf_HistogramType.Descriptor instead.
+func (SurfacerConf_HistogramType) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDescGZIP(), []int{4, 0}
+}
+
+// OTLP/HTTP exporter config.
+type HTTPExporter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Metrics endpoint URL, e.g. https://otel-collector:4318/v1/metrics. If
+	// not set, OTEL_EXPORTER_OTLP_METRICS_ENDPOINT or
+	// OTEL_EXPORTER_OTLP_ENDPOINT environment variables are used, and if they
+	// are not set either, http://localhost:4318/v1/metrics.
+	EndpointUrl *string `protobuf:"bytes,1,opt,name=endpoint_url,json=endpointUrl" json:"endpoint_url,omitempty"`
+	// TLS config for the https endpoints.
+	TlsConfig *proto.TLSConfig `protobuf:"bytes,2,opt,name=tls_config,json=tlsConfig" json:"tls_config,omitempty"`
+	// HTTP headers to add to the export requests, e.g. for authentication.
+	HttpHeader  map[string]string `protobuf:"bytes,3,rep,name=http_header,json=httpHeader" json:"http_header,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Compression *Compression      `protobuf:"varint,4,opt,name=compression,enum=cloudprober.surfacer.otel.Compression" json:"compression,omitempty"`
+}
+
+func (x *HTTPExporter) Reset() {
+	*x = HTTPExporter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HTTPExporter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HTTPExporter) ProtoMessage() {}
+
+func (x *HTTPExporter) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HTTPExporter.ProtoReflect.Descriptor instead.
+func (*HTTPExporter) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HTTPExporter) GetEndpointUrl() string {
+	if x != nil && x.EndpointUrl != nil {
+		return *x.EndpointUrl
+	}
+	return ""
+}
+
+func (x *HTTPExporter) GetTlsConfig() *proto.TLSConfig {
+	if x != nil {
+		return x.TlsConfig
+	}
+	return nil
+}
+
+func (x *HTTPExporter) GetHttpHeader() map[string]string {
+	if x != nil {
+		return x.HttpHeader
+	}
+	return nil
+}
+
+func (x *HTTPExporter) GetCompression() Compression {
+	if x != nil && x.Compression != nil {
+		return *x.Compression
+	}
+	return Compression_NONE
+}
+
+// OTLP/gRPC exporter config.
+type GRPCExporter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Collector's address, e.g. otel-collector:4317. If not set,
+	// OTEL_EXPORTER_OTLP_METRICS_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT
+	// environment variables are used, and if they are not set either,
+	// localhost:4317.
+	Endpoint *string `protobuf:"bytes,1,opt,name=endpoint" json:"endpoint,omitempty"`
+	// TLS config. It's ignored if insecure is set.
+	TlsConfig *proto.TLSConfig `protobuf:"bytes,2,opt,name=tls_config,json=tlsConfig" json:"tls_config,omitempty"`
+	// gRPC metadata (headers) to add to the export requests.
+	HttpHeader  map[string]string `protobuf:"bytes,3,rep,name=http_header,json=httpHeader" json:"http_header,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Compression *Compression      `protobuf:"varint,4,opt,name=compression,enum=cloudprober.surfacer.otel.Compression" json:"compression,omitempty"`
+	// Use an insecure (plaintext) connection to the collector.
+	Insecure *bool `protobuf:"varint,5,opt,name=insecure" json:"insecure,omitempty"`
+}
+
+func (x *GRPCExporter) Reset() {
+	*x = GRPCExporter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GRPCExporter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GRPCExporter) ProtoMessage() {}
+
+func (x *GRPCExporter) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GRPCExporter.ProtoReflect.Descriptor instead.
+func (*GRPCExporter) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GRPCExporter) GetEndpoint() string {
+	if x != nil && x.Endpoint != nil {
+		return *x.Endpoint
+	}
+	return ""
+}
+
+func (x *GRPCExporter) GetTlsConfig() *proto.TLSConfig {
+	if x != nil {
+		return x.TlsConfig
+	}
+	return nil
+}
+
+func (x *GRPCExporter) GetHttpHeader() map[string]string {
+	if x != nil {
+		return x.HttpHeader
+	}
+	return nil
+}
+
+func (x *GRPCExporter) GetCompression() Compression {
+	if x != nil && x.Compression != nil {
+		return *x.Compression
+	}
+	return Compression_NONE
+}
+
+func (x *GRPCExporter) GetInsecure() bool {
+	if x != nil && x.Insecure != nil {
+		return *x.Insecure
+	}
+	return false
+}
+
+// Retry config for the failed exports. Retries use exponential backoff,
+// and are attempted only for the retryable errors.
+type RetryConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Disabled *bool `protobuf:"varint,1,opt,name=disabled" json:"disabled,omitempty"`
+	// Time to wait after the first failure before retrying.
+	InitialIntervalMsec *int32 `protobuf:"varint,2,opt,name=initial_interval_msec,json=initialIntervalMsec,def=5000" json:"initial_interval_msec,omitempty"`
+	// Maximum time to wait between the retries.
+	MaxIntervalMsec *int32 `protobuf:"varint,3,opt,name=max_interval_msec,json=maxIntervalMsec,def=30000" json:"max_interval_msec,omitempty"`
+	// Maximum time spent trying to export a batch, after which the batch is
+	// dropped.
+	MaxElapsedTimeSec *int32 `protobuf:"varint,4,opt,name=max_elapsed_time_sec,json=maxElapsedTimeSec,def=60" json:"max_elapsed_time_sec,omitempty"`
+}
+
+// Default values for RetryConfig fields.
+const (
+	Default_RetryConfig_InitialIntervalMsec = int32(5000)
+	Default_RetryConfig_MaxIntervalMsec     = int32(30000)
+	Default_RetryConfig_MaxElapsedTimeSec   = int32(60)
+)
+
+func (x *RetryConfig) Reset() {
+	*x = RetryConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RetryConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryConfig) ProtoMessage() {}
+
+func (x *RetryConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryConfig.ProtoReflect.Descriptor instead.
+func (*RetryConfig) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RetryConfig) GetDisabled() bool {
+	if x != nil && x.Disabled != nil {
+		return *x.Disabled
+	}
+	return false
+}
+
+func (x *RetryConfig) GetInitialIntervalMsec() int32 {
+	if x != nil && x.InitialIntervalMsec != nil {
+		return *x.InitialIntervalMsec
+	}
+	return Default_RetryConfig_InitialIntervalMsec
+}
+
+func (x *RetryConfig) GetMaxIntervalMsec() int32 {
+	if x != nil && x.MaxIntervalMsec != nil {
+		return *x.MaxIntervalMsec
+	}
+	return Default_RetryConfig_MaxIntervalMsec
+}
+
+func (x *RetryConfig) GetMaxElapsedTimeSec() int32 {
+	if x != nil && x.MaxElapsedTimeSec != nil {
+		return *x.MaxElapsedTimeSec
+	}
+	return Default_RetryConfig_MaxElapsedTimeSec
+}
+
+type Attribute struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key   *string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Value *string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (x *Attribute) Reset() {
+	*x = Attribute{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Attribute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attribute) ProtoMessage() {}
+
+func (x *Attribute) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attribute.ProtoReflect.Descriptor instead.
+func (*Attribute) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Attribute) GetKey() string {
+	if x != nil && x.Key != nil {
+		return *x.Key
+	}
+	return ""
+}
+
+func (x *Attribute) GetValue() string {
+	if x != nil && x.Value != nil {
+		return *x.Value
+	}
+	return ""
+}
+
+// Surfacer config for the OpenTelemetry (OTLP) surfacer.
+//
+// EventMetrics are mapped to the OpenTelemetry metrics in the following way:
+//   - EventMetrics labels become the data point attributes.
+//   - Cumulative numeric metrics become monotonic sums, with cumulative
+//     temporality, and gauge metrics become gauges.
+//   - Map metrics become the data points with the map's name as an
+//     additional attribute, e.g. resp-code map becomes data points with a
+//     "code" attribute.
+//   - Distributions become explicit bucket histograms, or exponential
+//     histograms if histogram_type is EXPONENTIAL.
+//   - String metrics are not exported.
+type SurfacerConf struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Exporter:
+	//
+	//	*SurfacerConf_OtlpHttpExporter
+	//	*SurfacerConf_OtlpGrpcExporter
+	Exporter isSurfacerConf_Exporter `protobuf_oneof:"exporter"`
+	// How often to export metrics.
+	ExportIntervalSec *int32 `protobuf:"varint,3,opt,name=export_interval_sec,json=exportIntervalSec,def=10" json:"export_interval_sec,omitempty"`
+	// Metrics are exported early, before the export interval, if the number of
+	// buffered data points reaches this size. For every metric and attribute
+	// set, only the latest data point is exported.
+	MetricsBatchSize *int32 `protobuf:"varint,4,opt,name=metrics_batch_size,json=metricsBatchSize,def=1000" json:"metrics_batch_size,omitempty"`
+	// Prefix to add to all metric names.
+	MetricsPrefix *string `protobuf:"bytes,5,opt,name=metrics_prefix,json=metricsPrefix" json:"metrics_prefix,omitempty"`
+	// Additional resource attributes. By default, resource has the
+	// service.name attribute set to "cloudprober", and the attributes from
+	// the OTEL_RESOURCE_ATTRIBUTES environment variable.
+	ResourceAttribute []*Attribute `protobuf:"bytes,6,rep,name=resource_attribute,json=resourceAttribute" json:"resource_attribute,omitempty"`
+	Retry             *RetryConfig `protobuf:"bytes,7,opt,name=retry" json:"retry,omitempty"`
+	// How to export distributions. Explicit bucket histograms use the
+	// distribution's buckets as they are. Exponential histograms group the
+	// distribution's samples into the exponential buckets, using the value
+	// that represents each distribution bucket, i.e. bucket's middle point.
+	HistogramType *SurfacerConf_HistogramType `protobuf:"varint,8,opt,name=histogram_type,json=histogramType,enum=cloudprober.surfacer.otel.SurfacerConf_HistogramType" json:"histogram_type,omitempty"`
+	// Scale of the exponential histograms. Scale determines the resolution:
+	// bucket boundaries are the powers of 2^(2^-scale). For example, scale 2
+	// gives about 19% wide buckets. It's used only for the EXPONENTIAL
+	// histograms, and should be between -10 and 20.
+	ExponentialHistogramScale *int32 `protobuf:"varint,9,opt,name=exponential_histogram_scale,json=exponentialHistogramScale,def=2" json:"exponential_histogram_scale,omitempty"`
+}
+
+// Default values for SurfacerConf fields.
+const (
+	Default_SurfacerConf_ExportIntervalSec         = int32(10)
+	Default_SurfacerConf_MetricsBatchSize          = int32(1000)
+	Default_SurfacerConf_ExponentialHistogramScale = int32(2)
+)
+
+func (x *SurfacerConf) Reset() {
+	*x = SurfacerConf{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SurfacerConf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SurfacerConf) ProtoMessage() {}
+
+func (x *SurfacerConf) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SurfacerConf.ProtoReflect.Descriptor instead.
+func (*SurfacerConf) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDescGZIP(), []int{4}
+}
+
+func (m *SurfacerConf) GetExporter() isSurfacerConf_Exporter {
+	if m != nil {
+		return m.Exporter
+	}
+	return nil
+}
+
+func (x *SurfacerConf) GetOtlpHttpExporter() *HTTPExporter {
+	if x, ok := x.GetExporter().(*SurfacerConf_OtlpHttpExporter); ok {
+		return x.OtlpHttpExporter
+	}
+	return nil
+}
+
+func (x *SurfacerConf) GetOtlpGrpcExporter() *GRPCExporter {
+	if x, ok := x.GetExporter().(*SurfacerConf_OtlpGrpcExporter); ok {
+		return x.OtlpGrpcExporter
+	}
+	return nil
+}
+
+func (x *SurfacerConf) GetExportIntervalSec() int32 {
+	if x != nil && x.ExportIntervalSec != nil {
+		return *x.ExportIntervalSec
+	}
+	return Default_SurfacerConf_ExportIntervalSec
+}
+
+func (x *SurfacerConf) GetMetricsBatchSize() int32 {
+	if x != nil && x.MetricsBatchSize != nil {
+		return *x.MetricsBatchSize
+	}
+	return Default_SurfacerConf_MetricsBatchSize
+}
+
+func (x *SurfacerConf) GetMetricsPrefix() string {
+	if x != nil && x.MetricsPrefix != nil {
+		return *x.MetricsPrefix
+	}
+	return ""
+}
+
+func (x *SurfacerConf) GetResourceAttribute() []*Attribute {
+	if x != nil {
+		return x.ResourceAttribute
+	}
+	return nil
+}
+
+func (x *SurfacerConf) GetRetry() *RetryConfig {
+	if x != nil {
+		return x.Retry
+	}
+	return nil
+}
+
+func (x *SurfacerConf) GetHistogramType() SurfacerConf_HistogramType {
+	if x != nil && x.HistogramType != nil {
+		return *x.HistogramType
+	}
+	return SurfacerConf_EXPLICIT
+}
+
+func (x *SurfacerConf) GetExponentialHistogramScale() int32 {
+	if x != nil && x.ExponentialHistogramScale != nil {
+		return *x.ExponentialHistogramScale
+	}
+	return Default_SurfacerConf_ExponentialHistogramScale
+}
+
+type isSurfacerConf_Exporter interface {
+	isSurfacerConf_Exporter()
+}
+
+type SurfacerConf_OtlpHttpExporter struct {
+	OtlpHttpExporter *HTTPExporter `protobuf:"bytes,1,opt,name=otlp_http_exporter,json=otlpHttpExporter,oneof"`
+}
+
+type SurfacerConf_OtlpGrpcExporter struct {
+	OtlpGrpcExporter *GRPCExporter `protobuf:"bytes,2,opt,name=otlp_grpc_exporter,json=otlpGrpcExporter,oneof"`
+}
+
+func (*SurfacerConf_OtlpHttpExporter) isSurfacerConf_Exporter() {}
+
+func (*SurfacerConf_OtlpGrpcExporter) isSurfacerConf_Exporter() {}
+
+var File_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto protoreflect.FileDescriptor
+
+var file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDesc = []byte{
+	0x0a, 0x4d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x73, 0x2f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x6f, 0x74, 0x65, 0x6c, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x19, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x73, 0x75, 0x72,
+	0x66, 0x61, 0x63, 0x65, 0x72, 0x2e, 0x6f, 0x74, 0x65, 0x6c, 0x1a, 0x48, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62,
+	0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x74, 0x6c, 0x73, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd5, 0x02, 0x0a, 0x0c, 0x48, 0x54, 0x54, 0x50, 0x45, 0x78, 0x70,
+	0x6f, 0x72, 0x74, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x65, 0x6e, 0x64,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x3f, 0x0a, 0x0a, 0x74, 0x6c, 0x73, 0x5f,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x74, 0x6c, 0x73, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x54, 0x4c, 0x53, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x09,
+	0x74, 0x6c, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x58, 0x0a, 0x0b, 0x68, 0x74, 0x74,
+	0x70, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x37,
+	0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x73, 0x75, 0x72,
+	0x66, 0x61, 0x63, 0x65, 0x72, 0x2e, 0x6f, 0x74, 0x65, 0x6c, 0x2e, 0x48, 0x54, 0x54, 0x50, 0x45,
+	0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2e, 0x48, 0x74, 0x74, 0x70, 0x48, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x68, 0x74, 0x74, 0x70, 0x48, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x12, 0x48, 0x0a, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x26, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x2e,
+	0x6f, 0x74, 0x65, 0x6c, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x1a, 0x3d, 0x0a,
+	0x0f, 0x48, 0x74, 0x74, 0x70, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xea, 0x02, 0x0a,
+	0x0c, 0x47, 0x52, 0x50, 0x43, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x12, 0x1a, 0x0a,
+	0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x3f, 0x0a, 0x0a, 0x74, 0x6c, 0x73,
+	0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e,
+	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x74, 0x6c, 0x73, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x54, 0x4c, 0x53, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
+	0x09, 0x74, 0x6c, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x58, 0x0a, 0x0b, 0x68, 0x74,
+	0x74, 0x70, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x37, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x73, 0x75,
+	0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x2e, 0x6f, 0x74, 0x65, 0x6c, 0x2e, 0x47, 0x52, 0x50, 0x43,
+	0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2e, 0x48, 0x74, 0x74, 0x70, 0x48, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x68, 0x74, 0x74, 0x70, 0x48, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x12, 0x48, 0x0a, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x26, 0x2e, 0x63, 0x6c, 0x6f, 0x75,
+	0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72,
+	0x2e, 0x6f, 0x74, 0x65, 0x6c, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a,
+	0x0a, 0x08, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x08, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x1a, 0x3d, 0x0a, 0x0f, 0x48, 0x74,
+	0x74, 0x70, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xcb, 0x01, 0x0a, 0x0b, 0x52, 0x65,
+	0x74, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73,
+	0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x64, 0x69, 0x73,
+	0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x38, 0x0a, 0x15, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c,
+	0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x6d, 0x73, 0x65, 0x63, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x3a, 0x04, 0x35, 0x30, 0x30, 0x30, 0x52, 0x13, 0x69, 0x6e, 0x69, 0x74,
+	0x69, 0x61, 0x6c, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x65, 0x63, 0x12,
+	0x31, 0x0a, 0x11, 0x6d, 0x61, 0x78, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f,
+	0x6d, 0x73, 0x65, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x05, 0x33, 0x30, 0x30, 0x30,
+	0x30, 0x52, 0x0f, 0x6d, 0x61, 0x78, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73,
+	0x65, 0x63, 0x12, 0x33, 0x0a, 0x14, 0x6d, 0x61, 0x78, 0x5f, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65,
+	0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x3a, 0x02, 0x36, 0x30, 0x52, 0x11, 0x6d, 0x61, 0x78, 0x45, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64,
+	0x54, 0x69, 0x6d, 0x65, 0x53, 0x65, 0x63, 0x22, 0x33, 0x0a, 0x09, 0x41, 0x74, 0x74, 0x72, 0x69,
+	0x62, 0x75, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xbf, 0x05, 0x0a,
+	0x0c, 0x53, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x12, 0x57, 0x0a,
+	0x12, 0x6f, 0x74, 0x6c, 0x70, 0x5f, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x65, 0x78, 0x70, 0x6f, 0x72,
+	0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x63, 0x6c, 0x6f, 0x75,
+	0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72,
+	0x2e, 0x6f, 0x74, 0x65, 0x6c, 0x2e, 0x48, 0x54, 0x54, 0x50, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74,
+	0x65, 0x72, 0x48, 0x00, 0x52, 0x10, 0x6f, 0x74, 0x6c, 0x70, 0x48, 0x74, 0x74, 0x70, 0x45, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x12, 0x57, 0x0a, 0x12, 0x6f, 0x74, 0x6c, 0x70, 0x5f, 0x67,
+	0x72, 0x70, 0x63, 0x5f, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x27, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
+	0x2e, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x2e, 0x6f, 0x74, 0x65, 0x6c, 0x2e, 0x47,
+	0x52, 0x50, 0x43, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x48, 0x00, 0x52, 0x10, 0x6f,
+	0x74, 0x6c, 0x70, 0x47, 0x72, 0x70, 0x63, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x12,
+	0x32, 0x0a, 0x13, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x02, 0x31, 0x30,
+	0x52, 0x11, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c,
+	0x53, 0x65, 0x63, 0x12, 0x32, 0x0a, 0x12, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x5f, 0x62,
+	0x61, 0x74, 0x63, 0x68, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x3a,
+	0x04, 0x31, 0x30, 0x30, 0x30, 0x52, 0x10, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x6d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x53,
+	0x0a, 0x12, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x74, 0x74, 0x72, 0x69,
+	0x62, 0x75, 0x74, 0x65, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65,
+	0x72, 0x2e, 0x6f, 0x74, 0x65, 0x6c, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65,
+	0x52, 0x11, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62,
+	0x75, 0x74, 0x65, 0x12, 0x3c, 0x0a, 0x05, 0x72, 0x65, 0x74, 0x72, 0x79, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x26, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
+	0x2e, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x2e, 0x6f, 0x74, 0x65, 0x6c, 0x2e, 0x52,
+	0x65, 0x74, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x05, 0x72, 0x65, 0x74, 0x72,
+	0x79, 0x12, 0x5c, 0x0a, 0x0e, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x35, 0x2e, 0x63, 0x6c, 0x6f, 0x75,
+	0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72,
+	0x2e, 0x6f, 0x74, 0x65, 0x6c, 0x2e, 0x53, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x43, 0x6f,
+	0x6e, 0x66, 0x2e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x0d, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x41, 0x0a, 0x1b, 0x65, 0x78, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x68,
+	0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x5f, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x05, 0x3a, 0x01, 0x32, 0x52, 0x19, 0x65, 0x78, 0x70, 0x6f, 0x6e, 0x65, 0x6e,
+	0x74, 0x69, 0x61, 0x6c, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x53, 0x63, 0x61,
+	0x6c, 0x65, 0x22, 0x2e, 0x0a, 0x0d, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x45, 0x58, 0x50, 0x4c, 0x49, 0x43, 0x49, 0x54, 0x10,
+	0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x45, 0x58, 0x50, 0x4f, 0x4e, 0x45, 0x4e, 0x54, 0x49, 0x41, 0x4c,
+	0x10, 0x01, 0x42, 0x0a, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2a, 0x21,
+	0x0a, 0x0b, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x08, 0x0a,
+	0x04, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x47, 0x5a, 0x49, 0x50, 0x10,
+	0x01, 0x42, 0x42, 0x5a, 0x40, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75,
+	0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72,
+	0x73, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x6f, 0x74, 0x65, 0x6c, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f,
+}
+
+var (
+	file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDescOnce sync.Once
+	file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDescData = file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDesc
+)
+
+func file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDescGZIP() []byte {
+	file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDescOnce.Do(func() {
+		file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDescData)
+	})
+	return file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDescData
+}
+
+var file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_goTypes = []interface{}{
+	(Compression)(0),                // 0: cloudprober.surfacer.otel.Compression
+	(SurfacerConf_HistogramType)(0), // 1: cloudprober.surfacer.otel.SurfacerConf.HistogramType
+	(*HTTPExporter)(nil),            // 2: cloudprober.surfacer.otel.HTTPExporter
+	(*GRPCExporter)(nil),            // 3: cloudprober.surfacer.otel.GRPCExporter
+	(*RetryConfig)(nil),             // 4: cloudprober.surfacer.otel.RetryConfig
+	(*Attribute)(nil),               // 5: cloudprober.surfacer.otel.Attribute
+	(*SurfacerConf)(nil),            // 6: cloudprober.surfacer.otel.SurfacerConf
+	nil,                             // 7: cloudprober.surfacer.otel.HTTPExporter.HttpHeaderEntry
+	nil,                             // 8: cloudprober.surfacer.otel.GRPCExporter.HttpHeaderEntry
+	(*proto.TLSConfig)(nil),         // 9: cloudprober.tlsconfig.TLSConfig
+}
+var file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_depIdxs = []int32{
+	9,  // 0: cloudprober.surfacer.otel.HTTPExporter.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
+	7,  // 1: cloudprober.surfacer.otel.HTTPExporter.http_header:type_name -> cloudprober.surfacer.otel.HTTPExporter.HttpHeaderEntry
+	0,  // 2: cloudprober.surfacer.otel.HTTPExporter.compression:type_name -> cloudprober.surfacer.otel.Compression
+	9,  // 3: cloudprober.surfacer.otel.GRPCExporter.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
+	8,  // 4: cloudprober.surfacer.otel.GRPCExporter.http_header:type_name -> cloudprober.surfacer.otel.GRPCExporter.HttpHeaderEntry
+	0,  // 5: cloudprober.surfacer.otel.GRPCExporter.compression:type_name -> cloudprober.surfacer.otel.Compression
+	2,  // 6: cloudprober.surfacer.otel.SurfacerConf.otlp_http_exporter:type_name -> cloudprober.surfacer.otel.HTTPExporter
+	3,  // 7: cloudprober.surfacer.otel.SurfacerConf.otlp_grpc_exporter:type_name -> cloudprober.surfacer.otel.GRPCExporter
+	5,  // 8: cloudprober.surfacer.otel.SurfacerConf.resource_attribute:type_name -> cloudprober.surfacer.otel.Attribute
+	4,  // 9: cloudprober.surfacer.otel.SurfacerConf.retry:type_name -> cloudprober.surfacer.otel.RetryConfig
+	1,  // 10: cloudprober.surfacer.otel.SurfacerConf.histogram_type:type_name -> cloudprober.surfacer.otel.SurfacerConf.HistogramType
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() {
+	file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_init()
+}
+func file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_init() {
+	if File_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HTTPExporter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GRPCExporter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RetryConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Attribute); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SurfacerConf); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes[4].OneofWrappers = []interface{}{
+		(*SurfacerConf_OtlpHttpExporter)(nil),
+		(*SurfacerConf_OtlpGrpcExporter)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_goTypes,
+		DependencyIndexes: file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_depIdxs,
+		EnumInfos:         file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_enumTypes,
+		MessageInfos:      file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_msgTypes,
+	}.Build()
+	File_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto = out.File
+	file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_rawDesc = nil
+	file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_goTypes = nil
+	file_github_com_cloudprober_cloudprober_surfacers_internal_otel_proto_config_proto_depIdxs = nil
+}
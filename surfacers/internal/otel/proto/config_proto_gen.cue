@@ -0,0 +1,128 @@
+package proto
+
+import "github.com/cloudprober/cloudprober/internal/tlsconfig/proto"
+
+#Compression: {"NONE", #enumValue: 0} |
+	{"GZIP", #enumValue: 1}
+
+#Compression_value: {
+	NONE: 0
+	GZIP: 1
+}
+
+// OTLP/HTTP exporter config.
+#HTTPExporter: {
+	// Metrics endpoint URL, e.g. https://otel-collector:4318/v1/metrics. If
+	// not set, OTEL_EXPORTER_OTLP_METRICS_ENDPOINT or
+	// OTEL_EXPORTER_OTLP_ENDPOINT environment variables are used, and if they
+	// are not set either, http://localhost:4318/v1/metrics.
+	endpointUrl?: string @protobuf(1,string,name=endpoint_url)
+
+	// TLS config for the https endpoints.
+	tlsConfig?: proto.#TLSConfig @protobuf(2,tlsconfig.TLSConfig,name=tls_config)
+
+	// HTTP headers to add to the export requests, e.g. for authentication.
+	httpHeader?: {
+		[string]: string
+	} @protobuf(3,map[string]string,http_header)
+	compression?: #Compression @protobuf(4,Compression)
+}
+
+// OTLP/gRPC exporter config.
+#GRPCExporter: {
+	// Collector's address, e.g. otel-collector:4317. If not set,
+	// OTEL_EXPORTER_OTLP_METRICS_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT
+	// environment variables are used, and if they are not set either,
+	// localhost:4317.
+	endpoint?: string @protobuf(1,string)
+
+	// TLS config. It's ignored if insecure is set.
+	tlsConfig?: proto.#TLSConfig @protobuf(2,tlsconfig.TLSConfig,name=tls_config)
+
+	// gRPC metadata (headers) to add to the export requests.
+	httpHeader?: {
+		[string]: string
+	} @protobuf(3,map[string]string,http_header)
+	compression?: #Compression @protobuf(4,Compression)
+
+	// Use an insecure (plaintext) connection to the collector.
+	insecure?: bool @protobuf(5,bool)
+}
+
+// Retry config for the failed exports. Retries use exponential backoff,
+// and are attempted only for the retryable errors.
+#RetryConfig: {
+	disabled?: bool @protobuf(1,bool)
+
+	// Time to wait after the first failure before retrying.
+	initialIntervalMsec?: int32 @protobuf(2,int32,name=initial_interval_msec,"default=5000")
+
+	// Maximum time to wait between the retries.
+	maxIntervalMsec?: int32 @protobuf(3,int32,name=max_interval_msec,"default=30000")
+
+	// Maximum time spent trying to export a batch, after which the batch is
+	// dropped.
+	maxElapsedTimeSec?: int32 @protobuf(4,int32,name=max_elapsed_time_sec,"default=60")
+}
+
+#Attribute: {
+	key?:   string @protobuf(1,string)
+	value?: string @protobuf(2,string)
+}
+
+// Surfacer config for the OpenTelemetry (OTLP) surfacer.
+//
+// EventMetrics are mapped to the OpenTelemetry metrics in the following way:
+//   - EventMetrics labels become the data point attributes.
+//   - Cumulative numeric metrics become monotonic sums, with cumulative
+//     temporality, and gauge metrics become gauges.
+//   - Map metrics become the data points with the map's name as an
+//     additional attribute, e.g. resp-code map becomes data points with a
+//     "code" attribute.
+//   - Distributions become explicit bucket histograms, or exponential
+//     histograms if histogram_type is EXPONENTIAL.
+//   - String metrics are not exported.
+#SurfacerConf: {
+	{} | {
+		otlpHttpExporter: #HTTPExporter @protobuf(1,HTTPExporter,name=otlp_http_exporter)
+	} | {
+		otlpGrpcExporter: #GRPCExporter @protobuf(2,GRPCExporter,name=otlp_grpc_exporter)
+	}
+
+	// How often to export metrics.
+	exportIntervalSec?: int32 @protobuf(3,int32,name=export_interval_sec,"default=10")
+
+	// Metrics are exported early, before the export interval, if the number of
+	// buffered data points reaches this size. For every metric and attribute
+	// set, only the latest data point is exported.
+	metricsBatchSize?: int32 @protobuf(4,int32,name=metrics_batch_size,"default=1000")
+
+	// Prefix to add to all metric names.
+	metricsPrefix?: string @protobuf(5,string,name=metrics_prefix)
+
+	// Additional resource attributes. By default, resource has the
+	// service.name attribute set to "cloudprober", and the attributes from
+	// the OTEL_RESOURCE_ATTRIBUTES environment variable.
+	resourceAttribute?: [...#Attribute] @protobuf(6,Attribute,name=resource_attribute)
+	retry?: #RetryConfig @protobuf(7,RetryConfig)
+
+	#HistogramType: {"EXPLICIT", #enumValue: 0} |
+		{"EXPONENTIAL", #enumValue: 1}
+
+	#HistogramType_value: {
+		EXPLICIT:    0
+		EXPONENTIAL: 1
+	}
+
+	// How to export distributions. Explicit bucket histograms use the
+	// distribution's buckets as they are. Exponential histograms group the
+	// distribution's samples into the exponential buckets, using the value
+	// that represents each distribution bucket, i.e. bucket's middle point.
+	histogramType?: #HistogramType @protobuf(8,HistogramType,name=histogram_type)
+
+	// Scale of the exponential histograms. Scale determines the resolution:
+	// bucket boundaries are the powers of 2^(2^-scale). For example, scale 2
+	// gives about 19% wide buckets. It's used only for the EXPONENTIAL
+	// histograms, and should be between -10 and 20.
+	exponentialHistogramScale?: int32 @protobuf(9,int32,name=exponential_histogram_scale,"default=2")
+}
@@ -38,6 +38,7 @@ import (
 
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/flush"
 	"github.com/lib/pq"
 
 	configpb "github.com/cloudprober/cloudprober/surfacers/internal/postgres/proto"
@@ -169,6 +170,7 @@ type Surfacer struct {
 
 	// Channel for incoming data.
 	writeChan chan *metrics.EventMetrics
+	loopDone  chan struct{}
 
 	// Cloud logger
 	l *logger.Logger
@@ -255,9 +257,11 @@ func (s *Surfacer) init(ctx context.Context) error {
 	s.columns = generateColumns(s.c.GetLabelToColumn())
 
 	// Start a goroutine to run forever, polling on the writeChan. Allows
-	// for the surfacer to write asynchronously to the serial port.
+	// for the surfacer to write asynchronously to the serial port. Database
+	// is closed by Flush, after writing the remaining metrics.
+	s.loopDone = make(chan struct{})
 	go func() {
-		defer s.db.Close()
+		defer close(s.loopDone)
 
 		for {
 			select {
@@ -265,14 +269,7 @@ func (s *Surfacer) init(ctx context.Context) error {
 				s.l.Infof("Context canceled, stopping the surfacer write loop")
 				return
 			case em := <-s.writeChan:
-				if em.Kind != metrics.CUMULATIVE && em.Kind != metrics.GAUGE {
-					continue
-				}
-				// Note: we may want to batch calls to writeMetrics, as each call results in
-				// a database transaction.
-				if err := s.writeMetrics(em); err != nil {
-					s.l.Warningf("Error while writing metrics: %v", err)
-				}
+				s.write(em)
 			}
 		}
 	}()
@@ -280,6 +277,28 @@ func (s *Surfacer) init(ctx context.Context) error {
 	return nil
 }
 
+func (s *Surfacer) write(em *metrics.EventMetrics) {
+	if em.Kind != metrics.CUMULATIVE && em.Kind != metrics.GAUGE {
+		return
+	}
+	// Note: we may want to batch calls to writeMetrics, as each call results in
+	// a database transaction.
+	if err := s.writeMetrics(em); err != nil {
+		s.l.Warningf("Error while writing metrics: %v", err)
+	}
+}
+
+// Flush writes the EventMetrics remaining in the write channel and closes the
+// database, once the write loop has stopped, i.e. after the context passed to
+// New is canceled.
+func (s *Surfacer) Flush(ctx context.Context) error {
+	if err := flush.WaitForStop(ctx, s.loopDone); err != nil {
+		return err
+	}
+	defer s.db.Close()
+	return flush.DroppedError(ctx, flush.Drain(ctx, s.writeChan, s.write), "EventMetrics")
+}
+
 // Write takes the data to be written
 func (s *Surfacer) Write(ctx context.Context, em *metrics.EventMetrics) {
 	select {
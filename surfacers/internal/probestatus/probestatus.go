@@ -199,6 +199,12 @@ func New(ctx context.Context, config *configpb.SurfacerConf, opts *options.Optio
 	return ps, nil
 }
 
+// Flush is a no-op: metrics are served by the probe status page from memory, and there is nothing
+// buffered to export.
+func (ps *Surfacer) Flush(_ context.Context) error {
+	return nil
+}
+
 // Write queues the incoming data into a channel. This channel is watched by a
 // goroutine that actually processes the data and updates the in-memory
 // database.
@@ -36,12 +36,18 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/cloudprober/cloudprober/config/runconfig"
+	"github.com/cloudprober/cloudprober/internal/sysvars"
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
 	"github.com/cloudprober/cloudprober/surfacers/internal/common/options"
@@ -88,6 +94,12 @@ type promMetric struct {
 type dataPoint struct {
 	value     string
 	timestamp int64
+	target    targetKey
+}
+
+// targetKey identifies a probe's target, using the "probe" and "dst" labels.
+type targetKey struct {
+	probe, dst string
 }
 
 // httpWriter is a wrapper for http.ResponseWriter that includes a channel
@@ -122,15 +134,30 @@ type PromSurfacer struct {
 	// Regexes for metric and label names.
 	metricNameRe *regexp.Regexp
 	labelNameRe  *regexp.Regexp
+
+	// Last timestamps at which targets and probes reported metrics, used to
+	// detect the targets that are no longer being probed.
+	staleTargetTimeout int64 // In milliseconds.
+	targetLastSeen     map[targetKey]int64
+	probeLastSeen      map[string]int64
+
+	// Value of the "_created" series, i.e. process start time in Unix
+	// seconds. Empty if export_created_timestamp is not enabled.
+	createdValue string
 }
 
-// New returns a prometheus surfacer based on the config provided. It sets up a
-// goroutine to process both the incoming EventMetrics and the web requests for
-// the URL handler /metrics.
-func New(ctx context.Context, config *configpb.SurfacerConf, opts *options.Options, l *logger.Logger) (*PromSurfacer, error) {
+// newSurfacer validates the config and returns a prometheus surfacer,
+// without starting it.
+func newSurfacer(config *configpb.SurfacerConf, opts *options.Options, l *logger.Logger) (*PromSurfacer, error) {
 	if config == nil {
 		config = &configpb.SurfacerConf{}
 	}
+	if config.GetStaleTargetTimeoutSec() < 0 {
+		return nil, fmt.Errorf("stale_target_timeout_sec (%d) cannot be negative", config.GetStaleTargetTimeoutSec())
+	}
+	if config.GetUnixSocketOnly() && config.GetUnixSocketPath() == "" {
+		return nil, fmt.Errorf("unix_socket_only is set but unix_socket_path is not")
+	}
 	ps := &PromSurfacer{
 		c:            config,
 		opts:         opts,
@@ -141,6 +168,14 @@ func New(ctx context.Context, config *configpb.SurfacerConf, opts *options.Optio
 		metricNameRe: regexp.MustCompile(ValidMetricNameRegex),
 		labelNameRe:  regexp.MustCompile(ValidLabelNameRegex),
 		l:            l,
+
+		staleTargetTimeout: (time.Duration(config.GetStaleTargetTimeoutSec()) * time.Second).Milliseconds(),
+		targetLastSeen:     make(map[targetKey]int64),
+		probeLastSeen:      make(map[string]int64),
+	}
+
+	if ps.c.GetExportCreatedTimestamp() {
+		ps.createdValue = strconv.FormatFloat(float64(processStartTime().UnixMilli())/1000, 'f', -1, 64)
 	}
 
 	if ps.c.GetIncludeTimestamp() {
@@ -152,6 +187,17 @@ func New(ctx context.Context, config *configpb.SurfacerConf, opts *options.Optio
 			fmt.Fprintf(w, "%s %s\n", k, pm.data[k].value)
 		}
 	}
+	return ps, nil
+}
+
+// New returns a prometheus surfacer based on the config provided. It sets up a
+// goroutine to process both the incoming EventMetrics and the web requests for
+// the URL handler /metrics.
+func New(ctx context.Context, config *configpb.SurfacerConf, opts *options.Options, l *logger.Logger) (*PromSurfacer, error) {
+	ps, err := newSurfacer(config, opts, l)
+	if err != nil {
+		return nil, err
+	}
 
 	// Start a goroutine to process the incoming EventMetrics as well as
 	// the incoming web queries. To avoid data access race conditions, we do
@@ -176,7 +222,7 @@ func New(ctx context.Context, config *configpb.SurfacerConf, opts *options.Optio
 		}
 	}()
 
-	opts.HTTPServeMux.HandleFunc(ps.c.GetMetricsUrl(), func(w http.ResponseWriter, r *http.Request) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// doneChan is used to track the completion of the response writing. This is
 		// required as response is written in a different goroutine.
 		doneChan := make(chan struct{}, 1)
@@ -184,10 +230,100 @@ func New(ctx context.Context, config *configpb.SurfacerConf, opts *options.Optio
 		<-doneChan
 	})
 
-	l.Infof("Initialized prometheus exporter at the URL: %s", ps.c.GetMetricsUrl())
+	if ps.c.GetUnixSocketPath() != "" {
+		if err := ps.serveUnixSocket(ctx, handler); err != nil {
+			return nil, err
+		}
+		l.Infof("Initialized prometheus exporter at the URL: %s on the unix socket: %s", ps.c.GetMetricsUrl(), ps.c.GetUnixSocketPath())
+	}
+
+	if !ps.c.GetUnixSocketOnly() {
+		opts.HTTPServeMux.Handle(ps.c.GetMetricsUrl(), handler)
+		l.Infof("Initialized prometheus exporter at the URL: %s", ps.c.GetMetricsUrl())
+	}
 	return ps, nil
 }
 
+// NewDryRunPayload returns a function that returns the data that the
+// surfacer would serve for the given EventMetrics, in the Prometheus text
+// format. It's used in the dry_run mode, where the surfacer is not started.
+// The surfacer is created once and reused, but its state is reset after each
+// EventMetrics, so that the payload has only that EventMetrics' data.
+func NewDryRunPayload(config *configpb.SurfacerConf, opts *options.Options, l *logger.Logger) (func(*metrics.EventMetrics) string, error) {
+	ps, err := newSurfacer(config, opts, l)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	return func(em *metrics.EventMetrics) string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		ps.record(em)
+		var b strings.Builder
+		ps.writeData(&b)
+
+		ps.metrics, ps.metricNames = make(map[string]*promMetric), nil
+		ps.targetLastSeen, ps.probeLastSeen = make(map[targetKey]int64), make(map[string]int64)
+		return b.String()
+	}, nil
+}
+
+// listenUnix creates a unix socket listener at the given path. A socket file
+// left behind at the path, e.g. by an unclean shutdown, is removed first, but
+// we never remove other kinds of files.
+func listenUnix(path string) (net.Listener, error) {
+	if fi, err := os.Stat(filepath.Dir(path)); err != nil || !fi.IsDir() {
+		return nil, fmt.Errorf("unix socket path (%s): parent directory doesn't exist", path)
+	}
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("unix socket path (%s): file exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("unix socket path (%s): error removing the old socket: %v", path, err)
+		}
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("unix socket path (%s) is not writable: %v", path, err)
+	}
+	return ln, nil
+}
+
+// serveUnixSocket serves the metrics URL on the configured unix socket, until
+// the context is canceled.
+func (ps *PromSurfacer) serveUnixSocket(ctx context.Context, handler http.Handler) error {
+	ln, err := listenUnix(ps.c.GetUnixSocketPath())
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(ps.c.GetMetricsUrl(), handler)
+	srv := &http.Server{Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		// This closes the listener as well, which removes the socket file.
+		srv.Close()
+	}()
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			ps.l.Errorf("Error serving metrics on the unix socket %s: %v", ps.c.GetUnixSocketPath(), err)
+		}
+	}()
+	return nil
+}
+
+// Flush is a no-op: metrics are scraped by Prometheus from memory, and there is nothing
+// buffered to export.
+func (ps *PromSurfacer) Flush(_ context.Context) error {
+	return nil
+}
+
 // Write queues the incoming data into a channel. This channel is watched by a
 // goroutine that actually processes the data and updates the in-memory
 // database.
@@ -216,17 +352,21 @@ func promTime(t time.Time) int64 {
 }
 
 func (ps *PromSurfacer) recordMetric(metricName, key, value string, em *metrics.EventMetrics, typ string) {
+	target := targetKey{em.Label("probe"), em.Label("dst")}
+
 	// Recognized metric
 	if pm := ps.metrics[metricName]; pm != nil {
 		// Recognized metric name and labels combination.
 		if pm.data[key] != nil {
 			pm.data[key].value = value
 			pm.data[key].timestamp = promTime(em.Timestamp)
+			pm.data[key].target = target
 			return
 		}
 		pm.data[key] = &dataPoint{
 			value:     value,
 			timestamp: promTime(em.Timestamp),
+			target:    target,
 		}
 		pm.dataKeys = append(pm.dataKeys, key)
 	} else {
@@ -240,6 +380,7 @@ func (ps *PromSurfacer) recordMetric(metricName, key, value string, em *metrics.
 				key: {
 					value:     value,
 					timestamp: promTime(em.Timestamp),
+					target:    target,
 				},
 			},
 			dataKeys: []string{key},
@@ -336,6 +477,8 @@ func recordMap[T int64 | float64](ps *PromSurfacer, m *metrics.Map[T], em *metri
 //
 //	version{val=cloudprober-20170608-RC00} 1
 func (ps *PromSurfacer) record(em *metrics.EventMetrics) {
+	ps.recordTarget(em)
+
 	var labels []string
 	for _, k := range em.LabelsKeys() {
 		if labelName := ps.checkLabelName(k); labelName != "" {
@@ -387,14 +530,106 @@ func (ps *PromSurfacer) record(em *metrics.EventMetrics) {
 	}
 }
 
+// recordTarget records the time at which the EventMetrics' target and probe
+// last reported metrics.
+func (ps *PromSurfacer) recordTarget(em *metrics.EventMetrics) {
+	if ps.staleTargetTimeout == 0 || em.Label("probe") == "" || em.Label("dst") == "" {
+		return
+	}
+	ts := promTime(em.Timestamp)
+	tk := targetKey{em.Label("probe"), em.Label("dst")}
+	if ts > ps.targetLastSeen[tk] {
+		ps.targetLastSeen[tk] = ts
+	}
+	if ts > ps.probeLastSeen[tk.probe] {
+		ps.probeLastSeen[tk.probe] = ts
+	}
+}
+
+// deleteStaleTargets deletes metrics for the targets that have not been
+// reported for staleTargetTimeout, while their probe has reported metrics
+// for other targets.
+func (ps *PromSurfacer) deleteStaleTargets() {
+	if ps.staleTargetTimeout == 0 {
+		return
+	}
+
+	staleTargets := make(map[targetKey]bool)
+	for tk, ts := range ps.targetLastSeen {
+		if ts < ps.probeLastSeen[tk.probe]-ps.staleTargetTimeout {
+			staleTargets[tk] = true
+			delete(ps.targetLastSeen, tk)
+		}
+	}
+	if len(staleTargets) == 0 {
+		return
+	}
+
+	for _, name := range ps.metricNames {
+		pm := ps.metrics[name]
+		dataKeys := pm.dataKeys[:0]
+		for _, k := range pm.dataKeys {
+			if staleTargets[pm.data[k].target] {
+				delete(pm.data, k)
+				continue
+			}
+			dataKeys = append(dataKeys, k)
+		}
+		pm.dataKeys = dataKeys
+	}
+	ps.l.Infof("Removed metrics for %d stale target(s)", len(staleTargets))
+}
+
 // writeData writes metrics data on w io.Writer
 func (ps *PromSurfacer) writeData(w io.Writer) {
+	ps.deleteStaleTargets()
+
+	if ps.c.GetIncludeInfoHeader() {
+		fmt.Fprintf(w, "# cloudprober_info version=%q config_checksum=%q\n", runconfig.Version(), runconfig.ConfigChecksum())
+	}
 	for _, name := range ps.metricNames {
 		pm := ps.metrics[name]
 		fmt.Fprintf(w, "# TYPE %s %s\n", name, pm.typ)
 		for _, k := range pm.dataKeys {
 			ps.dataWriter(w, pm, k)
+			if ps.createdValue != "" {
+				ps.writeCreated(w, name, pm, k)
+			}
+		}
+	}
+}
+
+// processStartTime returns the cloudprober process start time. It's set
+// once per process, so it doesn't change on config reloads.
+var processStartTime = func() time.Time {
+	if ts := sysvars.StartTime(); !ts.IsZero() {
+		return ts
+	}
+	return time.Now()
+}
+
+// writeCreated writes the "_created" series for the given counter or
+// histogram data key. For histograms, "_created" series goes with the
+// "_count" series, as there is one per histogram.
+func (ps *PromSurfacer) writeCreated(w io.Writer, name string, pm *promMetric, k string) {
+	var labels string
+	switch pm.typ {
+	case "counter":
+		labels = strings.TrimPrefix(k, name)
+	case histogram:
+		var ok bool
+		if labels, ok = strings.CutPrefix(k, name+"_count"); !ok {
+			return
 		}
+	default:
+		return
+	}
+
+	createdKey := name + "_created" + labels
+	if ps.c.GetIncludeTimestamp() {
+		fmt.Fprintf(w, "%s %s %d\n", createdKey, ps.createdValue, pm.data[k].timestamp)
+	} else {
+		fmt.Fprintf(w, "%s %s\n", createdKey, ps.createdValue)
 	}
 }
 
@@ -419,6 +654,17 @@ func (ps *PromSurfacer) deleteExpiredMetrics() {
 			pm.dataKeys = deleteFromSlice(pm.dataKeys, expiredMetricKey)
 		}
 	}
+
+	for tk, ts := range ps.targetLastSeen {
+		if ts < staleTimeThreshold {
+			delete(ps.targetLastSeen, tk)
+		}
+	}
+	for probe, ts := range ps.probeLastSeen {
+		if ts < staleTimeThreshold {
+			delete(ps.probeLastSeen, probe)
+		}
+	}
 }
 
 // deleteFromSlice delete target on slice
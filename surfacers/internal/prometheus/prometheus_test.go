@@ -18,12 +18,18 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"math/rand"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/cloudprober/cloudprober/config/runconfig"
 	"github.com/cloudprober/cloudprober/metrics"
 	"github.com/cloudprober/cloudprober/surfacers/internal/common/options"
 	configpb "github.com/cloudprober/cloudprober/surfacers/internal/prometheus/proto"
@@ -292,3 +298,218 @@ func TestScrapeOutputWithExpiredTimeMetrics(t *testing.T) {
 		}
 	}
 }
+
+func TestScrapeOutputInfoHeader(t *testing.T) {
+	runconfig.SetVersion("v1.2.3")
+	runconfig.SetConfigChecksum("abcd")
+	defer runconfig.SetVersion("")
+	defer runconfig.SetConfigChecksum("")
+
+	em := metrics.NewEventMetrics(time.Now()).
+		AddMetric("sent", metrics.NewInt(32)).
+		AddLabel("ptype", "http")
+	wantHeader := "# cloudprober_info version=\"v1.2.3\" config_checksum=\"abcd\"\n"
+
+	for _, includeHeader := range []bool{false, true} {
+		t.Run(fmt.Sprintf("include_info_header=%v", includeHeader), func(t *testing.T) {
+			ps := newPromSurfacer(t, false)
+			ps.c.IncludeInfoHeader = proto.Bool(includeHeader)
+			ps.record(em)
+
+			var b bytes.Buffer
+			ps.writeData(&b)
+			data := b.String()
+
+			if got := strings.HasPrefix(data, wantHeader); got != includeHeader {
+				t.Errorf("Header %q at the start of output=%v, want=%v. Output: %s", wantHeader, got, includeHeader, data)
+			}
+			if !strings.Contains(data, "sent{ptype=\"http\"} 32") {
+				t.Errorf("Metric not found in output data: %s", data)
+			}
+		})
+	}
+}
+
+func TestScrapeOutputWithStaleTargets(t *testing.T) {
+	ps := newPromSurfacer(t, false)
+	ps.staleTargetTimeout = time.Minute.Milliseconds()
+
+	targetEM := func(ts time.Time, probe, dst string) *metrics.EventMetrics {
+		return metrics.NewEventMetrics(ts).
+			AddMetric("total", metrics.NewInt(10)).
+			AddLabel("ptype", "http").
+			AddLabel("probe", probe).
+			AddLabel("dst", dst)
+	}
+
+	now := time.Now()
+	for _, em := range []*metrics.EventMetrics{
+		targetEM(now.Add(-2*time.Minute), "p1", "t1"),
+		targetEM(now.Add(-2*time.Minute), "p1", "t2"),
+		targetEM(now.Add(-2*time.Minute), "p2", "t1"),
+		// p1 keeps reporting t1, but no longer reports t2.
+		targetEM(now, "p1", "t1"),
+	} {
+		ps.record(em)
+	}
+
+	var b bytes.Buffer
+	ps.writeData(&b)
+	data := b.String()
+
+	for _, d := range []string{
+		"total{ptype=\"http\",probe=\"p1\",dst=\"t1\"} 10",
+		// p2 has not reported anything else, so its target is not stale.
+		"total{ptype=\"http\",probe=\"p2\",dst=\"t1\"} 10",
+	} {
+		if !strings.Contains(data, d) {
+			t.Errorf("String \"%s\" not found in output data: %s", d, data)
+		}
+	}
+	if d := "dst=\"t2\""; strings.Contains(data, d) {
+		t.Errorf("Output data contains stale target (%s): %s", d, data)
+	}
+
+	// Target comes back.
+	ps.record(targetEM(now, "p1", "t2"))
+	b.Reset()
+	ps.writeData(&b)
+	if d := "total{ptype=\"http\",probe=\"p1\",dst=\"t2\"} 10"; !strings.Contains(b.String(), d) {
+		t.Errorf("String \"%s\" not found in output data: %s", d, b.String())
+	}
+}
+
+func TestNewWithInvalidStaleTargetTimeout(t *testing.T) {
+	c := &configpb.SurfacerConf{
+		MetricsUrl:            proto.String(fmt.Sprintf("/metrics_%d", rand.Int())),
+		StaleTargetTimeoutSec: proto.Int32(-1),
+	}
+	if _, err := New(context.Background(), c, &options.Options{HTTPServeMux: http.NewServeMux()}, nil); err == nil {
+		t.Error("Expected error for negative stale_target_timeout_sec, got nil")
+	}
+}
+
+func TestUnixSocket(t *testing.T) {
+	for _, socketOnly := range []bool{false, true} {
+		t.Run(fmt.Sprintf("socket_only=%v", socketOnly), func(t *testing.T) {
+			socketPath := filepath.Join(t.TempDir(), "prom.sock")
+
+			// Leave a stale socket behind, as an unclean shutdown would.
+			ln, err := net.Listen("unix", socketPath)
+			if err != nil {
+				t.Fatalf("Error creating the stale socket: %v", err)
+			}
+			ln.(*net.UnixListener).SetUnlinkOnClose(false)
+			ln.Close()
+
+			c := &configpb.SurfacerConf{
+				UnixSocketPath: proto.String(socketPath),
+				UnixSocketOnly: proto.Bool(socketOnly),
+			}
+			mux := http.NewServeMux()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ps, err := New(ctx, c, &options.Options{HTTPServeMux: mux}, nil)
+			if err != nil {
+				t.Fatalf("Error while initializing prometheus surfacer: %v", err)
+			}
+			ps.record(newEventMetrics(32, 22, map[string]int64{}, "http", "vm-to-google"))
+
+			client := &http.Client{
+				Transport: &http.Transport{
+					DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+						return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+					},
+				},
+			}
+			resp, err := client.Get("http://unix/metrics")
+			if err != nil {
+				t.Fatalf("Error scraping metrics over the unix socket: %v", err)
+			}
+			b, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if d := "sent{ptype=\"http\",probe=\"vm-to-google\"} 32"; !strings.Contains(string(b), d) {
+				t.Errorf("String \"%s\" not found in output data: %s", d, string(b))
+			}
+
+			_, pattern := mux.Handler(httptest.NewRequest(http.MethodGet, "/metrics", nil))
+			if got := pattern == "/metrics"; got == socketOnly {
+				t.Errorf("Metrics handler registered on the HTTP server=%v, socket_only=%v", got, socketOnly)
+			}
+
+			cancel()
+			for i := 0; i < 50; i++ {
+				if _, err = os.Stat(socketPath); os.IsNotExist(err) {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			if !os.IsNotExist(err) {
+				t.Errorf("Socket file %s not removed after shutdown, stat error: %v", socketPath, err)
+			}
+		})
+	}
+}
+
+func TestUnixSocketErrors(t *testing.T) {
+	dir := t.TempDir()
+	regularFile := filepath.Join(dir, "regular")
+	if err := os.WriteFile(regularFile, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []*configpb.SurfacerConf{
+		{UnixSocketOnly: proto.Bool(true)},
+		{UnixSocketPath: proto.String(filepath.Join(dir, "missing", "prom.sock"))},
+		{UnixSocketPath: proto.String(regularFile)},
+	} {
+		if _, err := New(context.Background(), c, &options.Options{HTTPServeMux: http.NewServeMux()}, nil); err == nil {
+			t.Errorf("Expected error for config: %v, got nil", c)
+		}
+	}
+}
+
+func TestScrapeOutputCreatedTimestamp(t *testing.T) {
+	oldStartTime := processStartTime
+	defer func() { processStartTime = oldStartTime }()
+	processStartTime = func() time.Time { return time.UnixMilli(1699000000123) }
+
+	c := &configpb.SurfacerConf{
+		MetricsUrl:             proto.String(fmt.Sprintf("/metrics_%d", rand.Int())),
+		IncludeTimestamp:       proto.Bool(false),
+		ExportCreatedTimestamp: proto.Bool(true),
+	}
+	ps, err := New(context.Background(), c, &options.Options{HTTPServeMux: http.NewServeMux()}, nil)
+	if err != nil {
+		t.Fatal("Error while initializing prometheus surfacer", err)
+	}
+
+	latencyVal := metrics.NewDistribution([]float64{1})
+	latencyVal.AddSample(0.5)
+	ps.record(metrics.NewEventMetrics(time.Now()).
+		AddMetric("sent", metrics.NewInt(32)).
+		AddMetric("latency", latencyVal).
+		AddLabel("ptype", "http"))
+	gaugeEM := metrics.NewEventMetrics(time.Now()).
+		AddMetric("queue_size", metrics.NewInt(5)).
+		AddLabel("ptype", "http")
+	gaugeEM.Kind = metrics.GAUGE
+	ps.record(gaugeEM)
+
+	var b bytes.Buffer
+	ps.writeData(&b)
+	data := b.String()
+	for _, d := range []string{
+		"sent{ptype=\"http\"} 32\n",
+		"sent_created{ptype=\"http\"} 1699000000.123\n",
+		"latency_created{ptype=\"http\"} 1699000000.123\n",
+		"queue_size{ptype=\"http\"} 5\n",
+	} {
+		if !strings.Contains(data, d) {
+			t.Errorf("String \"%s\" not found in output data: %s", d, data)
+		}
+	}
+	if n := strings.Count(data, "_created"); n != 2 {
+		t.Errorf("Got %d _created series, want 2. Output data: %s", n, data)
+	}
+}
@@ -43,6 +43,45 @@ type SurfacerConf struct {
 	// "cloudprober_" will result in metrics with names:
 	// cloudprober_total, cloudprober_success, cloudprober_latency, ..
 	MetricsPrefix *string `protobuf:"bytes,4,opt,name=metrics_prefix,json=metricsPrefix" json:"metrics_prefix,omitempty"`
+	// If set to true, metrics output starts with a comment line that records
+	// the cloudprober version and the config checksum, e.g.:
+	//
+	//	# cloudprober_info version="v0.13.0" config_checksum="1c3f4a7b9d2e5f60"
+	//
+	// Prometheus and other standard exposition format parsers ignore comment
+	// lines, but scrapers can use it to record which config version produced a
+	// scrape.
+	IncludeInfoHeader *bool `protobuf:"varint,5,opt,name=include_info_header,json=includeInfoHeader" json:"include_info_header,omitempty"`
+	// If set, metrics for a target are removed from the output once its probe
+	// has stopped reporting it for this duration, while still reporting other
+	// targets, e.g. when a target disappears from the targets discovery. Since
+	// target's series are no longer exported, Prometheus marks them as stale
+	// (if include_timestamp is false) or lets them age out, instead of
+	// continuing to show their last values.
+	// Set it to a few stats export intervals, to avoid removing targets that
+	// are merely slow to report. Default is to keep the metrics until they
+	// expire (10 minutes).
+	StaleTargetTimeoutSec *int32 `protobuf:"varint,6,opt,name=stale_target_timeout_sec,json=staleTargetTimeoutSec" json:"stale_target_timeout_sec,omitempty"`
+	// If set, metrics are also served on this Unix domain socket, at the same
+	// metrics_url, e.g. for sidecar setups that scrape over a socket instead of
+	// exposing a TCP port. Socket's directory must exist and be writable. An
+	// existing socket file at this path is replaced, and the socket file is
+	// removed on shutdown.
+	UnixSocketPath *string `protobuf:"bytes,7,opt,name=unix_socket_path,json=unixSocketPath" json:"unix_socket_path,omitempty"`
+	// If true, metrics are served only on the unix_socket_path, and not on the
+	// cloudprober's HTTP server.
+	UnixSocketOnly *bool `protobuf:"varint,8,opt,name=unix_socket_only,json=unixSocketOnly" json:"unix_socket_only,omitempty"`
+	// If set to true, every counter (and histogram) series gets a companion
+	// "<metric>_created" series, as in OpenMetrics, set to the cloudprober
+	// process start time in Unix seconds, e.g.:
+	//
+	//	total{ptype="http",probe="google",dst="google.com"} 125
+	//	total_created{ptype="http",probe="google",dst="google.com"} 1699000000.123
+	//
+	// Since counters start from zero when cloudprober restarts, backends can
+	// use it to detect counter resets. It changes only when the process
+	// restarts, not on config reloads.
+	ExportCreatedTimestamp *bool `protobuf:"varint,9,opt,name=export_created_timestamp,json=exportCreatedTimestamp" json:"export_created_timestamp,omitempty"`
 }
 
 // Default values for SurfacerConf fields.
@@ -112,6 +151,41 @@ func (x *SurfacerConf) GetMetricsPrefix() string {
 	return ""
 }
 
+func (x *SurfacerConf) GetIncludeInfoHeader() bool {
+	if x != nil && x.IncludeInfoHeader != nil {
+		return *x.IncludeInfoHeader
+	}
+	return false
+}
+
+func (x *SurfacerConf) GetStaleTargetTimeoutSec() int32 {
+	if x != nil && x.StaleTargetTimeoutSec != nil {
+		return *x.StaleTargetTimeoutSec
+	}
+	return 0
+}
+
+func (x *SurfacerConf) GetUnixSocketPath() string {
+	if x != nil && x.UnixSocketPath != nil {
+		return *x.UnixSocketPath
+	}
+	return ""
+}
+
+func (x *SurfacerConf) GetUnixSocketOnly() bool {
+	if x != nil && x.UnixSocketOnly != nil {
+		return *x.UnixSocketOnly
+	}
+	return false
+}
+
+func (x *SurfacerConf) GetExportCreatedTimestamp() bool {
+	if x != nil && x.ExportCreatedTimestamp != nil {
+		return *x.ExportCreatedTimestamp
+	}
+	return false
+}
+
 var File_github_com_cloudprober_cloudprober_surfacers_internal_prometheus_proto_config_proto protoreflect.FileDescriptor
 
 var file_github_com_cloudprober_cloudprober_surfacers_internal_prometheus_proto_config_proto_rawDesc = []byte{
@@ -122,7 +196,7 @@ var file_github_com_cloudprober_cloudprober_surfacers_internal_prometheus_proto_
 	0x75, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62,
 	0x65, 0x72, 0x2e, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x6d,
-	0x65, 0x74, 0x68, 0x65, 0x75, 0x73, 0x22, 0xca, 0x01, 0x0a, 0x0c, 0x53, 0x75, 0x72, 0x66, 0x61,
+	0x65, 0x74, 0x68, 0x65, 0x75, 0x73, 0x22, 0xc1, 0x03, 0x0a, 0x0c, 0x53, 0x75, 0x72, 0x66, 0x61,
 	0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x12, 0x35, 0x0a, 0x13, 0x6d, 0x65, 0x74, 0x72, 0x69,
 	0x63, 0x73, 0x5f, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x03, 0x3a, 0x05, 0x31, 0x30, 0x30, 0x30, 0x30, 0x52, 0x11, 0x6d, 0x65, 0x74,
@@ -135,11 +209,27 @@ var file_github_com_cloudprober_cloudprober_surfacers_internal_prometheus_proto_
 	0x52, 0x0a, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x55, 0x72, 0x6c, 0x12, 0x25, 0x0a, 0x0e,
 	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x04,
 	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x50, 0x72, 0x65,
-	0x66, 0x69, 0x78, 0x42, 0x48, 0x5a, 0x46, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c,
-	0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63,
-	0x65, 0x72, 0x73, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x72, 0x6f,
-	0x6d, 0x65, 0x74, 0x68, 0x65, 0x75, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x66, 0x69, 0x78, 0x12, 0x2e, 0x0a, 0x13, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x69,
+	0x6e, 0x66, 0x6f, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x11, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x48, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x12, 0x37, 0x0a, 0x18, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x5f, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x15, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x54, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x12, 0x28, 0x0a, 0x10,
+	0x75, 0x6e, 0x69, 0x78, 0x5f, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x75, 0x6e, 0x69, 0x78, 0x53, 0x6f, 0x63, 0x6b,
+	0x65, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12, 0x28, 0x0a, 0x10, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x73,
+	0x6f, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0e, 0x75, 0x6e, 0x69, 0x78, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f, 0x6e, 0x6c, 0x79,
+	0x12, 0x38, 0x0a, 0x18, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x16, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x48, 0x5a, 0x46, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
+	0x2f, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x73, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x68, 0x65, 0x75, 0x73, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f,
 }
 
 var (
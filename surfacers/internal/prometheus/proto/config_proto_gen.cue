@@ -22,4 +22,44 @@ package proto
 	// "cloudprober_" will result in metrics with names:
 	// cloudprober_total, cloudprober_success, cloudprober_latency, ..
 	metricsPrefix?: string @protobuf(4,string,name=metrics_prefix)
+
+	// If set to true, metrics output starts with a comment line that records
+	// the cloudprober version and the config checksum, e.g.:
+	//   # cloudprober_info version="v0.13.0" config_checksum="1c3f4a7b9d2e5f60"
+	// Prometheus and other standard exposition format parsers ignore comment
+	// lines, but scrapers can use it to record which config version produced a
+	// scrape.
+	includeInfoHeader?: bool @protobuf(5,bool,name=include_info_header)
+
+	// If set, metrics for a target are removed from the output once its probe
+	// has stopped reporting it for this duration, while still reporting other
+	// targets, e.g. when a target disappears from the targets discovery. Since
+	// target's series are no longer exported, Prometheus marks them as stale
+	// (if include_timestamp is false) or lets them age out, instead of
+	// continuing to show their last values.
+	// Set it to a few stats export intervals, to avoid removing targets that
+	// are merely slow to report. Default is to keep the metrics until they
+	// expire (10 minutes).
+	staleTargetTimeoutSec?: int32 @protobuf(6,int32,name=stale_target_timeout_sec)
+
+	// If set, metrics are also served on this Unix domain socket, at the same
+	// metrics_url, e.g. for sidecar setups that scrape over a socket instead of
+	// exposing a TCP port. Socket's directory must exist and be writable. An
+	// existing socket file at this path is replaced, and the socket file is
+	// removed on shutdown.
+	unixSocketPath?: string @protobuf(7,string,name=unix_socket_path)
+
+	// If true, metrics are served only on the unix_socket_path, and not on the
+	// cloudprober's HTTP server.
+	unixSocketOnly?: bool @protobuf(8,bool,name=unix_socket_only)
+
+	// If set to true, every counter (and histogram) series gets a companion
+	// "<metric>_created" series, as in OpenMetrics, set to the cloudprober
+	// process start time in Unix seconds, e.g.:
+	//   total{ptype="http",probe="google",dst="google.com"} 125
+	//   total_created{ptype="http",probe="google",dst="google.com"} 1699000000.123
+	// Since counters start from zero when cloudprober restarts, backends can
+	// use it to detect counter resets. It changes only when the process
+	// restarts, not on config reloads.
+	exportCreatedTimestamp?: bool @protobuf(9,bool,name=export_created_timestamp)
 }
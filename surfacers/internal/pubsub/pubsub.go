@@ -29,6 +29,7 @@ import (
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
 	"github.com/cloudprober/cloudprober/surfacers/internal/common/compress"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/flush"
 	"github.com/cloudprober/cloudprober/surfacers/internal/common/options"
 
 	configpb "github.com/cloudprober/cloudprober/surfacers/internal/pubsub/proto"
@@ -74,9 +75,13 @@ type Surfacer struct {
 	starttime         string
 	compressionBuffer *compress.CompressionBuffer
 	processInputWg    sync.WaitGroup
+
+	// Closed when the input processing and the publish results loops stop.
+	loopDone        chan struct{}
+	resultsLoopDone chan struct{}
 }
 
-func (s *Surfacer) publishMessage(globalCtx context.Context, data []byte) {
+func (s *Surfacer) publish(ctx context.Context, data []byte) *pubsub.PublishResult {
 	boolToString := map[bool]string{
 		true:  "true",
 		false: "false",
@@ -88,14 +93,18 @@ func (s *Surfacer) publishMessage(globalCtx context.Context, data []byte) {
 		},
 		Data: data,
 	}
+	return s.topic.Publish(ctx, msg)
+}
 
+func (s *Surfacer) publishMessage(globalCtx context.Context, data []byte) {
 	publishCtx, cancel := context.WithTimeout(globalCtx, publishTimeout)
 	defer cancel()
-	s.publishResultChan <- s.topic.Publish(publishCtx, msg)
+	s.publishResultChan <- s.publish(publishCtx, data)
 }
 
 func (s *Surfacer) processInput(ctx context.Context) {
 	defer s.processInputWg.Done()
+	defer close(s.loopDone)
 
 	for {
 		select {
@@ -117,6 +126,8 @@ func (s *Surfacer) processInput(ctx context.Context) {
 
 func (s *Surfacer) init(ctx context.Context) error {
 	s.inChan = make(chan *metrics.EventMetrics, s.opts.MetricsBufferSize)
+	s.loopDone = make(chan struct{})
+	s.resultsLoopDone = make(chan struct{})
 
 	// We use start timestamp in millisecond as the incarnation id.
 	s.starttime = strconv.FormatInt(time.Now().UnixNano()/(1000*1000), 10)
@@ -156,11 +167,12 @@ func (s *Surfacer) init(ctx context.Context) error {
 		s.topic = topic
 	}
 
+	// Topic is stopped by Flush, after publishing the remaining metrics.
 	go func() {
+		defer close(s.resultsLoopDone)
 		for {
 			select {
 			case <-ctx.Done():
-				s.topic.Stop()
 				return
 			case res, ok := <-s.publishResultChan:
 				if !ok {
@@ -175,8 +187,11 @@ func (s *Surfacer) init(ctx context.Context) error {
 	}()
 
 	if s.c.GetCompressionEnabled() {
+		// Compressed data is also published while flushing, i.e. after ctx
+		// is canceled.
+		publishCtx := context.WithoutCancel(ctx)
 		s.compressionBuffer = compress.NewCompressionBuffer(ctx, func(data []byte) {
-			s.publishMessage(ctx, data)
+			s.publishMessage(publishCtx, data)
 		}, s.opts.MetricsBufferSize/10, s.l)
 	}
 
@@ -201,6 +216,46 @@ func (s *Surfacer) close() {
 	s.topic.Stop()
 }
 
+// Flush publishes the EventMetrics remaining in the input channel, and waits
+// for all the pending publish results, once the input processing loop has
+// stopped, i.e. after the context passed to New is canceled.
+func (s *Surfacer) Flush(ctx context.Context) error {
+	if err := flush.WaitForStop(ctx, s.loopDone); err != nil {
+		return err
+	}
+	if err := flush.WaitForStop(ctx, s.resultsLoopDone); err != nil {
+		return err
+	}
+	defer s.topic.Stop()
+
+	var results []*pubsub.PublishResult
+	dropped := flush.Drain(ctx, s.inChan, func(em *metrics.EventMetrics) {
+		if s.c.GetCompressionEnabled() {
+			s.compressionBuffer.WriteLineToBuffer(em.String())
+			return
+		}
+		results = append(results, s.publish(ctx, []byte(em.String())))
+	})
+	if s.compressionBuffer != nil {
+		s.compressionBuffer.Close()
+	}
+	for n := len(s.publishResultChan); n > 0; n-- {
+		results = append(results, <-s.publishResultChan)
+	}
+
+	failed := 0
+	for _, res := range results {
+		if _, err := res.Get(ctx); err != nil {
+			s.l.Warningf("Error publishing message: %v", err)
+			failed++
+		}
+	}
+	if err := flush.DroppedError(ctx, dropped, "EventMetrics"); err != nil {
+		return err
+	}
+	return flush.DroppedError(ctx, failed, "messages")
+}
+
 // Write queues the incoming data into a channel. This channel is watched by a
 // goroutine that actually publishes it to a pubsub topic.
 func (s *Surfacer) Write(ctx context.Context, em *metrics.EventMetrics) {
@@ -87,8 +87,9 @@ func (s *testServer) Publish(_ context.Context, req *pb.PublishRequest) (*pb.Pub
 }
 
 func TestSurfacer(t *testing.T) {
-	for _, compression := range []bool{false, true} {
-		t.Run(fmt.Sprintf("with_compression=%v", compression), func(t *testing.T) {
+	for _, tc := range []struct{ compression, flush bool }{{false, false}, {true, false}, {false, true}, {true, true}} {
+		compression, flush := tc.compression, tc.flush
+		t.Run(fmt.Sprintf("with_compression=%v,flush=%v", compression, flush), func(t *testing.T) {
 			l, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", 0))
 			if err != nil {
 				t.Fatalf("Error creating listener: %v", err)
@@ -121,18 +122,18 @@ func TestSurfacer(t *testing.T) {
 				return pubsub.NewClient(ctx, project, option.WithGRPCConn(conn))
 			}
 
-			createSurfacerAndVerify(t, srv, compression)
+			createSurfacerAndVerify(t, srv, compression, flush)
 		})
 	}
 }
 
-func createSurfacerAndVerify(t *testing.T, srv *testServer, compression bool) {
+func createSurfacerAndVerify(t *testing.T, srv *testServer, compression, flush bool) {
 	t.Helper()
 
-	//ctx, cancel := context.WithCancel(context.Background())
-	//defer cancel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	s, err := New(context.Background(), &configpb.SurfacerConf{
+	s, err := New(ctx, &configpb.SurfacerConf{
 		Project:            proto.String("test-project"),
 		TopicName:          proto.String("test-topic"),
 		CompressionEnabled: proto.Bool(compression),
@@ -146,14 +147,28 @@ func createSurfacerAndVerify(t *testing.T, srv *testServer, compression bool) {
 		metrics.NewEventMetrics(time.Now()).AddMetric("float-test", metrics.NewInt(123457)),
 	}
 
+	if flush {
+		// Stop the processing loops, so that EventMetrics stay in the input
+		// channel until flushed.
+		cancel()
+		<-s.loopDone
+		<-s.resultsLoopDone
+	}
+
 	var expectedMsgs []string
 	for _, em := range testEM {
 		s.Write(context.Background(), em)
 		expectedMsgs = append(expectedMsgs, em.String())
 	}
 
-	// Closing the surfacer waits for inputs to be processed.
-	s.close()
+	if flush {
+		if err := s.Flush(context.Background()); err != nil {
+			t.Fatalf("Unexpected error while flushing: %v", err)
+		}
+	} else {
+		// Closing the surfacer waits for inputs to be processed.
+		s.close()
+	}
 
 	srv.wg.Wait()
 
@@ -0,0 +1,204 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.21.5
+// source: github.com/cloudprober/cloudprober/surfacers/internal/sqlite/proto/config.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SurfacerConf struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Path to the SQLite database file. It's created if it doesn't exist.
+	FilePath *string `protobuf:"bytes,1,req,name=file_path,json=filePath" json:"file_path,omitempty"`
+	// Metrics table name. Table and an index on its timestamp column are
+	// created automatically, if they don't exist:
+	// CREATE TABLE metrics (
+	//
+	//	timestamp INTEGER NOT NULL, -- Unix time in milliseconds.
+	//	probe TEXT, target TEXT, metric TEXT NOT NULL, value REAL,
+	//	labels TEXT -- Rest of the labels, as a JSON object.
+	//
+	// )
+	TableName *string `protobuf:"bytes,2,opt,name=table_name,json=tableName,def=metrics" json:"table_name,omitempty"`
+	// How long to keep the rows for. Rows older than this are deleted
+	// periodically, every prune_interval_sec. By default, rows are kept
+	// forever.
+	RetentionSec     *int32 `protobuf:"varint,3,opt,name=retention_sec,json=retentionSec" json:"retention_sec,omitempty"`
+	PruneIntervalSec *int32 `protobuf:"varint,4,opt,name=prune_interval_sec,json=pruneIntervalSec,def=3600" json:"prune_interval_sec,omitempty"`
+}
+
+// Default values for SurfacerConf fields.
+const (
+	Default_SurfacerConf_TableName        = string("metrics")
+	Default_SurfacerConf_PruneIntervalSec = int32(3600)
+)
+
+func (x *SurfacerConf) Reset() {
+	*x = SurfacerConf{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SurfacerConf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SurfacerConf) ProtoMessage() {}
+
+func (x *SurfacerConf) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SurfacerConf.ProtoReflect.Descriptor instead.
+func (*SurfacerConf) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SurfacerConf) GetFilePath() string {
+	if x != nil && x.FilePath != nil {
+		return *x.FilePath
+	}
+	return ""
+}
+
+func (x *SurfacerConf) GetTableName() string {
+	if x != nil && x.TableName != nil {
+		return *x.TableName
+	}
+	return Default_SurfacerConf_TableName
+}
+
+func (x *SurfacerConf) GetRetentionSec() int32 {
+	if x != nil && x.RetentionSec != nil {
+		return *x.RetentionSec
+	}
+	return 0
+}
+
+func (x *SurfacerConf) GetPruneIntervalSec() int32 {
+	if x != nil && x.PruneIntervalSec != nil {
+		return *x.PruneIntervalSec
+	}
+	return Default_SurfacerConf_PruneIntervalSec
+}
+
+var File_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto protoreflect.FileDescriptor
+
+var file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_rawDesc = []byte{
+	0x0a, 0x4f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x73, 0x2f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x73, 0x71, 0x6c, 0x69, 0x74, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x1b, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x73,
+	0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x2e, 0x73, 0x71, 0x6c, 0x69, 0x74, 0x65, 0x22, 0xac,
+	0x01, 0x0a, 0x0c, 0x53, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x12,
+	0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x02,
+	0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x26, 0x0a, 0x0a,
+	0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x3a, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x09, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x72, 0x65, 0x74,
+	0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x63, 0x12, 0x32, 0x0a, 0x12, 0x70, 0x72, 0x75,
+	0x6e, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x63, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x04, 0x33, 0x36, 0x30, 0x30, 0x52, 0x10, 0x70, 0x72, 0x75,
+	0x6e, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x65, 0x63, 0x42, 0x44, 0x5a,
+	0x42, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75,
+	0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f,
+	0x62, 0x65, 0x72, 0x2f, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x72, 0x73, 0x2f, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x73, 0x71, 0x6c, 0x69, 0x74, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f,
+}
+
+var (
+	file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_rawDescOnce sync.Once
+	file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_rawDescData = file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_rawDesc
+)
+
+func file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_rawDescGZIP() []byte {
+	file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_rawDescOnce.Do(func() {
+		file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_rawDescData)
+	})
+	return file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_rawDescData
+}
+
+var file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_goTypes = []interface{}{
+	(*SurfacerConf)(nil), // 0: cloudprober.surfacer.sqlite.SurfacerConf
+}
+var file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() {
+	file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_init()
+}
+func file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_init() {
+	if File_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SurfacerConf); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_goTypes,
+		DependencyIndexes: file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_depIdxs,
+		MessageInfos:      file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_msgTypes,
+	}.Build()
+	File_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto = out.File
+	file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_rawDesc = nil
+	file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_goTypes = nil
+	file_github_com_cloudprober_cloudprober_surfacers_internal_sqlite_proto_config_proto_depIdxs = nil
+}
@@ -0,0 +1,21 @@
+package proto
+
+#SurfacerConf: {
+	// Path to the SQLite database file. It's created if it doesn't exist.
+	filePath?: string @protobuf(1,string,name=file_path)
+
+	// Metrics table name. Table and an index on its timestamp column are
+	// created automatically, if they don't exist:
+	// CREATE TABLE metrics (
+	//   timestamp INTEGER NOT NULL, -- Unix time in milliseconds.
+	//   probe TEXT, target TEXT, metric TEXT NOT NULL, value REAL,
+	//   labels TEXT -- Rest of the labels, as a JSON object.
+	// )
+	tableName?: string @protobuf(2,string,name=table_name,#"default="metrics""#)
+
+	// How long to keep the rows for. Rows older than this are deleted
+	// periodically, every prune_interval_sec. By default, rows are kept
+	// forever.
+	retentionSec?:     int32 @protobuf(3,int32,name=retention_sec)
+	pruneIntervalSec?: int32 @protobuf(4,int32,name=prune_interval_sec,"default=3600")
+}
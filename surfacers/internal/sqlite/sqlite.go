@@ -0,0 +1,310 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build sqlite
+
+/*
+Package sqlite implements the "sqlite" surfacer. It writes probe results to
+a local SQLite database, e.g. on edge devices with intermittent connectivity,
+where results can be synced later. It's included only in the cloudprober
+builds with the "sqlite" build tag, as it uses the pure-Go SQLite driver
+(modernc.org/sqlite), which adds a number of modules to the dependencies.
+
+To use this surfacer, add a stanza similar to the following to your
+cloudprober config:
+
+	surfacer {
+	  type: SQLITE
+	  sqlite_surfacer {
+	    file_path: "/var/lib/cloudprober/metrics.db"
+	    retention_sec: 604800  # 7 days
+	  }
+	}
+*/
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/flush"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/options"
+	configpb "github.com/cloudprober/cloudprober/surfacers/internal/sqlite/proto"
+
+	// Registers the "sqlite" database/sql driver.
+	_ "modernc.org/sqlite"
+)
+
+var tableNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// row represents a single metric and corresponds to a single row in the
+// metrics table.
+type row struct {
+	timestamp     time.Time
+	probe, target string
+	metric        string
+	value         float64
+	labels        map[string]string
+}
+
+func withLabel(labels map[string]string, k, v string) map[string]string {
+	labelsCopy := make(map[string]string, len(labels)+1)
+	for lk, lv := range labels {
+		labelsCopy[lk] = lv
+	}
+	labelsCopy[k] = v
+	return labelsCopy
+}
+
+// emToRows converts an EventMetrics into rows. Map metrics result in a row
+// for each map key, with the key added to the labels; distributions result
+// in Prometheus style _sum, _count and _bucket rows; and string metrics are
+// converted to a row with value 1 and the string in the "val" label.
+func (s *Surfacer) emToRows(em *metrics.EventMetrics) []row {
+	base := row{timestamp: em.Timestamp, labels: make(map[string]string)}
+	for _, k := range em.LabelsKeys() {
+		switch k {
+		case "probe":
+			base.probe = em.Label(k)
+		case "dst":
+			base.target = em.Label(k)
+		default:
+			base.labels[k] = em.Label(k)
+		}
+	}
+	newRow := func(metric string, value float64, labels map[string]string) row {
+		r := base
+		r.metric, r.value, r.labels = metric, value, labels
+		return r
+	}
+
+	var rows []row
+	for _, metricName := range em.MetricsKeys() {
+		if !s.opts.AllowMetric(metricName) {
+			continue
+		}
+
+		switch val := em.Metric(metricName).(type) {
+		case *metrics.Map[int64]:
+			for _, k := range val.Keys() {
+				rows = append(rows, newRow(metricName, float64(val.GetKey(k)), withLabel(base.labels, val.MapName, k)))
+			}
+		case *metrics.Map[float64]:
+			for _, k := range val.Keys() {
+				rows = append(rows, newRow(metricName, val.GetKey(k), withLabel(base.labels, val.MapName, k)))
+			}
+		case *metrics.Distribution:
+			d := val.Data()
+			rows = append(rows, newRow(metricName+"_sum", d.Sum, base.labels), newRow(metricName+"_count", float64(d.Count), base.labels))
+			var count int64
+			for i := range d.LowerBounds {
+				count += d.BucketCounts[i]
+				le := "+Inf"
+				if i < len(d.LowerBounds)-1 {
+					le = strconv.FormatFloat(d.LowerBounds[i+1], 'f', -1, 64)
+				}
+				rows = append(rows, newRow(metricName+"_bucket", float64(count), withLabel(base.labels, "le", le)))
+			}
+		case metrics.String:
+			rows = append(rows, newRow(metricName, 1, withLabel(base.labels, "val", strings.Trim(val.String(), "\""))))
+		case metrics.NumValue:
+			rows = append(rows, newRow(metricName, val.Float64(), base.labels))
+		default:
+			s.l.Warningf("Unsupported value type for metric %s: %T", metricName, val)
+		}
+	}
+	return rows
+}
+
+// Surfacer implements the SQLite surfacer.
+type Surfacer struct {
+	c    *configpb.SurfacerConf
+	opts *options.Options
+	l    *logger.Logger
+
+	db         *sql.DB
+	insertStmt string
+
+	writeChan chan *metrics.EventMetrics
+	loopDone  chan struct{}
+}
+
+// New initializes a SQLite surfacer. It creates the metrics table and its
+// index, if they don't exist already.
+func New(ctx context.Context, config *configpb.SurfacerConf, opts *options.Options, l *logger.Logger) (*Surfacer, error) {
+	s := &Surfacer{
+		c:    config,
+		opts: opts,
+		l:    l,
+	}
+	return s, s.init(ctx)
+}
+
+func (s *Surfacer) init(ctx context.Context) error {
+	table := s.c.GetTableName()
+	if !tableNameRe.MatchString(table) {
+		return fmt.Errorf("sqlite: invalid table_name (%s)", table)
+	}
+	if s.c.GetRetentionSec() < 0 {
+		return fmt.Errorf("sqlite: retention_sec (%d) cannot be negative", s.c.GetRetentionSec())
+	}
+	if s.c.GetRetentionSec() > 0 && s.c.GetPruneIntervalSec() <= 0 {
+		return fmt.Errorf("sqlite: prune_interval_sec (%d) should be positive", s.c.GetPruneIntervalSec())
+	}
+
+	db, err := sql.Open("sqlite", s.c.GetFilePath())
+	if err != nil {
+		return fmt.Errorf("sqlite: error opening database (%s): %v", s.c.GetFilePath(), err)
+	}
+	// SQLite allows only one writer at a time.
+	db.SetMaxOpenConns(1)
+	s.db = db
+
+	if err := s.createTable(ctx); err != nil {
+		db.Close()
+		return err
+	}
+	s.insertStmt = fmt.Sprintf("INSERT INTO %s (timestamp, probe, target, metric, value, labels) VALUES (?, ?, ?, ?, ?, ?)", table)
+
+	if s.c.GetRetentionSec() > 0 {
+		s.prune(ctx, time.Now())
+	}
+
+	s.writeChan = make(chan *metrics.EventMetrics, s.opts.MetricsBufferSize)
+
+	// Write loop. Database is closed by Flush, after writing the remaining
+	// metrics.
+	s.loopDone = make(chan struct{})
+	go func() {
+		defer close(s.loopDone)
+
+		var pruneC <-chan time.Time
+		if s.c.GetRetentionSec() > 0 {
+			ticker := time.NewTicker(time.Duration(s.c.GetPruneIntervalSec()) * time.Second)
+			defer ticker.Stop()
+			pruneC = ticker.C
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				s.l.Infof("Context canceled, stopping the surfacer write loop")
+				return
+			case em := <-s.writeChan:
+				s.write(context.WithoutCancel(ctx), em)
+			case now := <-pruneC:
+				s.prune(ctx, now)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (s *Surfacer) createTable(ctx context.Context) error {
+	table := s.c.GetTableName()
+	for _, stmt := range []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (timestamp INTEGER NOT NULL, probe TEXT, target TEXT, metric TEXT NOT NULL, value REAL, labels TEXT)", table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_timestamp_idx ON %s (timestamp)", table, table),
+	} {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("sqlite: error creating table %s: %v", table, err)
+		}
+	}
+	return nil
+}
+
+// prune deletes the rows older than the retention period.
+func (s *Surfacer) prune(ctx context.Context, now time.Time) {
+	cutoff := now.Add(-time.Duration(s.c.GetRetentionSec()) * time.Second)
+	res, err := s.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE timestamp < ?", s.c.GetTableName()), cutoff.UnixMilli())
+	if err != nil {
+		s.l.Warningf("Error while pruning old rows: %v", err)
+		return
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		s.l.Infof("Pruned %d rows older than %s", n, cutoff)
+	}
+}
+
+// writeMetrics inserts all the rows for the EventMetrics in a single
+// transaction.
+func (s *Surfacer) writeMetrics(ctx context.Context, em *metrics.EventMetrics) error {
+	rows := s.emToRows(em)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	txn, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
+	stmt, err := txn.PrepareContext(ctx, s.insertStmt)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, r := range rows {
+		labels, err := json.Marshal(r.labels)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, r.timestamp.UnixMilli(), r.probe, r.target, r.metric, r.value, string(labels)); err != nil {
+			return err
+		}
+	}
+	return txn.Commit()
+}
+
+func (s *Surfacer) write(ctx context.Context, em *metrics.EventMetrics) {
+	if em.Kind != metrics.CUMULATIVE && em.Kind != metrics.GAUGE {
+		return
+	}
+	if err := s.writeMetrics(ctx, em); err != nil {
+		s.l.Warningf("Error while writing metrics: %v", err)
+	}
+}
+
+// Write queues the EventMetrics to be written to the database.
+func (s *Surfacer) Write(ctx context.Context, em *metrics.EventMetrics) {
+	select {
+	case s.writeChan <- em:
+	default:
+		s.l.Errorf("Surfacer's write channel is full, dropping new data.")
+	}
+}
+
+// Flush writes the EventMetrics remaining in the write channel and closes the
+// database, once the write loop has stopped, i.e. after the context passed to
+// New is canceled.
+func (s *Surfacer) Flush(ctx context.Context) error {
+	if err := flush.WaitForStop(ctx, s.loopDone); err != nil {
+		return err
+	}
+	defer s.db.Close()
+	return flush.DroppedError(ctx, flush.Drain(ctx, s.writeChan, func(em *metrics.EventMetrics) {
+		s.write(ctx, em)
+	}), "EventMetrics")
+}
@@ -0,0 +1,175 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/config/runconfig"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/options"
+	configpb "github.com/cloudprober/cloudprober/surfacers/internal/sqlite/proto"
+	surfacerpb "github.com/cloudprober/cloudprober/surfacers/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+type testRow struct {
+	timestamp     int64
+	probe, target string
+	metric        string
+	value         float64
+	labels        string
+}
+
+func readRows(t *testing.T, dbFile, table string) []testRow {
+	t.Helper()
+	db, err := sql.Open("sqlite", dbFile)
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows, err := db.Query("SELECT timestamp, probe, target, metric, value, labels FROM " + table + " ORDER BY rowid")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var out []testRow
+	for rows.Next() {
+		var r testRow
+		require.NoError(t, rows.Scan(&r.timestamp, &r.probe, &r.target, &r.metric, &r.value, &r.labels))
+		out = append(out, r)
+	}
+	require.NoError(t, rows.Err())
+	return out
+}
+
+func testEM(ts time.Time) *metrics.EventMetrics {
+	respCodes := metrics.NewMap("code")
+	respCodes.IncKeyBy("200", 19)
+	latency := metrics.NewDistribution([]float64{1, 4})
+	latency.AddSample(0.5)
+	latency.AddSample(5)
+
+	return metrics.NewEventMetrics(ts).
+		AddMetric("total", metrics.NewInt(20)).
+		AddMetric("resp_code", respCodes).
+		AddMetric("latency", latency).
+		AddMetric("version", metrics.NewString("v1")).
+		AddLabel("ptype", "http").
+		AddLabel("probe", "p1").
+		AddLabel("dst", "t1")
+}
+
+func TestSurfacer(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "metrics.db")
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s, err := New(ctx, &configpb.SurfacerConf{FilePath: proto.String(dbFile)}, &options.Options{MetricsBufferSize: 10}, nil)
+	require.NoError(t, err)
+
+	ts := time.Now()
+	s.Write(ctx, testEM(ts))
+	cancel()
+	require.NoError(t, s.Flush(context.Background()))
+
+	msec := ts.UnixMilli()
+	want := []testRow{
+		{msec, "p1", "t1", "total", 20, `{"ptype":"http"}`},
+		{msec, "p1", "t1", "resp_code", 19, `{"code":"200","ptype":"http"}`},
+		{msec, "p1", "t1", "latency_sum", 5.5, `{"ptype":"http"}`},
+		{msec, "p1", "t1", "latency_count", 2, `{"ptype":"http"}`},
+		{msec, "p1", "t1", "latency_bucket", 1, `{"le":"1","ptype":"http"}`},
+		{msec, "p1", "t1", "latency_bucket", 1, `{"le":"4","ptype":"http"}`},
+		{msec, "p1", "t1", "latency_bucket", 2, `{"le":"+Inf","ptype":"http"}`},
+		{msec, "p1", "t1", "version", 1, `{"ptype":"http","val":"v1"}`},
+	}
+	assert.Equal(t, want, readRows(t, dbFile, "metrics"))
+}
+
+func TestSurfacerMetricsFilter(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "metrics.db")
+	ctx, cancel := context.WithCancel(context.Background())
+
+	runconfig.SetDefaultHTTPServeMux(http.NewServeMux())
+	opts, err := options.BuildOptionsFromConfig(&surfacerpb.SurfacerDef{
+		AllowMetricsWithName: proto.String("^total$"),
+	}, nil)
+	require.NoError(t, err)
+	s, err := New(ctx, &configpb.SurfacerConf{FilePath: proto.String(dbFile), TableName: proto.String("probe_results")}, opts, nil)
+	require.NoError(t, err)
+
+	s.Write(ctx, testEM(time.Now()))
+	cancel()
+	require.NoError(t, s.Flush(context.Background()))
+
+	rows := readRows(t, dbFile, "probe_results")
+	require.Len(t, rows, 1)
+	assert.Equal(t, "total", rows[0].metric)
+}
+
+func TestPrune(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "metrics.db")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s, err := New(ctx, &configpb.SurfacerConf{
+		FilePath:     proto.String(dbFile),
+		RetentionSec: proto.Int32(3600),
+	}, &options.Options{MetricsBufferSize: 10}, nil)
+	require.NoError(t, err)
+
+	now := time.Now()
+	for _, ts := range []time.Time{now.Add(-2 * time.Hour), now.Add(-30 * time.Minute), now} {
+		em := metrics.NewEventMetrics(ts).AddMetric("total", metrics.NewInt(1))
+		require.NoError(t, s.writeMetrics(ctx, em))
+	}
+	s.prune(ctx, now)
+
+	rows := readRows(t, dbFile, "metrics")
+	require.Len(t, rows, 2)
+	assert.Equal(t, now.Add(-30*time.Minute).UnixMilli(), rows[0].timestamp)
+	assert.Equal(t, now.UnixMilli(), rows[1].timestamp)
+}
+
+func TestNewErrors(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "metrics.db")
+	for desc, c := range map[string]*configpb.SurfacerConf{
+		"invalid_table_name": {
+			FilePath:  proto.String(dbFile),
+			TableName: proto.String("metrics; DROP TABLE metrics"),
+		},
+		"negative_retention": {
+			FilePath:     proto.String(dbFile),
+			RetentionSec: proto.Int32(-1),
+		},
+		"invalid_prune_interval": {
+			FilePath:         proto.String(dbFile),
+			RetentionSec:     proto.Int32(60),
+			PruneIntervalSec: proto.Int32(0),
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			_, err := New(context.Background(), c, &options.Options{MetricsBufferSize: 10}, nil)
+			assert.Error(t, err)
+		})
+	}
+}
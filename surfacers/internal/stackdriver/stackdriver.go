@@ -35,6 +35,7 @@ import (
 	"google.golang.org/api/option"
 
 	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/flush"
 	"github.com/cloudprober/cloudprober/surfacers/internal/common/options"
 	configpb "github.com/cloudprober/cloudprober/surfacers/internal/stackdriver/proto"
 )
@@ -63,6 +64,7 @@ type SDSurfacer struct {
 
 	// Channel for writing the data without blocking
 	writeChan chan *metrics.EventMetrics
+	loopDone  chan struct{}
 
 	// VM Information
 	onGCE       bool
@@ -92,6 +94,7 @@ func New(ctx context.Context, config *configpb.SurfacerConf, opts *options.Optio
 		cache:        make(map[string]*monitoring.TimeSeries),
 		knownMetrics: make(map[string]bool),
 		writeChan:    make(chan *metrics.EventMetrics, config.GetMetricsBufferSize()),
+		loopDone:     make(chan struct{}),
 		c:            config,
 		opts:         opts,
 		projectName:  config.GetProject(),
@@ -201,10 +204,16 @@ func (s *SDSurfacer) createMetricDescriptor(ts *monitoring.TimeSeries) error {
 // writeBatch is set up to run as an infinite goroutine call in the New function
 // to allow it to write asynchronously to Stack Driver.
 func (s *SDSurfacer) writeBatch(ctx context.Context) {
+	defer close(s.loopDone)
+
 	// Introduce a random delay before starting the loop.
 	rand.Seed(time.Now().UnixNano())
 	randomDelay := time.Duration(rand.Int63n(int64(s.c.GetBatchTimerSec()))) * time.Second
-	time.Sleep(randomDelay)
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(randomDelay):
+	}
 
 	batchTicker := time.NewTicker(time.Duration(s.c.GetBatchTimerSec()) * time.Second)
 	for {
@@ -218,52 +227,77 @@ func (s *SDSurfacer) writeBatch(ctx context.Context) {
 			// objects.
 			s.recordEventMetrics(em)
 		case <-batchTicker.C:
-			// Empty time series writes cause an error to be returned, so
-			// we skip any calls that write but wouldn't set any data.
-			if len(s.cache) == 0 {
-				break
-			}
+			s.writeCache(ctx)
+		}
+	}
+}
 
-			var ts []*monitoring.TimeSeries
-			for _, v := range s.cache {
-				if !s.knownMetrics[v.Metric.Type] && v.Unit != "" {
-					if err := s.createMetricDescriptor(v); err != nil {
-						s.l.Warningf("Error creating metric descriptor for: %s, err: %v", v.Metric.Type, err)
-						continue
-					}
-					s.knownMetrics[v.Metric.Type] = true
-				}
-				ts = append(ts, v)
-			}
+// writeCache writes the cached time series to stackdriver, and clears the
+// cache. It returns the number of time series that couldn't be written.
+func (s *SDSurfacer) writeCache(ctx context.Context) int {
+	// Empty time series writes cause an error to be returned, so
+	// we skip any calls that write but wouldn't set any data.
+	if len(s.cache) == 0 {
+		return 0
+	}
 
-			// We batch the time series into appropriately-sized sets
-			// and write them
-			for i := 0; i < len(ts); i += batchSize {
-				endIndex := min(len(ts), i+batchSize)
-
-				s.l.Infof("Sending entries %d through %d of %d", i, endIndex, len(ts))
-
-				// Now that we've created the new metric, we can write the data. Making
-				// a time series create call will automatically register a new metric
-				// with the correct information if it does not already exist.
-				// Ref: https://cloud.google.com/monitoring/custom-metrics/creating-metrics#auto-creation
-				requestBody := monitoring.CreateTimeSeriesRequest{
-					TimeSeries: ts[i:endIndex],
-				}
-				if _, err := s.client.Projects.TimeSeries.Create("projects/"+s.projectName, &requestBody).Do(); err != nil {
-					s.failCnt++
-					s.l.Warningf("Unable to fulfill TimeSeries Create call. Err: %v", err)
-				}
+	var ts []*monitoring.TimeSeries
+	for _, v := range s.cache {
+		if !s.knownMetrics[v.Metric.Type] && v.Unit != "" {
+			if err := s.createMetricDescriptor(v); err != nil {
+				s.l.Warningf("Error creating metric descriptor for: %s, err: %v", v.Metric.Type, err)
+				continue
 			}
+			s.knownMetrics[v.Metric.Type] = true
+		}
+		ts = append(ts, v)
+	}
+	failed := len(s.cache) - len(ts)
 
-			// Flush the cache after we've finished writing so we don't accidentally
-			// re-write metric values that haven't been written over several write
-			// cycles.
-			for k := range s.cache {
-				delete(s.cache, k)
-			}
+	// We batch the time series into appropriately-sized sets
+	// and write them
+	for i := 0; i < len(ts); i += batchSize {
+		endIndex := min(len(ts), i+batchSize)
+
+		s.l.Infof("Sending entries %d through %d of %d", i, endIndex, len(ts))
+
+		// Now that we've created the new metric, we can write the data. Making
+		// a time series create call will automatically register a new metric
+		// with the correct information if it does not already exist.
+		// Ref: https://cloud.google.com/monitoring/custom-metrics/creating-metrics#auto-creation
+		requestBody := monitoring.CreateTimeSeriesRequest{
+			TimeSeries: ts[i:endIndex],
+		}
+		if _, err := s.client.Projects.TimeSeries.Create("projects/"+s.projectName, &requestBody).Context(ctx).Do(); err != nil {
+			s.failCnt++
+			failed += endIndex - i
+			s.l.Warningf("Unable to fulfill TimeSeries Create call. Err: %v", err)
 		}
 	}
+
+	// Flush the cache after we've finished writing so we don't accidentally
+	// re-write metric values that haven't been written over several write
+	// cycles.
+	for k := range s.cache {
+		delete(s.cache, k)
+	}
+	return failed
+}
+
+// Flush writes the EventMetrics remaining in the write channel, along with
+// the cached time series, once the batch write loop has stopped, i.e. after
+// the context passed to New is canceled.
+func (s *SDSurfacer) Flush(ctx context.Context) error {
+	if err := flush.WaitForStop(ctx, s.loopDone); err != nil {
+		return err
+	}
+	dropped := flush.Drain(ctx, s.writeChan, func(em *metrics.EventMetrics) {
+		s.recordEventMetrics(em)
+	})
+	if err := flush.DroppedError(ctx, dropped, "EventMetrics"); err != nil {
+		return err
+	}
+	return flush.DroppedError(ctx, s.writeCache(ctx), "time series")
 }
 
 //-----------------------------------------------------------------------------
@@ -10,6 +10,8 @@ import (
 	proto_B "github.com/cloudprober/cloudprober/surfacers/internal/datadog/proto"
 	proto_36 "github.com/cloudprober/cloudprober/surfacers/internal/probestatus/proto"
 	proto_9 "github.com/cloudprober/cloudprober/surfacers/internal/bigquery/proto"
+	proto_3 "github.com/cloudprober/cloudprober/surfacers/internal/sqlite/proto"
+	proto_A2 "github.com/cloudprober/cloudprober/surfacers/internal/otel/proto"
 )
 
 // Enumeration for each type of surfacer we can parse and create
@@ -28,6 +30,8 @@ import (
 		"PROBESTATUS"// Experimental mode.
 					#enumValue: 8
 	} | {"BIGQUERY", #enumValue: 9} |
+	{"SQLITE", #enumValue: 10} |
+	{"OTEL", #enumValue: 11} |
 	{"USER_DEFINED", #enumValue: 99}
 
 #Type_value: {
@@ -41,6 +45,8 @@ import (
 	DATADOG:      7
 	PROBESTATUS:  8
 	BIGQUERY:     9
+	SQLITE:       10
+	OTEL:         11
 	USER_DEFINED: 99
 }
 
@@ -49,6 +55,17 @@ import (
 	value?: string @protobuf(2,string)
 }
 
+#RateMetric: {
+	// Cumulative metric to compute the rate for, e.g. "total".
+	metricName?: string @protobuf(1,string,name=metric_name)
+
+	// Name of the rate metric. Default is <metric_name>_per_second.
+	rateMetricName?: string @protobuf(2,string,name=rate_metric_name)
+
+	// Window over which the per-second rate is computed.
+	windowSec?: int32 @protobuf(3,int32,name=window_sec,"default=60")
+}
+
 #SurfacerDef: {
 	// This name is used for logging. If not defined, it's derived from the type.
 	// Note that this field is required for the USER_DEFINED surfacer type and
@@ -107,6 +124,74 @@ import (
 	// However, it should not be noticeable unless you're producing large number
 	// of metrics (say > 10000 metrics per second).
 	exportAsGauge?: bool @protobuf(9,bool,name=export_as_gauge)
+
+	// If set to true, per-target metrics (metrics with a "dst" label) are
+	// exported only for failing targets, i.e. targets that had failures since
+	// their last export. When a target recovers, one final set of metrics is
+	// exported for it with an additional "recovered" metric set to 1, after
+	// which its metrics are skipped again.
+	// To retain the overall success rate, "aggregate_total" and
+	// "aggregate_success" metrics are exported for each probe, labeled only
+	// with "ptype" and "probe".
+	// This is useful to reduce metrics volume when probing a very large number
+	// of targets.
+	exportOnlyFailingTargets?: bool @protobuf(19,bool,name=export_only_failing_targets)
+
+	// Rate metrics to compute in-process, for the systems that can't compute
+	// rates from cumulative counters themselves. Rate metrics are exported
+	// alongside the original metrics, and only for the configured metrics, to
+	// avoid increasing the metrics volume unnecessarily.
+	// Example:
+	// rate_metric {
+	//   metric_name: "total"
+	//   rate_metric_name: "requests_per_second"
+	//   window_sec: 300
+	// }
+	//
+	// Note that these rates are less accurate than the rates computed by the
+	// monitoring backends: they are computed from the samples that surfacer
+	// sees, i.e. at the stats export interval, so window effectively gets
+	// rounded to the stats export interval, and there is no rate until the
+	// second sample. Rates also start fresh after a restart or counter reset,
+	// and are not aggregatable across instances like counters are.
+	rateMetric?: [...#RateMetric] @protobuf(20,RateMetric,name=rate_metric)
+
+	// How long to wait for the surfacer to export its buffered metrics during
+	// graceful shutdown (on SIGINT or SIGTERM). Metrics that can't be exported
+	// in time are dropped, and their count is logged. Note that the process
+	// exits after stop_time_sec (default 5s) irrespective of this deadline, so
+	// keep it lower than that.
+	flushTimeoutMsec?: int32 @protobuf(21,int32,name=flush_timeout_msec,"default=3000")
+
+	// If set, metrics are logged (at info level) instead of being exported,
+	// and the surfacer itself is not initialized, i.e. it doesn't connect to
+	// its backend or use its credentials. Logged metrics are the ones that
+	// surfacer would receive, i.e. after the filtering and transformations
+	// configured above. PROMETHEUS surfacers log the metrics in the format
+	// they would serve them in; other surfacers log the EventMetrics as they
+	// are. Values of the labels that look like secrets (e.g. "token" or
+	// "api_key"), and secret-like parameters in the values (e.g. "token=..."
+	// in a URL), are redacted.
+	// It's useful to validate the metrics and their labels before enabling a
+	// new surfacer.
+	dryRun?: bool @protobuf(22,bool,name=dry_run)
+
+	// If set, timestamps of the EventMetrics are truncated to this granularity
+	// before export, e.g. set it to 1000 for the backends that reject
+	// sub-second timestamps. Since truncation is applied to all the metrics
+	// going through the surfacer, metrics exported together share the aligned
+	// timestamps.
+	timestampGranularityMsec?: int32 @protobuf(24,int32,name=timestamp_granularity_msec)
+
+	// If set to true, an EventMetrics is not exported if it's identical (same
+	// labels and metric values) to the last exported EventMetrics for the same
+	// series, e.g. for the probes that report an unchanged state every
+	// interval. Identical EventMetrics are still exported every
+	// dedup_max_suppression_sec, to guarantee a periodic refresh.
+	// Note that cumulative counters, e.g. total, change with every probe run,
+	// so it's mostly useful for the gauge metrics.
+	dedupMetrics?:           bool  @protobuf(25,bool,name=dedup_metrics)
+	dedupMaxSuppressionSec?: int32 @protobuf(26,int32,name=dedup_max_suppression_sec,"default=300")
 	// Matching surfacer specific configuration (one for each type in the above
 	// enum)
 	{} | {
@@ -127,5 +212,9 @@ import (
 		probestatusSurfacer: proto_36.#SurfacerConf @protobuf(17,probestatus.SurfacerConf,name=probestatus_surfacer)
 	} | {
 		bigquerySurfacer: proto_9.#SurfacerConf @protobuf(18,bigquery.SurfacerConf,name=bigquery_surfacer)
+	} | {
+		sqliteSurfacer: proto_3.#SurfacerConf @protobuf(23,sqlite.SurfacerConf,name=sqlite_surfacer)
+	} | {
+		otelSurfacer: proto_A2.#SurfacerConf @protobuf(27,otel.SurfacerConf,name=otel_surfacer)
 	}
 }
@@ -0,0 +1,37 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// To build sqlite surfacer support, use '-tags sqlite' flag.
+//go:build sqlite
+
+package surfacers
+
+import (
+	"context"
+
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/options"
+	"github.com/cloudprober/cloudprober/surfacers/internal/sqlite"
+	surfacerpb "github.com/cloudprober/cloudprober/surfacers/proto"
+)
+
+func init() {
+	newSQLiteSurfacer = func(ctx context.Context, s *surfacerpb.SurfacerDef, opts *options.Options, l *logger.Logger) (Surfacer, error) {
+		surfacer, err := sqlite.New(ctx, s.GetSqliteSurfacer(), opts, l)
+		if err != nil {
+			return nil, err
+		}
+		return surfacer, nil
+	}
+}
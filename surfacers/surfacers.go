@@ -25,11 +25,13 @@ package surfacers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"html/template"
 	"log/slog"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
@@ -39,6 +41,7 @@ import (
 	"github.com/cloudprober/cloudprober/surfacers/internal/common/transform"
 	"github.com/cloudprober/cloudprober/surfacers/internal/datadog"
 	"github.com/cloudprober/cloudprober/surfacers/internal/file"
+	"github.com/cloudprober/cloudprober/surfacers/internal/otel"
 	"github.com/cloudprober/cloudprober/surfacers/internal/postgres"
 	"github.com/cloudprober/cloudprober/surfacers/internal/probestatus"
 	"github.com/cloudprober/cloudprober/surfacers/internal/prometheus"
@@ -54,6 +57,11 @@ var (
 	userDefinedSurfacersMu sync.Mutex
 )
 
+// newSQLiteSurfacer creates the sqlite surfacer. It's set only if the sqlite
+// surfacer is included in the build (see sqlite.go), as its pure-Go SQLite
+// driver adds a sizable dependency.
+var newSQLiteSurfacer func(context.Context, *surfacerpb.SurfacerDef, *options.Options, *logger.Logger) (Surfacer, error)
+
 // StatusTmpl variable stores the HTML template suitable to generate the
 // surfacers' status for cloudprober's /status page. It expects an array of
 // SurfacerInfo objects as input.
@@ -105,13 +113,37 @@ type Surfacer interface {
 	Write(ctx context.Context, em *metrics.EventMetrics)
 }
 
+// Flusher is implemented by the surfacers that buffer EventMetrics before
+// exporting them. Flush is called during graceful shutdown, after the context
+// passed to the surfacer at the creation time is canceled, to export the
+// buffered EventMetrics. It should return once done, or when ctx is done,
+// reporting the dropped metrics in the returned error. All built-in surfacers
+// implement Flusher; user defined surfacers may implement it as well.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
 type surfacerWrapper struct {
 	Surfacer
-	opts    *options.Options
-	lvCache map[string]*metrics.EventMetrics
+	opts          *options.Options
+	lvCache       map[string]*metrics.EventMetrics
+	failingFilter *transform.FailingTargetsFilter
+	rateCalc      *transform.RateCalculator
+	dedup         *transform.Deduplicator
+	tsGranularity time.Duration
+
+	// noMetricPrefix is set for the surfacers that look up the probes' metrics
+	// by their names, e.g. probestatus.
+	noMetricPrefix bool
 }
 
 func (sw *surfacerWrapper) Write(ctx context.Context, em *metrics.EventMetrics) {
+	sw.writeWithPrefix(ctx, em, "")
+}
+
+// writeWithPrefix processes the EventMetrics, and writes them to the
+// surfacer with the metric names prefixed by the given prefix.
+func (sw *surfacerWrapper) writeWithPrefix(ctx context.Context, em *metrics.EventMetrics, prefix string) {
 	if !sw.opts.AllowEventMetrics(em) {
 		return
 	}
@@ -122,6 +154,23 @@ func (sw *surfacerWrapper) Write(ctx context.Context, em *metrics.EventMetrics)
 		}
 	}
 
+	if sw.failingFilter == nil {
+		sw.write(ctx, em, prefix)
+		return
+	}
+	for _, outEM := range sw.failingFilter.Process(em) {
+		sw.write(ctx, outEM, prefix)
+	}
+}
+
+func (sw *surfacerWrapper) write(ctx context.Context, em *metrics.EventMetrics, prefix string) {
+	// Rates are computed from the cumulative values, i.e. before the gauge
+	// conversion below.
+	var rates []transform.Rate
+	if sw.rateCalc != nil {
+		rates = sw.rateCalc.Rates(em)
+	}
+
 	if sw.opts.Config.GetExportAsGauge() && em.Kind == metrics.CUMULATIVE {
 		newEM, err := transform.CumulativeToGauge(em, sw.lvCache, sw.opts.Logger)
 		if err != nil {
@@ -131,15 +180,130 @@ func (sw *surfacerWrapper) Write(ctx context.Context, em *metrics.EventMetrics)
 		em = newEM
 	}
 
+	if sw.tsGranularity > 0 {
+		// Clone as EventMetrics are shared across surfacers.
+		em = em.Clone()
+		em.Timestamp = em.Timestamp.Truncate(sw.tsGranularity)
+	}
+	sw.writeEM(ctx, em, prefix)
+
+	// Rates are gauges, so we write them in their own EventMetrics, instead
+	// of mixing them with the cumulative metrics.
+	if len(rates) > 0 {
+		rateEM := metrics.NewEventMetrics(em.Timestamp)
+		rateEM.Kind = metrics.GAUGE
+		for _, k := range em.LabelsKeys() {
+			rateEM.AddLabel(k, em.Label(k))
+		}
+		for _, r := range rates {
+			rateEM.AddMetric(r.Name, metrics.NewFloat(r.Value))
+		}
+		sw.writeEM(ctx, rateEM, prefix)
+	}
+}
+
+// writeEM de-duplicates and prefixes the EventMetrics as configured, and
+// writes them to the underlying surfacer.
+func (sw *surfacerWrapper) writeEM(ctx context.Context, em *metrics.EventMetrics, prefix string) {
+	if sw.dedup != nil && !sw.dedup.Allow(em) {
+		return
+	}
+
+	if prefix != "" && !sw.noMetricPrefix {
+		em = prefixMetrics(em, prefix)
+	}
 	sw.Surfacer.Write(ctx, em)
 }
 
+// prefixMetrics returns a copy of the EventMetrics, with the metric names
+// prefixed by the given prefix.
+func prefixMetrics(em *metrics.EventMetrics, prefix string) *metrics.EventMetrics {
+	newEM := metrics.NewEventMetrics(em.Timestamp)
+	newEM.Kind = em.Kind
+	newEM.LatencyUnit = em.LatencyUnit
+	for _, k := range em.LabelsKeys() {
+		newEM.AddLabel(k, em.Label(k))
+	}
+	for _, k := range em.MetricsKeys() {
+		newEM.AddMetric(prefix+k, em.Metric(k).Clone())
+	}
+	return newEM
+}
+
+// Flush flushes the underlying surfacer, if it implements Flusher, within the
+// configured flush timeout. Errors are logged, besides being returned.
+func (sw *surfacerWrapper) Flush(ctx context.Context) error {
+	f, ok := sw.Surfacer.(Flusher)
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(sw.opts.Config.GetFlushTimeoutMsec())*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := f.Flush(ctx); err != nil {
+		sw.opts.Logger.Warningf("Error flushing surfacer: %v", err)
+		return err
+	}
+	sw.opts.Logger.Infof("Flushed surfacer in %v", time.Since(start))
+	return nil
+}
+
+// Flush flushes all the surfacers concurrently, and returns once they are all
+// done. Surfacers that can't flush within their flush timeout log the number
+// of dropped metrics. Returned error combines the surfacers' flush errors.
+func Flush(ctx context.Context, ss []*SurfacerInfo) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(ss))
+	for i, si := range ss {
+		f, ok := si.Surfacer.(Flusher)
+		if !ok {
+			continue
+		}
+		name := si.Name
+		if name == "" {
+			name = strings.ToLower(si.Type)
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := f.Flush(ctx); err != nil {
+				errs[i] = fmt.Errorf("surfacer %s: %v", name, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
 // SurfacerInfo encapsulates a Surfacer and related info.
 type SurfacerInfo struct {
 	Surfacer
 	Type string
 	Name string
 	Conf string
+
+	// Def is the surfacer's definition. It's also used to compare the
+	// surfacers while updating them.
+	Def *surfacerpb.SurfacerDef
+
+	// cancel stops the surfacer.
+	cancel context.CancelFunc
+}
+
+// WriteWithMetricPrefix writes the EventMetrics to the surfacer, like Write,
+// but with the metric names prefixed by the given prefix. Prefix is added
+// right before the export, so surfacer's own processing, e.g. metrics
+// filtering, failure and rate metrics, works with the unprefixed names.
+func (si *SurfacerInfo) WriteWithMetricPrefix(ctx context.Context, em *metrics.EventMetrics, prefix string) {
+	if sw, ok := si.Surfacer.(*surfacerWrapper); ok {
+		sw.writeWithPrefix(ctx, em, prefix)
+		return
+	}
+	if prefix != "" {
+		em = prefixMetrics(em, prefix)
+	}
+	si.Surfacer.Write(ctx, em)
 }
 
 func inferType(s *surfacerpb.SurfacerDef) surfacerpb.Type {
@@ -162,6 +326,10 @@ func inferType(s *surfacerpb.SurfacerDef) surfacerpb.Type {
 		return surfacerpb.Type_PROBESTATUS
 	case *surfacerpb.SurfacerDef_BigquerySurfacer:
 		return surfacerpb.Type_BIGQUERY
+	case *surfacerpb.SurfacerDef_SqliteSurfacer:
+		return surfacerpb.Type_SQLITE
+	case *surfacerpb.SurfacerDef_OtelSurfacer:
+		return surfacerpb.Type_OTEL
 
 	}
 
@@ -183,9 +351,56 @@ func initSurfacer(ctx context.Context, s *surfacerpb.SurfacerDef, sType surfacer
 		return nil, nil, err
 	}
 
+	if s.GetTimestampGranularityMsec() < 0 {
+		return nil, nil, fmt.Errorf("timestamp_granularity_msec (%d) cannot be negative", s.GetTimestampGranularityMsec())
+	}
+	if s.GetDedupMetrics() && s.GetDedupMaxSuppressionSec() <= 0 {
+		return nil, nil, fmt.Errorf("dedup_max_suppression_sec (%d) should be positive", s.GetDedupMaxSuppressionSec())
+	}
+
 	var conf interface{}
 	var surfacer Surfacer
 
+	if s.GetDryRun() {
+		l.Warningf("Surfacer is in dry_run mode, metrics will be logged instead of being exported.")
+		surfacer, err = newDryRunSurfacer(s, sType, opts, l)
+		if err != nil {
+			return nil, nil, err
+		}
+		conf = s
+	} else {
+		surfacer, conf, err = newSurfacer(ctx, s, sType, opts, l)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	sw := &surfacerWrapper{
+		Surfacer:      surfacer,
+		opts:          opts,
+		lvCache:       make(map[string]*metrics.EventMetrics),
+		tsGranularity: time.Duration(s.GetTimestampGranularityMsec()) * time.Millisecond,
+		// Probe status surfacer reads the probes' total and success metrics.
+		noMetricPrefix: sType == surfacerpb.Type_PROBESTATUS,
+	}
+	if s.GetExportOnlyFailingTargets() {
+		sw.failingFilter = transform.NewFailingTargetsFilter()
+	}
+	if len(s.GetRateMetric()) > 0 {
+		rateCalc, rcErr := transform.NewRateCalculator(s.GetRateMetric())
+		if rcErr != nil {
+			return nil, nil, rcErr
+		}
+		sw.rateCalc = rateCalc
+	}
+	if s.GetDedupMetrics() {
+		sw.dedup = transform.NewDeduplicator(time.Duration(s.GetDedupMaxSuppressionSec()) * time.Second)
+	}
+	return sw, conf, nil
+}
+
+// newSurfacer creates the surfacer of the given type.
+func newSurfacer(ctx context.Context, s *surfacerpb.SurfacerDef, sType surfacerpb.Type, opts *options.Options, l *logger.Logger) (surfacer Surfacer, conf interface{}, err error) {
 	switch sType {
 	case surfacerpb.Type_PROMETHEUS:
 		surfacer, err = prometheus.New(ctx, s.GetPrometheusSurfacer(), opts, l)
@@ -214,6 +429,15 @@ func initSurfacer(ctx context.Context, s *surfacerpb.SurfacerDef, sType surfacer
 	case surfacerpb.Type_BIGQUERY:
 		surfacer, err = bigquery.New(ctx, s.GetBigquerySurfacer(), opts, l)
 		conf = s.GetBigquerySurfacer()
+	case surfacerpb.Type_SQLITE:
+		if newSQLiteSurfacer == nil {
+			return nil, nil, fmt.Errorf("sqlite surfacer is not included in this build, build with '-tags sqlite' to include it")
+		}
+		surfacer, err = newSQLiteSurfacer(ctx, s, opts, l)
+		conf = s.GetSqliteSurfacer()
+	case surfacerpb.Type_OTEL:
+		surfacer, err = otel.New(ctx, s.GetOtelSurfacer(), opts, l)
+		conf = s.GetOtelSurfacer()
 	case surfacerpb.Type_USER_DEFINED:
 		userDefinedSurfacersMu.Lock()
 		defer userDefinedSurfacersMu.Unlock()
@@ -224,17 +448,20 @@ func initSurfacer(ctx context.Context, s *surfacerpb.SurfacerDef, sType surfacer
 	default:
 		return nil, nil, fmt.Errorf("unknown surfacer type: %s", s.GetType())
 	}
+	return surfacer, conf, err
+}
 
-	return &surfacerWrapper{
-		Surfacer: surfacer,
-		opts:     opts,
-		lvCache:  make(map[string]*metrics.EventMetrics),
-	}, conf, err
+// typedSurfacerDef is a surfacer definition along with its effective type.
+type typedSurfacerDef struct {
+	def      *surfacerpb.SurfacerDef
+	sType    surfacerpb.Type
+	required bool
 }
 
-// Init initializes the surfacers from the config protobufs and returns them as
-// a list.
-func Init(ctx context.Context, sDefs []*surfacerpb.SurfacerDef) ([]*SurfacerInfo, error) {
+// effectiveDefs returns the definitions of the surfacers to run for the given
+// surfacer definitions: defined surfacers (or default surfacers if none are
+// defined), followed by the required surfacers that are not defined.
+func effectiveDefs(sDefs []*surfacerpb.SurfacerDef) []typedSurfacerDef {
 	// If no surfacers are defined, return default surfacers. This behavior
 	// can be disabled by explicitly specifying "surfacer {}" in the config.
 	if len(sDefs) == 0 {
@@ -243,7 +470,7 @@ func Init(ctx context.Context, sDefs []*surfacerpb.SurfacerDef) ([]*SurfacerInfo
 
 	foundSurfacers := make(map[surfacerpb.Type]bool)
 
-	var result []*SurfacerInfo
+	var result []typedSurfacerDef
 	for _, sDef := range sDefs {
 		sType := sDef.GetType()
 
@@ -258,32 +485,51 @@ func Init(ctx context.Context, sDefs []*surfacerpb.SurfacerDef) ([]*SurfacerInfo
 			sType = inferType(sDef)
 		}
 
-		s, conf, err := initSurfacer(ctx, sDef, sType)
-		if err != nil {
-			return nil, err
-		}
-
 		foundSurfacers[sType] = true
-
-		result = append(result, &SurfacerInfo{
-			Surfacer: s,
-			Type:     sType.String(),
-			Name:     sDef.GetName(),
-			Conf:     formatutils.ConfToString(conf),
-		})
+		result = append(result, typedSurfacerDef{def: sDef, sType: sType})
 	}
 
 	for _, s := range requiredSurfacers {
 		if !foundSurfacers[s.GetType()] {
-			surfacer, _, err := initSurfacer(ctx, s, s.GetType())
-			if err != nil {
-				return nil, err
-			}
-			result = append(result, &SurfacerInfo{
-				Surfacer: surfacer,
-				Type:     s.GetType().String(),
-			})
+			result = append(result, typedSurfacerDef{def: s, sType: s.GetType(), required: true})
+		}
+	}
+	return result
+}
+
+// newSurfacerInfo creates the surfacer for the definition. Surfacer runs
+// until ctx is canceled, or until it's stopped through an update.
+func newSurfacerInfo(ctx context.Context, td typedSurfacerDef) (*SurfacerInfo, error) {
+	sCtx, cancel := context.WithCancel(ctx)
+	s, conf, err := initSurfacer(sCtx, td.def, td.sType)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	si := &SurfacerInfo{
+		Surfacer: s,
+		Type:     td.sType.String(),
+		Def:      td.def,
+		cancel:   cancel,
+	}
+	if !td.required {
+		si.Name = td.def.GetName()
+		si.Conf = formatutils.ConfToString(conf)
+	}
+	return si, nil
+}
+
+// Init initializes the surfacers from the config protobufs and returns them as
+// a list.
+func Init(ctx context.Context, sDefs []*surfacerpb.SurfacerDef) ([]*SurfacerInfo, error) {
+	var result []*SurfacerInfo
+	for _, td := range effectiveDefs(sDefs) {
+		si, err := newSurfacerInfo(ctx, td)
+		if err != nil {
+			return nil, err
 		}
+		result = append(result, si)
 	}
 	return result, nil
 }
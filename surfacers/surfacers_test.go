@@ -15,13 +15,16 @@
 package surfacers
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/cloudprober/cloudprober/config/runconfig"
+	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/surfacers/internal/common/options"
 	surfacerpb "github.com/cloudprober/cloudprober/surfacers/proto"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/proto"
@@ -71,6 +74,8 @@ func TestInferType(t *testing.T) {
 		"PUBSUB":      {Surfacer: &surfacerpb.SurfacerDef_PubsubSurfacer{}},
 		"STACKDRIVER": {Surfacer: &surfacerpb.SurfacerDef_StackdriverSurfacer{}},
 		"BIGQUERY":    {Surfacer: &surfacerpb.SurfacerDef_BigquerySurfacer{}},
+		"SQLITE":      {Surfacer: &surfacerpb.SurfacerDef_SqliteSurfacer{}},
+		"OTEL":        {Surfacer: &surfacerpb.SurfacerDef_OtelSurfacer{}},
 	}
 
 	for k := range surfacerpb.Type_value {
@@ -228,3 +233,369 @@ func TestFailureMetric(t *testing.T) {
 		}
 	}
 }
+
+func TestExportOnlyFailingTargets(t *testing.T) {
+	runconfig.SetDefaultHTTPServeMux(http.NewServeMux())
+
+	ts := &testSurfacer{}
+	Register("s-failing-only", ts)
+
+	si, err := Init(context.Background(), []*surfacerpb.SurfacerDef{
+		{
+			Name:                     proto.String("s-failing-only"),
+			Type:                     surfacerpb.Type_USER_DEFINED.Enum(),
+			ExportOnlyFailingTargets: proto.Bool(true),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected initialization error: %v", err)
+	}
+
+	targetEM := func(dst string, total, success int64) *metrics.EventMetrics {
+		return metrics.NewEventMetrics(time.Now()).
+			AddMetric("total", metrics.NewInt(total)).
+			AddMetric("success", metrics.NewInt(success)).
+			AddLabel("ptype", "http").
+			AddLabel("probe", "p1").
+			AddLabel("dst", dst)
+	}
+
+	for _, em := range []*metrics.EventMetrics{
+		targetEM("healthy", 10, 10),
+		targetEM("failing", 10, 9),
+		testEventMetrics[1], // sysvars, always exported.
+	} {
+		si[0].Surfacer.Write(context.Background(), em)
+	}
+
+	var got []string
+	for _, em := range ts.received {
+		got = append(got, em.Label("dst")+"/"+em.Label("probe"))
+	}
+	assert.Equal(t, []string{"failing/p1", "/sysvars"}, got)
+}
+
+func TestRateMetrics(t *testing.T) {
+	runconfig.SetDefaultHTTPServeMux(http.NewServeMux())
+
+	ts := &testSurfacer{}
+	Register("s-rate", ts)
+
+	si, err := Init(context.Background(), []*surfacerpb.SurfacerDef{
+		{
+			Name:          proto.String("s-rate"),
+			Type:          surfacerpb.Type_USER_DEFINED.Enum(),
+			ExportAsGauge: proto.Bool(true),
+			RateMetric: []*surfacerpb.RateMetric{
+				{MetricName: proto.String("total")},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected initialization error: %v", err)
+	}
+
+	start := time.Now()
+	for i, total := range []int64{10, 40} {
+		em := metrics.NewEventMetrics(start.Add(time.Duration(i)*10*time.Second)).
+			AddMetric("total", metrics.NewInt(total)).
+			AddLabel("probe", "p1")
+		si[0].Surfacer.Write(context.Background(), em)
+	}
+
+	// No rate for the first sample; rate is written in its own gauge
+	// EventMetrics.
+	assert.Len(t, ts.received, 3)
+	assert.Nil(t, ts.received[0].Metric("total_per_second"), "rate in the first sample")
+	assert.Equal(t, "30", ts.received[1].Metric("total").String(), "gauge total")
+	assert.Nil(t, ts.received[1].Metric("total_per_second"), "rate with the total")
+
+	rateEM := ts.received[2]
+	assert.True(t, rateEM.Kind == metrics.GAUGE, "rate EventMetrics kind")
+	assert.Equal(t, "p1", rateEM.Label("probe"), "rate EventMetrics probe label")
+	assert.Equal(t, []string{"total_per_second"}, rateEM.MetricsKeys())
+	assert.Equal(t, "3.000", rateEM.Metric("total_per_second").String())
+
+	_, err = Init(context.Background(), []*surfacerpb.SurfacerDef{
+		{
+			Name:       proto.String("s-rate"),
+			Type:       surfacerpb.Type_USER_DEFINED.Enum(),
+			RateMetric: []*surfacerpb.RateMetric{{}},
+		},
+	})
+	assert.Error(t, err, "rate_metric without metric_name")
+}
+
+func TestWriteWithMetricPrefix(t *testing.T) {
+	runconfig.SetDefaultHTTPServeMux(http.NewServeMux())
+
+	ts := &testSurfacer{}
+	Register("s-prefix", ts)
+
+	si, err := Init(context.Background(), []*surfacerpb.SurfacerDef{
+		{
+			Name:                 proto.String("s-prefix"),
+			Type:                 surfacerpb.Type_USER_DEFINED.Enum(),
+			AddFailureMetric:     proto.Bool(true),
+			AllowMetricsWithName: proto.String("^(total|success)$"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected initialization error: %v", err)
+	}
+
+	// Surfacer's processing uses the unprefixed names.
+	em := metrics.NewEventMetrics(time.Now()).
+		AddMetric("total", metrics.NewInt(10)).
+		AddMetric("success", metrics.NewInt(8)).
+		AddLabel("probe", "p1")
+	si[0].WriteWithMetricPrefix(context.Background(), em, "team_a_")
+
+	if assert.Len(t, ts.received, 1) {
+		assert.Equal(t, []string{"team_a_total", "team_a_success", "team_a_failure"}, ts.received[0].MetricsKeys())
+		assert.Equal(t, "2", ts.received[0].Metric("team_a_failure").String())
+		assert.Equal(t, "p1", ts.received[0].Label("probe"))
+	}
+
+	// Prefix is not added for the surfacers that read the probe metrics by
+	// their names, e.g. probestatus.
+	ts.received = nil
+	sw := si[0].Surfacer.(*surfacerWrapper)
+	sw.noMetricPrefix = true
+	si[0].WriteWithMetricPrefix(context.Background(), em, "team_a_")
+	if assert.Len(t, ts.received, 1) {
+		assert.Equal(t, []string{"total", "success", "failure"}, ts.received[0].MetricsKeys())
+	}
+}
+
+type flushingSurfacer struct {
+	testSurfacer
+	flushTime time.Duration
+	flushed   bool
+}
+
+func (fs *flushingSurfacer) Flush(ctx context.Context) error {
+	select {
+	case <-time.After(fs.flushTime):
+		fs.flushed = true
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestFlush(t *testing.T) {
+	runconfig.SetDefaultHTTPServeMux(http.NewServeMux())
+
+	fast := &flushingSurfacer{flushTime: time.Millisecond}
+	slow := &flushingSurfacer{flushTime: time.Minute}
+	Register("flush_fast", fast)
+	Register("flush_slow", slow)
+	Register("no_flush", &testSurfacer{})
+
+	var configs []*surfacerpb.SurfacerDef
+	for _, name := range []string{"flush_fast", "flush_slow", "no_flush"} {
+		configs = append(configs, &surfacerpb.SurfacerDef{
+			Name:             proto.String(name),
+			Type:             surfacerpb.Type_USER_DEFINED.Enum(),
+			FlushTimeoutMsec: proto.Int32(100),
+		})
+	}
+	si, err := Init(context.Background(), configs)
+	if err != nil {
+		t.Fatalf("Unexpected initialization error: %v", err)
+	}
+
+	start := time.Now()
+	err = Flush(context.Background(), si)
+	assert.Less(t, time.Since(start), 10*time.Second, "flush time")
+	assert.ErrorContains(t, err, "surfacer flush_slow")
+	assert.NotContains(t, err.Error(), "flush_fast")
+	assert.True(t, fast.flushed, "fast surfacer flushed")
+	assert.False(t, slow.flushed, "slow surfacer flushed")
+}
+
+func TestDryRun(t *testing.T) {
+	runconfig.SetDefaultHTTPServeMux(http.NewServeMux())
+
+	ts := &testSurfacer{}
+	Register("dry_run", ts)
+
+	si, err := Init(context.Background(), []*surfacerpb.SurfacerDef{
+		{
+			Name:   proto.String("dry_run"),
+			Type:   surfacerpb.Type_USER_DEFINED.Enum(),
+			DryRun: proto.Bool(true),
+		},
+		{
+			// Dry run surfacers are not initialized, so invalid config is fine.
+			Name:   proto.String("dry_run_unregistered"),
+			Type:   surfacerpb.Type_USER_DEFINED.Enum(),
+			DryRun: proto.Bool(true),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected initialization error: %v", err)
+	}
+
+	for _, s := range si[:2] {
+		_, ok := s.Surfacer.(*surfacerWrapper).Surfacer.(*dryRunSurfacer)
+		assert.True(t, ok, "surfacer %s is not a dry run surfacer", s.Name)
+		for _, em := range testEventMetrics {
+			s.Surfacer.Write(context.Background(), em)
+		}
+	}
+	assert.Empty(t, ts.received, "dry run surfacer exported metrics")
+}
+
+func TestRedactLabels(t *testing.T) {
+	em := metrics.NewEventMetrics(time.Now()).
+		AddMetric("total", metrics.NewInt(20)).
+		AddLabel("probe", "api").
+		AddLabel("auth_token", "s3cret").
+		AddLabel("API-Key", "k3y")
+	em.Kind = metrics.GAUGE
+
+	got := redactLabels(em)
+	assert.Equal(t, "api", got.Label("probe"))
+	assert.Equal(t, redactedValue, got.Label("auth_token"))
+	assert.Equal(t, redactedValue, got.Label("API-Key"))
+	assert.Equal(t, "20", got.Metric("total").String())
+	assert.Equal(t, em.Kind, got.Kind)
+	assert.Equal(t, "s3cret", em.Label("auth_token"), "original EventMetrics modified")
+
+	em = testEventMetrics[0]
+	assert.Same(t, em, redactLabels(em), "EventMetrics without secrets")
+}
+
+func TestDryRunPayload(t *testing.T) {
+	runconfig.SetDefaultHTTPServeMux(http.NewServeMux())
+
+	em := metrics.NewEventMetrics(time.Now()).
+		AddMetric("total", metrics.NewInt(20)).
+		AddLabel("probe", "api").
+		AddLabel("api_key", "k3y").
+		AddLabel("url", "https://api.example.com/?token=s3cret&q=1")
+
+	for _, test := range []struct {
+		sType surfacerpb.Type
+		want  string
+	}{
+		{
+			sType: surfacerpb.Type_PROMETHEUS,
+			// Quotes are escaped in the log message.
+			want: `total{probe=\"api\",api_key=\"<redacted>\",url=\"https://api.example.com/?token=<redacted>&q=1\"} 20`,
+		},
+		{
+			sType: surfacerpb.Type_FILE,
+			want:  "labels=probe=api,api_key=<redacted>,url=https://api.example.com/?token=<redacted>&q=1 total=20",
+		},
+	} {
+		t.Run(test.sType.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			l := logger.New(logger.WithWriter(&buf))
+			s := &surfacerpb.SurfacerDef{Type: test.sType.Enum(), DryRun: proto.Bool(true)}
+			opts, err := options.BuildOptionsFromConfig(s, l)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			drs, err := newDryRunSurfacer(s, test.sType, opts, l)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			drs.Write(context.Background(), em)
+			assert.Contains(t, buf.String(), test.want)
+
+			// Payload has only the EventMetrics being written.
+			buf.Reset()
+			drs.Write(context.Background(), metrics.NewEventMetrics(time.Now()).AddMetric("failures", metrics.NewInt(1)).AddLabel("probe", "api"))
+			assert.NotContains(t, buf.String(), "total")
+			assert.NotContains(t, buf.String(), "s3cret")
+			assert.NotContains(t, buf.String(), "k3y")
+		})
+	}
+}
+
+func TestTimestampGranularity(t *testing.T) {
+	runconfig.SetDefaultHTTPServeMux(http.NewServeMux())
+
+	ts := &testSurfacer{}
+	Register("s-ts-granularity", ts)
+
+	si, err := Init(context.Background(), []*surfacerpb.SurfacerDef{
+		{
+			Name:                     proto.String("s-ts-granularity"),
+			Type:                     surfacerpb.Type_USER_DEFINED.Enum(),
+			TimestampGranularityMsec: proto.Int32(1000),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected initialization error: %v", err)
+	}
+
+	emTime := time.Date(2023, 6, 1, 10, 0, 5, 678e6, time.UTC)
+	em := metrics.NewEventMetrics(emTime).
+		AddMetric("total", metrics.NewInt(10)).
+		AddLabel("probe", "p1")
+	si[0].Surfacer.Write(context.Background(), em)
+
+	assert.Len(t, ts.received, 1)
+	assert.Equal(t, time.Date(2023, 6, 1, 10, 0, 5, 0, time.UTC), ts.received[0].Timestamp)
+	assert.Equal(t, emTime, em.Timestamp, "original EventMetrics modified")
+
+	_, err = Init(context.Background(), []*surfacerpb.SurfacerDef{
+		{
+			Name:                     proto.String("s-ts-granularity"),
+			Type:                     surfacerpb.Type_USER_DEFINED.Enum(),
+			TimestampGranularityMsec: proto.Int32(-1),
+		},
+	})
+	assert.Error(t, err, "negative timestamp_granularity_msec")
+}
+
+func TestDedupMetrics(t *testing.T) {
+	runconfig.SetDefaultHTTPServeMux(http.NewServeMux())
+
+	ts := &testSurfacer{}
+	Register("s-dedup", ts)
+
+	si, err := Init(context.Background(), []*surfacerpb.SurfacerDef{
+		{
+			Name:                   proto.String("s-dedup"),
+			Type:                   surfacerpb.Type_USER_DEFINED.Enum(),
+			DedupMetrics:           proto.Bool(true),
+			DedupMaxSuppressionSec: proto.Int32(60),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected initialization error: %v", err)
+	}
+
+	start := time.Now()
+	for i, v := range []int64{1, 1, 0, 0, 0, 0} {
+		em := metrics.NewEventMetrics(start.Add(time.Duration(i)*20*time.Second)).
+			AddMetric("up", metrics.NewInt(v)).
+			AddLabel("probe", "p1")
+		em.Kind = metrics.GAUGE
+		si[0].Surfacer.Write(context.Background(), em)
+	}
+
+	// EventMetrics are 20s apart. 2nd, 4th and 5th are identical to the last
+	// exported ones. 6th is exported as it's been 60s since the last export.
+	var got []int64
+	for _, em := range ts.received {
+		got = append(got, em.Metric("up").(*metrics.Int).Int64())
+	}
+	assert.Equal(t, []int64{1, 0, 0}, got)
+
+	_, err = Init(context.Background(), []*surfacerpb.SurfacerDef{
+		{
+			Name:                   proto.String("s-dedup"),
+			Type:                   surfacerpb.Type_USER_DEFINED.Enum(),
+			DedupMetrics:           proto.Bool(true),
+			DedupMaxSuppressionSec: proto.Int32(0),
+		},
+	})
+	assert.Error(t, err, "zero dedup_max_suppression_sec")
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package surfacers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudprober/cloudprober/logger"
+	surfacerpb "github.com/cloudprober/cloudprober/surfacers/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// restartOnlySurfacers are the surfacers that register handlers with the
+// default HTTP server. As handlers cannot be unregistered, these surfacers
+// cannot be re-created, and their changes require a restart.
+var restartOnlySurfacers = map[surfacerpb.Type]bool{
+	surfacerpb.Type_PROMETHEUS:  true,
+	surfacerpb.Type_PROBESTATUS: true,
+}
+
+// surfacerKey identifies a surfacer across config updates: surfacer's name if
+// configured, otherwise its type.
+func surfacerKey(sType string, name string) string {
+	if name != "" {
+		return name
+	}
+	return strings.ToLower(sType)
+}
+
+// Update returns the surfacers for the updated surfacer definitions, reusing
+// the current surfacers that haven't changed, so that they keep their state.
+// New and changed surfacers are created using ctx, and the current surfacers
+// that are not part of the returned list are stopped and flushed.
+//
+// Surfacers that can't be re-created in place (see restartOnlySurfacers) are
+// kept as they are, and a warning is logged if they've changed. If any of the
+// new surfacers fail to initialize, current surfacers are left unchanged and
+// an error is returned.
+func Update(ctx context.Context, current []*SurfacerInfo, sDefs []*surfacerpb.SurfacerDef, l *logger.Logger) ([]*SurfacerInfo, error) {
+	currentByKey := make(map[string]*SurfacerInfo)
+	for _, si := range current {
+		currentByKey[surfacerKey(si.Type, si.Name)] = si
+	}
+
+	var result, created []*SurfacerInfo
+	kept := make(map[*SurfacerInfo]bool)
+
+	// Keep restart-only surfacers, even if they're removed from the config.
+	for _, si := range current {
+		if restartOnlySurfacers[surfacerpb.Type(surfacerpb.Type_value[si.Type])] {
+			result = append(result, si)
+			kept[si] = true
+		}
+	}
+
+	for _, td := range effectiveDefs(sDefs) {
+		key := surfacerKey(td.sType.String(), td.def.GetName())
+		cur := currentByKey[key]
+
+		if restartOnlySurfacers[td.sType] {
+			if cur == nil {
+				l.Warningf("New %s surfacer (%s) is not added until restart.", td.sType, key)
+			} else if !proto.Equal(cur.Def, td.def) {
+				l.Warningf("Changes to the %s surfacer (%s) are not applied until restart.", td.sType, key)
+			}
+			continue
+		}
+
+		// Multiple surfacers of the same type may not have names, reuse a
+		// surfacer only once.
+		if cur != nil && !kept[cur] && proto.Equal(cur.Def, td.def) {
+			result = append(result, cur)
+			kept[cur] = true
+			continue
+		}
+
+		si, err := newSurfacerInfo(ctx, td)
+		if err != nil {
+			for _, s := range created {
+				s.cancel()
+			}
+			return current, fmt.Errorf("error initializing surfacer %s: %v", key, err)
+		}
+		l.Infof("Starting surfacer: %s", key)
+		created = append(created, si)
+		result = append(result, si)
+	}
+
+	for _, si := range current {
+		if kept[si] {
+			continue
+		}
+		l.Infof("Stopping surfacer: %s", surfacerKey(si.Type, si.Name))
+		go stop(si)
+	}
+	return result, nil
+}
+
+// stop stops the surfacer and flushes it. As per the Flusher interface, the
+// surfacer's context is canceled before it's flushed.
+func stop(si *SurfacerInfo) {
+	if si.cancel != nil {
+		si.cancel()
+	}
+	if f, ok := si.Surfacer.(Flusher); ok {
+		f.Flush(context.Background())
+	}
+}
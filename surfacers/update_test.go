@@ -0,0 +1,111 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package surfacers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/config/runconfig"
+	surfacerpb "github.com/cloudprober/cloudprober/surfacers/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// stopTrackingSurfacer closes the stopped channel when it's flushed.
+type stopTrackingSurfacer struct {
+	testSurfacer
+	stopped chan struct{}
+}
+
+func (s *stopTrackingSurfacer) Flush(ctx context.Context) error {
+	close(s.stopped)
+	return nil
+}
+
+func testUserDefinedSurfacer(name string) *surfacerpb.SurfacerDef {
+	return &surfacerpb.SurfacerDef{
+		Name: proto.String(name),
+		Type: surfacerpb.Type_USER_DEFINED.Enum(),
+	}
+}
+
+func surfacerKeys(ss []*SurfacerInfo) []string {
+	var keys []string
+	for _, si := range ss {
+		keys = append(keys, surfacerKey(si.Type, si.Name))
+	}
+	return keys
+}
+
+func TestUpdate(t *testing.T) {
+	runconfig.SetDefaultHTTPServeMux(http.NewServeMux())
+
+	removed := &stopTrackingSurfacer{stopped: make(chan struct{})}
+	Register("update_a", &testSurfacer{})
+	Register("update_b", removed)
+	Register("update_c", &testSurfacer{})
+
+	promDef := &surfacerpb.SurfacerDef{Type: surfacerpb.Type_PROMETHEUS.Enum()}
+	current, err := Init(context.Background(), []*surfacerpb.SurfacerDef{
+		testUserDefinedSurfacer("update_a"),
+		testUserDefinedSurfacer("update_b"),
+		promDef,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"update_a", "update_b", "prometheus", "probestatus"}, surfacerKeys(current))
+
+	// update_a is unchanged, update_b is removed, update_c is added, and
+	// prometheus is changed.
+	changedA := testUserDefinedSurfacer("update_a")
+	changedA.AddFailureMetric = proto.Bool(true)
+	updated, err := Update(context.Background(), current, []*surfacerpb.SurfacerDef{
+		testUserDefinedSurfacer("update_a"),
+		testUserDefinedSurfacer("update_c"),
+		{
+			Type:          surfacerpb.Type_PROMETHEUS.Enum(),
+			ExportAsGauge: proto.Bool(true),
+		},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"prometheus", "probestatus", "update_a", "update_c"}, surfacerKeys(updated))
+	assert.Same(t, current[0], updated[2], "unchanged surfacer should be reused")
+	assert.Same(t, current[2], updated[0], "prometheus surfacer should be kept")
+	assert.Same(t, current[3], updated[1], "probestatus surfacer should be kept")
+	select {
+	case <-removed.stopped:
+	case <-time.After(time.Second):
+		t.Errorf("removed surfacer was not flushed")
+	}
+
+	// Changed surfacer is re-created.
+	updated2, err := Update(context.Background(), updated, []*surfacerpb.SurfacerDef{
+		changedA,
+		testUserDefinedSurfacer("update_c"),
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"prometheus", "probestatus", "update_a", "update_c"}, surfacerKeys(updated2))
+	assert.NotSame(t, updated[2], updated2[2], "changed surfacer should be re-created")
+	assert.Same(t, updated[3], updated2[3])
+
+	// Initialization error leaves the surfacers unchanged.
+	updated3, err := Update(context.Background(), updated2, []*surfacerpb.SurfacerDef{
+		testUserDefinedSurfacer("update_unknown"),
+	}, nil)
+	assert.Error(t, err)
+	assert.Equal(t, updated2, updated3)
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targets
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+)
+
+// expandCIDR returns the host addresses in the given prefix. If skipNB is
+// true, network and broadcast addresses of IPv4 ranges bigger than /31 are
+// skipped. It returns false if prefix has more than limit host addresses.
+func expandCIDR(prefix netip.Prefix, skipNB bool, limit int) ([]netip.Addr, bool) {
+	prefix = prefix.Masked()
+	hostBits := prefix.Addr().BitLen() - prefix.Bits()
+	// Avoid iterating over (and overflowing on) very large ranges.
+	if hostBits >= 31 || 1<<hostBits > limit+2 {
+		return nil, false
+	}
+
+	skipNB = skipNB && prefix.Addr().Is4() && hostBits > 1
+
+	var addrs []netip.Addr
+	for addr, i := prefix.Addr(), 0; i < 1<<hostBits; addr, i = addr.Next(), i+1 {
+		if skipNB && (i == 0 || i == 1<<hostBits-1) {
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	if len(addrs) > limit {
+		return nil, false
+	}
+	return addrs, true
+}
+
+func cidrTargets(c *targetspb.CIDRTargets) (*staticLister, error) {
+	if len(c.GetCidr()) == 0 {
+		return nil, fmt.Errorf("cidr_targets: no CIDR specified")
+	}
+
+	maxHosts := int(c.GetMaxHosts())
+	sl := &staticLister{}
+	for _, cidr := range c.GetCidr() {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("cidr_targets: invalid CIDR (%s): %v", cidr, err)
+		}
+
+		addrs, ok := expandCIDR(prefix, c.GetSkipNetworkBroadcast(), maxHosts-len(sl.list))
+		if !ok {
+			return nil, fmt.Errorf("cidr_targets: CIDR ranges expand to more than max_hosts (%d) hosts, at %s", maxHosts, cidr)
+		}
+		for _, addr := range addrs {
+			sl.list = append(sl.list, endpoint.Endpoint{
+				Name: addr.String(),
+				IP:   net.IP(addr.AsSlice()),
+			})
+		}
+	}
+	return sl, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targets
+
+import (
+	"testing"
+
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCIDRTargets(t *testing.T) {
+	for _, test := range []struct {
+		desc      string
+		conf      *targetspb.CIDRTargets
+		wantNames []string
+		wantErr   bool
+	}{
+		{
+			desc:      "skip_network_broadcast",
+			conf:      &targetspb.CIDRTargets{Cidr: []string{"10.0.0.0/29"}},
+			wantNames: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4", "10.0.0.5", "10.0.0.6"},
+		},
+		{
+			desc: "all_addresses_unmasked",
+			conf: &targetspb.CIDRTargets{
+				Cidr:                 []string{"10.0.0.5/30"},
+				SkipNetworkBroadcast: proto.Bool(false),
+			},
+			wantNames: []string{"10.0.0.4", "10.0.0.5", "10.0.0.6", "10.0.0.7"},
+		},
+		{
+			desc:      "slash_31_and_32",
+			conf:      &targetspb.CIDRTargets{Cidr: []string{"10.0.0.0/31", "192.168.1.1/32"}},
+			wantNames: []string{"10.0.0.0", "10.0.0.1", "192.168.1.1"},
+		},
+		{
+			desc:      "ipv6",
+			conf:      &targetspb.CIDRTargets{Cidr: []string{"2001:db8::/126"}},
+			wantNames: []string{"2001:db8::", "2001:db8::1", "2001:db8::2", "2001:db8::3"},
+		},
+		{
+			desc: "at_max_hosts",
+			conf: &targetspb.CIDRTargets{
+				Cidr:     []string{"10.0.0.0/30", "10.0.1.0/30"},
+				MaxHosts: proto.Int32(4),
+			},
+			wantNames: []string{"10.0.0.1", "10.0.0.2", "10.0.1.1", "10.0.1.2"},
+		},
+		{
+			desc: "over_max_hosts_combined",
+			conf: &targetspb.CIDRTargets{
+				Cidr:     []string{"10.0.0.0/30", "10.0.1.0/29"},
+				MaxHosts: proto.Int32(4),
+			},
+			wantErr: true,
+		},
+		{
+			desc:    "over_default_max_hosts",
+			conf:    &targetspb.CIDRTargets{Cidr: []string{"10.0.0.0/16"}},
+			wantErr: true,
+		},
+		{
+			desc:    "huge_ipv6",
+			conf:    &targetspb.CIDRTargets{Cidr: []string{"2001:db8::/64"}},
+			wantErr: true,
+		},
+		{
+			desc:    "invalid_cidr",
+			conf:    &targetspb.CIDRTargets{Cidr: []string{"10.0.0.0"}},
+			wantErr: true,
+		},
+		{
+			desc:    "no_cidr",
+			conf:    &targetspb.CIDRTargets{},
+			wantErr: true,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			sl, err := cidrTargets(test.conf)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			var gotNames []string
+			for _, ep := range sl.list {
+				gotNames = append(gotNames, ep.Name)
+				assert.Equal(t, ep.Name, ep.IP.String(), "endpoint IP")
+			}
+			assert.Equal(t, test.wantNames, gotNames)
+		})
+	}
+}
+
+func TestNewCIDRTargets(t *testing.T) {
+	tgts, err := New(&targetspb.TargetsDef{
+		Type: &targetspb.TargetsDef_CidrTargets{CidrTargets: &targetspb.CIDRTargets{
+			Cidr: []string{"10.0.0.0/30"},
+		}},
+		Regex: proto.String(`\.2$`),
+	}, nil, nil, nil, nil)
+	assert.NoError(t, err)
+
+	eps := tgts.ListEndpoints()
+	assert.Len(t, eps, 1)
+	assert.Equal(t, "10.0.0.2", eps[0].Dst())
+
+	ip, err := tgts.Resolve(eps[0].Name, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.2", ip.String())
+}
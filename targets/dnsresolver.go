@@ -0,0 +1,113 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targets
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/cloudprober/cloudprober/logger"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+	dnsRes "github.com/cloudprober/cloudprober/targets/resolver"
+	"github.com/miekg/dns"
+)
+
+// resolverServerAddr returns the resolver server address in host:port
+// format.
+func resolverServerAddr(server string) string {
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server
+	}
+	return net.JoinHostPort(strings.Trim(server, "[]"), "53")
+}
+
+// searchNames returns the names to try, in order, for the given name.
+func searchNames(name string, searchDomains []string) []string {
+	if strings.HasSuffix(name, ".") || len(searchDomains) == 0 {
+		return []string{name}
+	}
+
+	var names []string
+	for _, domain := range searchDomains {
+		names = append(names, name+"."+strings.Trim(domain, "."))
+	}
+
+	if strings.Contains(name, ".") {
+		return append([]string{name}, names...)
+	}
+	return append(names, name)
+}
+
+// checkResolver sends a simple query to the resolver to verify that it's
+// reachable.
+func checkResolver(serverAddr, network string, timeout time.Duration) error {
+	client := &dns.Client{Net: network, Timeout: timeout}
+	msg := new(dns.Msg)
+	msg.SetQuestion(".", dns.TypeNS)
+	_, _, err := client.Exchange(msg, serverAddr)
+	return err
+}
+
+// newResolverFromConfig builds a caching resolver from the DNS resolver
+// config. It uses a net.Resolver that directs all queries to the configured
+// server, or a DNS-over-HTTPS resolver if protocol is HTTPS.
+func newResolverFromConfig(c *targetspb.DNSResolverConfig, l *logger.Logger) (*dnsRes.Resolver, error) {
+	if c.GetServer() == "" {
+		return nil, fmt.Errorf("dns_resolver: server is required")
+	}
+
+	if c.GetProtocol() == targetspb.DNSResolverConfig_HTTPS {
+		return dohCachingResolver(c)
+	}
+
+	serverAddr := resolverServerAddr(c.GetServer())
+	network := strings.ToLower(c.GetProtocol().String())
+	timeout := time.Duration(c.GetTimeoutMsec()) * time.Millisecond
+
+	netResolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			d := net.Dialer{Timeout: timeout}
+			return d.DialContext(ctx, network, serverAddr)
+		},
+	}
+
+	resolve := func(name string) ([]net.IP, error) {
+		var lastErr error
+		for _, n := range searchNames(name, c.GetSearchDomain()) {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			ips, err := netResolver.LookupIP(ctx, "ip", n)
+			cancel()
+			if err == nil {
+				return ips, nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+
+	if err := checkResolver(serverAddr, network, timeout); err != nil {
+		l.Warningf("dns_resolver: resolver %s (%s) may not be reachable: %v", serverAddr, network, err)
+	}
+
+	r := dnsRes.NewWithResolve(resolve)
+	if c.GetMaxCacheAgeSec() != 0 {
+		r.DefaultMaxAge = time.Duration(c.GetMaxCacheAgeSec()) * time.Second
+	}
+	return r, nil
+}
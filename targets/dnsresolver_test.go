@@ -0,0 +1,157 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targets
+
+import (
+	"net"
+	"testing"
+
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSearchNames(t *testing.T) {
+	domains := []string{"internal.example.com", ".corp.example.com."}
+
+	tests := []struct {
+		name    string
+		domains []string
+		want    []string
+	}{
+		{
+			name: "web",
+			want: []string{"web"},
+		},
+		{
+			name:    "web",
+			domains: domains,
+			want:    []string{"web.internal.example.com", "web.corp.example.com", "web"},
+		},
+		{
+			name:    "web.prod",
+			domains: domains,
+			want:    []string{"web.prod", "web.prod.internal.example.com", "web.prod.corp.example.com"},
+		},
+		{
+			name:    "web.example.com.",
+			domains: domains,
+			want:    []string{"web.example.com."},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, searchNames(test.name, test.domains))
+		})
+	}
+}
+
+func TestResolverServerAddr(t *testing.T) {
+	assert.Equal(t, "10.0.0.53:53", resolverServerAddr("10.0.0.53"))
+	assert.Equal(t, "10.0.0.53:5353", resolverServerAddr("10.0.0.53:5353"))
+	assert.Equal(t, "[::1]:53", resolverServerAddr("::1"))
+	assert.Equal(t, "[::1]:53", resolverServerAddr("[::1]"))
+}
+
+func startTestDNSServer(t *testing.T, records map[string]string) string {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error starting DNS server: %v", err)
+	}
+
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		q := req.Question[0]
+		if ip, ok := records[q.Name]; ok && q.Qtype == dns.TypeA {
+			resp.Answer = append(resp.Answer, &dns.A{
+				Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+				A:   net.ParseIP(ip),
+			})
+		} else if !ok && q.Name != "." {
+			resp.Rcode = dns.RcodeNameError
+		}
+		w.WriteMsg(resp)
+	})
+
+	srv := &dns.Server{PacketConn: pc, Handler: handler}
+	go srv.ActivateAndServe()
+	t.Cleanup(func() { srv.Shutdown() })
+
+	return pc.LocalAddr().String()
+}
+
+func TestNewResolverFromConfig(t *testing.T) {
+	addr := startTestDNSServer(t, map[string]string{
+		"web.internal.example.com.": "10.1.1.1",
+		"db.example.com.":           "10.1.1.2",
+	})
+
+	_, err := newResolverFromConfig(&targetspb.DNSResolverConfig{}, nil)
+	assert.Error(t, err, "no server")
+
+	r, err := newResolverFromConfig(&targetspb.DNSResolverConfig{
+		Server:       proto.String(addr),
+		SearchDomain: []string{"internal.example.com"},
+	}, nil)
+	assert.NoError(t, err)
+
+	for name, want := range map[string]string{
+		"web":            "10.1.1.1",
+		"db.example.com": "10.1.1.2",
+	} {
+		ip, err := r.Resolve(name, 4)
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, ip.String(), name)
+	}
+
+	_, err = r.Resolve("unknown", 4)
+	assert.Error(t, err, "unknown host")
+}
+
+func TestNewWithDNSResolver(t *testing.T) {
+	addr := startTestDNSServer(t, map[string]string{
+		"host1.internal.example.com.": "10.2.2.1",
+	})
+
+	tgts, err := New(&targetspb.TargetsDef{
+		Type: &targetspb.TargetsDef_HostNames{HostNames: "host1"},
+		DnsResolver: &targetspb.DNSResolverConfig{
+			Server:       proto.String(addr),
+			SearchDomain: []string{"internal.example.com"},
+		},
+	}, nil, nil, nil, nil)
+	assert.NoError(t, err)
+
+	ip, err := tgts.Resolve("host1", 4)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.2.2.1", ip.String())
+}
+
+func TestNewDNSResolverNotSupported(t *testing.T) {
+	addr := startTestDNSServer(t, map[string]string{})
+
+	_, err := New(&targetspb.TargetsDef{
+		Type: &targetspb.TargetsDef_DummyTargets{DummyTargets: &targetspb.DummyTargets{}},
+		DnsResolver: &targetspb.DNSResolverConfig{
+			Server: proto.String(addr),
+		},
+	}, nil, nil, nil, nil)
+	assert.ErrorContains(t, err, "dns_resolver is not supported for dummy_targets targets")
+}
@@ -0,0 +1,260 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targets
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/cloudprober/cloudprober/internal/tlsconfig"
+	"github.com/cloudprober/cloudprober/metrics"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+	dnsRes "github.com/cloudprober/cloudprober/targets/resolver"
+	"github.com/miekg/dns"
+	"google.golang.org/protobuf/proto"
+)
+
+const dohContentType = "application/dns-message"
+
+// dohResolver resolves names using DNS-over-HTTPS (RFC 8484).
+type dohResolver struct {
+	url           string
+	client        *http.Client
+	headers       map[string]string
+	searchDomains []string
+	maxCacheAge   time.Duration
+
+	mu       sync.Mutex
+	queries  int64
+	failures int64
+	latency  time.Duration
+}
+
+// DoH resolvers are shared by the targets with the same resolver config, so
+// that they share the cache, and to export the resolvers' metrics.
+var (
+	dohResolversMu sync.Mutex
+	dohResolvers   = make(map[string]*dohResolver)
+	dohCaches      = make(map[string]*dnsRes.Resolver)
+	dohKeys        []string
+)
+
+func newDoHResolver(c *targetspb.DNSResolverConfig) (*dohResolver, error) {
+	u, err := url.Parse(c.GetServer())
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("dns_resolver: invalid DNS-over-HTTPS URL (%s), it should be like: https://dns.example.com/dns-query", c.GetServer())
+	}
+
+	tlsConfig := &tls.Config{}
+	if c.GetTlsConfig() != nil {
+		if err := tlsconfig.UpdateTLSConfig(tlsConfig, c.GetTlsConfig()); err != nil {
+			return nil, fmt.Errorf("dns_resolver: %v", err)
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &dohResolver{
+		url: c.GetServer(),
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   time.Duration(c.GetTimeoutMsec()) * time.Millisecond,
+		},
+		headers:       c.GetHttpHeader(),
+		searchDomains: c.GetSearchDomain(),
+		maxCacheAge:   time.Duration(c.GetMaxCacheAgeSec()) * time.Second,
+	}, nil
+}
+
+// query sends a DNS query of the given type for the given name, and returns
+// the answer's IPs and the minimum TTL of the answer records.
+func (d *dohResolver) query(name string, qtype uint16) ([]net.IP, uint32, error) {
+	start := time.Now()
+	ips, ttl, err := d.exchange(name, qtype)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries++
+	d.latency += time.Since(start)
+	if err != nil {
+		d.failures++
+	}
+	return ips, ttl, err
+}
+
+func (d *dohResolver) exchange(name string, qtype uint16) ([]net.IP, uint32, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	// RFC 8484 recommends using 0 ID for cache friendliness.
+	msg.Id = 0
+	buf, err := msg.Pack()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, d.url, bytes.NewReader(buf))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", dohContentType)
+	req.Header.Set("Accept", dohContentType)
+	for k, v := range d.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("DNS-over-HTTPS query for %s failed, status: %s", name, resp.Status)
+	}
+
+	// DNS messages are limited to 64KB.
+	respBuf, err := io.ReadAll(io.LimitReader(resp.Body, 65535))
+	if err != nil {
+		return nil, 0, err
+	}
+	respMsg := new(dns.Msg)
+	if err := respMsg.Unpack(respBuf); err != nil {
+		return nil, 0, fmt.Errorf("error parsing DNS-over-HTTPS response for %s: %v", name, err)
+	}
+	if respMsg.Rcode != dns.RcodeSuccess {
+		return nil, 0, fmt.Errorf("DNS-over-HTTPS query for %s failed, rcode: %s", name, dns.RcodeToString[respMsg.Rcode])
+	}
+
+	var ips []net.IP
+	var ttl uint32
+	for _, rr := range respMsg.Answer {
+		if ttl == 0 || rr.Header().Ttl < ttl {
+			ttl = rr.Header().Ttl
+		}
+		switch rec := rr.(type) {
+		case *dns.A:
+			ips = append(ips, rec.A)
+		case *dns.AAAA:
+			ips = append(ips, rec.AAAA)
+		}
+	}
+	return ips, ttl, nil
+}
+
+// resolve resolves the given name to IPv4 and IPv6 addresses, and returns
+// them along with the TTL to cache them for.
+func (d *dohResolver) resolve(name string) ([]net.IP, time.Duration, error) {
+	if ip := net.ParseIP(name); ip != nil {
+		return []net.IP{ip}, 0, nil
+	}
+
+	var lastErr error
+	for _, n := range searchNames(name, d.searchDomains) {
+		var ips []net.IP
+		var minTTL uint32
+		var errs int
+		for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+			qIPs, ttl, err := d.query(n, qtype)
+			if err != nil {
+				lastErr = err
+				errs++
+				continue
+			}
+			if len(qIPs) > 0 && (minTTL == 0 || ttl < minTTL) {
+				minTTL = ttl
+			}
+			ips = append(ips, qIPs...)
+		}
+		if len(ips) == 0 {
+			if errs == 0 {
+				lastErr = fmt.Errorf("no IP addresses found for %s", n)
+			}
+			continue
+		}
+
+		ttl := time.Duration(minTTL) * time.Second
+		if d.maxCacheAge > 0 && ttl > d.maxCacheAge {
+			ttl = d.maxCacheAge
+		}
+		return ips, ttl, nil
+	}
+	return nil, 0, lastErr
+}
+
+// resolverMetrics returns the EventMetrics with the resolver's cumulative
+// query metrics.
+func (d *dohResolver) resolverMetrics(ts time.Time) *metrics.EventMetrics {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return metrics.NewEventMetrics(ts).
+		AddMetric("doh_queries", metrics.NewInt(d.queries)).
+		AddMetric("doh_failures", metrics.NewInt(d.failures)).
+		AddMetric("doh_latency_msec", metrics.NewFloat(float64(d.latency)/float64(time.Millisecond))).
+		AddLabel("ptype", "sysvars").
+		AddLabel("probe", "dns_resolver").
+		AddLabel("server", d.url)
+}
+
+// dohCachingResolver returns the caching resolver that uses DNS-over-HTTPS,
+// for the given config. Resolvers are shared by the identical configs.
+func dohCachingResolver(c *targetspb.DNSResolverConfig) (*dnsRes.Resolver, error) {
+	keyBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	key := string(keyBytes)
+
+	dohResolversMu.Lock()
+	defer dohResolversMu.Unlock()
+
+	if r := dohCaches[key]; r != nil {
+		return r, nil
+	}
+
+	d, err := newDoHResolver(c)
+	if err != nil {
+		return nil, err
+	}
+	r := dnsRes.NewWithResolveTTL(d.resolve)
+	if c.GetMaxCacheAgeSec() != 0 {
+		r.DefaultMaxAge = time.Duration(c.GetMaxCacheAgeSec()) * time.Second
+	}
+
+	dohResolvers[key], dohCaches[key] = d, r
+	dohKeys = append(dohKeys, key)
+	return r, nil
+}
+
+// DNSResolverMetrics returns the EventMetrics with the DNS-over-HTTPS
+// resolvers' query metrics: number of queries, failures, and the cumulative
+// latency. It returns nil if no DNS-over-HTTPS resolvers are configured.
+func DNSResolverMetrics(ts time.Time) []*metrics.EventMetrics {
+	dohResolversMu.Lock()
+	defer dohResolversMu.Unlock()
+
+	var ems []*metrics.EventMetrics
+	for _, key := range dohKeys {
+		ems = append(ems, dohResolvers[key].resolverMetrics(ts))
+	}
+	return ems
+}
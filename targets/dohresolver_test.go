@@ -0,0 +1,185 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targets
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tlsconfigpb "github.com/cloudprober/cloudprober/internal/tlsconfig/proto"
+	"github.com/cloudprober/cloudprober/metrics"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// startTestDoHServer starts a DNS-over-HTTPS server that answers A and AAAA
+// queries from the given records, with the given TTL.
+func startTestDoHServer(t *testing.T, records map[string][]string, ttl uint32) *httptest.Server {
+	t.Helper()
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != dohContentType {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		buf, _ := io.ReadAll(r.Body)
+		req := new(dns.Msg)
+		if err := req.Unpack(buf); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		q := req.Question[0]
+		ips, ok := records[q.Name]
+		if !ok {
+			resp.Rcode = dns.RcodeNameError
+		}
+		for _, s := range ips {
+			ip := net.ParseIP(s)
+			hdr := dns.RR_Header{Name: q.Name, Class: dns.ClassINET, Ttl: ttl}
+			if ip.To4() != nil && q.Qtype == dns.TypeA {
+				hdr.Rrtype = dns.TypeA
+				resp.Answer = append(resp.Answer, &dns.A{Hdr: hdr, A: ip})
+			}
+			if ip.To4() == nil && q.Qtype == dns.TypeAAAA {
+				hdr.Rrtype = dns.TypeAAAA
+				resp.Answer = append(resp.Answer, &dns.AAAA{Hdr: hdr, AAAA: ip})
+			}
+		}
+		respBuf, _ := resp.Pack()
+		w.Header().Set("Content-Type", dohContentType)
+		w.Write(respBuf)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func testDoHConfig(server string) *targetspb.DNSResolverConfig {
+	return &targetspb.DNSResolverConfig{
+		Server:         proto.String(server),
+		Protocol:       targetspb.DNSResolverConfig_HTTPS.Enum(),
+		SearchDomain:   []string{"internal.example.com"},
+		MaxCacheAgeSec: proto.Int32(30),
+		TlsConfig:      &tlsconfigpb.TLSConfig{DisableCertValidation: proto.Bool(true)},
+		HttpHeader:     map[string]string{"Authorization": "Bearer test-token"},
+	}
+}
+
+func TestDoHResolve(t *testing.T) {
+	ts := startTestDoHServer(t, map[string][]string{
+		"web.internal.example.com.": {"10.1.1.1", "2001:db8::1"},
+		"db.example.com.":           {"10.1.1.2"},
+	}, 300)
+
+	d, err := newDoHResolver(testDoHConfig(ts.URL))
+	assert.NoError(t, err)
+
+	ips, ttl, err := d.resolve("web")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.1.1.1", "2001:db8::1"}, []string{ips[0].String(), ips[1].String()})
+	assert.Equal(t, 30*time.Second, ttl, "TTL should be capped by max_cache_age_sec")
+
+	ips, _, err = d.resolve("db.example.com")
+	assert.NoError(t, err)
+	assert.Len(t, ips, 1)
+	assert.Equal(t, "10.1.1.2", ips[0].String())
+
+	// IP literals are not looked up.
+	ips, _, err = d.resolve("10.3.3.3")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.3.3.3", ips[0].String())
+
+	_, _, err = d.resolve("unknown")
+	assert.Error(t, err)
+
+	em := d.resolverMetrics(time.Now())
+	assert.Equal(t, int64(8), em.Metric("doh_queries").(*metrics.Int).Int64())
+	// All 4 queries for "unknown" fail with NXDOMAIN.
+	assert.Equal(t, int64(4), em.Metric("doh_failures").(*metrics.Int).Int64())
+	assert.Equal(t, ts.URL, em.Label("server"))
+}
+
+func TestDoHResolverErrors(t *testing.T) {
+	ts := startTestDoHServer(t, map[string][]string{
+		"web.internal.example.com.": {"10.1.1.1"},
+	}, 300)
+
+	for _, server := range []string{"http://dns.example.com/dns-query", "dns.example.com", "https://"} {
+		_, err := newDoHResolver(testDoHConfig(server))
+		assert.Error(t, err, server)
+	}
+
+	// Missing authorization header.
+	c := testDoHConfig(ts.URL)
+	c.HttpHeader = nil
+	d, err := newDoHResolver(c)
+	assert.NoError(t, err)
+	_, _, err = d.resolve("web")
+	assert.ErrorContains(t, err, "401")
+
+	// Server's certificate is not trusted.
+	c = testDoHConfig(ts.URL)
+	c.TlsConfig = nil
+	d, err = newDoHResolver(c)
+	assert.NoError(t, err)
+	_, _, err = d.resolve("web")
+	assert.Error(t, err)
+}
+
+func TestNewWithGlobalDoHResolver(t *testing.T) {
+	ts := startTestDoHServer(t, map[string][]string{
+		"host1.internal.example.com.": {"10.2.2.1"},
+	}, 300)
+
+	globalOpts := &targetspb.GlobalTargetsOptions{DnsResolver: testDoHConfig(ts.URL)}
+	var tgts []Targets
+	for i := 0; i < 2; i++ {
+		tgt, err := New(&targetspb.TargetsDef{
+			Type: &targetspb.TargetsDef_HostNames{HostNames: "host1"},
+		}, nil, globalOpts, nil, nil)
+		assert.NoError(t, err)
+		tgts = append(tgts, tgt)
+	}
+
+	// Both targets share the same resolver, and hence the cache.
+	assert.Same(t, tgts[0].(*targets).resolver, tgts[1].(*targets).resolver)
+	assert.True(t, RequiresResolveFirst(tgts[0]))
+	assert.False(t, RequiresResolveFirst(StaticTargets("host1")))
+
+	ip, err := tgts[0].Resolve("host1", 4)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.2.2.1", ip.String())
+
+	var found bool
+	for _, em := range DNSResolverMetrics(time.Now()) {
+		if em.Label("server") == ts.URL {
+			found = true
+			assert.Equal(t, int64(2), em.Metric("doh_queries").(*metrics.Int).Int64())
+		}
+	}
+	assert.True(t, found, "DNS resolver metrics not found for %s", ts.URL)
+}
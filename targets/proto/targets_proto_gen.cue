@@ -5,7 +5,9 @@ import (
 	proto_1 "github.com/cloudprober/cloudprober/internal/rds/proto"
 	proto_5 "github.com/cloudprober/cloudprober/targets/gce/proto"
 	proto_A "github.com/cloudprober/cloudprober/targets/file/proto"
-	proto_8 "github.com/cloudprober/cloudprober/targets/lameduck/proto"
+	proto_8 "github.com/cloudprober/cloudprober/targets/srv/proto"
+	proto_E "github.com/cloudprober/cloudprober/internal/tlsconfig/proto"
+	proto_B "github.com/cloudprober/cloudprober/targets/lameduck/proto"
 )
 
 #RDSTargets: {
@@ -32,6 +34,15 @@ import (
 
 	// IP config to specify the IP address to pick for a resource.
 	ipConfig?: proto_1.#IPConfig @protobuf(4,rds.IPConfig,name=ip_config)
+
+	// Resource discovery failure handling. Failed refreshes are retried up to
+	// max_retries times, with exponential backoff starting at
+	// retry_initial_backoff_msec. During a discovery outage, last known good
+	// targets are retained for max_staleness_sec (0 for no limit), and probes
+	// export the "targets_stale" metric. See rds.ClientConf for the defaults.
+	maxRetries?:              int32 @protobuf(5,int32,name=max_retries)
+	retryInitialBackoffMsec?: int32 @protobuf(6,int32,name=retry_initial_backoff_msec)
+	maxStalenessSec?:         int32 @protobuf(7,int32,name=max_staleness_sec)
 }
 
 #K8sTargets: {
@@ -155,6 +166,34 @@ import (
 		//   services: ""
 		// }
 		k8s: #K8sTargets @protobuf(6,K8sTargets)
+	} | {
+		// DNS SRV record based targets. Each SRV record becomes a target, with
+		// record's target host as the endpoint name and record's port as the
+		// endpoint port. Records are refreshed as per their TTL.
+		// Example:
+		// srv_targets {
+		//   name: "_http._tcp.service.example.com"
+		//   max_priority: 0
+		// }
+		srvTargets: proto_8.#TargetsConf @protobuf(7,srv.TargetsConf,name=srv_targets)
+	} | {
+		// Targets read from the standard input at startup, one target per line.
+		// Empty lines and lines starting with '#' are ignored. Standard input is
+		// read only once, and all probes using stdin_targets share the same
+		// targets. It's useful for ad-hoc runs, for example:
+		//   cat hosts.txt | cloudprober --config_file=sweep.cfg
+		// Example:
+		// stdin_targets {}
+		stdinTargets: #StdinTargets @protobuf(8,StdinTargets,name=stdin_targets)
+	} | {
+		// Targets generated by expanding CIDR ranges, one target per host
+		// address. Target names are the IP addresses, so metrics are labeled by
+		// IP. It's useful for sweeping a subnet, for example, to find live hosts.
+		// Example:
+		// cidr_targets {
+		//   cidr: "10.0.0.0/28"
+		// }
+		cidrTargets: #CIDRTargets @protobuf(9,CIDRTargets,name=cidr_targets)
 	} | {
 		// Empty targets to meet the probe definition requirement where there are
 		// actually no targets, for example in case of some external probes.
@@ -186,6 +225,65 @@ import (
 	// configurator) service. This functionality works only if lame_duck_options
 	// are specified.
 	excludeLameducks?: bool @protobuf(22,bool,name=exclude_lameducks,default)
+
+	// DNS resolver to use for resolving these targets. If not specified, a
+	// global resolver, based on the system's DNS configuration, is used.
+	// It's not supported for the targets types that resolve names themselves:
+	// shared_targets, rds_targets, k8s, dummy_targets and extensions.
+	// Example:
+	//   dns_resolver {
+	//     server: "10.0.0.53:53"
+	//     search_domain: "internal.example.com"
+	//   }
+	dnsResolver?: #DNSResolverConfig @protobuf(24,DNSResolverConfig,name=dns_resolver)
+}
+
+// DNSResolverConfig configures a custom DNS resolver for resolving targets.
+#DNSResolverConfig: {
+	// DNS server address, in "host:port" or "host" format. If port is not
+	// specified, 53 is used. For the HTTPS protocol, it's the DNS-over-HTTPS
+	// endpoint URL, e.g. "https://dns.example.com/dns-query".
+	server?: string @protobuf(1,string)
+
+	#Protocol: {"UDP", #enumValue: 0} |
+		{"TCP", #enumValue: 1} | {
+			// DNS-over-HTTPS (RFC 8484). Resolved IPs are cached as per the
+			// responses' TTL, capped by max_cache_age_sec.
+			"HTTPS"
+			#enumValue: 2
+		}
+
+	#Protocol_value: {
+		UDP:   0
+		TCP:   1
+		HTTPS: 2
+	}
+	protocol?: #Protocol @protobuf(2,Protocol,"default=UDP")
+
+	// Search domains to try while resolving names. For names without a dot,
+	// search domains are tried before the name itself; for the names with a
+	// dot, the name itself is tried first.
+	searchDomain?: [...string] @protobuf(3,string,name=search_domain)
+
+	// How long to cache the resolved IPs. Default is 5 minutes.
+	maxCacheAgeSec?: int32 @protobuf(4,int32,name=max_cache_age_sec)
+
+	// Timeout for DNS queries.
+	timeoutMsec?: int32 @protobuf(5,int32,name=timeout_msec,"default=5000")
+
+	// TLS config for DNS-over-HTTPS, e.g. to use a client certificate for
+	// authentication, or a custom CA.
+	tlsConfig?: proto_E.#TLSConfig @protobuf(6,tlsconfig.TLSConfig,name=tls_config)
+
+	// HTTP headers to add to the DNS-over-HTTPS requests, e.g. for
+	// authorization:
+	//   http_header {
+	//     key: "Authorization"
+	//     value: "Bearer {{envSecret "DOH_TOKEN"}}"
+	//   }
+	httpHeader?: {
+		[string]: string
+	} @protobuf(7,map[string]string,http_header)
 }
 
 // DummyTargets represent empty targets, which are useful for external
@@ -193,6 +291,26 @@ import (
 #DummyTargets: {
 }
 
+// StdinTargets represent targets read from the standard input.
+#StdinTargets: {
+}
+
+// CIDRTargets represent targets generated from CIDR ranges.
+#CIDRTargets: {
+	// CIDR ranges to expand, e.g. "10.0.0.0/24" or "2001:db8::/120".
+	cidr?: [...string] @protobuf(1,string)
+
+	// Maximum number of hosts across all the CIDR ranges. This is a safety cap
+	// to avoid accidentally probing very large ranges. Configuration is
+	// rejected if ranges expand to more hosts than this.
+	maxHosts?: int32 @protobuf(2,int32,name=max_hosts,"default=1024")
+
+	// Whether to skip the network and broadcast addresses (first and last
+	// address) of IPv4 ranges. This is not applicable to /31 and /32 ranges,
+	// and to IPv6 ranges.
+	skipNetworkBroadcast?: bool @protobuf(3,bool,name=skip_network_broadcast,default)
+}
+
 // Global targets options. These options are independent of the per-probe
 // targets which are defined by the "Targets" type above.
 //
@@ -219,5 +337,14 @@ import (
 
 	// Lame duck options. If provided, targets module checks for the lame duck
 	// targets and removes them from the targets list.
-	lameDuckOptions?: proto_8.#Options @protobuf(2,lameduck.Options,name=lame_duck_options)
+	lameDuckOptions?: proto_B.#Options @protobuf(2,lameduck.Options,name=lame_duck_options)
+
+	// DNS resolver for all the targets that don't specify their own resolver
+	// (see dns_resolver in TargetsDef). Targets types that resolve names
+	// themselves ignore it. It's useful, for example, to resolve
+	// all the targets through DNS-over-HTTPS in environments where that's the
+	// only permitted name resolution path. Note that HTTP and TCP probes use
+	// the targets resolver only if resolve_first is set. For DNS-over-HTTPS,
+	// resolve_first is implied, and setting it to false is an error.
+	dnsResolver?: #DNSResolverConfig @protobuf(5,DNSResolverConfig,name=dns_resolver)
 }
@@ -32,6 +32,7 @@ type cacheRecord struct {
 	ip4              net.IP
 	ip6              net.IP
 	lastUpdatedAt    time.Time
+	ttl              time.Duration // If set, caps the max age.
 	err              error
 	mu               sync.Mutex
 	updateInProgress bool
@@ -44,6 +45,10 @@ type Resolver struct {
 	mu            sync.Mutex
 	DefaultMaxAge time.Duration
 	resolve       func(string) ([]net.IP, error) // used for testing
+
+	// resolveTTL, if set, is used instead of resolve. It also returns the
+	// TTL of the resolved IPs, which is used as the cache record's max age.
+	resolveTTL func(string) ([]net.IP, time.Duration, error)
 }
 
 // ipVersion tells if an IP address is IPv4 or IPv6.
@@ -61,21 +66,26 @@ func ipVersion(ip net.IP) int {
 // takes more than defaultMaxAge.
 // Has the potential of creating a bunch of pending goroutines if backend
 // resolve call has a tendency of indefinitely hanging.
-func (r *Resolver) resolveOrTimeout(name string) ([]net.IP, error) {
+func (r *Resolver) resolveOrTimeout(name string) ([]net.IP, time.Duration, error) {
 	var ips []net.IP
+	var ttl time.Duration
 	var err error
 	doneChan := make(chan struct{})
 
 	go func() {
-		ips, err = r.resolve(name)
+		if r.resolveTTL != nil {
+			ips, ttl, err = r.resolveTTL(name)
+		} else {
+			ips, err = r.resolve(name)
+		}
 		close(doneChan)
 	}()
 
 	select {
 	case <-doneChan:
-		return ips, err
+		return ips, ttl, err
 	case <-time.After(defaultMaxAge):
-		return nil, fmt.Errorf("timed out after %v", defaultMaxAge)
+		return nil, 0, fmt.Errorf("timed out after %v", defaultMaxAge)
 	}
 }
 
@@ -139,10 +149,10 @@ func (r *Resolver) resolveWithMaxAge(name string, ipVer int, maxAge time.Duratio
 }
 
 // refresh refreshes the cacheRecord by making a call to the provided "resolve" function.
-func (cr *cacheRecord) refresh(name string, resolve func(string) ([]net.IP, error), refreshed chan<- bool) {
+func (cr *cacheRecord) refresh(name string, resolve func(string) ([]net.IP, time.Duration, error), refreshed chan<- bool) {
 	// Note that we call backend's resolve outside of the mutex locks and take the lock again
 	// to update the cache record once we have the results from the backend.
-	ips, err := resolve(name)
+	ips, ttl, err := resolve(name)
 
 	cr.mu.Lock()
 	defer cr.mu.Unlock()
@@ -155,6 +165,7 @@ func (cr *cacheRecord) refresh(name string, resolve func(string) ([]net.IP, erro
 	if err != nil {
 		return
 	}
+	cr.ttl = ttl
 	cr.ip4 = nil
 	cr.ip6 = nil
 	for _, ip := range ips {
@@ -174,11 +185,15 @@ func (cr *cacheRecord) refresh(name string, resolve func(string) ([]net.IP, erro
 // If cache record is new, blocks until it's resolved for the first time.
 // If cache record needs updating, kicks off refresh asynchronously.
 // If cache record is already being updated or fresh enough, returns immediately.
-func (cr *cacheRecord) refreshIfRequired(name string, resolve func(string) ([]net.IP, error), maxAge time.Duration, refreshed chan<- bool) {
+func (cr *cacheRecord) refreshIfRequired(name string, resolve func(string) ([]net.IP, time.Duration, error), maxAge time.Duration, refreshed chan<- bool) {
 	cr.callInit.Do(func() { cr.refresh(name, resolve, refreshed) })
 	cr.mu.Lock()
 	defer cr.mu.Unlock()
 
+	if cr.ttl > 0 && cr.ttl < maxAge {
+		maxAge = cr.ttl
+	}
+
 	// Cache record is old and no update in progress, issue a request to update.
 	if !cr.updateInProgress && time.Since(cr.lastUpdatedAt) >= maxAge {
 		cr.updateInProgress = true
@@ -198,6 +213,17 @@ func NewWithResolve(resolveFunc func(string) ([]net.IP, error)) *Resolver {
 	}
 }
 
+// NewWithResolveTTL returns a new Resolver with the given backend resolver,
+// which also returns the TTL of the resolved IPs. Cache records are
+// refreshed as per their TTL, or the requested max age if that's shorter.
+func NewWithResolveTTL(resolveFunc func(string) ([]net.IP, time.Duration, error)) *Resolver {
+	return &Resolver{
+		cache:         make(map[string]*cacheRecord),
+		resolveTTL:    resolveFunc,
+		DefaultMaxAge: defaultMaxAge,
+	}
+}
+
 // New returns a new Resolver.
 func New() *Resolver {
 	return NewWithResolve(net.LookupIP)
@@ -121,6 +121,57 @@ func TestResolveWithMaxAge(t *testing.T) {
 	}
 }
 
+func TestResolveWithTTL(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	r := NewWithResolveTTL(func(name string) ([]net.IP, time.Duration, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return []net.IP{net.ParseIP("1.2.3.4")}, time.Hour, nil
+	})
+	refreshed := make(chan bool, 2)
+
+	// First resolve populates the cache with the TTL.
+	r.resolveWithMaxAge("hostA", 4, 2*time.Hour, refreshed)
+	if !waitForChannelOrFail(t, refreshed, time.Second) {
+		t.Errorf("refreshed returned false, want true")
+	}
+	if waitForChannelOrFail(t, refreshed, time.Second) {
+		t.Errorf("refreshed returned true, want false")
+	}
+
+	// Within the TTL and the max age, no refresh.
+	ip, err := r.resolveWithMaxAge("hostA", 4, 2*time.Hour, refreshed)
+	mu.Lock()
+	verify("within-ttl", t, ip, net.ParseIP("1.2.3.4"), calls, 1, err)
+	mu.Unlock()
+	if waitForChannelOrFail(t, refreshed, time.Second) {
+		t.Errorf("refreshed returned true, want false")
+	}
+
+	// Max age shorter than the TTL wins.
+	r.resolveWithMaxAge("hostA", 4, 0, refreshed)
+	if !waitForChannelOrFail(t, refreshed, time.Second) {
+		t.Errorf("refreshed returned false, want true")
+	}
+
+	// Make the TTL short, TTL shorter than the max age wins.
+	r.cache["hostA"].mu.Lock()
+	r.cache["hostA"].ttl = time.Millisecond
+	r.cache["hostA"].mu.Unlock()
+	time.Sleep(2 * time.Millisecond)
+	r.resolveWithMaxAge("hostA", 4, time.Hour, refreshed)
+	if !waitForChannelOrFail(t, refreshed, time.Second) {
+		t.Errorf("refreshed returned false, want true")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 3 {
+		t.Errorf("backend calls=%d, want=3", calls)
+	}
+}
+
 func TestResolveErr(t *testing.T) {
 	cnt := 0
 	r := &Resolver{
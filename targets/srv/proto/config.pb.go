@@ -0,0 +1,232 @@
+// Configuration proto for DNS SRV record based targets.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.21.5
+// source: github.com/cloudprober/cloudprober/targets/srv/proto/config.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TargetsConf struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// SRV record name to look up, e.g. "_http._tcp.service.example.com". Each
+	// record in the answer becomes a target, with target host as the endpoint
+	// name and SRV port as the endpoint port.
+	Name *string `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	// DNS server to send SRV queries to, in "host:port" format. If not
+	// specified, first server from /etc/resolv.conf is used.
+	DnsServer *string `protobuf:"bytes,2,opt,name=dns_server,json=dnsServer" json:"dns_server,omitempty"`
+	// If set, only records with the lowest priority value (i.e. the most
+	// preferred records) are used as targets.
+	LowestPriorityOnly *bool `protobuf:"varint,3,opt,name=lowest_priority_only,json=lowestPriorityOnly" json:"lowest_priority_only,omitempty"`
+	// If specified, records with the priority value greater than this are
+	// ignored. For example, max_priority: 0 will probe only priority-0 records.
+	MaxPriority *int32 `protobuf:"varint,4,opt,name=max_priority,json=maxPriority" json:"max_priority,omitempty"`
+	// If set, records with zero weight are ignored. As per RFC 2782, zero
+	// weight records should be selected only if no other records are available.
+	SkipZeroWeight *bool `protobuf:"varint,5,opt,name=skip_zero_weight,json=skipZeroWeight" json:"skip_zero_weight,omitempty"`
+	// Records are re-looked up after their TTL expires, but not more often than
+	// min_refresh_sec and not less often than max_refresh_sec.
+	MinRefreshSec *int32 `protobuf:"varint,6,opt,name=min_refresh_sec,json=minRefreshSec,def=10" json:"min_refresh_sec,omitempty"`
+	MaxRefreshSec *int32 `protobuf:"varint,7,opt,name=max_refresh_sec,json=maxRefreshSec,def=300" json:"max_refresh_sec,omitempty"`
+}
+
+// Default values for TargetsConf fields.
+const (
+	Default_TargetsConf_MinRefreshSec = int32(10)
+	Default_TargetsConf_MaxRefreshSec = int32(300)
+)
+
+func (x *TargetsConf) Reset() {
+	*x = TargetsConf{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TargetsConf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TargetsConf) ProtoMessage() {}
+
+func (x *TargetsConf) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TargetsConf.ProtoReflect.Descriptor instead.
+func (*TargetsConf) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TargetsConf) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *TargetsConf) GetDnsServer() string {
+	if x != nil && x.DnsServer != nil {
+		return *x.DnsServer
+	}
+	return ""
+}
+
+func (x *TargetsConf) GetLowestPriorityOnly() bool {
+	if x != nil && x.LowestPriorityOnly != nil {
+		return *x.LowestPriorityOnly
+	}
+	return false
+}
+
+func (x *TargetsConf) GetMaxPriority() int32 {
+	if x != nil && x.MaxPriority != nil {
+		return *x.MaxPriority
+	}
+	return 0
+}
+
+func (x *TargetsConf) GetSkipZeroWeight() bool {
+	if x != nil && x.SkipZeroWeight != nil {
+		return *x.SkipZeroWeight
+	}
+	return false
+}
+
+func (x *TargetsConf) GetMinRefreshSec() int32 {
+	if x != nil && x.MinRefreshSec != nil {
+		return *x.MinRefreshSec
+	}
+	return Default_TargetsConf_MinRefreshSec
+}
+
+func (x *TargetsConf) GetMaxRefreshSec() int32 {
+	if x != nil && x.MaxRefreshSec != nil {
+		return *x.MaxRefreshSec
+	}
+	return Default_TargetsConf_MaxRefreshSec
+}
+
+var File_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto protoreflect.FileDescriptor
+
+var file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_rawDesc = []byte{
+	0x0a, 0x41, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x72, 0x2f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x2f, 0x73, 0x72, 0x76,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x17, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
+	0x2e, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x2e, 0x73, 0x72, 0x76, 0x22, 0x98, 0x02, 0x0a,
+	0x0b, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x6e, 0x73, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x6e, 0x73, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12,
+	0x30, 0x0a, 0x14, 0x6c, 0x6f, 0x77, 0x65, 0x73, 0x74, 0x5f, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69,
+	0x74, 0x79, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x6c,
+	0x6f, 0x77, 0x65, 0x73, 0x74, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x4f, 0x6e, 0x6c,
+	0x79, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74,
+	0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x50, 0x72, 0x69, 0x6f,
+	0x72, 0x69, 0x74, 0x79, 0x12, 0x28, 0x0a, 0x10, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x7a, 0x65, 0x72,
+	0x6f, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e,
+	0x73, 0x6b, 0x69, 0x70, 0x5a, 0x65, 0x72, 0x6f, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x2a,
+	0x0a, 0x0f, 0x6d, 0x69, 0x6e, 0x5f, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x5f, 0x73, 0x65,
+	0x63, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x3a, 0x02, 0x31, 0x30, 0x52, 0x0d, 0x6d, 0x69, 0x6e,
+	0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x53, 0x65, 0x63, 0x12, 0x2b, 0x0a, 0x0f, 0x6d, 0x61,
+	0x78, 0x5f, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x05, 0x3a, 0x03, 0x33, 0x30, 0x30, 0x52, 0x0d, 0x6d, 0x61, 0x78, 0x52, 0x65, 0x66,
+	0x72, 0x65, 0x73, 0x68, 0x53, 0x65, 0x63, 0x42, 0x36, 0x5a, 0x34, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65,
+	0x72, 0x2f, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2f, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x73, 0x2f, 0x73, 0x72, 0x76, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+}
+
+var (
+	file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_rawDescOnce sync.Once
+	file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_rawDescData = file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_rawDesc
+)
+
+func file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_rawDescGZIP() []byte {
+	file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_rawDescOnce.Do(func() {
+		file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_rawDescData)
+	})
+	return file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_rawDescData
+}
+
+var file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_goTypes = []interface{}{
+	(*TargetsConf)(nil), // 0: cloudprober.targets.srv.TargetsConf
+}
+var file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_init() }
+func file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_init() {
+	if File_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TargetsConf); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_goTypes,
+		DependencyIndexes: file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_depIdxs,
+		MessageInfos:      file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_msgTypes,
+	}.Build()
+	File_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto = out.File
+	file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_rawDesc = nil
+	file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_goTypes = nil
+	file_github_com_cloudprober_cloudprober_targets_srv_proto_config_proto_depIdxs = nil
+}
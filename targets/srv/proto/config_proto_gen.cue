@@ -0,0 +1,29 @@
+package proto
+
+#TargetsConf: {
+	// SRV record name to look up, e.g. "_http._tcp.service.example.com". Each
+	// record in the answer becomes a target, with target host as the endpoint
+	// name and SRV port as the endpoint port.
+	name?: string @protobuf(1,string)
+
+	// DNS server to send SRV queries to, in "host:port" format. If not
+	// specified, first server from /etc/resolv.conf is used.
+	dnsServer?: string @protobuf(2,string,name=dns_server)
+
+	// If set, only records with the lowest priority value (i.e. the most
+	// preferred records) are used as targets.
+	lowestPriorityOnly?: bool @protobuf(3,bool,name=lowest_priority_only)
+
+	// If specified, records with the priority value greater than this are
+	// ignored. For example, max_priority: 0 will probe only priority-0 records.
+	maxPriority?: int32 @protobuf(4,int32,name=max_priority)
+
+	// If set, records with zero weight are ignored. As per RFC 2782, zero
+	// weight records should be selected only if no other records are available.
+	skipZeroWeight?: bool @protobuf(5,bool,name=skip_zero_weight)
+
+	// Records are re-looked up after their TTL expires, but not more often than
+	// min_refresh_sec and not less often than max_refresh_sec.
+	minRefreshSec?: int32 @protobuf(6,int32,name=min_refresh_sec,"default=10")
+	maxRefreshSec?: int32 @protobuf(7,int32,name=max_refresh_sec,"default=300")
+}
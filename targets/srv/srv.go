@@ -0,0 +1,243 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package srv implements DNS SRV record based targets for cloudprober.
+*/
+package srv
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	configpb "github.com/cloudprober/cloudprober/targets/srv/proto"
+	"github.com/miekg/dns"
+)
+
+const resolvConfFile = "/etc/resolv.conf"
+
+// record is an SRV record along with its TTL.
+type record struct {
+	target   string
+	port     int
+	priority int
+	weight   int
+	ttl      time.Duration
+}
+
+// lookupFunc looks up SRV records for the given name.
+type lookupFunc func(name string) ([]record, error)
+
+// Targets implements SRV record based targets.
+type Targets struct {
+	c        *configpb.TargetsConf
+	resolver endpoint.Resolver
+	lookup   lookupFunc
+	l        *logger.Logger
+
+	// refreshMu serializes the refreshes triggered by ListEndpoints, so that
+	// concurrent callers don't look up the records at the same time, or
+	// overwrite newer endpoints with older ones.
+	refreshMu sync.Mutex
+
+	mu        sync.RWMutex
+	endpoints []endpoint.Endpoint
+	nextCheck time.Time
+}
+
+// dnsLookup returns a lookupFunc that queries the given DNS server.
+func dnsLookup(server string) lookupFunc {
+	client := &dns.Client{Timeout: 5 * time.Second}
+
+	return func(name string) ([]record, error) {
+		msg := new(dns.Msg)
+		msg.SetQuestion(dns.Fqdn(name), dns.TypeSRV)
+
+		resp, _, err := client.Exchange(msg, server)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Rcode != dns.RcodeSuccess {
+			return nil, fmt.Errorf("SRV lookup for %s failed, rcode: %s", name, dns.RcodeToString[resp.Rcode])
+		}
+
+		var records []record
+		for _, rr := range resp.Answer {
+			srv, ok := rr.(*dns.SRV)
+			if !ok {
+				continue
+			}
+			records = append(records, record{
+				target:   strings.TrimSuffix(srv.Target, "."),
+				port:     int(srv.Port),
+				priority: int(srv.Priority),
+				weight:   int(srv.Weight),
+				ttl:      time.Duration(srv.Hdr.Ttl) * time.Second,
+			})
+		}
+		return records, nil
+	}
+}
+
+func defaultDNSServer() (string, error) {
+	cc, err := dns.ClientConfigFromFile(resolvConfFile)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %v", resolvConfFile, err)
+	}
+	if len(cc.Servers) == 0 {
+		return "", fmt.Errorf("no DNS servers found in %s", resolvConfFile)
+	}
+	return net.JoinHostPort(cc.Servers[0], cc.Port), nil
+}
+
+// filterRecords filters records as per the priority and weight options.
+func (t *Targets) filterRecords(records []record) []record {
+	minPriority := -1
+	for _, r := range records {
+		if minPriority == -1 || r.priority < minPriority {
+			minPriority = r.priority
+		}
+	}
+
+	var result []record
+	for _, r := range records {
+		if t.c.MaxPriority != nil && r.priority > int(t.c.GetMaxPriority()) {
+			continue
+		}
+		if t.c.GetLowestPriorityOnly() && r.priority != minPriority {
+			continue
+		}
+		if t.c.GetSkipZeroWeight() && r.weight == 0 {
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// refreshInterval computes the next refresh interval from the records' TTL,
+// bounded by the configured min and max refresh intervals.
+func (t *Targets) refreshInterval(records []record) time.Duration {
+	minIntv := time.Duration(t.c.GetMinRefreshSec()) * time.Second
+	maxIntv := time.Duration(t.c.GetMaxRefreshSec()) * time.Second
+
+	intv := maxIntv
+	for _, r := range records {
+		if r.ttl < intv {
+			intv = r.ttl
+		}
+	}
+	if intv < minIntv {
+		intv = minIntv
+	}
+	return intv
+}
+
+func (t *Targets) refresh() {
+	records, err := t.lookup(t.c.GetName())
+	if err != nil {
+		t.l.Warningf("srv_targets: error looking up %s: %v", t.c.GetName(), err)
+
+		// Keep using the old endpoints, and retry after the min interval.
+		t.mu.Lock()
+		t.nextCheck = time.Now().Add(time.Duration(t.c.GetMinRefreshSec()) * time.Second)
+		t.mu.Unlock()
+		return
+	}
+
+	intv := t.refreshInterval(records)
+	records = t.filterRecords(records)
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].target != records[j].target {
+			return records[i].target < records[j].target
+		}
+		return records[i].port < records[j].port
+	})
+
+	now := time.Now()
+	eps := make([]endpoint.Endpoint, 0, len(records))
+	for _, r := range records {
+		eps = append(eps, endpoint.Endpoint{
+			Name: r.target,
+			Port: r.port,
+			Labels: map[string]string{
+				"srv_priority": strconv.Itoa(r.priority),
+				"srv_weight":   strconv.Itoa(r.weight),
+			},
+			LastUpdated: now,
+		})
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.endpoints = eps
+	t.nextCheck = now.Add(intv)
+}
+
+// ListEndpoints returns the list of endpoints, refreshing them if the records'
+// TTL has expired.
+func (t *Targets) ListEndpoints() []endpoint.Endpoint {
+	t.refreshMu.Lock()
+	t.mu.RLock()
+	refresh := time.Now().After(t.nextCheck)
+	t.mu.RUnlock()
+
+	if refresh {
+		t.refresh()
+	}
+	t.refreshMu.Unlock()
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return append([]endpoint.Endpoint{}, t.endpoints...)
+}
+
+// Resolve resolves the name using the underlying resolver.
+func (t *Targets) Resolve(name string, ipVer int) (net.IP, error) {
+	return t.resolver.Resolve(name, ipVer)
+}
+
+// New returns new SRV targets.
+func New(c *configpb.TargetsConf, res endpoint.Resolver, l *logger.Logger) (*Targets, error) {
+	if c.GetName() == "" {
+		return nil, fmt.Errorf("srv_targets: name is required")
+	}
+	if c.GetMinRefreshSec() > c.GetMaxRefreshSec() {
+		return nil, fmt.Errorf("srv_targets: min_refresh_sec (%d) is greater than max_refresh_sec (%d)", c.GetMinRefreshSec(), c.GetMaxRefreshSec())
+	}
+
+	server := c.GetDnsServer()
+	if server == "" {
+		var err error
+		if server, err = defaultDNSServer(); err != nil {
+			return nil, fmt.Errorf("srv_targets: %v", err)
+		}
+	}
+
+	t := &Targets{
+		c:        c,
+		resolver: res,
+		lookup:   dnsLookup(server),
+		l:        l,
+	}
+	t.refresh()
+	return t, nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package srv
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	configpb "github.com/cloudprober/cloudprober/targets/srv/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+var testRecords = []record{
+	{target: "web-2.example.com", port: 8080, priority: 0, weight: 10, ttl: 60 * time.Second},
+	{target: "web-1.example.com", port: 8080, priority: 0, weight: 0, ttl: 30 * time.Second},
+	{target: "web-3.example.com", port: 8081, priority: 10, weight: 5, ttl: 60 * time.Second},
+}
+
+func testTargets(c *configpb.TargetsConf, lookup lookupFunc) *Targets {
+	c.Name = proto.String("_http._tcp.example.com")
+	t := &Targets{c: c, lookup: lookup}
+	t.refresh()
+	return t
+}
+
+func names(eps []endpoint.Endpoint) []string {
+	return endpoint.NamesFromEndpoints(eps)
+}
+
+func TestListEndpoints(t *testing.T) {
+	lookup := func(string) ([]record, error) { return testRecords, nil }
+
+	tests := []struct {
+		name string
+		c    *configpb.TargetsConf
+		want []string
+	}{
+		{
+			name: "all",
+			c:    &configpb.TargetsConf{},
+			want: []string{"web-1.example.com", "web-2.example.com", "web-3.example.com"},
+		},
+		{
+			name: "max_priority_0",
+			c:    &configpb.TargetsConf{MaxPriority: proto.Int32(0)},
+			want: []string{"web-1.example.com", "web-2.example.com"},
+		},
+		{
+			name: "lowest_priority_only",
+			c:    &configpb.TargetsConf{LowestPriorityOnly: proto.Bool(true)},
+			want: []string{"web-1.example.com", "web-2.example.com"},
+		},
+		{
+			name: "skip_zero_weight",
+			c:    &configpb.TargetsConf{SkipZeroWeight: proto.Bool(true)},
+			want: []string{"web-2.example.com", "web-3.example.com"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tgts := testTargets(test.c, lookup)
+			assert.Equal(t, test.want, names(tgts.ListEndpoints()))
+		})
+	}
+}
+
+func TestEndpointFields(t *testing.T) {
+	tgts := testTargets(&configpb.TargetsConf{}, func(string) ([]record, error) { return testRecords[2:], nil })
+	want := []endpoint.Endpoint{
+		{
+			Name: "web-3.example.com",
+			Port: 8081,
+			Labels: map[string]string{
+				"srv_priority": "10",
+				"srv_weight":   "5",
+			},
+		},
+	}
+	got := tgts.ListEndpoints()
+	for i := range got {
+		got[i].LastUpdated = time.Time{}
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestRefresh(t *testing.T) {
+	var records []record
+	var lookupErr error
+	lookupCount := 0
+	lookup := func(string) ([]record, error) {
+		lookupCount++
+		return records, lookupErr
+	}
+
+	records = testRecords[:1]
+	tgts := testTargets(&configpb.TargetsConf{}, lookup)
+	assert.Equal(t, []string{"web-2.example.com"}, names(tgts.ListEndpoints()))
+	assert.Equal(t, 1, lookupCount, "lookup count")
+
+	// Next check should be as per the record TTL.
+	assert.WithinDuration(t, time.Now().Add(60*time.Second), tgts.nextCheck, time.Second)
+
+	// Force expiry. Lookup failure should retain the old targets.
+	tgts.nextCheck = time.Time{}
+	lookupErr = errors.New("lookup error")
+	assert.Equal(t, []string{"web-2.example.com"}, names(tgts.ListEndpoints()))
+	assert.Equal(t, 2, lookupCount, "lookup count")
+
+	tgts.nextCheck = time.Time{}
+	lookupErr = nil
+	records = testRecords
+	assert.Equal(t, []string{"web-1.example.com", "web-2.example.com", "web-3.example.com"}, names(tgts.ListEndpoints()))
+
+	// Not expired yet, no new lookup.
+	assert.Equal(t, 3, lookupCount, "lookup count")
+	tgts.ListEndpoints()
+	assert.Equal(t, 3, lookupCount, "lookup count")
+}
+
+func TestConcurrentListEndpoints(t *testing.T) {
+	var lookupCount atomic.Int32
+	lookup := func(string) ([]record, error) {
+		lookupCount.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return testRecords, nil
+	}
+	tgts := testTargets(&configpb.TargetsConf{}, lookup)
+
+	tgts.mu.Lock()
+	tgts.nextCheck = time.Time{}
+	tgts.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Len(t, tgts.ListEndpoints(), 3)
+		}()
+	}
+	wg.Wait()
+
+	// Only one of the concurrent calls refreshes the targets.
+	assert.Equal(t, int32(2), lookupCount.Load(), "lookup count")
+}
+
+func TestRefreshInterval(t *testing.T) {
+	tgts := &Targets{c: &configpb.TargetsConf{}}
+
+	tests := []struct {
+		records []record
+		want    time.Duration
+	}{
+		{records: nil, want: 300 * time.Second},
+		{records: []record{{ttl: 60 * time.Second}, {ttl: 30 * time.Second}}, want: 30 * time.Second},
+		{records: []record{{ttl: time.Second}}, want: 10 * time.Second},
+		{records: []record{{ttl: time.Hour}}, want: 300 * time.Second},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, tgts.refreshInterval(test.records))
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&configpb.TargetsConf{}, nil, nil)
+	assert.Error(t, err, "no name")
+
+	_, err = New(&configpb.TargetsConf{
+		Name:          proto.String("_http._tcp.example.com"),
+		MinRefreshSec: proto.Int32(60),
+		MaxRefreshSec: proto.Int32(30),
+	}, nil, nil)
+	assert.Error(t, err, "min_refresh_sec > max_refresh_sec")
+}
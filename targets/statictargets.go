@@ -32,52 +32,56 @@ func staticTargets(hosts string) (Targets, error) {
 		hostsSlice = strings.Fields(hosts)
 	}
 	for _, host := range hostsSlice {
-		host = strings.TrimSpace(host)
-
-		// Make sure there is no "/" in the host name. That typically happens
-		// when users accidentally add URLs in hostnames.
-		if strings.IndexByte(host, '/') >= 0 {
-			return nil, fmt.Errorf("invalid host (%s), contains '/'", host)
+		ep, err := parseHost(strings.TrimSpace(host))
+		if err != nil {
+			return nil, err
 		}
+		sl.list = append(sl.list, ep)
+	}
 
-		hostColonParts := strings.Split(host, ":")
+	t.lister = sl
+	t.resolver = globalResolver
+	return t, nil
+}
 
-		// There is no colon in host name.
-		if len(hostColonParts) == 1 {
-			sl.list = append(sl.list, endpoint.Endpoint{Name: host})
-			continue
-		}
+// parseHost parses a host, optionally with a port, into an endpoint.
+func parseHost(host string) (endpoint.Endpoint, error) {
+	// Make sure there is no "/" in the host name. That typically happens
+	// when users accidentally add URLs in hostnames.
+	if strings.IndexByte(host, '/') >= 0 {
+		return endpoint.Endpoint{}, fmt.Errorf("invalid host (%s), contains '/'", host)
+	}
 
-		// There is only 1 colon, assume it is for the port. An IPv6 address will
-		// more than 1 colon.
-		if len(hostColonParts) == 2 {
-			portNum, err := strconv.Atoi(hostColonParts[1])
-			if err != nil {
-				return nil, fmt.Errorf("error parsing port(%s): %v", hostColonParts[1], err)
-			}
-			sl.list = append(sl.list, endpoint.Endpoint{Name: hostColonParts[0], Port: portNum})
-			continue
-		}
+	hostColonParts := strings.Split(host, ":")
 
-		// More than 1 colon. It should include an IPv6 address.
-		// 1. Parses as an IP address. If that fails,
-		// 2. Parse for IPv6 and port.
-		if ip := net.ParseIP(host); ip != nil {
-			sl.list = append(sl.list, endpoint.Endpoint{Name: host})
-			continue
-		}
-		hostname, port, err := net.SplitHostPort(host)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing host(%s) as hostport: %v", host, err)
-		}
-		portNum, err := strconv.Atoi(port)
+	// There is no colon in host name.
+	if len(hostColonParts) == 1 {
+		return endpoint.Endpoint{Name: host}, nil
+	}
+
+	// There is only 1 colon, assume it is for the port. An IPv6 address will
+	// more than 1 colon.
+	if len(hostColonParts) == 2 {
+		portNum, err := strconv.Atoi(hostColonParts[1])
 		if err != nil {
-			return nil, fmt.Errorf("error parsing port(%s): %v", port, err)
+			return endpoint.Endpoint{}, fmt.Errorf("error parsing port(%s): %v", hostColonParts[1], err)
 		}
-		sl.list = append(sl.list, endpoint.Endpoint{Name: hostname, Port: portNum})
+		return endpoint.Endpoint{Name: hostColonParts[0], Port: portNum}, nil
 	}
 
-	t.lister = sl
-	t.resolver = globalResolver
-	return t, nil
+	// More than 1 colon. It should include an IPv6 address.
+	// 1. Parses as an IP address. If that fails,
+	// 2. Parse for IPv6 and port.
+	if ip := net.ParseIP(host); ip != nil {
+		return endpoint.Endpoint{Name: host}, nil
+	}
+	hostname, port, err := net.SplitHostPort(host)
+	if err != nil {
+		return endpoint.Endpoint{}, fmt.Errorf("error parsing host(%s) as hostport: %v", host, err)
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		return endpoint.Endpoint{}, fmt.Errorf("error parsing port(%s): %v", port, err)
+	}
+	return endpoint.Endpoint{Name: hostname, Port: portNum}, nil
 }
@@ -0,0 +1,76 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targets
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+)
+
+var (
+	// stdin is where stdin targets are read from. It's a variable to allow
+	// overriding it in tests.
+	stdin io.Reader = os.Stdin
+
+	// Standard input can be read only once; we cache the parsed endpoints
+	// (or error) and share them across all the probes using stdin targets.
+	stdinOnce sync.Once
+	stdinEps  []endpoint.Endpoint
+	stdinErr  error
+)
+
+// readTargets reads newline-delimited targets from r. Empty lines and lines
+// starting with '#' are skipped.
+func readTargets(r io.Reader) ([]endpoint.Endpoint, error) {
+	var eps []endpoint.Endpoint
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ep, err := parseHost(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		eps = append(eps, ep)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading targets: %v", err)
+	}
+	return eps, nil
+}
+
+func stdinTargets(l *logger.Logger) (*staticLister, error) {
+	stdinOnce.Do(func() {
+		stdinEps, stdinErr = readTargets(stdin)
+		if stdinErr == nil && len(stdinEps) == 0 {
+			l.Warning("stdin_targets: no targets found in the standard input")
+		}
+	})
+	if stdinErr != nil {
+		return nil, fmt.Errorf("stdin_targets: %v", stdinErr)
+	}
+	return &staticLister{list: stdinEps}, nil
+}
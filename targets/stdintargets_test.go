@@ -0,0 +1,90 @@
+// Copyright 2023 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targets
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadTargets(t *testing.T) {
+	for _, test := range []struct {
+		desc    string
+		input   string
+		want    []endpoint.Endpoint
+		wantErr bool
+	}{
+		{
+			desc: "empty input",
+		},
+		{
+			desc:  "only blank lines and comments",
+			input: "\n  \n# hosts\n",
+		},
+		{
+			desc:  "hosts with ports, no trailing newline",
+			input: "www.google.com\n# comment\n\n 127.0.0.1:8080 \n[2001::2001]:8081",
+			want: []endpoint.Endpoint{
+				{Name: "www.google.com"},
+				{Name: "127.0.0.1", Port: 8080},
+				{Name: "2001::2001", Port: 8081},
+			},
+		},
+		{
+			desc:    "invalid host",
+			input:   "www.google.com\nwww.google.com/url1\n",
+			wantErr: true,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			got, err := readTargets(strings.NewReader(test.input))
+			if test.wantErr {
+				assert.ErrorContains(t, err, "line 2")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestStdinTargets(t *testing.T) {
+	oldStdin := stdin
+	defer func() {
+		stdin = oldStdin
+		stdinOnce = sync.Once{}
+	}()
+	stdin = strings.NewReader("host1\nhost2:9313\n")
+	stdinOnce = sync.Once{}
+
+	tgtsDef := &targetspb.TargetsDef{
+		Type: &targetspb.TargetsDef_StdinTargets{StdinTargets: &targetspb.StdinTargets{}},
+	}
+	want := []endpoint.Endpoint{{Name: "host1"}, {Name: "host2", Port: 9313}}
+
+	// Standard input is read only once, but all targets see the same hosts.
+	for i := 0; i < 2; i++ {
+		tgts, err := New(tgtsDef, nil, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		assert.Equal(t, want, tgts.ListEndpoints())
+	}
+}
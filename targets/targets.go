@@ -41,6 +41,7 @@ import (
 	"github.com/cloudprober/cloudprober/targets/gce"
 	targetspb "github.com/cloudprober/cloudprober/targets/proto"
 	dnsRes "github.com/cloudprober/cloudprober/targets/resolver"
+	"github.com/cloudprober/cloudprober/targets/srv"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
@@ -84,6 +85,52 @@ type Targets interface {
 	endpoint.Resolver
 }
 
+// staleReporter is implemented by the targets that can tell if they are
+// serving stale data because of the discovery failures, e.g. RDS targets.
+type staleReporter interface {
+	Stale() bool
+}
+
+// StaleStatus reports whether the given targets are stale, i.e. recent
+// discovery attempts have failed and last known good targets are being used.
+// ok is false if targets don't track staleness.
+func StaleStatus(t Targets) (stale, ok bool) {
+	// Unwrap the filtering layer. Core listers, including shared targets,
+	// implement Targets.
+	if tt, isTargets := t.(*targets); isTargets {
+		lt, ok := tt.lister.(Targets)
+		if !ok {
+			return false, false
+		}
+		return StaleStatus(lt)
+	}
+
+	sr, ok := t.(staleReporter)
+	if !ok {
+		return false, false
+	}
+	return sr.Stale(), true
+}
+
+// RequiresResolveFirst tells if probes must resolve the targets themselves
+// (through Targets' Resolve) instead of leaving it to the system resolver.
+// It's true for targets that use a DNS-over-HTTPS resolver, as system
+// resolver won't go through it.
+func RequiresResolveFirst(t Targets) bool {
+	tt, isTargets := t.(*targets)
+	if !isTargets {
+		return false
+	}
+	if tt.resolveFirst {
+		return true
+	}
+	// Shared targets carry their own resolver config.
+	if lt, ok := tt.lister.(Targets); ok {
+		return RequiresResolveFirst(lt)
+	}
+	return false
+}
+
 // staticLister is a simple list of hosts that does not change. This corresponds
 // to the "host_names" type in cloudprober/targets/targets.proto.  For
 // example, one could have a probe whose targets are `host_names:
@@ -125,6 +172,9 @@ type targets struct {
 	re              *regexp.Regexp
 	ldLister        endpoint.Lister
 	l               *logger.Logger
+
+	// resolveFirst is set if targets are resolved through DNS-over-HTTPS.
+	resolveFirst bool
 }
 
 // Resolve either resolves a target using the core resolver, or returns an error
@@ -302,6 +352,9 @@ func rdsClientConf(pb *targetspb.RDSTargets, globalOpts *targetspb.GlobalTargets
 			Filter:       pb.GetFilter(),
 			IpConfig:     pb.GetIpConfig(),
 		},
+		MaxRetries:              pb.MaxRetries,
+		RetryInitialBackoffMsec: pb.RetryInitialBackoffMsec,
+		MaxStalenessSec:         pb.MaxStalenessSec,
 	}, nil
 }
 
@@ -323,13 +376,32 @@ func New(targetsDef *targetspb.TargetsDef, ldLister endpoint.Lister, globalOpts
 		return nil, fmt.Errorf("targets.New(): Error making baseTargets: %v", err)
 	}
 
+	// Resolver to use for the targets types that rely on DNS resolution.
+	// Targets' own resolver config takes precedence over the global one.
+	res := globalResolver
+	resolverConf := targetsDef.GetDnsResolver()
+	if resolverConf == nil {
+		resolverConf = globalOpts.GetDnsResolver()
+	}
+	if resolverConf != nil {
+		if res, err = newResolverFromConfig(resolverConf, globalLogger); err != nil {
+			return nil, fmt.Errorf("targets.New(): %v", err)
+		}
+		t.resolver = res
+		t.resolveFirst = resolverConf.GetProtocol() == targetspb.DNSResolverConfig_HTTPS
+	}
+
+	// Some targets types resolve names themselves, e.g. RDS and k8s targets
+	// come with IPs, so DNS resolver doesn't apply to them.
+	var ownResolverType string
+
 	switch targetsDef.Type.(type) {
 	case *targetspb.TargetsDef_HostNames:
 		st, err := staticTargets(targetsDef.GetHostNames())
 		if err != nil {
 			return nil, fmt.Errorf("targets.New(): error creating targets from host_names: %v", err)
 		}
-		t.lister, t.resolver = st, st
+		t.lister = st
 
 	case *targetspb.TargetsDef_SharedTargets:
 		sharedTargetsMu.RLock()
@@ -339,9 +411,10 @@ func New(targetsDef *targetspb.TargetsDef, ldLister endpoint.Lister, globalOpts
 			return nil, fmt.Errorf("targets.New(): Shared targets %s are not defined", targetsDef.GetSharedTargets())
 		}
 		t.lister, t.resolver = st, st
+		ownResolverType = "shared_targets"
 
 	case *targetspb.TargetsDef_GceTargets:
-		s, err := gce.New(targetsDef.GetGceTargets(), globalOpts.GetGlobalGceTargetsOptions(), globalResolver, globalLogger)
+		s, err := gce.New(targetsDef.GetGceTargets(), globalOpts.GetGlobalGceTargetsOptions(), res, globalLogger)
 		if err != nil {
 			return nil, fmt.Errorf("targets.New(): error creating GCE targets: %v", err)
 		}
@@ -359,9 +432,10 @@ func New(targetsDef *targetspb.TargetsDef, ldLister endpoint.Lister, globalOpts
 		}
 
 		t.lister, t.resolver = client, client
+		ownResolverType = "rds_targets"
 
 	case *targetspb.TargetsDef_FileTargets:
-		ft, err := file.New(targetsDef.GetFileTargets(), globalResolver, l)
+		ft, err := file.New(targetsDef.GetFileTargets(), res, l)
 		if err != nil {
 			return nil, fmt.Errorf("target.New(): %v", err)
 		}
@@ -373,10 +447,33 @@ func New(targetsDef *targetspb.TargetsDef, ldLister endpoint.Lister, globalOpts
 			return nil, fmt.Errorf("target.New(): error creating K8s targets: %v", err)
 		}
 		t.lister, t.resolver = kt, kt
+		ownResolverType = "k8s"
+
+	case *targetspb.TargetsDef_SrvTargets:
+		st, err := srv.New(targetsDef.GetSrvTargets(), res, l)
+		if err != nil {
+			return nil, fmt.Errorf("target.New(): error creating SRV targets: %v", err)
+		}
+		t.lister, t.resolver = st, st
+
+	case *targetspb.TargetsDef_StdinTargets:
+		sl, err := stdinTargets(l)
+		if err != nil {
+			return nil, fmt.Errorf("targets.New(): %v", err)
+		}
+		t.lister, t.resolver = sl, res
+
+	case *targetspb.TargetsDef_CidrTargets:
+		sl, err := cidrTargets(targetsDef.GetCidrTargets())
+		if err != nil {
+			return nil, fmt.Errorf("targets.New(): %v", err)
+		}
+		t.lister, t.resolver = sl, res
 
 	case *targetspb.TargetsDef_DummyTargets:
 		dummy := &dummy{}
 		t.lister, t.resolver = dummy, dummy
+		ownResolverType = "dummy_targets"
 
 	default:
 		targetsFunc, value := getExtensionTargets(targetsDef, t.l)
@@ -386,7 +483,15 @@ func New(targetsDef *targetspb.TargetsDef, ldLister endpoint.Lister, globalOpts
 				return nil, fmt.Errorf("targets.New(): targets extension: %v", err)
 			}
 			t.lister, t.resolver = extT, extT
+			ownResolverType = "extension"
+		}
+	}
+
+	if ownResolverType != "" {
+		if targetsDef.GetDnsResolver() != nil {
+			return nil, fmt.Errorf("targets.New(): dns_resolver is not supported for %s targets", ownResolverType)
 		}
+		t.resolveFirst = false
 	}
 
 	if t.lister == nil && len(t.staticEndpoints) == 0 {
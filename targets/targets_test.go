@@ -287,6 +287,72 @@ func TestRDSClientConf(t *testing.T) {
 	}
 }
 
+func TestRDSClientConfDiscoveryFailureOptions(t *testing.T) {
+	pb := &targetspb.RDSTargets{
+		RdsServerOptions: &rdsclientpb.ClientConf_ServerOptions{
+			ServerAddress: proto.String("test-addr"),
+		},
+		ResourcePath:    proto.String("test-provider://test-resources"),
+		MaxRetries:      proto.Int32(5),
+		MaxStalenessSec: proto.Int32(600),
+	}
+
+	_, cc, err := rdsClientConf(pb, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), cc.GetMaxRetries())
+	assert.Equal(t, int32(600), cc.GetMaxStalenessSec())
+	// Default from the client config.
+	assert.Equal(t, int32(500), cc.GetRetryInitialBackoffMsec())
+}
+
+type staleTestTargets struct {
+	mockLister
+	stale bool
+}
+
+func (st *staleTestTargets) Resolve(name string, ipVer int) (net.IP, error) {
+	return nil, nil
+}
+
+func (st *staleTestTargets) Stale() bool {
+	return st.stale
+}
+
+func TestStaleStatus(t *testing.T) {
+	st := &staleTestTargets{stale: true}
+	SetSharedTargets("stale_test_targets", st)
+	shared, err := New(&targetspb.TargetsDef{
+		Type: &targetspb.TargetsDef_SharedTargets{SharedTargets: "stale_test_targets"},
+	}, nil, nil, nil, nil)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		t         Targets
+		wantStale bool
+		wantOK    bool
+	}{
+		{name: "static", t: StaticTargets("host1,host2")},
+		{name: "stale_reporter", t: st, wantStale: true, wantOK: true},
+		{name: "wrapped", t: &targets{lister: st}, wantStale: true, wantOK: true},
+		{name: "shared", t: shared, wantStale: true, wantOK: true},
+		{name: "static_endpoints", t: StaticEndpoints(nil)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stale, ok := StaleStatus(test.t)
+			assert.Equal(t, test.wantStale, stale, "stale")
+			assert.Equal(t, test.wantOK, ok, "ok")
+		})
+	}
+
+	st.stale = false
+	stale, ok := StaleStatus(shared)
+	assert.False(t, stale)
+	assert.True(t, ok)
+}
+
 func TestNew(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -18,6 +18,7 @@ package resources
 import (
 	"bytes"
 	"html/template"
+	"runtime"
 	"time"
 
 	"github.com/cloudprober/cloudprober/config/runconfig"
@@ -32,8 +33,10 @@ var t = template.Must(template.New("header").Parse(`
 <div style="float:left">
   <b>Started</b>: {{.StartTime}} -- up {{.Uptime}}<br/>
   <b>Version</b>: {{.Version}}<br>
+  <b>Commit</b>: {{.Commit}}<br>
+  <b>Go version</b>: {{.GoVersion}}<br>
   <b>Built at</b>: {{.BuiltAt}}<br>
-  <b>Other Links</b>: <a href="/status">/status</a>, <a href="/config-running">/config</a> (<a href="/config-parsed">parsed</a> | <a href="/config">raw</a>), <a href="/alerts">/alerts</a>, <a href="/health">/health</a><br>
+  <b>Other Links</b>: <a href="/status">/status</a>, <a href="/config-running">/config</a> (<a href="/config-parsed">parsed</a> | <a href="/config">raw</a> | <a href="/config/warnings">warnings</a>), <a href="/alerts">/alerts</a>, <a href="/health">/health</a><br>
 </div>
 `))
 
@@ -44,9 +47,11 @@ func Header() template.HTML {
 	uptime := time.Since(startTime).Truncate(time.Millisecond)
 
 	t.Execute(&buf, struct {
-		Version, BuiltAt, StartTime, Uptime, RightDiv interface{}
+		Version, Commit, GoVersion, BuiltAt, StartTime, Uptime, RightDiv interface{}
 	}{
 		Version:   runconfig.Version(),
+		Commit:    runconfig.BuildCommit(),
+		GoVersion: runtime.Version(),
 		BuiltAt:   runconfig.BuildTimestamp(),
 		StartTime: startTime,
 		Uptime:    uptime,
@@ -18,6 +18,7 @@ package web
 import (
 	"bytes"
 	"embed"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"net/http"
@@ -102,7 +103,7 @@ func alertsState() string {
 // Init initializes cloudprober web interface handler.
 func Init() error {
 	srvMux := runconfig.DefaultHTTPServeMux()
-	for _, url := range []string{"/config", "/config-running", "/static/"} {
+	for _, url := range []string{"/config", "/config-running", "/config/warnings", "/static/"} {
 		if webutils.IsHandled(srvMux, url) {
 			return fmt.Errorf("url %s is already handled", url)
 		}
@@ -112,6 +113,17 @@ func Init() error {
 		fmt.Fprint(w, cloudprober.GetRawConfig())
 	})
 
+	srvMux.HandleFunc("/config/warnings", func(w http.ResponseWriter, r *http.Request) {
+		warnings := cloudprober.GetConfigWarnings()
+		if warnings == nil {
+			warnings = []config.Warning{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(warnings); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
 	parsedConfig := cloudprober.GetParsedConfig()
 	srvMux.HandleFunc("/config-parsed", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, cloudprober.GetParsedConfig())